@@ -33,15 +33,23 @@ func newDestroyCmd() *cobra.Command {
 	var message string
 
 	// Flags for engine.UpdateOptions.
+	var allowDestroys []string
 	var analyzers []string
 	var diffDisplay bool
+	var excludeProtected bool
+	var forceUnprotect bool
 	var parallel int
+	var previewOnly bool
+	var redactions []string
 	var refresh bool
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
 	var skipPreview bool
 	var suppressOutputs bool
+	var targets []string
+	var targetDependencies bool
+	var targetDependents bool
 	var yes bool
 
 	var cmd = &cobra.Command{
@@ -54,18 +62,26 @@ func newDestroyCmd() *cobra.Command {
 			"loaded from the associated state file in the workspace.  After running to completion,\n" +
 			"all of this stack's resources and associated state will be gone.\n" +
 			"\n" +
+			"Resources are deleted in reverse dependency order: a resource is only deleted once every resource\n" +
+			"that depends on it, or refers to its URN, is gone.  If a deletion fails, the resources deleted\n" +
+			"so far stay deleted, and running this command again picks up where it left off.\n" +
+			"\n" +
 			"Warning: this command is generally irreversible and should be used with great care.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			interactive := cmdutil.Interactive()
-			if !interactive {
-				yes = true // auto-approve changes, since we cannot prompt.
+			if !interactive || previewOnly {
+				yes = true // auto-approve changes, since we cannot prompt (or will not perform them).
+			}
+			if previewOnly && skipPreview {
+				return errors.New("--preview and --skip-preview may not be used together")
 			}
 
 			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
 			if err != nil {
 				return err
 			}
+			opts.PreviewOnly = previewOnly
 
 			opts.Display = display.Options{
 				Color:                cmdutil.GetGlobalColorization(),
@@ -92,11 +108,23 @@ func newDestroyCmd() *cobra.Command {
 				return errors.Wrap(err, "gathering environment metadata")
 			}
 
+			redaction, err := redactionPolicy(redactions)
+			if err != nil {
+				return err
+			}
+
 			opts.Engine = engine.UpdateOptions{
-				Analyzers: analyzers,
-				Parallel:  parallel,
-				Debug:     debug,
-				Refresh:   refresh,
+				AllowDestroy:       targetURNs(allowDestroys),
+				Analyzers:          analyzers,
+				ForceUnprotect:     forceUnprotect,
+				ExcludeProtected:   excludeProtected,
+				Targets:            targetURNs(targets),
+				TargetDependencies: targetDependencies,
+				TargetDependents:   targetDependents,
+				Parallel:           parallel,
+				Debug:              debug,
+				Refresh:            refresh,
+				RedactionPolicy:    redaction,
 			}
 
 			_, err = s.Destroy(commandContext(), backend.UpdateOperation{
@@ -127,18 +155,35 @@ func newDestroyCmd() *cobra.Command {
 		"Optional message to associate with the destroy operation")
 
 	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().StringArrayVar(
+		&allowDestroys, "allow-destroy", []string{},
+		"Allow a single resource URN that is guarded against deletion to be deleted."+
+			" Multiple resources can be specified using --allow-destroy urn1 --allow-destroy urn2")
 	cmd.PersistentFlags().StringSliceVar(
 		&analyzers, "analyzer", []string{},
 		"Run one or more analyzers as part of this update")
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().BoolVar(
+		&excludeProtected, "exclude-protected", false,
+		"Leave protected resources, and the resources they depend on, in place rather than fail to delete them")
+	cmd.PersistentFlags().BoolVar(
+		&forceUnprotect, "force-unprotect", false,
+		"Allow protected resources to be deleted or replaced")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview", false,
+		"Only preview the destroy; do not delete any resources")
 	cmd.PersistentFlags().BoolVarP(
 		&refresh, "refresh", "r", false,
 		"Refresh the state of the stack's resources before this update")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactions, "redact", []string{},
+		"Redact property values whose keys or paths match a glob pattern, such as '*password*', from logs and"+
+			" displayed changes. Multiple patterns can be specified using --redact pattern1 --redact pattern2")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -154,6 +199,16 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&suppressOutputs, "suppress-outputs", false,
 		"Suppress display of stack outputs (in case they contain sensitive values)")
+	cmd.PersistentFlags().StringArrayVarP(
+		&targets, "target", "t", []string{},
+		"Specify a single resource URN to destroy. Other resources will not be destroyed."+
+			" Multiple resources can be specified using --target urn1 --target urn2")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependencies, "target-dependencies", false,
+		"Allows destroying resources that the specified --target resources depend on")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false,
+		"Allows destroying resources that depend on the specified --target resources")
 	cmd.PersistentFlags().BoolVarP(
 		&yes, "yes", "y", false,
 		"Automatically approve and perform the destroy after previewing it")
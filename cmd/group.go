@@ -0,0 +1,329 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group",
+		Short: "Preview and update a group of stacks in dependency order",
+		Long: "Preview and update a group of stacks in dependency order.\n" +
+			"\n" +
+			"A stack group is declared in a `" + workspace.StackGroupFile + ".yaml` manifest, which lists each\n" +
+			"stack, the directory of its project, and the other stacks whose outputs it consumes:\n" +
+			"\n" +
+			"    stacks:\n" +
+			"      network:\n" +
+			"        project: ./network\n" +
+			"      app:\n" +
+			"        project: ./app\n" +
+			"        stack: production\n" +
+			"        dependsOn: [network]\n" +
+			"\n" +
+			"The manifest is found by searching upwards from the current directory. Stacks are operated on one\n" +
+			"at a time, each after all of the stacks it depends on. If a stack fails, the stacks that depend on\n" +
+			"it are skipped. A summary of every stack is printed at the end.",
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newGroupPreviewCmd())
+	cmd.AddCommand(newGroupUpCmd())
+
+	return cmd
+}
+
+func newGroupPreviewCmd() *cobra.Command {
+	var flags stackGroupFlags
+	var cmd = &cobra.Command{
+		Use:   "preview",
+		Short: "Preview the updates of every stack in the group, in dependency order",
+		Args:  cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.UpdateOptions{}
+			return runStackGroup(flags, opts, "Previewing", func(s backend.Stack, op backend.UpdateOperation) (
+				engine.ResourceChanges, error) {
+				return s.Preview(commandContext(), op)
+			})
+		}),
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+func newGroupUpCmd() *cobra.Command {
+	var flags stackGroupFlags
+	var skipPreview bool
+	var yes bool
+	var cmd = &cobra.Command{
+		Use:   "up",
+		Short: "Update every stack in the group, in dependency order",
+		Args:  cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts, err := updateFlagsToOptions(cmdutil.Interactive(), skipPreview, yes)
+			if err != nil {
+				return err
+			}
+			return runStackGroup(flags, opts, "Updating", func(s backend.Stack, op backend.UpdateOperation) (
+				engine.ResourceChanges, error) {
+				return s.Update(commandContext(), op)
+			})
+		}),
+	}
+	flags.register(cmd)
+	cmd.PersistentFlags().BoolVar(
+		&skipPreview, "skip-preview", false,
+		"Do not perform a preview before performing each update")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Automatically approve and perform each update after previewing it")
+	return cmd
+}
+
+// stackGroupFlags are the flags shared by the stack group commands.
+type stackGroupFlags struct {
+	file string
+
+	// Flags for engine.UpdateOptions.
+	debug                bool
+	diffDisplay          bool
+	parallel             int
+	showConfig           bool
+	showReplacementSteps bool
+	showSames            bool
+	suppressOutputs      bool
+}
+
+func (f *stackGroupFlags) register(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(
+		&f.file, "file", "f", "",
+		"The stack group manifest to use. Defaults to the closest "+workspace.StackGroupFile+" manifest")
+	cmd.PersistentFlags().BoolVarP(
+		&f.debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().BoolVar(
+		&f.diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().IntVarP(
+		&f.parallel, "parallel", "p", defaultParallel,
+		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().BoolVar(
+		&f.showConfig, "show-config", false,
+		"Show configuration keys and variables")
+	cmd.PersistentFlags().BoolVar(
+		&f.showReplacementSteps, "show-replacement-steps", false,
+		"Show detailed resource replacement creates and deletes instead of a single step")
+	cmd.PersistentFlags().BoolVar(
+		&f.showSames, "show-sames", false,
+		"Show resources that needn't be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&f.suppressOutputs, "suppress-outputs", false,
+		"Suppress display of stack outputs (in case they contain sensitive values)")
+}
+
+// runStackGroup loads the stack group manifest and runs the given operation on each of its stacks in dependency
+// order, from within the directory of each stack's project.
+func runStackGroup(flags stackGroupFlags, opts backend.UpdateOptions, verb string,
+	operation func(s backend.Stack, op backend.UpdateOperation) (engine.ResourceChanges, error)) error {
+
+	path := flags.file
+	if path == "" {
+		detected, err := workspace.DetectStackGroupPath()
+		if err != nil {
+			return errors.Wrap(err, "searching for a stack group manifest")
+		} else if detected == "" {
+			return errors.Errorf("no %s manifest found; pass one with --file", workspace.StackGroupFile)
+		}
+		path = detected
+	}
+	group, err := workspace.LoadStackGroup(path)
+	if err != nil {
+		return errors.Wrapf(err, "loading stack group manifest %s", path)
+	}
+
+	opts.Engine = engine.UpdateOptions{
+		Parallel: flags.parallel,
+		Debug:    flags.debug,
+	}
+	opts.Display = display.Options{
+		Color:                cmdutil.GetGlobalColorization(),
+		ShowConfig:           flags.showConfig,
+		ShowReplacementSteps: flags.showReplacementSteps,
+		ShowSameResources:    flags.showSames,
+		SuppressOutputs:      flags.suppressOutputs,
+		IsInteractive:        cmdutil.Interactive(),
+		DiffDisplay:          flags.diffDisplay,
+		Debug:                flags.debug,
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	runner := &stackGroupRunner{
+		group: group,
+		color: opts.Display.Color,
+		verb:  verb,
+		run: func(name string, member workspace.StackGroupMember) (engine.ResourceChanges, error) {
+			// Projects are always loaded from the current directory, so move into the stack's project for the
+			// duration of its operation.
+			if err := os.Chdir(workspace.StackGroupProjectDir(path, member)); err != nil {
+				return nil, err
+			}
+			defer func() {
+				if err := os.Chdir(cwd); err != nil {
+					cmdutil.Diag().Warningf(diag.Message("", "could not return to %s: %v"), cwd, err)
+				}
+			}()
+
+			s, err := requireStack(member.StackName(name), false, opts.Display, false /*setCurrent*/)
+			if err != nil {
+				return nil, err
+			}
+			proj, root, err := readProject()
+			if err != nil {
+				return nil, err
+			}
+			m, err := getUpdateMetadata("", root)
+			if err != nil {
+				return nil, errors.Wrap(err, "gathering environment metadata")
+			}
+
+			return operation(s, backend.UpdateOperation{
+				Proj:   proj,
+				Root:   root,
+				M:      m,
+				Opts:   opts,
+				Scopes: cancellationScopes,
+			})
+		},
+	}
+	return runner.runAll()
+}
+
+// stackGroupStatus is the outcome of operating on a single stack in a group.
+type stackGroupStatus string
+
+const (
+	stackGroupSucceeded stackGroupStatus = "succeeded"
+	stackGroupFailed    stackGroupStatus = "failed"
+	stackGroupSkipped   stackGroupStatus = "skipped"
+)
+
+// stackGroupResult records the outcome of operating on a single stack in a group.
+type stackGroupResult struct {
+	Name    string
+	Status  stackGroupStatus
+	Changes engine.ResourceChanges
+	Reason  string
+}
+
+// stackGroupRunner operates on each stack in a group in dependency order, skipping the dependents of any stack whose
+// operation fails.
+type stackGroupRunner struct {
+	group *workspace.StackGroup
+	color colors.Colorization
+	verb  string
+	run   func(name string, member workspace.StackGroupMember) (engine.ResourceChanges, error)
+}
+
+// operate runs the operation on each stack in the group and returns the outcome for each, in the order in which the
+// stacks were operated on.
+func (r *stackGroupRunner) operate() ([]stackGroupResult, error) {
+	order, err := r.group.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []stackGroupResult
+	skipped := make(map[string]string)
+	for i, name := range order {
+		if cause, has := skipped[name]; has {
+			results = append(results, stackGroupResult{
+				Name:   name,
+				Status: stackGroupSkipped,
+				Reason: fmt.Sprintf("depends on failed stack '%s'", cause),
+			})
+			continue
+		}
+
+		fmt.Println(r.color.Colorize(fmt.Sprintf("%s[%d/%d] %s stack '%s'...%s",
+			colors.SpecHeadline, i+1, len(order), r.verb, name, colors.Reset)))
+
+		changes, err := r.run(name, r.group.Stacks[name])
+		if err != nil {
+			err = PrintEngineError(err)
+			cmdutil.Diag().Errorf(diag.Message("", "%v"), err)
+			results = append(results, stackGroupResult{Name: name, Status: stackGroupFailed, Reason: err.Error()})
+			for _, dependent := range r.group.Dependents(name) {
+				if _, has := skipped[dependent]; !has {
+					skipped[dependent] = name
+				}
+			}
+			continue
+		}
+		results = append(results, stackGroupResult{Name: name, Status: stackGroupSucceeded, Changes: changes})
+	}
+	return results, nil
+}
+
+// runAll runs the operation on each stack in the group, prints a summary of the outcomes, and returns an error if any
+// stack failed or was skipped.
+func (r *stackGroupRunner) runAll() error {
+	results, err := r.operate()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(r.color.Colorize(fmt.Sprintf("%sStack group summary:%s", colors.SpecHeadline, colors.Reset)))
+	var failed []string
+	for _, result := range results {
+		var line string
+		switch result.Status {
+		case stackGroupSucceeded:
+			line = fmt.Sprintf("    %s%s%s: %d changes", colors.SpecCreate, result.Name, colors.Reset,
+				result.Changes.Count())
+		case stackGroupFailed:
+			failed = append(failed, result.Name)
+			line = fmt.Sprintf("    %s%s%s: failed: %s", colors.SpecError, result.Name, colors.Reset, result.Reason)
+		default:
+			line = fmt.Sprintf("    %s%s%s: skipped; %s", colors.SpecWarning, result.Name, colors.Reset, result.Reason)
+		}
+		fmt.Println(r.color.Colorize(line))
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("stack group operation failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func TestStackGroupRunnerSkipsDependentsOfFailures(t *testing.T) {
+	group := &workspace.StackGroup{Stacks: map[string]workspace.StackGroupMember{
+		"network":  {Project: "network"},
+		"database": {Project: "database", DependsOn: []string{"network"}},
+		"app":      {Project: "app", DependsOn: []string{"database"}},
+		"dns":      {Project: "dns"},
+	}}
+
+	var ran []string
+	runner := &stackGroupRunner{
+		group: group,
+		color: colors.Never,
+		verb:  "Updating",
+		run: func(name string, member workspace.StackGroupMember) (engine.ResourceChanges, error) {
+			ran = append(ran, name)
+			if name == "database" {
+				return nil, errors.New("boom")
+			}
+			return engine.ResourceChanges{deploy.OpCreate: 2}, nil
+		},
+	}
+
+	results, err := runner.operate()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"network", "database", "dns"}, ran)
+	assert.Equal(t, []stackGroupResult{
+		{Name: "network", Status: stackGroupSucceeded, Changes: engine.ResourceChanges{deploy.OpCreate: 2}},
+		{Name: "database", Status: stackGroupFailed, Reason: "boom"},
+		{Name: "app", Status: stackGroupSkipped, Reason: "depends on failed stack 'database'"},
+		{Name: "dns", Status: stackGroupSucceeded, Changes: engine.ResourceChanges{deploy.OpCreate: 2}},
+	}, results)
+
+	assert.Error(t, runner.runAll())
+}
@@ -94,6 +94,14 @@ type updateInfoJSON struct {
 	Environment map[string]string          `json:"environment"`
 	Config      map[string]configValueJSON `json:"config"`
 	Result      string                     `json:"result,omitempty"`
+	Version     int                        `json:"version,omitempty"`
+	User        string                     `json:"user,omitempty"`
+
+	// A digest of the config, so that the configurations of two updates can be compared without decrypting secrets.
+	ConfigDigest string `json:"configDigest,omitempty"`
+
+	// The digest of the last entry in the update's audit log, against which the log can be verified.
+	AuditLogDigest string `json:"auditLogDigest,omitempty"`
 
 	// These values are only present once the update finishes
 	EndTime         *string         `json:"endTime,omitempty"`
@@ -112,7 +120,11 @@ func displayUpdatesJSON(updates []backend.UpdateInfo, decrypter config.Decrypter
 			StartTime:   time.Unix(update.StartTime, 0).UTC().Format(timeFormat),
 			Message:     update.Message,
 			Environment: update.Environment,
+			Version:     update.Version,
+			User:        update.User,
 		}
+		info.ConfigDigest = update.ConfigDigest
+		info.AuditLogDigest = update.AuditLogDigest
 
 		info.Config = make(map[string]configValueJSON)
 		for k, v := range update.Config {
@@ -155,6 +167,9 @@ func displayUpdatesConsole(updates []backend.UpdateInfo, opts display.Options) e
 
 	for _, update := range updates {
 
+		if update.Version > 0 {
+			fmt.Printf("Version: %v\n", update.Version)
+		}
 		fmt.Printf("UpdateKind: %v\n", update.Kind)
 		if update.Result == "succeeded" {
 			fmt.Print(opts.Color.Colorize(fmt.Sprintf("%sStatus: %v%s\n", colors.Green, update.Result, colors.Reset)))
@@ -162,6 +177,12 @@ func displayUpdatesConsole(updates []backend.UpdateInfo, opts display.Options) e
 			fmt.Print(opts.Color.Colorize(fmt.Sprintf("%sStatus: %v%s\n", colors.Red, update.Result, colors.Reset)))
 		}
 		fmt.Printf("Message: %v\n", update.Message)
+		if update.User != "" {
+			fmt.Printf("User: %v\n", update.User)
+		}
+		if update.ConfigDigest != "" {
+			fmt.Printf("Config: %v\n", update.ConfigDigest)
+		}
 
 		printResourceChanges(colors.GreenBackground, colors.Black, "+", colors.Reset, update.ResourceChanges["create"])
 		printResourceChanges(colors.RedBackground, colors.Black, "-", colors.Reset, update.ResourceChanges["delete"])
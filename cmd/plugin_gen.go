@@ -0,0 +1,112 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/schema"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newPluginGenCmd() *cobra.Command {
+	var file string
+	var goPackage string
+
+	var cmd = &cobra.Command{
+		Use:   "gen NAME [VERSION]",
+		Args:  cmdutil.RangeArgs(1, 2),
+		Short: "Generate Go structs for the resources of a provider plugin",
+		Long: "Generate Go structs for the resources of a provider plugin.\n" +
+			"\n" +
+			"This command loads the installed resource plugin with the given NAME (and\n" +
+			"VERSION, if specified), asks it for the schema of the resource types that it\n" +
+			"manages, and generates a Go struct for the inputs and for the outputs of each of\n" +
+			"them.  Each field is tagged with the property that it holds, so that the structs\n" +
+			"can be used to decode and encode property maps, and regenerating them keeps\n" +
+			"code that consumes the provider's resources in sync with the provider.\n" +
+			"\n" +
+			"The generated code is written to standard output unless --file is passed.  Only\n" +
+			"providers that publish a schema are supported.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			var version *semver.Version
+			if len(args) > 1 {
+				v, err := semver.ParseTolerant(args[1])
+				if err != nil {
+					return errors.Wrap(err, "invalid plugin semver")
+				}
+				version = &v
+			}
+			if goPackage == "" {
+				goPackage = strings.Replace(name, "-", "", -1)
+			}
+
+			pkg, err := getProviderSchema(tokens.Package(name), version)
+			if err != nil {
+				return err
+			}
+			src, err := schema.GenerateGo(pkg, goPackage)
+			if err != nil {
+				return errors.Wrapf(err, "generating code for %s", name)
+			}
+
+			if file == "" {
+				_, err = os.Stdout.Write(src)
+				return err
+			}
+			return ioutil.WriteFile(file, src, 0644)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&file, "file", "f", "", "A filename to write the generated code to")
+	cmd.PersistentFlags().StringVar(
+		&goPackage, "package", "", "The name of the generated Go package (defaults to the plugin's name)")
+
+	return cmd
+}
+
+// getProviderSchema loads the given provider plugin and returns the schema that it publishes.
+func getProviderSchema(pkg tokens.Package, version *semver.Version) (*schema.Package, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := plugin.NewContext(cmdutil.Diag(), cmdutil.Diag(), nil, nil, nil, pwd, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(ctx)
+
+	prov, err := ctx.Host.Provider(pkg, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading the %s plugin", pkg)
+	}
+	data, err := plugin.GetSchema(prov, schema.FormatVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the schema of %s", pkg)
+	}
+	return schema.ParsePackage(data)
+}
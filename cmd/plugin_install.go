@@ -35,6 +35,7 @@ func newPluginInstallCmd() *cobra.Command {
 	var exact bool
 	var file string
 	var reinstall bool
+	var server string
 	var verbose bool
 
 	var cmd = &cobra.Command{
@@ -49,12 +50,22 @@ func newPluginInstallCmd() *cobra.Command {
 			"project.  VERSION cannot be a range: it must be a specific number.\n" +
 			"\n" +
 			"If you let Pulumi compute the set to download, it is conservative and may end up\n" +
-			"downloading more plugins than is strictly necessary.",
+			"downloading more plugins than is strictly necessary.\n" +
+			"\n" +
+			"By default, plugins are downloaded from the Pulumi service.  Pass --server to\n" +
+			"download them from a release URL instead.  Each download is verified against the\n" +
+			"SHA-256 checksum that the release publishes alongside the plugin's tarball.  If\n" +
+			"the release publishes no checksum, the install fails unless the\n" +
+			workspace.SkipPluginChecksumEnvVar + " environment variable is set.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			displayOpts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
 			}
 
+			if file != "" && server != "" {
+				return errors.New("only one of --file (-f) and --server may be passed")
+			}
+
 			// Parse the kind, name, and version, if specified.
 			var installs []workspace.PluginInfo
 			if len(args) > 0 {
@@ -95,7 +106,7 @@ func newPluginInstallCmd() *cobra.Command {
 
 			// Target the cloud URL for downloads.
 			var releases httpstate.Backend
-			if len(installs) > 0 && file == "" {
+			if len(installs) > 0 && file == "" && server == "" {
 				r, err := httpstate.New(cmdutil.Diag(), httpstate.ValueOrDefaultURL(cloudURL), "")
 				if err != nil {
 					return errors.Wrap(err, "creating API client")
@@ -135,7 +146,16 @@ func newPluginInstallCmd() *cobra.Command {
 				var source string
 				var tarball io.ReadCloser
 				var err error
-				if file == "" {
+				if file == "" && server != "" {
+					source = server
+					if verbose {
+						cmdutil.Diag().Infoerrf(
+							diag.Message("", "%s downloading from %s"), label, source)
+					}
+					if tarball, err = workspace.DownloadPlugin(install, server); err != nil {
+						return errors.Wrapf(err, "%s downloading from %s", label, source)
+					}
+				} else if file == "" {
 					source = releases.CloudURL()
 					if verbose {
 						cmdutil.Diag().Infoerrf(
@@ -175,6 +195,8 @@ func newPluginInstallCmd() *cobra.Command {
 		"file", "f", "", "Install a plugin from a tarball file, instead of downloading it")
 	cmd.PersistentFlags().BoolVar(&reinstall,
 		"reinstall", false, "Reinstall a plugin even if it already exists")
+	cmd.PersistentFlags().StringVar(&server,
+		"server", "", "A release URL to download plugins from, instead of the Pulumi service")
 	cmd.PersistentFlags().BoolVar(&verbose,
 		"verbose", false, "Print detailed information about the installation steps")
 
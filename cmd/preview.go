@@ -21,6 +21,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 )
 
@@ -33,11 +34,20 @@ func newPreviewCmd() *cobra.Command {
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
 	var diffDisplay bool
+	var forceUnprotect bool
 	var parallel int
+	var placeholderIDs bool
+	var policyPackPaths []string
+	var redactions []string
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
 	var suppressOutputs bool
+	var jsonDisplay bool
+	var replaces []string
+	var targets []string
+	var targetDependencies bool
+	var targetDependents bool
 
 	var cmd = &cobra.Command{
 		Use:        "preview",
@@ -57,11 +67,27 @@ func newPreviewCmd() *cobra.Command {
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			redaction, err := redactionPolicy(redactions)
+			if err != nil {
+				return err
+			}
+			packs, err := policyPacks(policyPackPaths)
+			if err != nil {
+				return err
+			}
+
 			opts := backend.UpdateOptions{
 				Engine: engine.UpdateOptions{
-					Analyzers: analyzers,
-					Parallel:  parallel,
-					Debug:     debug,
+					Analyzers:          analyzers,
+					ForceUnprotect:     forceUnprotect,
+					Targets:            targetURNs(targets),
+					TargetDependencies: targetDependencies,
+					TargetDependents:   targetDependents,
+					ReplaceTargets:     targetURNs(replaces),
+					Parallel:           parallel,
+					Debug:              debug,
+					RedactionPolicy:    redaction,
+					Policies:           packs,
 				},
 				Display: display.Options{
 					Color:                cmdutil.GetGlobalColorization(),
@@ -71,9 +97,13 @@ func newPreviewCmd() *cobra.Command {
 					SuppressOutputs:      suppressOutputs,
 					IsInteractive:        cmdutil.Interactive(),
 					DiffDisplay:          diffDisplay,
+					JSONDisplay:          jsonDisplay,
 					Debug:                debug,
 				},
 			}
+			if placeholderIDs {
+				opts.Engine.IDAllocator = deploy.DeterministicIDAllocator
+			}
 
 			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
 			if err != nil {
@@ -132,9 +162,26 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonDisplay, "json", "j", false,
+		"Serialize the preview's steps, diagnostics, and summary as a single JSON document")
+	cmd.PersistentFlags().BoolVar(
+		&forceUnprotect, "force-unprotect", false,
+		"Allow protected resources to be deleted or replaced")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().BoolVar(
+		&placeholderIDs, "placeholder-ids", false,
+		"Give resources that would be created stable placeholder IDs rather than unknown ones")
+	cmd.PersistentFlags().StringArrayVar(
+		&policyPackPaths, "policy-pack", []string{},
+		"Evaluate the policy pack in the given JSON or YAML file against each resource. Multiple packs can be"+
+			" specified using --policy-pack file1 --policy-pack file2")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactions, "redact", []string{},
+		"Redact property values whose keys or paths match a glob pattern, such as '*password*', from logs and"+
+			" displayed changes. Multiple patterns can be specified using --redact pattern1 --redact pattern2")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -147,6 +194,20 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&suppressOutputs, "suppress-outputs", false,
 		"Suppress display of stack outputs (in case they contain sensitive values)")
+	cmd.PersistentFlags().StringArrayVarP(
+		&targets, "target", "t", []string{},
+		"Specify a single resource URN to update. Other resources will not be updated."+
+			" Multiple resources can be specified using --target urn1 --target urn2")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependencies, "target-dependencies", false,
+		"Allows updating of resources that the specified --target resources depend on")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false,
+		"Allows updating of resources that depend on the specified --target resources")
+	cmd.PersistentFlags().StringArrayVar(
+		&replaces, "replace", []string{},
+		"Specify a single resource URN to replace, even if it has not changed."+
+			" Multiple resources can be specified using --replace urn1 --replace urn2")
 
 	return cmd
 }
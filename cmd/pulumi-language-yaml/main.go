@@ -0,0 +1,146 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi/pkg/resource/declarative"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil"
+	"github.com/pulumi/pulumi/pkg/version"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// defaultDocuments are the names of the documents that are run when a project's program is a directory.
+var defaultDocuments = []string{"Main.yaml", "Main.yml", "Main.json"}
+
+// Launches the language host, which in turn fires up an RPC server implementing the LanguageRuntimeServer endpoint.
+// Unlike the other language hosts, this one runs programs itself: its programs are declarative YAML or JSON documents.
+func main() {
+	var tracing string
+	flag.StringVar(&tracing, "tracing", "", "Emit tracing to a Zipkin-compatible tracing endpoint")
+
+	flag.Parse()
+	args := flag.Args()
+	logging.InitLogging(false, 0, false)
+	cmdutil.InitTracing("pulumi-language-yaml", "pulumi-language-yaml", tracing)
+
+	// Pluck out the engine so we can do logging, etc.
+	if len(args) == 0 {
+		cmdutil.Exit(errors.New("missing required engine RPC address argument"))
+	}
+
+	// Fire up a gRPC server, letting the kernel choose a free port.
+	port, done, err := rpcutil.Serve(0, nil, []func(*grpc.Server) error{
+		func(srv *grpc.Server) error {
+			pulumirpc.RegisterLanguageRuntimeServer(srv, &yamlLanguageHost{})
+			return nil
+		},
+	})
+	if err != nil {
+		cmdutil.Exit(errors.Wrapf(err, "could not start language host RPC server"))
+	}
+
+	// Otherwise, print out the port so that the spawner knows how to reach us.
+	fmt.Printf("%d\n", port)
+
+	// And finally wait for the server to stop serving.
+	if err := <-done; err != nil {
+		cmdutil.Exit(errors.Wrapf(err, "language host RPC stopped serving"))
+	}
+}
+
+// yamlLanguageHost implements the LanguageRuntimeServer interface for use as an API endpoint.
+type yamlLanguageHost struct{}
+
+// loadDocument loads the document named by the given working directory and program.  If the program is a directory,
+// the first of the default documents that it contains is loaded.
+func loadDocument(pwd, program string) (*declarative.Document, error) {
+	path := program
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(pwd, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		for _, name := range defaultDocuments {
+			candidate := filepath.Join(path, name)
+			if _, err := os.Stat(candidate); err == nil {
+				return declarative.Load(candidate)
+			}
+		}
+		return nil, errors.Errorf("no program found in %s; expected one of %v", path, defaultDocuments)
+	}
+	return declarative.Load(path)
+}
+
+// GetRequiredPlugins computes the complete set of anticipated plugins required by a program.
+func (host *yamlLanguageHost) GetRequiredPlugins(ctx context.Context,
+	req *pulumirpc.GetRequiredPluginsRequest) (*pulumirpc.GetRequiredPluginsResponse, error) {
+
+	doc, err := loadDocument(req.GetPwd(), req.GetProgram())
+	if err != nil {
+		return nil, err
+	}
+	var plugins []*pulumirpc.PluginDependency
+	for _, pkg := range doc.Packages() {
+		plugins = append(plugins, &pulumirpc.PluginDependency{Name: string(pkg), Kind: "resource"})
+	}
+	return &pulumirpc.GetRequiredPluginsResponse{Plugins: plugins}, nil
+}
+
+// RPC endpoint for LanguageRuntimeServer::Run
+func (host *yamlLanguageHost) Run(ctx context.Context, req *pulumirpc.RunRequest) (*pulumirpc.RunResponse, error) {
+	conn, err := grpc.Dial(req.GetMonitorAddress(), grpc.WithInsecure())
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to resource monitor over RPC")
+	}
+	defer contract.IgnoreClose(conn)
+
+	// Problems with the program itself are reported as the result of the run rather than as RPC errors.
+	var errResult string
+	doc, err := loadDocument(req.GetPwd(), req.GetProgram())
+	if err == nil {
+		logging.V(5).Infof("language host running declarative program %s", req.GetProgram())
+		err = declarative.Run(ctx, pulumirpc.NewResourceMonitorClient(conn), declarative.RunInfo{
+			Project: req.GetProject(),
+			Stack:   req.GetStack(),
+			DryRun:  req.GetDryRun(),
+		}, doc)
+	}
+	if err != nil {
+		errResult = err.Error()
+	}
+	return &pulumirpc.RunResponse{Error: errResult}, nil
+}
+
+func (host *yamlLanguageHost) GetPluginInfo(ctx context.Context, req *pbempty.Empty) (*pulumirpc.PluginInfo, error) {
+	return &pulumirpc.PluginInfo{
+		Version: version.Version,
+	}, nil
+}
@@ -29,6 +29,7 @@ import (
 func newRefreshCmd() *cobra.Command {
 	var debug bool
 	var expectNop bool
+	var merge bool
 	var message string
 	var stack string
 
@@ -36,6 +37,7 @@ func newRefreshCmd() *cobra.Command {
 	var analyzers []string
 	var diffDisplay bool
 	var parallel int
+	var redactions []string
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
@@ -51,6 +53,9 @@ func newRefreshCmd() *cobra.Command {
 			"This command resets the 'Pulumi.<stack name>.yaml' file to match the state snapshot copy from the last\n" +
 			"'pulumi up' command.\n" +
 			"\n" +
+			"With `--merge`, changes made outside of Pulumi to the properties that the program sets are reverted,\n" +
+			"while changes made to all other properties are kept.\n" +
+			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.NoArgs,
@@ -91,10 +96,17 @@ func newRefreshCmd() *cobra.Command {
 				return errors.Wrap(err, "gathering environment metadata")
 			}
 
+			redaction, err := redactionPolicy(redactions)
+			if err != nil {
+				return err
+			}
+
 			opts.Engine = engine.UpdateOptions{
-				Analyzers: analyzers,
-				Parallel:  parallel,
-				Debug:     debug,
+				Analyzers:       analyzers,
+				Parallel:        parallel,
+				Debug:           debug,
+				RefreshMerge:    merge,
+				RedactionPolicy: redaction,
 			}
 
 			changes, err := s.Refresh(commandContext(), backend.UpdateOperation{
@@ -123,6 +135,9 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&expectNop, "expect-no-changes", false,
 		"Return an error if any changes occur during this update")
+	cmd.PersistentFlags().BoolVar(
+		&merge, "merge", false,
+		"Revert out-of-band changes to the properties that the program sets, and keep changes to all others")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
@@ -144,6 +159,10 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactions, "redact", []string{},
+		"Redact property values whose keys or paths match a glob pattern, such as '*password*', from logs and"+
+			" displayed changes. Multiple patterns can be specified using --redact pattern1 --redact pattern2")
 	cmd.PersistentFlags().BoolVar(
 		&showReplacementSteps, "show-replacement-steps", false,
 		"Show detailed resource replacement creates and deletes instead of a single step")
@@ -0,0 +1,190 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newRollbackCmd() *cobra.Command {
+	var debug bool
+	var stack string
+
+	var message string
+
+	// Flags for engine.UpdateOptions.
+	var allowDestroys []string
+	var diffDisplay bool
+	var forceUnprotect bool
+	var parallel int
+	var previewOnly bool
+	var redactions []string
+	var showConfig bool
+	var showReplacementSteps bool
+	var showSames bool
+	var skipPreview bool
+	var suppressOutputs bool
+	var yes bool
+
+	var cmd = &cobra.Command{
+		Use:        "rollback <version>",
+		SuggestFor: []string{"revert", "undo"},
+		Short:      "Roll a stack's resources back to an earlier update",
+		Long: "Roll a stack's resources back to an earlier update\n" +
+			"\n" +
+			"This command converges the stack's resources back to the state recorded by the update\n" +
+			"with the given version, as numbered by `pulumi history`.  Rather than running the program,\n" +
+			"it registers each resource in that update's deployment with the inputs recorded there:\n" +
+			"resources deleted since are re-created, resources created since are deleted, and resources\n" +
+			"whose inputs have changed are updated or replaced to use their earlier inputs.\n" +
+			"\n" +
+			"The rollback is previewed before it is performed, just like an update.",
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.Atoi(args[0])
+			if err != nil || version <= 0 {
+				return errors.Errorf("'%s' is not an update version; expected a positive number", args[0])
+			}
+
+			interactive := cmdutil.Interactive()
+			if !interactive || previewOnly {
+				yes = true // auto-approve changes, since we cannot prompt (or will not perform them).
+			}
+			if previewOnly && skipPreview {
+				return errors.New("--preview and --skip-preview may not be used together")
+			}
+
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			if err != nil {
+				return err
+			}
+			opts.PreviewOnly = previewOnly
+
+			opts.Display = display.Options{
+				Color:                cmdutil.GetGlobalColorization(),
+				ShowConfig:           showConfig,
+				ShowReplacementSteps: showReplacementSteps,
+				ShowSameResources:    showSames,
+				SuppressOutputs:      suppressOutputs,
+				IsInteractive:        interactive,
+				DiffDisplay:          diffDisplay,
+				Debug:                debug,
+			}
+
+			s, err := requireStack(stack, false, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			proj, root, err := readProject()
+			if err != nil {
+				return err
+			}
+
+			m, err := getUpdateMetadata(message, root)
+			if err != nil {
+				return errors.Wrap(err, "gathering environment metadata")
+			}
+
+			redaction, err := redactionPolicy(redactions)
+			if err != nil {
+				return err
+			}
+
+			opts.Engine = engine.UpdateOptions{
+				AllowDestroy:    targetURNs(allowDestroys),
+				ForceUnprotect:  forceUnprotect,
+				Parallel:        parallel,
+				Debug:           debug,
+				RedactionPolicy: redaction,
+			}
+
+			_, err = s.Rollback(commandContext(), backend.UpdateOperation{
+				Proj:   proj,
+				Root:   root,
+				M:      m,
+				Opts:   opts,
+				Scopes: cancellationScopes,
+			}, version)
+			if err == context.Canceled {
+				return errors.New("rollback cancelled")
+			}
+			return PrintEngineError(err)
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&stackConfigFile, "config-file", "",
+		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().StringVarP(
+		&message, "message", "m", "",
+		"Optional message to associate with the rollback operation")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().StringArrayVar(
+		&allowDestroys, "allow-destroy", []string{},
+		"Allow a single resource URN that is guarded against deletion to be deleted or replaced."+
+			" Multiple resources can be specified using --allow-destroy urn1 --allow-destroy urn2")
+	cmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().BoolVar(
+		&forceUnprotect, "force-unprotect", false,
+		"Allow protected resources to be deleted or replaced")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", defaultParallel,
+		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview", false,
+		"Only preview the rollback; do not change any resources")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactions, "redact", []string{},
+		"Redact property values whose keys or paths match a glob pattern, such as '*password*', from logs and"+
+			" displayed changes. Multiple patterns can be specified using --redact pattern1 --redact pattern2")
+	cmd.PersistentFlags().BoolVar(
+		&showConfig, "show-config", false,
+		"Show configuration keys and variables")
+	cmd.PersistentFlags().BoolVar(
+		&showReplacementSteps, "show-replacement-steps", false,
+		"Show detailed resource replacement creates and deletes instead of a single step")
+	cmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources that don't need to be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&skipPreview, "skip-preview", false,
+		"Do not perform a preview before performing the rollback")
+	cmd.PersistentFlags().BoolVar(
+		&suppressOutputs, "suppress-outputs", false,
+		"Suppress display of stack outputs (in case they contain sensitive values)")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Automatically approve and perform the rollback after previewing it")
+
+	return cmd
+}
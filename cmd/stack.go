@@ -20,10 +20,14 @@ import (
 	"sort"
 
 	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
 	"github.com/pulumi/pulumi/pkg/backend/httpstate"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 )
@@ -32,6 +36,8 @@ func newStackCmd() *cobra.Command {
 	var showIDs bool
 	var showURNs bool
 	var stackName string
+	var redacted bool
+	var redactPaths []string
 
 	cmd := &cobra.Command{
 		Use:   "stack",
@@ -51,7 +57,16 @@ func newStackCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			snap, err := s.Snapshot(commandContext())
+			var snap *deploy.Snapshot
+			if redacted || len(redactPaths) > 0 {
+				paths, perr := parseRedactPaths(redactPaths)
+				if perr != nil {
+					return perr
+				}
+				snap, err = backend.GetRedactedSnapshot(commandContext(), s, paths)
+			} else {
+				snap, err = s.Snapshot(commandContext())
+			}
 			if err != nil {
 				return err
 			}
@@ -164,13 +179,23 @@ func newStackCmd() *cobra.Command {
 		&showIDs, "show-ids", "i", false, "Display each resource's provider-assigned unique ID")
 	cmd.PersistentFlags().BoolVarP(
 		&showURNs, "show-urns", "u", false, "Display each resource's Pulumi-assigned globally unique URN")
+	cmd.PersistentFlags().BoolVar(
+		&redacted, "redacted", false,
+		"Replace secret provider settings and the values at any --redact-path with digests")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactPaths, "redact-path", []string{},
+		"A property path, e.g. 'auth.password', whose values to redact in every resource. May be passed multiple times "+
+			"and implies --redacted")
 
+	cmd.AddCommand(newStackCloudFormationCmd())
 	cmd.AddCommand(newStackExportCmd())
 	cmd.AddCommand(newStackGraphCmd())
+	cmd.AddCommand(newStackGraphDiffCmd())
 	cmd.AddCommand(newStackImportCmd())
 	cmd.AddCommand(newStackInitCmd())
 	cmd.AddCommand(newStackLsCmd())
 	cmd.AddCommand(newStackOutputCmd())
+	cmd.AddCommand(newStackPromoteCmd())
 	cmd.AddCommand(newStackRmCmd())
 	cmd.AddCommand(newStackSelectCmd())
 	cmd.AddCommand(newStackTagCmd())
@@ -218,3 +243,16 @@ func stringifyOutput(v interface{}) string {
 
 	return string(b)
 }
+
+// parseRedactPaths parses the property paths passed to a command's --redact-path flags.
+func parseRedactPaths(paths []string) ([]resource.PropertyPath, error) {
+	var result []resource.PropertyPath
+	for _, p := range paths {
+		path, err := resource.ParsePropertyPath(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --redact-path '%s'", p)
+		}
+		result = append(result, path)
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/encoding"
+	"github.com/pulumi/pulumi/pkg/resource/cfnconv"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackCloudFormationCmd() *cobra.Command {
+	var stackName string
+
+	cmd := &cobra.Command{
+		Use:   "cloudformation <file>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Export a stack's AWS resources as a CloudFormation template",
+		Long: "Export a stack's AWS resources as a CloudFormation template.\n" +
+			"\n" +
+			"This command renders the AWS resources in your stack's most recent deployment as an AWS\n" +
+			"CloudFormation template, for use with CloudFormation-aware auditing and migration tools.\n" +
+			"References between resources are rendered as Ref and Fn::GetAtt intrinsic functions. The\n" +
+			"template is written as JSON unless the file has a .yaml or .yml extension.\n" +
+			"\n" +
+			"The conversion is best-effort: resources whose types have no known CloudFormation equivalent\n" +
+			"are skipped with a warning, and property names are not checked against the CloudFormation\n" +
+			"resource specification.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			if snap == nil {
+				return errors.New("the current stack has no resources to export")
+			}
+
+			tmpl, skipped := cfnconv.Convert(snap.Resources)
+			tmpl.Description = fmt.Sprintf("Resources exported from Pulumi stack %s", s.Ref().Name())
+			for _, res := range skipped {
+				cmdutil.Diag().Warningf(diag.Message(res.URN,
+					fmt.Sprintf("skipping resource of type %s, which has no CloudFormation equivalent", res.Type)))
+			}
+
+			m, _ := encoding.Detect(args[0])
+			if m == nil {
+				return errors.Errorf("unrecognized template file extension for %s", args[0])
+			}
+			b, err := m.Marshal(tmpl)
+			if err != nil {
+				return err
+			}
+			// The template holds the resources' inputs, which may be sensitive, so only its owner may read it.
+			if err = ioutil.WriteFile(args[0], b, 0600); err != nil {
+				return err
+			}
+
+			cmd.Printf("Wrote CloudFormation template with %d resources to `%s`", len(tmpl.Resources), args[0])
+			cmd.Println()
+			return nil
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
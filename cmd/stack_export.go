@@ -21,13 +21,21 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 )
 
 func newStackExportCmd() *cobra.Command {
 	var file string
 	var stackName string
+	var version int
+	var downgrade bool
+	var updateVersion int
+	var redacted bool
+	var redactPaths []string
 
 	cmd := &cobra.Command{
 		Use:   "export",
@@ -38,7 +46,27 @@ func newStackExportCmd() *cobra.Command {
 			"The deployment can then be hand-edited and used to update the stack via\n" +
 			"`pulumi stack import`. This process may be used to correct inconsistencies\n" +
 			"in a stack's state due to failed deployments, manual changes to cloud\n" +
-			"resources, etc.",
+			"resources, etc.\n" +
+			"\n" +
+			"The deployment is a JSON document whose `version` field gives its schema version\n" +
+			"and whose `deployment` field holds the stack's manifest, every resource along\n" +
+			"with its inputs and outputs, and any pending operations. It may be imported into\n" +
+			"a stack on any backend, which makes it suitable for migrating stacks and for\n" +
+			"backups.\n" +
+			"\n" +
+			"By default, the deployment is written using the current schema version. Pass\n" +
+			"`--version` to write an older schema for use with older versions of the CLI;\n" +
+			"because this may discard information, `--downgrade` must also be passed.\n" +
+			"\n" +
+			"Pass `--update-version` to export the deployment produced by an earlier update\n" +
+			"of the stack, as numbered by `pulumi history`. Importing that deployment with\n" +
+			"`pulumi stack import` rolls the stack's state back to that update; to roll its\n" +
+			"resources back as well, use `pulumi rollback`.\n" +
+			"\n" +
+			"Pass `--redacted` to replace the stack's secret provider settings, along with the\n" +
+			"values at any `--redact-path`, with digests of their values. A redacted deployment\n" +
+			"may be shared with those who need to inspect the stack's state but not its secrets;\n" +
+			"it must not be imported.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
@@ -50,10 +78,26 @@ func newStackExportCmd() *cobra.Command {
 				return err
 			}
 
-			deployment, err := s.ExportDeployment(commandContext())
+			var deployment *apitype.UntypedDeployment
+			if updateVersion > 0 {
+				deployment, err = s.Backend().ExportDeploymentVersion(commandContext(), s.Ref(), updateVersion)
+			} else {
+				deployment, err = s.ExportDeployment(commandContext())
+			}
 			if err != nil {
 				return err
 			}
+			if redacted || len(redactPaths) > 0 {
+				if deployment, err = redactDeployment(s, deployment, redactPaths); err != nil {
+					return err
+				}
+			}
+			if deployment, err = stack.MigrateUntypedDeployment(deployment, version, downgrade); err != nil {
+				if err == stack.ErrDeploymentDowngradeRequired {
+					return errors.Errorf("refusing to write deployment schema version %d without --downgrade", version)
+				}
+				return err
+			}
 
 			// Read from stdin or a specified file.
 			writer := os.Stdout
@@ -77,5 +121,44 @@ func newStackExportCmd() *cobra.Command {
 		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
 	cmd.PersistentFlags().StringVarP(
 		&file, "file", "", "", "A filename to write stack output to")
+	cmd.PersistentFlags().IntVar(
+		&version, "version", apitype.DeploymentSchemaVersionCurrent, "The deployment schema version to write")
+	cmd.PersistentFlags().BoolVar(
+		&downgrade, "downgrade", false, "Allow writing a deployment schema version older than the stack's")
+	cmd.PersistentFlags().IntVar(
+		&updateVersion, "update-version", 0, "The update whose deployment to export (defaults to the latest)")
+	cmd.PersistentFlags().BoolVar(
+		&redacted, "redacted", false,
+		"Replace secret provider settings and the values at any --redact-path with digests")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactPaths, "redact-path", []string{},
+		"A property path, e.g. 'auth.password', whose values to redact in every resource. May be passed multiple times "+
+			"and implies --redacted")
 	return cmd
 }
+
+// redactDeployment returns a copy of the given deployment of the stack with its secrets and the values at the given
+// paths redacted.
+func redactDeployment(s backend.Stack, deployment *apitype.UntypedDeployment,
+	redactPaths []string) (*apitype.UntypedDeployment, error) {
+
+	paths, err := parseRedactPaths(redactPaths)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(deployment)
+	if err != nil {
+		return nil, err
+	}
+	if snap, err = backend.RedactStackSnapshot(s, snap, paths); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(stack.SerializeDeployment(snap))
+	if err != nil {
+		return nil, err
+	}
+	return &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
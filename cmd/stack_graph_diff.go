@@ -0,0 +1,146 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/graph"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackGraphDiffCmd() *cobra.Command {
+	var stackName string
+	var file string
+	var format string
+	var changesOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "graph-diff <from-version> [to-version]",
+		Args:  cmdutil.RangeArgs(1, 2),
+		Short: "Compare a stack's dependency graph between two updates",
+		Long: "Compare a stack's dependency graph between two updates.\n" +
+			"\n" +
+			"This command compares the resource graphs produced by two updates of the stack,\n" +
+			"as numbered by `pulumi history`. If only one version is given, it is compared\n" +
+			"with the stack's most recent deployment. The diff lists the resources and the\n" +
+			"dependency, parent, and provider edges that were added or removed, along with\n" +
+			"the resources whose inputs or outputs changed. Changed properties are reported\n" +
+			"as digests of their values, so the diff never reveals the values themselves.\n" +
+			"\n" +
+			"The diff is written in the DOT format by default, with added resources and edges\n" +
+			"in green, changed resources in orange, and removed resources and edges in red.\n" +
+			"Pass `--format json` to write it as JSON instead.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if format != "dot" && format != "json" {
+				return errors.Errorf("unknown format '%s'; expected 'dot' or 'json'", format)
+			}
+
+			s, err := requireStack(stackName, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			olds, err := getVersionResources(s, args[0])
+			if err != nil {
+				return err
+			}
+			var news []*resource.State
+			if len(args) > 1 {
+				news, err = getVersionResources(s, args[1])
+			} else {
+				var snap *deploy.Snapshot
+				if snap, err = s.Snapshot(commandContext()); err == nil && snap != nil {
+					news = snap.Resources
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			diff := graph.DiffResources(olds, news)
+			if changesOnly {
+				diff = diff.Changes()
+			}
+
+			writer := os.Stdout
+			if file != "" {
+				writer, err = os.Create(file)
+				if err != nil {
+					return errors.Wrap(err, "could not open file")
+				}
+			}
+
+			if format == "json" {
+				enc := json.NewEncoder(writer)
+				enc.SetIndent("", "    ")
+				err = enc.Encode(diff)
+			} else {
+				err = diff.PrintDOT(writer)
+			}
+			if err != nil {
+				return errors.Wrap(err, "could not write graph diff")
+			}
+			if file != "" {
+				return writer.Close()
+			}
+			return nil
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&file, "file", "", "A filename to write the diff to")
+	cmd.PersistentFlags().StringVar(
+		&format, "format", "dot", "The format to write the diff in: 'dot' or 'json'")
+	cmd.PersistentFlags().BoolVar(
+		&changesOnly, "changes-only", false,
+		"Only include the resources and edges that changed, along with the resources that changed edges connect")
+	return cmd
+}
+
+// getVersionResources returns the resources of the deployment produced by the given version of the stack.
+func getVersionResources(s backend.Stack, version string) ([]*resource.State, error) {
+	v, err := strconv.Atoi(version)
+	if err != nil || v < 1 {
+		return nil, errors.Errorf("invalid update version '%s'; versions are numbered from 1", version)
+	}
+	deployment, err := s.Backend().ExportDeploymentVersion(commandContext(), s.Ref(), v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "exporting the deployment of version %d", v)
+	}
+	snap, err := stack.DeserializeUntypedDeployment(deployment)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading the deployment of version %d", v)
+	}
+	if snap == nil {
+		return nil, nil
+	}
+	return snap.Resources, nil
+}
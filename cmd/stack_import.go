@@ -26,7 +26,9 @@ import (
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend/display"
 	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 )
 
@@ -43,7 +45,11 @@ func newStackImportCmd() *cobra.Command {
 			"A deployment that was exported from a stack using `pulumi stack export` and\n" +
 			"hand-edited to correct inconsistencies due to failed updates, manual changes\n" +
 			"to cloud resources, etc. can be reimported to the stack using this command.\n" +
-			"The updated deployment will be read from standard in.",
+			"The updated deployment will be read from standard in.\n" +
+			"\n" +
+			"Before anything is written, the deployment is checked to ensure that all of its\n" +
+			"resources belong to the stack and that it passes the same integrity checks as\n" +
+			"`pulumi state repair`. Pass `--force` to import it anyway.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
@@ -90,20 +96,16 @@ func newStackImportCmd() *cobra.Command {
 			}
 
 			var result error
-			for _, res := range snapshot.Resources {
-				if res.URN.Stack() != stackName {
-					msg := fmt.Sprintf("resource '%s' is from a different stack (%s != %s)",
-						res.URN, res.URN.Stack(), stackName)
-					if force {
-						// If --force was passed, just issue a warning and proceed anyway.
-						// Note: we could associate this diagnostic with the resource URN
-						// we have.  However, this sort of message seems to be better as
-						// something associated with the stack as a whole.
-						cmdutil.Diag().Warningf(diag.Message("" /*urn*/, msg))
-					} else {
-						// Otherwise, gather up an error so that we can quit before doing damage.
-						result = multierror.Append(result, errors.New(msg))
-					}
+			for _, msg := range validateImportedSnapshot(stackName, snapshot) {
+				if force {
+					// If --force was passed, just issue a warning and proceed anyway.
+					// Note: we could associate this diagnostic with the resource URN
+					// we have.  However, this sort of message seems to be better as
+					// something associated with the stack as a whole.
+					cmdutil.Diag().Warningf(diag.Message("" /*urn*/, msg))
+				} else {
+					// Otherwise, gather up an error so that we can quit before doing damage.
+					result = multierror.Append(result, errors.New(msg))
 				}
 			}
 			if result != nil {
@@ -150,3 +152,20 @@ func newStackImportCmd() *cobra.Command {
 
 	return cmd
 }
+
+// validateImportedSnapshot checks a snapshot that is about to be imported into the given stack, returning a message
+// for each problem found. The snapshot must contain only resources from the target stack and must pass integrity
+// verification; the latter matters because some backends only verify a checkpoint after it has been written.
+func validateImportedSnapshot(stackName tokens.QName, snapshot *deploy.Snapshot) []string {
+	var msgs []string
+	for _, res := range snapshot.Resources {
+		if res.URN.Stack() != stackName {
+			msgs = append(msgs, fmt.Sprintf("resource '%s' is from a different stack (%s != %s)",
+				res.URN, res.URN.Stack(), stackName))
+		}
+	}
+	if err := snapshot.VerifyIntegrity(); err != nil {
+		msgs = append(msgs, fmt.Sprintf("the deployment is not well-formed: %v", err))
+	}
+	return msgs
+}
@@ -0,0 +1,42 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestValidateImportedSnapshot(t *testing.T) {
+	parent := &resource.State{Type: "a:b:c", URN: "urn:pulumi:dev::proj::a:b:c::parent"}
+	child := &resource.State{Type: "a:b:c", URN: "urn:pulumi:dev::proj::a:b:c::child", Parent: parent.URN}
+
+	snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{parent, child}, nil)
+	assert.Empty(t, validateImportedSnapshot("dev", snap))
+
+	msgs := validateImportedSnapshot("prod", snap)
+	assert.Len(t, msgs, 2)
+
+	snap = deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{child, parent}, nil)
+	msgs = validateImportedSnapshot("dev", snap)
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, "the deployment is not well-formed: child resource urn:pulumi:dev::proj::a:b:c::child's "+
+			"parent urn:pulumi:dev::proj::a:b:c::parent comes after it", msgs[0])
+	}
+}
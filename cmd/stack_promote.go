@@ -0,0 +1,226 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackPromoteCmd() *cobra.Command {
+	var exclude []string
+	var skipPreview bool
+
+	// Flags for engine.UpdateOptions.
+	var debug bool
+	var diffDisplay bool
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "promote <from> <to>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Promote one stack's configuration to another stack",
+		Long: "Promote one stack's configuration to another stack.\n" +
+			"\n" +
+			"This command copies the configuration of the <from> stack into the <to> stack, for example to\n" +
+			"move a change from a development stack to a staging stack and then on to production. Secret\n" +
+			"values are decrypted with the <from> stack's key and re-encrypted with the <to> stack's key.\n" +
+			"Configuration that is only present in the <to> stack is kept, and keys passed with `--exclude`\n" +
+			"are left untouched.\n" +
+			"\n" +
+			"The name and version of the <from> stack's latest update are recorded in the <to> stack's\n" +
+			"`" + apitype.PromotedFromTag + "` and `" + apitype.PromotedFromVersionTag + "` tags. Afterwards, the\n" +
+			"update of the <to> stack is previewed; run `pulumi up` on it to perform the promotion.\n" +
+			"\n" +
+			"Both stacks must belong to the project in the current directory.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.UpdateOptions{
+				Engine: engine.UpdateOptions{
+					Parallel: parallel,
+					Debug:    debug,
+				},
+				Display: display.Options{
+					Color:         cmdutil.GetGlobalColorization(),
+					IsInteractive: cmdutil.Interactive(),
+					DiffDisplay:   diffDisplay,
+					Debug:         debug,
+				},
+			}
+
+			from, err := requireStack(args[0], false, opts.Display, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			to, err := requireStack(args[1], false, opts.Display, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			var excluded []config.Key
+			for _, k := range exclude {
+				key, err := parseConfigKey(k)
+				if err != nil {
+					return errors.Wrapf(err, "invalid configuration key '%s'", k)
+				}
+				excluded = append(excluded, key)
+			}
+
+			// Copy the configuration, re-encrypting any secrets for the target stack.
+			fromStack, err := loadProjectStack(from)
+			if err != nil {
+				return err
+			}
+			toStack, err := loadProjectStack(to)
+			if err != nil {
+				return err
+			}
+			var decrypter config.Decrypter
+			var encrypter config.Encrypter
+			if fromStack.Config.HasSecureValue() {
+				if decrypter, err = backend.GetStackCrypter(from); err != nil {
+					return err
+				}
+				if encrypter, err = backend.GetStackCrypter(to); err != nil {
+					return err
+				}
+			}
+			toStack.Config, err = promoteConfig(fromStack.Config, toStack.Config, excluded, decrypter, encrypter)
+			if err != nil {
+				return err
+			}
+			if err = saveProjectStack(to, toStack); err != nil {
+				return err
+			}
+
+			// Record the update of the source stack that is being promoted.
+			if err = recordPromotion(from, to); err != nil {
+				return err
+			}
+
+			fmt.Printf("Promoted the configuration of stack '%s' to stack '%s'\n", from.Ref(), to.Ref())
+			if skipPreview {
+				return nil
+			}
+
+			proj, root, err := readProject()
+			if err != nil {
+				return err
+			}
+			m, err := getUpdateMetadata("", root)
+			if err != nil {
+				return errors.Wrap(err, "gathering environment metadata")
+			}
+			_, err = to.Preview(commandContext(), backend.UpdateOperation{
+				Proj:   proj,
+				Root:   root,
+				M:      m,
+				Opts:   opts,
+				Scopes: cancellationScopes,
+			})
+			return PrintEngineError(err)
+		}),
+	}
+
+	cmd.PersistentFlags().StringArrayVar(
+		&exclude, "exclude", []string{},
+		"A configuration key to leave untouched in the target stack. May be passed multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&skipPreview, "skip-preview", false,
+		"Do not preview the target stack's update after promoting its configuration")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", defaultParallel,
+		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+
+	return cmd
+}
+
+// promoteConfig returns the configuration that results from promoting the source configuration into the target
+// configuration. Values from the source replace those in the target, except for the excluded keys. Secrets are
+// decrypted with the source's decrypter and re-encrypted with the target's encrypter.
+func promoteConfig(source, target config.Map, exclude []config.Key,
+	decrypter config.Decrypter, encrypter config.Encrypter) (config.Map, error) {
+
+	excluded := make(map[config.Key]bool)
+	for _, k := range exclude {
+		excluded[k] = true
+	}
+
+	result := make(config.Map)
+	for k, v := range target {
+		result[k] = v
+	}
+	for k, v := range source {
+		if excluded[k] {
+			continue
+		}
+		if !v.Secure() {
+			result[k] = v
+			continue
+		}
+
+		plaintext, err := v.Value(decrypter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decrypting secret '%s'", k)
+		}
+		ciphertext, err := encrypter.EncryptValue(plaintext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encrypting secret '%s'", k)
+		}
+		result[k] = config.NewSecureValue(ciphertext)
+	}
+	return result, nil
+}
+
+// recordPromotion tags the target stack with the name of the source stack and the version of the source stack's
+// latest update, so that the history of a promotion pipeline can be traced.
+func recordPromotion(from, to backend.Stack) error {
+	ctx := commandContext()
+	history, err := from.Backend().GetHistory(ctx, from.Ref())
+	if err != nil {
+		return errors.Wrapf(err, "getting the history of stack '%s'", from.Ref())
+	}
+
+	tags, err := backend.GetStackTags(ctx, to)
+	if err != nil {
+		return err
+	}
+	if tags == nil {
+		tags = make(map[apitype.StackTagName]string)
+	}
+	tags[apitype.PromotedFromTag] = from.Ref().String()
+	delete(tags, apitype.PromotedFromVersionTag)
+	if len(history) > 0 && history[0].Version != 0 {
+		tags[apitype.PromotedFromVersionTag] = strconv.Itoa(history[0].Version)
+	}
+	return backend.UpdateStackTags(ctx, to, tags)
+}
@@ -0,0 +1,60 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestPromoteConfig(t *testing.T) {
+	fromCrypter := config.NewSymmetricCrypterFromPassphrase("dev", []byte("dev-salt"))
+	toCrypter := config.NewSymmetricCrypterFromPassphrase("prod", []byte("prod-salt"))
+
+	secret, err := fromCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	size, region, password, name := config.MustMakeKey("app", "size"), config.MustMakeKey("aws", "region"),
+		config.MustMakeKey("app", "password"), config.MustMakeKey("app", "name")
+	source := config.Map{
+		size:     config.NewValue("small"),
+		region:   config.NewValue("us-west-2"),
+		password: config.NewSecureValue(secret),
+	}
+	target := config.Map{
+		region: config.NewValue("us-east-1"),
+		name:   config.NewValue("production"),
+	}
+
+	result, err := promoteConfig(source, target, []config.Key{region}, fromCrypter, toCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, config.NewValue("small"), result[size])
+	assert.Equal(t, config.NewValue("us-east-1"), result[region])
+	assert.Equal(t, config.NewValue("production"), result[name])
+
+	// The secret is re-encrypted for the target stack.
+	assert.True(t, result[password].Secure())
+	plaintext, err := result[password].Value(toCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+	_, err = result[password].Value(fromCrypter)
+	assert.Error(t, err)
+
+	// The target's configuration is not modified in place.
+	assert.Len(t, target, 2)
+}
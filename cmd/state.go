@@ -46,6 +46,11 @@ troubleshooting a stack or when performing specific edits that otherwise would r
 	}
 
 	cmd.AddCommand(newStateDeleteCommand())
+	cmd.AddCommand(newStateGCCommand())
+	cmd.AddCommand(newStateMoveCommand())
+	cmd.AddCommand(newStateQueryCommand())
+	cmd.AddCommand(newStateRepairCommand())
+	cmd.AddCommand(newStateShowCommand())
 	cmd.AddCommand(newStateUnprotectCommand())
 	return cmd
 }
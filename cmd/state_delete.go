@@ -32,8 +32,9 @@ func newStateDeleteCommand() *cobra.Command {
 	var stack string
 
 	cmd := &cobra.Command{
-		Use:   "delete <resource URN>",
-		Short: "Deletes a resource from a stack's state",
+		Use:     "delete <resource URN>",
+		Aliases: []string{"rm"},
+		Short:   "Deletes a resource from a stack's state",
 		Long: `Deletes a resource from a stack's state
 
 This command deletes a resource from a stack's state, as long as it is safe to do so. Resources can't be deleted if
@@ -0,0 +1,175 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+	surveycore "gopkg.in/AlecAivazis/survey.v1/core"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateGCCommand() *cobra.Command {
+	var dryRun bool
+	var force bool
+	var stackName string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove unreachable resources and unused blobs from a stack's state",
+		Long: `Remove unreachable resources and unused blobs from a stack's state
+
+This command finds the resources in a stack's state that cannot be reached from the stack's root resource, through
+parents, dependencies, and providers. Such resources are typically left behind by interrupted updates or by edits to
+the state. When the stack is managed by a local backend, it also finds the values in the backend's blob store that no
+checkpoint refers to. Everything found is listed and, after confirmation, removed.
+
+Removing a resource from the state does not delete the cloud resource it describes. Protected resources will not be
+removed unless it is specifically requested using the --force flag.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runStateGC(stackName, dryRun, force, yes)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false,
+		"List what would be removed without removing it")
+	cmd.PersistentFlags().BoolVar(
+		&force, "force", false,
+		"Remove unreachable resources even if they are protected")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Remove everything that is found without prompting")
+	return cmd
+}
+
+func runStateGC(stackName string, dryRun, force, yes bool) error {
+	opts := display.Options{
+		Color: cmdutil.GetGlobalColorization(),
+	}
+	if !dryRun && !yes && !cmdutil.Interactive() {
+		return errors.New("--yes or --dry-run must be passed in to collect a stack's state non-interactively")
+	}
+
+	s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+	if err != nil {
+		return err
+	}
+	snap, err := s.Snapshot(commandContext())
+	if err != nil {
+		return err
+	}
+
+	var unreachable []*resource.State
+	if snap != nil {
+		unreachable = edit.UnreachableResources(snap)
+	}
+	var blobs []string
+	local, isLocal := s.Backend().(filestate.Backend)
+	if isLocal {
+		if blobs, err = local.UnreferencedBlobs(); err != nil {
+			return errors.Wrap(err, "searching for unreferenced blobs")
+		}
+	}
+
+	if len(unreachable) == 0 && len(blobs) == 0 {
+		fmt.Println("Nothing to collect")
+		return nil
+	}
+
+	var protected int
+	if len(unreachable) > 0 {
+		fmt.Printf("%d resource(s) are unreachable from the root stack resource:\n", len(unreachable))
+		for _, res := range unreachable {
+			note := ""
+			if res.Protect {
+				protected++
+				note = opts.Color.Colorize(colors.SpecWarning + " (protected)" + colors.Reset)
+			}
+			fmt.Printf("    %s%s\n", res.URN, note)
+		}
+	}
+	if len(blobs) > 0 {
+		fmt.Printf("%d blob(s) are not referred to by any checkpoint:\n", len(blobs))
+		for _, ref := range blobs {
+			fmt.Printf("    %s\n", ref)
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+	if protected > 0 && !force {
+		return errors.Errorf("%d unreachable resource(s) are protected; re-run this command with --force to remove "+
+			"them, or unprotect them with `pulumi state unprotect`", protected)
+	}
+
+	if !yes {
+		surveycore.DisableColor = true
+		surveycore.QuestionIcon = ""
+		confirm := false
+		prompt := opts.Color.Colorize(colors.SpecPrompt + "Remove everything listed above?" + colors.Reset)
+		if err = survey.AskOne(&survey.Confirm{
+			Message: prompt,
+		}, &confirm, nil); err != nil || !confirm {
+			return errors.New("confirmation declined")
+		}
+	}
+
+	if len(unreachable) > 0 {
+		edit.DeleteResources(snap, unreachable)
+
+		// Persist the collected snapshot through the backend, exactly as the other state edits do.
+		bytes, err := json.Marshal(stack.SerializeDeployment(snap))
+		if err != nil {
+			return err
+		}
+		dep := apitype.UntypedDeployment{
+			Version:    apitype.DeploymentSchemaVersionCurrent,
+			Deployment: bytes,
+		}
+		if err = s.ImportDeployment(commandContext(), &dep); err != nil {
+			return err
+		}
+	}
+
+	// Only the blobs found before the resources were removed are collected; any blobs that only the removed resources
+	// referred to are still referred to by the stack's backups and history.
+	if len(blobs) > 0 {
+		if err = local.RemoveBlobs(blobs); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Removed %d resource(s) and %d blob(s)\n", len(unreachable), len(blobs))
+	return nil
+}
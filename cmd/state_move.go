@@ -0,0 +1,63 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateMoveCommand() *cobra.Command {
+	var stack string
+
+	cmd := &cobra.Command{
+		Use:   "mv <old resource URN> <new resource URN>",
+		Short: "Renames a resource in a stack's state",
+		Long: `Renames a resource in a stack's state
+
+This command changes the URN of a resource in a stack's state without touching the underlying cloud resource. Every
+reference to the resource by other resources in the state, including parents, dependencies, and provider references,
+is updated to use the new URN. Only the name of a resource may be changed; the new URN must have the same stack,
+project, and type as the old URN.`,
+		Args: cmdutil.ExactArgs(2),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			oldURN, newURN := resource.URN(args[0]), resource.URN(args[1])
+			err := runStateEdit(stack, oldURN, func(snap *deploy.Snapshot, res *resource.State) error {
+				return edit.RenameResource(snap, res, newURN)
+			})
+			if err != nil {
+				if _, ok := err.(edit.ResourceAlreadyExistsError); ok {
+					return errors.Errorf(
+						"This resource can't be renamed because a resource with URN %q already exists", newURN)
+				}
+				return err
+			}
+			fmt.Println("Resource renamed successfully")
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
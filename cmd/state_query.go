@@ -0,0 +1,102 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource/query"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateQueryCommand() *cobra.Command {
+	var stackName string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "query <query>",
+		Short: "Find the resources in a stack's state that match a query",
+		Long: `Find the resources in a stack's state that match a query
+
+This command prints the resources in a stack's state that satisfy the given query. The state is not modified.
+
+A query is a boolean expression of comparisons joined by '&&', '||', and '!', and grouped with parentheses, e.g.
+
+    pulumi state query 'type == aws:ec2/instance:Instance && props.tags.env == "prod"'
+
+Comparisons name a field of a resource on the left and a value on the right. The fields are 'urn', 'type', 'name',
+'id', 'parent', 'provider', 'custom', 'protect', 'delete', and 'external', and property paths beneath 'inputs' or
+'outputs' (or 'props', which is short for 'outputs'), such as 'outputs.tags["kubernetes.io/name"]'. Values are quoted
+strings, or bare words that are compared as numbers, bools, or 'null' where the field holds one. The operators are
+'==', '!=', '<', '<=', '>', '>=', and '=~', which matches a regular expression. A field on its own is true if it is
+set to a value other than null, false, or the empty string.
+
+With --json, the matching resources are printed in the same JSON format used by 'pulumi stack export'.`,
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			q, err := query.Parse(args[0])
+			if err != nil {
+				return errors.Wrap(err, "parsing query")
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			if snap == nil {
+				return errors.New("the current stack has no state")
+			}
+
+			matches := q.Select(snap.Resources)
+			if jsonOut {
+				resources := make([]apitype.ResourceV3, 0, len(matches))
+				for _, res := range matches {
+					resources = append(resources, stack.SerializeResource(res))
+				}
+				return printJSON(resources)
+			}
+
+			rows := []cmdutil.TableRow{}
+			for _, res := range matches {
+				rows = append(rows, cmdutil.TableRow{
+					Columns: []string{string(res.Type), string(res.URN.Name()), string(res.ID)},
+				})
+			}
+			cmdutil.PrintTable(cmdutil.Table{
+				Headers: []string{"TYPE", "NAME", "ID"},
+				Rows:    rows,
+			})
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit the matching resources as JSON")
+	return cmd
+}
@@ -0,0 +1,146 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+	surveycore "gopkg.in/AlecAivazis/survey.v1/core"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateRepairCommand() *cobra.Command {
+	var stackName string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Verify a stack's state and repair any problems found",
+		Long: `Verify a stack's state and repair any problems found
+
+This command checks the referential integrity of a stack's state: that providers, parents and dependencies exist and
+precede the resources that refer to them, that no two live resources share a URN, that pending operations refer to
+resources in the state, and that URN-valued properties refer to resources that still exist. Each problem that can be
+fixed automatically is offered as a repair; problems that cannot are reported so that they can be fixed by hand.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runStateRepair(stackName, yes)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Apply every available repair without prompting")
+	return cmd
+}
+
+func runStateRepair(stackName string, yes bool) error {
+	opts := display.Options{
+		Color: cmdutil.GetGlobalColorization(),
+	}
+	if !yes && !cmdutil.Interactive() {
+		return errors.New("--yes must be passed in to repair a stack's state non-interactively")
+	}
+
+	// The whole point of this command is to load state that fails verification, so don't refuse to load it.
+	filestate.DisableIntegrityChecking = true
+
+	s, err := requireStack(stackName, true, opts, true /*setCurrent*/)
+	if err != nil {
+		return err
+	}
+	snap, err := s.Snapshot(commandContext())
+	if err != nil {
+		return err
+	}
+
+	problems := snap.Verify()
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return nil
+	}
+
+	surveycore.DisableColor = true
+	surveycore.QuestionIcon = ""
+	repaired, unrepaired := 0, 0
+	for _, problem := range problems {
+		severity := colors.Red + "error" + colors.Reset
+		if problem.Warning {
+			severity = colors.Yellow + "warning" + colors.Reset
+		}
+		fmt.Println(opts.Color.Colorize(severity + ": " + problem.Message))
+
+		repair := edit.RepairFor(problem)
+		if repair == nil {
+			unrepaired++
+			fmt.Println("  this problem must be fixed by hand")
+			continue
+		}
+
+		if !yes {
+			confirm := false
+			prompt := opts.Color.Colorize(colors.SpecPrompt + "Repair: " + repair.Description + "?" + colors.Reset)
+			if err = survey.AskOne(&survey.Confirm{
+				Message: prompt,
+			}, &confirm, nil); err != nil {
+				return err
+			}
+			if !confirm {
+				unrepaired++
+				continue
+			}
+		}
+
+		repair.Apply(snap)
+		repaired++
+	}
+
+	if repaired == 0 {
+		return errors.New("no repairs were applied")
+	}
+
+	// Persist the repaired snapshot through the backend, exactly as the other state edits do.
+	bytes, err := json.Marshal(stack.SerializeDeployment(snap))
+	if err != nil {
+		return err
+	}
+	dep := apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}
+	if err = s.ImportDeployment(commandContext(), &dep); err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied %d repair(s)\n", repaired)
+	if err = snap.VerifyIntegrity(); err != nil {
+		return errors.Wrapf(err, "%d problem(s) remain", unrepaired)
+	}
+	return nil
+}
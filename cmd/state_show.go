@@ -0,0 +1,113 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateShowCommand() *cobra.Command {
+	var stackName string
+	var provenance bool
+
+	cmd := &cobra.Command{
+		Use:   "show <resource URN>",
+		Short: "Shows a resource in a stack's state",
+		Long: `Shows a resource in a stack's state
+
+This command prints the state of a single resource, in the same JSON format used by 'pulumi stack export'. The state is
+not modified.
+
+With --provenance, the command instead prints the path of each of the resource's output properties, along with the
+provider operation that last set its value and when.`,
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(commandContext())
+			if err != nil {
+				return err
+			}
+			if snap == nil {
+				return errors.New("the current stack has no state")
+			}
+
+			res, err := locateStackResource(opts, snap, resource.URN(args[0]))
+			if err != nil {
+				return err
+			}
+			if provenance {
+				printProvenance(res)
+				return nil
+			}
+			b, err := json.MarshalIndent(stack.SerializeResource(res), "", "    ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVar(
+		&provenance, "provenance", false,
+		"Show the provider operation that last set each output property, and when")
+	return cmd
+}
+
+// printProvenance prints a table of the resource's output properties and the operations that last set them.
+func printProvenance(res *resource.State) {
+	if len(res.Provenance) == 0 {
+		fmt.Printf("No provenance has been recorded for %s\n", res.URN)
+		return
+	}
+
+	paths := make([]string, 0, len(res.Provenance))
+	for path := range res.Provenance {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	rows := []cmdutil.TableRow{}
+	for _, path := range paths {
+		source := res.Provenance[path]
+		rows = append(rows, cmdutil.TableRow{
+			Columns: []string{path, source.Op, source.Time.Local().Format(time.RFC3339)},
+		})
+	}
+	cmdutil.PrintTable(cmdutil.Table{
+		Headers: []string{"PROPERTY", "OPERATION", "TIME"},
+		Rows:    rows,
+	})
+}
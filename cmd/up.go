@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -50,15 +51,25 @@ func newUpCmd() *cobra.Command {
 	var configArray []string
 
 	// Flags for engine.UpdateOptions.
+	var allowDestroys []string
 	var analyzers []string
 	var diffDisplay bool
+	var forceUnprotect bool
 	var parallel int
+	var policyPackPaths []string
+	var readyPollInterval time.Duration
+	var readyTimeout time.Duration
+	var redactions []string
 	var refresh bool
+	var replaces []string
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
 	var skipPreview bool
 	var suppressOutputs bool
+	var targets []string
+	var targetDependencies bool
+	var targetDependents bool
 	var yes bool
 
 	// up implementation used when the source of the Pulumi program is in the current working directory.
@@ -90,11 +101,30 @@ func newUpCmd() *cobra.Command {
 			return errors.Wrap(err, "gathering environment metadata")
 		}
 
+		redaction, err := redactionPolicy(redactions)
+		if err != nil {
+			return err
+		}
+		packs, err := policyPacks(policyPackPaths)
+		if err != nil {
+			return err
+		}
+
 		opts.Engine = engine.UpdateOptions{
-			Analyzers: analyzers,
-			Parallel:  parallel,
-			Debug:     debug,
-			Refresh:   refresh,
+			AllowDestroy:       targetURNs(allowDestroys),
+			Analyzers:          analyzers,
+			ForceUnprotect:     forceUnprotect,
+			Targets:            targetURNs(targets),
+			TargetDependencies: targetDependencies,
+			TargetDependents:   targetDependents,
+			ReplaceTargets:     targetURNs(replaces),
+			Parallel:           parallel,
+			Debug:              debug,
+			Refresh:            refresh,
+			RedactionPolicy:    redaction,
+			Policies:           packs,
+			ReadyTimeout:       readyTimeout,
+			ReadyPollInterval:  readyPollInterval,
 		}
 
 		changes, err := s.Update(commandContext(), backend.UpdateOperation{
@@ -229,11 +259,30 @@ func newUpCmd() *cobra.Command {
 			return errors.Wrap(err, "gathering environment metadata")
 		}
 
+		redaction, err := redactionPolicy(redactions)
+		if err != nil {
+			return err
+		}
+		packs, err := policyPacks(policyPackPaths)
+		if err != nil {
+			return err
+		}
+
 		opts.Engine = engine.UpdateOptions{
-			Analyzers: analyzers,
-			Parallel:  parallel,
-			Debug:     debug,
-			Refresh:   refresh,
+			AllowDestroy:       targetURNs(allowDestroys),
+			Analyzers:          analyzers,
+			ForceUnprotect:     forceUnprotect,
+			Targets:            targetURNs(targets),
+			TargetDependencies: targetDependencies,
+			TargetDependents:   targetDependents,
+			ReplaceTargets:     targetURNs(replaces),
+			Parallel:           parallel,
+			Debug:              debug,
+			Refresh:            refresh,
+			RedactionPolicy:    redaction,
+			Policies:           packs,
+			ReadyTimeout:       readyTimeout,
+			ReadyPollInterval:  readyPollInterval,
 		}
 
 		// TODO for the URL case:
@@ -329,18 +378,40 @@ func newUpCmd() *cobra.Command {
 		"Optional message to associate with the update operation")
 
 	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().StringArrayVar(
+		&allowDestroys, "allow-destroy", []string{},
+		"Allow a single resource URN that is guarded against deletion to be deleted or replaced."+
+			" Multiple resources can be specified using --allow-destroy urn1 --allow-destroy urn2")
 	cmd.PersistentFlags().StringSliceVar(
 		&analyzers, "analyzer", []string{},
 		"Run one or more analyzers as part of this update")
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().BoolVar(
+		&forceUnprotect, "force-unprotect", false,
+		"Allow protected resources to be deleted or replaced")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().StringArrayVar(
+		&policyPackPaths, "policy-pack", []string{},
+		"Evaluate the policy pack in the given JSON or YAML file against each resource. Multiple packs can be"+
+			" specified using --policy-pack file1 --policy-pack file2")
+	cmd.PersistentFlags().DurationVar(
+		&readyPollInterval, "ready-poll-interval", 0,
+		"How often to refresh a resource while waiting for it to satisfy its ready conditions. Defaults to 5s")
+	cmd.PersistentFlags().DurationVar(
+		&readyTimeout, "ready-timeout", 0,
+		"How long a resource may take to satisfy its ready conditions after it is created or updated. Defaults to"+
+			" the timeout that its provider declares, or 10m")
 	cmd.PersistentFlags().BoolVarP(
 		&refresh, "refresh", "r", false,
 		"Refresh the state of the stack's resources before this update")
+	cmd.PersistentFlags().StringArrayVar(
+		&redactions, "redact", []string{},
+		"Redact property values whose keys or paths match a glob pattern, such as '*password*', from logs and"+
+			" displayed changes. Multiple patterns can be specified using --redact pattern1 --redact pattern2")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -356,6 +427,20 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&suppressOutputs, "suppress-outputs", false,
 		"Suppress display of stack outputs (in case they contain sensitive values)")
+	cmd.PersistentFlags().StringArrayVarP(
+		&targets, "target", "t", []string{},
+		"Specify a single resource URN to update. Other resources will not be updated."+
+			" Multiple resources can be specified using --target urn1 --target urn2")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependencies, "target-dependencies", false,
+		"Allows updating of resources that the specified --target resources depend on")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false,
+		"Allows updating of resources that depend on the specified --target resources")
+	cmd.PersistentFlags().StringArrayVar(
+		&replaces, "replace", []string{},
+		"Specify a single resource URN to replace, even if it has not changed."+
+			" Multiple resources can be specified using --replace urn1 --replace urn2")
 	cmd.PersistentFlags().BoolVarP(
 		&yes, "yes", "y", false,
 		"Automatically approve and perform the update after previewing it")
@@ -42,6 +42,8 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend/state"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/policy"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
 	"github.com/pulumi/pulumi/pkg/util/ciutil"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
@@ -598,6 +600,41 @@ func printJSON(v interface{}) error {
 	return nil
 }
 
+// targetURNs converts a list of --target flag values into a list of resource URNs.
+func targetURNs(targets []string) []resource.URN {
+	var urns []resource.URN
+	for _, t := range targets {
+		urns = append(urns, resource.URN(t))
+	}
+	return urns
+}
+
+// redactionPolicy returns a policy that redacts the property values whose keys or paths match the given glob patterns,
+// or nil if no patterns are given.
+func redactionPolicy(patterns []string) (resource.RedactionPolicy, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	policy, err := resource.NewKeyPatternPolicy(patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// policyPacks loads the policy packs in the files at the given paths.
+func policyPacks(paths []string) ([]policy.Pack, error) {
+	var packs []policy.Pack
+	for _, path := range paths {
+		pack, err := policy.LoadPack(path)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
+
 // updateFlagsToOptions ensures that the given update flags represent a valid combination.  If so, an UpdateOptions
 // is returned with a nil-error; otherwise, the non-nil error contains information about why the combination is invalid.
 func updateFlagsToOptions(interactive, skipPreview, yes bool) (backend.UpdateOptions, error) {
@@ -0,0 +1,223 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/display"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/fsutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// watchSkipDirs are the directories beneath a project that are not watched for changes.
+var watchSkipDirs = []string{workspace.BookkeepingDir, workspace.GitDir, "node_modules"}
+
+// shouldAutoApply returns true if a previewed update that makes the given changes should be applied automatically.
+// A maxChanges of zero places no limit on the size of the update.
+func shouldAutoApply(changes engine.ResourceChanges, maxChanges int) bool {
+	if !changes.HasChanges() {
+		return false
+	}
+	return maxChanges == 0 || changes.Count() <= maxChanges
+}
+
+func newWatchCmd() *cobra.Command {
+	var debug bool
+	var stack string
+
+	// Flags for watching.
+	var autoApply bool
+	var interval time.Duration
+	var maxChanges int
+
+	// Flags for engine.UpdateOptions.
+	var diffDisplay bool
+	var parallel int
+	var showConfig bool
+	var showReplacementSteps bool
+	var showSames bool
+	var suppressOutputs bool
+
+	var cmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Continuously preview, and optionally update, a stack as its program and configuration change",
+		Long: "Continuously preview, and optionally update, a stack as its program and configuration change.\n" +
+			"\n" +
+			"This command previews the stack's update, then watches the project directory and the stack's\n" +
+			"configuration file. Each time they change, the update is previewed again. If `--auto-apply` is\n" +
+			"passed, each previewed update that changes at most `--max-changes` resources is then performed\n" +
+			"without prompting; larger updates must be performed with `pulumi up`.\n" +
+			"\n" +
+			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
+			"`--cwd` flag to use a different directory.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return errors.New("--interval must be positive")
+			}
+			if maxChanges < 0 {
+				return errors.New("--max-changes must not be negative")
+			}
+
+			opts := backend.UpdateOptions{
+				Engine: engine.UpdateOptions{
+					Parallel: parallel,
+					Debug:    debug,
+				},
+				Display: display.Options{
+					Color:                cmdutil.GetGlobalColorization(),
+					ShowConfig:           showConfig,
+					ShowReplacementSteps: showReplacementSteps,
+					ShowSameResources:    showSames,
+					SuppressOutputs:      suppressOutputs,
+					IsInteractive:        cmdutil.Interactive(),
+					DiffDisplay:          diffDisplay,
+					Debug:                debug,
+				},
+			}
+
+			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			_, root, err := readProject()
+			if err != nil {
+				return err
+			}
+			configPath, err := getProjectStackPath(s)
+			if err != nil {
+				return err
+			}
+
+			ctx := commandContext()
+			w := &stackWatcher{stack: s, opts: opts, autoApply: autoApply, maxChanges: maxChanges}
+			w.run(ctx, "Previewing the update")
+			return fsutil.Watch(ctx, []string{root, configPath}, watchSkipDirs, interval, func() {
+				w.run(ctx, "Changes detected; previewing the update")
+			})
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&stackConfigFile, "config-file", "",
+		"Use the configuration values in the specified file rather than detecting the file name")
+
+	// Flags for watching.
+	cmd.PersistentFlags().BoolVar(
+		&autoApply, "auto-apply", false,
+		"Automatically perform each previewed update that changes at most --max-changes resources")
+	cmd.PersistentFlags().DurationVar(
+		&interval, "interval", time.Second,
+		"How often to check the program and configuration for changes")
+	cmd.PersistentFlags().IntVar(
+		&maxChanges, "max-changes", 10,
+		"The largest number of resource changes to perform automatically with --auto-apply (0 for no limit)")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", defaultParallel,
+		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().BoolVar(
+		&showConfig, "show-config", false,
+		"Show configuration keys and variables")
+	cmd.PersistentFlags().BoolVar(
+		&showReplacementSteps, "show-replacement-steps", false,
+		"Show detailed resource replacement creates and deletes instead of a single step")
+	cmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources that needn't be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&suppressOutputs, "suppress-outputs", false,
+		"Suppress display of stack outputs (in case they contain sensitive values)")
+
+	return cmd
+}
+
+// stackWatcher previews, and optionally performs, the update of a stack each time its program or configuration
+// changes.
+type stackWatcher struct {
+	stack      backend.Stack
+	opts       backend.UpdateOptions
+	autoApply  bool
+	maxChanges int
+}
+
+// run previews the stack's update and, if it is small enough to be applied automatically, performs it. Errors are
+// reported rather than returned, so that the watch continues and the next change can fix them.
+func (w *stackWatcher) run(ctx context.Context, banner string) {
+	color := w.opts.Display.Color
+	fmt.Println(color.Colorize(fmt.Sprintf("%s[%s] %s...%s",
+		colors.SpecHeadline, time.Now().Format("15:04:05"), banner, colors.Reset)))
+
+	// The project is reloaded each time, since it may itself have changed.
+	proj, root, err := readProject()
+	if err != nil {
+		w.report(err)
+		return
+	}
+	m, err := getUpdateMetadata("", root)
+	if err != nil {
+		w.report(errors.Wrap(err, "gathering environment metadata"))
+		return
+	}
+
+	op := backend.UpdateOperation{Proj: proj, Root: root, M: m, Opts: w.opts, Scopes: cancellationScopes}
+	changes, err := w.stack.Preview(ctx, op)
+	if err != nil {
+		w.report(PrintEngineError(err))
+		return
+	}
+
+	switch {
+	case !w.autoApply || !changes.HasChanges():
+		// Nothing more to do.
+	case !shouldAutoApply(changes, w.maxChanges):
+		fmt.Println(color.Colorize(fmt.Sprintf("%sThis update changes %d resources, more than the %d that may be "+
+			"changed automatically; run `pulumi up` to perform it.%s",
+			colors.SpecWarning, changes.Count(), w.maxChanges, colors.Reset)))
+	default:
+		// The update was just previewed, so it is performed without another preview or a prompt.
+		op.Opts.AutoApprove, op.Opts.SkipPreview = true, true
+		if _, err = w.stack.Update(ctx, op); err != nil {
+			w.report(PrintEngineError(err))
+		}
+	}
+}
+
+func (w *stackWatcher) report(err error) {
+	if err != nil {
+		cmdutil.Diag().Errorf(diag.Message("", "%v"), err)
+	}
+}
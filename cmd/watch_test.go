@@ -0,0 +1,38 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestShouldAutoApply(t *testing.T) {
+	none := engine.ResourceChanges{deploy.OpSame: 20}
+	small := engine.ResourceChanges{deploy.OpSame: 20, deploy.OpCreate: 1, deploy.OpUpdate: 2}
+	large := engine.ResourceChanges{deploy.OpCreate: 8, deploy.OpReplace: 2, deploy.OpDelete: 1}
+
+	assert.False(t, shouldAutoApply(none, 10))
+	assert.False(t, shouldAutoApply(nil, 0))
+	assert.True(t, shouldAutoApply(small, 10))
+	assert.True(t, shouldAutoApply(small, 3))
+	assert.False(t, shouldAutoApply(small, 2))
+	assert.False(t, shouldAutoApply(large, 10))
+	assert.True(t, shouldAutoApply(large, 0))
+}
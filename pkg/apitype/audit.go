@@ -0,0 +1,62 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitype
+
+// AuditLogResult is the outcome of an audited provider operation.
+type AuditLogResult string
+
+const (
+	// AuditLogSucceeded is for provider operations that completed successfully.
+	AuditLogSucceeded AuditLogResult = "succeeded"
+	// AuditLogFailed is for provider operations that returned an error.
+	AuditLogFailed AuditLogResult = "failed"
+)
+
+// AuditLogEntry records a single Create, Update, or Delete call made to a resource provider during an update. Property
+// values are never recorded; only their digests are, so the log may be retained without exposing secrets.
+//
+// Entries form a hash chain: each entry records the digest of the entry that precedes it, and its own digest covers
+// every other field. Removing, reordering, or altering an entry therefore breaks the chain.
+type AuditLogEntry struct {
+	// Sequence is the entry's position in the update's audit log, starting at 1.
+	Sequence int `json:"sequence"`
+	// Timestamp is the time at which the operation started, in RFC 3339 format with nanosecond precision.
+	Timestamp string `json:"timestamp"`
+	// URN is the URN of the resource the operation was applied to.
+	URN string `json:"urn"`
+	// Operation is the provider operation: "create", "update", or "delete".
+	Operation string `json:"operation"`
+	// ID is the resource's ID, if known.
+	ID string `json:"id,omitempty"`
+	// InputsDigest is the SHA-256 digest of the operation's input properties.
+	InputsDigest string `json:"inputsDigest,omitempty"`
+	// OutputsDigest is the SHA-256 digest of the output properties returned by the provider, if any.
+	OutputsDigest string `json:"outputsDigest,omitempty"`
+	// DurationMS is the time the provider took to complete the operation, in milliseconds.
+	DurationMS int64 `json:"durationMs"`
+	// Result is the outcome of the operation.
+	Result AuditLogResult `json:"result"`
+	// Error is the error returned by the provider, if the operation failed.
+	Error string `json:"error,omitempty"`
+	// PreviousDigest is the digest of the preceding entry, or empty for the first entry.
+	PreviousDigest string `json:"previousDigest,omitempty"`
+	// Digest is the SHA-256 digest of this entry with its Digest field omitted.
+	Digest string `json:"digest"`
+}
+
+// AppendUpdateAuditLogRequest is used to append entries to an update's audit log.
+type AppendUpdateAuditLogRequest struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
@@ -49,6 +49,42 @@ const (
 type VersionedCheckpoint struct {
 	Version    int             `json:"version"`
 	Checkpoint json.RawMessage `json:"checkpoint"`
+	// Provenance optionally records how the checkpoint was produced.
+	Provenance *ProvenanceV1 `json:"provenance,omitempty"`
+	// Signature optionally holds a signature over the checkpoint and its provenance.
+	Signature *CheckpointSignatureV1 `json:"signature,omitempty"`
+	// Journal optionally identifies the journal whose entries bring this checkpoint up to date (see JournalEntryV1).
+	Journal string `json:"journal,omitempty"`
+}
+
+// ProvenanceV1 records how a checkpoint was produced: the versions of the engine and the plugins that wrote it, and
+// the git commit of the program that was run, if known.
+type ProvenanceV1 struct {
+	// EngineVersion is the version of the Pulumi engine that wrote the checkpoint.
+	EngineVersion string `json:"engineVersion"`
+	// Plugins holds the versions of the plugins used by the update that produced the checkpoint.
+	Plugins []ProvenancePluginV1 `json:"plugins,omitempty"`
+	// GitCommit is the commit hash of the HEAD of the program's git repository.
+	GitCommit string `json:"gitCommit,omitempty"`
+	// GitDirty is true if the program's git work tree had uncommitted changes.
+	GitDirty bool `json:"gitDirty,omitempty"`
+}
+
+// ProvenancePluginV1 records the version of a single plugin in a checkpoint's provenance.
+type ProvenancePluginV1 struct {
+	Name    string               `json:"name"`
+	Kind    workspace.PluginKind `json:"kind"`
+	Version string               `json:"version,omitempty"`
+}
+
+// CheckpointSignatureV1 is a signature over a checkpoint and its provenance.
+type CheckpointSignatureV1 struct {
+	// Algorithm names the signature algorithm, e.g. "ecdsa-sha256".
+	Algorithm string `json:"algorithm"`
+	// KeyID identifies the key that produced the signature.
+	KeyID string `json:"keyID"`
+	// Value is the base64-encoded signature.
+	Value string `json:"value"`
 }
 
 // CheckpointV1 is a serialized deployment target plus a record of the latest deployment.
@@ -114,6 +150,9 @@ type DeploymentV3 struct {
 	Resources []ResourceV3 `json:"resources,omitempty" yaml:"resources,omitempty"`
 	// PendingOperations are all operations that were known by the engine to be currently executing.
 	PendingOperations []OperationV2 `json:"pending_operations,omitempty" yaml:"pending_operations,omitempty"`
+	// SharedValues holds the property values that are shared by several places in a compacted deployment, keyed by
+	// the IDs by which those places refer to them.
+	SharedValues map[string]interface{} `json:"shared_values,omitempty" yaml:"shared_values,omitempty"`
 }
 
 // OperationType is the type of an operation initiated by the engine. Its value indicates the type of operation
@@ -276,6 +315,24 @@ type ResourceV3 struct {
 	// PendingReplacement is used to track delete-before-replace resources that have been deleted but not yet
 	// recreated.
 	PendingReplacement bool `json:"pendingReplacement,omitempty" yaml:"pendingReplacement,omitempty"`
+	// RetainOnDelete is set to true when deleting this resource should remove it from the stack's state without
+	// deleting the underlying cloud resource.
+	RetainOnDelete bool `json:"retainOnDelete,omitempty" yaml:"retainOnDelete,omitempty"`
+	// Defaults lists the input properties whose values were supplied as defaults by the provider rather than by the
+	// program.
+	Defaults []resource.PropertyKey `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	// CustomTimeouts bounds how long the provider may take to create, update, or delete this resource.
+	CustomTimeouts *resource.CustomTimeouts `json:"customTimeouts,omitempty" yaml:"customTimeouts,omitempty"`
+	// PropertyReads maps from an input property name to the outputs of the resources it depends on that were read to
+	// compute its value.
+	PropertyReads resource.PropertyReads `json:"propertyReads,omitempty" yaml:"propertyReads,omitempty"`
+	// Provenance maps from the path of each output property to the provider operation that last set its value, and
+	// when.
+	Provenance resource.PropertyProvenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	// InputHashes maps from the path of the inputs and of each of their large objects and arrays to the fingerprint of
+	// its value, so that diffs can skip the values that have not changed. Tools that edit a resource's inputs must
+	// remove its hashes.
+	InputHashes resource.PropertyHashes `json:"inputHashes,omitempty" yaml:"inputHashes,omitempty"`
 }
 
 // ManifestV1 captures meta-information about this checkpoint file, such as versions of binaries, etc.
@@ -341,6 +398,12 @@ const (
 	// VCSRepositoryKindTag is a tag that represents the kind of the cloud VCS that this stack
 	// may be associated with (inferred by the CLI based on the git remote info).
 	VCSRepositoryKindTag StackTagName = "vcs:kind"
+	// PromotedFromTag is a tag that records the stack whose configuration was last promoted to this stack by
+	// `pulumi stack promote`.
+	PromotedFromTag StackTagName = "pulumi:promotedFrom"
+	// PromotedFromVersionTag is a tag that records the version of the latest update of the stack named by
+	// PromotedFromTag at the time of the promotion.
+	PromotedFromVersionTag StackTagName = "pulumi:promotedFromVersion"
 )
 
 // Stack describes a Stack running on a Pulumi Cloud.
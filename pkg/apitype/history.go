@@ -33,6 +33,8 @@ const (
 	DestroyUpdate UpdateKind = "destroy"
 	// ImportUpdate is an update that entails importing a raw checkpoint file.
 	ImportUpdate UpdateKind = "import"
+	// RollbackUpdate is an update that converges a stack's resources back to the deployment of an earlier update.
+	RollbackUpdate UpdateKind = "rollback"
 )
 
 // UpdateResult is an enum for the result of the update.
@@ -0,0 +1,41 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitype
+
+// JournalEntryV1 records the changes that one step of an update made to a stack's deployment. Rather than rewriting a
+// stack's checkpoint after every step, a backend may write it in full only now and then, and append an entry to a
+// journal after each step in between. Loading the stack replays the entries of the checkpoint's journal, in sequence,
+// on top of the checkpoint's deployment.
+//
+// Between two steps, resources are added to and removed from the middle of a deployment's resource list far more often
+// than they are added to or removed from its ends, so an entry records how many resources at the start and at the end
+// of the previous list it keeps, and the resources that replace those in between.
+type JournalEntryV1 struct {
+	// Journal identifies the journal that the entry belongs to. Entries of any other journal than the one named by the
+	// checkpoint they would extend are ignored, so that those left behind by a later full write are never replayed.
+	Journal string `json:"journal"`
+	// Sequence is the entry's position in its journal, starting at 1.
+	Sequence int `json:"sequence"`
+	// Manifest contains metadata about the deployment after the step.
+	Manifest ManifestV1 `json:"manifest"`
+	// KeepPrefix is the number of resources at the start of the previous resource list that are unchanged.
+	KeepPrefix int `json:"keepPrefix,omitempty"`
+	// KeepSuffix is the number of resources at the end of the previous resource list that are unchanged.
+	KeepSuffix int `json:"keepSuffix,omitempty"`
+	// Resources are the resources that replace those of the previous list that are not kept.
+	Resources []ResourceV3 `json:"resources,omitempty"`
+	// PendingOperations are all operations that were known by the engine to be executing after the step.
+	PendingOperations []OperationV2 `json:"pending_operations,omitempty"`
+}
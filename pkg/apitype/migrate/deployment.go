@@ -14,7 +14,11 @@
 
 package migrate
 
-import "github.com/pulumi/pulumi/pkg/apitype"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+)
 
 // UpToDeploymentV2 migrates a deployment from DeploymentV1 to DeploymentV2.
 func UpToDeploymentV2(v1 apitype.DeploymentV1) apitype.DeploymentV2 {
@@ -42,3 +46,46 @@ func UpToDeploymentV3(v2 apitype.DeploymentV2) apitype.DeploymentV3 {
 
 	return v3
 }
+
+// DownToDeploymentV2 migrates a deployment from DeploymentV3 to DeploymentV2. See DownToResourceV2 for the
+// information that is lost by this migration.
+func DownToDeploymentV2(v3 apitype.DeploymentV3) (apitype.DeploymentV2, error) {
+	var v2 apitype.DeploymentV2
+	v2.Manifest = v3.Manifest
+	for _, res := range v3.Resources {
+		r, err := DownToResourceV2(res)
+		if err != nil {
+			return apitype.DeploymentV2{}, err
+		}
+		v2.Resources = append(v2.Resources, r)
+	}
+	for _, op := range v3.PendingOperations {
+		o, err := DownToOperationV1(op)
+		if err != nil {
+			return apitype.DeploymentV2{}, err
+		}
+		v2.PendingOperations = append(v2.PendingOperations, o)
+	}
+
+	return v2, nil
+}
+
+// DownToDeploymentV1 migrates a deployment from DeploymentV2 to DeploymentV1. Deployments with pending operations
+// cannot be represented in a DeploymentV1.
+func DownToDeploymentV1(v2 apitype.DeploymentV2) (apitype.DeploymentV1, error) {
+	if len(v2.PendingOperations) != 0 {
+		return apitype.DeploymentV1{}, errors.New("deployment has pending operations")
+	}
+
+	var v1 apitype.DeploymentV1
+	v1.Manifest = v2.Manifest
+	for _, res := range v2.Resources {
+		r, err := DownToResourceV1(res)
+		if err != nil {
+			return apitype.DeploymentV1{}, err
+		}
+		v1.Resources = append(v1.Resources, r)
+	}
+
+	return v1, nil
+}
@@ -23,4 +23,9 @@
 //
 // The migrations in this package are designed to preserve semantics between
 // versions. It is always safe to migrate an entity up from one version to another.
+//
+// Deployments and resources may also be migrated "down" to an older version so
+// that they can be consumed by older clients. Down migrations may discard
+// information, and fail if the entity uses a feature that the older version
+// cannot represent.
 package migrate
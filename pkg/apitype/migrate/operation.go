@@ -14,7 +14,11 @@
 
 package migrate
 
-import "github.com/pulumi/pulumi/pkg/apitype"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+)
 
 // UpToOperationV2 migrates a resource from OperationV1 to OperationV2.
 func UpToOperationV2(v1 apitype.OperationV1) apitype.OperationV2 {
@@ -23,3 +27,15 @@ func UpToOperationV2(v1 apitype.OperationV1) apitype.OperationV2 {
 		Type:     v1.Type,
 	}
 }
+
+// DownToOperationV1 migrates an operation from OperationV2 to OperationV1.
+func DownToOperationV1(v2 apitype.OperationV2) (apitype.OperationV1, error) {
+	res, err := DownToResourceV2(v2.Resource)
+	if err != nil {
+		return apitype.OperationV1{}, errors.Wrapf(err, "migrating pending %s operation", v2.Type)
+	}
+	return apitype.OperationV1{
+		Resource: res,
+		Type:     v2.Type,
+	}, nil
+}
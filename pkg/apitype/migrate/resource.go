@@ -15,6 +15,10 @@
 package migrate
 
 import (
+	"strings"
+
+	"github.com/pkg/errors"
+
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/resource"
 )
@@ -76,3 +80,58 @@ func UpToResourceV3(v2 apitype.ResourceV2) apitype.ResourceV3 {
 
 	return v3
 }
+
+// DownToResourceV2 migrates a resource from ResourceV3 to ResourceV2. This migration is lossy: per-property
+// dependencies are dropped, and will be conservatively recomputed from the resource's dependencies when the resource
+// is migrated back up. The record of which inputs were defaulted by the provider, any custom timeouts, and the record
+// of which outputs each property read are dropped as well. Resources that are pending replacement or retained on
+// delete cannot be represented in a ResourceV2.
+func DownToResourceV2(v3 apitype.ResourceV3) (apitype.ResourceV2, error) {
+	if v3.PendingReplacement {
+		return apitype.ResourceV2{}, errors.Errorf("resource '%s' is pending replacement", v3.URN)
+	}
+	if v3.RetainOnDelete {
+		return apitype.ResourceV2{}, errors.Errorf("resource '%s' is retained on delete", v3.URN)
+	}
+
+	var v2 apitype.ResourceV2
+	v2.URN = v3.URN
+	v2.Custom = v3.Custom
+	v2.Delete = v3.Delete
+	v2.ID = v3.ID
+	v2.Type = v3.Type
+	v2.Inputs = v3.Inputs
+	v2.Outputs = v3.Outputs
+	v2.Parent = v3.Parent
+	v2.Protect = v3.Protect
+	v2.External = v3.External
+	v2.Dependencies = v3.Dependencies
+	v2.InitErrors = v3.InitErrors
+	v2.Provider = v3.Provider
+	return v2, nil
+}
+
+// DownToResourceV1 migrates a resource from ResourceV2 to ResourceV1. External resources and resources that
+// reference first-class providers cannot be represented in a ResourceV1.
+func DownToResourceV1(v2 apitype.ResourceV2) (apitype.ResourceV1, error) {
+	if v2.External {
+		return apitype.ResourceV1{}, errors.Errorf("resource '%s' is external", v2.URN)
+	}
+	if v2.Provider != "" || strings.HasPrefix(string(v2.Type), "pulumi:providers:") {
+		return apitype.ResourceV1{}, errors.Errorf("resource '%s' uses a first-class provider", v2.URN)
+	}
+
+	var v1 apitype.ResourceV1
+	v1.URN = v2.URN
+	v1.Custom = v2.Custom
+	v1.Delete = v2.Delete
+	v1.ID = v2.ID
+	v1.Type = v2.Type
+	v1.Inputs = v2.Inputs
+	v1.Outputs = v2.Outputs
+	v1.Parent = v2.Parent
+	v1.Protect = v2.Protect
+	v1.Dependencies = append(v1.Dependencies, v2.Dependencies...)
+	v1.InitErrors = append(v1.InitErrors, v2.InitErrors...)
+	return v1, nil
+}
@@ -68,3 +68,75 @@ func TestV1ToV2(t *testing.T) {
 	}, v2.Dependencies)
 	assert.Empty(t, v2.Provider)
 }
+
+func TestV3ToV2(t *testing.T) {
+	v3 := apitype.ResourceV3{
+		URN:      resource.URN("foo"),
+		Custom:   true,
+		ID:       resource.ID("bar"),
+		Type:     tokens.Type("special"),
+		Inputs:   map[string]interface{}{"foo_in": "baz"},
+		Outputs:  map[string]interface{}{"foo_out": "out"},
+		Parent:   resource.URN("parent"),
+		Protect:  true,
+		Provider: "prov",
+		Dependencies: []resource.URN{
+			resource.URN("dep1"),
+		},
+		PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+			"foo_in": {resource.URN("dep1")},
+		},
+	}
+
+	v2, err := DownToResourceV2(v3)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.URN("foo"), v2.URN)
+	assert.True(t, v2.Custom)
+	assert.Equal(t, resource.ID("bar"), v2.ID)
+	assert.Equal(t, map[string]interface{}{"foo_in": "baz"}, v2.Inputs)
+	assert.Equal(t, map[string]interface{}{"foo_out": "out"}, v2.Outputs)
+	assert.True(t, v2.Protect)
+	assert.Equal(t, "prov", v2.Provider)
+	assert.Equal(t, []resource.URN{resource.URN("dep1")}, v2.Dependencies)
+
+	// Resources that are pending replacement cannot be downgraded.
+	v3.PendingReplacement = true
+	_, err = DownToResourceV2(v3)
+	assert.Error(t, err)
+}
+
+func TestV2ToV1(t *testing.T) {
+	v2 := apitype.ResourceV2{
+		URN:     resource.URN("foo"),
+		Custom:  true,
+		ID:      resource.ID("bar"),
+		Type:    tokens.Type("special"),
+		Inputs:  map[string]interface{}{"foo_in": "baz"},
+		Outputs: map[string]interface{}{"foo_out": "out"},
+		Protect: true,
+	}
+
+	v1, err := DownToResourceV1(v2)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.URN("foo"), v1.URN)
+	assert.Equal(t, map[string]interface{}{"foo_in": "baz"}, v1.Inputs)
+	assert.Equal(t, map[string]interface{}{"foo_out": "out"}, v1.Outputs)
+	assert.Empty(t, v1.Defaults)
+	assert.True(t, v1.Protect)
+
+	// External resources and resources with first-class providers cannot be downgraded.
+	ext := v2
+	ext.External = true
+	_, err = DownToResourceV1(ext)
+	assert.Error(t, err)
+
+	prov := v2
+	prov.Provider = "prov"
+	_, err = DownToResourceV1(prov)
+	assert.Error(t, err)
+
+	provRes := v2
+	provRes.Type = tokens.Type("pulumi:providers:aws")
+	_, err = DownToResourceV1(provRes)
+	assert.Error(t, err)
+}
@@ -61,11 +61,12 @@ var updateTextMap = map[apitype.UpdateKind]struct {
 	previewText string
 	text        string
 }{
-	apitype.PreviewUpdate: {"update", "Previewing"},
-	apitype.UpdateUpdate:  {"update", "Updating"},
-	apitype.RefreshUpdate: {"refresh", "Refreshing"},
-	apitype.DestroyUpdate: {"destroy", "Destroying"},
-	apitype.ImportUpdate:  {"import", "Importing"},
+	apitype.PreviewUpdate:  {"update", "Previewing"},
+	apitype.UpdateUpdate:   {"update", "Updating"},
+	apitype.RefreshUpdate:  {"refresh", "Refreshing"},
+	apitype.DestroyUpdate:  {"destroy", "Destroying"},
+	apitype.ImportUpdate:   {"import", "Importing"},
+	apitype.RollbackUpdate: {"rollback", "Rolling back"},
 }
 
 type response string
@@ -78,6 +79,13 @@ const (
 
 func PreviewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack,
 	op UpdateOperation, apply Applier) (engine.ResourceChanges, error) {
+	return previewThenPrompt(ctx, kind, stack, &op, apply)
+}
+
+// previewThenPrompt previews the operation and asks the user whether to proceed. If the user confirms the deletion
+// or replacement of guarded resources, they are added to the operation's allowed URNs.
+func previewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack,
+	op *UpdateOperation, apply Applier) (engine.ResourceChanges, error) {
 	// create a channel to hear about the update events from the engine. this will be used so that
 	// we can build up the diff display in case the user asks to see the details of the diff
 
@@ -109,24 +117,95 @@ func PreviewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack
 		ShowLink: false,
 	}
 
-	changes, err := apply(ctx, kind, stack, op, opts, eventsChannel)
+	changes, err := apply(ctx, kind, stack, *op, opts, eventsChannel)
 	if err != nil {
 		close(eventsChannel)
 		return changes, err
 	}
 
-	// If there are no changes, or we're auto-approving or just previewing, we can skip the confirmation prompt.
-	if op.Opts.AutoApprove || kind == apitype.PreviewUpdate {
+	// If we're just previewing, we can skip the confirmation prompt.
+	if op.Opts.PreviewOnly || kind == apitype.PreviewUpdate {
 		close(eventsChannel)
 		return changes, nil
 	}
 
-	// Otherwise, ensure the user wants to proceed.
+	// Guarded resources must be allowed explicitly, so auto-approval does not extend to them.
+	guarded := guardedResources(events, op.Opts.Engine.AllowDestroy)
+	if op.Opts.AutoApprove {
+		close(eventsChannel)
+		if len(guarded) > 0 {
+			return changes, errors.Errorf("the %s would delete or replace guarded resources; "+
+				"to proceed, pass --allow-destroy for each of them:\n    %s", kind, joinURNs(guarded))
+		}
+		return changes, nil
+	}
+
+	// Otherwise, ensure the user wants to proceed, and that they mean to delete or replace any guarded resources.
 	err = confirmBeforeUpdating(kind, stack, events, op.Opts)
+	if err == nil && len(guarded) > 0 {
+		if err = confirmGuardedResources(kind, stack, guarded, op.Opts); err == nil {
+			op.Opts.Engine.AllowDestroy = append(op.Opts.Engine.AllowDestroy, guarded...)
+		}
+	}
 	close(eventsChannel)
 	return changes, err
 }
 
+// guardedResources returns the URNs of the guarded resources that the previewed events delete or replace, other than
+// those that have already been allowed.
+func guardedResources(events []engine.Event, allowed []resource.URN) []resource.URN {
+	seen := make(map[resource.URN]bool)
+	for _, urn := range allowed {
+		seen[urn] = true
+	}
+
+	var guarded []resource.URN
+	for _, e := range events {
+		if e.Type != engine.ResourcePreEvent {
+			continue
+		}
+		step := e.Payload.(engine.ResourcePreEventPayload).Metadata
+		if step.Guarded && !seen[step.URN] {
+			seen[step.URN] = true
+			guarded = append(guarded, step.URN)
+		}
+	}
+	return guarded
+}
+
+func joinURNs(urns []resource.URN) string {
+	strs := make([]string, len(urns))
+	for i, urn := range urns {
+		strs[i] = string(urn)
+	}
+	return strings.Join(strs, "\n    ")
+}
+
+// confirmGuardedResources lists the guarded resources that the operation would delete or replace, and asks the user
+// to confirm them by typing the name of the stack. A nil error means they were confirmed.
+func confirmGuardedResources(kind apitype.UpdateKind, stack Stack, guarded []resource.URN,
+	opts UpdateOptions) error {
+
+	name := string(stack.Ref().Name())
+	message := fmt.Sprintf("This %s will delete or replace the following guarded resources:\n    %s\n",
+		kind, joinURNs(guarded))
+	message = opts.Display.Color.Colorize(colors.SpecAttention + message + colors.Reset)
+	prompt := "\b" + opts.Display.Color.Colorize(
+		colors.SpecPrompt+fmt.Sprintf("Type the name of the stack (%s) to confirm:", name)+colors.Reset)
+
+	_, err := os.Stdout.WriteString(message)
+	contract.IgnoreError(err)
+
+	var response string
+	if err := survey.AskOne(&survey.Input{Message: prompt}, &response, nil); err != nil {
+		return errors.Wrapf(err, "confirmation cancelled, not proceeding with the %s", kind)
+	}
+	if strings.TrimSpace(response) != name {
+		return errors.Errorf("confirmation declined, not proceeding with the %s", kind)
+	}
+	return nil
+}
+
 // confirmBeforeUpdating asks the user whether to proceed. A nil error means yes.
 func confirmBeforeUpdating(kind apitype.UpdateKind, stack Stack,
 	events []engine.Event, opts UpdateOptions) error {
@@ -191,8 +270,8 @@ func PreviewThenPromptThenExecute(ctx context.Context, kind apitype.UpdateKind,
 	// Preview the operation to the user and ask them if they want to proceed.
 
 	if !op.Opts.SkipPreview {
-		changes, err := PreviewThenPrompt(ctx, kind, stack, op, apply)
-		if err != nil || kind == apitype.PreviewUpdate {
+		changes, err := previewThenPrompt(ctx, kind, stack, &op, apply)
+		if err != nil || op.Opts.PreviewOnly || kind == apitype.PreviewUpdate {
 			return changes, err
 		}
 	}
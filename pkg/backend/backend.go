@@ -106,6 +106,10 @@ type Backend interface {
 	Refresh(ctx context.Context, stackRef StackReference, op UpdateOperation) (engine.ResourceChanges, error)
 	// Destroy destroys all of this stack's resources.
 	Destroy(ctx context.Context, stackRef StackReference, op UpdateOperation) (engine.ResourceChanges, error)
+	// Rollback converges the stack's resources back to the deployment produced by the given version of the stack (see
+	// UpdateInfo.Version).
+	Rollback(ctx context.Context, stackRef StackReference, op UpdateOperation,
+		version int) (engine.ResourceChanges, error)
 
 	// GetHistory returns all updates for the stack. The returned UpdateInfo slice will be in
 	// descending order (newest first).
@@ -122,6 +126,10 @@ type Backend interface {
 
 	// ExportDeployment exports the deployment for the given stack as an opaque JSON message.
 	ExportDeployment(ctx context.Context, stackRef StackReference) (*apitype.UntypedDeployment, error)
+	// ExportDeploymentVersion exports the deployment produced by the given version of the stack (see
+	// UpdateInfo.Version) as an opaque JSON message.
+	ExportDeploymentVersion(ctx context.Context, stackRef StackReference,
+		version int) (*apitype.UntypedDeployment, error)
 	// ImportDeployment imports the given deployment into the indicated stack.
 	ImportDeployment(ctx context.Context, stackRef StackReference, deployment *apitype.UntypedDeployment) error
 	// Logout logs you out of the backend and removes any stored credentials.
@@ -137,6 +145,23 @@ type UpdateOperation struct {
 	M      *UpdateMetadata
 	Opts   UpdateOptions
 	Scopes CancellationScopeSource
+
+	// RollbackTo is the snapshot that a rollback converges the stack to (see RollbackSnapshot).
+	RollbackTo *deploy.Snapshot
+}
+
+// RollbackSnapshot loads the deployment produced by the given version of the given stack, to which rolling the stack
+// back converges it.
+func RollbackSnapshot(ctx context.Context, b Backend, stackRef StackReference, version int) (*deploy.Snapshot, error) {
+	deployment, err := b.ExportDeploymentVersion(ctx, stackRef, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading the deployment of update %d", version)
+	}
+	snap, err := stack.DeserializeUntypedDeployment(deployment)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading the deployment of update %d", version)
+	}
+	return snap, nil
 }
 
 // UpdateOptions is the full set of update options, including backend and engine options.
@@ -150,6 +175,8 @@ type UpdateOptions struct {
 	AutoApprove bool
 	// SkipPreview, when true, causes the preview step to be skipped.
 	SkipPreview bool
+	// PreviewOnly, when true, causes the operation to be previewed but never performed.
+	PreviewOnly bool
 }
 
 // CancellationScope provides a scoped source of cancellation and termination requests.
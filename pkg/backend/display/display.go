@@ -21,6 +21,7 @@ import (
 	"math"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize/english"
@@ -43,7 +44,9 @@ func ShowEvents(
 	op string, action apitype.UpdateKind, stack tokens.QName, proj tokens.PackageName,
 	events <-chan engine.Event, done chan<- bool, opts Options, isPreview bool) {
 
-	if opts.DiffDisplay {
+	if opts.JSONDisplay {
+		ShowJSONEvents(events, done, opts)
+	} else if opts.DiffDisplay {
 		ShowDiffEvents(op, action, events, done, opts)
 	} else {
 		ShowProgressEvents(op, action, stack, proj, events, done, opts, isPreview)
@@ -212,6 +215,23 @@ func renderSummaryEvent(action apitype.UpdateKind, event engine.SummaryEventPayl
 		fprintfIgnoreError(out, "\n")
 	}
 
+	// If the stack fans out to several instances, break the changes down by instance.
+	if event.Summary != nil && len(event.Summary.Instances) > 0 {
+		fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("\n%sInstances:%s\n", colors.SpecHeadline, colors.Reset)))
+		renderInstanceChanges(out, event.Summary.Instances, planTo, event.IsPreview, opts)
+	}
+
+	// If the engine estimated the stack's costs, print their total and how much the update changes it.
+	if event.Summary != nil && event.Summary.Cost != nil {
+		cost := event.Summary.Cost
+		fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("\n%sEstimated cost:%s %.2f (%+.2f)\n",
+			colors.SpecHeadline, colors.Reset, cost.Total, cost.Change)))
+		if cost.Unestimated > 0 {
+			fprintfIgnoreError(out, "    %d %s without an estimate\n",
+				cost.Unestimated, english.PluralWord(cost.Unestimated, "resource", ""))
+		}
+	}
+
 	// For actual deploys, we print some additional summary information
 	if !event.IsPreview {
 		// Round up to the nearest second.  It's not useful to spit out time with 9 digits of
@@ -226,6 +246,35 @@ func renderSummaryEvent(action apitype.UpdateKind, event engine.SummaryEventPayl
 	return out.String()
 }
 
+// renderInstanceChanges prints a line for each fan-out instance that counts its resources by operation.
+func renderInstanceChanges(out *bytes.Buffer, instances map[string]engine.ResourceChanges, planTo string,
+	isPreview bool, opts Options) {
+
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		changes := instances[name]
+		var pieces []string
+		for _, op := range deploy.StepOps {
+			if c := changes[op]; c > 0 && op != deploy.OpSame {
+				opDescription := string(op)
+				if !isPreview {
+					opDescription = op.PastTense()
+				}
+				pieces = append(pieces, fmt.Sprintf("%s%d %s%s%s", op.Prefix(), c, planTo, opDescription, colors.Reset))
+			}
+		}
+		if c := changes[deploy.OpSame]; c > 0 {
+			pieces = append(pieces, fmt.Sprintf("%d unchanged", c))
+		}
+		fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("    %s: %s\n", name, strings.Join(pieces, ", "))))
+	}
+}
+
 func renderPreludeEvent(event engine.PreludeEventPayload, opts Options) string {
 	// Only if we have been instructed to show configuration values will we print anything during the prelude.
 	if !opts.ShowConfig {
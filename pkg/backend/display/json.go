@@ -0,0 +1,107 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+// PreviewDigest is the machine-readable document produced by the JSON display.
+type PreviewDigest struct {
+	Steps       []deploy.SerializedStep `json:"steps"`                 // the steps in the plan, in order.
+	Diagnostics []PreviewDiagnostic     `json:"diagnostics,omitempty"` // any warnings or errors that were reported.
+	Changes     engine.ResourceChanges  `json:"changeSummary,omitempty"`
+}
+
+// PreviewDiagnostic is a single warning or error reported while computing a plan.
+type PreviewDiagnostic struct {
+	URN      resource.URN  `json:"urn,omitempty"`
+	Message  string        `json:"message"`
+	Severity diag.Severity `json:"severity"`
+}
+
+// ShowJSONEvents reads events from the `events` channel until it is closed, accumulating the steps, diagnostics, and
+// summary they describe.  Once the engine has finished, the accumulated digest is written to stdout as a single JSON
+// document and the `done` channel is closed.
+func ShowJSONEvents(events <-chan engine.Event, done chan<- bool, opts Options) {
+	defer close(done)
+
+	sub := &jsonSubscriber{
+		digest: PreviewDigest{Steps: []deploy.SerializedStep{}},
+		opts:   opts,
+	}
+	engine.DispatchEvents(events, sub)
+	printJSONDigest(sub.digest)
+}
+
+// jsonSubscriber accumulates a PreviewDigest from the engine's event stream.
+type jsonSubscriber struct {
+	digest PreviewDigest
+	opts   Options
+}
+
+func (s *jsonSubscriber) OnPreStep(payload engine.ResourcePreEventPayload) {
+	if payload.Debug && !s.opts.Debug {
+		return
+	}
+	s.digest.Steps = append(s.digest.Steps, serializeStepMetadata(payload.Metadata))
+}
+
+func (s *jsonSubscriber) OnPostStep(payload engine.ResourceOutputsEventPayload) {}
+
+func (s *jsonSubscriber) OnStepFailed(payload engine.ResourceOperationFailedPayload) {}
+
+func (s *jsonSubscriber) OnDiagnostic(payload engine.DiagEventPayload) {
+	if payload.Severity == diag.Error || payload.Severity == diag.Warning {
+		s.digest.Diagnostics = append(s.digest.Diagnostics, PreviewDiagnostic{
+			URN:      payload.URN,
+			Message:  colors.Never.Colorize(payload.Message),
+			Severity: payload.Severity,
+		})
+	}
+}
+
+func (s *jsonSubscriber) OnSummary(payload engine.SummaryEventPayload) {
+	s.digest.Changes = payload.ResourceChanges
+}
+
+func serializeStepMetadata(step engine.StepEventMetadata) deploy.SerializedStep {
+	var oldInputs, newInputs resource.PropertyMap
+	if step.Old != nil {
+		oldInputs = step.Old.Inputs
+	}
+	if step.New != nil {
+		newInputs = step.New.Inputs
+	}
+	return deploy.NewSerializedStep(step.Op, step.URN, step.Type, step.Provider, oldInputs, newInputs, step.Keys,
+		step.Diffs, step.DetailedDiff)
+}
+
+func printJSONDigest(digest PreviewDigest) {
+	out, err := json.MarshalIndent(digest, "", "  ")
+	if err != nil {
+		fprintIgnoreError(os.Stderr, fmt.Sprintf("error: could not serialize preview: %v\n", err))
+		return
+	}
+	fprintIgnoreError(os.Stdout, string(out)+"\n")
+}
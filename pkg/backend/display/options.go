@@ -26,5 +26,6 @@ type Options struct {
 	SummaryDiff          bool                // If the diff display should be summarized
 	IsInteractive        bool                // If we should display things interactively
 	DiffDisplay          bool                // true if we should display things as a rich diff
+	JSONDisplay          bool                // true if we should emit a machine-readable JSON document instead.
 	Debug                bool                // true to enable debug output.
 }
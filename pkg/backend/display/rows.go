@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize/english"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 )
 
 type Row interface {
@@ -127,6 +129,10 @@ type resourceRowData struct {
 	// If we failed this operation for any reason.
 	failed bool
 
+	// When the operation on this resource started and, once it has, finished.  Both are zero for unchanged resources.
+	start time.Time
+	end   time.Time
+
 	diagInfo *DiagInfo
 
 	// If this row should be hidden by default.  We will hide unless we have any child nodes
@@ -163,10 +169,16 @@ func (data *resourceRowData) SetStep(step engine.StepEventMetadata) {
 	if step.Op == deploy.OpRefresh {
 		data.diffOutputs = true
 	}
+	if data.start.IsZero() && step.Op != deploy.OpSame {
+		data.start = time.Now()
+	}
 }
 
 func (data *resourceRowData) AddOutputStep(step engine.StepEventMetadata) {
 	data.outputSteps = append(data.outputSteps, step)
+	if data.end.IsZero() && !data.start.IsZero() && data.ContainsOutputsStep(data.step.Op) {
+		data.end = time.Now()
+	}
 }
 
 func (data *resourceRowData) Tick() int {
@@ -179,6 +191,20 @@ func (data *resourceRowData) Failed() bool {
 
 func (data *resourceRowData) SetFailed() {
 	data.failed = true
+	if data.end.IsZero() && !data.start.IsZero() {
+		data.end = time.Now()
+	}
+}
+
+// elapsed returns how long the operation on this resource has been running or, if it has finished, how long it took.
+func (data *resourceRowData) elapsed() time.Duration {
+	if data.start.IsZero() {
+		return 0
+	}
+	if data.end.IsZero() {
+		return time.Since(data.start)
+	}
+	return data.end.Sub(data.start)
 }
 
 func (data *resourceRowData) DiagInfo() *DiagInfo {
@@ -302,6 +328,14 @@ func (data *resourceRowData) ColorizedColumns() []string {
 		columns[statusColumn] = data.display.getStepInProgressDescription(step)
 	}
 
+	// Show how long operations that take a noticeable amount of time have taken.  Outside of a terminal, rows are only
+	// printed as they change, so the time is only shown once an operation is done.
+	if !data.display.isPreview && (data.display.isTerminal || !data.end.IsZero()) {
+		if elapsed := data.elapsed(); elapsed >= time.Second {
+			columns[statusColumn] += fmt.Sprintf(" (%v)", elapsed.Round(time.Second))
+		}
+	}
+
 	columns[infoColumn] = data.getInfoColumn()
 	return columns
 }
@@ -387,7 +421,9 @@ func (data *resourceRowData) getDiffInfo(step engine.StepEventMetadata) string {
 	changesBuf := &bytes.Buffer{}
 	if step.Old != nil && step.New != nil {
 		var diff *resource.ObjectDiff
-		if data.diffOutputs {
+		if step.DetailedDiff != nil {
+			diff = translateDetailedDiff(step.DetailedDiff)
+		} else if data.diffOutputs {
 			if step.Old.Outputs != nil && step.New.Outputs != nil {
 				diff = step.Old.Outputs.Diff(step.New.Outputs)
 			}
@@ -429,12 +465,53 @@ func (data *resourceRowData) getDiffInfo(step engine.StepEventMetadata) string {
 			writePropertyKeys(changesBuf, diff.Deletes, deploy.OpDelete)
 			writePropertyKeys(changesBuf, updates, deploy.OpUpdate)
 		}
+
+		// For replacements, also show the properties whose changes caused the replacement.
+		if (step.Op == deploy.OpReplace || step.Op == deploy.OpCreateReplacement) && len(step.Keys) > 0 {
+			if diff != nil {
+				writeString(changesBuf, "; ")
+			}
+			writeString(changesBuf, "replace: ")
+
+			keys := make(resource.PropertyMap)
+			for _, k := range step.Keys {
+				keys[k] = resource.PropertyValue{}
+			}
+			writePropertyKeys(changesBuf, keys, deploy.OpReplace)
+		}
 	}
 
 	fprintIgnoreError(changesBuf, colors.Reset)
 	return changesBuf.String()
 }
 
+// translateDetailedDiff converts a provider's detailed diff into an object diff keyed by property path, or nil if the
+// provider reported no changes.
+func translateDetailedDiff(detailedDiff map[string]plugin.PropertyDiff) *resource.ObjectDiff {
+	if len(detailedDiff) == 0 {
+		return nil
+	}
+
+	diff := &resource.ObjectDiff{
+		Adds:    make(resource.PropertyMap),
+		Deletes: make(resource.PropertyMap),
+		Sames:   make(resource.PropertyMap),
+		Updates: make(map[resource.PropertyKey]resource.ValueDiff),
+	}
+	for path, pd := range detailedDiff {
+		key := resource.PropertyKey(path)
+		switch pd.Kind {
+		case plugin.DiffAdd, plugin.DiffAddReplace:
+			diff.Adds[key] = resource.PropertyValue{}
+		case plugin.DiffDelete, plugin.DiffDeleteReplace:
+			diff.Deletes[key] = resource.PropertyValue{}
+		default:
+			diff.Updates[key] = resource.ValueDiff{}
+		}
+	}
+	return diff
+}
+
 func writePropertyKeys(b *bytes.Buffer, propMap resource.PropertyMap, op deploy.StepOp) {
 	if len(propMap) > 0 {
 		writeString(b, strings.Trim(op.Prefix(), " "))
@@ -0,0 +1,75 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/fsutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// localAuditLog appends audit log entries, one JSON object per line, to a file on the local machine. The file is not
+// created until the first entry is appended, so updates that make no changes leave no audit log behind.
+type localAuditLog struct {
+	path string
+	last string // the digest of the last entry appended, which is recorded in the update's history.
+}
+
+func (l *localAuditLog) Append(entry apitype.AuditLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	l.last = entry.Digest
+	return nil
+}
+
+var _ deploy.AuditLog = (*localAuditLog)(nil)
+
+func (b *localBackend) auditDirectory(stack tokens.QName) string {
+	contract.Require(stack != "", "stack")
+	return filepath.Join(b.StateDir(), workspace.AuditDir, fsutil.QnamePath(stack))
+}
+
+// newAuditLog returns the audit log for a new update of the given stack.
+func (b *localBackend) newAuditLog(stackName tokens.QName) *localAuditLog {
+	file := fmt.Sprintf("%s-%d.jsonl", stackName, time.Now().UnixNano())
+	return &localAuditLog{path: filepath.Join(b.auditDirectory(stackName), file)}
+}
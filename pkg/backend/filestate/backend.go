@@ -51,6 +51,12 @@ const localBackendURLPrefix = "file://"
 type Backend interface {
 	backend.Backend
 	local() // at the moment, no local specific info, so just use a marker function.
+
+	// UnreferencedBlobs returns the references of the values in the backend's blob store that no checkpoint, history
+	// entry, or backup refers to.
+	UnreferencedBlobs() ([]string, error)
+	// RemoveBlobs removes the values with the given references from the backend's blob store.
+	RemoveBlobs(refs []string) error
 }
 
 type localBackend struct {
@@ -154,7 +160,7 @@ func (b *localBackend) CreateStack(ctx context.Context, stackRef backend.StackRe
 		return nil, errors.Wrap(err, "validating stack properties")
 	}
 
-	file, err := b.saveStack(stackName, nil, nil)
+	file, err := b.saveStack(stackName, nil, nil, nil, "")
 	if err != nil {
 		return nil, err
 	}
@@ -275,22 +281,44 @@ func (b *localBackend) Destroy(ctx context.Context, stackRef backend.StackRefere
 	return backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
 }
 
+func (b *localBackend) Rollback(ctx context.Context, stackRef backend.StackReference,
+	op backend.UpdateOperation, version int) (engine.ResourceChanges, error) {
+	stack, err := b.GetStack(ctx, stackRef)
+	if err != nil {
+		return nil, err
+	}
+	if op.RollbackTo, err = backend.RollbackSnapshot(ctx, b, stackRef, version); err != nil {
+		return nil, err
+	}
+	return backend.PreviewThenPromptThenExecute(ctx, apitype.RollbackUpdate, stack, op, b.apply)
+}
+
 // apply actually performs the provided type of update on a locally hosted stack.
 func (b *localBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack backend.Stack,
 	op backend.UpdateOperation, opts backend.ApplierOptions, events chan<- engine.Event) (engine.ResourceChanges, error) {
 	stackRef := stack.Ref()
 	stackName := stackRef.Name()
 
-	// Print a banner so it's clear this is a local deployment.
+	// Print a banner so it's clear this is a local deployment, unless stdout is reserved for JSON.
 	actionLabel := backend.ActionLabel(kind, opts.DryRun)
-	fmt.Printf(op.Opts.Display.Color.Colorize(
-		colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
+	if !op.Opts.Display.JSONDisplay {
+		fmt.Printf(op.Opts.Display.Color.Colorize(
+			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
+	}
 
 	// Start the update.
 	update, err := b.newUpdate(stackName, op.Proj, op.Root)
 	if err != nil {
 		return nil, err
 	}
+	persister, err := b.newSnapshotPersister(stackName, op.M.Environment)
+	if err != nil {
+		return nil, err
+	}
+	notifier, err := backend.NewUpdateNotifier(ctx, kind, update.GetTarget(), op.Proj.Name, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
 
 	// Spawn a display loop to show events on the CLI.
 	displayEvents := make(chan engine.Event)
@@ -313,22 +341,31 @@ func (b *localBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack
 			if events != nil {
 				events <- e
 			}
+
+			// Likewise, notify any configured notification sinks.
+			if notifier != nil {
+				engine.DispatchEvent(e, notifier)
+			}
 		}
 
 		close(eventsDone)
 	}()
 
 	// Create the management machinery.
-	persister := b.newSnapshotPersister(stackName)
 	manager := backend.NewSnapshotManager(persister, update.GetTarget().Snapshot)
+	auditLog := b.newAuditLog(stackName)
 	engineCtx := &engine.Context{
 		Cancel:          scope.Context(),
 		Events:          engineEvents,
 		SnapshotManager: manager,
 		BackendClient:   backend.NewBackendClient(b),
+		AuditLog:        auditLog,
 	}
 
 	// Perform the update
+	if notifier != nil {
+		notifier.Started()
+	}
 	start := time.Now().Unix()
 	var changes engine.ResourceChanges
 	var updateErr error
@@ -341,6 +378,8 @@ func (b *localBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack
 		changes, updateErr = engine.Refresh(update, engineCtx, op.Opts.Engine, opts.DryRun)
 	case apitype.DestroyUpdate:
 		changes, updateErr = engine.Destroy(update, engineCtx, op.Opts.Engine, opts.DryRun)
+	case apitype.RollbackUpdate:
+		changes, updateErr = engine.Rollback(update, engineCtx, op.Opts.Engine, op.RollbackTo, opts.DryRun)
 	default:
 		contract.Failf("Unrecognized update kind: %s", kind)
 	}
@@ -352,9 +391,19 @@ func (b *localBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack
 	close(engineEvents)
 	contract.IgnoreClose(manager)
 
+	// Write out the final snapshot in full, so that the stack's history and later loads need not replay its journal.
+	if journal, ok := persister.(*backend.JournalingPersister); ok {
+		if err := journal.Compact(); err != nil {
+			logging.Warningf("failed to compact the journal of stack %s: %v", stackName, err)
+		}
+	}
+
 	// Make sure the goroutine writing to displayEvents and events has exited before proceeding.
 	<-eventsDone
 	close(displayEvents)
+	if notifier != nil {
+		notifier.Finished(updateErr)
+	}
 
 	// Save update results.
 	result := backend.SucceededResult
@@ -374,6 +423,11 @@ func (b *localBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack
 		//     trivial to achieve today given the event driven nature of plan-walking, however.
 		ResourceChanges: changes,
 	}
+	info.ConfigDigest = backend.ConfigDigest(info.Config)
+	info.AuditLogDigest = auditLog.last
+	if user, userErr := b.CurrentUser(); userErr == nil {
+		info.User = user
+	}
 
 	var saveErr error
 	var backupErr error
@@ -471,6 +525,43 @@ func (b *localBackend) ExportDeployment(ctx context.Context,
 	}, nil
 }
 
+func (b *localBackend) ExportDeploymentVersion(ctx context.Context,
+	stackRef backend.StackReference, version int) (*apitype.UntypedDeployment, error) {
+
+	chk, err := b.getHistoryCheckpoint(stackRef.Name(), version)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := chk.Latest
+	if deployment == nil {
+		deployment = stack.SerializeDeployment(deploy.NewSnapshot(deploy.Manifest{}, nil, nil))
+	}
+
+	// Exported deployments must stand alone and be readable by older CLIs, so resolve any values that were offloaded
+	// to the blob store and expand any that were shared.
+	store, err := b.blobStore()
+	if err != nil {
+		return nil, err
+	}
+	if err = stack.ResolveBlobs(deployment, store); err != nil {
+		return nil, err
+	}
+	if err = stack.ExpandDeployment(deployment); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
 func (b *localBackend) ImportDeployment(ctx context.Context, stackRef backend.StackReference,
 	deployment *apitype.UntypedDeployment) error {
 
@@ -485,7 +576,7 @@ func (b *localBackend) ImportDeployment(ctx context.Context, stackRef backend.St
 		return err
 	}
 
-	_, err = b.saveStack(stackName, config, snap)
+	_, err = b.saveStack(stackName, config, snap, nil, "")
 	return err
 }
 
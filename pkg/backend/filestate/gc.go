@@ -0,0 +1,113 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// blobGracePeriod is how long a newly written blob is kept even if nothing refers to it, since an update that is in
+// progress writes its blobs before the checkpoint that refers to them.
+const blobGracePeriod = time.Hour
+
+// blobRefPattern matches the content-addressed references of blobs.
+var blobRefPattern = regexp.MustCompile("[0-9a-f]{64}")
+
+func (b *localBackend) blobDirectory() string {
+	return filepath.Join(b.StateDir(), workspace.BlobDir)
+}
+
+func (b *localBackend) UnreferencedBlobs() ([]string, error) {
+	dir := b.blobDirectory()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	candidates := make(map[string]bool)
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && len(name) == 64 && blobRefPattern.MatchString(name) &&
+			time.Since(info.ModTime()) > blobGracePeriod {
+			candidates[name] = true
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Rather than parse each kind of file that may refer to a blob (current checkpoints and their backups, checkpoints
+	// retained by PULUMI_RETAIN_CHECKPOINTS, and the checkpoints kept with the update history), look for references in
+	// every file in the directories that hold them.  This errs on the side of keeping blobs.
+	for _, sub := range []string{workspace.StackDir, workspace.HistoryDir, workspace.BackupDir} {
+		err = filepath.Walk(filepath.Join(b.StateDir(), sub), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return errors.Wrapf(err, "reading %s", path)
+			}
+			for _, ref := range blobRefPattern.FindAll(data, -1) {
+				delete(candidates, string(ref))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var refs []string
+	for ref := range candidates {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+func (b *localBackend) RemoveBlobs(refs []string) error {
+	dir := b.blobDirectory()
+	for _, ref := range refs {
+		// References are hex digests; refuse anything that could escape the store's directory.
+		if _, err := hex.DecodeString(ref); err != nil {
+			return errors.Errorf("malformed blob reference '%s'", ref)
+		}
+		if err := os.Remove(filepath.Join(dir, ref)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// journalCompactionInterval is the number of journal entries after which an update rewrites its stack's checkpoint in
+// full.
+const journalCompactionInterval = 100
+
+// journalPath returns the path of the journal that extends the given stack's checkpoint. Journals hold one JSON-encoded
+// apitype.JournalEntryV1 per line.
+func (b *localBackend) journalPath(name tokens.QName) string {
+	file := b.stackPath(name)
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".journal"
+}
+
+// appendJournal appends the given entry to the given stack's journal, and syncs the journal to disk before returning.
+func (b *localBackend) appendJournal(name tokens.QName, entry apitype.JournalEntryV1) error {
+	byts, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "encoding journal entry")
+	}
+
+	f, err := os.OpenFile(b.journalPath(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "An IO error occurred during the current operation")
+	}
+	defer contract.IgnoreClose(f)
+
+	if _, err = f.Write(append(byts, '\n')); err != nil {
+		return errors.Wrap(err, "An IO error occurred during the current operation")
+	}
+	return f.Sync()
+}
+
+// replayJournal applies the entries of the given journal of the given stack to its checkpoint, if it has any. Journal
+// entries are not signed, so they are refused if checkpoints must be verified, or if the checkpoint that they extend
+// was signed; like unsigned checkpoints, they are otherwise accepted so that existing stacks can start to be signed.
+func (b *localBackend) replayJournal(name tokens.QName, chk *apitype.CheckpointV3, journal string,
+	signed bool) error {
+
+	f, err := os.Open(b.journalPath(name))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+
+	// A process that crashes while appending an entry leaves it incomplete. That step is lost, but the entries before
+	// it are intact, so an undecodable entry is only an error if it is not the last.
+	var entries []apitype.JournalEntryV1
+	var incomplete error
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<30)
+	for scanner.Scan() {
+		if incomplete != nil {
+			return errors.Wrap(incomplete, "the journal is corrupt")
+		}
+		var entry apitype.JournalEntryV1
+		if incomplete = json.Unmarshal(scanner.Bytes(), &entry); incomplete == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	if incomplete != nil {
+		logging.V(5).Infof("ignoring incomplete entry at the end of the journal for stack %s: %v", name, incomplete)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	verifier, strict, err := checkpointVerifier()
+	if err != nil {
+		return err
+	}
+	if verifier != nil && (strict || signed) {
+		return errors.New("the checkpoint has an unsigned journal, and so cannot be verified")
+	}
+
+	return stack.ReplayJournal(chk, journal, entries)
+}
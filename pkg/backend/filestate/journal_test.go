@@ -0,0 +1,101 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/version"
+)
+
+func newTestSnapshot(names ...string) *deploy.Snapshot {
+	var resources []*resource.State
+	for _, name := range names {
+		resources = append(resources, &resource.State{
+			Type:    "test:index:Thing",
+			URN:     resource.NewURN("dev", "proj", "", "test:index:Thing", tokens.QName(name)),
+			Inputs:  resource.PropertyMap{},
+			Outputs: resource.PropertyMap{},
+		})
+	}
+	manifest := deploy.Manifest{Time: time.Now(), Version: version.Version}
+	manifest.Magic = manifest.NewMagic()
+	return deploy.NewSnapshot(manifest, resources, nil)
+}
+
+func writeTestSigningKey(t *testing.T, dir string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "signing.pem")
+	assert.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	return path
+}
+
+func TestJournalWithSigningKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.NoError(t, err)
+	defer func() { contract.IgnoreError(os.RemoveAll(dir)) }()
+
+	b := &localBackend{url: localBackendURLPrefix + dir}
+	name := tokens.QName("dev")
+	_, err = b.saveStack(name, nil, newTestSnapshot(), nil, "")
+	assert.NoError(t, err)
+
+	// Journal an update, but stop before it compacts its journal.
+	persister := backend.NewJournalingPersister(&localSnapshotPersister{name: name, backend: b}, 0)
+	assert.NoError(t, persister.Save(newTestSnapshot("a")))
+	assert.NoError(t, persister.Save(newTestSnapshot("a", "b")))
+	_, err = os.Stat(b.journalPath(name))
+	assert.NoError(t, err)
+
+	// Once a signing key is set, the unsigned checkpoint and its journal are still accepted.
+	key := writeTestSigningKey(t, dir)
+	assert.NoError(t, os.Setenv(CheckpointSigningKeyEnvVar, key))
+	defer func() { contract.IgnoreError(os.Unsetenv(CheckpointSigningKeyEnvVar)) }()
+	_, snap, _, err := b.getStack(name)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 2)
+
+	// Compacting the journal signs the checkpoint and removes the journal, so the stack may then be verified.
+	assert.NoError(t, persister.Compact())
+	assert.NoError(t, os.Setenv(CheckpointVerificationKeyEnvVar, key))
+	_, snap, _, err = b.getStack(name)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 2)
+	assert.NoError(t, os.Unsetenv(CheckpointVerificationKeyEnvVar))
+
+	// Unsigned journal entries are refused if the checkpoint that they extend is signed.
+	assert.NoError(t, persister.Save(newTestSnapshot("a")))
+	_, _, _, err = b.getStack(name)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+)
+
+// CheckpointSigningKeyEnvVar, if set to the path of a PEM-encoded ECDSA private key, causes every checkpoint to be
+// signed with that key when it is written. Signed checkpoints are verified with the same key when they are read;
+// unsigned checkpoints are still accepted, so that existing stacks can start to be signed.
+const CheckpointSigningKeyEnvVar = "PULUMI_CHECKPOINT_SIGNING_KEY"
+
+// CheckpointVerificationKeyEnvVar, if set to the path of a PEM-encoded ECDSA public key, causes every checkpoint to be
+// verified with that key when it is read. Checkpoints that are unsigned, or that were signed by another key, are
+// rejected.
+const CheckpointVerificationKeyEnvVar = "PULUMI_CHECKPOINT_VERIFICATION_KEY"
+
+// checkpointSigner returns the signer configured by CheckpointSigningKeyEnvVar, or nil if checkpoints are not signed.
+func checkpointSigner() (stack.CheckpointSigner, error) {
+	path := os.Getenv(CheckpointSigningKeyEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	signer, err := stack.LoadCheckpointSigner(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading the checkpoint signing key named by %s", CheckpointSigningKeyEnvVar)
+	}
+	return signer, nil
+}
+
+// checkpointVerifier returns the verifier configured by CheckpointVerificationKeyEnvVar or, failing that, by
+// CheckpointSigningKeyEnvVar, and whether unsigned checkpoints must be rejected. It returns a nil verifier if
+// checkpoints are not verified.
+func checkpointVerifier() (stack.CheckpointVerifier, bool, error) {
+	if path := os.Getenv(CheckpointVerificationKeyEnvVar); path != "" {
+		verifier, err := stack.LoadCheckpointVerifier(path)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "loading the checkpoint verification key named by %s",
+				CheckpointVerificationKeyEnvVar)
+		}
+		return verifier, true, nil
+	}
+	if path := os.Getenv(CheckpointSigningKeyEnvVar); path != "" {
+		verifier, err := stack.LoadCheckpointVerifier(path)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "loading the checkpoint signing key named by %s",
+				CheckpointSigningKeyEnvVar)
+		}
+		return verifier, false, nil
+	}
+	return nil, false, nil
+}
+
+// signCheckpoint signs the given checkpoint if a signing key is configured.
+func signCheckpoint(chk *apitype.VersionedCheckpoint) error {
+	signer, err := checkpointSigner()
+	if err != nil || signer == nil {
+		return err
+	}
+	return stack.SignCheckpoint(chk, signer)
+}
+
+// verifyCheckpoint verifies the signature of the given serialized checkpoint if a verification key is configured.
+func verifyCheckpoint(file string, bytes []byte) error {
+	verifier, strict, err := checkpointVerifier()
+	if err != nil || verifier == nil {
+		return err
+	}
+
+	var chk apitype.VersionedCheckpoint
+	if err = json.Unmarshal(bytes, &chk); err != nil {
+		return err
+	}
+	if chk.Signature == nil && !strict {
+		return nil
+	}
+	if err = stack.VerifyCheckpoint(&chk, verifier); err != nil {
+		return errors.Wrapf(err, "%s: checkpoint verification failure; refusing to use it", file)
+	}
+	return nil
+}
@@ -17,6 +17,8 @@ package filestate
 import (
 	"os"
 
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/tokens"
 )
@@ -25,6 +27,7 @@ import (
 // to disk on the local machine.
 type localSnapshotPersister struct {
 	name    tokens.QName
+	env     map[string]string
 	backend *localBackend
 }
 
@@ -33,16 +36,35 @@ func (sm *localSnapshotPersister) Invalidate() error {
 }
 
 func (sm *localSnapshotPersister) Save(snapshot *deploy.Snapshot) error {
+	return sm.SaveCheckpoint(snapshot, "")
+}
+
+func (sm *localSnapshotPersister) SaveCheckpoint(snapshot *deploy.Snapshot, journal string) error {
 	config, _, _, err := sm.backend.getStack(sm.name)
 	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
-	_, err = sm.backend.saveStack(sm.name, config, snapshot)
+	_, err = sm.backend.saveStack(sm.name, config, snapshot, sm.env, journal)
 	return err
+}
 
+func (sm *localSnapshotPersister) AppendJournal(entry apitype.JournalEntryV1) error {
+	return sm.backend.appendJournal(sm.name, entry)
 }
 
-func (b *localBackend) newSnapshotPersister(stackName tokens.QName) *localSnapshotPersister {
-	return &localSnapshotPersister{name: stackName, backend: b}
+// newSnapshotPersister returns a persister that journals the snapshots of the given stack, unless its checkpoints are
+// signed: journal entries are not, so in that case each snapshot is written in full.
+func (b *localBackend) newSnapshotPersister(stackName tokens.QName,
+	env map[string]string) (backend.SnapshotPersister, error) {
+
+	persister := &localSnapshotPersister{name: stackName, env: env, backend: b}
+	signer, err := checkpointSigner()
+	if err != nil {
+		return nil, err
+	}
+	if signer != nil {
+		return persister, nil
+	}
+	return backend.NewJournalingPersister(persister, journalCompactionInterval), nil
 }
@@ -78,6 +78,11 @@ func (s *localStack) Destroy(ctx context.Context, op backend.UpdateOperation) (e
 	return backend.DestroyStack(ctx, s, op)
 }
 
+func (s *localStack) Rollback(ctx context.Context, op backend.UpdateOperation,
+	version int) (engine.ResourceChanges, error) {
+	return backend.RollbackStack(ctx, s, op, version)
+}
+
 func (s *localStack) GetLogs(ctx context.Context, query operations.LogQuery) ([]operations.LogEntry, error) {
 	return backend.GetStackLogs(ctx, s, query)
 }
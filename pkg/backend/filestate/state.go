@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/encoding"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
@@ -42,6 +44,15 @@ import (
 
 const DisableCheckpointBackupsEnvVar = "PULUMI_DISABLE_CHECKPOINT_BACKUPS"
 
+// CheckpointBlobThresholdEnvVar, if set to a positive number of bytes, causes property values larger than that to be
+// offloaded from checkpoints to a content-addressed blob store alongside them. Checkpoints that refer to offloaded
+// values cannot be read by older versions of the CLI.
+const CheckpointBlobThresholdEnvVar = "PULUMI_CHECKPOINT_BLOB_THRESHOLD"
+
+// CheckpointCompactEnvVar, if set to a truthy value, causes checkpoints to store each repeated property value once,
+// referring to it from each place that it occurs. Compacted checkpoints cannot be read by older versions of the CLI.
+const CheckpointCompactEnvVar = "PULUMI_CHECKPOINT_COMPACT"
+
 // DisableIntegrityChecking can be set to true to disable checkpoint state integrity verification.  This is not
 // recommended, because it could mean proceeding even in the face of a corrupted checkpoint state file, but can
 // be used as a last resort when a command absolutely must be run.
@@ -128,7 +139,11 @@ func (b *localBackend) getStack(name tokens.QName) (config.Map, *deploy.Snapshot
 	}
 
 	// Materialize an actual snapshot object.
-	snapshot, err := stack.DeserializeCheckpoint(chk)
+	store, err := b.blobStore()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	snapshot, err := stack.DeserializeCheckpointWithBlobs(chk, store)
 	if err != nil {
 		return nil, nil, "", err
 	}
@@ -144,19 +159,42 @@ func (b *localBackend) getStack(name tokens.QName) (config.Map, *deploy.Snapshot
 	return chk.Config, snapshot, file, nil
 }
 
-// GetCheckpoint loads a checkpoint file for the given stack in this project, from the current project workspace.
+// GetCheckpoint loads a checkpoint file for the given stack in this project, from the current project workspace, and
+// replays the entries of its journal, if it has one.
 func (b *localBackend) getCheckpoint(stackName tokens.QName) (*apitype.CheckpointV3, error) {
 	chkpath := b.stackPath(stackName)
 	bytes, err := ioutil.ReadFile(chkpath)
 	if err != nil {
 		return nil, err
 	}
+	if err = verifyCheckpoint(chkpath, bytes); err != nil {
+		return nil, err
+	}
 
-	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var versioned apitype.VersionedCheckpoint
+	if err = json.Unmarshal(bytes, &versioned); err != nil {
+		return nil, err
+	}
+	if versioned.Journal != "" {
+		if err = b.replayJournal(stackName, chk, versioned.Journal, versioned.Signature != nil); err != nil {
+			return nil, errors.Wrapf(err, "%s: replaying journal", chkpath)
+		}
+	}
+	return chk, nil
 }
 
-func (b *localBackend) saveStack(name tokens.QName,
-	config map[config.Key]config.Value, snap *deploy.Snapshot) (string, error) {
+// saveStack writes the checkpoint for the given stack, recording its provenance and signing it if a signing key is
+// configured. The environment is the metadata of the update that produced the snapshot, if any.
+//
+// If journal is non-empty, it names the journal whose entries will extend the checkpoint (see appendJournal). Once the
+// checkpoint has been written, the stack's existing journal is no longer needed and is removed.
+func (b *localBackend) saveStack(name tokens.QName, config map[config.Key]config.Value, snap *deploy.Snapshot,
+	env map[string]string, journal string) (string, error) {
 	// Make a serializable stack and then use the encoder to encode it.
 	file := b.stackPath(name)
 	m, ext := encoding.Detect(file)
@@ -166,7 +204,15 @@ func (b *localBackend) saveStack(name tokens.QName,
 	if filepath.Ext(file) == "" {
 		file = file + ext
 	}
-	chk := stack.SerializeCheckpoint(name, config, snap)
+	chk, err := b.serializeCheckpoint(name, config, snap)
+	if err != nil {
+		return "", err
+	}
+	chk.Provenance = stack.NewProvenance(snap, env[backend.GitHead], env[backend.GitDirty] == "true")
+	chk.Journal = journal
+	if err = signCheckpoint(chk); err != nil {
+		return "", err
+	}
 	byts, err := m.Marshal(chk)
 	if err != nil {
 		return "", errors.Wrap(err, "An IO error occurred during the current operation")
@@ -187,6 +233,10 @@ func (b *localBackend) saveStack(name tokens.QName,
 
 	logging.V(7).Infof("Saved stack %s checkpoint to: %s (backup=%s)", name, file, bck)
 
+	if err = os.Remove(b.journalPath(name)); err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "An IO error occurred during the current operation")
+	}
+
 	// And if we are retaining historical checkpoint information, write it out again
 	if cmdutil.IsTruthy(os.Getenv("PULUMI_RETAIN_CHECKPOINTS")) {
 		if err = ioutil.WriteFile(fmt.Sprintf("%v.%v", file, time.Now().UnixNano()), byts, 0600); err != nil {
@@ -208,6 +258,32 @@ func (b *localBackend) saveStack(name tokens.QName,
 	return file, nil
 }
 
+// serializeCheckpoint serializes the checkpoint for the given stack, offloading large values to the blob store if
+// CheckpointBlobThresholdEnvVar is set, and sharing repeated values if CheckpointCompactEnvVar is set.
+func (b *localBackend) serializeCheckpoint(name tokens.QName, config map[config.Key]config.Value,
+	snap *deploy.Snapshot) (*apitype.VersionedCheckpoint, error) {
+
+	opts := stack.CheckpointOptions{Compact: cmdutil.IsTruthy(os.Getenv(CheckpointCompactEnvVar))}
+	if thresholdVar := os.Getenv(CheckpointBlobThresholdEnvVar); thresholdVar != "" {
+		threshold, err := strconv.Atoi(thresholdVar)
+		if err != nil || threshold <= 0 {
+			return nil, errors.Errorf("%v must be a positive number of bytes; got '%v'", CheckpointBlobThresholdEnvVar,
+				thresholdVar)
+		}
+		store, err := b.blobStore()
+		if err != nil {
+			return nil, err
+		}
+		opts.BlobStore, opts.BlobThreshold = store, threshold
+	}
+	return stack.SerializeCheckpointWithOptions(name, config, snap, opts)
+}
+
+// blobStore returns the store for large values offloaded from this backend's checkpoints.
+func (b *localBackend) blobStore() (plugin.LargeValueStore, error) {
+	return plugin.NewFileLargeValueStore(b.blobDirectory())
+}
+
 // removeStack removes information about a stack from the current workspace.
 func (b *localBackend) removeStack(name tokens.QName) error {
 	contract.Require(name != "", "name")
@@ -215,6 +291,7 @@ func (b *localBackend) removeStack(name tokens.QName) error {
 	// Just make a backup of the file and don't write out anything new.
 	file := b.stackPath(name)
 	backupTarget(file)
+	backupTarget(b.journalPath(name))
 
 	historyDir := b.historyDirectory(name)
 	return os.RemoveAll(historyDir)
@@ -282,9 +359,8 @@ func (b *localBackend) backupDirectory(stack tokens.QName) string {
 	return filepath.Join(b.StateDir(), workspace.BackupDir, fsutil.QnamePath(stack))
 }
 
-// getHistory returns locally stored update history. The first element of the result will be
-// the most recent update record.
-func (b *localBackend) getHistory(name tokens.QName) ([]backend.UpdateInfo, error) {
+// historyFiles returns the paths of the locally stored update history files for the given stack, oldest first.
+func (b *localBackend) historyFiles(name tokens.QName) ([]string, error) {
 	contract.Require(name != "", "name")
 
 	dir := b.historyDirectory(name)
@@ -297,18 +373,31 @@ func (b *localBackend) getHistory(name tokens.QName) ([]backend.UpdateInfo, erro
 		return nil, err
 	}
 
-	var updates []backend.UpdateInfo
+	// os.ReadDir returns the array sorted by file name, and because of how we name files, older updates come before
+	// newer ones.
+	var files []string
+	for _, file := range allFiles {
+		// Ignore the checkpoints.
+		if strings.HasSuffix(file.Name(), ".history.json") {
+			files = append(files, path.Join(dir, file.Name()))
+		}
+	}
+	return files, nil
+}
 
-	// os.ReadDir returns the array sorted by file name, but because of how we name files, older updates come before
-	// newer ones. Loop backwards so we added the newest updates to the array we will return first.
-	for i := len(allFiles) - 1; i >= 0; i-- {
-		file := allFiles[i]
-		filepath := path.Join(dir, file.Name())
+// getHistory returns locally stored update history. The first element of the result will be
+// the most recent update record.
+func (b *localBackend) getHistory(name tokens.QName) ([]backend.UpdateInfo, error) {
+	files, err := b.historyFiles(name)
+	if err != nil {
+		return nil, err
+	}
 
-		// Open all of the history files, ignoring the checkpoints.
-		if !strings.HasSuffix(filepath, ".history.json") {
-			continue
-		}
+	var updates []backend.UpdateInfo
+
+	// Loop backwards so we add the newest updates to the array we will return first.
+	for i := len(files) - 1; i >= 0; i-- {
+		filepath := files[i]
 
 		var update backend.UpdateInfo
 		b, err := ioutil.ReadFile(filepath)
@@ -320,12 +409,41 @@ func (b *localBackend) getHistory(name tokens.QName) ([]backend.UpdateInfo, erro
 			return nil, errors.Wrapf(err, "reading history file %s", filepath)
 		}
 
+		// History files written by older versions of the CLI do not record a version or config digest.
+		if update.Version == 0 {
+			update.Version = i + 1
+		}
+		if update.ConfigDigest == "" {
+			update.ConfigDigest = backend.ConfigDigest(update.Config)
+		}
+
 		updates = append(updates, update)
 	}
 
 	return updates, nil
 }
 
+// getHistoryCheckpoint loads the checkpoint saved by the given version of the stack.
+func (b *localBackend) getHistoryCheckpoint(name tokens.QName, version int) (*apitype.CheckpointV3, error) {
+	files, err := b.historyFiles(name)
+	if err != nil {
+		return nil, err
+	}
+	if version < 1 || version > len(files) {
+		return nil, errors.Errorf("stack %s has no version %d", name, version)
+	}
+
+	chkpath := strings.TrimSuffix(files[version-1], ".history.json") + ".checkpoint.json"
+	bytes, err := ioutil.ReadFile(chkpath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading checkpoint for version %d", version)
+	}
+	if err = verifyCheckpoint(chkpath, bytes); err != nil {
+		return nil, err
+	}
+	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+}
+
 // addToHistory saves the UpdateInfo and makes a copy of the current Checkpoint file.
 func (b *localBackend) addToHistory(name tokens.QName, update backend.UpdateInfo) error {
 	contract.Require(name != "", "name")
@@ -335,6 +453,13 @@ func (b *localBackend) addToHistory(name tokens.QName, update backend.UpdateInfo
 		return err
 	}
 
+	// Number this update after those that precede it.
+	files, err := b.historyFiles(name)
+	if err != nil {
+		return err
+	}
+	update.Version = len(files) + 1
+
 	// Prefix for the update and checkpoint files.
 	pathPrefix := path.Join(dir, fmt.Sprintf("%s-%d", name, time.Now().UnixNano()))
 
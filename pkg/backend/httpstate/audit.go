@@ -0,0 +1,51 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpstate
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend/httpstate/client"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+// cloudAuditLog appends audit log entries to an update's audit log in the Pulumi service.
+type cloudAuditLog struct {
+	context     context.Context         // The context to use for client requests.
+	update      client.UpdateIdentifier // The UpdateIdentifier for this update sequence.
+	tokenSource *tokenSource            // A token source for interacting with the service.
+	backend     *cloudBackend           // A backend for communicating with the service
+}
+
+func (l *cloudAuditLog) Append(entry apitype.AuditLogEntry) error {
+	token, err := l.tokenSource.GetToken()
+	if err != nil {
+		return err
+	}
+	return l.backend.client.AppendUpdateAuditLogEntries(l.context, l.update, []apitype.AuditLogEntry{entry}, token)
+}
+
+var _ deploy.AuditLog = (*cloudAuditLog)(nil)
+
+func (cb *cloudBackend) newAuditLog(ctx context.Context, update client.UpdateIdentifier,
+	tokenSource *tokenSource) *cloudAuditLog {
+	return &cloudAuditLog{
+		context:     ctx,
+		update:      update,
+		tokenSource: tokenSource,
+		backend:     cb,
+	}
+}
@@ -680,6 +680,18 @@ func (b *cloudBackend) Destroy(ctx context.Context, stackRef backend.StackRefere
 	return backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
 }
 
+func (b *cloudBackend) Rollback(ctx context.Context, stackRef backend.StackReference,
+	op backend.UpdateOperation, version int) (engine.ResourceChanges, error) {
+	stack, err := getStack(ctx, b, stackRef)
+	if err != nil {
+		return nil, err
+	}
+	if op.RollbackTo, err = backend.RollbackSnapshot(ctx, b, stackRef, version); err != nil {
+		return nil, err
+	}
+	return backend.PreviewThenPromptThenExecute(ctx, apitype.RollbackUpdate, stack, op, b.apply)
+}
+
 func (b *cloudBackend) createAndStartUpdate(
 	ctx context.Context, action apitype.UpdateKind, stack backend.Stack,
 	op backend.UpdateOperation, dryRun bool) (client.UpdateIdentifier, int, string, error) {
@@ -733,10 +745,12 @@ func (b *cloudBackend) createAndStartUpdate(
 // apply actually performs the provided type of update on a stack hosted in the Pulumi Cloud.
 func (b *cloudBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack backend.Stack,
 	op backend.UpdateOperation, opts backend.ApplierOptions, events chan<- engine.Event) (engine.ResourceChanges, error) {
-	// Print a banner so it's clear this is going to the cloud.
+	// Print a banner so it's clear this is going to the cloud, unless stdout is reserved for JSON.
 	actionLabel := backend.ActionLabel(kind, opts.DryRun)
-	fmt.Printf(op.Opts.Display.Color.Colorize(
-		colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stack.Ref())
+	if !op.Opts.Display.JSONDisplay {
+		fmt.Printf(op.Opts.Display.Color.Colorize(
+			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stack.Ref())
+	}
 
 	// Create an update object to persist results.
 	update, version, token, err := b.createAndStartUpdate(ctx, kind, stack, op, opts.DryRun)
@@ -744,7 +758,7 @@ func (b *cloudBackend) apply(ctx context.Context, kind apitype.UpdateKind, stack
 		return nil, err
 	}
 
-	if opts.ShowLink {
+	if opts.ShowLink && !op.Opts.Display.JSONDisplay {
 		// Print a URL at the end of the update pointing to the Pulumi Service.
 		var link string
 		base := b.cloudConsoleStackPath(update.StackIdentifier)
@@ -776,6 +790,10 @@ func (b *cloudBackend) runEngineAction(
 	if err != nil {
 		return nil, err
 	}
+	notifier, err := backend.NewUpdateNotifier(ctx, kind, u.GetTarget(), op.Proj.Name, dryRun)
+	if err != nil {
+		return nil, err
+	}
 
 	// displayEvents renders the event to the console and Pulumi service. The processor for the
 	// will signal all events have been proceed when a value is written to the displayDone channel.
@@ -795,6 +813,9 @@ func (b *cloudBackend) runEngineAction(
 			if callerEventsOpt != nil {
 				callerEventsOpt <- e
 			}
+			if notifier != nil {
+				engine.DispatchEvent(e, notifier)
+			}
 		}
 
 		close(eventsDone)
@@ -813,11 +834,15 @@ func (b *cloudBackend) runEngineAction(
 		Events:          engineEvents,
 		SnapshotManager: snapshotManager,
 		BackendClient:   httpstateBackendClient{backend: b},
+		AuditLog:        b.newAuditLog(ctx, u.update, u.tokenSource),
 	}
 	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
 		engineCtx.ParentSpan = parentSpan.Context()
 	}
 
+	if notifier != nil {
+		notifier.Started()
+	}
 	var changes engine.ResourceChanges
 	switch kind {
 	case apitype.PreviewUpdate:
@@ -828,6 +853,8 @@ func (b *cloudBackend) runEngineAction(
 		changes, err = engine.Refresh(u, engineCtx, op.Opts.Engine, dryRun)
 	case apitype.DestroyUpdate:
 		changes, err = engine.Destroy(u, engineCtx, op.Opts.Engine, dryRun)
+	case apitype.RollbackUpdate:
+		changes, err = engine.Rollback(u, engineCtx, op.Opts.Engine, op.RollbackTo, dryRun)
 	default:
 		contract.Failf("Unrecognized update kind: %s", kind)
 	}
@@ -842,6 +869,9 @@ func (b *cloudBackend) runEngineAction(
 	// has exited before proceeding
 	<-eventsDone
 	close(displayEvents)
+	if notifier != nil {
+		notifier.Finished(err)
+	}
 
 	// Mark the update as complete.
 	status := apitype.UpdateStatusSucceeded
@@ -906,10 +936,12 @@ func (b *cloudBackend) GetHistory(ctx context.Context, stackRef backend.StackRef
 			Message:         update.Message,
 			Environment:     update.Environment,
 			Config:          cfg,
+			ConfigDigest:    backend.ConfigDigest(cfg),
 			Result:          backend.UpdateResult(update.Result),
 			StartTime:       update.StartTime,
 			EndTime:         update.EndTime,
 			ResourceChanges: convertResourceChanges(update.ResourceChanges),
+			Version:         update.Version,
 		})
 	}
 
@@ -987,6 +1019,22 @@ func (b *cloudBackend) ExportDeployment(ctx context.Context,
 	return &deployment, nil
 }
 
+func (b *cloudBackend) ExportDeploymentVersion(ctx context.Context,
+	stackRef backend.StackReference, version int) (*apitype.UntypedDeployment, error) {
+
+	stack, err := b.getCloudStackIdentifier(stackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := b.client.ExportStackDeploymentVersion(ctx, stack, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
 func (b *cloudBackend) ImportDeployment(ctx context.Context, stackRef backend.StackReference,
 	deployment *apitype.UntypedDeployment) error {
 
@@ -21,6 +21,7 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"strconv"
 	"time"
 
 	"github.com/blang/semver"
@@ -325,6 +326,20 @@ func (pc *Client) ExportStackDeployment(ctx context.Context,
 	return apitype.UntypedDeployment(resp), nil
 }
 
+// ExportStackDeploymentVersion exports the deployment produced by the given version of the indicated stack as a raw
+// JSON message.
+func (pc *Client) ExportStackDeploymentVersion(ctx context.Context, stack StackIdentifier,
+	version int) (apitype.UntypedDeployment, error) {
+
+	var resp apitype.ExportStackResponse
+	exportPath := getStackPath(stack, "export", strconv.Itoa(version))
+	if err := pc.restCall(ctx, "GET", exportPath, nil, nil, &resp); err != nil {
+		return apitype.UntypedDeployment{}, err
+	}
+
+	return apitype.UntypedDeployment(resp), nil
+}
+
 // ImportStackDeployment imports a new deployment into the indicated stack.
 func (pc *Client) ImportStackDeployment(ctx context.Context, stack StackIdentifier,
 	deployment *apitype.UntypedDeployment) (UpdateIdentifier, error) {
@@ -394,6 +409,9 @@ func (pc *Client) CreateUpdate(
 		endpoint = "refresh"
 	case apitype.DestroyUpdate:
 		endpoint = "destroy"
+	case apitype.RollbackUpdate:
+		// The service has no endpoint of its own for rollbacks, which it records as updates.
+		endpoint = "update"
 	default:
 		contract.Failf("Unknown kind: %s", kind)
 	}
@@ -535,6 +553,18 @@ func (pc *Client) RecordEngineEvent(
 		updateAccessToken(token), callOpts)
 }
 
+// AppendUpdateAuditLogEntries appends entries to the audit log of the indicated update.
+func (pc *Client) AppendUpdateAuditLogEntries(ctx context.Context, update UpdateIdentifier,
+	entries []apitype.AuditLogEntry, token string) error {
+
+	req := apitype.AppendUpdateAuditLogRequest{Entries: entries}
+
+	// It is safe to retry this POST operation, because each entry carries its sequence number, so the service can
+	// discard entries it has already recorded.
+	return pc.updateRESTCall(ctx, "POST", getUpdatePath(update, "auditlog"), nil, req, nil,
+		updateAccessToken(token), httpCallOptions{RetryAllMethods: true, GzipCompress: true})
+}
+
 // UpdateStackTags updates the stacks's tags, replacing all existing tags.
 func (pc *Client) UpdateStackTags(
 	ctx context.Context, stack StackIdentifier, tags map[apitype.StackTagName]string) error {
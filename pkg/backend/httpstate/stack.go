@@ -143,6 +143,11 @@ func (s *cloudStack) Destroy(ctx context.Context, op backend.UpdateOperation) (e
 	return backend.DestroyStack(ctx, s, op)
 }
 
+func (s *cloudStack) Rollback(ctx context.Context, op backend.UpdateOperation,
+	version int) (engine.ResourceChanges, error) {
+	return backend.RollbackStack(ctx, s, op, version)
+}
+
 func (s *cloudStack) GetLogs(ctx context.Context, query operations.LogQuery) ([]operations.LogEntry, error) {
 	return backend.GetStackLogs(ctx, s, query)
 }
@@ -0,0 +1,39 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/engine/notify"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// NewUpdateNotifier returns a notifier for the notification sinks configured for the given update's stack, or nil if
+// the stack has none or the update is only a preview. Backends feed the notifier the update's engine events.
+func NewUpdateNotifier(ctx context.Context, kind apitype.UpdateKind, target *deploy.Target,
+	project tokens.PackageName, dryRun bool) (*notify.Notifier, error) {
+
+	if dryRun || kind == apitype.PreviewUpdate {
+		return nil, nil
+	}
+	subs, err := notify.GetSubscriptions(target)
+	if err != nil || len(subs) == 0 {
+		return nil, err
+	}
+	return notify.NewNotifier(ctx, string(kind), target.Name, project, subs), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// GetRedactedSnapshot returns the stack's latest snapshot with its secrets and the values at the given sensitive paths
+// redacted (see RedactSnapshot).  It gives read-only viewers, such as on-call engineers, a view of a stack's state that
+// does not expose its secrets.
+func GetRedactedSnapshot(ctx context.Context, s Stack, paths []resource.PropertyPath) (*deploy.Snapshot, error) {
+	snap, err := s.Snapshot(ctx)
+	if err != nil || snap == nil {
+		return snap, err
+	}
+	return RedactStackSnapshot(s, snap, paths)
+}
+
+// RedactStackSnapshot redacts the given snapshot of the stack (see RedactSnapshot), using the stack's configuration
+// and its secrets.
+func RedactStackSnapshot(s Stack, snap *deploy.Snapshot, paths []resource.PropertyPath) (*deploy.Snapshot, error) {
+	var decrypter config.Decrypter = config.NopDecrypter
+	if s.Config().HasSecureValue() {
+		crypter, err := s.Backend().GetStackCrypter(s.Ref())
+		if err != nil {
+			return nil, err
+		}
+		decrypter = crypter
+	}
+	return RedactSnapshot(snap, s.Config(), decrypter, paths)
+}
+
+// RedactSnapshot returns a copy of the given snapshot in which the values at the given paths in every resource's
+// inputs and outputs have been replaced by their digests (see resource.RedactValue).  The settings of provider
+// resources that come from secret configuration values are redacted as well, and so is every occurrence of a secret
+// configuration value in any other string, just as such values are filtered out of the CLI's logs.  The snapshot
+// itself is not modified.
+func RedactSnapshot(snap *deploy.Snapshot, cfg config.Map, decrypter config.Decrypter,
+	paths []resource.PropertyPath) (*deploy.Snapshot, error) {
+
+	// Providers are configured by the keys in their package's namespace, so a secret `aws:secretKey` ends up in the
+	// `secretKey` property of the `aws` provider's inputs and outputs.
+	secretPaths := make(map[string][]resource.PropertyPath)
+	var secrets []string
+	for k, v := range cfg {
+		if !v.Secure() {
+			continue
+		}
+		secretPaths[k.Namespace()] = append(secretPaths[k.Namespace()], resource.PropertyPath{k.Name()})
+
+		secret, err := v.Value(decrypter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt configuration value '%v'", k)
+		}
+		secrets = append(secrets, secret)
+	}
+	filter := logging.CreateFilter(secrets, "[secret]")
+
+	redact := func(res *resource.State) *resource.State {
+		resPaths := paths
+		if providers.IsProviderType(res.Type) {
+			resPaths = append(append([]resource.PropertyPath(nil), paths...), secretPaths[res.Type.Name().String()]...)
+		}
+
+		redacted := *res
+		redacted.Inputs = filterSecrets(filter, res.Inputs.Redact(resPaths))
+		redacted.Outputs = filterSecrets(filter, res.Outputs.Redact(resPaths))
+		// The hashes of the inputs describe their unredacted values, so they are dropped along with them.
+		redacted.InputHashes = nil
+		return &redacted
+	}
+
+	resources := make([]*resource.State, len(snap.Resources))
+	for i, res := range snap.Resources {
+		resources[i] = redact(res)
+	}
+	ops := make([]resource.Operation, len(snap.PendingOperations))
+	for i, op := range snap.PendingOperations {
+		ops[i] = resource.NewOperation(redact(op.Resource), op.Type)
+	}
+	return deploy.NewSnapshot(snap.Manifest, resources, ops), nil
+}
+
+// filterSecrets replaces each secret that the given filter matches in the strings of the given map.  The map is
+// modified in place.
+func filterSecrets(filter logging.Filter, m resource.PropertyMap) resource.PropertyMap {
+	for k, v := range m {
+		m[k] = filterSecretsValue(filter, v)
+	}
+	return m
+}
+
+func filterSecretsValue(filter logging.Filter, v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsString():
+		return resource.NewStringProperty(filter.Filter(v.StringValue()))
+	case v.IsArray():
+		for i, e := range v.ArrayValue() {
+			v.ArrayValue()[i] = filterSecretsValue(filter, e)
+		}
+	case v.IsObject():
+		filterSecrets(filter, v.ObjectValue())
+	}
+	return v
+}
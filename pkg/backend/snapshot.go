@@ -25,6 +25,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
 	"github.com/pulumi/pulumi/pkg/version"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -64,6 +65,9 @@ type SnapshotManager struct {
 	mutationRequests chan<- mutationRequest   // The queue of mutation requests, to be retired serially by the manager
 	cancel           chan bool                // A channel used to request cancellation of any new mutation requests.
 	done             <-chan error             // A channel that sends a single result when the manager has shut down.
+
+	// The set of old URNs that have been renamed by an alias, mapped to their new URNs
+	aliases map[resource.URN]resource.URN
 }
 
 var _ engine.SnapshotManager = (*SnapshotManager)(nil)
@@ -176,8 +180,11 @@ type sameSnapshotMutation struct {
 // step that forces us to write the checkpoint. If no such difference exists, the checkpoint write that corresponds to
 // this step can be elided.
 func (ssm *sameSnapshotMutation) mustWrite(old, new *resource.State) bool {
-	contract.Assert(old.Type == new.Type)
-	contract.Assert(old.URN == new.URN)
+	// If the URN or type of this resource has changed (i.e. it was renamed via an alias), we must write the checkpoint.
+	if old.URN != new.URN || old.Type != new.Type {
+		return true
+	}
+
 	contract.Assert(old.Delete == new.Delete)
 	contract.Assert(old.External == new.External)
 
@@ -226,6 +233,7 @@ func (ssm *sameSnapshotMutation) End(step deploy.Step, successful bool) error {
 	return ssm.manager.mutate(func() bool {
 		ssm.manager.markDone(step.Old())
 		ssm.manager.markNew(step.New())
+		ssm.manager.markAliased(step.Old(), step.New())
 
 		// Note that "Same" steps only consider input and provider diffs, so it is possible to see a same step for a
 		// resource with new dependencies, outputs, parent, protection. etc.
@@ -279,6 +287,11 @@ func (csm *createSnapshotMutation) End(step deploy.Step, successful bool) error
 			if old := step.Old(); old != nil && old.PendingReplacement {
 				csm.manager.markDone(old)
 			}
+
+			// If this replacement was matched to its old state by an alias, record the rename.
+			if old := step.Old(); old != nil {
+				csm.manager.markAliased(old, step.New())
+			}
 		}
 		return true
 	})
@@ -309,6 +322,7 @@ func (usm *updateSnapshotMutation) End(step deploy.Step, successful bool) error
 		if successful {
 			usm.manager.markDone(step.Old())
 			usm.manager.markNew(step.New())
+			usm.manager.markAliased(step.Old(), step.New())
 		}
 		return true
 	})
@@ -337,7 +351,8 @@ func (dsm *deleteSnapshotMutation) End(step deploy.Step, successful bool) error
 	return dsm.manager.mutate(func() bool {
 		dsm.manager.markOperationComplete(step.Old())
 		if successful {
-			contract.Assert(!step.Old().Protect)
+			// Note that the resource may be protected: the plan only deletes protected resources when it was asked to
+			// ignore protection, and such deletes must be recorded like any other.
 			if !step.Old().PendingReplacement {
 				dsm.manager.markDone(step.Old())
 			}
@@ -437,6 +452,17 @@ func (sm *SnapshotManager) markNew(state *resource.State) {
 	logging.V(9).Infof("Appended new state snapshot to be written: %v", state.URN)
 }
 
+// markAliased records that a resource has been renamed from its old URN to its new URN via an alias. References to the
+// old URN by resources from the base snapshot that have not yet been processed are rewritten when the snapshot is
+// produced.
+func (sm *SnapshotManager) markAliased(old, new *resource.State) {
+	contract.Assert(old != nil && new != nil)
+	if old.URN != new.URN {
+		sm.aliases[old.URN] = new.URN
+		logging.V(9).Infof("Marked old state snapshot as aliased: %v -> %v", old.URN, new.URN)
+	}
+}
+
 // markOperationPending marks a resource as undergoing an operation that will now be considered pending.
 func (sm *SnapshotManager) markOperationPending(state *resource.State, op resource.OperationType) {
 	contract.Assert(state != nil)
@@ -491,7 +517,7 @@ func (sm *SnapshotManager) snap() *deploy.Snapshot {
 	if base := sm.baseSnapshot; base != nil {
 		for _, res := range base.Resources {
 			if !sm.dones[res] {
-				resources = append(resources, res)
+				resources = append(resources, sm.rewriteAliases(res))
 			}
 		}
 	}
@@ -514,10 +540,105 @@ func (sm *SnapshotManager) snap() *deploy.Snapshot {
 	return deploy.NewSnapshot(manifest, resources, operations)
 }
 
+// rewriteAliases returns a copy of the given base state whose references to renamed resources have been updated to
+// refer to their new URNs. If the state does not refer to any renamed resources, it is returned as-is.
+func (sm *SnapshotManager) rewriteAliases(res *resource.State) *resource.State {
+	if len(sm.aliases) == 0 {
+		return res
+	}
+
+	rewrite := func(urn resource.URN) (resource.URN, bool) {
+		if alias, has := sm.aliases[urn]; has {
+			return alias, true
+		}
+		return urn, false
+	}
+	rewriteDeps := func(deps []resource.URN) ([]resource.URN, bool) {
+		var result []resource.URN
+		for i, dep := range deps {
+			if alias, changed := rewrite(dep); changed {
+				if result == nil {
+					result = make([]resource.URN, len(deps))
+					copy(result, deps)
+				}
+				result[i] = alias
+			}
+		}
+		if result == nil {
+			return deps, false
+		}
+		return result, true
+	}
+
+	parent, changed := rewrite(res.Parent)
+	deps, depsChanged := rewriteDeps(res.Dependencies)
+	changed = changed || depsChanged
+	var propDeps map[resource.PropertyKey][]resource.URN
+	for k, v := range res.PropertyDependencies {
+		if rewritten, propChanged := rewriteDeps(v); propChanged {
+			if propDeps == nil {
+				propDeps = make(map[resource.PropertyKey][]resource.URN, len(res.PropertyDependencies))
+				for pk, pv := range res.PropertyDependencies {
+					propDeps[pk] = pv
+				}
+			}
+			propDeps[k] = rewritten
+		}
+	}
+	propReads, readsChanged := sm.rewriteReadAliases(res.PropertyReads)
+	if !changed && propDeps == nil && !readsChanged {
+		return res
+	}
+
+	// Note that the base snapshot must not be mutated, so we make a shallow copy of the state.
+	copied := *res
+	copied.Parent, copied.Dependencies = parent, deps
+	if propDeps != nil {
+		copied.PropertyDependencies = propDeps
+	}
+	copied.PropertyReads = propReads
+	return &copied
+}
+
+// rewriteReadAliases returns a copy of the given property reads whose references to renamed resources have been
+// updated to refer to their new URNs, and true if there were any such references. Otherwise, it returns the reads
+// as-is.
+func (sm *SnapshotManager) rewriteReadAliases(reads resource.PropertyReads) (resource.PropertyReads, bool) {
+	changed := false
+	for _, byURN := range reads {
+		for urn := range byURN {
+			if _, has := sm.aliases[urn]; has {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return reads, false
+	}
+
+	result := make(resource.PropertyReads, len(reads))
+	for pk, byURN := range reads {
+		result[pk] = make(map[resource.URN][]string, len(byURN))
+		for urn, paths := range byURN {
+			if alias, has := sm.aliases[urn]; has {
+				urn = alias
+			}
+			result[pk][urn] = paths
+		}
+	}
+	return result, true
+}
+
+var snapshotSaveDuration = metrics.Default.NewHistogram("pulumi_backend_snapshot_save_duration_seconds",
+	"The time taken to persist each snapshot to the state backend.", nil)
+
 // saveSnapshot persists the current snapshot and optionally verifies it afterwards.
 func (sm *SnapshotManager) saveSnapshot() error {
 	snap := sm.snap()
-	if err := sm.persister.Save(snap); err != nil {
+	start := time.Now()
+	err := sm.persister.Save(snap)
+	snapshotSaveDuration.ObserveSince(start)
+	if err != nil {
 		return errors.Wrap(err, "failed to save snapshot")
 	}
 	if sm.doVerify {
@@ -542,6 +663,7 @@ func NewSnapshotManager(persister SnapshotPersister, baseSnap *deploy.Snapshot)
 		baseSnapshot:     baseSnap,
 		dones:            make(map[*resource.State]bool),
 		completeOps:      make(map[*resource.State]bool),
+		aliases:          make(map[resource.URN]resource.URN),
 		doVerify:         true,
 		mutationRequests: mutationRequests,
 		cancel:           cancel,
@@ -0,0 +1,105 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"sync"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// SnapshotJournal is implemented by backends that can append to a stack's checkpoint rather than rewriting it.
+type SnapshotJournal interface {
+	// SaveCheckpoint writes the given snapshot in full as the stack's checkpoint, naming the journal whose entries
+	// will extend it. Once the checkpoint has been written, the entries of any earlier journal may be discarded.
+	SaveCheckpoint(snap *deploy.Snapshot, journal string) error
+	// AppendJournal durably appends an entry to the journal named by the stack's checkpoint.
+	AppendJournal(entry apitype.JournalEntryV1) error
+}
+
+// JournalingPersister is a SnapshotPersister that writes a stack's checkpoint in full only for its first snapshot and
+// then after every so many entries, and in between appends the changes that each snapshot makes to the last to the
+// checkpoint's journal. Each write is then proportional to the size of a step rather than to the size of the stack,
+// yet a process that crashes loses at most the step that it was in the midst of.
+//
+// Saves are serialized, so the persister may be shared by concurrent callers.
+type JournalingPersister struct {
+	journal      SnapshotJournal
+	compactEvery int // the number of entries after which the checkpoint is written in full again.
+
+	lock     sync.Mutex
+	id       string                // the ID of the current journal; empty until the first snapshot is saved.
+	sequence int                   // the sequence number of the last entry appended to the current journal.
+	last     *apitype.DeploymentV3 // the deployment as of the last save.
+	lastSnap *deploy.Snapshot      // the snapshot that was last saved.
+}
+
+var _ SnapshotPersister = (*JournalingPersister)(nil)
+
+// NewJournalingPersister returns a persister that saves snapshots to the given journal, writing the checkpoint in
+// full again after every compactEvery entries. If compactEvery is not positive, the checkpoint is only written in full
+// for the first snapshot and by Compact.
+func NewJournalingPersister(journal SnapshotJournal, compactEvery int) *JournalingPersister {
+	return &JournalingPersister{journal: journal, compactEvery: compactEvery}
+}
+
+// Save appends the changes that the given snapshot makes to the last one to the journal, or writes the snapshot in
+// full if it is the first or the journal is due to be compacted.
+func (p *JournalingPersister) Save(snap *deploy.Snapshot) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	deployment := stack.SerializeDeployment(snap)
+	if p.id == "" || (p.compactEvery > 0 && p.sequence >= p.compactEvery) {
+		return p.compact(snap, deployment)
+	}
+
+	entry := stack.DiffDeployments(p.id, p.sequence+1, p.last, deployment)
+	if err := p.journal.AppendJournal(entry); err != nil {
+		return err
+	}
+	logging.V(9).Infof("JournalingPersister: appended entry %d to journal %s (kept %d+%d resources, wrote %d)",
+		entry.Sequence, p.id, entry.KeepPrefix, entry.KeepSuffix, len(entry.Resources))
+	p.sequence, p.last, p.lastSnap = entry.Sequence, deployment, snap
+	return nil
+}
+
+// Compact writes the last snapshot that was saved in full, so that loading the stack need not replay a journal. It
+// does nothing if no snapshot has been saved or the journal is empty.
+func (p *JournalingPersister) Compact() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.lastSnap == nil || p.sequence == 0 {
+		return nil
+	}
+	return p.compact(p.lastSnap, stack.SerializeDeployment(p.lastSnap))
+}
+
+// compact writes the given snapshot in full and starts a new journal to extend it.
+func (p *JournalingPersister) compact(snap *deploy.Snapshot, deployment *apitype.DeploymentV3) error {
+	id := uuid.NewV4().String()
+	if err := p.journal.SaveCheckpoint(snap, id); err != nil {
+		return err
+	}
+	logging.V(9).Infof("JournalingPersister: wrote checkpoint in full; starting journal %s", id)
+	p.id, p.sequence, p.last, p.lastSnap = id, 0, deployment, snap
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+)
+
+// MockSnapshotJournal keeps a stack's checkpoint and journal in memory.
+type MockSnapshotJournal struct {
+	Checkpoint *apitype.CheckpointV3
+	Journal    string
+	Entries    []apitype.JournalEntryV1
+	FullWrites int
+}
+
+func (m *MockSnapshotJournal) SaveCheckpoint(snap *deploy.Snapshot, journal string) error {
+	m.Checkpoint = &apitype.CheckpointV3{Latest: stack.SerializeDeployment(snap)}
+	m.Journal, m.Entries = journal, nil
+	m.FullWrites++
+	return nil
+}
+
+func (m *MockSnapshotJournal) AppendJournal(entry apitype.JournalEntryV1) error {
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+// Load returns the deployment that a backend would load from the journal.
+func (m *MockSnapshotJournal) Load(t *testing.T) *apitype.DeploymentV3 {
+	chk := *m.Checkpoint
+	assert.NoError(t, stack.ReplayJournal(&chk, m.Journal, m.Entries))
+	return chk.Latest
+}
+
+func TestJournalingPersister(t *testing.T) {
+	journal := &MockSnapshotJournal{}
+	persister := NewJournalingPersister(journal, 3)
+
+	a, b, c := NewResource("a"), NewResource("b"), NewResource("c")
+	snaps := []*deploy.Snapshot{
+		NewSnapshot([]*resource.State{a}),
+		NewSnapshot([]*resource.State{a, b}),
+		NewSnapshot([]*resource.State{a, b, c}),
+		NewSnapshot([]*resource.State{b, c}),
+		NewSnapshot([]*resource.State{c}),
+	}
+
+	// The first snapshot is written in full, and those that follow are journaled until the journal is compacted.
+	for i, snap := range snaps {
+		assert.NoError(t, persister.Save(snap))
+		assert.Equal(t, stack.SerializeDeployment(snap), journal.Load(t))
+		if i < 4 {
+			assert.Equal(t, 1, journal.FullWrites)
+			assert.Len(t, journal.Entries, i)
+		}
+	}
+	assert.Equal(t, 2, journal.FullWrites)
+	assert.Len(t, journal.Entries, 0)
+
+	// Changes made in place to the resources of the last snapshot are journaled, too.
+	c.Outputs["size"] = resource.NewNumberProperty(3)
+	assert.NoError(t, persister.Save(NewSnapshot([]*resource.State{c})))
+	assert.Len(t, journal.Entries, 1)
+	assert.Equal(t, map[string]interface{}{"size": 3.0}, journal.Load(t).Resources[0].Outputs)
+
+	// Compacting writes the last snapshot in full.
+	assert.NoError(t, persister.Compact())
+	assert.Equal(t, 3, journal.FullWrites)
+	assert.Len(t, journal.Entries, 0)
+	assert.Equal(t, map[string]interface{}{"size": 3.0}, journal.Load(t).Resources[0].Outputs)
+}
@@ -328,8 +328,8 @@ func TestVexingDeployment(t *testing.T) {
 	cPrime := NewResource(string(c.URN), bPrime.URN)
 
 	// mocking out the behavior of a provider indicating that this resource needs to be deleted
-	createReplacement := deploy.NewCreateReplacementStep(nil, MockRegisterResourceEvent{}, c, cPrime, nil, true)
-	replace := deploy.NewReplaceStep(nil, c, cPrime, nil, true)
+	createReplacement := deploy.NewCreateReplacementStep(nil, MockRegisterResourceEvent{}, c, cPrime, nil, nil, nil, true)
+	replace := deploy.NewReplaceStep(nil, c, cPrime, nil, nil, nil, true)
 	c.Delete = true
 
 	applyStep(createReplacement)
@@ -338,7 +338,7 @@ func TestVexingDeployment(t *testing.T) {
 	// cPrime now exists, c is now pending deletion
 	// dPrime now depends on cPrime, which got replaced
 	dPrime := NewResource(string(d.URN), cPrime.URN)
-	applyStep(deploy.NewUpdateStep(nil, MockRegisterResourceEvent{}, d, dPrime, nil))
+	applyStep(deploy.NewUpdateStep(nil, MockRegisterResourceEvent{}, d, dPrime, nil, nil, nil))
 
 	lastSnap := sp.SavedSnapshots[len(sp.SavedSnapshots)-1]
 	assert.Len(t, lastSnap.Resources, 6)
@@ -427,6 +427,35 @@ func TestFailedDelete(t *testing.T) {
 	assert.Equal(t, resourceA.URN, lastSnap.Resources[0].URN)
 }
 
+func TestForceUnprotectDelete(t *testing.T) {
+	resourceA := NewResource("a")
+	resourceA.Protect = true
+	resourceB := NewResource("b", resourceA.URN)
+	snap := NewSnapshot([]*resource.State{
+		resourceA,
+		resourceB,
+	})
+
+	// Plans that ignore protection delete protected resources with ordinary delete steps; recording them must not
+	// fail, and must remove the resources from the snapshot.
+	manager, sp := MockSetup(t, snap)
+	for _, res := range []*resource.State{resourceB, resourceA} {
+		step := deploy.NewDeleteStep(nil, res)
+		mutation, err := manager.BeginMutation(step)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		err = mutation.End(step, true /* successful */)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+	}
+
+	snap = sp.LastSnap()
+	assert.Len(t, snap.Resources, 0)
+	assert.Len(t, snap.PendingOperations, 0)
+}
+
 func TestRecordingCreateSuccess(t *testing.T) {
 	resourceA := NewResource("a")
 	snap := NewSnapshot(nil)
@@ -498,7 +527,7 @@ func TestRecordingUpdateSuccess(t *testing.T) {
 	})
 
 	manager, sp := MockSetup(t, snap)
-	step := deploy.NewUpdateStep(nil, &MockRegisterResourceEvent{}, resourceA, resourceANew, nil)
+	step := deploy.NewUpdateStep(nil, &MockRegisterResourceEvent{}, resourceA, resourceANew, nil, nil, nil)
 	mutation, err := manager.BeginMutation(step)
 	if !assert.NoError(t, err) {
 		t.FailNow()
@@ -537,7 +566,7 @@ func TestRecordingUpdateFailure(t *testing.T) {
 	})
 
 	manager, sp := MockSetup(t, snap)
-	step := deploy.NewUpdateStep(nil, &MockRegisterResourceEvent{}, resourceA, resourceANew, nil)
+	step := deploy.NewUpdateStep(nil, &MockRegisterResourceEvent{}, resourceA, resourceANew, nil, nil, nil)
 	mutation, err := manager.BeginMutation(step)
 	if !assert.NoError(t, err) {
 		t.FailNow()
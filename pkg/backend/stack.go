@@ -47,6 +47,8 @@ type Stack interface {
 	Refresh(ctx context.Context, op UpdateOperation) (engine.ResourceChanges, error)
 	// Destroy this stack's resources.
 	Destroy(ctx context.Context, op UpdateOperation) (engine.ResourceChanges, error)
+	// Rollback this stack's resources to the deployment produced by the given version of the stack.
+	Rollback(ctx context.Context, op UpdateOperation, version int) (engine.ResourceChanges, error)
 
 	// remove this stack.
 	Remove(ctx context.Context, force bool) (bool, error)
@@ -83,6 +85,11 @@ func DestroyStack(ctx context.Context, s Stack, op UpdateOperation) (engine.Reso
 	return s.Backend().Destroy(ctx, s.Ref(), op)
 }
 
+// RollbackStack converges the stack's resources back to the deployment produced by the given version of the stack.
+func RollbackStack(ctx context.Context, s Stack, op UpdateOperation, version int) (engine.ResourceChanges, error) {
+	return s.Backend().Rollback(ctx, s.Ref(), op, version)
+}
+
 // GetStackCrypter fetches the encrypter/decrypter for a stack.
 func GetStackCrypter(s Stack) (config.Crypter, error) {
 	return s.Backend().GetStackCrypter(s.Ref())
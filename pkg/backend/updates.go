@@ -15,9 +15,14 @@
 package backend
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
 // UpdateMetadata describes optional metadata about an update.
@@ -102,8 +107,31 @@ type UpdateInfo struct {
 	// Config used for the update.
 	Config config.Map `json:"config"`
 
+	// ConfigDigest is a digest of the config used for the update, which can be used to tell at a glance whether two
+	// updates used the same configuration.
+	ConfigDigest string `json:"configDigest,omitempty"`
+
+	// User is the identity of the user that performed the update, if known.
+	User string `json:"user,omitempty"`
+
+	// AuditLogDigest is the digest of the last entry in the update's audit log, if the update made any changes. It
+	// anchors the log's hash chain, so that a log that was later truncated or rewritten no longer verifies.
+	AuditLogDigest string `json:"auditLogDigest,omitempty"`
+
 	// Information obtained from an update completing.
 	Result          UpdateResult           `json:"result"`
 	EndTime         int64                  `json:"endTime"`
 	ResourceChanges engine.ResourceChanges `json:"resourceChanges,omitempty"`
+
+	// Version is the sequence number of the update within the stack's history, starting at 1. The deployment that an
+	// update produced can be fetched by passing this version to Backend.ExportDeploymentVersion.
+	Version int `json:"version,omitempty"`
+}
+
+// ConfigDigest computes a digest of the given config. Secret values contribute their ciphertext to the digest.
+func ConfigDigest(cfg config.Map) string {
+	b, err := json.Marshal(cfg)
+	contract.AssertNoError(err)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
 }
@@ -0,0 +1,42 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestConfigDigest(t *testing.T) {
+	a := config.Map{
+		config.MustMakeKey("proj", "a"): config.NewValue("1"),
+		config.MustMakeKey("proj", "b"): config.NewSecureValue("c2VjcmV0"),
+	}
+	b := config.Map{
+		config.MustMakeKey("proj", "b"): config.NewSecureValue("c2VjcmV0"),
+		config.MustMakeKey("proj", "a"): config.NewValue("1"),
+	}
+	c := config.Map{
+		config.MustMakeKey("proj", "a"): config.NewValue("2"),
+		config.MustMakeKey("proj", "b"): config.NewSecureValue("c2VjcmV0"),
+	}
+
+	assert.Equal(t, ConfigDigest(a), ConfigDigest(b))
+	assert.NotEqual(t, ConfigDigest(a), ConfigDigest(c))
+	assert.Len(t, ConfigDigest(nil), 16)
+}
@@ -52,3 +52,96 @@ func GetAnalyzeResourceFailureError(urn resource.URN) *Diag {
 func GetPreviewFailedError(urn resource.URN) *Diag {
 	return newError(urn, 2005, "Preview failed: %v")
 }
+
+func GetProtectedResourceDeleteError(urn resource.URN) *Diag {
+	return newError(urn, 2006,
+		"Refusing to delete protected resource '%v'; unprotect it first or pass --force-unprotect")
+}
+
+func GetUntargetedCreateError(urn resource.URN) *Diag {
+	return newError(urn, 2007, "Resource '%v' does not exist and is not targeted by this update")
+}
+
+func GetUntargetedDependentError(urn resource.URN) *Diag {
+	return newError(urn, 2008,
+		"Cannot delete resource '%v' because it is depended upon by untargeted resource '%v'")
+}
+
+func GetProviderRetryWarning(urn resource.URN) *Diag {
+	return newError(urn, 2009, "%v of resource '%v' failed with a transient error: %v; retrying in %v (attempt %v of %v)")
+}
+
+func GetResourceInvalidPropertiesError(urn resource.URN) *Diag {
+	return newError(urn, 2010, "%v resource '%v' has %v problems:%v")
+}
+
+func GetDuplicateResourceAliasError(urn resource.URN) *Diag {
+	return newError(urn, 2011,
+		"Duplicate resource alias '%v' applied to resource with URN '%v' conflicting with resource with URN '%v'")
+}
+
+func GetResourceParentNotFoundError(urn resource.URN) *Diag {
+	return newError(urn, 2012, "Resource '%v' refers to parent '%v', which has not been registered")
+}
+
+func GetPolicyViolationError(urn resource.URN) *Diag {
+	return newError(urn, 2013,
+		"Policy '%v' from policy pack '%v' reported a %v violation:\n"+
+			"\tResource: %v\n"+
+			"\tProperty: %v\n"+
+			"\tReason: %v")
+}
+
+func GetResourceAwaitingReadinessMessage(urn resource.URN) *Diag {
+	return newError(urn, 2014, "Waiting for resource '%v' to become ready: %v not yet satisfied (%v elapsed)")
+}
+
+func GetResourceHookFailedError(urn resource.URN) *Diag {
+	return newError(urn, 2015, "Advisory %v hook '%v' failed: %v")
+}
+
+func GetAuditLogError(urn resource.URN) *Diag {
+	return newError(urn, 2016, "Could not record the %v of '%v' in the audit log: %v")
+}
+
+func GetMalformedCollectionIndexError(urn resource.URN) *Diag {
+	return newError(urn, 2017,
+		"Resource '%v' has a malformed collection index; indices must be non-negative integers without leading zeros")
+}
+
+func GetProviderRateLimitedMessage(urn resource.URN) *Diag {
+	return newError(urn, 2018, "%v of resource '%v' is waiting %v for the %v rate limit (%v operations queued)")
+}
+
+func GetGuardedResourceDeleteError(urn resource.URN) *Diag {
+	return newError(urn, 2019, "Resource '%v' is guarded by guardrail '%v' and cannot be deleted or replaced; "+
+		"to allow it, pass --allow-destroy=%v")
+}
+
+func GetGuardedResourceDeleteWarning(urn resource.URN) *Diag {
+	return newError(urn, 2020, "Resource '%v' would be deleted or replaced, but is guarded by guardrail '%v'; "+
+		"the update must be confirmed or pass --allow-destroy=%v")
+}
+
+func GetResourceAlreadyDeletedWarning(urn resource.URN) *Diag {
+	return newError(urn, 2021, "Resource '%v' was not found by its provider while it was being deleted; "+
+		"assuming that it has already been deleted: %v")
+}
+
+// Property marshaling errors are in the [2100,2200) range.
+
+func GetUnexpectedUnknownValueError(urn resource.URN) *Diag {
+	return newError(urn, 2100, "unexpected unknown property value")
+}
+
+func GetUnsupportedSecretError(urn resource.URN) *Diag {
+	return newError(urn, 2101, "this version of the Pulumi SDK does not support first-class secrets")
+}
+
+func GetUnrecognizedSignatureError(urn resource.URN) *Diag {
+	return newError(urn, 2102, "unrecognized signature '%v' in property map")
+}
+
+func GetAssetHashError(urn resource.URN) *Diag {
+	return newError(urn, 2103, "failed to compute %v hash: %v")
+}
@@ -0,0 +1,209 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// StructuredError is a diagnostic error.  In addition to a message, it carries the error's code, the resource and
+// property it concerns (if any), and a hint describing how to fix it, so that displays can group and render errors
+// in a more helpful way than a flat string allows.
+type StructuredError struct {
+	ID      ID           // the error's code.
+	URN     resource.URN // the resource this error concerns; empty if it does not concern a particular resource.
+	Path    string       // the path of the property this error concerns (e.g. `tags.name` or `rules[0].port`).
+	Message string       // a human-friendly message describing the error.
+	Hint    string       // an optional human-friendly suggestion for how to fix the error.
+}
+
+// NewStructuredError creates a structured error from the given diagnostic, formatting its message with the given
+// arguments.
+func NewStructuredError(d *Diag, args ...interface{}) *StructuredError {
+	msg := d.Message
+	if !d.Raw {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	return &StructuredError{ID: d.ID, URN: d.URN, Message: msg}
+}
+
+// WithHint returns a copy of this error with the given remediation hint.
+func (e *StructuredError) WithHint(hint string) *StructuredError {
+	c := *e
+	c.Hint = hint
+	return &c
+}
+
+// WithPath returns a copy of this error with the given property path.
+func (e *StructuredError) WithPath(path string) *StructuredError {
+	c := *e
+	c.Path = path
+	return &c
+}
+
+// WithURN returns a copy of this error associated with the given resource.
+func (e *StructuredError) WithURN(urn resource.URN) *StructuredError {
+	c := *e
+	c.URN = urn
+	return &c
+}
+
+// Error returns the error's message, prefixed by its property path if it has one.
+func (e *StructuredError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("property '%s': %s", e.Path, e.Message)
+}
+
+// String returns a full description of the error, including its code and hint.
+func (e *StructuredError) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "error PUL%d: %s", e.ID, e.Error())
+	if e.Hint != "" {
+		fmt.Fprintf(&b, " (hint: %s)", e.Hint)
+	}
+	return b.String()
+}
+
+// JoinPath prepends the given property key to a property path.
+func JoinPath(key string, path string) string {
+	switch {
+	case path == "":
+		return key
+	case strings.HasPrefix(path, "["):
+		return key + path
+	default:
+		return key + "." + path
+	}
+}
+
+// IndexPath prepends the given array index to a property path.
+func IndexPath(index int, path string) string {
+	return JoinPath("["+strconv.Itoa(index)+"]", path)
+}
+
+// PrefixPath prepends the given property key to the paths of any structured errors in err.  Other errors are
+// returned unchanged.  This lets code that walks nested properties report the full path to the property at fault.
+func PrefixPath(err error, key string) error {
+	return mapErrors(err, func(e *StructuredError) *StructuredError {
+		return e.WithPath(JoinPath(key, e.Path))
+	})
+}
+
+// PrefixIndex prepends the given array index to the paths of any structured errors in err.  Other errors are
+// returned unchanged.
+func PrefixIndex(err error, index int) error {
+	return mapErrors(err, func(e *StructuredError) *StructuredError {
+		return e.WithPath(IndexPath(index, e.Path))
+	})
+}
+
+// AttachURN associates any structured errors in err that do not yet concern a resource with the given resource.
+// Other errors are returned unchanged.
+func AttachURN(err error, urn resource.URN) error {
+	return mapErrors(err, func(e *StructuredError) *StructuredError {
+		if e.URN != "" {
+			return e
+		}
+		return e.WithURN(urn)
+	})
+}
+
+func mapErrors(err error, f func(e *StructuredError) *StructuredError) error {
+	switch err := err.(type) {
+	case *StructuredError:
+		return f(err)
+	case StructuredErrors:
+		result := make(StructuredErrors, len(err))
+		for i, e := range err {
+			result[i] = f(e)
+		}
+		return result
+	default:
+		return err
+	}
+}
+
+// StructuredErrors is an aggregate of structured diagnostic errors.
+type StructuredErrors []*StructuredError
+
+// Append adds the given error to this aggregate.  Aggregates are flattened, and errors that are not structured are
+// converted into structured errors with no code.
+func (es StructuredErrors) Append(err error) StructuredErrors {
+	switch err := err.(type) {
+	case nil:
+		return es
+	case *StructuredError:
+		return append(es, err)
+	case StructuredErrors:
+		return append(es, err...)
+	default:
+		return append(es, &StructuredError{Message: err.Error()})
+	}
+}
+
+// ErrorOrNil returns nil if this aggregate is empty, its only error if it has exactly one, and itself otherwise.
+func (es StructuredErrors) ErrorOrNil() error {
+	switch len(es) {
+	case 0:
+		return nil
+	case 1:
+		return es[0]
+	default:
+		return es
+	}
+}
+
+// Error returns the messages of the errors in this aggregate, one per line.
+func (es StructuredErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("%d errors occurred:\n\t* %s", len(es), strings.Join(msgs, "\n\t* "))
+}
+
+// Details renders the errors in this aggregate as a bulleted list, one error (and its hint, if any) per item.  Each
+// item begins with a newline, so the result may be appended directly to a summary line.
+func (es StructuredErrors) Details() string {
+	var b bytes.Buffer
+	for _, e := range es {
+		fmt.Fprintf(&b, "\n\t- %s", e.Error())
+		if e.Hint != "" {
+			fmt.Fprintf(&b, "\n\t  hint: %s", e.Hint)
+		}
+	}
+	return b.String()
+}
+
+// ByResource groups the errors in this aggregate by the resource they concern.  The returned URNs are in the order
+// in which each resource first appears; errors that do not concern a resource are grouped under the empty URN.
+func (es StructuredErrors) ByResource() ([]resource.URN, map[resource.URN]StructuredErrors) {
+	var urns []resource.URN
+	groups := make(map[resource.URN]StructuredErrors)
+	for _, e := range es {
+		if _, has := groups[e.URN]; !has {
+			urns = append(urns, e.URN)
+		}
+		groups[e.URN] = append(groups[e.URN], e)
+	}
+	return urns, groups
+}
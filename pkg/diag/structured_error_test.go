@@ -0,0 +1,79 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestStructuredErrorPaths(t *testing.T) {
+	err := NewStructuredError(GetUnrecognizedSignatureError(""), "foo").WithHint("upgrade")
+	assert.Equal(t, "unrecognized signature 'foo' in property map", err.Error())
+
+	// Paths are built from the innermost property outwards.
+	var wrapped error = err
+	wrapped = PrefixPath(wrapped, "port")
+	wrapped = PrefixIndex(wrapped, 2)
+	wrapped = PrefixPath(wrapped, "rules")
+	serr, ok := wrapped.(*StructuredError)
+	assert.True(t, ok)
+	assert.Equal(t, "rules[2].port", serr.Path)
+	assert.Equal(t, "property 'rules[2].port': unrecognized signature 'foo' in property map", serr.Error())
+	assert.Equal(t, "error PUL2102: property 'rules[2].port': unrecognized signature 'foo' in property map "+
+		"(hint: upgrade)", serr.String())
+
+	// The original error is not modified.
+	assert.Equal(t, "", err.Path)
+
+	// Unstructured errors pass through unchanged.
+	plain := errors.New("plain")
+	assert.Equal(t, plain, PrefixPath(plain, "key"))
+	assert.Equal(t, plain, AttachURN(plain, "urn"))
+}
+
+func TestStructuredErrorsAggregate(t *testing.T) {
+	a := resource.URN("urn:pulumi:stack::proj::typ::a")
+	b := resource.URN("urn:pulumi:stack::proj::typ::b")
+
+	var errs StructuredErrors
+	assert.Nil(t, errs.ErrorOrNil())
+
+	errs = errs.Append(nil)
+	errs = errs.Append(&StructuredError{URN: a, Path: "x", Message: "bad x"})
+	assert.Equal(t, errs[0], errs.ErrorOrNil())
+
+	errs = errs.Append(StructuredErrors{
+		{URN: b, Message: "bad b"},
+		{Path: "y", Message: "bad y", Hint: "fix y"},
+	})
+	errs = errs.Append(errors.New("plain"))
+	assert.Len(t, errs, 4)
+	assert.Equal(t, errs, errs.ErrorOrNil())
+
+	// Resource-less errors can be attached to a resource after the fact.
+	attached := AttachURN(errs, a).(StructuredErrors)
+	urns, groups := attached.ByResource()
+	assert.Equal(t, []resource.URN{a, b}, urns)
+	assert.Len(t, groups[a], 3)
+	assert.Len(t, groups[b], 1)
+
+	assert.Equal(t, "\n\t- property 'x': bad x\n\t- bad b\n\t- property 'y': bad y\n\t  hint: fix y\n\t- plain",
+		errs.Details())
+}
@@ -165,12 +165,21 @@ func GetResourcePropertiesSummary(step StepEventMetadata, indent int) string {
 			contract.Assert(err == nil)
 
 			// Elide references to default providers.
-			if prov.URN().Name() != "default" {
+			if !providers.IsDefaultProvider(prov.URN()) {
 				writeWithIndentNoPrefix(&b, indent+1, simplePropOp, "[provider=%s]\n", step.Provider)
 			}
 		}
 	}
 
+	// For replacements, print the properties whose changes caused the replacement.
+	if (op == deploy.OpReplace || op == deploy.OpCreateReplacement) && len(step.Keys) > 0 {
+		keys := make([]string, len(step.Keys))
+		for i, k := range step.Keys {
+			keys[i] = string(k)
+		}
+		writeWithIndentNoPrefix(&b, indent+1, deploy.OpReplace, "[replace: %s]\n", strings.Join(keys, ", "))
+	}
+
 	return b.String()
 }
 
@@ -325,6 +334,9 @@ func shouldPrintPropertyValue(v resource.PropertyValue, outs bool) bool {
 	if v.IsString() && v.StringValue() == "" {
 		return false // don't print empty strings either.
 	}
+	if v.IsJSON() && v.JSONValue().Raw == "" {
+		return false // nor empty JSON values.
+	}
 	if v.IsArray() && len(v.ArrayValue()) == 0 {
 		return false // skip empty arrays, since they are often uninteresting default values.
 	}
@@ -349,7 +361,17 @@ func printPropertyValue(
 	b *bytes.Buffer, v resource.PropertyValue, planning bool,
 	indent int, op deploy.StepOp, prefix bool, debug bool) {
 
-	if isPrimitive(v) {
+	if v.IsJSON() {
+		// Print JSON values as the documents they hold, so that they are as readable as any other value.
+		if doc, err := v.JSONValue().Document(); err == nil {
+			printPropertyValue(b, doc, planning, indent, op, prefix, debug)
+			return
+		}
+		write(b, op, "%q", v.JSONValue().Raw)
+	} else if v.IsFileRef() {
+		ref := v.FileRefValue()
+		write(b, op, "file(%s) { %s }", shortHash(ref.Hash), ref.Path)
+	} else if isPrimitive(v) {
 		printPrimitivePropertyValue(b, v, planning, op)
 	} else if v.IsArray() {
 		arr := v.ArrayValue()
@@ -564,7 +586,7 @@ func printPropertyValueDiff(
 
 func isPrimitive(value resource.PropertyValue) bool {
 	return value.IsNull() || value.IsString() || value.IsNumber() ||
-		value.IsBool() || value.IsComputed() || value.IsOutput()
+		value.IsBool() || value.IsComputed() || value.IsOutput() || value.IsSecret()
 }
 
 func printPrimitivePropertyValue(b *bytes.Buffer, v resource.PropertyValue, planning bool, op deploy.StepOp) {
@@ -578,6 +600,9 @@ func printPrimitivePropertyValue(b *bytes.Buffer, v resource.PropertyValue, plan
 		write(b, op, "%v", v.NumberValue())
 	} else if v.IsString() {
 		write(b, op, "%q", v.StringValue())
+	} else if v.IsSecret() {
+		// Secrets are never displayed.
+		writeVerbatim(b, op, "[secret]")
 	} else if v.IsComputed() || v.IsOutput() {
 		// We render computed and output values differently depending on whether or not we are
 		// planning or deploying: in the former case, we display `computed<type>` or `output<type>`;
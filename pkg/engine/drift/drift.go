@@ -0,0 +1,126 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drift detects resources whose live state has drifted from the state recorded in a stack's checkpoint. A
+// drift check is a preview of a refresh: each resource is read from its provider and its outputs are compared against
+// those in the checkpoint, without saving anything. A Monitor runs checks on a schedule and notifies webhooks or Slack
+// when the drift it finds changes.
+package drift
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// ResourceDrift describes how a single resource has drifted from its checkpointed state.
+type ResourceDrift struct {
+	URN     resource.URN `json:"urn"`               // the resource's URN.
+	Type    tokens.Type  `json:"type"`              // the resource's type.
+	Deleted bool         `json:"deleted,omitempty"` // true if the resource no longer exists.
+	Paths   []string     `json:"paths,omitempty"`   // the paths of the output properties that have changed.
+}
+
+// Report is the result of a single drift check.
+type Report struct {
+	Stack   tokens.QName    `json:"stack"`             // the stack that was checked.
+	Time    time.Time       `json:"time"`              // the time at which the check started.
+	Drifted []ResourceDrift `json:"drifted,omitempty"` // the resources that have drifted, sorted by URN.
+}
+
+// HasDrift returns true if any resources have drifted.
+func (r Report) HasDrift() bool {
+	return len(r.Drifted) > 0
+}
+
+// Detect checks the stack described by the given deployment for drift. The deployment's operation and dry-run flag
+// are overridden so that the check never modifies the stack; any subscriber in d.Events still receives the refresh's
+// events.
+func Detect(ctx context.Context, d engine.Deployment) (Report, error) {
+	if d.Update == nil {
+		return Report{}, errors.New("a drift check requires update information")
+	}
+
+	rec := &recorder{next: d.Events}
+	d.Operation, d.DryRun, d.Events = engine.Refresh, true, rec
+
+	report := Report{Stack: d.Update.GetTarget().Name, Time: time.Now()}
+	if _, err := engine.Run(ctx, d); err != nil {
+		return report, errors.Wrap(err, "refreshing stack")
+	}
+
+	sort.Slice(rec.drifted, func(i, j int) bool { return rec.drifted[i].URN < rec.drifted[j].URN })
+	report.Drifted = rec.drifted
+	return report, nil
+}
+
+// recorder is an engine.EventSubscriber that records the drift reported by a refresh's steps and forwards every
+// event to the next subscriber, if any. The engine dispatches events one at a time, so it needs no locking.
+type recorder struct {
+	next    engine.EventSubscriber
+	drifted []ResourceDrift
+}
+
+func (r *recorder) OnPreStep(payload engine.ResourcePreEventPayload) {
+	if r.next != nil {
+		r.next.OnPreStep(payload)
+	}
+}
+
+func (r *recorder) OnPostStep(payload engine.ResourceOutputsEventPayload) {
+	md := payload.Metadata
+	switch md.Op {
+	case deploy.OpDelete:
+		r.drifted = append(r.drifted, ResourceDrift{URN: md.URN, Type: md.Type, Deleted: true})
+	case deploy.OpUpdate:
+		var paths []string
+		if md.Old != nil && md.New != nil {
+			if diff := md.Old.Outputs.Diff(md.New.Outputs); diff != nil {
+				for _, path := range diff.ChangedPaths() {
+					paths = append(paths, path.String())
+				}
+			}
+		}
+		r.drifted = append(r.drifted, ResourceDrift{URN: md.URN, Type: md.Type, Paths: paths})
+	}
+
+	if r.next != nil {
+		r.next.OnPostStep(payload)
+	}
+}
+
+func (r *recorder) OnStepFailed(payload engine.ResourceOperationFailedPayload) {
+	if r.next != nil {
+		r.next.OnStepFailed(payload)
+	}
+}
+
+func (r *recorder) OnDiagnostic(payload engine.DiagEventPayload) {
+	if r.next != nil {
+		r.next.OnDiagnostic(payload)
+	}
+}
+
+func (r *recorder) OnSummary(payload engine.SummaryEventPayload) {
+	if r.next != nil {
+		r.next.OnSummary(payload)
+	}
+}
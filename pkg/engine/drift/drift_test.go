@@ -0,0 +1,158 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/engine/enginetest"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// driftTest is a stack of three resources, resA, resB, and resC, whose live state can be changed out from under it.
+type driftTest struct {
+	stack *enginetest.Stack
+	opts  engine.UpdateOptions
+	live  map[string]resource.PropertyMap // the live outputs of each resource, by name; nil if it has been deleted.
+}
+
+func newDriftTest(t *testing.T) *driftTest {
+	dt := &driftTest{stack: enginetest.NewStack("test", "test"), live: make(map[string]resource.PropertyMap)}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					dt.live[string(urn.Name())] = inputs.Copy()
+					return resource.ID(urn.Name()), inputs, resource.StatusOK, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					return dt.live[string(urn.Name())], resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB", "resC"} {
+			inputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+				"size": 1,
+				"tags": map[string]interface{}{"owner": "alice"},
+			})
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, "", false, nil, "", inputs, nil,
+				false, false, nil, nil, nil, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	dt.opts = engine.UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
+
+	_, err := dt.stack.Update(dt.opts)
+	assert.NoError(t, err)
+	return dt
+}
+
+// deployment returns a deployment that targets the stack's latest checkpoint.
+func (dt *driftTest) deployment() (engine.Deployment, error) {
+	snap, err := dt.stack.Snapshot()
+	if err != nil {
+		return engine.Deployment{}, err
+	}
+	target := &deploy.Target{Name: dt.stack.Name, Config: dt.stack.Config, Snapshot: snap}
+	return engine.Deployment{
+		Update:  engine.NewUpdateInfo("", &dt.stack.Project, target),
+		Options: dt.opts,
+	}, nil
+}
+
+func TestDetect(t *testing.T) {
+	dt := newDriftTest(t)
+	checkpoints := len(dt.stack.Checkpoints)
+
+	d, err := dt.deployment()
+	assert.NoError(t, err)
+	report, err := Detect(context.Background(), d)
+	assert.NoError(t, err)
+	assert.Equal(t, tokens.QName("test"), report.Stack)
+	assert.False(t, report.HasDrift())
+
+	dt.live["resA"]["tags"] = resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"owner": "bob",
+	}))
+	dt.live["resB"] = nil
+
+	d, err = dt.deployment()
+	assert.NoError(t, err)
+	report, err = Detect(context.Background(), d)
+	assert.NoError(t, err)
+	assert.Equal(t, []ResourceDrift{
+		{URN: dt.stack.NewURN("pkgA:m:typA", "resA", ""), Type: "pkgA:m:typA", Paths: []string{"tags.owner"}},
+		{URN: dt.stack.NewURN("pkgA:m:typA", "resB", ""), Type: "pkgA:m:typA", Deleted: true},
+	}, report.Drifted)
+
+	// Drift checks never save checkpoints.
+	assert.Equal(t, checkpoints, len(dt.stack.Checkpoints))
+}
+
+type recordingNotifier struct {
+	reports []Report
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, report Report) error {
+	n.reports = append(n.reports, report)
+	return nil
+}
+
+func TestMonitorNotifiesOnChange(t *testing.T) {
+	dt := newDriftTest(t)
+	n := &recordingNotifier{}
+	m := &Monitor{
+		Deployment: dt.deployment,
+		Notifiers:  []Notifier{n},
+		OnError:    func(err error) { assert.NoError(t, err) },
+	}
+	ctx := context.Background()
+
+	// No drift, no notification.
+	last := m.check(ctx, Report{})
+	assert.Empty(t, n.reports)
+
+	// New drift is reported once, for as long as it stays the same.
+	dt.live["resC"]["size"] = resource.NewNumberProperty(2)
+	last = m.check(ctx, last)
+	last = m.check(ctx, last)
+	assert.Equal(t, 1, len(n.reports))
+	assert.Equal(t, []string{"size"}, n.reports[0].Drifted[0].Paths)
+
+	// Further drift is reported again.
+	dt.live["resA"] = nil
+	m.check(ctx, last)
+	assert.Equal(t, 2, len(n.reports))
+	assert.Equal(t, 2, len(n.reports[1].Drifted))
+}
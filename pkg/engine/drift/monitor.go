@@ -0,0 +1,94 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+)
+
+// Monitor checks a stack for drift on a schedule.
+type Monitor struct {
+	// Interval is the time between the start of one check and the start of the next. It must be positive.
+	Interval time.Duration
+	// Deployment returns the deployment to check. It is called before every check, so that each check starts from
+	// the stack's latest checkpoint.
+	Deployment func() (engine.Deployment, error)
+	// Notifiers are notified whenever a check finds drift that differs from the drift found by the last successful
+	// check. Drift that persists unchanged is reported only once.
+	Notifiers []Notifier
+	// OnError, if non-nil, is called with the errors from failed checks and notifications. These do not stop the
+	// monitor.
+	OnError func(err error)
+}
+
+// Run checks for drift immediately and then once per interval, until ctx is canceled.
+func (m *Monitor) Run(ctx context.Context) error {
+	if m.Interval <= 0 {
+		return errors.New("the drift check interval must be positive")
+	}
+	if m.Deployment == nil {
+		return errors.New("a drift monitor requires a deployment")
+	}
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	var last Report
+	for {
+		last = m.check(ctx, last)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// check runs a single drift check and sends any notifications it calls for. It returns the report to compare the
+// next check against: the new report if the check succeeded, and last otherwise.
+func (m *Monitor) check(ctx context.Context, last Report) Report {
+	d, err := m.Deployment()
+	if err != nil {
+		m.error(errors.Wrap(err, "preparing drift check"))
+		return last
+	}
+	report, err := Detect(ctx, d)
+	if err != nil {
+		m.error(err)
+		return last
+	}
+
+	if report.HasDrift() && !reflect.DeepEqual(report.Drifted, last.Drifted) {
+		for _, n := range m.Notifiers {
+			if err := n.Notify(ctx, report); err != nil {
+				m.error(err)
+			}
+		}
+	}
+	return report
+}
+
+func (m *Monitor) error(err error) {
+	if m.OnError != nil {
+		m.OnError(err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/engine/notify"
+)
+
+// Notifier is notified of the drift found by a Monitor.
+type Notifier interface {
+	// Notify reports the drift described by the given report, which always has drift.
+	Notify(ctx context.Context, report Report) error
+}
+
+// WebhookNotifier notifies a webhook by POSTing the report to it as JSON.
+type WebhookNotifier struct {
+	URL    string       // the URL to POST reports to.
+	Client *http.Client // the client to POST with; if nil, http.DefaultClient is used.
+}
+
+// Notify POSTs the report to the webhook.
+func (n *WebhookNotifier) Notify(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "marshaling drift report")
+	}
+	return notify.PostJSON(ctx, n.Client, n.URL, body)
+}
+
+// SlackNotifier notifies a Slack channel through an incoming webhook, listing each drifted resource along with the
+// paths of its drifted properties.
+type SlackNotifier struct {
+	WebhookURL string       // the Slack incoming webhook URL.
+	Client     *http.Client // the client to POST with; if nil, http.DefaultClient is used.
+}
+
+// Notify posts a message describing the report to Slack.
+func (n *SlackNotifier) Notify(ctx context.Context, report Report) error {
+	return notify.PostSlack(ctx, n.Client, n.WebhookURL, FormatReport(report))
+}
+
+// FormatReport renders a report as a short message in Slack's markdown dialect.
+func FormatReport(report Report) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Drift detected in stack `%s`:", report.Stack)
+	for _, r := range report.Drifted {
+		switch {
+		case r.Deleted:
+			fmt.Fprintf(&buf, "\n• `%s` has been deleted", r.URN)
+		case len(r.Paths) == 0:
+			fmt.Fprintf(&buf, "\n• `%s` has changed", r.URN)
+		default:
+			fmt.Fprintf(&buf, "\n• `%s` has changed: `%s`", r.URN, strings.Join(r.Paths, "`, `"))
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,77 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine/enginetest"
+)
+
+func testReport() Report {
+	return Report{
+		Stack: "dev",
+		Drifted: []ResourceDrift{
+			{URN: "urn:pulumi:dev::proj::pkgA:m:typA::resA", Type: "pkgA:m:typA", Paths: []string{"size", "tags.owner"}},
+			{URN: "urn:pulumi:dev::proj::pkgA:m:typA::resB", Type: "pkgA:m:typA", Deleted: true},
+		},
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var bodies [][]byte
+	server := enginetest.RecordJSONPosts(t, http.StatusOK, &bodies)
+	defer server.Close()
+
+	err := (&WebhookNotifier{URL: server.URL}).Notify(context.Background(), testReport())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bodies))
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(bodies[0], &report))
+	assert.Equal(t, testReport().Drifted, report.Drifted)
+}
+
+func TestWebhookNotifierFailure(t *testing.T) {
+	var bodies [][]byte
+	server := enginetest.RecordJSONPosts(t, http.StatusInternalServerError, &bodies)
+	defer server.Close()
+
+	err := (&WebhookNotifier{URL: server.URL}).Notify(context.Background(), testReport())
+	assert.Error(t, err)
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var bodies [][]byte
+	server := enginetest.RecordJSONPosts(t, http.StatusOK, &bodies)
+	defer server.Close()
+
+	err := (&SlackNotifier{WebhookURL: server.URL}).Notify(context.Background(), testReport())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bodies))
+
+	var message struct {
+		Text string `json:"text"`
+	}
+	assert.NoError(t, json.Unmarshal(bodies[0], &message))
+	assert.Equal(t, "Drift detected in stack `dev`:\n"+
+		"• `urn:pulumi:dev::proj::pkgA:m:typA::resA` has changed: `size`, `tags.owner`\n"+
+		"• `urn:pulumi:dev::proj::pkgA:m:typA::resB` has been deleted", message.Text)
+}
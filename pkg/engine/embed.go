@@ -0,0 +1,125 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/util/cancel"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// Operation is an engine operation that can be run by Run: Update, Refresh, or Destroy.
+type Operation func(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (ResourceChanges, error)
+
+// Deployment describes a single engine operation to be run in-process by a Go program that embeds the engine.
+type Deployment struct {
+	// Operation is the operation to perform.  If nil, Update is used.
+	Operation Operation
+	// Update describes the project and stack to operate upon.
+	Update UpdateInfo
+	// Options customizes the operation.  Options.Host may be set to inject a plugin host.
+	Options UpdateOptions
+	// DryRun is true if the operation should only be previewed.
+	DryRun bool
+	// SnapshotManager persists the stack's state as the operation proceeds.  It is required unless DryRun is true.
+	SnapshotManager SnapshotManager
+	// BackendClient resolves references to other stacks.  It may be nil if the program does not use them.
+	BackendClient deploy.BackendClient
+	// Events, if non-nil, receives a typed view of the operation's events.  Any events that are not consumed by a
+	// subscriber are discarded.
+	Events EventSubscriber
+	// ParentSpan is an optional tracing span to parent the operation's spans within.
+	ParentSpan opentracing.SpanContext
+}
+
+// Result is the outcome of an operation performed by Run.
+type Result struct {
+	// Changes counts the resources affected by the operation, by step operation.
+	Changes ResourceChanges
+}
+
+// Run performs the given deployment in-process, returning once the operation has finished.  Canceling ctx requests a
+// graceful cancellation of the operation: in-flight resource operations are allowed to finish, but no new ones are
+// started.  Metrics recorded while the operation runs, such as step counts and provider latencies, can be served to
+// Prometheus by mounting metrics.Handler.
+func Run(ctx context.Context, d Deployment) (Result, error) {
+	if d.Update == nil {
+		return Result{}, errors.New("a deployment requires update information")
+	}
+	if d.SnapshotManager == nil && !d.DryRun {
+		return Result{}, errors.New("a deployment that is not a dry run requires a snapshot manager")
+	}
+	op := d.Operation
+	if op == nil {
+		op = Update
+	}
+
+	// Translate cancellation of the caller's context into a cancellation request for the engine.
+	cancelCtx, cancelSrc := cancel.NewContext(context.Background())
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancelSrc.Cancel()
+		case <-done:
+		}
+	}()
+
+	// The engine blocks on each event it sends, so the events must always be drained, even if nobody is listening.
+	events := make(chan Event)
+	eventsDone := make(chan bool)
+	go func() {
+		if d.Events != nil {
+			DispatchEvents(events, d.Events)
+		}
+		for range events {
+		}
+		close(eventsDone)
+	}()
+
+	changes, err := op(d.Update, &Context{
+		Cancel:          cancelCtx,
+		Events:          events,
+		SnapshotManager: d.SnapshotManager,
+		BackendClient:   d.BackendClient,
+		ParentSpan:      d.ParentSpan,
+	}, d.Options, d.DryRun)
+
+	close(events)
+	<-eventsDone
+
+	return Result{Changes: changes}, err
+}
+
+// NewUpdateInfo returns an UpdateInfo for the given root directory, project, and deployment target.
+func NewUpdateInfo(root string, project *workspace.Project, target *deploy.Target) UpdateInfo {
+	return &staticUpdateInfo{root: root, project: project, target: target}
+}
+
+type staticUpdateInfo struct {
+	root    string
+	project *workspace.Project
+	target  *deploy.Target
+}
+
+func (u *staticUpdateInfo) GetRoot() string                { return u.root }
+func (u *staticUpdateInfo) GetProject() *workspace.Project { return u.project }
+func (u *staticUpdateInfo) GetTarget() *deploy.Target      { return u.target }
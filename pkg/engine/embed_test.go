@@ -0,0 +1,84 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func TestEmbeddedRun(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+
+	p := &TestPlan{}
+	project, target := p.GetProject(), p.GetTarget(nil)
+	opts := UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
+
+	// A deployment that is not a dry run must have somewhere to put its state.
+	_, err := Run(context.Background(), Deployment{
+		Update:  NewUpdateInfo("", &project, &target),
+		Options: opts,
+	})
+	assert.Error(t, err)
+
+	// A preview does not need a snapshot manager, and its events are delivered to the subscriber.
+	sub := &recordingSubscriber{}
+	res, err := Run(context.Background(), Deployment{
+		Update:  NewUpdateInfo("", &project, &target),
+		Options: opts,
+		DryRun:  true,
+		Events:  sub,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Changes[deploy.OpCreate])
+	assert.Contains(t, sub.calls, "pre:create")
+	assert.Contains(t, sub.calls, "summary")
+
+	// An update records its state in the snapshot manager.
+	journal := newJournal()
+	res, err = Run(context.Background(), Deployment{
+		Operation:       Update,
+		Update:          NewUpdateInfo("", &project, &target),
+		Options:         opts,
+		SnapshotManager: journal,
+	})
+	contract.IgnoreClose(journal)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Changes[deploy.OpCreate])
+
+	snap := journal.Snap(target.Snapshot)
+	assert.NoError(t, snap.VerifyIntegrity())
+	assert.Len(t, snap.Resources, 2)
+}
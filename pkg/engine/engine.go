@@ -36,11 +36,13 @@ type UpdateInfo interface {
 }
 
 // Context provides cancellation, termination, and eventing options for an engine operation. It also provides
-// a way for the engine to persist snapshots, using the `SnapshotManager`.
+// a way for the engine to persist snapshots, using the `SnapshotManager`, and, if `AuditLog` is set, to record each
+// mutation made by a resource provider.
 type Context struct {
 	Cancel          *cancel.Context
 	Events          chan<- Event
 	SnapshotManager SnapshotManager
 	BackendClient   deploy.BackendClient
 	ParentSpan      opentracing.SpanContext
+	AuditLog        deploy.AuditLog
 }
@@ -0,0 +1,64 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+// AcceptGoldenEnvVar is the environment variable that, when set to a non-empty value, causes AssertGolden to accept
+// the actual output as the new contents of golden files rather than comparing against them.
+const AcceptGoldenEnvVar = "PULUMI_ACCEPT"
+
+// AssertGolden asserts that the given output matches the contents of the golden file at the given path. If
+// AcceptGoldenEnvVar is set, the golden file is written with the given output instead.
+func AssertGolden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+
+	if os.Getenv(AcceptGoldenEnvVar) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating directory for golden file %v: %v", path, err)
+		}
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("writing golden file %v: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file %v (set %v=1 to create it): %v", path, AcceptGoldenEnvVar, err)
+	}
+	assert.Equal(t, string(expected), string(actual),
+		"output does not match golden file %v; set %v=1 to accept the changes", path, AcceptGoldenEnvVar)
+}
+
+// AssertGoldenPlan asserts that the stable text of the given plan matches the golden file at the given path.
+func AssertGoldenPlan(t *testing.T, path string, plan deploy.SerializedPlan) {
+	t.Helper()
+
+	text, err := plan.StableText()
+	if err != nil {
+		t.Fatalf("rendering plan: %v", err)
+	}
+	AssertGolden(t, path, text)
+}
@@ -0,0 +1,207 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package enginetest provides an in-memory stack for running engine operations hermetically. Together with the mock
+// providers, programs, and plugin hosts in the deploytest package, it lets the engine's behaviors, such as
+// replacements, partial failures, and parallelism, be tested without a language runtime, plugins, or a backend. It is
+// intended for the test suites of the engine, of resource providers, and of programs that embed the engine.
+package enginetest
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cancel"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// Stack is a stack whose checkpoints are kept in memory. Like those of a real backend, the checkpoints are stored in
+// their serialized form, so every operation also exercises checkpoint serialization.
+type Stack struct {
+	Project       workspace.Project    // the stack's project.
+	Name          tokens.QName         // the stack's name.
+	Config        config.Map           // the stack's configuration.
+	Decrypter     config.Decrypter     // an optional decrypter for the stack's secret configuration.
+	BackendClient deploy.BackendClient // an optional client for resolving references to other stacks.
+
+	// Checkpoints holds every checkpoint saved by the stack's operations, oldest first. The engine saves a checkpoint
+	// after each step, so these record the progress of each operation as well as its result.
+	Checkpoints []*apitype.DeploymentV3
+}
+
+// NewStack creates a new stack with no checkpoints for a project that uses the deploytest language runtime.
+func NewStack(project tokens.PackageName, name tokens.QName) *Stack {
+	return &Stack{
+		Project: workspace.Project{Name: project, Runtime: workspace.NewProjectRuntimeInfo("test", nil)},
+		Name:    name,
+		Config:  config.Map{},
+	}
+}
+
+// NewURN returns the URN of a resource in this stack.
+func (s *Stack) NewURN(t tokens.Type, name string, parent resource.URN) resource.URN {
+	var pt tokens.Type
+	if parent != "" {
+		pt = parent.Type()
+	}
+	return resource.NewURN(s.Name, s.Project.Name, pt, t, tokens.QName(name))
+}
+
+// Snapshot returns the stack's latest checkpoint, or nil if it has none.
+func (s *Stack) Snapshot() (*deploy.Snapshot, error) {
+	if len(s.Checkpoints) == 0 {
+		return nil, nil
+	}
+	return stack.DeserializeDeploymentV3(*s.Checkpoints[len(s.Checkpoints)-1])
+}
+
+// Result is the result of an engine operation.
+type Result struct {
+	Changes engine.ResourceChanges // a summary of the changes made, or that would be made, by the operation.
+	Events  []engine.Event         // the events that the engine emitted during the operation.
+}
+
+// Plan returns the plan for the operation: the steps that the engine issued, in the order in which it issued them.
+func (r Result) Plan() deploy.SerializedPlan {
+	var plan deploy.SerializedPlan
+	for _, e := range r.Events {
+		if e.Type != engine.ResourcePreEvent {
+			continue
+		}
+
+		step := e.Payload.(engine.ResourcePreEventPayload).Metadata
+		var oldInputs, newInputs resource.PropertyMap
+		if step.Old != nil {
+			oldInputs = step.Old.Inputs
+		}
+		if step.New != nil {
+			newInputs = step.New.Inputs
+		}
+		plan.Steps = append(plan.Steps, deploy.NewSerializedStep(step.Op, step.URN, step.Type, step.Provider,
+			oldInputs, newInputs, step.Keys, step.Diffs, step.DetailedDiff))
+	}
+	return plan
+}
+
+// Preview previews an update of the stack.
+func (s *Stack) Preview(opts engine.UpdateOptions) (Result, error) {
+	return s.run(engine.Update, opts, true)
+}
+
+// Update updates the stack.
+func (s *Stack) Update(opts engine.UpdateOptions) (Result, error) {
+	return s.run(engine.Update, opts, false)
+}
+
+// Refresh refreshes the stack's checkpoint from the state of its resources.
+func (s *Stack) Refresh(opts engine.UpdateOptions) (Result, error) {
+	return s.run(engine.Refresh, opts, false)
+}
+
+// Destroy destroys the stack's resources.
+func (s *Stack) Destroy(opts engine.UpdateOptions) (Result, error) {
+	return s.run(engine.Destroy, opts, false)
+}
+
+type operation func(engine.UpdateInfo, *engine.Context, engine.UpdateOptions, bool) (engine.ResourceChanges, error)
+
+func (s *Stack) run(op operation, opts engine.UpdateOptions, dryRun bool) (Result, error) {
+	// Each operation starts from a freshly deserialized snapshot, as it would with a real backend. This matters
+	// because the engine mutates the snapshot it is given.
+	snap, err := s.Snapshot()
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Previews do not save checkpoints.
+	var persister backend.SnapshotPersister = (*stackPersister)(s)
+	if dryRun {
+		persister = discardPersister{}
+	}
+	manager := backend.NewSnapshotManager(persister, snap)
+
+	events := make(chan engine.Event)
+	eventsDone := make(chan []engine.Event)
+	go func() {
+		var all []engine.Event
+		for e := range events {
+			all = append(all, e)
+		}
+		eventsDone <- all
+	}()
+
+	cancelCtx, _ := cancel.NewContext(context.Background())
+	ctx := &engine.Context{
+		Cancel:          cancelCtx,
+		Events:          events,
+		SnapshotManager: manager,
+		BackendClient:   s.BackendClient,
+	}
+	info := &updateInfo{
+		project: s.Project,
+		target: deploy.Target{
+			Name:      s.Name,
+			Config:    s.Config,
+			Decrypter: s.Decrypter,
+			Snapshot:  snap,
+		},
+	}
+
+	changes, err := op(info, ctx, opts, dryRun)
+	close(events)
+	if closeErr := manager.Close(); err == nil {
+		err = closeErr
+	}
+	return Result{Changes: changes, Events: <-eventsDone}, err
+}
+
+// stackPersister saves checkpoints to a stack.
+type stackPersister Stack
+
+func (p *stackPersister) Save(snap *deploy.Snapshot) error {
+	p.Checkpoints = append(p.Checkpoints, stack.SerializeDeployment(snap))
+	return nil
+}
+
+// discardPersister discards checkpoints.
+type discardPersister struct{}
+
+func (discardPersister) Save(snap *deploy.Snapshot) error {
+	return nil
+}
+
+// updateInfo is the engine.UpdateInfo for an operation on a stack.
+type updateInfo struct {
+	project workspace.Project
+	target  deploy.Target
+}
+
+func (u *updateInfo) GetRoot() string {
+	return ""
+}
+
+func (u *updateInfo) GetProject() *workspace.Project {
+	return &u.project
+}
+
+func (u *updateInfo) GetTarget() *deploy.Target {
+	return &u.target
+}
@@ -0,0 +1,139 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func TestStackLifecycle(t *testing.T) {
+	failB := false
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult, error) {
+
+					if !oldInputs["a"].DeepEquals(newInputs["a"]) {
+						return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"a"}}, nil
+					}
+					return plugin.DiffResult{}, nil
+				},
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					if failB && urn.Name() == "resB" {
+						return "", nil, resource.StatusOK, errors.New("oops")
+					}
+					return resource.ID(inputs["a"].StringValue()), inputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	a := "1"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, "", false, nil, "",
+				resource.NewPropertyMapFromMap(map[string]interface{}{"a": name + a}), nil, false, false, nil, nil,
+				nil, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	opts := engine.UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...), Parallel: 4}
+
+	s := NewStack("test", "test")
+	urnA, urnB := s.NewURN("pkgA:m:typA", "resA", ""), s.NewURN("pkgA:m:typA", "resB", "")
+	ids := func() map[resource.URN]resource.ID {
+		snap, err := s.Snapshot()
+		assert.NoError(t, err)
+		result := make(map[resource.URN]resource.ID)
+		for _, res := range snap.Resources {
+			if (res.URN == urnA || res.URN == urnB) && !res.Delete {
+				result[res.URN] = res.ID
+			}
+		}
+		return result
+	}
+
+	// Previews do not save checkpoints.
+	_, err := s.Preview(opts)
+	assert.NoError(t, err)
+	assert.Empty(t, s.Checkpoints)
+
+	result, err := s.Update(opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Events)
+	assert.Equal(t, map[resource.URN]resource.ID{urnA: "resA1", urnB: "resB1"}, ids())
+
+	// If a replacement fails partway through, the checkpoint records the progress that was made.
+	a, failB = "2", true
+	_, err = s.Update(opts)
+	assert.Error(t, err)
+	assert.Equal(t, map[resource.URN]resource.ID{urnA: "resA2", urnB: "resB1"}, ids())
+
+	failB = false
+	_, err = s.Update(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, map[resource.URN]resource.ID{urnA: "resA2", urnB: "resB2"}, ids())
+
+	_, err = s.Destroy(opts)
+	assert.NoError(t, err)
+	assert.Empty(t, ids())
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enginetest")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() { contract.IgnoreError(os.RemoveAll(dir)) }()
+
+	plan := deploy.SerializedPlan{Steps: []deploy.SerializedStep{
+		deploy.NewSerializedStep(deploy.OpCreate, "urn:pulumi:test::test::pkgA:m:typA::resA", "pkgA:m:typA", "",
+			nil, resource.PropertyMap{}, nil, nil, nil),
+	}}
+	path := filepath.Join(dir, "testdata", "plan.json")
+
+	// Accepting writes the golden file, which then matches.
+	accept := os.Getenv(AcceptGoldenEnvVar)
+	defer func() { contract.IgnoreError(os.Setenv(AcceptGoldenEnvVar, accept)) }()
+	assert.NoError(t, os.Setenv(AcceptGoldenEnvVar, "1"))
+	AssertGoldenPlan(t, path, plan)
+
+	assert.NoError(t, os.Setenv(AcceptGoldenEnvVar, ""))
+	AssertGoldenPlan(t, path, plan)
+
+	text, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(text), `"urn": "urn:pulumi:test::test::pkgA:m:typA::resA"`)
+}
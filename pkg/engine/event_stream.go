@@ -0,0 +1,62 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+// EventSubscriber is a typed view of the engine's event stream.  Embedders that want to observe the lifecycle of each
+// step without switching on event types and asserting payloads themselves can implement this interface and hand it to
+// DispatchEvents.  Each method is called synchronously, in the order the engine emitted the corresponding events.
+type EventSubscriber interface {
+	// OnPreStep is called before a step is applied (or previewed).
+	OnPreStep(payload ResourcePreEventPayload)
+	// OnPostStep is called after a step has been applied (or previewed) successfully.
+	OnPostStep(payload ResourceOutputsEventPayload)
+	// OnStepFailed is called after a step has failed.
+	OnStepFailed(payload ResourceOperationFailedPayload)
+	// OnDiagnostic is called for each diagnostic message, including program and provider output.
+	OnDiagnostic(payload DiagEventPayload)
+	// OnSummary is called once the operation has finished, with the number of resources changed.
+	OnSummary(payload SummaryEventPayload)
+}
+
+// DispatchEvents reads events from the given channel and dispatches each to the matching method of the subscriber.
+// It returns once the engine signals that the operation is finished or the channel is closed.  Events that have no
+// corresponding method (for example, the prelude) are ignored.
+func DispatchEvents(events <-chan Event, sub EventSubscriber) {
+	for event := range events {
+		if !DispatchEvent(event, sub) {
+			return
+		}
+	}
+}
+
+// DispatchEvent dispatches a single event to the matching method of the subscriber, if any.  It returns false if the
+// event signals that the operation is finished.
+func DispatchEvent(event Event, sub EventSubscriber) bool {
+	switch event.Type {
+	case CancelEvent:
+		return false
+	case ResourcePreEvent:
+		sub.OnPreStep(event.Payload.(ResourcePreEventPayload))
+	case ResourceOutputsEvent:
+		sub.OnPostStep(event.Payload.(ResourceOutputsEventPayload))
+	case ResourceOperationFailed:
+		sub.OnStepFailed(event.Payload.(ResourceOperationFailedPayload))
+	case DiagEvent:
+		sub.OnDiagnostic(event.Payload.(DiagEventPayload))
+	case SummaryEvent:
+		sub.OnSummary(event.Payload.(SummaryEventPayload))
+	}
+	return true
+}
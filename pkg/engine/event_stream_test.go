@@ -0,0 +1,99 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+type recordingSubscriber struct {
+	calls []string
+}
+
+func (r *recordingSubscriber) OnPreStep(payload ResourcePreEventPayload) {
+	r.calls = append(r.calls, "pre:"+string(payload.Metadata.Op))
+}
+
+func (r *recordingSubscriber) OnPostStep(payload ResourceOutputsEventPayload) {
+	r.calls = append(r.calls, "post:"+string(payload.Metadata.Op))
+}
+
+func (r *recordingSubscriber) OnStepFailed(payload ResourceOperationFailedPayload) {
+	r.calls = append(r.calls, "failed:"+string(payload.Metadata.Op))
+}
+
+func (r *recordingSubscriber) OnDiagnostic(payload DiagEventPayload) {
+	r.calls = append(r.calls, "diag:"+payload.Message)
+}
+
+func (r *recordingSubscriber) OnSummary(payload SummaryEventPayload) {
+	r.calls = append(r.calls, "summary")
+}
+
+func TestDispatchEvents(t *testing.T) {
+	events := make(chan Event, 8)
+	events <- Event{Type: PreludeEvent, Payload: PreludeEventPayload{}}
+	events <- Event{Type: ResourcePreEvent, Payload: ResourcePreEventPayload{
+		Metadata: StepEventMetadata{Op: deploy.OpCreate},
+	}}
+	events <- Event{Type: DiagEvent, Payload: DiagEventPayload{Message: "hello", Severity: diag.Info}}
+	events <- Event{Type: ResourceOutputsEvent, Payload: ResourceOutputsEventPayload{
+		Metadata: StepEventMetadata{Op: deploy.OpCreate},
+	}}
+	events <- Event{Type: ResourceOperationFailed, Payload: ResourceOperationFailedPayload{
+		Metadata: StepEventMetadata{Op: deploy.OpUpdate},
+	}}
+	events <- Event{Type: SummaryEvent, Payload: SummaryEventPayload{}}
+	events <- cancelEvent()
+
+	// Events after cancellation must not be dispatched.
+	events <- Event{Type: SummaryEvent, Payload: SummaryEventPayload{}}
+
+	sub := &recordingSubscriber{}
+	DispatchEvents(events, sub)
+	assert.Equal(t, []string{
+		"pre:create",
+		"diag:hello",
+		"post:create",
+		"failed:update",
+		"summary",
+	}, sub.calls)
+}
+
+func TestStepEventMetadataDiffs(t *testing.T) {
+	typ := tokens.Type("pkgA:m:typA")
+	urn := resource.NewURN("test", "test", "", typ, "resA")
+	old := &resource.State{Type: typ, URN: urn, Inputs: resource.PropertyMap{
+		"a": resource.NewStringProperty("foo"),
+		"b": resource.NewNumberProperty(1),
+		"c": resource.NewBoolProperty(true),
+	}}
+	new := &resource.State{Type: typ, URN: urn, Inputs: resource.PropertyMap{
+		"a": resource.NewStringProperty("bar"),
+		"b": resource.NewNumberProperty(1),
+		"d": resource.NewBoolProperty(false),
+	}}
+
+	step := deploy.NewUpdateStep(nil, nil, old, new, nil, nil, nil)
+	metadata := makeStepEventMetadata(step.Op(), step, false, nil)
+	assert.Equal(t, []resource.PropertyKey{"a", "c", "d"}, metadata.Diffs)
+}
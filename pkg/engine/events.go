@@ -23,6 +23,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
@@ -79,6 +80,7 @@ type SummaryEventPayload struct {
 	MaybeCorrupt    bool            // true if one or more resources may be corrupt
 	Duration        time.Duration   // the duration of the entire update operation (zero values for previews)
 	ResourceChanges ResourceChanges // count of changed resources, useful for reporting
+	Summary         *PlanSummary    // counts of resources by operation and type, and their estimated costs
 }
 
 type ResourceOperationFailedPayload struct {
@@ -107,8 +109,13 @@ type StepEventMetadata struct {
 	New      *StepEventStateMetadata // the state of the resource after performing this step.
 	Res      *StepEventStateMetadata // the latest state for the resource that is known (worst case, old).
 	Keys     []resource.PropertyKey  // the keys causing replacement (only for CreateStep and ReplaceStep).
+	Diffs    []resource.PropertyKey  // the input keys that differ between the old and new states, if both exist.
 	Logical  bool                    // true if this step represents a logical operation in the program.
 	Provider string                  // the provider that performed this step.
+	Guarded  bool                    // true if this step deletes or replaces a resource that is guarded.
+
+	// the structured diff reported by the provider, if any, keyed by property path.
+	DetailedDiff map[string]plugin.PropertyDiff
 }
 
 type StepEventStateMetadata struct {
@@ -143,7 +150,9 @@ type StepEventStateMetadata struct {
 	InitErrors []string
 }
 
-func makeEventEmitter(events chan<- Event, update UpdateInfo) (eventEmitter, error) {
+func makeEventEmitter(events chan<- Event, update UpdateInfo,
+	redaction resource.RedactionPolicy) (eventEmitter, error) {
+
 	target := update.GetTarget()
 	var secrets []string
 	if target.Config.HasSecureValue() {
@@ -166,22 +175,44 @@ func makeEventEmitter(events chan<- Event, update UpdateInfo) (eventEmitter, err
 	logging.AddGlobalFilter(logging.CreateFilter(secrets, "[secret]"))
 
 	return eventEmitter{
-		Chan: events,
+		Chan:      events,
+		Redaction: redaction,
 	}, nil
 }
 
 type eventEmitter struct {
-	Chan chan<- Event
+	Chan      chan<- Event
+	Redaction resource.RedactionPolicy // the policy that decides which property values events must not reveal.
 }
 
-func makeStepEventMetadata(op deploy.StepOp, step deploy.Step, debug bool) StepEventMetadata {
+func makeStepEventMetadata(op deploy.StepOp, step deploy.Step, debug bool,
+	redaction resource.RedactionPolicy) StepEventMetadata {
+
 	contract.Assert(op == step.Op() || step.Op() == deploy.OpRefresh)
 
-	var keys []resource.PropertyKey
-	if step.Op() == deploy.OpCreateReplacement {
-		keys = step.(*deploy.CreateStep).Keys()
-	} else if step.Op() == deploy.OpReplace {
-		keys = step.(*deploy.ReplaceStep).Keys()
+	var keys, diffs []resource.PropertyKey
+	var detailedDiff map[string]plugin.PropertyDiff
+	switch s := step.(type) {
+	case *deploy.CreateStep:
+		keys, diffs, detailedDiff = s.Keys(), s.Diffs(), s.DetailedDiff()
+	case *deploy.ReplaceStep:
+		keys, diffs, detailedDiff = s.Keys(), s.Diffs(), s.DetailedDiff()
+	case *deploy.UpdateStep:
+		diffs, detailedDiff = s.Diffs(), s.DetailedDiff()
+	}
+
+	// Prefer the changed keys reported by the provider, if any, to those we can infer by diffing the inputs.
+	if diffs == nil {
+		if old, new := step.Old(), step.New(); old != nil && new != nil {
+			if diff := old.Inputs.DiffWithHashes(new.Inputs, old.InputHashes, new.InputHashes); diff != nil {
+				diffs = diff.ChangedKeys()
+			}
+		}
+	}
+
+	var guarded bool
+	if plan := step.Plan(); plan != nil && (op == deploy.OpDelete || op == deploy.OpReplace) {
+		_, guarded = plan.Guarded(step.Old())
 	}
 
 	return StepEventMetadata{
@@ -189,15 +220,21 @@ func makeStepEventMetadata(op deploy.StepOp, step deploy.Step, debug bool) StepE
 		URN:      step.URN(),
 		Type:     step.Type(),
 		Keys:     keys,
-		Old:      makeStepEventStateMetadata(step.Old(), debug),
-		New:      makeStepEventStateMetadata(step.New(), debug),
-		Res:      makeStepEventStateMetadata(step.Res(), debug),
+		Diffs:    diffs,
+		Old:      makeStepEventStateMetadata(step.Old(), debug, redaction),
+		New:      makeStepEventStateMetadata(step.New(), debug, redaction),
+		Res:      makeStepEventStateMetadata(step.Res(), debug, redaction),
 		Logical:  step.Logical(),
 		Provider: step.Provider(),
+		Guarded:  guarded,
+
+		DetailedDiff: detailedDiff,
 	}
 }
 
-func makeStepEventStateMetadata(state *resource.State, debug bool) *StepEventStateMetadata {
+func makeStepEventStateMetadata(state *resource.State, debug bool,
+	redaction resource.RedactionPolicy) *StepEventStateMetadata {
+
 	if state == nil {
 		return nil
 	}
@@ -210,15 +247,21 @@ func makeStepEventStateMetadata(state *resource.State, debug bool) *StepEventSta
 		ID:         state.ID,
 		Parent:     state.Parent,
 		Protect:    state.Protect,
-		Inputs:     filterPropertyMap(state.Inputs, debug),
-		Outputs:    filterPropertyMap(state.Outputs, debug),
+		Inputs:     filterPropertyMap(state.Inputs.Redacted(state.Type, redaction), debug),
+		Outputs:    filterPropertyMap(state.Outputs.Redacted(state.Type, redaction), debug),
 		Provider:   state.Provider,
 		InitErrors: state.InitErrors,
 	}
 }
 
 func filterPropertyMap(propertyMap resource.PropertyMap, debug bool) resource.PropertyMap {
-	mappable := propertyMap.Mappable()
+	// Keep JSON values intact rather than flattening them into strings, so that they can be displayed as documents.
+	mappable := propertyMap.MapRepl(nil, func(v resource.PropertyValue) (interface{}, bool) {
+		if v.IsJSON() {
+			return v.JSONValue(), true
+		}
+		return nil, false
+	})
 
 	var filterValue func(v interface{}) interface{}
 
@@ -285,6 +328,11 @@ func filterPropertyMap(propertyMap resource.PropertyMap, debug bool) resource.Pr
 			return resource.Output{
 				Element: filterPropertyValue(t.Element),
 			}
+		case resource.JSON:
+			return resource.JSON{Raw: logging.FilterString(t.Raw)}
+		case resource.FileRef:
+			// File references hold only a path and a hash, so there is nothing to filter.
+			return t
 		}
 
 		// Next, see if it's an array, slice, pointer or struct, and handle each accordingly.
@@ -334,7 +382,7 @@ func (e *eventEmitter) resourceOperationFailedEvent(
 	e.Chan <- Event{
 		Type: ResourceOperationFailed,
 		Payload: ResourceOperationFailedPayload{
-			Metadata: makeStepEventMetadata(step.Op(), step, debug),
+			Metadata: makeStepEventMetadata(step.Op(), step, debug, e.Redaction),
 			Status:   status,
 			Steps:    steps,
 		},
@@ -347,7 +395,7 @@ func (e *eventEmitter) resourceOutputsEvent(op deploy.StepOp, step deploy.Step,
 	e.Chan <- Event{
 		Type: ResourceOutputsEvent,
 		Payload: ResourceOutputsEventPayload{
-			Metadata: makeStepEventMetadata(op, step, debug),
+			Metadata: makeStepEventMetadata(op, step, debug, e.Redaction),
 			Planning: planning,
 			Debug:    debug,
 		},
@@ -362,7 +410,7 @@ func (e *eventEmitter) resourcePreEvent(
 	e.Chan <- Event{
 		Type: ResourcePreEvent,
 		Payload: ResourcePreEventPayload{
-			Metadata: makeStepEventMetadata(step.Op(), step, debug),
+			Metadata: makeStepEventMetadata(step.Op(), step, debug, e.Redaction),
 			Planning: planning,
 			Debug:    debug,
 		},
@@ -389,7 +437,7 @@ func (e *eventEmitter) preludeEvent(isPreview bool, cfg config.Map) {
 	}
 }
 
-func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges) {
+func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges, summary *PlanSummary) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
 	e.Chan <- Event{
@@ -399,12 +447,13 @@ func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges) {
 			MaybeCorrupt:    false,
 			Duration:        0,
 			ResourceChanges: resourceChanges,
+			Summary:         summary,
 		},
 	}
 }
 
 func (e *eventEmitter) updateSummaryEvent(maybeCorrupt bool,
-	duration time.Duration, resourceChanges ResourceChanges) {
+	duration time.Duration, resourceChanges ResourceChanges, summary *PlanSummary) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
 	e.Chan <- Event{
@@ -414,6 +463,7 @@ func (e *eventEmitter) updateSummaryEvent(maybeCorrupt bool,
 			MaybeCorrupt:    maybeCorrupt,
 			Duration:        duration,
 			ResourceChanges: resourceChanges,
+			Summary:         summary,
 		},
 	}
 }
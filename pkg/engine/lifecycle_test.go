@@ -16,12 +16,21 @@ package engine
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/mitchellh/copystructure"
@@ -37,12 +46,15 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/policy"
+	"github.com/pulumi/pulumi/pkg/resource/schema"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
 	"github.com/pulumi/pulumi/pkg/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
 )
 
 type JournalEntryKind int
@@ -525,7 +537,7 @@ func TestEmptyProgramLifecycle(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, program)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps:   MakeBasicLifecycleSteps(t, 0),
 	}
 	p.Run(t, nil)
@@ -540,14 +552,14 @@ func TestSingleResourceDefaultProviderLifecycle(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		return nil
 	})
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps:   MakeBasicLifecycleSteps(t, 2),
 	}
 	p.Run(t, nil)
@@ -562,7 +574,7 @@ func TestSingleResourceExplicitProviderLifecycle(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		provURN, provID, _, err := monitor.RegisterResource(providers.MakeProviderType("pkgA"), "provA", true, "",
-			false, nil, "", resource.PropertyMap{}, nil, false)
+			false, nil, "", resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		if provID == "" {
@@ -573,7 +585,7 @@ func TestSingleResourceExplicitProviderLifecycle(t *testing.T) {
 		assert.NoError(t, err)
 
 		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, provRef.String(),
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		return nil
@@ -581,7 +593,7 @@ func TestSingleResourceExplicitProviderLifecycle(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps:   MakeBasicLifecycleSteps(t, 2),
 	}
 	p.Run(t, nil)
@@ -596,14 +608,14 @@ func TestSingleResourceDefaultProviderUpgrade(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		return nil
 	})
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 	}
 
 	provURN := p.NewProviderURN("pkgA", "default", "")
@@ -697,14 +709,14 @@ func TestSingleResourceDefaultProviderReplace(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		return nil
 	})
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Config: config.Map{
 			config.MustMakeKey("pkgA", "foo"): config.NewValue("bar"),
 		},
@@ -776,7 +788,7 @@ func TestSingleResourceExplicitProviderReplace(t *testing.T) {
 	}
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		provURN, provID, _, err := monitor.RegisterResource(providers.MakeProviderType("pkgA"), "provA", true, "",
-			false, nil, "", providerInputs, nil, false)
+			false, nil, "", providerInputs, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		if provID == "" {
@@ -787,7 +799,7 @@ func TestSingleResourceExplicitProviderReplace(t *testing.T) {
 		assert.NoError(t, err)
 
 		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, provRef.String(),
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		return nil
@@ -795,7 +807,7 @@ func TestSingleResourceExplicitProviderReplace(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 	}
 
 	// Build a basic lifecycle.
@@ -863,7 +875,7 @@ func TestSingleResourceExplicitProviderDeleteBeforeReplace(t *testing.T) {
 	}
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		provURN, provID, _, err := monitor.RegisterResource(providers.MakeProviderType("pkgA"), "provA", true, "",
-			false, nil, "", providerInputs, nil, false)
+			false, nil, "", providerInputs, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		if provID == "" {
@@ -874,7 +886,7 @@ func TestSingleResourceExplicitProviderDeleteBeforeReplace(t *testing.T) {
 		assert.NoError(t, err)
 
 		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, provRef.String(),
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		return nil
@@ -882,7 +894,7 @@ func TestSingleResourceExplicitProviderDeleteBeforeReplace(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 	}
 
 	// Build a basic lifecycle.
@@ -954,7 +966,7 @@ func TestSingleResourceDiffUnavailable(t *testing.T) {
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
 			return &deploytest.Provider{
 				DiffF: func(urn resource.URN, id resource.ID,
-					olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
 
 					return plugin.DiffResult{}, plugin.DiffUnavailable("diff unavailable")
 				},
@@ -964,14 +976,15 @@ func TestSingleResourceDiffUnavailable(t *testing.T) {
 
 	inputs := resource.PropertyMap{}
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
-		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", inputs, nil, false)
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", inputs, nil, false,
+			false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		return nil
 	})
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 	}
 	resURN := p.NewURN("pkgA:m:typA", "resA", "")
 
@@ -1012,7 +1025,7 @@ func TestDestroyWithPendingDelete(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 	}
 
 	resURN := p.NewURN("pkgA:m:typA", "resA", "")
@@ -1084,7 +1097,7 @@ func TestUpdateWithPendingDelete(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, nil, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 	}
 
 	resURN := p.NewURN("pkgA:m:typA", "resA", "")
@@ -1157,19 +1170,19 @@ func TestParallelRefresh(t *testing.T) {
 	// it.
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		resB, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{resA}, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		resC, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, []resource.URN{resB}, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resD", true, "", false, []resource.URN{resC}, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		return nil
@@ -1177,7 +1190,7 @@ func TestParallelRefresh(t *testing.T) {
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p := &TestPlan{
-		Options: UpdateOptions{Parallel: 4, host: host},
+		Options: UpdateOptions{Parallel: 4, Host: host},
 	}
 
 	p.Steps = []TestStep{{Op: Update}}
@@ -1219,7 +1232,7 @@ func TestExternalRefresh(t *testing.T) {
 	})
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps:   []TestStep{{Op: Update}},
 	}
 
@@ -1231,7 +1244,7 @@ func TestExternalRefresh(t *testing.T) {
 	assert.True(t, snap.Resources[1].External)
 
 	p = &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps:   []TestStep{{Op: Refresh}},
 	}
 
@@ -1243,6 +1256,72 @@ func TestExternalRefresh(t *testing.T) {
 	assert.True(t, snap.Resources[1].External)
 }
 
+// Tests that external resources expose their outputs to the resources that depend on them, but are never updated or
+// deleted, even when their stack is destroyed.
+func TestExternalResources(t *testing.T) {
+	var deleted []resource.URN
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					if id == "missing" {
+						return nil, resource.StatusOK, nil
+					}
+					return resource.PropertyMap{"arn": resource.NewStringProperty("arn:" + string(id))},
+						resource.StatusOK, nil
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					deleted = append(deleted, urn)
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	id := resource.ID("resA-id")
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, outsA, err := monitor.ReadResource("pkgA:m:typA", "resA", id, "", resource.PropertyMap{}, "")
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typB", "resB", true, "", false, []resource.URN{urnA}, "",
+			resource.PropertyMap{"arn": outsA["arn"]}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	urnA, urnB := p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typB", "resB", "")
+
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 3)
+	for _, res := range snap.Resources {
+		switch res.URN {
+		case urnA:
+			assert.True(t, res.External)
+		case urnB:
+			assert.False(t, res.External)
+			assert.Equal(t, resource.NewStringProperty("arn:resA-id"), res.Inputs["arn"])
+		}
+	}
+
+	// Destroying the stack deletes the resources that it manages, and discards the external resource.
+	p.Steps = []TestStep{{Op: Destroy}}
+	snap = p.Run(t, snap)
+	assert.Empty(t, snap.Resources)
+	assert.Equal(t, []resource.URN{urnB}, deleted)
+
+	// Reading a resource that does not exist fails the update.
+	id = "missing"
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, nil)
+}
+
 func TestRefreshInitFailure(t *testing.T) {
 	p := &TestPlan{}
 
@@ -1283,13 +1362,13 @@ func TestRefreshInitFailure(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		return nil
 	})
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 
-	p.Options.host = host
+	p.Options.Host = host
 
 	//
 	// Create an old snapshot with a single initialization failure.
@@ -1370,14 +1449,15 @@ func TestCheckFailureRecord(t *testing.T) {
 	}
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
-		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", nil, nil, false)
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", nil, nil, false,
+			false, nil, nil, nil, nil, nil, nil)
 		assert.Error(t, err)
 		return err
 	})
 
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps: []TestStep{{
 			Op:            Update,
 			ExpectFailure: true,
@@ -1418,14 +1498,15 @@ func TestCheckFailureInvalidPropertyRecord(t *testing.T) {
 	}
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
-		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", nil, nil, false)
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", nil, nil, false,
+			false, nil, nil, nil, nil, nil, nil)
 		assert.Error(t, err)
 		return err
 	})
 
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps: []TestStep{{
 			Op:            Update,
 			ExpectFailure: true,
@@ -1473,13 +1554,14 @@ func TestRefreshWithDelete(t *testing.T) {
 			}
 
 			program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
-				_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", nil, nil, false)
+				_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", nil, nil,
+					false, false, nil, nil, nil, nil, nil, nil)
 				assert.NoError(t, err)
 				return err
 			})
 
 			host := deploytest.NewPluginHost(nil, nil, program, loaders...)
-			p := &TestPlan{Options: UpdateOptions{host: host, Parallel: parallelFactor}}
+			p := &TestPlan{Options: UpdateOptions{Host: host, Parallel: parallelFactor}}
 
 			p.Steps = []TestStep{{Op: Update}}
 			snap := p.Run(t, nil)
@@ -1549,7 +1631,7 @@ func TestRefreshDeleteDependencies(t *testing.T) {
 		}),
 	}
 
-	p.Options.host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
 
 	p.Steps = []TestStep{{Op: Refresh}}
 	snap := p.Run(t, old)
@@ -1651,7 +1733,7 @@ func TestRefreshBasics(t *testing.T) {
 		}),
 	}
 
-	p.Options.host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
 
 	p.Steps = []TestStep{{
 		Op: Refresh,
@@ -1718,6 +1800,185 @@ func TestRefreshBasics(t *testing.T) {
 	}
 }
 
+// Tests that a refresh leaves resources as they are if their provider advertises that it cannot read them.
+func TestRefreshWithoutRead(t *testing.T) {
+	p := &TestPlan{}
+
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	outputs := resource.PropertyMap{"size": resource.NewNumberProperty(1)}
+	old := &deploy.Snapshot{
+		Resources: []*resource.State{{
+			Type:    urnA.Type(),
+			URN:     urnA,
+			Custom:  true,
+			ID:      "0",
+			Inputs:  resource.PropertyMap{},
+			Outputs: outputs,
+		}},
+	}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CapabilitiesF: func() plugin.ProviderCapabilities {
+					return plugin.ProviderCapabilities{Advertised: true}
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					state resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					assert.Fail(t, "Read should not be called")
+					return nil, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
+	p.Steps = []TestStep{{Op: Refresh}}
+	snap := p.Run(t, old)
+
+	for _, r := range snap.Resources {
+		if r.URN == urnA {
+			assert.Equal(t, outputs, r.Outputs)
+		}
+	}
+}
+
+// Tests that a refresh that merges reverts out-of-band changes to the properties that the program sets and keeps
+// changes to all other properties.
+func TestRefreshMerge(t *testing.T) {
+	p := &TestPlan{}
+
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	old := &deploy.Snapshot{
+		Resources: []*resource.State{{
+			Type:   urnA.Type(),
+			URN:    urnA,
+			Custom: true,
+			ID:     "0",
+			Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			Outputs: resource.PropertyMap{
+				"size": resource.NewNumberProperty(1),
+				"etag": resource.NewStringProperty("a"),
+			},
+		}},
+	}
+
+	updates := 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					state resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					return resource.PropertyMap{
+						"size": resource.NewNumberProperty(2),
+						"etag": resource.NewStringProperty("b"),
+					}, resource.StatusOK, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID,
+					oldInputs, oldOutputs, newInputs resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					updates++
+					assert.Equal(t, resource.NewNumberProperty(2), oldOutputs["size"])
+					return resource.PropertyMap{
+						"size": newInputs["size"],
+						"etag": oldOutputs["etag"],
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
+	p.Options.RefreshMerge = true
+	p.Steps = []TestStep{{Op: Refresh}}
+	snap := p.Run(t, old)
+
+	assert.Equal(t, 1, updates)
+	for _, r := range snap.Resources {
+		if r.URN == urnA {
+			assert.Equal(t, resource.PropertyMap{
+				"size": resource.NewNumberProperty(1),
+				"etag": resource.NewStringProperty("b"),
+			}, r.Outputs)
+		}
+	}
+}
+
+// Tests that a refresh reads resources in batches from providers that support it, and that a resource whose batched
+// read fails is read again on its own.
+func TestRefreshBatches(t *testing.T) {
+	p := &TestPlan{}
+
+	const resType = "pkgA:m:typA"
+
+	var oldResources []*resource.State
+	for i := 0; i < 5; i++ {
+		oldResources = append(oldResources, &resource.State{
+			Type:    resType,
+			URN:     p.NewURN(resType, fmt.Sprintf("res%d", i), ""),
+			Custom:  true,
+			ID:      resource.ID(strconv.Itoa(i)),
+			Inputs:  resource.PropertyMap{},
+			Outputs: resource.PropertyMap{},
+		})
+	}
+	old := &deploy.Snapshot{Resources: oldResources}
+
+	var lock sync.Mutex
+	var batchSizes []int
+	var reads []resource.ID
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.BatchProvider{
+				Provider: deploytest.Provider{
+					ReadF: func(urn resource.URN, id resource.ID,
+						state resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+						lock.Lock()
+						defer lock.Unlock()
+						reads = append(reads, id)
+						return resource.PropertyMap{"id": resource.NewStringProperty(string(id))}, resource.StatusOK, nil
+					},
+				},
+				ReadBatchF: func(batch []plugin.BatchRead) ([]plugin.BatchReadResult, error) {
+					lock.Lock()
+					defer lock.Unlock()
+					batchSizes = append(batchSizes, len(batch))
+
+					results := make([]plugin.BatchReadResult, len(batch))
+					for i, r := range batch {
+						if r.ID == "3" {
+							results[i].Err = errors.New("throttled")
+							continue
+						}
+						results[i].Outputs = resource.PropertyMap{"id": resource.NewStringProperty(string(r.ID))}
+					}
+					return results, nil
+				},
+			}, nil
+		}),
+	}
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, nil, loaders...)
+	p.Options.RefreshBatchSize = 2
+	p.Steps = []TestStep{{Op: Refresh}}
+	snap := p.Run(t, old)
+
+	sort.Ints(batchSizes)
+	assert.Equal(t, []int{1, 2, 2}, batchSizes)
+	assert.Equal(t, []resource.ID{"3"}, reads)
+
+	provURN := p.NewProviderURN("pkgA", "default", "")
+	for _, r := range snap.Resources {
+		if r.URN == provURN {
+			continue
+		}
+		assert.Equal(t, resource.PropertyMap{"id": resource.NewStringProperty(string(r.ID))}, r.Outputs)
+	}
+}
+
 // Tests that an interrupted refresh leaves behind an expected state.
 func TestCanceledRefresh(t *testing.T) {
 	p := &TestPlan{}
@@ -1793,7 +2054,7 @@ func TestCanceledRefresh(t *testing.T) {
 	op := TestOp(Refresh)
 	options := UpdateOptions{
 		Parallel: 1,
-		host:     deploytest.NewPluginHost(nil, nil, nil, loaders...),
+		Host:     deploytest.NewPluginHost(nil, nil, nil, loaders...),
 	}
 	project, target := p.GetProject(), p.GetTarget(old)
 	validate := func(project workspace.Project, target deploy.Target, j *Journal, _ []Event, err error) error {
@@ -1885,7 +2146,7 @@ func TestLanguageHostDiagnostics(t *testing.T) {
 
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps: []TestStep{{
 			Op:            Update,
 			ExpectFailure: true,
@@ -1938,7 +2199,7 @@ func TestBrokenDecrypter(t *testing.T) {
 	configMap := make(config.Map)
 	configMap[key] = config.NewSecureValue("hunter2")
 	p := &TestPlan{
-		Options:   UpdateOptions{host: host},
+		Options:   UpdateOptions{Host: host},
 		Decrypter: brokenDecrypter{ErrorMessage: msg},
 		Config:    configMap,
 		Steps: []TestStep{{
@@ -1967,7 +2228,8 @@ func TestBadResourceType(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, mon *deploytest.ResourceMonitor) error {
 		_, _, _, err := mon.RegisterResource(
-			"very:bad", "resA", true, "", false, nil, "", resource.PropertyMap{}, nil, false)
+			"very:bad", "resA", true, "", false, nil, "", resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil,
+			nil)
 		assert.Error(t, err)
 		rpcerr, ok := rpcerror.FromError(err)
 		assert.True(t, ok)
@@ -1983,11 +2245,13 @@ func TestBadResourceType(t *testing.T) {
 
 		// Component resources may have any format type.
 		_, _, _, noErr := mon.RegisterResource(
-			"a:component", "resB", false /* custom */, "", false, nil, "", resource.PropertyMap{}, nil, false)
+			"a:component", "resB", false /* custom */, "", false, nil, "", resource.PropertyMap{}, nil, false, false,
+			nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, noErr)
 
 		_, _, _, noErr = mon.RegisterResource(
-			"singlename", "resC", false /* custom */, "", false, nil, "", resource.PropertyMap{}, nil, false)
+			"singlename", "resC", false /* custom */, "", false, nil, "", resource.PropertyMap{}, nil, false, false,
+			nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, noErr)
 
 		return err
@@ -1995,7 +2259,7 @@ func TestBadResourceType(t *testing.T) {
 
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps: []TestStep{{
 			Op:            Update,
 			ExpectFailure: true,
@@ -2051,7 +2315,7 @@ func TestProviderCancellation(t *testing.T) {
 		for i := 0; i < resourceCount; i++ {
 			go func(idx int) {
 				_, _, _, errors[idx] = monitor.RegisterResource("pkgA:m:typA", fmt.Sprintf("res%d", idx), true, "",
-					false, nil, "", resource.PropertyMap{}, nil, false)
+					false, nil, "", resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 				resources.Done()
 			}(i)
 		}
@@ -2067,7 +2331,7 @@ func TestProviderCancellation(t *testing.T) {
 	op := TestOp(Update)
 	options := UpdateOptions{
 		Parallel: resourceCount,
-		host:     deploytest.NewPluginHost(nil, nil, program, loaders...),
+		Host:     deploytest.NewPluginHost(nil, nil, program, loaders...),
 	}
 	project, target := p.GetProject(), p.GetTarget(nil)
 
@@ -2116,13 +2380,13 @@ func TestPreviewWithPendingOperations(t *testing.T) {
 
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
-			resource.PropertyMap{}, nil, false)
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		return nil
 	})
 
 	op := TestOp(Update)
-	options := UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
+	options := UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
 	project, target := p.GetProject(), p.GetTarget(old)
 
 	// A preview should succeed despite the pending operations.
@@ -2139,13 +2403,14 @@ func TestUpdatePartialFailure(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
 			return &deploytest.Provider{
-				DiffF: func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
 					return plugin.DiffResult{
 						Changes: plugin.DiffSome,
 					}, nil
 				},
 
-				UpdateF: func(urn resource.URN, id resource.ID, olds,
+				UpdateF: func(urn resource.URN, id resource.ID, olds, _,
 					news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
 					outputs := resource.NewPropertyMapFromMap(map[string]interface{}{
 						"output_prop": 42,
@@ -2161,13 +2426,13 @@ func TestUpdatePartialFailure(t *testing.T) {
 		_, _, _, err := mon.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
 			resource.NewPropertyMapFromMap(map[string]interface{}{
 				"input_prop": "new inputs",
-			}), nil, false)
+			}), nil, false, false, nil, nil, nil, nil, nil, nil)
 
 		return err
 	})
 
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
-	p := &TestPlan{Options: UpdateOptions{host: host}}
+	p := &TestPlan{Options: UpdateOptions{Host: host}}
 
 	resURN := p.NewURN("pkgA:m:typA", "resA", "")
 	p.Steps = []TestStep{{
@@ -2231,7 +2496,7 @@ func TestStackReference(t *testing.T) {
 		_, _, state, err := mon.RegisterResource("pulumi:pulumi:StackReference", "other", true, "", false, nil, "",
 			resource.NewPropertyMapFromMap(map[string]interface{}{
 				"name": "other",
-			}), nil, false)
+			}), nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 		if !info.DryRun {
 			assert.Equal(t, "bar", state["outputs"].ObjectValue()["foo"].StringValue())
@@ -2251,7 +2516,7 @@ func TestStackReference(t *testing.T) {
 				}
 			},
 		},
-		Options: UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program, loaders...)},
+		Options: UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...)},
 		Steps:   MakeBasicLifecycleSteps(t, 2),
 	}
 	p.Run(t, nil)
@@ -2302,11 +2567,11 @@ func TestStackReference(t *testing.T) {
 		_, _, _, err := mon.RegisterResource("pulumi:pulumi:StackReference", "other", true, "", false, nil, "",
 			resource.NewPropertyMapFromMap(map[string]interface{}{
 				"name": "rehto",
-			}), nil, false)
+			}), nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.Error(t, err)
 		return err
 	})
-	p.Options = UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
+	p.Options = UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
 	p.Steps = []TestStep{{
 		Op:            Update,
 		ExpectFailure: true,
@@ -2320,11 +2585,11 @@ func TestStackReference(t *testing.T) {
 			resource.NewPropertyMapFromMap(map[string]interface{}{
 				"name": "other",
 				"foo":  "bar",
-			}), nil, false)
+			}), nil, false, false, nil, nil, nil, nil, nil, nil)
 		assert.Error(t, err)
 		return err
 	})
-	p.Options = UpdateOptions{host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
+	p.Options = UpdateOptions{Host: deploytest.NewPluginHost(nil, nil, program, loaders...)}
 	p.Run(t, nil)
 }
 
@@ -2421,7 +2686,7 @@ func TestLoadFailureShutdown(t *testing.T) {
 
 	op := TestOp(Update)
 	sink := diag.DefaultSink(sinkWriter, sinkWriter, diag.FormatOptions{Color: colors.Raw})
-	options := UpdateOptions{host: deploytest.NewPluginHost(sink, sink, program, loaders...)}
+	options := UpdateOptions{Host: deploytest.NewPluginHost(sink, sink, program, loaders...)}
 	project, target := p.GetProject(), p.GetTarget(old)
 
 	_, err := op.Run(project, target, options, true, nil, nil)
@@ -2537,7 +2802,7 @@ func TestDeleteBeforeReplace(t *testing.T) {
 					return plugin.DiffResult{}, nil
 				},
 				DiffF: func(urn resource.URN, id resource.ID,
-					olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
 
 					if !olds["A"].DeepEquals(news["A"]) {
 						return plugin.DiffResult{ReplaceKeys: []resource.PropertyKey{"A"}}, nil
@@ -2551,7 +2816,7 @@ func TestDeleteBeforeReplace(t *testing.T) {
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		register := func(urn resource.URN, provider string, inputs resource.PropertyMap) resource.ID {
 			_, id, _, err := monitor.RegisterResource(urn.Type(), string(urn.Name()), true, "", false, nil, provider,
-				inputs, nil, false)
+				inputs, nil, false, false, nil, nil, nil, nil, nil, nil)
 			assert.NoError(t, err)
 			return id
 		}
@@ -2572,7 +2837,7 @@ func TestDeleteBeforeReplace(t *testing.T) {
 		return nil
 	})
 
-	p.Options.host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
 
 	p.Steps = []TestStep{{
 		Op:            Update,
@@ -2624,7 +2889,7 @@ func TestPropertyDependenciesAdapter(t *testing.T) {
 			dependencies []resource.URN) resource.URN {
 
 			urn, _, _, err := monitor.RegisterResource(resType, name, true, "", false, dependencies, "", inputs,
-				inputDeps, false)
+				inputDeps, false, false, nil, nil, nil, nil, nil, nil)
 			assert.NoError(t, err)
 
 			return urn
@@ -2649,7 +2914,7 @@ func TestPropertyDependenciesAdapter(t *testing.T) {
 
 	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p := &TestPlan{
-		Options: UpdateOptions{host: host},
+		Options: UpdateOptions{Host: host},
 		Steps:   []TestStep{{Op: Update}},
 	}
 	snap := p.Run(t, nil)
@@ -2681,7 +2946,7 @@ func TestExplicitDeleteBeforeReplace(t *testing.T) {
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
 			return &deploytest.Provider{
 				DiffF: func(urn resource.URN, id resource.ID,
-					olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
 
 					if !olds["A"].DeepEquals(news["A"]) {
 						return plugin.DiffResult{ReplaceKeys: []resource.PropertyKey{"A"}}, nil
@@ -2703,7 +2968,8 @@ func TestExplicitDeleteBeforeReplace(t *testing.T) {
 	var err error
 	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
 		provURN, provID, _, err = monitor.RegisterResource(
-			providers.MakeProviderType("pkgA"), "provA", true, "", false, nil, "", nil, nil, false)
+			providers.MakeProviderType("pkgA"), "provA", true, "", false, nil, "", nil, nil, false, false, nil, nil, nil, nil,
+			nil, nil)
 		assert.NoError(t, err)
 
 		if provID == "" {
@@ -2713,18 +2979,19 @@ func TestExplicitDeleteBeforeReplace(t *testing.T) {
 		assert.NoError(t, err)
 		provA := provRef.String()
 
-		urnA, _, _, err = monitor.RegisterResource(resType, "resA", true, "", false, nil, provA, inputsA, nil, dbrA)
+		urnA, _, _, err = monitor.RegisterResource(resType, "resA", true, "", false, nil, provA, inputsA, nil, dbrA,
+			false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		inputDepsB := map[resource.PropertyKey][]resource.URN{"A": {urnA}}
 		urnB, _, _, err = monitor.RegisterResource(resType, "resB", true, "", false, []resource.URN{urnA}, provA,
-			inputsB, inputDepsB, false)
+			inputsB, inputDepsB, false, false, nil, nil, nil, nil, nil, nil)
 		assert.NoError(t, err)
 
 		return nil
 	})
 
-	p.Options.host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
 	p.Steps = []TestStep{{Op: Update}}
 	snap := p.Run(t, nil)
 
@@ -2816,3 +3083,2823 @@ func TestExplicitDeleteBeforeReplace(t *testing.T) {
 	}}
 	p.Run(t, snap)
 }
+
+// Tests that a delete-before-replace replacement only condemns the dependents that read outputs of the replaced
+// resource that may change.
+func TestDeleteBeforeReplacePropertyReads(t *testing.T) {
+	p := &TestPlan{}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outputs := inputs.Copy()
+					outputs["arn"] = resource.NewStringProperty("arn:" + string(urn.Name()))
+					return resource.ID(urn.Name()), outputs, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					var replaceKeys []resource.PropertyKey
+					for k, v := range news {
+						if !olds[k].DeepEquals(v) {
+							replaceKeys = append(replaceKeys, k)
+						}
+					}
+					return plugin.DiffResult{ReplaceKeys: replaceKeys}, nil
+				},
+			}, nil
+		}),
+	}
+
+	const resType = "pkgA:index:typ"
+
+	inputsA := resource.NewPropertyMapFromMap(map[string]interface{}{"A": "foo", "name": "resA"})
+
+	var urnA, urnB, urnC resource.URN
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var outsA resource.PropertyMap
+		var err error
+		urnA, _, outsA, err = monitor.RegisterResource(resType, "resA", true, "", false, nil, "", inputsA, nil, true,
+			false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		// resB reads resA's name, which the replacement keeps.
+		urnB, _, _, err = monitor.RegisterResource(resType, "resB", true, "", false, []resource.URN{urnA}, "",
+			resource.PropertyMap{"name": outsA["name"]}, map[resource.PropertyKey][]resource.URN{"name": {urnA}},
+			false, false, nil, nil, nil, nil, nil, resource.PropertyReads{"name": {urnA: {"name"}}})
+		assert.NoError(t, err)
+
+		// resC reads resA's ARN, which is computed by the provider.
+		urnC, _, _, err = monitor.RegisterResource(resType, "resC", true, "", false, []resource.URN{urnA}, "",
+			resource.PropertyMap{"ref": outsA["arn"]}, map[resource.PropertyKey][]resource.URN{"ref": {urnA}},
+			false, false, nil, nil, nil, nil, nil, resource.PropertyReads{"ref": {urnA: {"arn"}}})
+		assert.NoError(t, err)
+
+		// Reads must name the outputs that were read.
+		_, _, _, err = monitor.RegisterResource(resType, "resD", true, "", false, []resource.URN{urnA}, "",
+			resource.PropertyMap{}, map[resource.PropertyKey][]resource.URN{"ref": {urnA}},
+			false, false, nil, nil, nil, nil, nil, resource.PropertyReads{"ref": {urnA: {"[0]"}}})
+		assert.Error(t, err)
+
+		return nil
+	})
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	for _, res := range snap.Resources {
+		if res.URN == urnB {
+			assert.Equal(t, resource.PropertyReads{"name": {urnA: {"name"}}}, res.PropertyReads)
+		}
+	}
+
+	// Change resA.A, which requires resA to be replaced. resC must be deleted before resA, but resB need not be.
+	inputsA["A"] = resource.NewStringProperty("bar")
+	p.Steps = []TestStep{{
+		Op: Update,
+
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal, evts []Event, err error) error {
+			assert.NoError(t, err)
+
+			deleted := make(map[resource.URN]bool)
+			for _, step := range j.SuccessfulSteps() {
+				if step.Op() == deploy.OpDeleteReplaced {
+					deleted[step.URN()] = true
+				}
+			}
+			assert.Equal(t, map[resource.URN]bool{urnA: true, urnC: true}, deleted)
+
+			return err
+		},
+	}}
+	p.Run(t, snap)
+}
+
+// Tests that a delete-before-replace replacement condemns the dependents that depend on the replaced resource through
+// their property dependencies alone, and leaves in place the dependents that refer to it only by URN.
+func TestDeleteBeforeReplaceUndeclaredDependencies(t *testing.T) {
+	p := &TestPlan{}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outputs := inputs.Copy()
+					outputs["arn"] = resource.NewStringProperty("arn:" + string(urn.Name()))
+					return resource.ID(urn.Name()), outputs, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					var replaceKeys []resource.PropertyKey
+					for k, v := range news {
+						if !olds[k].DeepEquals(v) {
+							replaceKeys = append(replaceKeys, k)
+						}
+					}
+					return plugin.DiffResult{ReplaceKeys: replaceKeys}, nil
+				},
+			}, nil
+		}),
+	}
+
+	const resType = "pkgA:index:typ"
+
+	inputsA := resource.NewPropertyMapFromMap(map[string]interface{}{"A": "foo"})
+
+	var urnA, urnB, urnC resource.URN
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var outsA resource.PropertyMap
+		var err error
+		urnA, _, outsA, err = monitor.RegisterResource(resType, "resA", true, "", false, nil, "", inputsA, nil, true,
+			false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		// resB reads resA's ARN, but declares no dependency on resA beyond the property dependency.
+		urnB, _, _, err = monitor.RegisterResource(resType, "resB", true, "", false, nil, "",
+			resource.PropertyMap{"ref": outsA["arn"]}, map[resource.PropertyKey][]resource.URN{"ref": {urnA}},
+			false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		// resC refers to resA by URN, which a replacement keeps.
+		urnC, _, _, err = monitor.RegisterResource(resType, "resC", true, "", false, nil, "",
+			resource.PropertyMap{"owner": resource.NewStringProperty(string(urnA))}, nil,
+			false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		return nil
+	})
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	// Change resA.A, which requires resA to be replaced. resB must be deleted before resA, but resC need not be.
+	inputsA["A"] = resource.NewStringProperty("bar")
+	p.Steps = []TestStep{{
+		Op: Update,
+
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal, evts []Event, err error) error {
+			assert.NoError(t, err)
+
+			var deleted []resource.URN
+			for _, step := range j.SuccessfulSteps() {
+				if step.Op() == deploy.OpDeleteReplaced {
+					deleted = append(deleted, step.URN())
+				}
+				if step.URN() == urnC {
+					assert.Equal(t, deploy.OpSame, step.Op())
+				}
+			}
+			assert.Equal(t, []resource.URN{urnB, urnA}, deleted)
+
+			return err
+		},
+	}}
+	p.Run(t, snap)
+}
+
+// Tests that outputs that the provider reports as stable are known during the preview of an update, so that the
+// resources that refer to them do not appear to change.
+func TestStableOutputsKnownDuringPreview(t *testing.T) {
+	p := &TestPlan{}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outputs := inputs.Copy()
+					outputs["arn"] = resource.NewStringProperty("arn:" + string(urn.Name()))
+					return resource.ID(urn.Name()), outputs, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					if olds["foo"].DeepEquals(news["foo"]) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{
+						Changes:     plugin.DiffSome,
+						ChangedKeys: []resource.PropertyKey{"foo"},
+						StableKeys:  []resource.PropertyKey{"arn"},
+					}, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, _, oldOutputs,
+					news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					outputs := news.Copy()
+					outputs["arn"] = oldOutputs["arn"]
+					return outputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	const resType = "pkgA:m:typA"
+
+	foo, created := "bar", false
+	var urnB resource.URN
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, outsA, err := monitor.RegisterResource(resType, "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo}), nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		assert.NoError(t, err)
+
+		// Once resA exists, its ARN must be known even while previewing an update to it.
+		if created {
+			assert.Equal(t, resource.NewStringProperty("arn:resA"), outsA["arn"])
+		}
+
+		urnB, _, _, err = monitor.RegisterResource(resType, "resB", true, "", false, nil, "",
+			resource.PropertyMap{"ref": outsA["arn"]}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		return nil
+	})
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	created = true
+
+	// Change resA's inputs. resA is updated, but resB is left alone in both the preview and the update.
+	foo = "baz"
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal, evts []Event, err error) error {
+			assert.NoError(t, err)
+			for _, step := range j.SuccessfulSteps() {
+				if step.URN() == urnB {
+					assert.Equal(t, deploy.OpSame, step.Op())
+				}
+			}
+			return err
+		},
+	}}
+	p.Run(t, snap)
+}
+
+// Tests that the outputs a provider predicts for resources that are being created or updated are reported during
+// previews, so that the resources that refer to them do not see unknowns.
+func TestPreviewOutputs(t *testing.T) {
+	p := &TestPlan{}
+
+	size := func(props resource.PropertyMap) resource.PropertyValue {
+		return resource.NewNumberProperty(float64(len(props["foo"].StringValue())))
+	}
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				PreviewOutputsF: func(urn resource.URN, inputs resource.PropertyMap) resource.PropertyMap {
+					return resource.PropertyMap{"arn": resource.NewStringProperty("arn:" + string(urn.Name()))}
+				},
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outputs := inputs.Copy()
+					outputs["arn"] = resource.NewStringProperty("arn:" + string(urn.Name()))
+					outputs["size"] = size(inputs)
+					return resource.ID(urn.Name()), outputs, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					if olds["foo"].DeepEquals(news["foo"]) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{
+						Changes:        plugin.DiffSome,
+						ChangedKeys:    []resource.PropertyKey{"foo"},
+						StableKeys:     []resource.PropertyKey{"arn"},
+						PreviewOutputs: resource.PropertyMap{"size": size(news)},
+					}, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, _, oldOutputs,
+					news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					outputs := news.Copy()
+					outputs["arn"] = oldOutputs["arn"]
+					outputs["size"] = size(news)
+					return outputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	const resType = "pkgA:m:typA"
+
+	foo, created := "bar", false
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, outsA, err := monitor.RegisterResource(resType, "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo}), nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		assert.NoError(t, err)
+
+		// The ARN is predicted when resA is created, and its size when it is updated.
+		assert.Equal(t, resource.NewStringProperty("arn:resA"), outsA["arn"])
+		if created {
+			assert.Equal(t, resource.NewNumberProperty(float64(len(foo))), outsA["size"])
+		}
+		return nil
+	})
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	created = true
+
+	foo = "bazqux"
+	p.Run(t, snap)
+}
+
+func TestPlaceholderIDs(t *testing.T) {
+	p := &TestPlan{}
+
+	var refs []string
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					if ref, ok := inputs["ref"]; ok {
+						refs = append(refs, ref.StringValue())
+					}
+					return resource.ID("id-" + urn.Name()), inputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	const resType = "pkgA:m:typA"
+
+	program := deploytest.NewLanguageRuntime(func(info plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, idA, _, err := monitor.RegisterResource(resType, "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		// A preview allocates the same placeholder ID for resA every time; an update uses the ID that it is created with.
+		if info.DryRun {
+			assert.Equal(t, deploy.DeterministicIDAllocator.AllocateID(urnA), idA)
+			assert.True(t, deploy.IsPlaceholderID(idA))
+		} else {
+			assert.Equal(t, resource.ID("id-resA"), idA)
+		}
+
+		_, _, _, err = monitor.RegisterResource(resType, "resB", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"ref": string(idA)}), nil, false, false, nil, nil,
+			nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p.Options.IDAllocator = deploy.DeterministicIDAllocator
+	p.Steps = []TestStep{{Op: Update}}
+	p.Run(t, nil)
+
+	// Only the update creates resources, and resB is created with resA's real ID.
+	assert.Equal(t, []string{"id-resA"}, refs)
+}
+
+// Tests that protected resources cannot be deleted unless protection is explicitly overridden.
+func TestProtectedResourceDelete(t *testing.T) {
+	deleted := false
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					deleted = true
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", true, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// Create the protected resource, then attempt to destroy it.
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps: []TestStep{
+			{Op: Update},
+			{Op: Destroy, ExpectFailure: true, SkipPreview: true},
+		},
+	}
+	snap := p.Run(t, nil)
+	assert.False(t, deleted)
+	assert.Len(t, snap.Resources, 2)
+
+	// Now force the destroy.
+	p.Options.ForceUnprotect = true
+	p.Steps = []TestStep{{Op: Destroy}}
+	snap = p.Run(t, snap)
+	assert.True(t, deleted)
+	assert.Len(t, snap.Resources, 0)
+}
+
+// Tests that a destroy that excludes protected resources deletes everything else, leaving the protected resources and
+// the resources they depend on -- including those whose URNs they refer to -- in place.
+func TestDestroyExcludeProtected(t *testing.T) {
+	var deleted []resource.URN
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					deleted = append(deleted, urn)
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", true, nil, "",
+			resource.PropertyMap{"source": resource.NewStringProperty(string(urnA))}, nil, false, false, nil, nil,
+			nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	p.Options.ExcludeProtected = true
+	p.Steps = []TestStep{{Op: Destroy}}
+	snap = p.Run(t, snap)
+	if assert.Len(t, deleted, 1) {
+		assert.Equal(t, tokens.QName("resC"), deleted[0].Name())
+	}
+	var names []tokens.QName
+	for _, res := range snap.Resources {
+		names = append(names, res.URN.Name())
+	}
+	assert.Equal(t, []tokens.QName{"default", "resA", "resB"}, names)
+}
+
+// Tests that deleting a resource that is retained on delete removes it from the snapshot without calling the
+// provider.
+func TestRetainOnDelete(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					assert.Fail(t, "Delete was called for a resource that is retained on delete")
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	createResource := true
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		if createResource {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+				resource.PropertyMap{}, nil, false, true, nil, nil, nil, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resURN := p.NewURN("pkgA:m:typA", "resA", "")
+
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, resURN, snap.Resources[1].URN)
+	assert.True(t, snap.Resources[1].RetainOnDelete)
+
+	// Remove the resource from the program. The resource should be dropped from the snapshot.
+	createResource = false
+	snap = p.Run(t, snap)
+	for _, res := range snap.Resources {
+		assert.NotEqual(t, resURN, res.URN)
+	}
+}
+
+// Tests that a plan restricted to a set of targets leaves all other resources untouched.
+func TestTargetedUpdate(t *testing.T) {
+	updated := make(map[resource.URN]bool)
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
+					if !olds["foo"].DeepEquals(news["foo"]) {
+						return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, olds, _,
+					news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+					updated[urn] = true
+					return news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	foo, createC := "bar", true
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		inputs := resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo})
+		for _, name := range []string{"resA", "resB"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, "", false, nil, "", inputs, nil,
+				false, false, nil, nil, nil, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+		if createC {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, nil, "", inputs, nil,
+				false, false, nil, nil, nil, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA, resB, resC := p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typA", "resB", ""),
+		p.NewURN("pkgA:m:typA", "resC", "")
+	snap := p.Run(t, nil)
+
+	// Change every resource's inputs and remove resC from the program, but only target resA.
+	foo, createC = "baz", false
+	p.Options.Targets = []resource.URN{resA}
+	snap = p.Run(t, snap)
+	assert.True(t, updated[resA])
+	assert.False(t, updated[resB])
+
+	urns := make(map[resource.URN]*resource.State)
+	for _, res := range snap.Resources {
+		urns[res.URN] = res
+	}
+	assert.Equal(t, resource.NewStringProperty("baz"), urns[resA].Inputs["foo"])
+	assert.Equal(t, resource.NewStringProperty("bar"), urns[resB].Inputs["foo"])
+	assert.Contains(t, urns, resC)
+
+	// A new resource that is not targeted cannot be created.
+	var withoutC []*resource.State
+	for _, res := range snap.Resources {
+		if res.URN != resC {
+			withoutC = append(withoutC, res)
+		}
+	}
+	createC = true
+	p.Options.Targets = []resource.URN{resB}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, &deploy.Snapshot{Resources: withoutC})
+}
+
+// Tests that providers receive a resource's old inputs and old outputs separately in Diff and Update.
+func TestProviderInputOutputSeparation(t *testing.T) {
+	var diffInputs, diffOutputs, updateInputs, updateOutputs resource.PropertyMap
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					outputs := resource.PropertyMap{"computed": resource.NewNumberProperty(42)}
+					return "created-id", outputs, resource.StatusOK, nil
+				},
+				DiffF: func(urn resource.URN, id resource.ID,
+					oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult, error) {
+					diffInputs, diffOutputs = oldInputs, oldOutputs
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+					newInputs resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+					updateInputs, updateOutputs = oldInputs, oldOutputs
+					return oldOutputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	foo := "bar"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		inputs := resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo})
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", inputs, nil,
+			false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+
+	foo = "baz"
+	snap = p.Run(t, snap)
+
+	oldInputs := resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"})
+	oldOutputs := resource.PropertyMap{"computed": resource.NewNumberProperty(42)}
+	assert.Equal(t, oldInputs, diffInputs)
+	assert.Equal(t, oldOutputs, diffOutputs)
+	assert.Equal(t, oldInputs, updateInputs)
+	assert.Equal(t, oldOutputs, updateOutputs)
+
+	// The snapshot records the new inputs alongside the outputs returned by the provider.
+	res := snap.Resources[1]
+	assert.Equal(t, resource.NewStringProperty("baz"), res.Inputs["foo"])
+	assert.Equal(t, oldOutputs, res.Outputs)
+}
+
+func TestProviderRetries(t *testing.T) {
+	var createAttempts, deleteAttempts int
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					// Fail the first two attempts with a throttling error.
+					createAttempts++
+					if createAttempts < 3 {
+						return "", nil, resource.StatusOK, rpcerror.New(codes.ResourceExhausted, "slow down")
+					}
+					return "created-id", news, resource.StatusOK, nil
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					// Always fail with an error that the policy for this type does not retry.
+					deleteAttempts++
+					return resource.StatusOK, rpcerror.New(codes.Unavailable, "try again later")
+				},
+			}, nil
+		}),
+	}
+
+	register := true
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		if register {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+				resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host: host,
+			Retries: &deploy.RetryPolicies{
+				Default: &deploy.RetryPolicy{MaxAttempts: 5},
+				Types: map[tokens.Type]*deploy.RetryPolicy{
+					"pkgA:m:typA": {MaxAttempts: 5, Classes: []deploy.ErrorClass{deploy.ErrorClassThrottled}},
+				},
+			},
+		},
+	}
+	resURN := p.NewURN("pkgA:m:typA", "resA", "")
+
+	// The create succeeds on its third attempt, and each retry is reported as a warning.
+	p.Steps = []TestStep{{
+		Op:          Update,
+		SkipPreview: true,
+		Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			retries := 0
+			for _, e := range events {
+				if e.Type == DiagEvent {
+					p := e.Payload.(DiagEventPayload)
+					if p.URN == resURN && p.Severity == diag.Warning && strings.Contains(p.Message, "slow down") {
+						retries++
+					}
+				}
+			}
+			assert.Equal(t, 2, retries)
+			return err
+		},
+	}}
+	snap := p.Run(t, nil)
+	assert.Equal(t, 3, createAttempts)
+	assert.Len(t, snap.Resources, 2)
+
+	// The delete fails with an unavailable error, which the type's policy does not retry.
+	register = false
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}}
+	p.Run(t, snap)
+	assert.Equal(t, 1, deleteAttempts)
+}
+
+// Tests that resources may be renamed and reparented via aliases without being replaced.
+func TestAliases(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	parentName := "resA"
+	var parentAliases, childAliases []resource.URN
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		parent, _, _, err := monitor.RegisterResource("pkgA:m:typA", parentName, true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, parentAliases, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typB", "resB", true, parent, false, nil, "",
+			resource.PropertyMap{}, nil, false, false, childAliases, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	oldA := p.NewURN("pkgA:m:typA", "resA", "")
+	oldB := p.NewURN("pkgA:m:typB", "resB", oldA)
+	snap := p.Run(t, nil)
+
+	// Rename the parent, which also changes the child's URN. Both resources should be matched to their old states by
+	// their aliases, and nothing should be created or deleted.
+	parentName, parentAliases, childAliases = "resA2", []resource.URN{oldA}, []resource.URN{oldB}
+	newA := p.NewURN("pkgA:m:typA", "resA2", "")
+	newB := p.NewURN("pkgA:m:typB", "resB", newA)
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			for _, entry := range j.Entries {
+				assert.Equal(t, deploy.OpSame, entry.Step.Op())
+			}
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 3)
+	assert.Equal(t, newA, snap.Resources[1].URN)
+	assert.Equal(t, newB, snap.Resources[2].URN)
+	assert.Equal(t, newA, snap.Resources[2].Parent)
+
+	// Two resources may not claim the same old resource.
+	parentName, parentAliases, childAliases = "resA3", []resource.URN{newA}, []resource.URN{newA}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true, SkipPreview: true}}
+	p.Run(t, snap)
+}
+
+// Tests that a resource may not refer to a parent that has not been registered.
+func TestMissingParent(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	p := &TestPlan{}
+	missing := p.NewURN("pkgA:m:typComponent", "component", "")
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, missing, false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.Error(t, err)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p.Options = UpdateOptions{Host: host}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true, SkipPreview: true}}
+	snap := p.Run(t, nil)
+	for _, res := range snap.Resources {
+		assert.NotEqual(t, missing, res.Parent)
+	}
+}
+
+// Tests that a rollback restores the resources and inputs recorded by an earlier snapshot.
+func TestRollback(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	inputs, createB, createC := resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"}), true, false
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			inputs, nil, false, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		if createB {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, nil, "",
+				resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if createC {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, nil, "",
+				resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		}
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	urnB := p.NewURN("pkgA:m:typA", "resB", "")
+	urnC := p.NewURN("pkgA:m:typA", "resC", "")
+	first := p.Run(t, nil)
+
+	// Change resA's inputs, delete resB, and create resC.
+	inputs, createB, createC = resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "baz"}), false, true
+	second := p.Run(t, first)
+	assert.Len(t, second.Resources, 3)
+
+	// Rolling back to the first snapshot should update resA, re-create resB, and delete resC.
+	rollback := func(info UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (ResourceChanges, error) {
+		return Rollback(info, ctx, opts, first, dryRun)
+	}
+	p.Steps = []TestStep{{
+		Op: rollback,
+		Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			ops := make(map[resource.URN]deploy.StepOp)
+			for _, entry := range j.Entries {
+				if entry.Step.Op() != deploy.OpSame {
+					ops[entry.Step.URN()] = entry.Step.Op()
+				}
+			}
+			assert.Equal(t, map[resource.URN]deploy.StepOp{
+				urnA: deploy.OpUpdate,
+				urnB: deploy.OpCreate,
+				urnC: deploy.OpDelete,
+			}, ops)
+			return err
+		},
+	}}
+	third := p.Run(t, second)
+	assert.Len(t, third.Resources, 3)
+	for _, res := range third.Resources {
+		assert.NotEqual(t, urnC, res.URN)
+		if res.URN == urnA {
+			assert.Equal(t, first.Resources[1].Inputs, res.Inputs)
+		}
+	}
+}
+
+// Tests that rolling back re-computes the inputs of resources that depend on a resource that must be replaced.
+func TestRollbackReplacedDependency(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, _, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					if !olds["foo"].DeepEquals(news["foo"]) {
+						return plugin.DiffResult{ReplaceKeys: []resource.PropertyKey{"foo"}}, nil
+					}
+					return plugin.DiffResult{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	foo, prefix := "bar", ""
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, idA, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo}), nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{urnA}, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"a": prefix + string(idA)}),
+			map[resource.PropertyKey][]resource.URN{"a": {urnA}}, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	urnB := p.NewURN("pkgA:m:typA", "resB", "")
+	first := p.Run(t, nil)
+
+	// Replace resA, which gives it a new ID.
+	foo = "baz"
+	second := p.Run(t, first)
+
+	// Rolling back to the first snapshot replaces resA again. resB must refer to its newest ID, not the one that the
+	// first snapshot recorded.
+	rollback := func(info UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (ResourceChanges, error) {
+		return Rollback(info, ctx, opts, first, dryRun)
+	}
+	p.Steps = []TestStep{{Op: rollback}}
+	third := p.Run(t, second)
+	var idA resource.ID
+	var inputsB resource.PropertyMap
+	for _, res := range third.Resources {
+		switch res.URN {
+		case urnA:
+			idA = res.ID
+		case urnB:
+			inputsB = res.Inputs
+		}
+	}
+	assert.NotEqual(t, first.Resources[1].ID, idA)
+	assert.Equal(t, resource.NewStringProperty(string(idA)), inputsB["a"])
+
+	// If resB's input was computed from resA's ID in a way that cannot be undone, rolling back must fail.
+	foo, prefix = "bar", "prefix-"
+	p.Steps = []TestStep{{Op: Update}}
+	first = p.Run(t, third)
+	foo = "baz"
+	second = p.Run(t, first)
+	p.Steps = []TestStep{{Op: rollback, ExpectFailure: true}}
+	p.Run(t, second)
+}
+
+// Tests that violations of mandatory policies block an update and that violations of advisory policies do not.
+func TestPolicyViolations(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"cidr": "0.0.0.0/0"}), nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	noOpenIngress := func(level policy.EnforcementLevel) policy.Pack {
+		return policy.NewPack("network", policy.Policy{
+			Name:             "no-open-ingress",
+			EnforcementLevel: level,
+			Validate: func(res policy.Resource) ([]policy.Violation, error) {
+				if cidr, ok := res.Properties["cidr"]; ok && cidr.IsString() && cidr.StringValue() == "0.0.0.0/0" {
+					return []policy.Violation{{Message: "ingress is open to the world", Property: "cidr"}}, nil
+				}
+				return nil, nil
+			},
+		})
+	}
+
+	// An advisory violation should not prevent the resource from being created.
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host, Policies: []policy.Pack{noOpenIngress(policy.Advisory)}},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+
+	// A mandatory violation should fail both the preview and the update.
+	p.Options.Policies = []policy.Pack{noOpenIngress(policy.Mandatory)}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, nil)
+
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true, SkipPreview: true}}
+	if snap = p.Run(t, nil); snap != nil {
+		for _, res := range snap.Resources {
+			assert.NotEqual(t, tokens.Type("pkgA:m:typA"), res.Type)
+		}
+	}
+}
+
+// Tests that stack and parent transformations are applied to resources before they are planned.
+func TestTransformations(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		comp, _, _, err := monitor.RegisterResource("pkgA:m:typComponent", "comp", false, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, comp, false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{}
+	comp := p.NewURN("pkgA:m:typComponent", "comp", "")
+	urnA := p.NewURN("pkgA:m:typA", "resA", comp)
+	urnB := p.NewURN("pkgA:m:typA", "resB", "")
+
+	// Tag every custom resource in the stack, and protect the component's children.
+	tag := func(goal *resource.Goal) error {
+		if goal.Custom && !providers.IsProviderType(goal.Type) {
+			goal.Properties["tag"] = resource.NewStringProperty("stack")
+		}
+		return nil
+	}
+	protect := func(goal *resource.Goal) error {
+		goal.Protect = true
+		return nil
+	}
+	p.Options = UpdateOptions{
+		Host: host,
+		Transformations: &deploy.Transformations{
+			Stack:   []deploy.ResourceTransformation{tag},
+			Parents: map[resource.URN][]deploy.ResourceTransformation{comp: {protect}},
+		},
+	}
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	tagged := resource.PropertyMap{"tag": resource.NewStringProperty("stack")}
+	found := 0
+	for _, res := range snap.Resources {
+		switch res.URN {
+		case comp:
+			assert.False(t, res.Protect)
+			assert.Equal(t, resource.PropertyMap{}, res.Inputs)
+		case urnA:
+			assert.True(t, res.Protect)
+			assert.Equal(t, tagged, res.Inputs)
+		case urnB:
+			assert.False(t, res.Protect)
+			assert.Equal(t, tagged, res.Inputs)
+		default:
+			continue
+		}
+		found++
+	}
+	assert.Equal(t, 3, found)
+
+	// A transformation may not change a resource's type.
+	p.Options.Transformations = &deploy.Transformations{
+		Stack: []deploy.ResourceTransformation{func(goal *resource.Goal) error {
+			goal.Type = "pkgA:m:typB"
+			return nil
+		}},
+	}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true, SkipPreview: true}}
+	p.Run(t, nil)
+}
+
+// Tests that errors planning a transformed resource are reported against its transformed URN.
+func TestTransformationErrorURN(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CheckF: func(urn resource.URN,
+					olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					return nil, nil, errors.New("oh no, check had an error")
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.Error(t, err)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{}
+	renamed := p.NewURN("pkgA:m:typA", "resB", "")
+
+	p.Options = UpdateOptions{
+		Host: host,
+		Transformations: &deploy.Transformations{
+			Stack: []deploy.ResourceTransformation{func(goal *resource.Goal) error {
+				if !providers.IsProviderType(goal.Type) {
+					goal.Name = "resB"
+				}
+				return nil
+			}},
+		},
+	}
+	p.Steps = []TestStep{{
+		Op:            Update,
+		ExpectFailure: true,
+		SkipPreview:   true,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal, evts []Event, err error) error {
+			sawFailure := false
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					e := evt.Payload.(DiagEventPayload)
+					if e.Severity == diag.Error && strings.Contains(colors.Never.Colorize(e.Message), "oh no") {
+						assert.Equal(t, renamed, e.URN)
+						sawFailure = true
+					}
+				}
+			}
+			assert.True(t, sawFailure)
+			return err
+		},
+	}}
+	p.Run(t, nil)
+}
+
+// Tests that a stack's default tags are merged into taggable resources, and that changing them alone does not cause
+// resources to be updated.
+func TestDefaultTags(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	foo := "bar"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo}), nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	tagsKey := config.MustMakeKey("pulumi", "tags")
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host:          host,
+			TaggableTypes: map[tokens.Type]resource.PropertyKey{"pkgA:m:typA": "tags"},
+		},
+		Config: config.Map{tagsKey: config.NewValue(`{"env":"dev"}`)},
+		Steps:  []TestStep{{Op: Update}},
+	}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	inputsA := func(snap *deploy.Snapshot) resource.PropertyMap {
+		for _, res := range snap.Resources {
+			if res.URN == urnA {
+				return res.Inputs
+			}
+		}
+		return nil
+	}
+
+	snap := p.Run(t, nil)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":  "bar",
+		"tags": map[string]interface{}{"env": "dev"},
+	}), inputsA(snap))
+
+	// Changing only the default tags should not update the resource.
+	p.Config[tagsKey] = config.NewValue(`{"env":"prod"}`)
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			for _, entry := range j.Entries {
+				assert.Equal(t, deploy.OpSame, entry.Step.Op())
+			}
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":  "bar",
+		"tags": map[string]interface{}{"env": "dev"},
+	}), inputsA(snap))
+
+	// Once something else changes, the new default tags are applied as well.
+	foo = "baz"
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":  "baz",
+		"tags": map[string]interface{}{"env": "prod"},
+	}), inputsA(snap))
+}
+
+// Tests that reordering the elements of an array that is treated as a set does not cause the resource to be updated.
+func TestUnorderedProperties(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	rules := []interface{}{"80", "443", "22"}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"rules": rules}), nil, false, false, nil, nil, nil, nil, nil,
+			nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host:                host,
+			UnorderedProperties: map[tokens.Type][]resource.PropertyPath{"pkgA:m:typA": {{"rules"}}},
+		},
+		Steps: []TestStep{{Op: Update}},
+	}
+	expectOps := func(op deploy.StepOp) ValidateFunc {
+		return func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			for _, entry := range j.Entries {
+				if entry.Step.URN().Type() == "pkgA:m:typA" {
+					assert.Equal(t, op, entry.Step.Op())
+				}
+			}
+			return err
+		}
+	}
+	snap := p.Run(t, nil)
+
+	// Reordering the rules is not a change.
+	rules = []interface{}{"22", "443", "80"}
+	p.Steps = []TestStep{{Op: Update, Validate: expectOps(deploy.OpSame)}}
+	snap = p.Run(t, snap)
+
+	// Changing their contents still is.
+	rules = []interface{}{"22", "443"}
+	p.Steps = []TestStep{{Op: Update, Validate: expectOps(deploy.OpUpdate)}}
+	p.Run(t, snap)
+}
+
+// Tests that changes to normalized properties that do not change their normalized values are not reported as diffs.
+func TestPropertyNormalizers(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	policy := `{"Version": "2012-10-17", "Statement": []}`
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"policy": policy}), nil, false, false, nil, nil, nil, nil, nil,
+			nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host: host,
+			PropertyNormalizers: map[tokens.Type][]deploy.PropertyNormalizer{
+				"pkgA:m:typA": {{Path: resource.PropertyPath{"policy"}, Normalize: deploy.NormalizeJSON}},
+			},
+		},
+		Steps: []TestStep{{Op: Update}},
+	}
+	expectOp := func(op deploy.StepOp, policy string) ValidateFunc {
+		return func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			for _, entry := range j.Entries {
+				if entry.Step.URN().Type() == "pkgA:m:typA" {
+					assert.Equal(t, op, entry.Step.Op())
+					assert.Equal(t, policy, entry.Step.New().Inputs["policy"].StringValue())
+				}
+			}
+			return err
+		}
+	}
+	snap := p.Run(t, nil)
+
+	// Reformatting the policy is not a change, and the old policy is kept.
+	old := policy
+	policy = `{"Statement":[],"Version":"2012-10-17"}`
+	p.Steps = []TestStep{{Op: Update, Validate: expectOp(deploy.OpSame, old)}}
+	snap = p.Run(t, snap)
+
+	// Changing its contents still is.
+	policy = `{"Statement":[],"Version":"2008-10-17"}`
+	p.Steps = []TestStep{{Op: Update, Validate: expectOp(deploy.OpUpdate, policy)}}
+	p.Run(t, snap)
+}
+
+// Tests that properties that hold JSON documents are compared by their documents rather than by their text.
+func TestJSONProperties(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	policy := `{"Version": "2012-10-17", "Statement": []}`
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"policy": policy}), nil, false, false, nil, nil, nil, nil, nil,
+			nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host:           host,
+			JSONProperties: map[tokens.Type][]resource.PropertyPath{"pkgA:m:typA": {{"policy"}}},
+		},
+		Steps: []TestStep{{Op: Update}},
+	}
+	expectOp := func(op deploy.StepOp) ValidateFunc {
+		return func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			for _, entry := range j.Entries {
+				if entry.Step.URN().Type() == "pkgA:m:typA" {
+					assert.Equal(t, op, entry.Step.Op())
+					assert.True(t, entry.Step.New().Inputs["policy"].IsJSON())
+				}
+			}
+			return err
+		}
+	}
+	snap := p.Run(t, nil)
+
+	// Reformatting the policy is not a change.
+	policy = `{"Statement":[],"Version":"2012-10-17"}`
+	p.Steps = []TestStep{{Op: Update, Validate: expectOp(deploy.OpSame)}}
+	snap = p.Run(t, snap)
+
+	// Changing its contents still is.
+	policy = `{"Statement":[],"Version":"2008-10-17"}`
+	p.Steps = []TestStep{{Op: Update, Validate: expectOp(deploy.OpUpdate)}}
+	p.Run(t, snap)
+}
+
+// Tests that resources, with and without providers, may take file references as inputs, and that a reference is not a
+// change from the string that it refers to.
+func TestFileRefProperties(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileref")
+	assert.NoError(t, err)
+	defer func() { contract.IgnoreError(os.RemoveAll(dir)) }()
+
+	const contents = "-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"
+	path := filepath.Join(dir, "cert.pem")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	sum := sha256.Sum256([]byte(contents))
+	ref := resource.NewFileRefProperty(path, hex.EncodeToString(sum[:]))
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	cert := ref
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		// File references are registered by their serialized form, since the monitor would otherwise send their
+		// contents.
+		inputs := resource.PropertyMap{"cert": cert}
+		if cert.IsFileRef() {
+			inputs["cert"] = resource.NewObjectProperty(resource.NewPropertyMapFromMap(cert.FileRefValue().Serialize()))
+		}
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", inputs, nil, false,
+			false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("my:comp:Comp", "compA", false, "", false, nil, "", inputs, nil, false,
+			false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	expectSame := func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+		for _, entry := range j.Entries {
+			if !providers.IsProviderType(entry.Step.URN().Type()) {
+				assert.Equal(t, deploy.OpSame, entry.Step.Op())
+			}
+		}
+		return err
+	}
+	snap := p.Run(t, nil)
+	for _, res := range snap.Resources {
+		if !providers.IsProviderType(res.Type) {
+			assert.True(t, res.Inputs["cert"].IsFileRef())
+		}
+	}
+
+	// Registering the same reference again is not a change, and neither is registering the string that it refers to.
+	p.Steps = []TestStep{{Op: Update, Validate: expectSame}}
+	snap = p.Run(t, snap)
+	cert = resource.NewStringProperty(contents)
+	p.Run(t, snap)
+}
+
+// Tests that changes to ignored properties are not applied, and that the ignored properties keep their old values.
+func TestIgnoreChanges(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	var ignoreChanges []string
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			inputs, nil, false, false, nil, ignoreChanges, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{Host: host}}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	run := func(snap *deploy.Snapshot, props map[string]interface{}, ignore []string,
+		expectedOp deploy.StepOp, expected map[string]interface{}) *deploy.Snapshot {
+
+		inputs, ignoreChanges = resource.NewPropertyMapFromMap(props), ignore
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+				for _, entry := range j.Entries {
+					if entry.Step.URN() == urnA {
+						assert.Equal(t, expectedOp, entry.Step.Op())
+					}
+				}
+				return err
+			},
+		}}
+		snap = p.Run(t, snap)
+		for _, res := range snap.Resources {
+			if res.URN == urnA {
+				assert.Equal(t, resource.NewPropertyMapFromMap(expected), res.Inputs)
+			}
+		}
+		return snap
+	}
+
+	snap := run(nil, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "foo"}}, nil,
+		deploy.OpCreate, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "foo"}})
+
+	// Changes to an ignored property are not applied, but other changes are.
+	snap = run(snap, map[string]interface{}{"a": 2, "b": map[string]interface{}{"c": "bar"}}, []string{"a"},
+		deploy.OpUpdate, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "bar"}})
+
+	// If every change is ignored, the resource is left as it is. Ignored properties that have no old value are removed.
+	snap = run(snap, map[string]interface{}{"a": 3, "b": map[string]interface{}{"c": "baz"}, "d": true},
+		[]string{"a", "b.c", "d"},
+		deploy.OpSame, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "bar"}})
+
+	// Invalid paths are rejected.
+	inputs, ignoreChanges = resource.PropertyMap{}, []string{"a..b"}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true, SkipPreview: true}}
+	p.Run(t, snap)
+}
+
+// Tests that changes to properties that force a replacement cause a replacement rather than an in-place update.
+func TestReplaceOnChanges(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	var replaceOnChanges []string
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			inputs, nil, false, false, nil, nil, replaceOnChanges, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{Host: host}}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	run := func(snap *deploy.Snapshot, props map[string]interface{}, replace []string,
+		expectedKeys []resource.PropertyKey) *deploy.Snapshot {
+
+		inputs, replaceOnChanges = resource.NewPropertyMapFromMap(props), replace
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+				var keys []resource.PropertyKey
+				replaced := false
+				for _, entry := range j.Entries {
+					if entry.Step.URN() != urnA {
+						continue
+					}
+					switch entry.Step.Op() {
+					case deploy.OpReplace:
+						replaced, keys = true, entry.Step.(*deploy.ReplaceStep).Keys()
+					case deploy.OpUpdate, deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpDeleteReplaced:
+					default:
+						assert.Fail(t, "unexpected step", "%v", entry.Step.Op())
+					}
+				}
+				assert.Equal(t, expectedKeys != nil, replaced)
+				assert.Equal(t, expectedKeys, keys)
+				return err
+			},
+		}}
+		return p.Run(t, snap)
+	}
+
+	snap := run(nil, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "foo"}}, []string{"b.c"}, nil)
+
+	// Changes to other properties are applied in place.
+	snap = run(snap, map[string]interface{}{"a": 2, "b": map[string]interface{}{"c": "foo"}}, []string{"b.c"}, nil)
+
+	// Changes to the listed properties force a replacement.
+	snap = run(snap, map[string]interface{}{"a": 2, "b": map[string]interface{}{"c": "bar"}}, []string{"b.c"},
+		[]resource.PropertyKey{"b.c"})
+
+	// `*` forces a replacement on any change.
+	run(snap, map[string]interface{}{"a": 3, "b": map[string]interface{}{"c": "bar"}}, []string{"*"},
+		[]resource.PropertyKey{"*"})
+}
+
+// Tests that resources marked for replacement are replaced even though they have not changed, along with any dependents
+// whose inputs would change as a result.
+func TestReplaceTargets(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult, error) {
+
+					if newInputs["a"].IsComputed() {
+						return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"a"}}, nil
+					}
+					return plugin.DiffResult{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, true, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{urnA}, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"a": "x"}),
+			map[resource.PropertyKey][]resource.URN{"a": {urnA}}, false, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{Host: host}, Steps: []TestStep{{Op: Update}}}
+	urnA, urnB, urnC := p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typA", "resB", ""),
+		p.NewURN("pkgA:m:typA", "resC", "")
+	snap := p.Run(t, nil)
+
+	// Replacing resA also replaces resB, whose input depends on it, but leaves resC alone. resA is replaced even when
+	// the update is restricted to other resources.
+	for _, targets := range [][]resource.URN{nil, {urnB, urnC}} {
+		p.Options.Targets, p.Options.ReplaceTargets = targets, []resource.URN{urnA}
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+				replaced := make(map[resource.URN]bool)
+				for _, entry := range j.Entries {
+					if entry.Step.Op() == deploy.OpReplace {
+						replaced[entry.Step.URN()] = true
+					}
+				}
+				assert.Equal(t, map[resource.URN]bool{urnA: true, urnB: true}, replaced)
+				return err
+			},
+		}}
+		snap = p.Run(t, snap)
+	}
+}
+
+func TestDetailedDiff(t *testing.T) {
+	var detailedDiff map[string]plugin.PropertyDiff
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult, error) {
+
+					return plugin.DiffResult{DetailedDiff: detailedDiff}, nil
+				},
+			}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			inputs, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{Host: host}}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	run := func(snap *deploy.Snapshot, props map[string]interface{}, expectedOp deploy.StepOp,
+		expectedKeys, expectedDiffs []resource.PropertyKey) *deploy.Snapshot {
+
+		inputs = resource.NewPropertyMapFromMap(props)
+		p.Steps = []TestStep{{
+			Op: Update,
+			Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+				var ops []deploy.StepOp
+				for _, e := range events {
+					if e.Type != ResourcePreEvent {
+						continue
+					}
+					md := e.Payload.(ResourcePreEventPayload).Metadata
+					if md.URN != urnA || md.Op == deploy.OpCreateReplacement || md.Op == deploy.OpDeleteReplaced {
+						continue
+					}
+					ops = append(ops, md.Op)
+					if md.Op == deploy.OpUpdate || md.Op == deploy.OpReplace {
+						assert.Equal(t, expectedKeys, md.Keys)
+						assert.Equal(t, expectedDiffs, md.Diffs)
+						assert.Equal(t, detailedDiff, md.DetailedDiff)
+					}
+				}
+				assert.Equal(t, []deploy.StepOp{expectedOp}, ops)
+				return err
+			},
+		}}
+		return p.Run(t, snap)
+	}
+
+	snap := run(nil, map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "foo"}}, deploy.OpCreate,
+		nil, nil)
+
+	// The provider's detailed diff determines which properties are reported as changed, even if other inputs differ.
+	detailedDiff = map[string]plugin.PropertyDiff{"b.c": {Kind: plugin.DiffUpdate}}
+	snap = run(snap, map[string]interface{}{"a": 2, "b": map[string]interface{}{"c": "bar"}}, deploy.OpUpdate,
+		nil, []resource.PropertyKey{"b"})
+
+	// A replacement kind in the detailed diff causes a replacement even if the provider reports no replace keys.
+	detailedDiff = map[string]plugin.PropertyDiff{
+		"a":   {Kind: plugin.DiffUpdateReplace},
+		"b.c": {Kind: plugin.DiffUpdate},
+	}
+	snap = run(snap, map[string]interface{}{"a": 3, "b": map[string]interface{}{"c": "baz"}}, deploy.OpReplace,
+		[]resource.PropertyKey{"a"}, []resource.PropertyKey{"a", "b"})
+
+	// An empty detailed diff means that there are no changes.
+	detailedDiff = map[string]plugin.PropertyDiff{}
+	run(snap, map[string]interface{}{"a": 4, "b": map[string]interface{}{"c": "baz"}}, deploy.OpSame, nil, nil)
+}
+
+func TestInvokeUnknownArgs(t *testing.T) {
+	invokes := 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				InvokeF: func(tok tokens.ModuleMember,
+					args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					invokes++
+					return resource.PropertyMap{"id": args["name"]}, nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		// Invokes with known arguments are passed to the provider.
+		outs, failures, err := monitor.Invoke("pkgA:m:funcA", resource.PropertyMap{
+			"name": resource.NewStringProperty("foo"),
+		}, "")
+		assert.NoError(t, err)
+		assert.Empty(t, failures)
+		assert.Equal(t, resource.PropertyMap{"id": resource.NewStringProperty("foo")}, outs)
+
+		// Invokes with unknown arguments are not, and their results are unknown.
+		outs, failures, err = monitor.Invoke("pkgA:m:funcA", resource.PropertyMap{
+			"name": resource.MakeComputed(resource.NewStringProperty("")),
+		}, "")
+		assert.NoError(t, err)
+		assert.Empty(t, failures)
+		assert.Nil(t, outs)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	p.Run(t, nil)
+	assert.Equal(t, 1, invokes)
+}
+
+func TestProviderDefaults(t *testing.T) {
+	checks := 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				// Choose a fresh default name on every check.
+				CheckF: func(urn resource.URN,
+					olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+					checks++
+					if !news.HasValue("name") {
+						news = news.Copy()
+						news["name"] = resource.NewStringProperty(fmt.Sprintf("name-%d", checks))
+					}
+					return news, nil, nil
+				},
+			}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			inputs, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{Options: UpdateOptions{Host: host}}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	run := func(snap *deploy.Snapshot, props map[string]interface{}, expectedOp deploy.StepOp,
+		expectedName string, expectedDefaults []resource.PropertyKey) *deploy.Snapshot {
+
+		inputs = resource.NewPropertyMapFromMap(props)
+		p.Steps = []TestStep{{
+			Op:          Update,
+			SkipPreview: true,
+			Validate: func(_ workspace.Project, target deploy.Target, j *Journal, _ []Event, err error) error {
+				for _, entry := range j.Entries {
+					if entry.Step.URN() == urnA {
+						assert.Equal(t, expectedOp, entry.Step.Op())
+					}
+				}
+				for _, res := range j.Snap(target.Snapshot).Resources {
+					if res.URN == urnA {
+						assert.Equal(t, resource.NewStringProperty(expectedName), res.Inputs["name"])
+						assert.Equal(t, expectedDefaults, res.Defaults)
+					}
+				}
+				return err
+			},
+		}}
+		return p.Run(t, snap)
+	}
+
+	snap := run(nil, map[string]interface{}{"a": 1}, deploy.OpCreate, "name-1", []resource.PropertyKey{"name"})
+
+	// The provider's new default does not cause a diff; the old default is kept.
+	snap = run(snap, map[string]interface{}{"a": 1}, deploy.OpSame, "name-1", []resource.PropertyKey{"name"})
+	snap = run(snap, map[string]interface{}{"a": 2}, deploy.OpUpdate, "name-1", []resource.PropertyKey{"name"})
+
+	// A value supplied by the program replaces the default.
+	snap = run(snap, map[string]interface{}{"a": 2, "name": "foo"}, deploy.OpUpdate, "foo", nil)
+
+	// Once the program stops supplying the value, the provider's default is used again.
+	run(snap, map[string]interface{}{"a": 2}, deploy.OpUpdate, "name-5", []resource.PropertyKey{"name"})
+}
+
+// Tests that auto-named resources keep their generated names across updates, and get fresh ones when replaced.
+func TestAutoNaming(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			inputs, nil, false, false, nil, nil, []string{"b"}, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host:           host,
+			AutoNamedTypes: map[tokens.Type]resource.PropertyKey{"pkgA:m:typA": "name"},
+		},
+		Config: config.Map{
+			config.MustMakeKey("pulumi", "autonaming"): config.NewValue(`{"length":4,"prefix":"dev-"}`),
+		},
+	}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	run := func(snap *deploy.Snapshot, props map[string]interface{}) (*deploy.Snapshot, string) {
+		inputs = resource.NewPropertyMapFromMap(props)
+		p.Steps = []TestStep{{Op: Update, SkipPreview: true}}
+		snap = p.Run(t, snap)
+		for _, res := range snap.Resources {
+			if res.URN == urnA {
+				assert.Equal(t, []resource.PropertyKey{"name"}, res.Defaults)
+				return snap, res.Inputs["name"].StringValue()
+			}
+		}
+		assert.Fail(t, "missing resource", "%v", urnA)
+		return snap, ""
+	}
+
+	snap, name := run(nil, map[string]interface{}{"a": 1, "b": 1})
+	assert.Regexp(t, "^dev-resA-[a-z0-9]{4}$", name)
+
+	// Updates keep the generated name.
+	snap, updated := run(snap, map[string]interface{}{"a": 2, "b": 1})
+	assert.Equal(t, name, updated)
+
+	// Replacements get a fresh one.
+	_, replaced := run(snap, map[string]interface{}{"a": 2, "b": 2})
+	assert.Regexp(t, "^dev-resA-[a-z0-9]{4}$", replaced)
+	assert.NotEqual(t, name, replaced)
+}
+
+func TestReadyConditions(t *testing.T) {
+	var readsUntilReady int
+	reads := 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outs := resource.PropertyMap{}
+					if urn.Name() == "resA" {
+						reads = 0
+						outs["status"] = resource.NewObjectProperty(resource.PropertyMap{
+							"phase": resource.NewStringProperty("Pending"),
+						})
+					} else {
+						// resB depends on resA, so it must not be created until resA is ready.
+						assert.Equal(t, readsUntilReady, reads)
+					}
+					return resource.ID(urn.Name()), outs, resource.StatusOK, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					reads++
+					phase := "Pending"
+					if reads >= readsUntilReady {
+						phase = "Running"
+					}
+					return resource.PropertyMap{
+						"status": resource.NewObjectProperty(resource.PropertyMap{
+							"phase": resource.NewStringProperty(phase),
+						}),
+						"reads": resource.NewNumberProperty(float64(reads)),
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, []string{"status.phase == Running"}, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{urnA}, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host, ReadyPollInterval: time.Millisecond, ReadyTimeout: time.Minute},
+	}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+	validate := func(ready bool) ValidateFunc {
+		return func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			waiting := 0
+			for _, e := range events {
+				if e.Type == DiagEvent && e.Payload.(DiagEventPayload).URN == urnA &&
+					strings.Contains(e.Payload.(DiagEventPayload).Message, "to become ready") {
+					waiting++
+				}
+			}
+			if ready {
+				assert.Equal(t, readsUntilReady, waiting)
+			}
+			return err
+		}
+	}
+
+	// resA becomes ready after three refreshes, and its refreshed outputs are recorded.
+	readsUntilReady = 3
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, Validate: validate(true)}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 3)
+	assert.Equal(t, "Running", snap.Resources[1].Outputs["status"].ObjectValue()["phase"].StringValue())
+
+	// If resA never becomes ready, the update fails, but resA is still recorded in the snapshot with the outputs that
+	// were last read.
+	readsUntilReady = math.MaxInt32
+	p.Options.ReadyTimeout = 10 * time.Millisecond
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true, Validate: validate(false)}}
+	snap = p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, urnA, snap.Resources[1].URN)
+	assert.Equal(t, float64(reads), snap.Resources[1].Outputs["reads"].NumberValue())
+}
+
+// Tests that the ready conditions that a provider declares for a resource type apply to the resources of that type
+// that register none of their own.
+func TestProviderReadyConditions(t *testing.T) {
+	reads := 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				GetSchemaF: func(version int) ([]byte, error) {
+					pkg := &schema.Package{
+						Name: "pkgA",
+						Resources: map[tokens.Type]*schema.Resource{
+							"pkgA:m:typA": {ReadyConditions: []string{"ready"}},
+						},
+					}
+					return pkg.Marshal()
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					reads++
+					return resource.PropertyMap{"ready": resource.NewBoolProperty(reads >= 2)}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typB", "resB", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host, ReadyPollInterval: time.Millisecond, ReadyTimeout: time.Minute},
+		Steps:   []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, 2, reads)
+	assert.Len(t, snap.Resources, 3)
+	assert.True(t, snap.Resources[1].Outputs["ready"].BoolValue())
+}
+
+// Tests that hooks run around the creation, update, and deletion of the resources they apply to, and that only the
+// failures of blocking hooks fail their steps.
+func TestResourceHooks(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	foo := "bar"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo}), nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	var events []string
+	record := deploy.Hook{
+		Resources: []string{"pkgA:m:typA"},
+		Events: []deploy.HookEvent{
+			deploy.BeforeCreate, deploy.AfterCreate, deploy.BeforeUpdate, deploy.AfterUpdate,
+			deploy.BeforeDelete, deploy.AfterDelete,
+		},
+		Callback: func(event deploy.HookEvent, urn resource.URN, props resource.PropertyMap) error {
+			events = append(events, fmt.Sprintf("%v %v %v", event, urn.Name(), props["foo"]))
+			return nil
+		},
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host, Hooks: []deploy.Hook{record}},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	assert.Equal(t, []string{"before-create resA bar", "after-create resA bar"}, events)
+
+	events, foo = nil, "baz"
+	snap = p.Run(t, snap)
+	assert.Equal(t, []string{"before-update resA baz", "after-update resA baz"}, events)
+
+	events = nil
+	p.Steps = []TestStep{{Op: Destroy}}
+	p.Run(t, snap)
+	assert.Equal(t, []string{"before-delete resA baz", "after-delete resA baz"}, events)
+
+	// A blocking hook that fails before a resource is created prevents its creation.
+	failing := deploy.Hook{
+		Name:   "migrate",
+		Events: []deploy.HookEvent{deploy.BeforeCreate},
+		Callback: func(deploy.HookEvent, resource.URN, resource.PropertyMap) error {
+			return errors.New("migration failed")
+		},
+	}
+	p.Options.Hooks = []deploy.Hook{failing}
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}}
+	snap = p.Run(t, nil)
+	for _, res := range snap.Resources {
+		assert.NotEqual(t, p.NewURN("pkgA:m:typA", "resA", ""), res.URN)
+	}
+
+	// An advisory hook's failure is only reported as a warning.
+	failing.Advisory = true
+	p.Options.Hooks = []deploy.Hook{failing}
+	p.Steps = []TestStep{{
+		Op:          Update,
+		SkipPreview: true,
+		Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, evts []Event, err error) error {
+			warned := false
+			for _, e := range evts {
+				if e.Type == DiagEvent && strings.Contains(e.Payload.(DiagEventPayload).Message, "migration failed") {
+					warned = true
+				}
+			}
+			assert.True(t, warned)
+			return err
+		},
+	}}
+	snap = p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+
+	// A resource whose after-delete hook fails has nonetheless been deleted, so it is removed from the snapshot.
+	failing.Advisory, failing.Events = false, []deploy.HookEvent{deploy.AfterDelete}
+	p.Options.Hooks = []deploy.Hook{failing}
+	p.Steps = []TestStep{{Op: Destroy, SkipPreview: true, ExpectFailure: true}}
+	snap = p.Run(t, snap)
+	for _, res := range snap.Resources {
+		assert.NotEqual(t, p.NewURN("pkgA:m:typA", "resA", ""), res.URN)
+	}
+}
+
+// Tests that a program may register hooks for its resources, and that such hooks may not run when resources are
+// deleted.
+func TestProgramResourceHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands in this test require a POSIX shell")
+	}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	dir, err := ioutil.TempDir("", "hooks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "events")
+
+	events := []string{"before-create", "after-create"}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, err := monitor.RegisterResourceRequest(&pulumirpc.RegisterResourceRequest{
+			Type:   "pkgA:m:typA",
+			Name:   "resA",
+			Custom: true,
+			Hooks: []*pulumirpc.RegisterResourceRequest_Hook{{
+				Events:  events,
+				Command: []string{"sh", "-c", `echo "$PULUMI_HOOK_EVENT" >> ` + out},
+			}},
+		})
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	p.Run(t, nil)
+	written, err := ioutil.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "before-create\nafter-create\n", string(written))
+
+	events = []string{"before-delete"}
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true}}
+	p.Run(t, nil)
+}
+
+// Tests that a resource's custom timeouts are recorded in its state, and that a provider operation that exceeds its
+// timeout fails its step and is left pending in the snapshot.
+func TestCustomTimeouts(t *testing.T) {
+	release := make(chan bool)
+	defer close(release)
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				UpdateF: func(urn resource.URN, id resource.ID,
+					oldInputs, oldOutputs, newInputs resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					<-release
+					return newInputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	foo := "bar"
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.NewPropertyMapFromMap(map[string]interface{}{"foo": foo}), nil, false, false, nil, nil, nil, nil,
+			&pulumirpc.RegisterResourceRequest_CustomTimeouts{Create: "1m", Update: "10ms"}, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+	}
+	urnA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	// The create completes well within its timeout, and the timeouts are recorded in resA's state.
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, urnA, snap.Resources[1].URN)
+	assert.Equal(t, resource.CustomTimeouts{Create: 60, Update: 0.01}, snap.Resources[1].CustomTimeouts)
+
+	// The update never completes, so it times out and is left pending.
+	foo = "baz"
+	p.Steps = []TestStep{{
+		Op:            Update,
+		SkipPreview:   true,
+		ExpectFailure: true,
+		Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			timedOut := false
+			for _, e := range events {
+				if e.Type == DiagEvent && strings.Contains(e.Payload.(DiagEventPayload).Message,
+					"timed out after 10ms while updating") {
+					timedOut = true
+				}
+			}
+			assert.True(t, timedOut)
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.PendingOperations, 1)
+	assert.Equal(t, urnA, snap.PendingOperations[0].Resource.URN)
+	assert.Equal(t, resource.OperationTypeUpdating, snap.PendingOperations[0].Type)
+}
+
+// Tests that a resource whose provider crashes while creating it fails cleanly, and that its creation is left pending
+// in the snapshot, since the provider may have created it before crashing.
+func TestProviderCrash(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					if urn.Name() == "resB" {
+						return "", nil, resource.StatusUnknown,
+							&plugin.ProviderCrashError{Pkg: "pkgA", Operation: "create of resB", Mutation: true}
+					}
+					return resource.ID(urn.Name()), resource.PropertyMap{}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		urnA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{urnA}, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps: []TestStep{{
+			Op:            Update,
+			SkipPreview:   true,
+			ExpectFailure: true,
+			Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+				crashed := false
+				for _, e := range events {
+					if e.Type == DiagEvent && strings.Contains(e.Payload.(DiagEventPayload).Message,
+						"the pkgA provider crashed during the create of resB") {
+						crashed = true
+					}
+				}
+				assert.True(t, crashed)
+				return err
+			},
+		}},
+	}
+	urnA, urnB := p.NewURN("pkgA:m:typA", "resA", ""), p.NewURN("pkgA:m:typA", "resB", "")
+
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, urnA, snap.Resources[1].URN)
+	assert.Len(t, snap.PendingOperations, 1)
+	assert.Equal(t, urnB, snap.PendingOperations[0].Resource.URN)
+	assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[0].Type)
+}
+
+// Tests that growing or shrinking an indexed resource collection only creates or deletes the members at the indices
+// that were added or removed, and that malformed indices are rejected.
+func TestIndexedCollections(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	count, extraName := 3, ""
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for i := 0; i < count; i++ {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", string(resource.IndexedName("web", i)), true, "",
+				false, nil, "", resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if extraName != "" {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", extraName, true, "", false, nil, "",
+				resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+			return err
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+
+	// expectOps returns a validation function that checks the operation applied to each member of the collection.
+	expectOps := func(expected map[int]deploy.StepOp) ValidateFunc {
+		return func(_ workspace.Project, _ deploy.Target, j *Journal, _ []Event, err error) error {
+			for _, entry := range j.Entries {
+				if entry.Kind != JournalEntrySuccess {
+					continue
+				}
+				_, index, ok := entry.Step.URN().CollectionIndex()
+				if !ok {
+					continue
+				}
+				assert.Equal(t, expected[index], entry.Step.Op(), "web[%d]", index)
+			}
+			return err
+		}
+	}
+
+	// Growing the collection creates only the new members.
+	count = 5
+	p.Steps = []TestStep{{Op: Update, Validate: expectOps(map[int]deploy.StepOp{
+		0: deploy.OpSame, 1: deploy.OpSame, 2: deploy.OpSame, 3: deploy.OpCreate, 4: deploy.OpCreate,
+	})}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 6)
+
+	// Shrinking it deletes only the removed members.
+	count = 2
+	p.Steps = []TestStep{{Op: Update, Validate: expectOps(map[int]deploy.StepOp{
+		0: deploy.OpSame, 1: deploy.OpSame, 2: deploy.OpDelete, 3: deploy.OpDelete, 4: deploy.OpDelete,
+	})}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 3)
+
+	// A second spelling of an existing index is rejected.
+	extraName = "web[01]"
+	p.Steps = []TestStep{{Op: Update, ExpectFailure: true, SkipPreview: true}}
+	p.Run(t, snap)
+}
+
+type costEstimatorFunc func(t tokens.Type, props resource.PropertyMap) (float64, bool, error)
+
+func (f costEstimatorFunc) EstimateCost(t tokens.Type, props resource.PropertyMap) (float64, bool, error) {
+	return f(t, props)
+}
+
+// Tests that an update's summary counts its resources by operation and type, and adds up their estimated costs.
+func TestPlanSummary(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	sizeB := 3.0
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{"size": resource.NewNumberProperty(2)}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, nil, "",
+			resource.PropertyMap{"size": resource.NewNumberProperty(sizeB)}, nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typB", "resC", true, "", false, nil, "",
+			resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	// Resources of type A cost 1.5 per unit of size; the estimator knows nothing of type B.
+	estimator := costEstimatorFunc(func(t tokens.Type, props resource.PropertyMap) (float64, bool, error) {
+		if t != "pkgA:m:typA" {
+			return 0, false, nil
+		}
+		return 1.5 * props["size"].NumberValue(), true, nil
+	})
+
+	// expectSummary returns a validation function that checks the summary of the update.
+	expectSummary := func(ops ResourceChanges, types map[tokens.Type]ResourceChanges, cost CostSummary) ValidateFunc {
+		return func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			var summary *PlanSummary
+			for _, e := range events {
+				if e.Type == SummaryEvent {
+					summary = e.Payload.(SummaryEventPayload).Summary
+				}
+			}
+			if assert.NotNil(t, summary) {
+				assert.Equal(t, ops, summary.Ops)
+				assert.Equal(t, types, summary.Types)
+				assert.Equal(t, &cost, summary.Cost)
+			}
+			return err
+		}
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host, CostEstimator: estimator},
+		Steps: []TestStep{{Op: Update, Validate: expectSummary(
+			ResourceChanges{deploy.OpCreate: 3},
+			map[tokens.Type]ResourceChanges{
+				"pkgA:m:typA": {deploy.OpCreate: 2},
+				"pkgA:m:typB": {deploy.OpCreate: 1},
+			},
+			CostSummary{Total: 7.5, Change: 7.5, Types: map[tokens.Type]float64{"pkgA:m:typA": 7.5}, Unestimated: 1},
+		)}},
+	}
+	snap := p.Run(t, nil)
+
+	// Growing resB changes the total by the difference in its cost.
+	sizeB = 5
+	p.Steps = []TestStep{{Op: Update, Validate: expectSummary(
+		ResourceChanges{deploy.OpSame: 2, deploy.OpUpdate: 1},
+		map[tokens.Type]ResourceChanges{
+			"pkgA:m:typA": {deploy.OpSame: 1, deploy.OpUpdate: 1},
+			"pkgA:m:typB": {deploy.OpSame: 1},
+		},
+		CostSummary{Total: 10.5, Change: 3, Types: map[tokens.Type]float64{"pkgA:m:typA": 10.5}, Unestimated: 1},
+	)}}
+	p.Run(t, snap)
+}
+
+// Tests that each output property records the provider operation that last set its value.
+func TestPropertyProvenance(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "id", resource.PropertyMap{
+						"size": news["size"],
+						"arn":  resource.NewStringProperty("arn:a"),
+					}, resource.StatusOK, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, olds, oldOuts,
+					news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					return resource.PropertyMap{"size": news["size"], "arn": oldOuts["arn"]}, resource.StatusOK, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					olds resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					outs := olds.Copy()
+					outs["status"] = resource.NewStringProperty("ready")
+					return outs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	size := 1.0
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{"size": resource.NewNumberProperty(size)}, nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	provenance := func(snap *deploy.Snapshot) resource.PropertyProvenance {
+		for _, r := range snap.Resources {
+			if r.URN == resA {
+				return r.Provenance
+			}
+		}
+		return nil
+	}
+
+	// Creating the resource sets all of its outputs.
+	snap := p.Run(t, nil)
+	created := provenance(snap)
+	assert.Equal(t, "create", created["size"].Op)
+	assert.Equal(t, "create", created["arn"].Op)
+	assert.False(t, created["arn"].Time.IsZero())
+
+	// Updating it attributes only the outputs that changed to the update.
+	size = 2
+	snap = p.Run(t, snap)
+	updated := provenance(snap)
+	assert.Equal(t, "update", updated["size"].Op)
+	assert.Equal(t, created["arn"], updated["arn"])
+
+	// Refreshing it attributes the outputs that the provider reports as changed to the refresh.
+	p.Steps = []TestStep{{Op: Refresh}}
+	snap = p.Run(t, snap)
+	refreshed := provenance(snap)
+	assert.Equal(t, updated["size"], refreshed["size"])
+	assert.Equal(t, created["arn"], refreshed["arn"])
+	assert.Equal(t, "refresh", refreshed["status"].Op)
+}
+
+func TestRedactionPolicy(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	inputs := resource.PropertyMap{
+		"name":       resource.NewStringProperty("db"),
+		"dbPassword": resource.NewStringProperty("hunter2"),
+		"login": resource.NewObjectProperty(resource.PropertyMap{
+			"user":  resource.NewStringProperty("admin"),
+			"token": resource.NewStringProperty("abc123"),
+		}),
+	}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", inputs, nil,
+			false, false, nil, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	keys, err := resource.NewKeyPatternPolicy("*password*")
+	assert.NoError(t, err)
+	policy := resource.RedactionPolicies{
+		keys,
+		resource.SensitivePaths{"pkgA:m:typA": {{"login", "token"}}},
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host, RedactionPolicy: policy},
+		Steps: []TestStep{{
+			Op: Update,
+			Validate: func(_ workspace.Project, _ deploy.Target, j *Journal, events []Event, err error) error {
+				// Events see only digests of the sensitive values...
+				var sawResA bool
+				for _, e := range events {
+					if e.Type != ResourcePreEvent {
+						continue
+					}
+					md := e.Payload.(ResourcePreEventPayload).Metadata
+					if md.URN.Name() != "resA" {
+						continue
+					}
+					sawResA = true
+					news := md.New.Inputs
+					assert.Equal(t, resource.NewStringProperty("db"), news["name"])
+					assert.Equal(t, resource.RedactValue(inputs["dbPassword"]), news["dbPassword"])
+					login := news["login"].ObjectValue()
+					assert.Equal(t, resource.NewStringProperty("admin"), login["user"])
+					assert.Equal(t, resource.RedactValue(resource.NewStringProperty("abc123")), login["token"])
+				}
+				assert.True(t, sawResA)
+
+				// ...but the values themselves are what the provider and the snapshot receive.
+				for _, entry := range j.Entries {
+					if entry.Step.URN().Name() == "resA" {
+						assert.Equal(t, inputs, entry.Step.New().Inputs)
+					}
+				}
+				return err
+			},
+		}},
+	}
+	p.Run(t, nil)
+}
+
+// Tests that a stack that fans out to several instances evaluates its program once for each of them, with URNs,
+// default providers, and configuration of their own.
+func TestFanOut(t *testing.T) {
+	var lock sync.Mutex
+	regions := map[resource.URN]string{}
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			var region string
+			return &deploytest.Provider{
+				ConfigureF: func(news resource.PropertyMap) error {
+					region = news["region"].StringValue()
+					return nil
+				},
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					lock.Lock()
+					defer lock.Unlock()
+					regions[urn] = region
+					return resource.ID(urn.Name()), news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(info plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		instance := info.Config[deploy.InstanceConfigKey]
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{"instance": resource.NewStringProperty(instance)}, nil, false, false, nil, nil, nil, nil,
+			nil, nil)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Config: config.Map{
+			config.MustMakeKey("pkgA", "region"): config.NewValue("us-west-2"),
+			config.MustMakeKey("pulumi", "instances"): config.NewValue(
+				`[{"name":"east","config":{"pkgA:region":"us-east-1"}},{"name":"west"}]`),
+		},
+		Steps: []TestStep{{
+			Op: Update,
+			Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+				for _, e := range events {
+					if e.Type == SummaryEvent {
+						assert.Equal(t, map[string]ResourceChanges{
+							"east": {deploy.OpCreate: 1},
+							"west": {deploy.OpCreate: 1},
+						}, e.Payload.(SummaryEventPayload).Summary.Instances)
+					}
+				}
+				return err
+			},
+		}},
+	}
+	snap := p.Run(t, nil)
+
+	east, west := p.NewURN("pkgA:m:typA", "east@resA", ""), p.NewURN("pkgA:m:typA", "west@resA", "")
+	assert.Equal(t, map[resource.URN]string{east: "us-east-1", west: "us-west-2"}, regions)
+
+	providerURNs := map[resource.URN]resource.URN{}
+	for _, res := range snap.Resources {
+		if res.URN == east || res.URN == west {
+			ref, err := providers.ParseReference(res.Provider)
+			assert.NoError(t, err)
+			providerURNs[res.URN] = ref.URN()
+		}
+	}
+	assert.Equal(t, map[resource.URN]resource.URN{
+		east: p.NewProviderURN("pkgA", "east@default", ""),
+		west: p.NewProviderURN("pkgA", "west@default", ""),
+	}, providerURNs)
+
+	// Removing an instance deletes its resources.
+	p.Config[config.MustMakeKey("pulumi", "instances")] = config.NewValue(`[{"name":"west"}]`)
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	for _, res := range snap.Resources {
+		instance, _, _ := resource.SplitInstanceName(res.URN.Name())
+		assert.NotEqual(t, "east", instance)
+	}
+}
+
+// Tests that guarded resources are only deleted if the update is explicitly allowed to delete them.
+func TestGuardrails(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	register := true
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		if !register {
+			return nil
+		}
+		for _, typ := range []tokens.Type{"pkgA:m:typA", "pkgA:m:typB"} {
+			_, _, _, err := monitor.RegisterResource(typ, "res", true, "", false, nil, "",
+				resource.PropertyMap{}, nil, false, false, nil, nil, nil, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Config: config.Map{
+			config.MustMakeKey("pulumi", "guardrails"): config.NewValue(`[{"type":"pkgA:*:typA"}]`),
+		},
+		Steps: []TestStep{{Op: Update}},
+	}
+	snap := p.Run(t, nil)
+	guardedURN := p.NewURN("pkgA:m:typA", "res", "")
+
+	// A preview of the deletion succeeds, but warns about the guarded resource.
+	register = false
+	_, err := Update.Run(p.GetProject(), p.GetTarget(CloneSnapshot(t, snap)), p.Options, true, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			warned := false
+			for _, e := range events {
+				if e.Type == DiagEvent {
+					payload := e.Payload.(DiagEventPayload)
+					warned = warned || payload.URN == guardedURN && payload.Severity == diag.Warning
+				}
+			}
+			assert.True(t, warned)
+			return err
+		})
+	assert.NoError(t, err)
+
+	// The update itself fails without deleting anything.
+	p.Steps = []TestStep{{Op: Update, SkipPreview: true, ExpectFailure: true}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 3)
+
+	// Once the guarded resource is allowed, the update deletes it, and its delete is marked as guarded.
+	p.Options.AllowDestroy = []resource.URN{guardedURN}
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			guarded := map[resource.URN]bool{}
+			for _, e := range events {
+				if e.Type == ResourcePreEvent {
+					step := e.Payload.(ResourcePreEventPayload).Metadata
+					if step.Op == deploy.OpDelete {
+						guarded[step.URN] = step.Guarded
+					}
+				}
+			}
+			assert.Equal(t, map[resource.URN]bool{
+				guardedURN:                         true,
+				p.NewURN("pkgA:m:typB", "res", ""): false,
+			}, guarded)
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 0)
+}
+
+// Tests that the engine acts on the failures that providers describe: retryable failures are retried, authorization
+// failures are reported with their remediation, and deleting a resource that is not found succeeds.
+func TestProviderErrors(t *testing.T) {
+	var createAttempts int
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					// Fail the first attempt with a conflict that the provider reports as retryable.
+					createAttempts++
+					if createAttempts == 1 {
+						return "", nil, resource.StatusOK, &plugin.ProviderError{
+							Class:     plugin.ProviderErrorConflict,
+							Message:   "resource is busy",
+							Code:      "OperationInProgress",
+							Retryable: true,
+						}
+					}
+					return "created-id", news, resource.StatusOK, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+					newInputs resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+					return nil, resource.StatusOK, &plugin.ProviderError{
+						Class:       plugin.ProviderErrorAuth,
+						Message:     "access denied",
+						Remediation: "Grant the update permission to the deployment role.",
+					}
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					return resource.StatusOK, &plugin.ProviderError{Class: plugin.ProviderErrorNotFound, Message: "gone"}
+				},
+			}, nil
+		}),
+	}
+
+	register, size := true, 1.0
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		if register {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+				resource.PropertyMap{"size": resource.NewNumberProperty(size)}, nil, false, false, nil, nil, nil, nil,
+				nil, nil)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{
+			Host:    host,
+			Retries: &deploy.RetryPolicies{Default: &deploy.RetryPolicy{MaxAttempts: 3}},
+		},
+		Steps: []TestStep{{Op: Update, SkipPreview: true}},
+	}
+	resURN := p.NewURN("pkgA:m:typA", "resA", "")
+	diagMessages := func(events []Event, severity diag.Severity) []string {
+		var messages []string
+		for _, e := range events {
+			if e.Type == DiagEvent {
+				payload := e.Payload.(DiagEventPayload)
+				if payload.URN == resURN && payload.Severity == severity {
+					messages = append(messages, payload.Message)
+				}
+			}
+		}
+		return messages
+	}
+
+	// The create is retried once.
+	snap := p.Run(t, nil)
+	assert.Equal(t, 2, createAttempts)
+	assert.Len(t, snap.Resources, 2)
+
+	// The update fails with the provider's remediation.
+	size = 2
+	p.Steps = []TestStep{{
+		Op:            Update,
+		SkipPreview:   true,
+		ExpectFailure: true,
+		Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			errs := diagMessages(events, diag.Error)
+			if assert.NotEmpty(t, errs) {
+				assert.Contains(t, strings.Join(errs, "\n"), "Grant the update permission to the deployment role.")
+			}
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+
+	// The delete succeeds with a warning, since the resource is already gone.
+	register = false
+	p.Steps = []TestStep{{
+		Op:          Update,
+		SkipPreview: true,
+		Validate: func(_ workspace.Project, _ deploy.Target, _ *Journal, events []Event, err error) error {
+			assert.Len(t, diagMessages(events, diag.Warning), 1)
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 0)
+}
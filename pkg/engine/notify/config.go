@@ -0,0 +1,111 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"encoding/json"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+// ConfigKey is the configuration key that holds a stack's notification sinks as a JSON array of SinkConfigs. Since
+// webhook URLs often embed credentials, the value may be a secret.
+var ConfigKey = config.MustMakeKey("pulumi", "notifications")
+
+// SinkConfig configures a sink and the events that it is subscribed to.
+type SinkConfig struct {
+	Type      string          `json:"type"`                // the kind of sink: "webhook", "slack", or "sns".
+	URL       string          `json:"url,omitempty"`       // the URL of a webhook or Slack incoming webhook.
+	Topic     string          `json:"topic,omitempty"`     // the ARN of an SNS topic.
+	Region    string          `json:"region,omitempty"`    // the region of an SNS topic; defaults to AWS's default.
+	Template  string          `json:"template,omitempty"`  // an optional template for the payload or message.
+	Events    []Event         `json:"events,omitempty"`    // the events to notify.
+	Resources []string        `json:"resources,omitempty"` // the URNs or types of the resources to notify about.
+	Ops       []deploy.StepOp `json:"ops,omitempty"`       // the operations to notify about.
+}
+
+// Subscription creates the sink that the configuration describes and subscribes it to the configured events.
+func (c *SinkConfig) Subscription() (Subscription, error) {
+	for _, e := range c.Events {
+		switch e {
+		case UpdateStarted, UpdateSucceeded, UpdateFailed, ResourceChanged, ResourceFailed:
+		default:
+			return Subscription{}, errors.Errorf("unknown notification event '%v'", e)
+		}
+	}
+
+	var tmpl *template.Template
+	if c.Template != "" {
+		t, err := ParseTemplate(c.Template)
+		if err != nil {
+			return Subscription{}, errors.Wrap(err, "parsing notification template")
+		}
+		tmpl = t
+	}
+
+	var sink Sink
+	switch c.Type {
+	case "webhook", "slack":
+		if c.URL == "" {
+			return Subscription{}, errors.Errorf("%s notifications require a url", c.Type)
+		}
+		if c.Type == "webhook" {
+			sink = &WebhookSink{URL: c.URL, Template: tmpl}
+		} else {
+			sink = &SlackSink{WebhookURL: c.URL, Template: tmpl}
+		}
+	case "sns":
+		if c.Topic == "" {
+			return Subscription{}, errors.New("sns notifications require a topic")
+		}
+		s, err := NewSNSSink(c.Topic, c.Region, tmpl)
+		if err != nil {
+			return Subscription{}, err
+		}
+		sink = s
+	default:
+		return Subscription{}, errors.Errorf("unknown notification sink type '%v'", c.Type)
+	}
+
+	return Subscription{Sink: sink, Events: c.Events, Resources: c.Resources, Ops: c.Ops}, nil
+}
+
+// GetSubscriptions returns the notification subscriptions configured for the given target, if any.
+func GetSubscriptions(target *deploy.Target) ([]Subscription, error) {
+	c, has := target.Config[ConfigKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(target.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []SinkConfig
+	if err = json.Unmarshal([]byte(v), &configs); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON array of notification sinks", ConfigKey)
+	}
+	subs := make([]Subscription, len(configs))
+	for i := range configs {
+		if subs[i], err = configs[i].Subscription(); err != nil {
+			return nil, errors.Wrapf(err, "invalid %v", ConfigKey)
+		}
+	}
+	return subs, nil
+}
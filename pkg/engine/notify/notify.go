@@ -0,0 +1,282 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify sends notifications about the lifecycle of updates to sinks such as webhooks, Slack channels, and SNS
+// topics. A Notifier is fed an update's engine events; it notifies its subscribers when the update starts, succeeds,
+// or fails, and when the update applies steps to the resources that they are interested in.
+//
+// Notifications never include property values: the changes that they describe list only the names of the properties
+// that changed, and error messages are filtered of any secrets that they echo.
+package notify
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// Event identifies the occurrence that a notification describes.
+type Event string
+
+const (
+	UpdateStarted   Event = "update-started"   // an update has started.
+	UpdateSucceeded Event = "update-succeeded" // an update has finished successfully.
+	UpdateFailed    Event = "update-failed"    // an update has failed.
+	ResourceChanged Event = "resource-changed" // a step has been applied to a resource.
+	ResourceFailed  Event = "resource-failed"  // a step failed to apply to a resource.
+)
+
+// sendTimeout bounds how long a single notification may take to send.
+const sendTimeout = 30 * time.Second
+
+// queueSize is the number of notifications that may be waiting to be sent. Resource notifications that arrive while the
+// queue is full are dropped rather than holding up the update.
+const queueSize = 64
+
+// ResourceChange describes a step applied to a resource.
+type ResourceChange struct {
+	URN   resource.URN  `json:"urn"`             // the resource's URN.
+	Type  tokens.Type   `json:"type"`            // the resource's type.
+	Op    deploy.StepOp `json:"op"`              // the operation applied to the resource.
+	Diffs []string      `json:"diffs,omitempty"` // the names of the properties that changed.
+}
+
+// Notification is the payload sent to sinks.
+type Notification struct {
+	Event     Event                  `json:"event"`               // the occurrence being described.
+	Kind      string                 `json:"kind"`                // the kind of update, e.g. "update" or "destroy".
+	Stack     tokens.QName           `json:"stack"`               // the stack being updated.
+	Project   tokens.PackageName     `json:"project"`             // the stack's project.
+	Time      time.Time              `json:"time"`                // the time of the occurrence.
+	Error     string                 `json:"error,omitempty"`     // the update's error, for update-failed.
+	Changes   engine.ResourceChanges `json:"changes,omitempty"`   // the number of resources changed, by operation.
+	Resources []ResourceChange       `json:"resources,omitempty"` // the changes applied by a finished update.
+	Resource  *ResourceChange        `json:"resource,omitempty"`  // the change, for resource events.
+}
+
+// Sink sends notifications somewhere.
+type Sink interface {
+	// Send sends the given notification.
+	Send(ctx context.Context, n Notification) error
+}
+
+// Subscription subscribes a sink to a set of events.
+type Subscription struct {
+	Sink      Sink            // the sink to send notifications to.
+	Events    []Event         // the events to notify; if empty, the start, success, and failure of updates.
+	Resources []string        // the URNs or types of the resources whose events to notify; if empty, all resources.
+	Ops       []deploy.StepOp // the operations whose resource events to notify; if empty, all operations.
+}
+
+// wants returns true if the subscriber should be sent the given notification.
+func (s *Subscription) wants(n Notification) bool {
+	events := s.Events
+	if len(events) == 0 {
+		events = []Event{UpdateStarted, UpdateSucceeded, UpdateFailed}
+	}
+	if !containsEvent(events, n.Event) {
+		return false
+	}
+	if n.Resource == nil {
+		return true
+	}
+
+	matched := len(s.Resources) == 0
+	for _, r := range s.Resources {
+		if r == string(n.Resource.URN) || r == string(n.Resource.Type) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	if len(s.Ops) == 0 {
+		return true
+	}
+	for _, op := range s.Ops {
+		if op == n.Resource.Op {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEvent(events []Event, e Event) bool {
+	for _, x := range events {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Notifier is an engine.EventSubscriber that notifies its subscribers of the lifecycle of a single update. The update's
+// owner calls Started before the update begins and Finished once it ends. Notifications are sent in order, in the
+// background, so that slow sinks do not hold up the update; failures to send them are logged. If the sinks fall so far
+// behind that the queue fills, further resource notifications are dropped; the notification sent when the update
+// finishes still lists every change.
+type Notifier struct {
+	ctx  context.Context
+	subs []Subscription
+	base Notification // the fields that every notification shares.
+
+	changes []ResourceChange       // the changes applied so far.
+	counts  engine.ResourceChanges // the summary of the update's changes, once it is known.
+	queue   chan Notification      // the notifications waiting to be sent.
+	done    chan bool              // closed once every notification has been sent.
+	dropped int                    // the number of resource notifications dropped because the queue was full.
+}
+
+var _ engine.EventSubscriber = (*Notifier)(nil)
+
+// NewNotifier creates a notifier for an update of the given kind to the given stack.
+func NewNotifier(ctx context.Context, kind string, stack tokens.QName, project tokens.PackageName,
+	subs []Subscription) *Notifier {
+
+	n := &Notifier{
+		ctx:   ctx,
+		subs:  subs,
+		base:  Notification{Kind: kind, Stack: stack, Project: project},
+		queue: make(chan Notification, queueSize),
+		done:  make(chan bool),
+	}
+	go n.send()
+	return n
+}
+
+// send sends each queued notification to the subscribers that want it.
+func (n *Notifier) send() {
+	for note := range n.queue {
+		for i := range n.subs {
+			if !n.subs[i].wants(note) {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(n.ctx, sendTimeout)
+			if err := n.subs[i].Sink.Send(ctx, note); err != nil {
+				logging.Warningf("failed to send %s notification for stack %s: %v", note.Event, note.Stack, err)
+			}
+			cancel()
+		}
+	}
+	close(n.done)
+}
+
+// wanted returns true if any subscriber should be sent the given notification.
+func (n *Notifier) wanted(note Notification) bool {
+	for i := range n.subs {
+		if n.subs[i].wants(note) {
+			return true
+		}
+	}
+	return false
+}
+
+// notify queues a notification of the given event, if any subscriber wants it. If wait is false and the queue is
+// full, the notification is dropped.
+func (n *Notifier) notify(event Event, wait bool, fill func(note *Notification)) {
+	note := n.base
+	note.Event, note.Time = event, time.Now()
+	if fill != nil {
+		fill(&note)
+	}
+	if !n.wanted(note) {
+		return
+	}
+
+	if wait {
+		n.queue <- note
+		return
+	}
+	select {
+	case n.queue <- note:
+	default:
+		n.dropped++
+	}
+}
+
+// Started notifies subscribers that the update has started.
+func (n *Notifier) Started() {
+	n.notify(UpdateStarted, true, nil)
+}
+
+// Finished notifies subscribers that the update has finished, successfully if err is nil, and waits for every
+// notification to be sent.
+func (n *Notifier) Finished(err error) {
+	event := UpdateSucceeded
+	if err != nil {
+		event = UpdateFailed
+	}
+	n.notify(event, true, func(note *Notification) {
+		if err != nil {
+			note.Error = logging.FilterString(err.Error())
+		}
+		note.Changes, note.Resources = n.counts, n.changes
+	})
+	close(n.queue)
+	<-n.done
+
+	if n.dropped > 0 {
+		logging.Warningf("dropped %d resource notifications for stack %s because its sinks could not keep up",
+			n.dropped, n.base.Stack)
+	}
+}
+
+// isChange returns true if a step with the given operation changes its resource in a way that is worth reporting.
+// The steps that make up a replacement are summarized by the replacement itself.
+func isChange(op deploy.StepOp) bool {
+	switch op {
+	case deploy.OpSame, deploy.OpCreateReplacement, deploy.OpDeleteReplaced, deploy.OpDiscardReplaced,
+		deploy.OpRemovePendingReplace:
+		return false
+	}
+	return true
+}
+
+func makeResourceChange(step engine.StepEventMetadata) ResourceChange {
+	var diffs []string
+	for _, k := range step.Diffs {
+		diffs = append(diffs, string(k))
+	}
+	sort.Strings(diffs)
+	return ResourceChange{URN: step.URN, Type: step.Type, Op: step.Op, Diffs: diffs}
+}
+
+func (n *Notifier) OnPreStep(payload engine.ResourcePreEventPayload) {}
+
+func (n *Notifier) OnPostStep(payload engine.ResourceOutputsEventPayload) {
+	if payload.Planning || !isChange(payload.Metadata.Op) {
+		return
+	}
+	change := makeResourceChange(payload.Metadata)
+	n.changes = append(n.changes, change)
+	n.notify(ResourceChanged, false, func(note *Notification) { note.Resource = &change })
+}
+
+func (n *Notifier) OnStepFailed(payload engine.ResourceOperationFailedPayload) {
+	change := makeResourceChange(payload.Metadata)
+	n.notify(ResourceFailed, false, func(note *Notification) { note.Resource = &change })
+}
+
+func (n *Notifier) OnDiagnostic(payload engine.DiagEventPayload) {}
+
+func (n *Notifier) OnSummary(payload engine.SummaryEventPayload) {
+	n.counts = payload.ResourceChanges
+}
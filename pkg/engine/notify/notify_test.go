@@ -0,0 +1,252 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/engine/enginetest"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+const (
+	urnA = "urn:pulumi:dev::proj::pkgA:m:typA::resA"
+	urnB = "urn:pulumi:dev::proj::pkgA:m:typB::resB"
+)
+
+// recordingSink records the notifications that it is sent.
+type recordingSink struct {
+	notes []Notification
+}
+
+func (s *recordingSink) Send(ctx context.Context, n Notification) error {
+	s.notes = append(s.notes, n)
+	return nil
+}
+
+func events(notes []Notification) []Event {
+	var result []Event
+	for _, n := range notes {
+		result = append(result, n.Event)
+	}
+	return result
+}
+
+func postStep(op deploy.StepOp, urn resource.URN, diffs ...resource.PropertyKey) engine.Event {
+	return engine.Event{Type: engine.ResourceOutputsEvent, Payload: engine.ResourceOutputsEventPayload{
+		Metadata: engine.StepEventMetadata{Op: op, URN: urn, Type: urn.Type(), Diffs: diffs},
+	}}
+}
+
+func TestNotifier(t *testing.T) {
+	lifecycle, resources, updates := &recordingSink{}, &recordingSink{}, &recordingSink{}
+	n := NewNotifier(context.Background(), "update", "dev", "proj", []Subscription{
+		{Sink: lifecycle},
+		{Sink: resources, Events: []Event{ResourceChanged, ResourceFailed}, Resources: []string{"pkgA:m:typA"}},
+		{Sink: updates, Events: []Event{ResourceChanged}, Ops: []deploy.StepOp{deploy.OpUpdate}},
+	})
+
+	n.Started()
+	for _, e := range []engine.Event{
+		postStep(deploy.OpSame, urnA),
+		postStep(deploy.OpUpdate, urnA, "tags", "size"),
+		postStep(deploy.OpCreate, urnB),
+		postStep(deploy.OpCreateReplacement, urnB),
+		{Type: engine.ResourceOperationFailed, Payload: engine.ResourceOperationFailedPayload{
+			Metadata: engine.StepEventMetadata{Op: deploy.OpDelete, URN: urnA, Type: "pkgA:m:typA"},
+		}},
+		{Type: engine.SummaryEvent, Payload: engine.SummaryEventPayload{
+			ResourceChanges: engine.ResourceChanges{deploy.OpUpdate: 1, deploy.OpCreate: 1},
+		}},
+	} {
+		engine.DispatchEvent(e, n)
+	}
+	n.Finished(errors.New("oh no"))
+
+	assert.Equal(t, []Event{UpdateStarted, UpdateFailed}, events(lifecycle.notes))
+	failed := lifecycle.notes[1]
+	assert.Equal(t, "update", failed.Kind)
+	assert.Equal(t, "oh no", failed.Error)
+	assert.Equal(t, engine.ResourceChanges{deploy.OpUpdate: 1, deploy.OpCreate: 1}, failed.Changes)
+	assert.Equal(t, []ResourceChange{
+		{URN: urnA, Type: "pkgA:m:typA", Op: deploy.OpUpdate, Diffs: []string{"size", "tags"}},
+		{URN: urnB, Type: "pkgA:m:typB", Op: deploy.OpCreate},
+	}, failed.Resources)
+
+	assert.Equal(t, []Event{ResourceChanged, ResourceFailed}, events(resources.notes))
+	assert.Equal(t, deploy.OpUpdate, resources.notes[0].Resource.Op)
+	assert.Equal(t, deploy.OpDelete, resources.notes[1].Resource.Op)
+
+	assert.Equal(t, []Event{ResourceChanged}, events(updates.notes))
+	assert.Equal(t, resource.URN(urnA), updates.notes[0].Resource.URN)
+}
+
+// blockingSink records the notifications that it is sent, but waits for its channel to close before recording them.
+type blockingSink struct {
+	recordingSink
+	release chan bool
+}
+
+func (s *blockingSink) Send(ctx context.Context, n Notification) error {
+	<-s.release
+	return s.recordingSink.Send(ctx, n)
+}
+
+func TestNotifierSlowSinks(t *testing.T) {
+	// Resource notifications that no subscriber wants are not queued, so they are never dropped.
+	lifecycle := &blockingSink{release: make(chan bool)}
+	n := NewNotifier(context.Background(), "update", "dev", "proj", []Subscription{{Sink: lifecycle}})
+	n.Started()
+	for i := 0; i < 2*queueSize; i++ {
+		engine.DispatchEvent(postStep(deploy.OpCreate, urnA), n)
+	}
+	close(lifecycle.release)
+	n.Finished(nil)
+	assert.Equal(t, 0, n.dropped)
+	assert.Equal(t, []Event{UpdateStarted, UpdateSucceeded}, events(lifecycle.notes))
+
+	// A slow sink does not hold up the update: resource notifications that do not fit in the queue are dropped, but
+	// the update's lifecycle notifications are always sent.
+	resources := &blockingSink{release: make(chan bool)}
+	n = NewNotifier(context.Background(), "update", "dev", "proj", []Subscription{
+		{Sink: resources, Events: []Event{UpdateStarted, UpdateSucceeded, ResourceChanged}},
+	})
+	n.Started()
+	for i := 0; i < 2*queueSize; i++ {
+		engine.DispatchEvent(postStep(deploy.OpCreate, urnA), n)
+	}
+	close(resources.release)
+	n.Finished(nil)
+	assert.True(t, n.dropped > 0)
+	notes := resources.notes
+	assert.Equal(t, UpdateStarted, notes[0].Event)
+	assert.Equal(t, UpdateSucceeded, notes[len(notes)-1].Event)
+	assert.Len(t, notes[len(notes)-1].Resources, 2*queueSize)
+	assert.Equal(t, 2*queueSize, len(notes)-2+n.dropped)
+}
+
+func TestFormatNotification(t *testing.T) {
+	assert.Equal(t, "Destroy of stack dev started",
+		FormatNotification(Notification{Event: UpdateStarted, Kind: "destroy", Stack: "dev"}))
+	assert.Equal(t, "Update of stack dev succeeded (1 create, 1 update)\n"+
+		"• update `"+urnA+"`: `size`, `tags`\n"+
+		"• create `"+urnB+"`",
+		FormatNotification(Notification{
+			Event:   UpdateSucceeded,
+			Kind:    "update",
+			Stack:   "dev",
+			Changes: engine.ResourceChanges{deploy.OpSame: 3, deploy.OpUpdate: 1, deploy.OpCreate: 1},
+			Resources: []ResourceChange{
+				{URN: urnA, Op: deploy.OpUpdate, Diffs: []string{"size", "tags"}},
+				{URN: urnB, Op: deploy.OpCreate},
+			},
+		}))
+	assert.Equal(t, "Delete of resA in stack dev failed: access denied\n• delete `"+urnA+"`",
+		FormatNotification(Notification{
+			Event:    ResourceFailed,
+			Kind:     "update",
+			Stack:    "dev",
+			Error:    "access denied",
+			Resource: &ResourceChange{URN: urnA, Op: deploy.OpDelete},
+		}))
+}
+
+func TestWebhookSink(t *testing.T) {
+	var bodies [][]byte
+	server := enginetest.RecordJSONPosts(t, http.StatusOK, &bodies)
+	defer server.Close()
+
+	note := Notification{Event: UpdateSucceeded, Kind: "update", Stack: "dev", Project: "proj"}
+	assert.NoError(t, (&WebhookSink{URL: server.URL}).Send(context.Background(), note))
+
+	tmpl, err := ParseTemplate(`{"stack":{{json .Stack}},"message":{{json (summary .)}}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, (&WebhookSink{URL: server.URL, Template: tmpl}).Send(context.Background(), note))
+
+	assert.Equal(t, 2, len(bodies))
+	var sent Notification
+	assert.NoError(t, json.Unmarshal(bodies[0], &sent))
+	assert.Equal(t, note.Event, sent.Event)
+	assert.Equal(t, note.Stack, sent.Stack)
+	assert.Equal(t, `{"stack":"dev","message":"Update of stack dev succeeded"}`, string(bodies[1]))
+}
+
+func TestWebhookSinkFailure(t *testing.T) {
+	var bodies [][]byte
+	server := enginetest.RecordJSONPosts(t, http.StatusInternalServerError, &bodies)
+	defer server.Close()
+
+	err := (&WebhookSink{URL: server.URL + "/secret"}).Send(context.Background(), Notification{})
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestSlackSink(t *testing.T) {
+	var bodies [][]byte
+	server := enginetest.RecordJSONPosts(t, http.StatusOK, &bodies)
+	defer server.Close()
+
+	note := Notification{Event: UpdateStarted, Kind: "update", Stack: "dev"}
+	assert.NoError(t, (&SlackSink{WebhookURL: server.URL}).Send(context.Background(), note))
+	assert.Equal(t, 1, len(bodies))
+
+	var message struct {
+		Text string `json:"text"`
+	}
+	assert.NoError(t, json.Unmarshal(bodies[0], &message))
+	assert.Equal(t, "Update of stack dev started", message.Text)
+}
+
+func TestGetSubscriptions(t *testing.T) {
+	target := &deploy.Target{Config: config.Map{}}
+	subs, err := GetSubscriptions(target)
+	assert.NoError(t, err)
+	assert.Nil(t, subs)
+
+	target.Config[ConfigKey] = config.NewValue(`[
+		{"type":"webhook","url":"https://example.com/hook","events":["update-failed"]},
+		{"type":"slack","url":"https://hooks.slack.com/x","resources":["pkgA:m:typA"],"ops":["delete"]}
+	]`)
+	subs, err = GetSubscriptions(target)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(subs))
+	assert.Equal(t, &WebhookSink{URL: "https://example.com/hook"}, subs[0].Sink)
+	assert.Equal(t, []Event{UpdateFailed}, subs[0].Events)
+	assert.Equal(t, &SlackSink{WebhookURL: "https://hooks.slack.com/x"}, subs[1].Sink)
+	assert.Equal(t, []string{"pkgA:m:typA"}, subs[1].Resources)
+	assert.Equal(t, []deploy.StepOp{deploy.OpDelete}, subs[1].Ops)
+
+	for _, invalid := range []string{
+		`{}`,
+		`[{"type":"email"}]`,
+		`[{"type":"webhook"}]`,
+		`[{"type":"sns"}]`,
+		`[{"type":"slack","url":"https://hooks.slack.com/x","events":["update-exploded"]}]`,
+		`[{"type":"webhook","url":"https://example.com/hook","template":"{{.Stack"}]`,
+	} {
+		target.Config[ConfigKey] = config.NewValue(invalid)
+		_, err = GetSubscriptions(target)
+		assert.Error(t, err, invalid)
+	}
+}
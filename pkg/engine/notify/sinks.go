@@ -0,0 +1,233 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// templateFuncs are the functions available to notification templates, in addition to the standard ones.
+var templateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. to embed a string in a JSON payload.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// summary renders the notification as a short message, as FormatNotification does.
+	"summary": FormatNotification,
+}
+
+// ParseTemplate parses a notification template. Templates are executed with a Notification as their data.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("notification").Funcs(templateFuncs).Parse(text)
+}
+
+// render executes the given template for the notification, or if the template is nil, calls def.
+func render(tmpl *template.Template, n Notification, def func(Notification) ([]byte, error)) ([]byte, error) {
+	if tmpl == nil {
+		return def(n)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return nil, errors.Wrap(err, "rendering notification template")
+	}
+	return buf.Bytes(), nil
+}
+
+// WebhookSink POSTs notifications to a webhook. By default the notification is sent as JSON.
+type WebhookSink struct {
+	URL      string             // the URL to POST notifications to.
+	Template *template.Template // an optional template that renders the request body.
+	Client   *http.Client       // the client to POST with; if nil, http.DefaultClient is used.
+}
+
+// Send POSTs the notification to the webhook.
+func (s *WebhookSink) Send(ctx context.Context, n Notification) error {
+	body, err := render(s.Template, n, func(n Notification) ([]byte, error) { return json.Marshal(n) })
+	if err != nil {
+		return err
+	}
+	return PostJSON(ctx, s.Client, s.URL, body)
+}
+
+// SlackSink posts notifications to a Slack channel through an incoming webhook. By default the message is the
+// notification rendered by FormatNotification.
+type SlackSink struct {
+	WebhookURL string             // the Slack incoming webhook URL.
+	Template   *template.Template // an optional template that renders the message's text.
+	Client     *http.Client       // the client to POST with; if nil, http.DefaultClient is used.
+}
+
+// Send posts a message describing the notification to Slack.
+func (s *SlackSink) Send(ctx context.Context, n Notification) error {
+	text, err := render(s.Template, n, formatBytes)
+	if err != nil {
+		return err
+	}
+	return PostSlack(ctx, s.Client, s.WebhookURL, string(text))
+}
+
+// snsPublisher is the subset of the SNS API that SNSSink uses.
+type snsPublisher interface {
+	PublishWithContext(ctx aws.Context, input *sns.PublishInput, opts ...request.Option) (*sns.PublishOutput, error)
+}
+
+// SNSSink publishes notifications to an SNS topic. By default the message is the notification rendered by
+// FormatNotification.
+type SNSSink struct {
+	TopicARN string             // the ARN of the topic to publish to.
+	Template *template.Template // an optional template that renders the message.
+
+	client snsPublisher
+}
+
+// NewSNSSink creates a sink that publishes to the given topic using the default AWS credentials. If region is empty,
+// the default region is used.
+func NewSNSSink(topicARN, region string, tmpl *template.Template) (*SNSSink, error) {
+	cfg := &aws.Config{}
+	if region != "" {
+		cfg.Region = aws.String(region)
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AWS session")
+	}
+	return &SNSSink{TopicARN: topicARN, Template: tmpl, client: sns.New(sess)}, nil
+}
+
+// Send publishes the notification to the topic.
+func (s *SNSSink) Send(ctx context.Context, n Notification) error {
+	message, err := render(s.Template, n, formatBytes)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PublishWithContext(ctx, &sns.PublishInput{
+		TopicArn: aws.String(s.TopicARN),
+		Subject:  aws.String(subject(n)),
+		Message:  aws.String(string(message)),
+	})
+	return errors.Wrapf(err, "publishing notification to %s", s.TopicARN)
+}
+
+// subject returns a one-line description of the notification.
+func subject(n Notification) string {
+	kind := strings.Title(n.Kind)
+	switch n.Event {
+	case UpdateStarted:
+		return fmt.Sprintf("%s of stack %s started", kind, n.Stack)
+	case UpdateSucceeded:
+		return fmt.Sprintf("%s of stack %s succeeded", kind, n.Stack)
+	case UpdateFailed:
+		return fmt.Sprintf("%s of stack %s failed", kind, n.Stack)
+	case ResourceChanged:
+		return fmt.Sprintf("%s of %s in stack %s", strings.Title(string(n.Resource.Op)), n.Resource.URN.Name(),
+			n.Stack)
+	case ResourceFailed:
+		return fmt.Sprintf("%s of %s in stack %s failed", strings.Title(string(n.Resource.Op)),
+			n.Resource.URN.Name(), n.Stack)
+	default:
+		return fmt.Sprintf("%s of stack %s", kind, n.Stack)
+	}
+}
+
+// FormatNotification renders a notification as a short message in Slack's markdown dialect.
+func FormatNotification(n Notification) string {
+	var buf bytes.Buffer
+	buf.WriteString(subject(n))
+	if n.Error != "" {
+		fmt.Fprintf(&buf, ": %s", n.Error)
+	}
+
+	if len(n.Changes) > 0 {
+		ops := make([]string, 0, len(n.Changes))
+		for op, count := range n.Changes {
+			if op != "same" && count > 0 {
+				ops = append(ops, fmt.Sprintf("%d %s", count, op))
+			}
+		}
+		sort.Strings(ops)
+		if len(ops) > 0 {
+			fmt.Fprintf(&buf, " (%s)", strings.Join(ops, ", "))
+		}
+	}
+
+	changes := n.Resources
+	if n.Resource != nil {
+		changes = []ResourceChange{*n.Resource}
+	}
+	for _, c := range changes {
+		fmt.Fprintf(&buf, "\n• %s `%s`", c.Op, c.URN)
+		if len(c.Diffs) > 0 {
+			fmt.Fprintf(&buf, ": `%s`", strings.Join(c.Diffs, "`, `"))
+		}
+	}
+	return buf.String()
+}
+
+func formatBytes(n Notification) ([]byte, error) {
+	return []byte(FormatNotification(n)), nil
+}
+
+// PostSlack posts a message with the given text to a Slack incoming webhook.
+func PostSlack(ctx context.Context, client *http.Client, webhookURL string, text string) error {
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: text})
+	contract.AssertNoError(err)
+	return PostJSON(ctx, client, webhookURL, body)
+}
+
+// PostJSON POSTs the given JSON body to the given URL, failing if the response does not have a 2xx status. If client
+// is nil, http.DefaultClient is used. Webhook URLs often embed credentials, so errors do not include the URL.
+func PostJSON(ctx context.Context, client *http.Client, target string, body []byte) error {
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "creating notification request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "posting notification")
+	}
+	defer contract.IgnoreClose(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("posting notification: %s", resp.Status)
+	}
+	return nil
+}
@@ -127,11 +127,12 @@ func plan(ctx *Context, info *planContext, opts planOptions, dryRun bool) (*plan
 	contract.Assert(proj != nil)
 	contract.Assert(target != nil)
 	projinfo := &Projinfo{Proj: proj, Root: info.Update.GetRoot()}
-	pwd, main, plugctx, err := ProjectInfoContext(projinfo, opts.host, target, pluginEvents,
+	pwd, main, plugctx, err := ProjectInfoContext(projinfo, opts.Host, target, pluginEvents,
 		opts.Diag, opts.StatusDiag, info.TracingSpan)
 	if err != nil {
 		return nil, err
 	}
+	plugctx.Redaction = opts.RedactionPolicy
 
 	opts.trustDependencies = proj.TrustResourceDependencies()
 	// Now create the state source.  This may issue an error if it can't create the source.  This entails,
@@ -201,17 +202,42 @@ func (res *planResult) Chdir() (func(), error) {
 // resulting Snapshot, no matter whether an error occurs or not; an error, if something went wrong; the step that
 // failed, if the error is non-nil; and finally the state of the resource modified in the failing step.
 func (res *planResult) Walk(cancelCtx *Context, events deploy.Events, preview bool) error {
-	ctx, cancelFunc := context.WithCancel(context.Background())
+	// Parent the spans of the plan's steps within the plan's span.
+	ctx, cancelFunc := context.WithCancel(opentracing.ContextWithSpan(context.Background(), res.Ctx.TracingSpan))
 
 	done := make(chan bool)
 	var err error
 	go func() {
 		opts := deploy.Options{
-			Events:            events,
-			Parallel:          res.Options.Parallel,
-			Refresh:           res.Options.Refresh,
-			RefreshOnly:       res.Options.isRefresh,
-			TrustDependencies: res.Options.trustDependencies,
+			Events:              events,
+			Parallel:            res.Options.Parallel,
+			Refresh:             res.Options.Refresh,
+			ForceUnprotect:      res.Options.ForceUnprotect,
+			ExcludeProtected:    res.Options.ExcludeProtected,
+			RefreshMerge:        res.Options.RefreshMerge,
+			Targets:             res.Options.Targets,
+			TargetDependencies:  res.Options.TargetDependencies,
+			TargetDependents:    res.Options.TargetDependents,
+			ReplaceTargets:      res.Options.ReplaceTargets,
+			Retries:             res.Options.Retries,
+			RateLimits:          res.Options.RateLimits,
+			Policies:            res.Options.Policies,
+			Transformations:     res.Options.Transformations,
+			TaggableTypes:       res.Options.TaggableTypes,
+			AutoNamedTypes:      res.Options.AutoNamedTypes,
+			UnorderedProperties: res.Options.UnorderedProperties,
+			PropertyNormalizers: res.Options.PropertyNormalizers,
+			JSONProperties:      res.Options.JSONProperties,
+			ReadyTimeout:        res.Options.ReadyTimeout,
+			ReadyPollInterval:   res.Options.ReadyPollInterval,
+			Hooks:               res.Options.Hooks,
+			Guardrails:          res.Options.Guardrails,
+			AllowDestroy:        res.Options.AllowDestroy,
+			RefreshBatchSize:    res.Options.RefreshBatchSize,
+			IDAllocator:         res.Options.IDAllocator,
+			RefreshOnly:         res.Options.isRefresh,
+			TrustDependencies:   res.Options.trustDependencies,
+			AuditLog:            cancelCtx.AuditLog,
 		}
 		err = res.Plan.Execute(ctx, opts, preview)
 		close(done)
@@ -252,13 +278,13 @@ func printPlan(ctx *Context, result *planResult, dryRun bool) (ResourceChanges,
 	}
 
 	// Emit an event with a summary of operation counts.
-	changes := ResourceChanges(actions.Ops)
-	result.Options.Events.previewSummaryEvent(changes)
+	changes := actions.Summary.Ops
+	result.Options.Events.previewSummaryEvent(changes, actions.Summary)
 	return changes, nil
 }
 
 type planActions struct {
-	Ops     map[deploy.StepOp]int
+	Summary *PlanSummary
 	Opts    planOptions
 	Seen    map[resource.URN]deploy.Step
 	MapLock sync.Mutex
@@ -271,9 +297,9 @@ func shouldReportStep(step deploy.Step, opts planOptions) bool {
 
 func newPlanActions(opts planOptions) *planActions {
 	return &planActions{
-		Ops:  make(map[deploy.StepOp]int),
-		Opts: opts,
-		Seen: make(map[resource.URN]deploy.Step),
+		Summary: newPlanSummary(opts.CostEstimator),
+		Opts:    opts,
+		Seen:    make(map[resource.URN]deploy.Step),
 	}
 }
 
@@ -321,11 +347,14 @@ func (acts *planActions) OnResourceStepPost(ctx interface{},
 		}
 
 		// Track the operation if shown and/or if it is a logically meaningful operation.
+		acts.MapLock.Lock()
 		if record {
-			acts.MapLock.Lock()
-			acts.Ops[op]++
-			acts.MapLock.Unlock()
+			acts.Summary.recordOp(op, step)
 		}
+		if step.Logical() || acts.Opts.isRefresh && step.Op() == deploy.OpRefresh {
+			acts.Summary.recordCost(acts.Opts.CostEstimator, step, acts.Opts.Diag)
+		}
+		acts.MapLock.Unlock()
 
 		acts.Opts.Events.resourceOutputsEvent(op, step, true /*planning*/, acts.Opts.Debug)
 	}
@@ -368,6 +397,5 @@ func assertSeen(seen map[resource.URN]deploy.Step, step deploy.Step) {
 }
 
 func isDefaultProviderStep(step deploy.Step) bool {
-	urn := step.URN()
-	return providers.IsProviderType(urn.Type()) && urn.Name() == "default"
+	return providers.IsDefaultProvider(step.URN())
 }
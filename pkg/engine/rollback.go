@@ -0,0 +1,69 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// Rollback converges a stack's resources back to the state recorded in the given snapshot, which is typically the
+// checkpoint written by a prior update. Rather than running the program, the plan registers each resource in the
+// snapshot with the inputs recorded there: resources deleted since the snapshot was taken are re-created, resources
+// created since are deleted, and resources whose inputs have changed are updated (or replaced) to use their old
+// inputs. As with any other operation, passing dryRun produces a preview of these changes without applying them.
+func Rollback(u UpdateInfo, ctx *Context, opts UpdateOptions, to *deploy.Snapshot,
+	dryRun bool) (ResourceChanges, error) {
+
+	contract.Require(u != nil, "u")
+	contract.Require(ctx != nil, "ctx")
+	contract.Require(to != nil, "to")
+
+	defer func() { ctx.Events <- cancelEvent() }()
+
+	info, err := newPlanContext(u, "rollback", ctx.ParentSpan)
+	if err != nil {
+		return nil, err
+	}
+	defer info.Close()
+
+	emitter, err := makeEventEmitter(ctx.Events, u, opts.RedactionPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return update(ctx, info, planOptions{
+		UpdateOptions: opts,
+		SourceFunc: func(opts planOptions, proj *workspace.Project, pwd, main string,
+			target *deploy.Target, plugctx *plugin.Context, dryRun bool) (deploy.Source, error) {
+			return newRollbackSource(proj, to, plugctx)
+		},
+		Events:     emitter,
+		Diag:       newEventSink(emitter, false),
+		StatusDiag: newEventSink(emitter, true),
+	}, dryRun)
+}
+
+func newRollbackSource(proj *workspace.Project, to *deploy.Snapshot,
+	plugctx *plugin.Context) (deploy.Source, error) {
+
+	// Like destroy, rollback doesn't run code, so we only need the analyzers recorded by the target snapshot.
+	if err := plugctx.Host.EnsurePlugins(to.Manifest.Plugins, plugin.AnalyzerPlugins); err != nil {
+		return nil, err
+	}
+
+	return deploy.NewSnapshotSource(proj.Name, to), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// CostEstimator estimates what the resources of a stack cost to run. Estimates are in whatever currency and over
+// whatever period the estimator chooses (e.g. USD per month); the engine only adds them up.
+type CostEstimator interface {
+	// EstimateCost returns the estimated cost of a resource of the given type with the given input properties. It
+	// returns false if it has no estimate for the resource.
+	EstimateCost(t tokens.Type, props resource.PropertyMap) (float64, bool, error)
+}
+
+// PlanSummary summarizes the resource operations that an update planned or performed.
+type PlanSummary struct {
+	Ops       ResourceChanges                 // the number of resources by operation.
+	Types     map[tokens.Type]ResourceChanges // the number of resources of each type by operation.
+	Instances map[string]ResourceChanges      // the number of resources of each fan-out instance by operation.
+	Cost      *CostSummary                    // the estimated cost of the stack's resources; nil without an estimator.
+}
+
+// CostSummary aggregates the estimated costs of the resources that an update planned or performed.
+type CostSummary struct {
+	Total       float64                 // the estimated cost of the stack's resources after the update.
+	Change      float64                 // the difference between Total and the estimated cost before the update.
+	Types       map[tokens.Type]float64 // the estimated cost of the stack's resources of each type after the update.
+	Unestimated int                     // the number of resources that the estimator had no estimate for.
+}
+
+// newPlanSummary creates an empty summary, with room for costs if the given estimator is non-nil.
+func newPlanSummary(estimator CostEstimator) *PlanSummary {
+	summary := &PlanSummary{
+		Ops:       make(ResourceChanges),
+		Types:     make(map[tokens.Type]ResourceChanges),
+		Instances: make(map[string]ResourceChanges),
+	}
+	if estimator != nil {
+		summary.Cost = &CostSummary{Types: make(map[tokens.Type]float64)}
+	}
+	return summary
+}
+
+// recordOp counts a resource operation of the given step.
+func (s *PlanSummary) recordOp(op deploy.StepOp, step deploy.Step) {
+	s.Ops[op]++
+	changes, has := s.Types[step.Type()]
+	if !has {
+		changes = make(ResourceChanges)
+		s.Types[step.Type()] = changes
+	}
+	changes[op]++
+
+	if instance := step.Plan().Instance(step.URN()); instance != "" {
+		changes, has := s.Instances[instance]
+		if !has {
+			changes = make(ResourceChanges)
+			s.Instances[instance] = changes
+		}
+		changes[op]++
+	}
+}
+
+// recordCost adds the estimated costs of the step's old and new states to the summary. Each resource's logical step
+// is recorded once, so the new states add up to the cost of the stack after the update and the old states to its cost
+// before. Estimates that fail are reported as warnings and left out of the totals.
+func (s *PlanSummary) recordCost(estimator CostEstimator, step deploy.Step, sink diag.Sink) {
+	if s.Cost == nil {
+		return
+	}
+
+	estimate := func(state *resource.State) (float64, bool) {
+		cost, ok, err := estimator.EstimateCost(state.Type, state.Inputs)
+		if err != nil {
+			sink.Warningf(diag.Message(state.URN, "could not estimate the cost of %v: %v"), state.URN, err)
+			return 0, false
+		}
+		return cost, ok
+	}
+
+	if old := step.Old(); old != nil && !old.Delete {
+		if cost, ok := estimate(old); ok {
+			s.Cost.Change -= cost
+		}
+	}
+	if new := step.New(); new != nil {
+		cost, ok := estimate(new)
+		if !ok {
+			s.Cost.Unestimated++
+			return
+		}
+		s.Cost.Total += cost
+		s.Cost.Change += cost
+		s.Cost.Types[new.Type] += cost
+	}
+}
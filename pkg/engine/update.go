@@ -24,6 +24,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/policy"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
@@ -48,11 +49,100 @@ type UpdateOptions struct {
 	// true if the plan should refresh before executing.
 	Refresh bool
 
+	// true if the plan may delete or replace protected resources.
+	ForceUnprotect bool
+
+	// true if the plan should leave protected resources, and the resources they depend on, in place rather than fail
+	// when it would delete them; this lets a stack be destroyed around the resources that it must keep.
+	ExcludeProtected bool
+
+	// true if refreshes should revert changes made out of band to the properties that the program sets, while keeping
+	// changes made to all other properties.
+	RefreshMerge bool
+
+	// an optional set of URNs to restrict the plan to; all other resources are left untouched.
+	Targets []resource.URN
+
+	// true if the targets should include the resources that they depend on.
+	TargetDependencies bool
+
+	// true if the targets should include the resources that depend on them.
+	TargetDependents bool
+
+	// an optional set of URNs of resources to replace even if they have not changed.
+	ReplaceTargets []resource.URN
+
+	// the plugin host to use for this update.  If nil, a default host that loads plugins from the workspace is used.
+	Host plugin.Host
+
+	// an optional set of policies that control how provider operations that fail with transient errors are retried.
+	Retries *deploy.RetryPolicies
+
+	// an optional set of limits on the rate at which resource operations are issued to providers, which override any
+	// configured for the stack.
+	RateLimits *deploy.RateLimits
+
+	// an optional set of policy packs to evaluate against each resource's planned state.
+	Policies []policy.Pack
+
+	// an optional set of transformations that may modify each resource's goal state before it is planned.
+	Transformations *deploy.Transformations
+
+	// an optional map from taggable resource types to their tags properties; if nil, deploy.DefaultTaggableTypes is used.
+	TaggableTypes map[tokens.Type]resource.PropertyKey
+
+	// an optional map from auto-named resource types to their name properties; if nil, deploy.DefaultAutoNamedTypes
+	// is used.
+	AutoNamedTypes map[tokens.Type]resource.PropertyKey
+
+	// an optional map from resource types to the paths of their array-valued properties that are treated as unordered
+	// sets; if nil, deploy.DefaultUnorderedProperties is used.
+	UnorderedProperties map[tokens.Type][]resource.PropertyPath
+
+	// an optional map from resource types to normalizers for properties whose changes in representation alone should
+	// not be reported as diffs; if nil, deploy.DefaultPropertyNormalizers is used.
+	PropertyNormalizers map[tokens.Type][]deploy.PropertyNormalizer
+
+	// an optional map from resource types to the paths of their string properties that hold JSON documents; if nil,
+	// deploy.DefaultJSONProperties is used.
+	JSONProperties map[tokens.Type][]resource.PropertyPath
+
+	// how long a resource may take to satisfy its ready conditions; if zero, deploy.DefaultReadyTimeout is used.
+	ReadyTimeout time.Duration
+
+	// how often a resource is refreshed while waiting for it to become ready; if zero,
+	// deploy.DefaultReadyPollInterval is used.
+	ReadyPollInterval time.Duration
+
+	// an optional set of hooks to run at points in the lifecycle of the stack's resources, after those configured for
+	// the stack.
+	Hooks []deploy.Hook
+
+	// an optional set of guardrails that guard the resources they match against deletion and replacement, in addition
+	// to those configured for the stack.
+	Guardrails []deploy.Guardrail
+
+	// the URNs of guarded resources that this update may delete or replace.
+	AllowDestroy []resource.URN
+
+	// the largest number of resources that a refresh reads in a single call to a provider that supports batched reads;
+	// if zero, deploy.DefaultRefreshBatchSize is used, and if negative, resources are read individually.
+	RefreshBatchSize int
+
+	// an optional allocator of placeholder IDs for the resources that a preview would create; if nil, such resources
+	// have unknown IDs.
+	IDAllocator deploy.IDAllocator
+
+	// an optional estimator of the cost of the stack's resources; if set, each summary includes the estimated cost of
+	// the resources that the update plans or produces.
+	CostEstimator CostEstimator
+
+	// an optional policy that decides which property values are sensitive; if set, sensitive values are replaced by
+	// their digests in events, printed plans, and logged provider calls.
+	RedactionPolicy resource.RedactionPolicy
+
 	// true if we should report events for steps that involve default providers.
 	reportDefaultProviderSteps bool
-
-	// the plugin host to use for this update
-	host plugin.Host
 }
 
 // ResourceChanges contains the aggregate resource changes by operation type.
@@ -60,13 +150,18 @@ type ResourceChanges map[deploy.StepOp]int
 
 // HasChanges returns true if there are any non-same changes in the resulting summary.
 func (changes ResourceChanges) HasChanges() bool {
+	return changes.Count() > 0
+}
+
+// Count returns the number of resources that are created, updated, replaced, or deleted in the resulting summary.
+func (changes ResourceChanges) Count() int {
 	var c int
 	for op, count := range changes {
 		if op != deploy.OpSame {
 			c += count
 		}
 	}
-	return c > 0
+	return c
 }
 
 func Update(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (ResourceChanges, error) {
@@ -81,7 +176,7 @@ func Update(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (Resour
 	}
 	defer info.Close()
 
-	emitter, err := makeEventEmitter(ctx.Events, u)
+	emitter, err := makeEventEmitter(ctx.Events, u, opts.RedactionPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -162,11 +257,11 @@ func update(ctx *Context, info *planContext, opts planOptions, dryRun bool) (Res
 			actions := newUpdateActions(ctx, info.Update, opts)
 
 			err = result.Walk(ctx, actions, false)
-			resourceChanges = ResourceChanges(actions.Ops)
+			resourceChanges = actions.Summary.Ops
 
 			if len(resourceChanges) != 0 {
 				// Print out the total number of steps performed (and their kinds), the duration, and any summary info.
-				opts.Events.updateSummaryEvent(actions.MaybeCorrupt, time.Since(start), resourceChanges)
+				opts.Events.updateSummaryEvent(actions.MaybeCorrupt, time.Since(start), resourceChanges, actions.Summary)
 			}
 		}
 	}
@@ -187,7 +282,7 @@ func (p *pluginActions) OnPluginLoad(loadedPlug workspace.PluginInfo) error {
 type updateActions struct {
 	Context      *Context
 	Steps        int
-	Ops          map[deploy.StepOp]int
+	Summary      *PlanSummary
 	Seen         map[resource.URN]deploy.Step
 	MapLock      sync.Mutex
 	MaybeCorrupt bool
@@ -198,7 +293,7 @@ type updateActions struct {
 func newUpdateActions(context *Context, u UpdateInfo, opts planOptions) *updateActions {
 	return &updateActions{
 		Context: context,
-		Ops:     make(map[deploy.StepOp]int),
+		Summary: newPlanSummary(opts.CostEstimator),
 		Seen:    make(map[resource.URN]deploy.Step),
 		Update:  u,
 		Opts:    opts,
@@ -263,13 +358,16 @@ func (acts *updateActions) OnResourceStepPost(
 			record = ShouldRecordReadStep(step)
 		}
 
+		// Increment the counters.
+		acts.MapLock.Lock()
 		if record {
-			// Increment the counters.
-			acts.MapLock.Lock()
 			acts.Steps++
-			acts.Ops[op]++
-			acts.MapLock.Unlock()
+			acts.Summary.recordOp(op, step)
 		}
+		if step.Logical() || acts.Opts.isRefresh && step.Op() == deploy.OpRefresh {
+			acts.Summary.recordCost(acts.Opts.CostEstimator, step, acts.Opts.Diag)
+		}
+		acts.MapLock.Unlock()
 
 		// Also show outputs here for custom resources, since there might be some from the initial registration. We do
 		// not show outputs for component resources at this point: any that exist must be from a previous execution of
@@ -302,6 +400,16 @@ func (acts *updateActions) OnResourceStepPost(
 		for key, value := range old.Inputs {
 			new.Inputs[key] = value
 		}
+		new.InputHashes = old.InputHashes
+	}
+
+	// If the step's provider operation timed out, or its provider crashed while performing it, we cannot know whether
+	// the resource was affected. Leave the operation recorded as pending in the snapshot rather than completing the
+	// mutation so that the next update requires the user to reconcile it.
+	if crashErr, isCrash := err.(*plugin.ProviderCrashError); deploy.IsOperationTimeoutError(err) ||
+		isCrash && crashErr.Mutation {
+		logging.V(7).Infof("OnResourceStepPost(%s): Step's outcome is unknown, leaving its operation pending", step.URN())
+		return nil
 	}
 
 	// Write out the current snapshot. Note that even if a failure has occurred, we should still have a
@@ -218,6 +218,15 @@ func (a *Asset) Equals(other *Asset) bool {
 	return a.Hash == other.Hash
 }
 
+// deepCopy returns a copy of the asset that shares no mutable state with the original.
+func (a *Asset) deepCopy() *Asset {
+	if a == nil {
+		return nil
+	}
+	new := *a
+	return &new
+}
+
 // Serialize returns a weakly typed map that contains the right signature for serialization purposes.
 func (a *Asset) Serialize() map[string]interface{} {
 	result := map[string]interface{}{
@@ -533,6 +542,28 @@ func (a *Archive) Equals(other *Archive) bool {
 	return a.Hash == other.Hash
 }
 
+// deepCopy returns a copy of the archive that shares no mutable state with the original.
+func (a *Archive) deepCopy() *Archive {
+	if a == nil {
+		return nil
+	}
+	new := *a
+	if a.Assets != nil {
+		new.Assets = make(map[string]interface{}, len(a.Assets))
+		for k, v := range a.Assets {
+			switch t := v.(type) {
+			case *Asset:
+				new.Assets[k] = t.deepCopy()
+			case *Archive:
+				new.Assets[k] = t.deepCopy()
+			default:
+				new.Assets[k] = v
+			}
+		}
+	}
+	return &new
+}
+
 // Serialize returns a weakly typed map that contains the right signature for serialization purposes.
 func (a *Archive) Serialize() map[string]interface{} {
 	result := map[string]interface{}{
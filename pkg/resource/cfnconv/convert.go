@@ -0,0 +1,264 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfnconv converts the AWS resources in a snapshot or plan into an equivalent AWS CloudFormation template.
+// This is useful for auditing a stack with CloudFormation-aware tools and for migrating resources between the two.
+// Please see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/template-anatomy.html for a thorough
+// specification of the template format.
+//
+// The conversion is best-effort: only a well-known set of AWS resource types are converted, and property names are
+// converted from camelCase to PascalCase without consulting the CloudFormation resource specification.  The keys of
+// maps whose keys are chosen by the user, such as tags, are left as they are.
+package cfnconv
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// TemplateFormatVersion is the CloudFormation template format version of converted templates.
+const TemplateFormatVersion = "2010-09-09"
+
+// Template is a CloudFormation template.
+type Template struct {
+	AWSTemplateFormatVersion string               `json:"AWSTemplateFormatVersion" yaml:"AWSTemplateFormatVersion"`
+	Description              string               `json:"Description,omitempty" yaml:"Description,omitempty"`
+	Resources                map[string]*Resource `json:"Resources" yaml:"Resources"`
+}
+
+// Resource is a single resource in a CloudFormation template.
+type Resource struct {
+	Type           string                 `json:"Type" yaml:"Type"`
+	Properties     map[string]interface{} `json:"Properties,omitempty" yaml:"Properties,omitempty"`
+	DependsOn      []string               `json:"DependsOn,omitempty" yaml:"DependsOn,omitempty"`
+	DeletionPolicy string                 `json:"DeletionPolicy,omitempty" yaml:"DeletionPolicy,omitempty"`
+	Metadata       map[string]interface{} `json:"Metadata,omitempty" yaml:"Metadata,omitempty"`
+}
+
+// resourceTypes maps the Pulumi AWS resource types that may be converted to their CloudFormation equivalents.
+var resourceTypes = map[tokens.Type]string{
+	"aws:dynamodb/table:Table":            "AWS::DynamoDB::Table",
+	"aws:ec2/instance:Instance":           "AWS::EC2::Instance",
+	"aws:ec2/securityGroup:SecurityGroup": "AWS::EC2::SecurityGroup",
+	"aws:ec2/subnet:Subnet":               "AWS::EC2::Subnet",
+	"aws:ec2/vpc:Vpc":                     "AWS::EC2::VPC",
+	"aws:iam/policy:Policy":               "AWS::IAM::ManagedPolicy",
+	"aws:iam/role:Role":                   "AWS::IAM::Role",
+	"aws:kinesis/stream:Stream":           "AWS::Kinesis::Stream",
+	"aws:lambda/function:Function":        "AWS::Lambda::Function",
+	"aws:s3/bucket:Bucket":                "AWS::S3::Bucket",
+	"aws:sns/topic:Topic":                 "AWS::SNS::Topic",
+	"aws:sqs/queue:Queue":                 "AWS::SQS::Queue",
+}
+
+// Convert renders the given resources as a CloudFormation template.  Resources are expected to be in dependency order,
+// as they are in snapshots.  Component and provider resources, as well as resources that are pending deletion, are
+// ignored; custom resources whose types have no CloudFormation equivalent are returned in the list of skipped
+// resources.
+//
+// Where a resource's input property refers to the ID or an output property of a resource on which it depends, the
+// reference is rendered as a `Ref` or `Fn::GetAtt` intrinsic function rather than as a literal value.
+func Convert(resources []*resource.State) (*Template, []*resource.State) {
+	tmpl := &Template{
+		AWSTemplateFormatVersion: TemplateFormatVersion,
+		Resources:                make(map[string]*Resource),
+	}
+
+	var skipped []*resource.State
+	logicalIDs := make(map[resource.URN]string)
+	states := make(map[resource.URN]*resource.State)
+	for _, res := range resources {
+		if !res.Custom || res.Delete || providers.IsProviderType(res.Type) {
+			continue
+		}
+		typ, has := resourceTypes[res.Type]
+		if !has {
+			skipped = append(skipped, res)
+			continue
+		}
+
+		id := newLogicalID(tmpl, res.URN)
+		logicalIDs[res.URN], states[res.URN] = id, res
+
+		r := &Resource{
+			Type:       typ,
+			Properties: convertProperties(res, logicalIDs, states),
+			Metadata: map[string]interface{}{
+				"Pulumi": map[string]interface{}{"URN": string(res.URN), "ID": string(res.ID)},
+			},
+		}
+		for _, dep := range res.Dependencies {
+			if depID, has := logicalIDs[dep]; has {
+				r.DependsOn = append(r.DependsOn, depID)
+			}
+		}
+		sort.Strings(r.DependsOn)
+		if res.Protect || res.RetainOnDelete {
+			r.DeletionPolicy = "Retain"
+		}
+		tmpl.Resources[id] = r
+	}
+
+	return tmpl, skipped
+}
+
+// newLogicalID computes a logical ID for a resource that is unique within the template.  Logical IDs must be
+// alphanumeric, so the resource's name is converted to PascalCase and stripped of any other characters.
+func newLogicalID(tmpl *Template, urn resource.URN) string {
+	var b bytes.Buffer
+	upper := true
+	for _, c := range string(urn.Name()) {
+		switch {
+		case c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)):
+			if upper {
+				c = unicode.ToUpper(c)
+			}
+			b.WriteRune(c)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+
+	base := b.String()
+	if base == "" || !unicode.IsLetter(rune(base[0])) {
+		base = "Resource" + base
+	}
+	id := base
+	for i := 2; tmpl.Resources[id] != nil; i++ {
+		id = base + strconv.Itoa(i)
+	}
+	return id
+}
+
+// userMapProperties names the properties whose values are maps with keys chosen by the user rather than by the
+// resource's schema, such as tags and environment variables.  The keys of these maps are not property names, so they
+// are not converted.
+var userMapProperties = map[resource.PropertyKey]bool{
+	"tags":      true,
+	"variables": true,
+}
+
+// propertyName converts a Pulumi property name to its CloudFormation equivalent.
+func propertyName(key resource.PropertyKey) string {
+	if key == "" {
+		return ""
+	}
+	return strings.ToUpper(string(key)[:1]) + string(key)[1:]
+}
+
+// convertProperties converts a resource's inputs to CloudFormation properties.
+func convertProperties(res *resource.State, logicalIDs map[resource.URN]string,
+	states map[resource.URN]*resource.State) map[string]interface{} {
+
+	props := make(map[string]interface{})
+	for _, k := range res.Inputs.StableKeys() {
+		// Skip properties reserved for use by providers (e.g. "__defaults").
+		if strings.HasPrefix(string(k), "__") {
+			continue
+		}
+
+		// Prefer the property's own dependencies, if they were recorded; otherwise, consider all of the resource's.
+		deps, has := res.PropertyDependencies[k]
+		if !has {
+			deps = res.Dependencies
+		}
+		var refs []*resource.State
+		for _, dep := range deps {
+			if state, has := states[dep]; has {
+				refs = append(refs, state)
+			}
+		}
+
+		if v, ok := convertValue(res.Inputs[k], !userMapProperties[k], refs, logicalIDs); ok {
+			props[propertyName(k)] = v
+		}
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+// convertValue converts a single property value.  Values that have no CloudFormation equivalent (nulls, assets,
+// archives, and unknowns) are dropped, in which case false is returned.  If names is true, the keys of objects within
+// the value are property names, and are converted to their CloudFormation equivalents; otherwise, they are left as
+// they are.
+func convertValue(v resource.PropertyValue, names bool, refs []*resource.State,
+	logicalIDs map[resource.URN]string) (interface{}, bool) {
+
+	switch {
+	case v.IsBool():
+		return v.BoolValue(), true
+	case v.IsNumber():
+		return v.NumberValue(), true
+	case v.IsString():
+		if ref, ok := convertReference(v.StringValue(), refs, logicalIDs); ok {
+			return ref, true
+		}
+		return v.StringValue(), true
+	case v.IsArray():
+		arr := []interface{}{}
+		for _, e := range v.ArrayValue() {
+			if ev, ok := convertValue(e, names, refs, logicalIDs); ok {
+				arr = append(arr, ev)
+			}
+		}
+		return arr, true
+	case v.IsObject():
+		obj := make(map[string]interface{})
+		for _, k := range v.ObjectValue().StableKeys() {
+			key, elemNames := string(k), false
+			if names {
+				key, elemNames = propertyName(k), !userMapProperties[k]
+			}
+			if ev, ok := convertValue(v.ObjectValue()[k], elemNames, refs, logicalIDs); ok {
+				obj[key] = ev
+			}
+		}
+		return obj, true
+	default:
+		return nil, false
+	}
+}
+
+// convertReference returns a `Ref` to a resource whose ID is equal to the given string, or an `Fn::GetAtt` of a
+// resource output property whose value is equal to the given string.  If no such resource is found, false is returned.
+func convertReference(s string, refs []*resource.State,
+	logicalIDs map[resource.URN]string) (interface{}, bool) {
+
+	if s == "" {
+		return nil, false
+	}
+	for _, ref := range refs {
+		if string(ref.ID) == s {
+			return map[string]interface{}{"Ref": logicalIDs[ref.URN]}, true
+		}
+	}
+	for _, ref := range refs {
+		for _, k := range ref.Outputs.StableKeys() {
+			if out := ref.Outputs[k]; out.IsString() && out.StringValue() == s {
+				return map[string]interface{}{"Fn::GetAtt": []string{logicalIDs[ref.URN], propertyName(k)}}, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,114 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfnconv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func newResource(t tokens.Type, name string, id resource.ID, inputs, outputs resource.PropertyMap,
+	deps ...resource.URN) *resource.State {
+
+	return &resource.State{
+		Type:         t,
+		URN:          resource.NewURN("test", "test", "", t, tokens.QName(name)),
+		Custom:       true,
+		ID:           id,
+		Inputs:       inputs,
+		Outputs:      outputs,
+		Dependencies: deps,
+	}
+}
+
+func TestConvert(t *testing.T) {
+	role := newResource("aws:iam/role:Role", "my-role", "my-role-1234", resource.PropertyMap{
+		"assumeRolePolicy": resource.NewStringProperty("{}"),
+	}, resource.PropertyMap{
+		"arn": resource.NewStringProperty("arn:aws:iam::123456789012:role/my-role-1234"),
+	})
+	bucket := newResource("aws:s3/bucket:Bucket", "my-bucket", "my-bucket-1234", resource.PropertyMap{
+		"acl": resource.NewStringProperty("private"),
+		"versioning": resource.NewObjectProperty(resource.PropertyMap{
+			"enabled": resource.NewBoolProperty(true),
+		}),
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"costCenter": resource.NewStringProperty("42"),
+		}),
+	}, nil)
+	bucket.Protect = true
+	fn := newResource("aws:lambda/function:Function", "my-function", "my-function-1234", resource.PropertyMap{
+		"role": resource.NewStringProperty("arn:aws:iam::123456789012:role/my-role-1234"),
+		"environment": resource.NewObjectProperty(resource.PropertyMap{
+			"variables": resource.NewObjectProperty(resource.PropertyMap{
+				"BUCKET":   resource.NewStringProperty("my-bucket-1234"),
+				"logLevel": resource.NewStringProperty("debug"),
+			}),
+		}),
+		"code":       resource.NewArchiveProperty(&resource.Archive{Path: "."}),
+		"__defaults": resource.NewArrayProperty(nil),
+	}, nil, role.URN, bucket.URN)
+	other := newResource("aws:apigateway/restApi:RestApi", "my-api", "abcd", nil, nil)
+	component := &resource.State{
+		Type: "my:component:Component",
+		URN:  resource.NewURN("test", "test", "", "my:component:Component", "component"),
+	}
+
+	tmpl, skipped := Convert([]*resource.State{component, role, bucket, fn, other})
+	assert.Equal(t, []*resource.State{other}, skipped)
+	assert.Equal(t, TemplateFormatVersion, tmpl.AWSTemplateFormatVersion)
+	assert.Len(t, tmpl.Resources, 3)
+
+	b, err := json.Marshal(tmpl.Resources["MyBucket"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Type": "AWS::S3::Bucket",
+		"Properties": {"Acl": "private", "Tags": {"costCenter": "42"}, "Versioning": {"Enabled": true}},
+		"DeletionPolicy": "Retain",
+		"Metadata": {"Pulumi": {"URN": "`+string(bucket.URN)+`", "ID": "my-bucket-1234"}}
+	}`, string(b))
+
+	// References to the role's ARN and the bucket's ID are rendered as intrinsic functions.  The names of environment
+	// variables are left as they are.
+	b, err = json.Marshal(tmpl.Resources["MyFunction"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Type": "AWS::Lambda::Function",
+		"Properties": {
+			"Environment": {"Variables": {"BUCKET": {"Ref": "MyBucket"}, "logLevel": "debug"}},
+			"Role": {"Fn::GetAtt": ["MyRole", "Arn"]}
+		},
+		"DependsOn": ["MyBucket", "MyRole"],
+		"Metadata": {"Pulumi": {"URN": "`+string(fn.URN)+`", "ID": "my-function-1234"}}
+	}`, string(b))
+}
+
+func TestLogicalIDs(t *testing.T) {
+	a := newResource("aws:sqs/queue:Queue", "queue", "a", nil, nil)
+	b := newResource("aws:sqs/queue:Queue", "queue", "b", nil, nil)
+	b.URN = resource.NewURN("test", "test", "my:component:Component", "aws:sqs/queue:Queue", "queue")
+	c := newResource("aws:sqs/queue:Queue", "1-queue", "c", nil, nil)
+
+	tmpl, skipped := Convert([]*resource.State{a, b, c})
+	assert.Empty(t, skipped)
+	assert.Equal(t, "a", tmpl.Resources["Queue"].Metadata["Pulumi"].(map[string]interface{})["ID"])
+	assert.Equal(t, "b", tmpl.Resources["Queue2"].Metadata["Pulumi"].(map[string]interface{})["ID"])
+	assert.Equal(t, "c", tmpl.Resources["Resource1Queue"].Metadata["Pulumi"].(map[string]interface{})["ID"])
+}
@@ -0,0 +1,74 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// An indexed resource collection is a set of resources that share a base name and are told apart by their index, e.g.
+// `web[0]`, `web[1]`, and `web[2]`.  Because each member's URN depends only upon its own index, growing or shrinking
+// a collection creates or deletes only the members at the indices that were added or removed; the members at the
+// surviving indices keep their identity and are diffed against their old state as usual.
+
+// indexedNameRegexp matches names that look like members of an indexed collection.
+var indexedNameRegexp = regexp.MustCompile(`^(.+)\[(-?[0-9]+)\]$`)
+
+// IndexedName returns the name of the member of the collection with the given base name at the given index.
+func IndexedName(base tokens.QName, index int) tokens.QName {
+	contract.Requiref(base != "", "base", "must not be empty")
+	contract.Requiref(index >= 0, "index", "must not be negative")
+	return tokens.QName(fmt.Sprintf("%s[%d]", base, index))
+}
+
+// ParseIndexedName returns the base name and index of the given collection member name.  The last result is false if
+// the name is not the name of a collection member.
+func ParseIndexedName(name tokens.QName) (tokens.QName, int, bool) {
+	m := indexedNameRegexp.FindStringSubmatch(string(name))
+	if m == nil {
+		return "", 0, false
+	}
+	index, err := strconv.Atoi(m[2])
+	if err != nil || strconv.Itoa(index) != m[2] || index < 0 {
+		return "", 0, false
+	}
+	return tokens.QName(m[1]), index, true
+}
+
+// IsMalformedIndexedName returns true if the given name looks like the name of a collection member but its index is
+// not in canonical form, e.g. `web[01]` or `web[-1]`.  Such names would give two spellings of the same index distinct
+// identities, so they are rejected.
+func IsMalformedIndexedName(name tokens.QName) bool {
+	if !indexedNameRegexp.MatchString(string(name)) {
+		return false
+	}
+	_, _, ok := ParseIndexedName(name)
+	return !ok
+}
+
+// CollectionIndex returns the URN of the collection to which the resource with the given URN belongs, and the index of
+// the resource within that collection.  The last result is false if the resource is not a collection member.
+func (urn URN) CollectionIndex() (URN, int, bool) {
+	base, index, ok := ParseIndexedName(urn.Name())
+	if !ok {
+		return "", 0, false
+	}
+	return NewURN(urn.Stack(), urn.Project(), "", urn.QualifiedType(), base), index, true
+}
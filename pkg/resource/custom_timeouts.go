@@ -0,0 +1,75 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CustomTimeouts bounds how long each kind of provider operation on a resource may take, in seconds.  A zero timeout
+// means that the operation may take as long as it needs.
+type CustomTimeouts struct {
+	Create float64 `json:"create,omitempty" yaml:"create,omitempty"`
+	Update float64 `json:"update,omitempty" yaml:"update,omitempty"`
+	Delete float64 `json:"delete,omitempty" yaml:"delete,omitempty"`
+}
+
+// IsZero returns true if none of the timeouts are set.
+func (t CustomTimeouts) IsZero() bool {
+	return t.Create == 0 && t.Update == 0 && t.Delete == 0
+}
+
+// ParseCustomTimeouts parses a set of timeouts given as duration strings such as "30s" or "1h30m".  Empty strings
+// leave the corresponding timeouts unset.
+func ParseCustomTimeouts(create, update, delete string) (CustomTimeouts, error) {
+	var timeouts CustomTimeouts
+	for _, t := range []struct {
+		name     string
+		value    string
+		duration *float64
+	}{
+		{"create", create, &timeouts.Create},
+		{"update", update, &timeouts.Update},
+		{"delete", delete, &timeouts.Delete},
+	} {
+		if t.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(t.value)
+		if err != nil {
+			return CustomTimeouts{}, errors.Wrapf(err, "invalid %s timeout", t.name)
+		} else if d < 0 {
+			return CustomTimeouts{}, errors.Errorf("invalid %s timeout %q: timeouts must not be negative", t.name, t.value)
+		}
+		*t.duration = d.Seconds()
+	}
+	return timeouts, nil
+}
+
+// Timeout returns the timeout for the given kind of operation, or zero if the operation has none.
+func (t CustomTimeouts) Timeout(op OperationType) time.Duration {
+	var seconds float64
+	switch op {
+	case OperationTypeCreating:
+		seconds = t.Create
+	case OperationTypeUpdating:
+		seconds = t.Update
+	case OperationTypeDeleting:
+		seconds = t.Delete
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,42 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCustomTimeouts(t *testing.T) {
+	timeouts, err := ParseCustomTimeouts("5m", "", "1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, CustomTimeouts{Create: 300, Delete: 5400}, timeouts)
+	assert.Equal(t, 5*time.Minute, timeouts.Timeout(OperationTypeCreating))
+	assert.Equal(t, time.Duration(0), timeouts.Timeout(OperationTypeUpdating))
+	assert.Equal(t, 90*time.Minute, timeouts.Timeout(OperationTypeDeleting))
+	assert.Equal(t, time.Duration(0), timeouts.Timeout(OperationTypeReading))
+
+	timeouts, err = ParseCustomTimeouts("", "", "")
+	assert.NoError(t, err)
+	assert.True(t, timeouts.IsZero())
+
+	_, err = ParseCustomTimeouts("five minutes", "", "")
+	assert.Error(t, err)
+
+	_, err = ParseCustomTimeouts("", "-1s", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,359 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package declarative implements Pulumi programs that are written as YAML or JSON documents rather than in a general
+// purpose language.  A document declares a set of resources and the stack's outputs:
+//
+//	resources:
+//	  bucket:
+//	    type: aws:s3/bucket:Bucket
+//	  object:
+//	    type: aws:s3/bucketObject:BucketObject
+//	    properties:
+//	      bucket: ${bucket.id}
+//	      key: index.html
+//	      content: "<a href='https://${bucket.bucketDomainName}'>home</a>"
+//	outputs:
+//	  bucketName: ${bucket.id}
+//
+// Strings may refer to the `id`, `urn`, or output properties of other resources using `${name.property}`.  A string
+// that is a single reference takes the referenced value as-is; other strings are interpolated by the engine, so they
+// are unknown during previews if any value they refer to is unknown.  `$${` stands for a literal `${`.
+package declarative
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// Document is a declarative Pulumi program.
+type Document struct {
+	Resources map[string]*Resource   `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Outputs   map[string]interface{} `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+}
+
+// Resource declares a single resource.  The resource's name is its key in the document's resources.
+type Resource struct {
+	Type       string                 `json:"type" yaml:"type"`                                 // the resource's type.
+	Component  bool                   `json:"component,omitempty" yaml:"component,omitempty"`   // true for components.
+	Properties map[string]interface{} `json:"properties,omitempty" yaml:"properties,omitempty"` // the inputs.
+	Options    ResourceOptions        `json:"options,omitempty" yaml:"options,omitempty"`       // optional settings.
+}
+
+// ResourceOptions holds the optional settings of a resource.  Other resources are referred to by name.
+type ResourceOptions struct {
+	Parent              string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	DependsOn           []string `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	Protect             bool     `json:"protect,omitempty" yaml:"protect,omitempty"`
+	Provider            string   `json:"provider,omitempty" yaml:"provider,omitempty"`
+	DeleteBeforeReplace bool     `json:"deleteBeforeReplace,omitempty" yaml:"deleteBeforeReplace,omitempty"`
+}
+
+// Load reads a document from the given YAML or JSON file.
+func Load(path string) (*Document, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Parse(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading %s", path)
+	}
+	return doc, nil
+}
+
+// Parse parses and validates a YAML or JSON document.
+func Parse(b []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	// YAML decodes nested objects as maps with interface keys; turn them into ordinary JSON-like maps.
+	for _, r := range doc.Resources {
+		if r == nil {
+			continue
+		}
+		for k, v := range r.Properties {
+			r.Properties[k] = normalize(v)
+		}
+	}
+	for k, v := range doc.Outputs {
+		doc.Outputs[k] = normalize(v)
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[fmt.Sprintf("%v", k)] = normalize(e)
+		}
+		return m
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalize(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalize(e)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// validate checks that every resource has a well-formed type and that every reference names a declared resource.
+func (doc *Document) validate() error {
+	for _, name := range doc.ResourceNames() {
+		r := doc.Resources[name]
+		if r == nil || r.Type == "" {
+			return errors.Errorf("resource %s is missing its type", name)
+		}
+		parts := strings.Split(r.Type, tokens.TokenDelimiter)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return errors.Errorf("resource %s has an invalid type '%s'; types are of the form package:module:Type",
+				name, r.Type)
+		}
+	}
+	_, err := doc.sortResources()
+	return err
+}
+
+// ResourceNames returns the names of the document's resources in sorted order.
+func (doc *Document) ResourceNames() []string {
+	names := make([]string, 0, len(doc.Resources))
+	for name := range doc.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Packages returns the packages whose providers the document's resources require, in sorted order.
+func (doc *Document) Packages() []tokens.Package {
+	pkgs := make(map[tokens.Package]bool)
+	for _, r := range doc.Resources {
+		t := tokens.Type(r.Type)
+		pkg := t.Package()
+		if pkg == "pulumi" && t.Module().Name() == "providers" {
+			// Provider resources require the plugin of the package they configure.
+			pkg = tokens.Package(t.Name())
+		}
+		if pkg != "pulumi" {
+			pkgs[pkg] = true
+		}
+	}
+	result := make([]tokens.Package, 0, len(pkgs))
+	for pkg := range pkgs {
+		result = append(result, pkg)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// dependencies returns the names of the resources that the given resource refers to or otherwise depends on.
+func (doc *Document) dependencies(r *Resource) ([]string, error) {
+	var deps []string
+	for _, k := range sortedKeys(r.Properties) {
+		refs, err := references(r.Properties[k])
+		if err != nil {
+			return nil, errors.Wrapf(err, "property %s", k)
+		}
+		for _, ref := range refs {
+			deps = append(deps, ref.resource)
+		}
+	}
+	deps = append(deps, r.Options.DependsOn...)
+	if r.Options.Parent != "" {
+		deps = append(deps, r.Options.Parent)
+	}
+	if r.Options.Provider != "" {
+		deps = append(deps, r.Options.Provider)
+	}
+	return deps, nil
+}
+
+// sortResources returns the names of the document's resources ordered so that each resource follows all of the
+// resources it depends on.
+func (doc *Document) sortResources() ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var order []string
+	var visit func(name string, from string) error
+	visit = func(name string, from string) error {
+		if _, has := doc.Resources[name]; !has {
+			return errors.Errorf("resource %s refers to undeclared resource %s", from, name)
+		}
+		switch state[name] {
+		case visiting:
+			return errors.Errorf("resource %s depends on itself", name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		deps, err := doc.dependencies(doc.Resources[name])
+		if err != nil {
+			return errors.Wrapf(err, "resource %s", name)
+		}
+		for _, dep := range deps {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range doc.ResourceNames() {
+		if err := visit(name, name); err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range sortedKeys(doc.Outputs) {
+		refs, err := references(doc.Outputs[k])
+		if err != nil {
+			return nil, errors.Wrapf(err, "output %s", k)
+		}
+		for _, ref := range refs {
+			if _, has := doc.Resources[ref.resource]; !has {
+				return nil, errors.Errorf("output %s refers to undeclared resource %s", k, ref.resource)
+			}
+		}
+	}
+	return order, nil
+}
+
+// reference is a `${resource.property}` reference to a value of another resource.
+type reference struct {
+	resource string                // the name of the resource.
+	path     resource.PropertyPath // the path to the value; its first element is `id`, `urn`, or an output property.
+}
+
+// templatePart is either a run of literal text or a reference within a string.
+type templatePart struct {
+	text string
+	ref  *reference
+}
+
+// parseTemplate splits a string into literal text and references.
+func parseTemplate(s string) ([]templatePart, error) {
+	var parts []templatePart
+	var text bytes.Buffer
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			text.WriteString("${")
+			i += 3
+		case strings.HasPrefix(s[i:], "${"):
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return nil, errors.Errorf("'%s' has a reference that is missing its closing '}'", s)
+			}
+			ref, err := parseReference(s[i+2 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			if text.Len() > 0 {
+				parts = append(parts, templatePart{text: text.String()})
+				text.Reset()
+			}
+			parts = append(parts, templatePart{ref: ref})
+			i += end + 1
+		default:
+			text.WriteByte(s[i])
+			i++
+		}
+	}
+	if text.Len() > 0 {
+		parts = append(parts, templatePart{text: text.String()})
+	}
+	return parts, nil
+}
+
+func parseReference(s string) (*reference, error) {
+	end := strings.IndexAny(s, ".[")
+	if end <= 0 || s[end] != '.' {
+		return nil, errors.Errorf("reference '${%s}' must be of the form ${resource.property}", s)
+	}
+	path, err := resource.ParsePropertyPath(s[end+1:])
+	if err != nil || len(path) == 0 {
+		return nil, errors.Errorf("reference '${%s}' has an invalid property path", s)
+	}
+	return &reference{resource: s[:end], path: path}, nil
+}
+
+// references returns the references held by the strings within the given value.
+func references(v interface{}) ([]*reference, error) {
+	var refs []*reference
+	switch t := v.(type) {
+	case string:
+		parts, err := parseTemplate(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			if part.ref != nil {
+				refs = append(refs, part.ref)
+			}
+		}
+	case []interface{}:
+		for _, e := range t {
+			erefs, err := references(e)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, erefs...)
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(t) {
+			erefs, err := references(t[k])
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, erefs...)
+		}
+	}
+	return refs, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,277 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package declarative
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// RunInfo describes the stack that a document is being run against.
+type RunInfo struct {
+	Project string // the name of the project.
+	Stack   string // the name of the stack.
+	DryRun  bool   // true if this is a preview.
+}
+
+// registeredResource is the state of a resource that has been registered with the engine.
+type registeredResource struct {
+	urn     resource.URN
+	id      resource.ID
+	outputs resource.PropertyMap
+}
+
+// evaluator registers the resources of a document with the engine.
+type evaluator struct {
+	ctx       context.Context
+	monitor   pulumirpc.ResourceMonitorClient
+	info      RunInfo
+	resources map[string]*registeredResource
+}
+
+// Run registers the resources declared by the document with the given resource monitor, and then registers the
+// document's outputs as the outputs of the stack.  Each resource is registered after the resources it depends on, so
+// that their outputs are available to it.
+func Run(ctx context.Context, monitor pulumirpc.ResourceMonitorClient, info RunInfo, doc *Document) error {
+	order, err := doc.sortResources()
+	if err != nil {
+		return err
+	}
+
+	e := &evaluator{ctx: ctx, monitor: monitor, info: info, resources: make(map[string]*registeredResource)}
+
+	// Create a root stack resource that we'll parent everything to.
+	stack, err := monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type: "pulumi:pulumi:Stack",
+		Name: fmt.Sprintf("%s-%s", info.Project, info.Stack),
+	})
+	if err != nil {
+		return errors.Wrap(err, "registering the stack")
+	}
+
+	for _, name := range order {
+		if err = e.register(name, doc.Resources[name], resource.URN(stack.GetUrn())); err != nil {
+			return errors.Wrapf(err, "resource %s", name)
+		}
+	}
+
+	outputs := make(resource.PropertyMap)
+	for _, k := range sortedKeys(doc.Outputs) {
+		v, _, err := e.evaluate(doc.Outputs[k])
+		if err != nil {
+			return errors.Wrapf(err, "output %s", k)
+		}
+		outputs[resource.PropertyKey(k)] = v
+	}
+	mouts, err := plugin.MarshalProperties(outputs, plugin.MarshalOptions{Label: "outputs", KeepUnknowns: true})
+	if err != nil {
+		return err
+	}
+	_, err = monitor.RegisterResourceOutputs(ctx, &pulumirpc.RegisterResourceOutputsRequest{
+		Urn:     stack.GetUrn(),
+		Outputs: mouts,
+	})
+	return err
+}
+
+// register registers a single resource, once the resources it depends on have been registered.
+func (e *evaluator) register(name string, r *Resource, stackURN resource.URN) error {
+	inputs := make(resource.PropertyMap)
+	var deps []string
+	propertyDeps := make(map[string]*pulumirpc.RegisterResourceRequest_PropertyDependencies)
+	seen := make(map[resource.URN]bool)
+	for _, k := range sortedKeys(r.Properties) {
+		v, refs, err := e.evaluate(r.Properties[k])
+		if err != nil {
+			return errors.Wrapf(err, "property %s", k)
+		}
+		inputs[resource.PropertyKey(k)] = v
+
+		var urns []string
+		for _, ref := range refs {
+			urn := e.resources[ref].urn
+			urns = append(urns, string(urn))
+			if !seen[urn] {
+				seen[urn] = true
+				deps = append(deps, string(urn))
+			}
+		}
+		propertyDeps[k] = &pulumirpc.RegisterResourceRequest_PropertyDependencies{Urns: urns}
+	}
+	for _, dep := range r.Options.DependsOn {
+		if urn := e.resources[dep].urn; !seen[urn] {
+			seen[urn] = true
+			deps = append(deps, string(urn))
+		}
+	}
+
+	parent := stackURN
+	if r.Options.Parent != "" {
+		parent = e.resources[r.Options.Parent].urn
+	}
+	var provider string
+	if r.Options.Provider != "" {
+		p := e.resources[r.Options.Provider]
+		id := p.id
+		if id == "" {
+			id = providers.UnknownID
+		}
+		ref, err := providers.NewReference(p.urn, id)
+		if err != nil {
+			return err
+		}
+		provider = ref.String()
+	}
+
+	label := fmt.Sprintf("RegisterResource(%s, %s)", r.Type, name)
+	object, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{Label: label, KeepUnknowns: true})
+	if err != nil {
+		return err
+	}
+
+	logging.V(7).Infof("Registering declared resource %s (%s)", name, r.Type)
+	resp, err := e.monitor.RegisterResource(e.ctx, &pulumirpc.RegisterResourceRequest{
+		Type:                 r.Type,
+		Name:                 name,
+		Parent:               string(parent),
+		Custom:               !r.Component,
+		Object:               object,
+		Protect:              r.Options.Protect,
+		Dependencies:         deps,
+		Provider:             provider,
+		PropertyDependencies: propertyDeps,
+		DeleteBeforeReplace:  r.Options.DeleteBeforeReplace,
+	})
+	if err != nil {
+		return err
+	}
+	outputs, err := plugin.UnmarshalProperties(resp.GetObject(), plugin.MarshalOptions{
+		Label:        label,
+		KeepUnknowns: true,
+	})
+	if err != nil {
+		return err
+	}
+	e.resources[name] = &registeredResource{
+		urn:     resource.URN(resp.GetUrn()),
+		id:      resource.ID(resp.GetId()),
+		outputs: outputs,
+	}
+	return nil
+}
+
+// evaluate converts a value of the document into a property value, replacing its references with the values they
+// refer to.  It also returns the names of the resources that the value refers to.
+func (e *evaluator) evaluate(v interface{}) (resource.PropertyValue, []string, error) {
+	switch t := v.(type) {
+	case string:
+		return e.evaluateString(t)
+	case []interface{}:
+		var refs []string
+		arr := make([]resource.PropertyValue, len(t))
+		for i, elem := range t {
+			ev, erefs, err := e.evaluate(elem)
+			if err != nil {
+				return resource.PropertyValue{}, nil, err
+			}
+			arr[i] = ev
+			refs = append(refs, erefs...)
+		}
+		return resource.NewArrayProperty(arr), refs, nil
+	case map[string]interface{}:
+		var refs []string
+		obj := make(resource.PropertyMap, len(t))
+		for _, k := range sortedKeys(t) {
+			ev, erefs, err := e.evaluate(t[k])
+			if err != nil {
+				return resource.PropertyValue{}, nil, err
+			}
+			obj[resource.PropertyKey(k)] = ev
+			refs = append(refs, erefs...)
+		}
+		return resource.NewObjectProperty(obj), refs, nil
+	default:
+		return resource.NewPropertyValue(v), nil, nil
+	}
+}
+
+// evaluateString evaluates a string that may hold references.  A string that is a single reference evaluates to the
+// value it refers to; other strings that hold references evaluate to formats that the engine interpolates.
+func (e *evaluator) evaluateString(s string) (resource.PropertyValue, []string, error) {
+	parts, err := parseTemplate(s)
+	if err != nil {
+		return resource.PropertyValue{}, nil, err
+	}
+	if len(parts) == 1 && parts[0].ref != nil {
+		v, err := e.lookup(parts[0].ref)
+		return v, []string{parts[0].ref.resource}, err
+	}
+
+	var template, text bytes.Buffer
+	var args []resource.PropertyValue
+	var refs []string
+	for _, part := range parts {
+		if part.ref == nil {
+			text.WriteString(part.text)
+			template.WriteString(strings.Replace(part.text, "%", "%%", -1))
+			continue
+		}
+		v, err := e.lookup(part.ref)
+		if err != nil {
+			return resource.PropertyValue{}, nil, err
+		}
+		template.WriteString("%s")
+		args = append(args, v)
+		refs = append(refs, part.ref.resource)
+	}
+	if len(args) == 0 {
+		return resource.NewStringProperty(text.String()), nil, nil
+	}
+	return resource.NewFormatProperty(resource.Format{Template: template.String(), Args: args}), refs, nil
+}
+
+// lookup returns the value that a reference refers to.  Output properties that are missing are unknown during
+// previews and null otherwise.
+func (e *evaluator) lookup(ref *reference) (resource.PropertyValue, error) {
+	r := e.resources[ref.resource]
+	if len(ref.path) == 1 {
+		switch ref.path[0] {
+		case "urn":
+			return resource.NewStringProperty(string(r.urn)), nil
+		case "id":
+			if r.id == "" {
+				return resource.MakeComputed(resource.NewStringProperty("")), nil
+			}
+			return resource.NewStringProperty(string(r.id)), nil
+		}
+	}
+	if v, has := ref.path.Get(r.outputs); has {
+		return v, nil
+	}
+	if e.info.DryRun {
+		return resource.MakeComputed(resource.NewStringProperty("")), nil
+	}
+	return resource.NewNullProperty(), nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package declarative
+
+import (
+	"testing"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// testMonitor is a resource monitor that records the resources registered with it.  Custom resources get an ID and
+// a `domain` output derived from their name unless this is a preview, in which case their outputs are their inputs.
+type testMonitor struct {
+	dryRun    bool
+	requests  []*pulumirpc.RegisterResourceRequest
+	inputs    map[string]resource.PropertyMap
+	outputs   resource.PropertyMap
+	outputURN string
+}
+
+func (m *testMonitor) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest,
+	opts ...grpc.CallOption) (*pulumirpc.InvokeResponse, error) {
+	panic("not implemented")
+}
+
+func (m *testMonitor) ReadResource(ctx context.Context, req *pulumirpc.ReadResourceRequest,
+	opts ...grpc.CallOption) (*pulumirpc.ReadResourceResponse, error) {
+	panic("not implemented")
+}
+
+func (m *testMonitor) RegisterResource(ctx context.Context, req *pulumirpc.RegisterResourceRequest,
+	opts ...grpc.CallOption) (*pulumirpc.RegisterResourceResponse, error) {
+
+	m.requests = append(m.requests, req)
+	inputs, err := plugin.UnmarshalProperties(req.GetObject(), plugin.MarshalOptions{KeepUnknowns: true})
+	if err != nil {
+		return nil, err
+	}
+	inputs, err = inputs.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	if m.inputs == nil {
+		m.inputs = make(map[string]resource.PropertyMap)
+	}
+	m.inputs[req.GetName()] = inputs
+
+	urn := resource.NewURN("stack", "project", "", tokens.Type(req.GetType()), tokens.QName(req.GetName()))
+	var id string
+	outputs := inputs.Copy()
+	if req.GetCustom() && !m.dryRun {
+		id = req.GetName() + "-id"
+		outputs["domain"] = resource.NewStringProperty(req.GetName() + ".example.com")
+	}
+	object, err := plugin.MarshalProperties(outputs, plugin.MarshalOptions{KeepUnknowns: true})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.RegisterResourceResponse{Urn: string(urn), Id: id, Object: object}, nil
+}
+
+func (m *testMonitor) RegisterResourceOutputs(ctx context.Context, req *pulumirpc.RegisterResourceOutputsRequest,
+	opts ...grpc.CallOption) (*pbempty.Empty, error) {
+
+	outputs, err := plugin.UnmarshalProperties(req.GetOutputs(), plugin.MarshalOptions{KeepUnknowns: true})
+	if err != nil {
+		return nil, err
+	}
+	m.outputs, err = outputs.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	m.outputURN = req.GetUrn()
+	return &pbempty.Empty{}, nil
+}
+
+const testDocument = `
+resources:
+  site:
+    type: test:index:Site
+    properties:
+      bucket: ${bucket.id}
+      url: "https://${bucket.domain}/index.html?x=100%"
+      tags:
+        - ${bucket.tags[0]}
+        - $${literal}
+    options:
+      dependsOn: [ group ]
+      parent: group
+  bucket:
+    type: test:index:Bucket
+    properties:
+      tags: [ web ]
+  group:
+    type: test:index:Group
+    component: true
+outputs:
+  url: ${site.url}
+  bucketURN: ${bucket.urn}
+`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(testDocument))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bucket", "group", "site"}, doc.ResourceNames())
+	assert.Equal(t, []tokens.Package{"test"}, doc.Packages())
+
+	order, err := doc.sortResources()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bucket", "group", "site"}, order)
+
+	// Nested objects are plain maps.
+	doc, err = Parse([]byte("resources:\n  a:\n    type: test:index:A\n    properties:\n      o: { k: v }\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"k": "v"}, doc.Resources["a"].Properties["o"])
+
+	// Providers require the plugin of the package they configure.
+	doc, err = Parse([]byte("resources:\n  p:\n    type: pulumi:providers:aws\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []tokens.Package{"aws"}, doc.Packages())
+
+	for _, bad := range []string{
+		"resources:\n  a:\n    properties: {}\n",
+		"resources:\n  a:\n    type: Bucket\n",
+		"resources:\n  a:\n    type: test:index:A\n    properties:\n      x: ${b.id}\n",
+		"resources:\n  a:\n    type: test:index:A\n    properties:\n      x: ${a.id}\n",
+		"resources:\n  a:\n    type: test:index:A\n    properties:\n      x: ${b}\n  b:\n    type: test:index:B\n",
+		"resources:\n  a:\n    type: test:index:A\n    properties:\n      x: ${b.id\n",
+		"resources:\n  a:\n    type: test:index:A\n    options:\n      dependsOn: [ b ]\n  b:\n" +
+			"    type: test:index:B\n    options:\n      parent: a\n",
+		"resources:\n  a:\n    type: test:index:A\noutputs:\n  x: ${b.id}\n",
+	} {
+		_, err = Parse([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRun(t *testing.T) {
+	doc, err := Parse([]byte(testDocument))
+	assert.NoError(t, err)
+
+	monitor := &testMonitor{}
+	err = Run(context.Background(), monitor, RunInfo{Project: "project", Stack: "stack"}, doc)
+	assert.NoError(t, err)
+
+	// The stack is registered first, and every other resource after the resources it depends on.
+	var names []string
+	for _, req := range monitor.requests {
+		names = append(names, req.GetName())
+	}
+	assert.Equal(t, []string{"project-stack", "bucket", "group", "site"}, names)
+
+	stackURN := string(resource.NewURN("stack", "project", "", "pulumi:pulumi:Stack", "project-stack"))
+	bucketURN := string(resource.NewURN("stack", "project", "", "test:index:Bucket", "bucket"))
+	groupURN := string(resource.NewURN("stack", "project", "", "test:index:Group", "group"))
+
+	bucket, group, site := monitor.requests[1], monitor.requests[2], monitor.requests[3]
+	assert.True(t, bucket.GetCustom())
+	assert.False(t, group.GetCustom())
+	assert.Equal(t, stackURN, bucket.GetParent())
+	assert.Equal(t, groupURN, site.GetParent())
+	assert.Equal(t, []string{bucketURN, groupURN}, site.GetDependencies())
+	assert.Equal(t, []string{bucketURN}, site.GetPropertyDependencies()["bucket"].GetUrns())
+	assert.Empty(t, bucket.GetDependencies())
+
+	assert.Equal(t, resource.PropertyMap{
+		"bucket": resource.NewStringProperty("bucket-id"),
+		"url":    resource.NewStringProperty("https://bucket.example.com/index.html?x=100%"),
+		"tags": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewStringProperty("web"),
+			resource.NewStringProperty("${literal}"),
+		}),
+	}, monitor.inputs["site"])
+
+	assert.Equal(t, stackURN, monitor.outputURN)
+	assert.Equal(t, resource.PropertyMap{
+		"url":       resource.NewStringProperty("https://bucket.example.com/index.html?x=100%"),
+		"bucketURN": resource.NewStringProperty(bucketURN),
+	}, monitor.outputs)
+}
+
+func TestRunPreview(t *testing.T) {
+	doc, err := Parse([]byte(testDocument))
+	assert.NoError(t, err)
+
+	// During previews, IDs and missing outputs are unknown, as are the strings that interpolate them.
+	monitor := &testMonitor{dryRun: true}
+	err = Run(context.Background(), monitor, RunInfo{Project: "project", Stack: "stack", DryRun: true}, doc)
+	assert.NoError(t, err)
+
+	site := monitor.inputs["site"]
+	assert.True(t, site["bucket"].IsComputed())
+	assert.True(t, site["url"].IsComputed())
+	assert.Equal(t, resource.NewStringProperty("web"), site["tags"].ArrayValue()[0])
+	assert.True(t, monitor.outputs["url"].IsComputed())
+}
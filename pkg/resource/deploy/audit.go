@@ -0,0 +1,166 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// AuditLog is an append-only sink for the audit log entries recorded during a plan's execution.
+type AuditLog interface {
+	// Append durably records the given entry after all previously appended entries.
+	Append(entry apitype.AuditLogEntry) error
+}
+
+// AuditLogDigest computes the digest of the given entry, which covers every field except the entry's own digest.
+func AuditLogDigest(entry apitype.AuditLogEntry) string {
+	entry.Digest = ""
+	bytes, err := json.Marshal(entry)
+	contract.AssertNoError(err)
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditLog checks that the given entries form an unbroken hash chain, and returns an error describing the first
+// entry that was removed, reordered, or altered. The chain is not keyed, so a log that was truncated or rewritten from
+// scratch is only detected if final, the digest recorded for the log's last entry when its update finished, is given.
+func VerifyAuditLog(entries []apitype.AuditLogEntry, final string) error {
+	prev := ""
+	for i, entry := range entries {
+		if entry.Sequence != i+1 {
+			return errors.Errorf("audit log entry %d has sequence number %d", i+1, entry.Sequence)
+		}
+		if entry.PreviousDigest != prev {
+			return errors.Errorf("audit log entry %d does not follow the entry before it", entry.Sequence)
+		}
+		if AuditLogDigest(entry) != entry.Digest {
+			return errors.Errorf("audit log entry %d has been altered", entry.Sequence)
+		}
+		prev = entry.Digest
+	}
+	if final != "" && prev != final {
+		return errors.New("audit log does not end with the entry recorded for its update")
+	}
+	return nil
+}
+
+// auditor numbers and chains audit log entries before appending them to an audit log.
+type auditor struct {
+	log  AuditLog
+	diag diag.Sink
+
+	lock     sync.Mutex
+	sequence int    // the sequence number of the last entry.
+	prev     string // the digest of the last entry.
+}
+
+func newAuditor(log AuditLog, sink diag.Sink) *auditor {
+	if log == nil {
+		return nil
+	}
+	return &auditor{log: log, diag: sink}
+}
+
+// record appends an entry for a provider operation that started at the given time. Failures to record the entry are
+// reported as errors, but do not fail the operation itself, which has already been applied.
+func (a *auditor) record(urn resource.URN, op string, id resource.ID, inputs, outputs resource.PropertyMap,
+	start time.Time, opErr error) {
+
+	entry := apitype.AuditLogEntry{
+		Timestamp:  start.UTC().Format(time.RFC3339Nano),
+		URN:        string(urn),
+		Operation:  op,
+		ID:         string(id),
+		DurationMS: time.Since(start).Nanoseconds() / int64(time.Millisecond),
+		Result:     apitype.AuditLogSucceeded,
+	}
+	if inputs != nil {
+		entry.InputsDigest = inputs.Fingerprint().String()
+	}
+	if outputs != nil {
+		entry.OutputsDigest = outputs.Fingerprint().String()
+	}
+	if opErr != nil {
+		// Provider errors often echo the values they reject, so filter out any secrets they hold.
+		entry.Result, entry.Error = apitype.AuditLogFailed, logging.FilterString(opErr.Error())
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	entry.Sequence, entry.PreviousDigest = a.sequence+1, a.prev
+	entry.Digest = AuditLogDigest(entry)
+	if err := a.log.Append(entry); err != nil {
+		a.diag.Errorf(diag.GetAuditLogError(urn), op, urn.Name(), err)
+		return
+	}
+	a.sequence, a.prev = entry.Sequence, entry.Digest
+}
+
+// wrap returns a provider whose Create, Update, and Delete calls are recorded by this auditor. If the auditor is nil,
+// the provider is returned unchanged.
+func (a *auditor) wrap(prov plugin.Provider) plugin.Provider {
+	if a == nil {
+		return prov
+	}
+	return &auditingProvider{Provider: prov, audit: a}
+}
+
+// auditingProvider wraps a provider s.t. each of its resource mutations is recorded in the plan's audit log.
+type auditingProvider struct {
+	plugin.Provider
+
+	audit *auditor
+}
+
+func (p *auditingProvider) Create(urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+	start := time.Now()
+	id, outs, status, err := p.Provider.Create(urn, news, timeout)
+	p.audit.record(urn, "create", id, news, outs, start, err)
+	return id, outs, status, err
+}
+
+func (p *auditingProvider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
+
+	start := time.Now()
+	outs, status, err := p.Provider.Update(urn, id, oldInputs, oldOutputs, newInputs, timeout)
+	p.audit.record(urn, "update", id, newInputs, outs, start, err)
+	return outs, status, err
+}
+
+func (p *auditingProvider) Delete(urn resource.URN, id resource.ID,
+	props resource.PropertyMap, timeout float64) (resource.Status, error) {
+
+	start := time.Now()
+	status, err := p.Provider.Delete(urn, id, props, timeout)
+	p.audit.record(urn, "delete", id, props, nil, start, err)
+	return status, err
+}
@@ -0,0 +1,117 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+type memoryAuditLog struct {
+	entries []apitype.AuditLogEntry
+}
+
+func (l *memoryAuditLog) Append(entry apitype.AuditLogEntry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func TestAuditingProvider(t *testing.T) {
+	prov := &deploytest.Provider{
+		CreateF: func(urn resource.URN,
+			news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+			return "id1", resource.PropertyMap{"out": resource.NewStringProperty("secret")}, resource.StatusOK, nil
+		},
+		DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+			return resource.StatusOK, errors.New("resource is in use")
+		},
+	}
+
+	log := &memoryAuditLog{}
+	audited := newAuditor(log, cmdutil.Diag()).wrap(prov)
+
+	urn := resource.URN("urn:pulumi:stack::proj::pkgA:m:typA::resA")
+	inputs := resource.PropertyMap{"in": resource.NewStringProperty("password")}
+	id, outs, _, err := audited.Create(urn, inputs, 0)
+	assert.NoError(t, err)
+	_, err = audited.Delete(urn, id, outs, 0)
+	assert.Error(t, err)
+
+	assert.Len(t, log.entries, 2)
+	create, del := log.entries[0], log.entries[1]
+
+	assert.Equal(t, 1, create.Sequence)
+	assert.Equal(t, "create", create.Operation)
+	assert.Equal(t, string(urn), create.URN)
+	assert.Equal(t, "id1", create.ID)
+	assert.Equal(t, inputs.Fingerprint().String(), create.InputsDigest)
+	assert.Equal(t, outs.Fingerprint().String(), create.OutputsDigest)
+	assert.Equal(t, apitype.AuditLogSucceeded, create.Result)
+	assert.Empty(t, create.PreviousDigest)
+
+	assert.Equal(t, 2, del.Sequence)
+	assert.Equal(t, "delete", del.Operation)
+	assert.Empty(t, del.OutputsDigest)
+	assert.Equal(t, apitype.AuditLogFailed, del.Result)
+	assert.Equal(t, "resource is in use", del.Error)
+	assert.Equal(t, create.Digest, del.PreviousDigest)
+
+	assert.NoError(t, VerifyAuditLog(log.entries, del.Digest))
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	var entries []apitype.AuditLogEntry
+	prev := ""
+	for i, op := range []string{"create", "update", "delete"} {
+		entry := apitype.AuditLogEntry{
+			Sequence:       i + 1,
+			URN:            "urn:pulumi:stack::proj::pkgA:m:typA::resA",
+			Operation:      op,
+			Result:         apitype.AuditLogSucceeded,
+			PreviousDigest: prev,
+		}
+		entry.Digest = AuditLogDigest(entry)
+		entries, prev = append(entries, entry), entry.Digest
+	}
+	assert.NoError(t, VerifyAuditLog(entries, ""))
+	assert.NoError(t, VerifyAuditLog(entries, prev))
+
+	// An altered entry no longer matches its digest.
+	altered := append([]apitype.AuditLogEntry{}, entries...)
+	altered[1].Result = apitype.AuditLogFailed
+	assert.EqualError(t, VerifyAuditLog(altered, ""), "audit log entry 2 has been altered")
+
+	// Recomputing the altered entry's digest breaks the link to the entry after it.
+	altered[1].Digest = AuditLogDigest(altered[1])
+	assert.EqualError(t, VerifyAuditLog(altered, ""), "audit log entry 3 does not follow the entry before it")
+
+	// Removing an entry leaves a gap in the sequence.
+	removed := []apitype.AuditLogEntry{entries[0], entries[2]}
+	assert.EqualError(t, VerifyAuditLog(removed, ""), "audit log entry 2 has sequence number 3")
+
+	// A log that was truncated, or rewritten from scratch, still forms a chain, but no longer ends with the entry
+	// recorded for its update.
+	truncated := entries[:2]
+	assert.NoError(t, VerifyAuditLog(truncated, ""))
+	assert.EqualError(t, VerifyAuditLog(truncated, prev), "audit log does not end with the entry recorded for its update")
+}
@@ -0,0 +1,142 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/rand"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// AutoNaming is a strategy for generating the physical names of resources whose programs do not supply one. A
+// generated name is the resource's logical name, a hyphen, and a random suffix, surrounded by an optional prefix and
+// suffix.
+type AutoNaming struct {
+	Length  int    `json:"length,omitempty"`  // the number of random characters in each name.
+	Charset string `json:"charset,omitempty"` // the characters from which the random characters are drawn.
+	Prefix  string `json:"prefix,omitempty"`  // an optional prefix for each name.
+	Suffix  string `json:"suffix,omitempty"`  // an optional suffix for each name.
+}
+
+// AutoNamingConfig is a stack's auto-naming configuration. Its strategy applies to every auto-named resource unless
+// overridden by a per-resource strategy, keyed by resource URN or type. Fields that a strategy leaves unset fall back
+// to the stack's strategy, and then to DefaultAutoNaming.
+type AutoNamingConfig struct {
+	AutoNaming
+	Resources map[string]AutoNaming `json:"resources,omitempty"`
+}
+
+// DefaultAutoNaming supplies any fields that a stack's auto-naming configuration leaves unset.
+var DefaultAutoNaming = AutoNaming{Length: 7, Charset: "abcdefghijklmnopqrstuvwxyz0123456789"}
+
+// DefaultAutoNamedTypes maps each resource type that may be auto-named to the property that holds its physical name.
+// Physical names are generated for these properties unless a plan's options supply a different set of types.
+var DefaultAutoNamedTypes = map[tokens.Type]resource.PropertyKey{
+	"aws:dynamodb/table:Table":               "name",
+	"aws:iam/role:Role":                      "name",
+	"aws:kinesis/stream:Stream":              "name",
+	"aws:lambda/function:Function":           "name",
+	"aws:s3/bucket:Bucket":                   "bucket",
+	"aws:sns/topic:Topic":                    "name",
+	"aws:sqs/queue:Queue":                    "name",
+	"azure:core/resourceGroup:ResourceGroup": "name",
+	"gcp:compute/instance:Instance":          "name",
+	"gcp:storage/bucket:Bucket":              "name",
+}
+
+// strategy returns the auto-naming strategy for the resource with the given URN and type.
+func (c *AutoNamingConfig) strategy(urn resource.URN, t tokens.Type) AutoNaming {
+	s := c.AutoNaming.withDefaults(DefaultAutoNaming)
+	if r, has := c.Resources[string(urn)]; has {
+		return r.withDefaults(s)
+	}
+	if r, has := c.Resources[string(t)]; has {
+		return r.withDefaults(s)
+	}
+	return s
+}
+
+// withDefaults fills in any fields of this strategy that are unset from the given strategy.
+func (a AutoNaming) withDefaults(d AutoNaming) AutoNaming {
+	if a.Length == 0 {
+		a.Length = d.Length
+	}
+	if a.Charset == "" {
+		a.Charset = d.Charset
+	}
+	if a.Prefix == "" {
+		a.Prefix = d.Prefix
+	}
+	if a.Suffix == "" {
+		a.Suffix = d.Suffix
+	}
+	return a
+}
+
+// newName generates a fresh physical name for the resource with the given URN.
+func (a AutoNaming) newName(urn resource.URN) (string, error) {
+	if a.Length < 0 || a.Charset == "" {
+		return "", errors.Errorf("invalid auto-naming strategy for '%v'", urn)
+	}
+
+	random := make([]byte, a.Length)
+	if _, err := rand.Read(random); err != nil {
+		return "", errors.Wrapf(err, "generating a name for '%v'", urn)
+	}
+	for i, b := range random {
+		random[i] = a.Charset[int(b)%len(a.Charset)]
+	}
+	return a.Prefix + string(urn.Name()) + "-" + string(random) + a.Suffix, nil
+}
+
+// autoName sets the given name property of a resource's inputs to a generated physical name, unless the program
+// supplied one, and returns the resulting inputs and whether a name was generated. A name generated for the old
+// inputs, as recorded in the old resource's defaults, is reused so that the resource keeps its name across updates.
+// If olds is nil, a fresh name is always generated.
+func autoName(config *AutoNamingConfig, urn resource.URN, t tokens.Type, key resource.PropertyKey,
+	inputs, olds resource.PropertyMap, oldDefaults []resource.PropertyKey) (resource.PropertyMap, bool, error) {
+
+	if config == nil || key == "" || inputs.HasValue(key) {
+		return inputs, false, nil
+	}
+
+	var name resource.PropertyValue
+	if old, has := olds[key]; has && old.IsString() && containsKey(oldDefaults, key) {
+		name = old
+	} else {
+		generated, err := config.strategy(urn, t).newName(urn)
+		if err != nil {
+			return nil, false, err
+		}
+		name = resource.NewStringProperty(generated)
+	}
+
+	result := inputs.Copy()
+	result[key] = name
+	return result, true, nil
+}
+
+// containsKey returns true if the given list of property keys contains the given key.
+func containsKey(keys []resource.PropertyKey, key resource.PropertyKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestAutoNamingStrategy(t *testing.T) {
+	urnA := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA")
+	urnB := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resB")
+	config := &AutoNamingConfig{
+		AutoNaming: AutoNaming{Prefix: "dev-"},
+		Resources: map[string]AutoNaming{
+			string(urnA):  {Length: 3},
+			"pkgA:m:typA": {Charset: "x"},
+		},
+	}
+
+	// Strategies keyed by URN take precedence over those keyed by type, and unset fields fall back to the stack's
+	// strategy and then to the defaults.
+	assert.Equal(t, AutoNaming{Length: 3, Charset: DefaultAutoNaming.Charset, Prefix: "dev-"},
+		config.strategy(urnA, "pkgA:m:typA"))
+	assert.Equal(t, AutoNaming{Length: 7, Charset: "x", Prefix: "dev-"}, config.strategy(urnB, "pkgA:m:typA"))
+	assert.Equal(t, AutoNaming{Length: 7, Charset: DefaultAutoNaming.Charset, Prefix: "dev-"},
+		config.strategy(urnB, "pkgA:m:typB"))
+
+	name, err := AutoNaming{Length: 4, Charset: "x", Prefix: "p-", Suffix: "-s"}.newName(urnB)
+	assert.NoError(t, err)
+	assert.Equal(t, "p-resB-xxxx-s", name)
+
+	_, err = AutoNaming{Length: 4}.newName(urnB)
+	assert.Error(t, err)
+}
+
+func TestAutoName(t *testing.T) {
+	urn := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA")
+	config := &AutoNamingConfig{AutoNaming: AutoNaming{Charset: "x"}}
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{"name": "resA-old"})
+
+	// A name generated for the old inputs is reused.
+	inputs, named, err := autoName(config, urn, "pkgA:m:typA", "name", resource.PropertyMap{}, olds,
+		[]resource.PropertyKey{"name"})
+	assert.NoError(t, err)
+	assert.True(t, named)
+	assert.Equal(t, olds, inputs)
+
+	// A name supplied by the old program is not.
+	inputs, named, err = autoName(config, urn, "pkgA:m:typA", "name", resource.PropertyMap{}, olds, nil)
+	assert.NoError(t, err)
+	assert.True(t, named)
+	assert.Equal(t, resource.NewStringProperty("resA-xxxxxxx"), inputs["name"])
+
+	// Names supplied by the program are left alone, as are resources when auto-naming is disabled.
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{"name": "mine"})
+	inputs, named, err = autoName(config, urn, "pkgA:m:typA", "name", news, nil, nil)
+	assert.NoError(t, err)
+	assert.False(t, named)
+	assert.Equal(t, news, inputs)
+
+	inputs, named, err = autoName(nil, urn, "pkgA:m:typA", "name", resource.PropertyMap{}, nil, nil)
+	assert.NoError(t, err)
+	assert.False(t, named)
+	assert.Equal(t, resource.PropertyMap{}, inputs)
+}
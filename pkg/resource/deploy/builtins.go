@@ -59,34 +59,54 @@ const stackReferenceType = "pulumi:pulumi:StackReference"
 func (p *builtinProvider) Check(urn resource.URN, state, inputs resource.PropertyMap,
 	allowUnknowns bool) (resource.PropertyMap, []plugin.CheckFailure, error) {
 
-	typ := urn.Type()
-	if typ != stackReferenceType {
+	// The inputs are checked as the values they hold, but are returned with their secrets intact so that they are
+	// still recorded as secrets.
+	switch urn.Type() {
+	case stackReferenceType:
+		failures := checkStackReference(revealSecrets(inputs))
+		return inputs, failures, nil
+	case commandType:
+		failures := checkCommand(revealSecrets(inputs))
+		return inputs, failures, nil
+	case webhookCallType:
+		failures := checkHTTPRequest(revealSecrets(inputs), true)
+		return secretWebhookHeaders(inputs), failures, nil
+	default:
 		return nil, nil, errors.Errorf("unrecognized resource type '%v'", urn.Type())
 	}
+}
 
+func checkStackReference(inputs resource.PropertyMap) []plugin.CheckFailure {
 	var name resource.PropertyValue
 	for k := range inputs {
 		if k != "name" {
-			return nil, []plugin.CheckFailure{{Property: k, Reason: fmt.Sprintf("unknown property \"%v\"", k)}}, nil
+			return []plugin.CheckFailure{{Property: k, Reason: fmt.Sprintf("unknown property \"%v\"", k)}}
 		}
 	}
 
 	name, ok := inputs["name"]
 	if !ok {
-		return nil, []plugin.CheckFailure{{Property: "name", Reason: `missing required property "name"`}}, nil
+		return []plugin.CheckFailure{{Property: "name", Reason: `missing required property "name"`}}
 	}
 	if !name.IsString() && !name.IsComputed() {
-		return nil, []plugin.CheckFailure{{Property: "name", Reason: `property "name" must be a string`}}, nil
+		return []plugin.CheckFailure{{Property: "name", Reason: `property "name" must be a string`}}
 	}
-	return inputs, nil, nil
+	return nil
 }
 
-func (p *builtinProvider) Diff(urn resource.URN, id resource.ID, state, inputs resource.PropertyMap,
+func (p *builtinProvider) Diff(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
 	allowUnknowns bool) (plugin.DiffResult, error) {
 
+	oldInputs, newInputs = revealSecrets(oldInputs), revealSecrets(newInputs)
+	switch urn.Type() {
+	case commandType:
+		return diffCommand(oldInputs, newInputs), nil
+	case webhookCallType:
+		return diffWebhookCall(oldInputs, newInputs), nil
+	}
 	contract.Assert(urn.Type() == stackReferenceType)
 
-	if !inputs["name"].DeepEquals(state["name"]) {
+	if !newInputs["name"].DeepEquals(oldInputs["name"]) {
 		return plugin.DiffResult{
 			Changes:     plugin.DiffSome,
 			ReplaceKeys: []resource.PropertyKey{"name"},
@@ -96,9 +116,17 @@ func (p *builtinProvider) Diff(urn resource.URN, id resource.ID, state, inputs r
 	return plugin.DiffResult{Changes: plugin.DiffNone}, nil
 }
 
-func (p *builtinProvider) Create(urn resource.URN,
-	inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+func (p *builtinProvider) Create(urn resource.URN, inputs resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
 
+	switch urn.Type() {
+	case commandType:
+		id, outputs, err := p.createCommand(inputs, timeout)
+		return id, outputs, resource.StatusOK, err
+	case webhookCallType:
+		id, outputs, err := p.createWebhookCall(inputs, timeout)
+		return id, outputs, resource.StatusOK, err
+	}
 	contract.Assert(urn.Type() == stackReferenceType)
 
 	state, err := p.readStackReference(inputs)
@@ -109,18 +137,37 @@ func (p *builtinProvider) Create(urn resource.URN,
 	return id, state, resource.StatusOK, nil
 }
 
-func (p *builtinProvider) Update(urn resource.URN, id resource.ID, state,
-	inputs resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+func (p *builtinProvider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
+
+	switch urn.Type() {
+	case commandType:
+		outputs, err := p.updateCommand(oldInputs, oldOutputs, newInputs, timeout)
+		return outputs, resource.StatusOK, err
+	case webhookCallType:
+		outputs, err := p.callWebhook(newInputs, timeout)
+		if err != nil {
+			return oldOutputs, resource.StatusOK, err
+		}
+		return outputs, resource.StatusOK, nil
+	}
 
 	contract.Failf("unexpected update for builtin resource %v", urn)
 	contract.Assert(urn.Type() == stackReferenceType)
 
-	return state, resource.StatusOK, errors.New("unexpected update for builtin resource")
+	return oldOutputs, resource.StatusOK, errors.New("unexpected update for builtin resource")
 }
 
 func (p *builtinProvider) Delete(urn resource.URN, id resource.ID,
-	state resource.PropertyMap) (resource.Status, error) {
-
+	state resource.PropertyMap, timeout float64) (resource.Status, error) {
+
+	switch urn.Type() {
+	case commandType:
+		return resource.StatusOK, p.deleteCommand(state, timeout)
+	case webhookCallType:
+		// Webhook calls cannot be undone, so deleting one simply forgets it.
+		return resource.StatusOK, nil
+	}
 	contract.Assert(urn.Type() == stackReferenceType)
 
 	return resource.StatusOK, nil
@@ -129,6 +176,11 @@ func (p *builtinProvider) Delete(urn resource.URN, id resource.ID,
 func (p *builtinProvider) Read(urn resource.URN, id resource.ID,
 	state resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
 
+	if urn.Type() == commandType || urn.Type() == webhookCallType {
+		// Commands and webhooks are not re-run on refresh: the state recorded by the last create or update is the
+		// resource.
+		return state, resource.StatusOK, nil
+	}
 	contract.Assert(urn.Type() == stackReferenceType)
 
 	state, err := p.readStackReference(state)
@@ -142,6 +194,9 @@ func (p *builtinProvider) Read(urn resource.URN, id resource.ID,
 func (p *builtinProvider) Invoke(tok tokens.ModuleMember,
 	args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error) {
 
+	if tok == httpRequestFunction {
+		return p.invokeHTTPRequest(revealSecrets(args))
+	}
 	return nil, nil, errors.Errorf("unrecognized function name: '%v'", tok)
 }
 
@@ -158,13 +213,14 @@ func (p *builtinProvider) SignalCancellation() error {
 func (p *builtinProvider) readStackReference(inputs resource.PropertyMap) (resource.PropertyMap, error) {
 	name, ok := inputs["name"]
 	contract.Assert(ok)
-	contract.Assert(name.IsString())
+	stack := revealSecret(name)
+	contract.Assert(stack.IsString())
 
 	if p.backendClient == nil {
 		return nil, errors.New("no backend client is available")
 	}
 
-	outputs, err := p.backendClient.GetStackOutputs(p.context, name.StringValue())
+	outputs, err := p.backendClient.GetStackOutputs(p.context, stack.StringValue())
 	if err != nil {
 		return nil, err
 	}
@@ -174,3 +230,34 @@ func (p *builtinProvider) readStackReference(inputs resource.PropertyMap) (resou
 		"outputs": resource.NewObjectProperty(outputs),
 	}, nil
 }
+
+// revealSecrets returns a copy of the given properties in which each secret is replaced by the value that it holds.
+// Programs send secrets to the builtin provider as-is, but its resources and functions use them like any other values.
+func revealSecrets(props resource.PropertyMap) resource.PropertyMap {
+	if !props.ContainsSecrets() {
+		return props
+	}
+	result := make(resource.PropertyMap, len(props))
+	for k, v := range props {
+		result[k] = revealSecret(v)
+	}
+	return result
+}
+
+func revealSecret(v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsSecret():
+		return revealSecret(v.SecretValue().Element)
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			arr[i] = revealSecret(elem)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		return resource.NewObjectProperty(revealSecrets(v.ObjectValue()))
+	case v.IsComputed():
+		return resource.MakeComputed(revealSecret(v.Input().Element))
+	}
+	return v
+}
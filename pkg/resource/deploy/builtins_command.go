@@ -0,0 +1,203 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// commandType is the type of the builtin resource that runs local commands when it is created, updated, and deleted.
+//
+// Its inputs are the `create`, `update`, and `delete` commands, an object of `triggers` whose changes cause the
+// resource to be updated or replaced, an object of `environment` variables to add to the commands' environments (e.g.
+// the outputs of other resources), the `dir` in which to run the commands, and the `interpreter` that runs each
+// command, which defaults to ["/bin/sh", "-c"].  Only `create` is required.  If `update` is not set, changes to the
+// triggers or environment replace the resource instead, which runs the create command again.
+//
+// Its outputs are its inputs plus `stdout`, the standard output of the last create or update command.
+const commandType = "pulumi:command:Command"
+
+// commandStdoutKey is the output property that holds the standard output of a command resource's last command.
+const commandStdoutKey = "stdout"
+
+// commandReplaceKeys are the inputs of a command resource whose changes always require replacement.
+var commandReplaceKeys = map[resource.PropertyKey]bool{"create": true, "dir": true, "interpreter": true}
+
+// commandRerunKeys are the inputs of a command resource whose changes cause its commands to be run again.
+var commandRerunKeys = map[resource.PropertyKey]bool{"triggers": true, "environment": true}
+
+func checkCommand(inputs resource.PropertyMap) []plugin.CheckFailure {
+	var failures []plugin.CheckFailure
+	fail := func(k resource.PropertyKey, format string, args ...interface{}) {
+		failures = append(failures, plugin.CheckFailure{Property: k, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	for _, k := range inputs.StableKeys() {
+		v := inputs[k]
+		switch k {
+		case "create", "update", "delete", "dir":
+			if !v.IsString() && !v.IsComputed() {
+				fail(k, "property \"%v\" must be a string", k)
+			}
+		case "interpreter":
+			if v.IsComputed() {
+				continue
+			}
+			if !v.IsArray() || len(v.ArrayValue()) == 0 {
+				fail(k, "property \"%v\" must be a non-empty array of strings", k)
+				continue
+			}
+			for _, elem := range v.ArrayValue() {
+				if !elem.IsString() && !elem.IsComputed() {
+					fail(k, "property \"%v\" must be a non-empty array of strings", k)
+					break
+				}
+			}
+		case "environment":
+			if v.IsComputed() {
+				continue
+			}
+			if !v.IsObject() {
+				fail(k, "property \"%v\" must be an object of strings", k)
+				continue
+			}
+			for name, elem := range v.ObjectValue() {
+				if !elem.IsString() && !elem.IsComputed() {
+					fail(k, "environment variable \"%v\" must be a string", name)
+				}
+			}
+		case "triggers":
+			// Triggers may hold any value.
+		default:
+			fail(k, "unknown property \"%v\"", k)
+		}
+	}
+
+	if create, has := inputs["create"]; !has || create.IsNull() {
+		fail("create", `missing required property "create"`)
+	}
+	return failures
+}
+
+func diffCommand(oldInputs, newInputs resource.PropertyMap) plugin.DiffResult {
+	diff := oldInputs.Diff(newInputs)
+	if diff == nil {
+		return plugin.DiffResult{Changes: plugin.DiffNone}
+	}
+
+	// Changes to the triggers or environment re-run the update command if there is one, and replace the resource
+	// otherwise.
+	hasUpdate := newInputs["update"].IsComputed() ||
+		(newInputs["update"].IsString() && newInputs["update"].StringValue() != "")
+	changed := diff.ChangedKeys()
+	var replaces []resource.PropertyKey
+	for _, k := range changed {
+		if commandReplaceKeys[k] || (commandRerunKeys[k] && !hasUpdate) {
+			replaces = append(replaces, k)
+		}
+	}
+	return plugin.DiffResult{Changes: plugin.DiffSome, ChangedKeys: changed, ReplaceKeys: replaces}
+}
+
+func (p *builtinProvider) createCommand(inputs resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, error) {
+
+	stdout, err := p.runCommand(inputs, "create", timeout)
+	if err != nil {
+		return "", nil, err
+	}
+	outputs := inputs.Copy()
+	outputs[commandStdoutKey] = resource.NewStringProperty(stdout)
+	return resource.ID(uuid.NewV4().String()), outputs, nil
+}
+
+func (p *builtinProvider) updateCommand(oldInputs, oldOutputs, newInputs resource.PropertyMap,
+	timeout float64) (resource.PropertyMap, error) {
+
+	outputs := newInputs.Copy()
+	outputs[commandStdoutKey] = oldOutputs[commandStdoutKey]
+
+	// Only run the update command if the triggers or environment changed; changes to the commands themselves take
+	// effect the next time they are run.
+	rerun := false
+	if diff := oldInputs.Diff(newInputs); diff != nil {
+		for k := range commandRerunKeys {
+			rerun = rerun || diff.Changed(k)
+		}
+	}
+	if rerun {
+		stdout, err := p.runCommand(newInputs, "update", timeout)
+		if err != nil {
+			return oldOutputs, err
+		}
+		outputs[commandStdoutKey] = resource.NewStringProperty(stdout)
+	}
+	return outputs, nil
+}
+
+func (p *builtinProvider) deleteCommand(state resource.PropertyMap, timeout float64) error {
+	_, err := p.runCommand(state, "delete", timeout)
+	return err
+}
+
+// runCommand runs the command held by the given property of a command resource's inputs, if any, and returns its
+// standard output.
+func (p *builtinProvider) runCommand(inputs resource.PropertyMap, key resource.PropertyKey,
+	timeout float64) (string, error) {
+
+	inputs = revealSecrets(inputs)
+	command := inputs[key]
+	if !command.IsString() || command.StringValue() == "" {
+		return "", nil
+	}
+
+	interpreter := []string{"/bin/sh", "-c"}
+	if runtime.GOOS == "windows" {
+		interpreter = []string{"cmd", "/C"}
+	}
+	if v := inputs["interpreter"]; v.IsArray() {
+		interpreter = nil
+		for _, elem := range v.ArrayValue() {
+			interpreter = append(interpreter, elem.StringValue())
+		}
+	}
+
+	ctx := p.context
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout*float64(time.Second)))
+		defer cancel()
+	}
+
+	args := append(append([]string{}, interpreter[1:]...), command.StringValue())
+	cmd := exec.CommandContext(ctx, interpreter[0], args...)
+	cmd.Env = os.Environ()
+	if env := inputs["environment"]; env.IsObject() {
+		for _, name := range env.ObjectValue().StableKeys() {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", name, env.ObjectValue()[name].StringValue()))
+		}
+	}
+	if dir := inputs["dir"]; dir.IsString() {
+		cmd.Dir = dir.StringValue()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	logging.V(7).Infof("Running %s command of builtin command resource: %v", key, command.StringValue())
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "%s command failed: %s", key, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
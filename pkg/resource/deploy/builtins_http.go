@@ -0,0 +1,217 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// httpRequestFunction is the builtin function that sends an HTTP request and returns its response, e.g. to fetch a
+// token from an external system.  Its arguments are the request's `url` (required), `method` (GET by default),
+// `headers`, and `body`.  It returns the response's `status`, `headers`, and `body`.  Because functions are also
+// invoked during previews, only GET and HEAD requests may be sent.
+const httpRequestFunction tokens.ModuleMember = "pulumi:http:request"
+
+// webhookCallType is the type of the builtin resource that sends an HTTP request when it is created and whenever its
+// inputs change, e.g. to notify an external system of a deployment.  Its inputs are those of httpRequestFunction,
+// except that its method is POST by default, plus `triggers`, arbitrary values whose changes cause the request to be
+// sent again.  Its outputs are its inputs plus the `response` to the last request, which holds the response's `status`,
+// `headers`, and `body`.  The request's headers and the response's body often hold credentials, so they are recorded
+// as secrets, both in the resource's inputs and in its outputs.
+const webhookCallType = "pulumi:webhook:Call"
+
+// webhookResponseKey is the output property that holds the response to a webhook call's last request.
+const webhookResponseKey = "response"
+
+// defaultHTTPTimeout bounds builtin HTTP requests that are not given a timeout of their own.
+const defaultHTTPTimeout = 5 * time.Minute
+
+// maxHTTPResponseSize is the largest response body that a builtin HTTP request will read.
+const maxHTTPResponseSize = 10 * 1024 * 1024
+
+func checkHTTPRequest(inputs resource.PropertyMap, allowTriggers bool) []plugin.CheckFailure {
+	var failures []plugin.CheckFailure
+	fail := func(k resource.PropertyKey, format string, args ...interface{}) {
+		failures = append(failures, plugin.CheckFailure{Property: k, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	for _, k := range inputs.StableKeys() {
+		v := inputs[k]
+		switch {
+		case k == "url" || k == "method" || k == "body":
+			if !v.IsString() && !v.IsComputed() {
+				fail(k, "property \"%v\" must be a string", k)
+			}
+		case k == "headers":
+			if v.IsComputed() {
+				continue
+			}
+			if !v.IsObject() {
+				fail(k, "property \"%v\" must be an object of strings", k)
+				continue
+			}
+			for name, elem := range v.ObjectValue() {
+				if !elem.IsString() && !elem.IsComputed() {
+					fail(k, "header \"%v\" must be a string", name)
+				}
+			}
+		case k == "triggers" && allowTriggers:
+			// Triggers may hold any value.
+		default:
+			fail(k, "unknown property \"%v\"", k)
+		}
+	}
+
+	if url, has := inputs["url"]; !has || url.IsNull() {
+		fail("url", `missing required property "url"`)
+	}
+	return failures
+}
+
+func (p *builtinProvider) invokeHTTPRequest(args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure,
+	error) {
+
+	if failures := checkHTTPRequest(args, false); len(failures) > 0 {
+		return nil, failures, nil
+	}
+	if method := httpMethod(args, "GET"); method != "GET" && method != "HEAD" {
+		reason := fmt.Sprintf("%s requests may not be sent by %s, which also runs during previews; use a %s resource",
+			method, httpRequestFunction, webhookCallType)
+		return nil, []plugin.CheckFailure{{Property: "method", Reason: reason}}, nil
+	}
+	response, err := p.sendHTTPRequest(args, "GET", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, nil, nil
+}
+
+// httpMethod returns the method of the HTTP request described by the given inputs.
+func httpMethod(inputs resource.PropertyMap, defaultMethod string) string {
+	if v := inputs["method"]; v.IsString() && v.StringValue() != "" {
+		return strings.ToUpper(v.StringValue())
+	}
+	return defaultMethod
+}
+
+func diffWebhookCall(oldInputs, newInputs resource.PropertyMap) plugin.DiffResult {
+	diff := oldInputs.Diff(newInputs)
+	if diff == nil {
+		return plugin.DiffResult{Changes: plugin.DiffNone}
+	}
+	// Any change simply sends the request again; there is nothing to replace.
+	return plugin.DiffResult{Changes: plugin.DiffSome, ChangedKeys: diff.ChangedKeys()}
+}
+
+func (p *builtinProvider) callWebhook(inputs resource.PropertyMap, timeout float64) (resource.PropertyMap, error) {
+	response, err := p.sendHTTPRequest(inputs, "POST", timeout)
+	if err != nil {
+		return nil, err
+	}
+	response["body"] = resource.MakeSecret(response["body"])
+	outputs := secretWebhookHeaders(inputs)
+	outputs[webhookResponseKey] = resource.NewObjectProperty(response)
+	return outputs, nil
+}
+
+// secretWebhookHeaders returns a copy of the given webhook call inputs in which each of the request's headers is a
+// secret.
+func secretWebhookHeaders(inputs resource.PropertyMap) resource.PropertyMap {
+	result := inputs.Copy()
+	if headers := inputs["headers"]; headers.IsObject() {
+		secrets := make(resource.PropertyMap, len(headers.ObjectValue()))
+		for name, v := range headers.ObjectValue() {
+			if !v.IsSecret() {
+				v = resource.MakeSecret(v)
+			}
+			secrets[name] = v
+		}
+		result["headers"] = resource.NewObjectProperty(secrets)
+	}
+	return result
+}
+
+func (p *builtinProvider) createWebhookCall(inputs resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, error) {
+
+	outputs, err := p.callWebhook(inputs, timeout)
+	if err != nil {
+		return "", nil, err
+	}
+	return resource.ID(uuid.NewV4().String()), outputs, nil
+}
+
+// sendHTTPRequest sends the HTTP request described by the given inputs and returns the response's status, headers,
+// and body.  Responses with a status other than 2xx are reported as errors.  Requests without a timeout are given
+// defaultHTTPTimeout, and response bodies larger than maxHTTPResponseSize are rejected.  A header that appears more
+// than once in the response is returned as its values joined by commas.
+func (p *builtinProvider) sendHTTPRequest(inputs resource.PropertyMap, defaultMethod string,
+	timeout float64) (resource.PropertyMap, error) {
+
+	inputs = revealSecrets(inputs)
+	url := inputs["url"].StringValue()
+	method := httpMethod(inputs, defaultMethod)
+	var body string
+	if v := inputs["body"]; v.IsString() {
+		body = v.StringValue()
+	}
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid request to %s", url)
+	}
+	if headers := inputs["headers"]; headers.IsObject() {
+		for _, name := range headers.ObjectValue().StableKeys() {
+			req.Header.Set(string(name), headers.ObjectValue()[name].StringValue())
+		}
+	}
+
+	duration := defaultHTTPTimeout
+	if timeout > 0 {
+		duration = time.Duration(timeout * float64(time.Second))
+	}
+	ctx, cancel := context.WithTimeout(p.context, duration)
+	defer cancel()
+
+	logging.V(7).Infof("Sending builtin HTTP request: %s %s", method, url)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", method, url)
+	}
+	defer contract.IgnoreClose(resp.Body)
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading the response to %s %s", method, url)
+	}
+	if len(respBody) > maxHTTPResponseSize {
+		return nil, errors.Errorf("the response to %s %s is larger than %d bytes", method, url, maxHTTPResponseSize)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.Errorf("%s %s failed with status %s: %s", method, url, resp.Status,
+			strings.TrimSpace(string(respBody)))
+	}
+
+	respHeaders := resource.PropertyMap{}
+	for name, values := range resp.Header {
+		respHeaders[resource.PropertyKey(name)] = resource.NewStringProperty(strings.Join(values, ", "))
+	}
+
+	return resource.PropertyMap{
+		"status":  resource.NewNumberProperty(float64(resp.StatusCode)),
+		"headers": resource.NewObjectProperty(respHeaders),
+		"body":    resource.NewStringProperty(string(respBody)),
+	}, nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+func TestBuiltinCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command tests use a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "command")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := newBuiltinProvider(nil)
+	urn := resource.NewURN("stack", "proj", "", commandType, "cmd")
+
+	inputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"create":      "echo created $NAME",
+		"update":      "echo updated $NAME",
+		"delete":      "touch deleted",
+		"dir":         dir,
+		"environment": map[string]interface{}{"NAME": "web"},
+		"triggers":    map[string]interface{}{"version": 1},
+	})
+	_, failures, err := p.Check(urn, nil, inputs, false)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+
+	_, failures, err = p.Check(urn, nil, resource.NewPropertyMapFromMap(map[string]interface{}{"run": "ls"}), false)
+	assert.NoError(t, err)
+	assert.Len(t, failures, 2)
+
+	// Creating the resource runs its create command and captures its output.
+	id, outputs, _, err := p.Create(urn, inputs, 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, resource.ID(""), id)
+	assert.Equal(t, "created web\n", outputs["stdout"].StringValue())
+
+	// Changing only the delete command neither replaces the resource nor re-runs anything.
+	news := inputs.Copy()
+	news["delete"] = resource.NewStringProperty("touch removed")
+	diff, err := p.Diff(urn, id, inputs, outputs, news, false)
+	assert.NoError(t, err)
+	assert.Equal(t, plugin.DiffSome, diff.Changes)
+	assert.False(t, diff.Replace())
+	updated, _, err := p.Update(urn, id, inputs, outputs, news, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "created web\n", updated["stdout"].StringValue())
+
+	// Changing the triggers runs the update command.
+	olds := news
+	news = olds.Copy()
+	news["triggers"] = resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{"version": 2}))
+	diff, err = p.Diff(urn, id, olds, updated, news, false)
+	assert.NoError(t, err)
+	assert.False(t, diff.Replace())
+	updated, _, err = p.Update(urn, id, olds, updated, news, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "updated web\n", updated["stdout"].StringValue())
+
+	// Without an update command, changing the triggers replaces the resource.
+	delete(olds, "update")
+	delete(news, "update")
+	diff, err = p.Diff(urn, id, olds, updated, news, false)
+	assert.NoError(t, err)
+	assert.True(t, diff.Replace())
+	assert.Equal(t, []resource.PropertyKey{"triggers"}, diff.ReplaceKeys)
+
+	// Deleting the resource runs the delete command recorded in its state.
+	_, err = p.Delete(urn, id, updated, 0)
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "removed"))
+	assert.NoError(t, err)
+
+	// Failed commands report their standard error.
+	_, _, _, err = p.Create(urn, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"create": "echo broken >&2; exit 1",
+	}), 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+}
+
+func TestBuiltinHTTP(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+string(body))
+		switch r.URL.Path {
+		case "/missing":
+			http.Error(w, "no such thing", http.StatusNotFound)
+			return
+		case "/large":
+			_, _ = w.Write(make([]byte, maxHTTPResponseSize+1))
+			return
+		}
+		w.Header().Set("X-Token", r.Header.Get("X-Key")+"-token")
+		w.Header().Add("X-Region", "us-east-1")
+		w.Header().Add("X-Region", "us-west-2")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	p := newBuiltinProvider(nil)
+
+	// The request function sends a GET by default and returns the response.
+	ret, failures, err := p.Invoke(httpRequestFunction, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"url":     server.URL + "/token",
+		"headers": map[string]interface{}{"X-Key": "abc"},
+	}))
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, 200.0, ret["status"].NumberValue())
+	assert.Equal(t, "ok", ret["body"].StringValue())
+	assert.Equal(t, "abc-token", ret["headers"].ObjectValue()["X-Token"].StringValue())
+	assert.Equal(t, "us-east-1, us-west-2", ret["headers"].ObjectValue()["X-Region"].StringValue())
+
+	_, failures, err = p.Invoke(httpRequestFunction, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"triggers": "x",
+	}))
+	assert.NoError(t, err)
+	assert.Len(t, failures, 2)
+
+	_, _, err = p.Invoke(httpRequestFunction, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"url": server.URL + "/missing",
+	}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no such thing")
+
+	// Oversized responses are rejected.
+	_, _, err = p.Invoke(httpRequestFunction, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"url": server.URL + "/large",
+	}))
+	assert.Error(t, err)
+
+	// Functions also run during previews, so they may not send requests that change anything.
+	_, failures, err = p.Invoke(httpRequestFunction, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"url":    server.URL + "/token",
+		"method": "delete",
+	}))
+	assert.NoError(t, err)
+	assert.Len(t, failures, 1)
+
+	// Webhook calls POST when they are created and again whenever their inputs change.
+	urn := resource.NewURN("stack", "proj", "", webhookCallType, "hook")
+	inputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"url":      server.URL + "/notify",
+		"body":     "deployed",
+		"headers":  map[string]interface{}{"Authorization": "Bearer secret"},
+		"triggers": map[string]interface{}{"version": 1},
+	})
+	checked, failures, err := p.Check(urn, nil, inputs, false)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	id, outputs, _, err := p.Create(urn, inputs, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 200.0, outputs["response"].ObjectValue()["status"].NumberValue())
+
+	// The request's headers and the response's body are recorded as secrets.
+	assert.True(t, checked["headers"].ObjectValue()["Authorization"].IsSecret())
+	assert.True(t, outputs["headers"].ObjectValue()["Authorization"].IsSecret())
+	body := outputs["response"].ObjectValue()["body"]
+	assert.True(t, body.IsSecret())
+	assert.Equal(t, "ok", body.SecretValue().Element.StringValue())
+
+	diff, err := p.Diff(urn, id, inputs, outputs, inputs.Copy(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, plugin.DiffNone, diff.Changes)
+
+	news := inputs.Copy()
+	news["body"] = resource.NewStringProperty("redeployed")
+	diff, err = p.Diff(urn, id, inputs, outputs, news, false)
+	assert.NoError(t, err)
+	assert.Equal(t, plugin.DiffSome, diff.Changes)
+	assert.False(t, diff.Replace())
+	_, _, err = p.Update(urn, id, inputs, outputs, news, 0)
+	assert.NoError(t, err)
+
+	_, err = p.Delete(urn, id, outputs, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET ", "GET ", "GET ", "POST deployed", "POST redeployed"}, calls)
+}
+
+func TestBuiltinSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command tests use a POSIX shell")
+	}
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("X-Key"))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	p := newBuiltinProvider(nil)
+	secret := resource.MakeSecret(resource.NewStringProperty("abc"))
+
+	// Secret environment variables are passed to commands as the values that they hold.
+	urn := resource.NewURN("stack", "proj", "", commandType, "cmd")
+	inputs := resource.PropertyMap{
+		"create":      resource.NewStringProperty("echo $TOKEN"),
+		"environment": resource.NewObjectProperty(resource.PropertyMap{"TOKEN": secret}),
+	}
+	checked, failures, err := p.Check(urn, nil, inputs, false)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.True(t, checked["environment"].ObjectValue()["TOKEN"].IsSecret())
+	_, outputs, _, err := p.Create(urn, checked, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc\n", outputs["stdout"].StringValue())
+
+	// Secret headers are sent as the values that they hold, by both functions and webhook calls.
+	headers := resource.NewObjectProperty(resource.PropertyMap{"X-Key": secret})
+	_, failures, err = p.Invoke(httpRequestFunction, resource.PropertyMap{
+		"url":     resource.NewStringProperty(server.URL),
+		"headers": headers,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+
+	urn = resource.NewURN("stack", "proj", "", webhookCallType, "hook")
+	inputs = resource.PropertyMap{
+		"url":     resource.NewStringProperty(server.URL),
+		"headers": headers,
+	}
+	checked, failures, err = p.Check(urn, nil, inputs, false)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	_, _, _, err = p.Create(urn, checked, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "abc"}, keys)
+}
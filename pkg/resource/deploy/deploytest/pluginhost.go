@@ -147,6 +147,9 @@ func (host *pluginHost) Close() error {
 func (host *pluginHost) ServerAddr() string {
 	panic("Host RPC address not available")
 }
+func (host *pluginHost) ProviderServerAddr() string {
+	panic("Host RPC address not available")
+}
 func (host *pluginHost) Log(sev diag.Severity, urn resource.URN, msg string, streamID int32) {
 	if !host.isClosed() {
 		host.sink.Logf(sev, diag.StreamMessage(urn, msg, streamID))
@@ -38,11 +38,12 @@ type Provider struct {
 
 	CheckF func(urn resource.URN,
 		olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error)
-	DiffF   func(urn resource.URN, id resource.ID, olds, news resource.PropertyMap) (plugin.DiffResult, error)
+	DiffF func(urn resource.URN, id resource.ID,
+		oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult, error)
 	CreateF func(urn resource.URN,
 		inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error)
 	UpdateF func(urn resource.URN, id resource.ID,
-		olds, news resource.PropertyMap) (resource.PropertyMap, resource.Status, error)
+		oldInputs, oldOutputs, newInputs resource.PropertyMap) (resource.PropertyMap, resource.Status, error)
 	DeleteF func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error)
 
 	ReadF func(urn resource.URN, id resource.ID,
@@ -50,7 +51,10 @@ type Provider struct {
 	InvokeF func(tok tokens.ModuleMember,
 		inputs resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error)
 
-	CancelF func() error
+	CancelF         func() error
+	CapabilitiesF   func() plugin.ProviderCapabilities
+	PreviewOutputsF func(urn resource.URN, inputs resource.PropertyMap) resource.PropertyMap
+	GetSchemaF      func(version int) ([]byte, error)
 }
 
 func (prov *Provider) SignalCancellation() error {
@@ -60,6 +64,20 @@ func (prov *Provider) SignalCancellation() error {
 	return prov.CancelF()
 }
 
+func (prov *Provider) Capabilities() plugin.ProviderCapabilities {
+	if prov.CapabilitiesF == nil {
+		return plugin.DefaultCapabilities
+	}
+	return prov.CapabilitiesF()
+}
+
+func (prov *Provider) GetSchema(version int) ([]byte, error) {
+	if prov.GetSchemaF == nil {
+		return nil, plugin.ErrSchemaUnsupported
+	}
+	return prov.GetSchemaF(version)
+}
+
 func (prov *Provider) Close() error {
 	return nil
 }
@@ -105,7 +123,15 @@ func (prov *Provider) Check(urn resource.URN,
 	}
 	return prov.CheckF(urn, olds, news)
 }
-func (prov *Provider) Create(urn resource.URN, props resource.PropertyMap) (resource.ID,
+func (prov *Provider) CheckPreview(urn resource.URN, olds, news resource.PropertyMap,
+	allowUnknowns bool) (resource.PropertyMap, resource.PropertyMap, []plugin.CheckFailure, error) {
+	inputs, failures, err := prov.Check(urn, olds, news, allowUnknowns)
+	if err != nil || prov.PreviewOutputsF == nil {
+		return inputs, nil, failures, err
+	}
+	return inputs, prov.PreviewOutputsF(urn, inputs), failures, nil
+}
+func (prov *Provider) Create(urn resource.URN, props resource.PropertyMap, timeout float64) (resource.ID,
 	resource.PropertyMap, resource.Status, error) {
 	if prov.CreateF == nil {
 		return resource.ID(uuid.NewV4().String()), resource.PropertyMap{}, resource.StatusOK, nil
@@ -113,21 +139,21 @@ func (prov *Provider) Create(urn resource.URN, props resource.PropertyMap) (reso
 	return prov.CreateF(urn, props)
 }
 func (prov *Provider) Diff(urn resource.URN, id resource.ID,
-	olds resource.PropertyMap, news resource.PropertyMap, _ bool) (plugin.DiffResult, error) {
+	oldInputs, oldOutputs, newInputs resource.PropertyMap, _ bool) (plugin.DiffResult, error) {
 	if prov.DiffF == nil {
 		return plugin.DiffResult{}, nil
 	}
-	return prov.DiffF(urn, id, olds, news)
+	return prov.DiffF(urn, id, oldInputs, oldOutputs, newInputs)
 }
-func (prov *Provider) Update(urn resource.URN, id resource.ID,
-	olds resource.PropertyMap, news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+func (prov *Provider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
 	if prov.UpdateF == nil {
-		return news, resource.StatusOK, nil
+		return newInputs, resource.StatusOK, nil
 	}
-	return prov.UpdateF(urn, id, olds, news)
+	return prov.UpdateF(urn, id, oldInputs, oldOutputs, newInputs)
 }
 func (prov *Provider) Delete(urn resource.URN,
-	id resource.ID, props resource.PropertyMap) (resource.Status, error) {
+	id resource.ID, props resource.PropertyMap, timeout float64) (resource.Status, error) {
 	if prov.DeleteF == nil {
 		return resource.StatusOK, nil
 	}
@@ -148,3 +174,34 @@ func (prov *Provider) Invoke(tok tokens.ModuleMember,
 	}
 	return prov.InvokeF(tok, args)
 }
+
+// BatchProvider is a Provider that also reads and diffs resources in batches.
+type BatchProvider struct {
+	Provider
+
+	ReadBatchF func(reads []plugin.BatchRead) ([]plugin.BatchReadResult, error)
+	DiffBatchF func(diffs []plugin.BatchDiff) ([]plugin.BatchDiffResult, error)
+}
+
+func (prov *BatchProvider) ReadBatch(reads []plugin.BatchRead) ([]plugin.BatchReadResult, error) {
+	if prov.ReadBatchF == nil {
+		results := make([]plugin.BatchReadResult, len(reads))
+		for i, r := range reads {
+			results[i].Outputs, results[i].Status, results[i].Err = prov.Read(r.URN, r.ID, r.Props)
+		}
+		return results, nil
+	}
+	return prov.ReadBatchF(reads)
+}
+func (prov *BatchProvider) DiffBatch(diffs []plugin.BatchDiff,
+	allowUnknowns bool) ([]plugin.BatchDiffResult, error) {
+	if prov.DiffBatchF == nil {
+		results := make([]plugin.BatchDiffResult, len(diffs))
+		for i, d := range diffs {
+			results[i].Diff, results[i].Err = prov.Diff(d.URN, d.ID, d.OldInputs, d.OldOutputs, d.NewInputs,
+				allowUnknowns)
+		}
+		return results, nil
+	}
+	return prov.DiffBatchF(diffs)
+}
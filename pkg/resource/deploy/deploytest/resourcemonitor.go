@@ -30,7 +30,11 @@ type ResourceMonitor struct {
 func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom bool, parent resource.URN, protect bool,
 	dependencies []resource.URN, provider string, inputs resource.PropertyMap,
 	propertyDeps map[resource.PropertyKey][]resource.URN,
-	deleteBeforeReplace bool) (resource.URN, resource.ID, resource.PropertyMap, error) {
+	deleteBeforeReplace bool, retainOnDelete bool,
+	aliases []resource.URN, ignoreChanges []string,
+	replaceOnChanges []string, readyConditions []string,
+	customTimeouts *pulumirpc.RegisterResourceRequest_CustomTimeouts,
+	propertyReads resource.PropertyReads) (resource.URN, resource.ID, resource.PropertyMap, error) {
 
 	// marshal inputs
 	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true})
@@ -44,6 +48,12 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		deps = append(deps, string(d))
 	}
 
+	// marshal aliases
+	aliasStrs := []string{}
+	for _, a := range aliases {
+		aliasStrs = append(aliasStrs, string(a))
+	}
+
 	inputDeps := make(map[string]*pulumirpc.RegisterResourceRequest_PropertyDependencies)
 	for pk, pd := range propertyDeps {
 		pdeps := []string{}
@@ -54,6 +64,16 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 			Urns: pdeps,
 		}
 	}
+	for pk, reads := range propertyReads {
+		pd, ok := inputDeps[string(pk)]
+		if !ok {
+			pd = &pulumirpc.RegisterResourceRequest_PropertyDependencies{}
+			inputDeps[string(pk)] = pd
+		}
+		for urn, paths := range reads {
+			pd.Reads = append(pd.Reads, &pulumirpc.RegisterResourceRequest_PropertyRead{Urn: string(urn), Paths: paths})
+		}
+	}
 
 	// submit request
 	resp, err := rm.resmon.RegisterResource(context.Background(), &pulumirpc.RegisterResourceRequest{
@@ -67,6 +87,12 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		Object:               ins,
 		PropertyDependencies: inputDeps,
 		DeleteBeforeReplace:  deleteBeforeReplace,
+		RetainOnDelete:       retainOnDelete,
+		Aliases:              aliasStrs,
+		IgnoreChanges:        ignoreChanges,
+		ReplaceOnChanges:     replaceOnChanges,
+		ReadyConditions:      readyConditions,
+		CustomTimeouts:       customTimeouts,
 	})
 	if err != nil {
 		return "", "", nil, err
@@ -81,6 +107,14 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 	return resource.URN(resp.Urn), resource.ID(resp.Id), outs, nil
 }
 
+// RegisterResourceRequest submits the given registration as-is. It allows tests to exercise options of the request
+// that RegisterResource does not expose.
+func (rm *ResourceMonitor) RegisterResourceRequest(
+	req *pulumirpc.RegisterResourceRequest) (*pulumirpc.RegisterResourceResponse, error) {
+
+	return rm.resmon.RegisterResource(context.Background(), req)
+}
+
 func (rm *ResourceMonitor) ReadResource(t tokens.Type, name string, id resource.ID, parent resource.URN,
 	inputs resource.PropertyMap, provider string) (resource.URN, resource.PropertyMap, error) {
 
@@ -135,6 +169,11 @@ func (rm *ResourceMonitor) Invoke(tok tokens.ModuleMember,
 		return nil, resp.Failures, nil
 	}
 
+	// an unknown result has no outputs
+	if resp.Unknown {
+		return nil, nil, nil
+	}
+
 	// unmarshal outputs
 	outs, err := plugin.UnmarshalProperties(resp.Return, plugin.MarshalOptions{KeepUnknowns: true})
 	if err != nil {
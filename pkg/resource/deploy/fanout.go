@@ -0,0 +1,217 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// FanOutInstance is one of the instances of a stack's program that a fan-out deployment evaluates. Each instance runs
+// the program with the stack's configuration overridden by its own, and has default providers of its own that are
+// configured likewise, so that the same resources can be deployed to several regions or accounts from one stack. The
+// names of each instance's resources are qualified by the instance's name (see resource.InstanceName).
+//
+// All of a stack's instances are planned and applied together, so they share the plan's parallelism and are reported
+// in a single summary. Removing an instance deletes its resources, just as removing them from the program would.
+type FanOutInstance struct {
+	Name   string            `json:"name"`             // the instance's name, e.g. "us-west-2".
+	Config map[string]string `json:"config,omitempty"` // configuration that overrides the stack's, e.g. aws:region.
+}
+
+// InstanceConfigKey is the configuration key through which a program learns the name of the instance it is running
+// as.  It is not set when a program runs without fan-out.
+var InstanceConfigKey = config.MustMakeKey("pulumi", "instance")
+
+// validate ensures that the instance has a valid name and valid configuration keys.
+func (inst *FanOutInstance) validate() error {
+	if !resource.IsInstanceName(inst.Name) {
+		return errors.Errorf("instance name '%s' must consist of letters, digits, '_', '.', and '-'", inst.Name)
+	}
+	for k := range inst.Config {
+		if _, err := config.ParseKey(k); err != nil {
+			return errors.Wrapf(err, "instance '%s'", inst.Name)
+		}
+	}
+	return nil
+}
+
+// overrides returns the instance's configuration, keyed by parsed configuration keys.
+func (inst *FanOutInstance) overrides() map[config.Key]string {
+	result := make(map[config.Key]string, len(inst.Config))
+	for k, v := range inst.Config {
+		key, err := config.ParseKey(k)
+		contract.Assertf(err == nil, "instance configuration must be validated")
+		result[key] = v
+	}
+	return result
+}
+
+// programConfig returns the given configuration with the instance's overrides applied, and with InstanceConfigKey set.
+func (inst *FanOutInstance) programConfig(cfg map[config.Key]string) map[config.Key]string {
+	result := make(map[config.Key]string, len(cfg)+len(inst.Config)+1)
+	for k, v := range cfg {
+		result[k] = v
+	}
+	for k, v := range inst.overrides() {
+		result[k] = v
+	}
+	result[InstanceConfigKey] = inst.Name
+	return result
+}
+
+// instanceConfigSource supplies the configuration of an instance's default providers: that of the stack, overridden
+// by that of the instance.
+type instanceConfigSource struct {
+	base     plugin.ConfigSource
+	instance *FanOutInstance
+}
+
+func (s *instanceConfigSource) GetPackageConfig(pkg tokens.Package) (map[config.Key]string, error) {
+	cfg, err := s.base.GetPackageConfig(pkg)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.instance.overrides() {
+		if tokens.Package(k.Namespace()) != pkg {
+			continue
+		}
+		if cfg == nil {
+			cfg = make(map[config.Key]string)
+		}
+		cfg[k] = v
+	}
+	return cfg, nil
+}
+
+// Instance returns the name of the fan-out instance that registered the resource with the given URN, or "" if the
+// resource does not belong to one of the plan's instances.
+func (p *Plan) Instance(urn resource.URN) string {
+	if instance, _, ok := resource.SplitInstanceName(urn.Name()); ok && p.instances[instance] {
+		return instance
+	}
+	return ""
+}
+
+// logicalURN returns the given URN with the name of its fan-out instance, if any, removed.  The logical URN is the
+// same for a resource in every instance.
+func (p *Plan) logicalURN(urn resource.URN) resource.URN {
+	if p.Instance(urn) == "" {
+		return urn
+	}
+	_, name, _ := resource.SplitInstanceName(urn.Name())
+	return resource.NewURN(urn.Stack(), urn.Project(), "", urn.QualifiedType(), name)
+}
+
+// hostRefs counts the evaluations that are using a language host, so that the host is closed only once every one of
+// them has finished.
+type hostRefs struct {
+	lock  sync.Mutex
+	count int       // the number of evaluations that have yet to finish.
+	host  io.Closer // the language host, once an evaluation has launched it.
+}
+
+// release releases one evaluation's reference to the given host, which is nil if the evaluation failed to launch it,
+// and closes the host if the evaluation was the last.
+func (r *hostRefs) release(host io.Closer) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if host != nil {
+		r.host = host
+	}
+	r.count--
+	if r.count == 0 && r.host != nil {
+		contract.IgnoreClose(r.host)
+	}
+}
+
+// fanOutEvent is an event, or the end of the events, of one of the iterators that a fanOutIterator merges.
+type fanOutEvent struct {
+	event SourceEvent
+	err   error
+}
+
+// fanOutIterator merges the events of the iterators of each of a fan-out deployment's instances.  It ends once every
+// iterator has ended, or as soon as any of them fails.
+type fanOutIterator struct {
+	iters     []SourceIterator // the iterators of each instance.
+	events    chan fanOutEvent // the channel to which the iterators' events are sent.
+	cancel    chan bool        // a channel that is closed when the iterator is closed.
+	remaining int              // the number of iterators that have yet to end.
+	done      bool             // set to true when the iterator is done.
+}
+
+func newFanOutIterator(iters []SourceIterator) *fanOutIterator {
+	iter := &fanOutIterator{
+		iters:     iters,
+		events:    make(chan fanOutEvent),
+		cancel:    make(chan bool),
+		remaining: len(iters),
+	}
+	for _, it := range iters {
+		go iter.pump(it)
+	}
+	return iter
+}
+
+// pump sends the events of the given iterator to the merged channel until the iterator ends.
+func (iter *fanOutIterator) pump(it SourceIterator) {
+	for {
+		event, err := it.Next()
+		select {
+		case iter.events <- fanOutEvent{event: event, err: err}:
+		case <-iter.cancel:
+			return
+		}
+		if event == nil || err != nil {
+			return
+		}
+	}
+}
+
+func (iter *fanOutIterator) Next() (SourceEvent, error) {
+	for !iter.done {
+		e := <-iter.events
+		switch {
+		case e.err != nil:
+			iter.done = true
+			return nil, e.err
+		case e.event != nil:
+			return e.event, nil
+		}
+		iter.remaining--
+		iter.done = iter.remaining == 0
+	}
+	return nil, nil
+}
+
+func (iter *fanOutIterator) Close() error {
+	close(iter.cancel)
+	var result error
+	for _, it := range iter.iters {
+		if err := it.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
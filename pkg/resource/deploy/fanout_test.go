@@ -0,0 +1,80 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestGetInstances(t *testing.T) {
+	target := &Target{Config: config.Map{}}
+	instances, err := target.GetInstances()
+	assert.NoError(t, err)
+	assert.Nil(t, instances)
+
+	target.Config[instancesKey] = config.NewValue(`[{"name":"east","config":{"aws:region":"us-east-1"}},{"name":"west"}]`)
+	instances, err = target.GetInstances()
+	assert.NoError(t, err)
+	assert.Equal(t, []FanOutInstance{
+		{Name: "east", Config: map[string]string{"aws:region": "us-east-1"}},
+		{Name: "west"},
+	}, instances)
+
+	for _, invalid := range []string{
+		`{"name":"east"}`,
+		`[{"name":"east@1"}]`,
+		`[{"name":""}]`,
+		`[{"name":"east","config":{"region":"us-east-1"}}]`,
+		`[{"name":"east"},{"name":"east"}]`,
+	} {
+		target.Config[instancesKey] = config.NewValue(invalid)
+		_, err = target.GetInstances()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFanOutInstanceConfig(t *testing.T) {
+	inst := &FanOutInstance{Name: "east", Config: map[string]string{"aws:region": "us-east-1", "app:size": "2"}}
+
+	region, size := config.MustMakeKey("aws", "region"), config.MustMakeKey("app", "size")
+	cfg := map[config.Key]string{region: "us-west-2", config.MustMakeKey("aws", "profile"): "dev"}
+	assert.Equal(t, map[config.Key]string{
+		region:                               "us-east-1",
+		config.MustMakeKey("aws", "profile"): "dev",
+		size:                                 "2",
+		InstanceConfigKey:                    "east",
+	}, inst.programConfig(cfg))
+
+	// The stack's configuration is left untouched.
+	assert.Equal(t, "us-west-2", cfg[region])
+}
+
+func TestLogicalURN(t *testing.T) {
+	p := &Plan{instances: map[string]bool{"east": true}}
+
+	urn := resource.NewURN("stack", "proj", "pkgA:m:typB", "pkgA:m:typA", "east@resA")
+	assert.Equal(t, "east", p.Instance(urn))
+	assert.Equal(t, resource.NewURN("stack", "proj", "pkgA:m:typB", "pkgA:m:typA", "resA"), p.logicalURN(urn))
+
+	// Names that merely look qualified are left alone.
+	other := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "west@resA")
+	assert.Equal(t, "", p.Instance(other))
+	assert.Equal(t, other, p.logicalURN(other))
+}
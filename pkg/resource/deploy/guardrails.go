@@ -0,0 +1,118 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// Guardrail guards the resources that it matches against being deleted or replaced by accident. A plan that would
+// delete or replace a guarded resource is only applied if the resource's URN is among the plan's AllowDestroy URNs;
+// previews merely warn about such resources, so that they can be confirmed before the plan is applied.
+//
+// Type and URN are patterns in which '*' matches any sequence of characters and '?' matches any single character. A
+// resource is guarded if it matches every pattern that is set, e.g. {"type": "aws:rds/*"} guards all of a stack's
+// RDS resources, and {"urn": "*::prod-db*"} guards resources of any type whose names begin with "prod-db".
+type Guardrail struct {
+	Type string `json:"type,omitempty"` // a pattern that matches the types of guarded resources.
+	URN  string `json:"urn,omitempty"`  // a pattern that matches the URNs of guarded resources.
+}
+
+// validate ensures that the guardrail has a pattern, so that it cannot guard every resource by accident.
+func (g *Guardrail) validate() error {
+	if g.Type == "" && g.URN == "" {
+		return errors.New("guardrails must have a type or URN pattern")
+	}
+	return nil
+}
+
+// Matches returns true if the guardrail guards the resource with the given URN.
+func (g *Guardrail) Matches(urn resource.URN) bool {
+	return (g.Type == "" || matchPattern(g.Type, string(urn.Type()))) &&
+		(g.URN == "" || matchPattern(g.URN, string(urn)))
+}
+
+func (g Guardrail) String() string {
+	var patterns []string
+	if g.Type != "" {
+		patterns = append(patterns, "type="+g.Type)
+	}
+	if g.URN != "" {
+		patterns = append(patterns, "urn="+g.URN)
+	}
+	return strings.Join(patterns, ",")
+}
+
+// matchPattern returns true if the given string matches the given pattern, in which '*' matches any sequence of
+// characters and '?' matches any single character. Unlike path.Match, '*' also matches the separators in types and
+// URNs.
+func matchPattern(pattern, s string) bool {
+	// star and retry record the position of the last '*' seen in the pattern and the position in s to which it would
+	// next extend, so that a failed match can backtrack by letting the '*' match one more character.
+	p, i, star, retry := 0, 0, -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, retry = p, i
+			p++
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p, i = p+1, i+1
+		case star >= 0:
+			retry++
+			p, i = star+1, retry
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// Guarded returns the guardrail that guards the given resource, if any. Only custom resources are guarded: deleting
+// a component or an external resource does not delete anything that its provider manages.
+func (p *Plan) Guarded(res *resource.State) (*Guardrail, bool) {
+	if res == nil || !res.Custom || res.External {
+		return nil, false
+	}
+	for i := range p.guardrails {
+		if p.guardrails[i].Matches(res.URN) {
+			return &p.guardrails[i], true
+		}
+	}
+	return nil, false
+}
+
+// checkGuardrails returns true if the plan may delete or replace the given resource. Guarded resources may only be
+// deleted or replaced if the plan was explicitly allowed to do so; otherwise, an update fails with an error, and a
+// preview warns that the resource will need to be allowed.
+func (p *Plan) checkGuardrails(res *resource.State) bool {
+	g, guarded := p.Guarded(res)
+	if !guarded || p.allowDestroy[res.URN] {
+		return true
+	}
+	if p.preview {
+		p.Diag().Warningf(diag.GetGuardedResourceDeleteWarning(res.URN), res.URN, g, res.URN)
+		return true
+	}
+	p.Diag().Errorf(diag.GetGuardedResourceDeleteError(res.URN), res.URN, g, res.URN)
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		s       string
+		match   bool
+	}{
+		{"aws:rds/instance:Instance", "aws:rds/instance:Instance", true},
+		{"aws:rds/*", "aws:rds/instance:Instance", true},
+		{"aws:*:Instance", "aws:ec2/instance:Instance", true},
+		{"aws:*:Instance", "aws:ec2/instance:InstanceProfile", false},
+		{"*db*", "prod-db-1", true},
+		{"db?", "db1", true},
+		{"db?", "db", false},
+		{"*", "", true},
+		{"", "x", false},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b*c", "aXbYbZ", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, matchPattern(c.pattern, c.s), "%s ~ %s", c.pattern, c.s)
+	}
+}
+
+func TestGuardrailMatches(t *testing.T) {
+	urn := resource.URN("urn:pulumi:prod::app::aws:rds/instance:Instance::prod-db")
+	assert.True(t, (&Guardrail{Type: "aws:rds/*"}).Matches(urn))
+	assert.True(t, (&Guardrail{URN: "*::prod-db*"}).Matches(urn))
+	assert.True(t, (&Guardrail{Type: "aws:rds/*", URN: "urn:pulumi:prod::*"}).Matches(urn))
+	assert.False(t, (&Guardrail{Type: "aws:rds/*", URN: "urn:pulumi:dev::*"}).Matches(urn))
+	assert.Equal(t, "type=aws:rds/*,urn=*::prod-db*", Guardrail{Type: "aws:rds/*", URN: "*::prod-db*"}.String())
+}
+
+func TestGetGuardrails(t *testing.T) {
+	target := &Target{Config: config.Map{}}
+	guardrails, err := target.GetGuardrails()
+	assert.NoError(t, err)
+	assert.Nil(t, guardrails)
+
+	target.Config[guardrailsKey] = config.NewValue(`[{"type":"aws:rds/*"},{"urn":"*::prod-*"}]`)
+	guardrails, err = target.GetGuardrails()
+	assert.NoError(t, err)
+	assert.Equal(t, []Guardrail{{Type: "aws:rds/*"}, {URN: "*::prod-*"}}, guardrails)
+
+	for _, invalid := range []string{`{"type":"aws:rds/*"}`, `[{}]`} {
+		target.Config[guardrailsKey] = config.NewValue(invalid)
+		_, err = target.GetGuardrails()
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// HookEvent identifies the point in a resource's lifecycle at which a hook runs.
+type HookEvent string
+
+const (
+	BeforeCreate HookEvent = "before-create" // before a resource is created.
+	AfterCreate  HookEvent = "after-create"  // after a resource has been created and has become ready.
+	BeforeUpdate HookEvent = "before-update" // before a resource is updated.
+	AfterUpdate  HookEvent = "after-update"  // after a resource has been updated and has become ready.
+	BeforeDelete HookEvent = "before-delete" // before a resource is deleted.
+	AfterDelete  HookEvent = "after-delete"  // after a resource has been deleted.
+)
+
+const (
+	// HookEventEnvVar is set to the hook's event in the environment of a hook command.
+	HookEventEnvVar = "PULUMI_HOOK_EVENT"
+	// HookURNEnvVar is set to the resource's URN in the environment of a hook command.
+	HookURNEnvVar = "PULUMI_HOOK_URN"
+)
+
+// DefaultHookTimeout is how long a hook's command may run if the hook does not set a timeout of its own.
+const DefaultHookTimeout = 10 * time.Minute
+
+// HookCallback is a hook that runs in the engine's process. Embedders of the engine may use callbacks to forward
+// hooks to other processes, e.g. over RPC.
+type HookCallback func(event HookEvent, urn resource.URN, props resource.PropertyMap) error
+
+// Hook runs a local command or a callback at one or more points in the lifecycle of the resources it applies to. Hooks
+// do not run during previews. A hook that fails stops the step that ran it, unless the hook is advisory, in which case
+// its failure is reported as a warning.
+//
+// A command is run with the event and the resource's URN in its environment, and the resource's properties written to
+// its standard input as a JSON object. Before a resource is created or updated these are its new inputs; otherwise
+// they are its inputs and outputs combined. A command that runs for longer than the hook's timeout, or that is still
+// running when the plan is canceled, is killed.
+//
+// Besides the hooks configured for a stack, a program may register hooks for each of its resources. Because a deleted
+// resource is no longer registered by its program, such hooks may only run when their resource is created or updated.
+type Hook struct {
+	Name      string       `json:"name,omitempty"`      // an optional name for the hook, used in diagnostics.
+	Resources []string     `json:"resources,omitempty"` // the URNs or types of the resources; empty for all resources.
+	Events    []HookEvent  `json:"events"`              // the events at which the hook runs.
+	Command   []string     `json:"command,omitempty"`   // the command to run and its arguments.
+	Advisory  bool         `json:"advisory,omitempty"`  // true if the hook's failures should not stop its step.
+	Timeout   string       `json:"timeout,omitempty"`   // how long the command may run, e.g. "30s"; "" for the default.
+	Callback  HookCallback `json:"-"`                   // a callback to run instead of a command.
+}
+
+// validate ensures that the hook names a known event and has something to run.
+func (h *Hook) validate() error {
+	if len(h.Command) == 0 && h.Callback == nil {
+		return errors.Errorf("hook '%v' has no command", h.name())
+	}
+	if len(h.Events) == 0 {
+		return errors.Errorf("hook '%v' has no events", h.name())
+	}
+	if h.Timeout != "" {
+		if timeout, err := time.ParseDuration(h.Timeout); err != nil || timeout <= 0 {
+			return errors.Errorf("hook '%v' has an invalid timeout '%v'", h.name(), h.Timeout)
+		}
+	}
+	for _, e := range h.Events {
+		switch e {
+		case BeforeCreate, AfterCreate, BeforeUpdate, AfterUpdate, BeforeDelete, AfterDelete:
+		default:
+			return errors.Errorf("hook '%v' has an unknown event '%v'", h.name(), e)
+		}
+	}
+	return nil
+}
+
+// name returns the hook's name, or its command if it is unnamed.
+func (h *Hook) name() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return strings.Join(h.Command, " ")
+}
+
+// appliesTo returns true if the hook should run at the given event for the resource with the given URN.
+func (h *Hook) appliesTo(event HookEvent, urn resource.URN) bool {
+	matched := len(h.Resources) == 0
+	for _, r := range h.Resources {
+		if r == string(urn) || r == string(urn.Type()) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs the hook for the given event and resource. The hook's command is killed if the given context is canceled.
+func (h *Hook) run(ctx context.Context, event HookEvent, urn resource.URN, props resource.PropertyMap) error {
+	if h.Callback != nil {
+		return h.Callback(event, urn, props)
+	}
+
+	var stdin bytes.Buffer
+	if err := plugin.StreamProperties(&stdin, props, plugin.MarshalOptions{Label: string(event)}); err != nil {
+		return err
+	}
+
+	timeout := DefaultHookTimeout
+	if h.Timeout != "" {
+		// The timeout was checked by validate.
+		timeout, _ = time.ParseDuration(h.Timeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// nolint: gosec
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Env = append(os.Environ(), HookEventEnvVar+"="+string(event), HookURNEnvVar+"="+string(urn))
+	cmd.Stdin = &stdin
+	out, err := cmd.CombinedOutput()
+	logging.V(7).Infof("Hook '%v' for %v of '%v' wrote: %s", h.name(), event, urn, out)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Errorf("timed out after %v", timeout)
+		}
+		if output := strings.TrimSpace(string(out)); output != "" {
+			return errors.Wrapf(err, "%v", output)
+		}
+		return err
+	}
+	return nil
+}
+
+// runHooks runs each of the plan's hooks that applies to the given event and resource, followed by any hooks that the
+// program registered for the resource, in order. The failure of an advisory hook is reported as a warning; the failure
+// of any other hook stops the remaining hooks and is returned.
+func (p *Plan) runHooks(reg RegisterResourceEvent, event HookEvent, urn resource.URN,
+	props resource.PropertyMap) error {
+
+	ctx := p.cancelCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	hooks := p.hooks
+	if reg, ok := reg.(*registerResourceEvent); ok && len(reg.hooks) > 0 {
+		hooks = append(append([]Hook{}, hooks...), reg.hooks...)
+	}
+	for i := range hooks {
+		h := &hooks[i]
+		if !h.appliesTo(event, urn) {
+			continue
+		}
+		if err := h.run(ctx, event, urn, props); err != nil {
+			if h.Advisory {
+				p.Diag().Warningf(diag.GetResourceHookFailedError(urn), event, h.name(), err)
+				continue
+			}
+			return errors.Wrapf(err, "%v hook '%v' failed", event, h.name())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestHookAppliesTo(t *testing.T) {
+	urn := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "resA")
+	other := resource.NewURN("stack", "proj", "", "pkgA:m:typB", "resB")
+
+	byType := &Hook{Resources: []string{"pkgA:m:typA"}, Events: []HookEvent{BeforeCreate, AfterDelete}}
+	assert.True(t, byType.appliesTo(BeforeCreate, urn))
+	assert.True(t, byType.appliesTo(AfterDelete, urn))
+	assert.False(t, byType.appliesTo(AfterCreate, urn))
+	assert.False(t, byType.appliesTo(BeforeCreate, other))
+
+	byURN := &Hook{Resources: []string{string(other)}, Events: []HookEvent{BeforeUpdate}}
+	assert.True(t, byURN.appliesTo(BeforeUpdate, other))
+	assert.False(t, byURN.appliesTo(BeforeUpdate, urn))
+
+	all := &Hook{Events: []HookEvent{AfterUpdate}}
+	assert.True(t, all.appliesTo(AfterUpdate, urn))
+	assert.True(t, all.appliesTo(AfterUpdate, other))
+}
+
+func TestHookValidate(t *testing.T) {
+	assert.NoError(t, (&Hook{Command: []string{"true"}, Events: []HookEvent{AfterCreate}}).validate())
+	assert.Error(t, (&Hook{Events: []HookEvent{AfterCreate}}).validate())
+	assert.Error(t, (&Hook{Command: []string{"true"}}).validate())
+	assert.Error(t, (&Hook{Command: []string{"true"}, Events: []HookEvent{"during-create"}}).validate())
+	assert.NoError(t, (&Hook{Command: []string{"true"}, Events: []HookEvent{AfterCreate}, Timeout: "30s"}).validate())
+	assert.Error(t, (&Hook{Command: []string{"true"}, Events: []HookEvent{AfterCreate}, Timeout: "soon"}).validate())
+}
+
+func TestHookCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands in this test require a POSIX shell")
+	}
+
+	urn := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "resA")
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"})
+
+	// The command receives the event and URN in its environment, and the properties on its standard input. Its
+	// output is included in the error if it fails.
+	h := &Hook{Command: []string{"sh", "-c", `cat; echo " $PULUMI_HOOK_EVENT $PULUMI_HOOK_URN"; exit 1`}}
+	err := h.run(context.Background(), BeforeCreate, urn, props)
+	assert.EqualError(t, err, `{"foo":"bar"} before-create `+string(urn)+`: exit status 1`)
+
+	h = &Hook{Command: []string{"sh", "-c", "exit 0"}}
+	assert.NoError(t, h.run(context.Background(), AfterCreate, urn, props))
+
+	// A command that outlives its timeout, or the plan's context, is killed.
+	h = &Hook{Command: []string{"sh", "-c", "exec sleep 10"}, Timeout: "50ms"}
+	assert.EqualError(t, h.run(context.Background(), AfterCreate, urn, props), "timed out after 50ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h = &Hook{Command: []string{"sh", "-c", "exec sleep 10"}}
+	assert.Error(t, h.run(ctx, AfterCreate, urn, props))
+}
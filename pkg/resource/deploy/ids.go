@@ -0,0 +1,65 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+)
+
+// IDAllocator allocates placeholder IDs for resources that a preview would create. Ordinarily such resources have no
+// ID until they are created, so references to them are unknown; a placeholder gives programs and providers that need
+// an ID-shaped value something to work with. An allocator must return the same ID for the same URN every time it is
+// asked, including across runs, so that previews are repeatable.
+type IDAllocator interface {
+	// AllocateID returns the placeholder ID for the resource with the given URN.
+	AllocateID(urn resource.URN) resource.ID
+}
+
+// IDAllocatorFunc adapts a function to the IDAllocator interface.
+type IDAllocatorFunc func(urn resource.URN) resource.ID
+
+// AllocateID calls f(urn).
+func (f IDAllocatorFunc) AllocateID(urn resource.URN) resource.ID {
+	return f(urn)
+}
+
+// PlaceholderIDPrefix begins every ID allocated by DeterministicIDAllocator.
+const PlaceholderIDPrefix = "preview-"
+
+// DeterministicIDAllocator allocates placeholder IDs derived from a hash of each resource's URN.
+var DeterministicIDAllocator IDAllocator = IDAllocatorFunc(func(urn resource.URN) resource.ID {
+	sum := sha256.Sum256([]byte(urn))
+	return resource.ID(PlaceholderIDPrefix + hex.EncodeToString(sum[:8]))
+})
+
+// IsPlaceholderID returns true if the given ID was allocated by DeterministicIDAllocator.
+func IsPlaceholderID(id resource.ID) bool {
+	return strings.HasPrefix(string(id), PlaceholderIDPrefix)
+}
+
+// placeholderID returns the placeholder ID for a resource that a preview would create, or "" if the plan has no ID
+// allocator.  Provider resources never receive placeholders: references to providers that have yet to be created
+// must use providers.UnknownID, which is how the provider registry recognizes them.
+func (p *Plan) placeholderID(res *resource.State) resource.ID {
+	if p.idAllocator == nil || !res.Custom || providers.IsProviderType(res.Type) {
+		return ""
+	}
+	return p.idAllocator.AllocateID(res.URN)
+}
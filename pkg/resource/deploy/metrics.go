@@ -0,0 +1,30 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/util/metrics"
+)
+
+var (
+	stepsExecuted = metrics.Default.NewCounter("pulumi_engine_steps_total",
+		"The number of steps applied, by operation and result.", "op", "result")
+	providerRetries = metrics.Default.NewCounter("pulumi_provider_retries_total",
+		"The number of provider operations retried after failing with a transient error, by error class.", "class")
+	providerRateLimitWaits = metrics.Default.NewHistogram("pulumi_provider_rate_limit_wait_seconds",
+		"The time provider operations spent waiting for a rate limit, by limit.", nil, "limit")
+	providerRateLimitQueued = metrics.Default.NewGauge("pulumi_provider_rate_limit_queued",
+		"The number of provider operations currently waiting for a rate limit, by limit.", "limit")
+)
@@ -0,0 +1,111 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// A NormalizeFunc returns the canonical form of a property value. Two values whose canonical forms are equal are
+// considered to be the same value, even if their representations differ.
+type NormalizeFunc func(v resource.PropertyValue) resource.PropertyValue
+
+// A PropertyNormalizer normalizes the value at a particular property path before it is compared with its old value.
+type PropertyNormalizer struct {
+	Path      resource.PropertyPath // the path of the property to normalize.
+	Normalize NormalizeFunc         // the function that computes the property's canonical form.
+}
+
+// DefaultPropertyNormalizers maps resource types to the normalizers of their properties whose representations may
+// change without changing their meaning. These normalizers are applied unless a plan's options supply a different set.
+var DefaultPropertyNormalizers = map[tokens.Type][]PropertyNormalizer{}
+
+// DefaultJSONProperties maps resource types to the paths of their string properties that hold JSON documents. These
+// properties are compared by the documents they hold rather than by their text unless a plan's options supply a
+// different set of JSON properties.
+var DefaultJSONProperties = map[tokens.Type][]resource.PropertyPath{
+	"aws:iam/policy:Policy":            {{"policy"}},
+	"aws:iam/role:Role":                {{"assumeRolePolicy"}},
+	"aws:iam/rolePolicy:RolePolicy":    {{"policy"}},
+	"aws:s3/bucketPolicy:BucketPolicy": {{"policy"}},
+	"aws:sns/topicPolicy:TopicPolicy":  {{"policy"}},
+	"aws:sqs/queuePolicy:QueuePolicy":  {{"policy"}},
+}
+
+// RegisterPropertyNormalizer adds a normalizer for the property at the given path of the given resource type to the
+// default set of normalizers.
+func RegisterPropertyNormalizer(t tokens.Type, path resource.PropertyPath, normalize NormalizeFunc) {
+	DefaultPropertyNormalizers[t] = append(DefaultPropertyNormalizers[t],
+		PropertyNormalizer{Path: path, Normalize: normalize})
+}
+
+// NormalizeJSON normalizes a string that holds a JSON document by removing insignificant whitespace and sorting the
+// keys of its objects. Values that are not strings or do not hold valid JSON are returned unchanged.
+func NormalizeJSON(v resource.PropertyValue) resource.PropertyValue {
+	if !v.IsString() {
+		return v
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(v.StringValue()), &doc); err != nil {
+		return v
+	}
+	bytes, err := json.Marshal(doc)
+	if err != nil {
+		return v
+	}
+	return resource.NewStringProperty(string(bytes))
+}
+
+// propertyNormalizers returns the normalizers for the given resource type's properties.
+func (sg *stepGenerator) propertyNormalizers(t tokens.Type) []PropertyNormalizer {
+	if sg.opts.PropertyNormalizers != nil {
+		return sg.opts.PropertyNormalizers[t]
+	}
+	return DefaultPropertyNormalizers[t]
+}
+
+// jsonProperties returns the paths of the given resource type's string properties that hold JSON documents.
+func (sg *stepGenerator) jsonProperties(t tokens.Type) []resource.PropertyPath {
+	if sg.opts.JSONProperties != nil {
+		return sg.opts.JSONProperties[t]
+	}
+	return DefaultJSONProperties[t]
+}
+
+// suppressNormalizedChanges carries the old value of each normalized property over to the new inputs if the old and
+// new values have the same canonical form, so that differences in representation alone are not reported as changes.
+// Unknown values are never suppressed.
+func suppressNormalizedChanges(olds, news resource.PropertyMap, normalizers []PropertyNormalizer) resource.PropertyMap {
+	result, copied := news, false
+	for _, n := range normalizers {
+		old, hasOld := n.Path.Get(olds)
+		new, hasNew := n.Path.Get(news)
+		if !hasOld || !hasNew || old.ContainsUnknowns() || new.ContainsUnknowns() || old.DeepEquals(new) {
+			continue
+		}
+		if !n.Normalize(old).DeepEquals(n.Normalize(new)) {
+			continue
+		}
+
+		if !copied {
+			result, copied = news.DeepCopy(), true
+		}
+		n.Path.Set(result, old)
+	}
+	return result
+}
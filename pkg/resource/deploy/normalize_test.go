@@ -0,0 +1,72 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	a := NormalizeJSON(resource.NewStringProperty(`{"b": [1, 2], "a": {"d": true, "c": null}}`))
+	b := NormalizeJSON(resource.NewStringProperty(`{"a":{"c":null,"d":true},"b":[1,2]}`))
+	assert.Equal(t, a, b)
+
+	// Array order is significant.
+	c := NormalizeJSON(resource.NewStringProperty(`{"a":{"c":null,"d":true},"b":[2,1]}`))
+	assert.NotEqual(t, a, c)
+
+	// Values that are not JSON documents are left alone.
+	assert.Equal(t, resource.NewStringProperty("{"), NormalizeJSON(resource.NewStringProperty("{")))
+	assert.Equal(t, resource.NewNumberProperty(42), NormalizeJSON(resource.NewNumberProperty(42)))
+}
+
+func TestSuppressNormalizedChanges(t *testing.T) {
+	normalizers := []PropertyNormalizer{{Path: resource.PropertyPath{"doc", "policy"}, Normalize: NormalizeJSON}}
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo": "bar",
+		"doc": map[string]interface{}{"policy": `{"Version": "2012-10-17", "Statement": []}`},
+	})
+
+	// A change in representation alone is suppressed, and the new inputs are not modified.
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo": "baz",
+		"doc": map[string]interface{}{"policy": `{"Statement":[],"Version":"2012-10-17"}`},
+	})
+	expected := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo": "baz",
+		"doc": map[string]interface{}{"policy": `{"Version": "2012-10-17", "Statement": []}`},
+	})
+	assert.Equal(t, expected, suppressNormalizedChanges(olds, news, normalizers))
+	assert.Equal(t, `{"Statement":[],"Version":"2012-10-17"}`,
+		news["doc"].ObjectValue()["policy"].StringValue())
+
+	// A change in meaning is not.
+	news = resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo": "bar",
+		"doc": map[string]interface{}{"policy": `{"Statement":[],"Version":"2008-10-17"}`},
+	})
+	assert.Equal(t, news, suppressNormalizedChanges(olds, news, normalizers))
+
+	// Neither is a change to an unknown value.
+	news = resource.PropertyMap{
+		"foo": resource.NewStringProperty("bar"),
+		"doc": resource.MakeComputed(resource.NewStringProperty("")),
+	}
+	assert.Equal(t, news, suppressNormalizedChanges(olds, news, normalizers))
+}
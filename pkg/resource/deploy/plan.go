@@ -17,6 +17,8 @@ package deploy
 import (
 	"context"
 	"math"
+	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
@@ -27,6 +29,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/resource/graph"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/policy"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 )
@@ -44,6 +47,93 @@ type Options struct {
 	Refresh           bool   // whether or not to refresh before executing the plan.
 	RefreshOnly       bool   // whether or not to exit after refreshing.
 	TrustDependencies bool   // whether or not to trust the resource dependency graph.
+	ForceUnprotect    bool   // whether or not to allow protected resources to be deleted.
+	ExcludeProtected  bool   // whether to keep protected resources and their dependencies rather than fail to delete.
+
+	// RefreshMerge, if true, makes a refresh merge the state that it reads with the resource's inputs: changes made out of
+	// band to properties that the program sets are reverted, while changes to all other properties are kept.
+	RefreshMerge bool
+
+	// Targets, if non-empty, restricts the plan to the resources with the given URNs. All other resources are left
+	// untouched. TargetDependencies and TargetDependents widen the set of targets to include the resources that the
+	// targets depend on and the resources that depend on the targets, respectively.
+	Targets            []resource.URN
+	TargetDependencies bool
+	TargetDependents   bool
+
+	// ReplaceTargets lists resources that must be replaced even if their providers report that they have not changed.
+	// If the plan is restricted to a set of targets, these resources are targeted as well.
+	ReplaceTargets []resource.URN
+
+	// Retries controls how provider operations that fail with transient errors are retried. If nil, the default
+	// retry policy is used for every resource type.
+	Retries *RetryPolicies
+
+	// RateLimits bounds the rate at which resource operations are issued to providers, in addition to any limits
+	// configured for the target's stack. Where both set a limit for the same provider or type, this one is used.
+	RateLimits *RateLimits
+
+	// Policies is the set of policy packs to evaluate against each resource's planned state. Violations of mandatory
+	// policies prevent the plan from proceeding.
+	Policies []policy.Pack
+
+	// Transformations, if non-nil, may modify each resource's goal state before it is planned.
+	Transformations *Transformations
+
+	// TaggableTypes maps each resource type that supports tags to the property that holds its tags. The target's
+	// default tags are merged into these properties. If nil, DefaultTaggableTypes is used.
+	TaggableTypes map[tokens.Type]resource.PropertyKey
+
+	// AutoNamedTypes maps each resource type that may be auto-named to the property that holds its physical name. If
+	// the target enables auto-naming, names are generated for these properties. If nil, DefaultAutoNamedTypes is used.
+	AutoNamedTypes map[tokens.Type]resource.PropertyKey
+
+	// UnorderedProperties maps resource types to the paths of their array-valued properties whose element order is
+	// insignificant. These arrays are sorted by element hash before they are diffed or saved, so reordering their
+	// elements does not produce a diff. If nil, DefaultUnorderedProperties is used.
+	UnorderedProperties map[tokens.Type][]resource.PropertyPath
+
+	// PropertyNormalizers maps resource types to normalizers for properties whose representation may change without
+	// changing their meaning. If a property's old and new values normalize to the same value, its old value is kept so
+	// that no diff is reported. If nil, DefaultPropertyNormalizers is used.
+	PropertyNormalizers map[tokens.Type][]PropertyNormalizer
+
+	// JSONProperties maps resource types to the paths of their string properties that hold JSON documents. These
+	// properties are compared by the documents they hold, and their diffs are shown element by element. If nil,
+	// DefaultJSONProperties is used.
+	JSONProperties map[tokens.Type][]resource.PropertyPath
+
+	// ReadyTimeout bounds how long a resource may take to satisfy its ready conditions after it is created or
+	// updated, and ReadyPollInterval controls how often it is refreshed in the meantime. Zero values select
+	// DefaultReadyTimeout and DefaultReadyPollInterval.
+	ReadyTimeout      time.Duration
+	ReadyPollInterval time.Duration
+
+	// Hooks are run at points in the lifecycle of the resources they apply to, after any hooks configured for the
+	// target's stack.
+	Hooks []Hook
+
+	// Guardrails guard the resources that they match against being deleted or replaced, in addition to any guardrails
+	// configured for the target's stack. Guarded resources may only be deleted or replaced if their URNs are listed in
+	// AllowDestroy.
+	Guardrails   []Guardrail
+	AllowDestroy []resource.URN
+
+	// AuditLog, if non-nil, receives an entry for each Create, Update, and Delete call made to a resource provider.
+	AuditLog AuditLog
+
+	// RefreshBatchSize is the largest number of resources that a refresh reads in a single call to a provider that
+	// supports batched reads. Zero selects DefaultRefreshBatchSize; a negative value disables batching.
+	RefreshBatchSize int
+
+	// IDAllocator, if non-nil, allocates placeholder IDs for the resources that a preview would create, in place of
+	// the unknown IDs that they would otherwise have.
+	IDAllocator IDAllocator
+
+	// RecordSteps, if true, makes the plan keep each step that it issues, so that the plan can be serialized once it
+	// has been executed (see Plan.Serialize). The steps of a large stack take a great deal of memory, so plans that
+	// will not be serialized should leave this unset.
+	RecordSteps bool
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -92,6 +182,28 @@ type Plan struct {
 	preview   bool                             // true if this plan is to be previewed rather than applied.
 	depGraph  *graph.DependencyGraph           // the dependency graph of the old snapshot
 	providers *providers.Registry              // the provider registry for this plan.
+	record    bool                             // true if the steps issued by this plan are kept.
+	steps     []Step                           // the steps issued by this plan, in order, if they are kept.
+	stepsLock sync.Mutex                       // a lock guarding steps.
+	retries   *RetryPolicies                   // the policies used to retry failed provider operations.
+	cancelCtx context.Context                  // the plan's cancellation context, once it is executed.
+	policies  []policy.Pack                    // the policy packs to evaluate against each planned resource.
+	merge     bool                             // true if refreshes reassert the properties that the program sets.
+
+	readyTimeout      time.Duration     // how long a resource may take to satisfy its ready conditions.
+	readyPollInterval time.Duration     // how often a resource is refreshed while waiting for it to become ready.
+	readinessDefaults readinessDefaults // the ready conditions and timeouts that providers declare.
+	hooks             []Hook            // the hooks to run at each point in the lifecycle of the plan's resources.
+	audit             *auditor          // the auditor that records provider mutations, or nil if they are not audited.
+	limiter           *rateLimiter      // the limiter of the rate of provider operations, or nil if they are unlimited.
+
+	guardrails   []Guardrail           // the guardrails that guard resources against deletion and replacement.
+	allowDestroy map[resource.URN]bool // the guarded resources that may be deleted or replaced anyway.
+
+	defaultTags map[string]string // the default tags to merge into taggable resources.
+	autoNaming  *AutoNamingConfig // the target's auto-naming configuration, or nil if auto-naming is disabled.
+	idAllocator IDAllocator       // the allocator of placeholder IDs for previewed creates, if any.
+	instances   map[string]bool   // the names of the target's fan-out instances, if any.
 }
 
 // addDefaultProviders adds any necessary default provider definitions and references to the given snapshot. Version
@@ -218,6 +330,28 @@ func NewPlan(ctx *plugin.Context, target *Target, prev *Snapshot, source Source,
 		depGraph = graph.NewDependencyGraph(oldResources)
 	}
 
+	// Fetch the default tags to merge into taggable resources, if any.
+	defaultTags, err := target.GetDefaultTags()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the auto-naming configuration, if any.
+	autoNaming, err := target.GetAutoNaming()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the fan-out instances, if any.
+	fanOut, err := target.GetInstances()
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]bool, len(fanOut))
+	for _, inst := range fanOut {
+		instances[inst.Name] = true
+	}
+
 	// Create a new builtin provider. This provider implements features such as `getStack`.
 	builtins := newBuiltinProvider(backendClient)
 
@@ -239,6 +373,10 @@ func NewPlan(ctx *plugin.Context, target *Target, prev *Snapshot, source Source,
 		preview:   preview,
 		depGraph:  depGraph,
 		providers: reg,
+
+		defaultTags: defaultTags,
+		autoNaming:  autoNaming,
+		instances:   instances,
 	}, nil
 }
 
@@ -249,8 +387,33 @@ func (p *Plan) Prev() *Snapshot                        { return p.prev }
 func (p *Plan) Olds() map[resource.URN]*resource.State { return p.olds }
 func (p *Plan) Source() Source                         { return p.source }
 
+// providerCapabilities returns the capabilities of the provider with the given reference, or the default capabilities
+// if the reference does not name a loaded provider.
+func (p *Plan) providerCapabilities(ref string) plugin.ProviderCapabilities {
+	if providerRef, err := providers.ParseReference(ref); err == nil {
+		if prov, ok := p.providers.GetProvider(providerRef); ok {
+			return plugin.GetCapabilities(prov)
+		}
+	}
+	return plugin.DefaultCapabilities
+}
+
+// GetProvider returns the provider for the given reference. Resource operations performed using the returned
+// provider are subject to the plan's rate limits, and are retried according to the plan's retry policies.
 func (p *Plan) GetProvider(ref providers.Reference) (plugin.Provider, bool) {
-	return p.providers.GetProvider(ref)
+	return p.getProvider(context.Background(), ref)
+}
+
+// getProvider is like GetProvider, but the provider's resource operations are traced as children of the span in ctx,
+// if any, rather than of the plugin context's span.
+func (p *Plan) getProvider(ctx context.Context, ref providers.Reference) (plugin.Provider, bool) {
+	prov, ok := p.providers.GetProvider(ref)
+	if !ok {
+		return nil, false
+	}
+	traced := plugin.WithTraceContext(ctx, prov)
+	limited := p.limiter.wrap(p.cancelCtx, p.audit.wrap(traced))
+	return newRetryingProvider(p.cancelCtx, limited, p.Diag(), p.retries), true
 }
 
 // generateURN generates a resource's URN from its parent, type, and name under the scope of the plan's stack and
@@ -291,6 +454,52 @@ func (p *Plan) generateEventURN(event SourceEvent) resource.URN {
 // Execute executes a plan to completion, using the given cancellation context and running a preview
 // or update.
 func (p *Plan) Execute(ctx context.Context, opts Options, preview bool) error {
+	p.retries, p.cancelCtx = opts.Retries, ctx
+	p.policies = opts.Policies
+	p.merge = opts.RefreshMerge
+	p.record = opts.RecordSteps
+	p.idAllocator = opts.IDAllocator
+	p.readyTimeout, p.readyPollInterval = opts.ReadyTimeout, opts.ReadyPollInterval
+	p.audit = newAuditor(opts.AuditLog, p.Diag())
+
+	// Limits supplied by the caller override those configured for the stack.
+	limits, err := p.target.GetRateLimits()
+	if err != nil {
+		return err
+	}
+	if err = opts.RateLimits.Validate(); err != nil {
+		return err
+	}
+	p.limiter = newRateLimiter(limits.merge(opts.RateLimits), p.Diag())
+
+	// The stack's configured hooks run before any that were supplied by the caller.
+	hooks, err := p.target.GetHooks()
+	if err != nil {
+		return err
+	}
+	for i := range opts.Hooks {
+		if err = opts.Hooks[i].validate(); err != nil {
+			return err
+		}
+	}
+	p.hooks = append(hooks, opts.Hooks...)
+
+	// Likewise, the stack's guardrails are joined by any that were supplied by the caller.
+	guardrails, err := p.target.GetGuardrails()
+	if err != nil {
+		return err
+	}
+	for i := range opts.Guardrails {
+		if err = opts.Guardrails[i].validate(); err != nil {
+			return err
+		}
+	}
+	p.guardrails = append(guardrails, opts.Guardrails...)
+	p.allowDestroy = make(map[resource.URN]bool)
+	for _, urn := range opts.AllowDestroy {
+		p.allowDestroy[urn] = true
+	}
+
 	planExec := &planExecutor{plan: p}
 	return planExec.Execute(ctx, opts, preview)
 }
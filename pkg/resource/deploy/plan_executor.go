@@ -17,6 +17,7 @@ package deploy
 import (
 	"context"
 
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
@@ -147,7 +148,17 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 				}
 
 				if event.Event == nil {
-					deleteSteps := pe.stepGen.GenerateDeletes()
+					deleteSteps, res := pe.stepGen.GenerateDeletes()
+					if res != nil {
+						if resErr := res.Error(); resErr != nil {
+							logging.V(4).Infof("planExecutor.Execute(...): error generating deletes: %v", resErr)
+							pe.reportError("", resErr)
+						}
+						cancel()
+						return false, result.TODO()
+					}
+
+					pe.plan.recordSteps(deleteSteps...)
 					deletes := pe.stepGen.ScheduleDeletes(deleteSteps)
 
 					// ScheduleDeletes gives us a list of lists of steps. Each list of steps can safely be executed in
@@ -171,7 +182,7 @@ func (pe *planExecutor) Execute(callerCtx context.Context, opts Options, preview
 				if res := pe.handleSingleEvent(event.Event); res != nil {
 					if resErr := res.Error(); resErr != nil {
 						logging.V(4).Infof("planExecutor.Execute(...): error handling event: %v", resErr)
-						pe.reportError(pe.plan.generateEventURN(event.Event), resErr)
+						pe.reportError(pe.stepGen.generateEventURN(event.Event), resErr)
 					}
 					cancel()
 					return false, result.TODO()
@@ -208,10 +219,16 @@ func (pe *planExecutor) handleSingleEvent(event SourceEvent) *result.Result {
 	switch e := event.(type) {
 	case RegisterResourceEvent:
 		logging.V(4).Infof("planExecutor.handleSingleEvent(...): received RegisterResourceEvent")
+		span, _ := opentracing.StartSpanFromContext(pe.stepExec.ctx, "pulumi-generate-steps",
+			opentracing.Tags{"type": string(e.Goal().Type), "name": string(e.Goal().Name)})
 		steps, res = pe.stepGen.GenerateSteps(e)
+		span.Finish()
 	case ReadResourceEvent:
 		logging.V(4).Infof("planExecutor.handleSingleEvent(...): received ReadResourceEvent")
+		span, _ := opentracing.StartSpanFromContext(pe.stepExec.ctx, "pulumi-generate-steps",
+			opentracing.Tags{"type": string(e.Type()), "name": string(e.Name()), "read": true})
 		steps, res = pe.stepGen.GenerateReadSteps(e)
+		span.Finish()
 	case RegisterResourceOutputsEvent:
 		logging.V(4).Infof("planExecutor.handleSingleEvent(...): received register resource outputs")
 		pe.stepExec.ExecuteRegisterResourceOutputs(e)
@@ -222,6 +239,7 @@ func (pe *planExecutor) handleSingleEvent(event SourceEvent) *result.Result {
 		return res
 	}
 
+	pe.plan.recordSteps(steps...)
 	pe.stepExec.ExecuteSerial(steps)
 	return nil
 }
@@ -239,6 +257,7 @@ func (pe *planExecutor) retirePendingDeletes(callerCtx context.Context, opts Opt
 	}
 
 	logging.V(4).Infof("planExecutor.retirePendingDeletes(...): executing %d steps", len(steps))
+	pe.plan.recordSteps(steps...)
 	ctx, cancel := context.WithCancel(callerCtx)
 
 	stepExec := newStepExecutor(ctx, cancel, pe.plan, opts, preview, false)
@@ -279,6 +298,10 @@ func (pe *planExecutor) refresh(callerCtx context.Context, opts Options, preview
 	for i := range prev.Resources {
 		steps[i] = NewRefreshStep(pe.plan, prev.Resources[i], nil)
 	}
+	pe.plan.recordSteps(steps...)
+
+	// Read as many resources as possible in batches from the providers that support it.
+	pe.prefetchReads(callerCtx, opts, steps)
 
 	// Fire up a worker pool and issue each refresh in turn.
 	ctx, cancel := context.WithCancel(callerCtx)
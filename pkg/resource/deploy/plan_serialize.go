@@ -0,0 +1,233 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// SerializedPlan is the machine-readable form of a plan: the full list of steps, in the order they were issued.
+type SerializedPlan struct {
+	Steps []SerializedStep `json:"steps"`
+}
+
+// SerializedStep is the machine-readable form of a single step in a plan.  Property values that are not known until
+// the step is applied are rendered as plugin.UnknownStringValue.
+type SerializedStep struct {
+	Op          StepOp                 `json:"op"`                    // the operation performed by this step.
+	URN         resource.URN           `json:"urn"`                   // the resource URN (for before and after).
+	Type        tokens.Type            `json:"type"`                  // the type affected by this step.
+	Provider    string                 `json:"provider,omitempty"`    // the provider reference for this step.
+	Old         map[string]interface{} `json:"old,omitempty"`         // the resource's inputs before this step.
+	New         map[string]interface{} `json:"new,omitempty"`         // the resource's inputs after this step.
+	Diffs       []resource.PropertyKey `json:"diffs,omitempty"`       // the input keys that changed, if any.
+	ReplaceKeys []resource.PropertyKey `json:"replaceKeys,omitempty"` // the keys causing replacement, if any.
+
+	// the kind of change made to each property path, if the provider reported a detailed diff.
+	DetailedDiff map[string]string `json:"detailedDiff,omitempty"`
+}
+
+// NewSerializedStep creates the machine-readable form of a step from its constituent parts.  Either of the input maps
+// may be nil.  If no changed keys are given, they are computed from the inputs when both are present.
+func NewSerializedStep(op StepOp, urn resource.URN, t tokens.Type, provider string,
+	oldInputs, newInputs resource.PropertyMap, replaceKeys, diffs []resource.PropertyKey,
+	detailedDiff map[string]plugin.PropertyDiff) SerializedStep {
+
+	if diffs == nil && oldInputs != nil && newInputs != nil {
+		if diff := oldInputs.Diff(newInputs); diff != nil {
+			diffs = diff.ChangedKeys()
+		}
+	}
+
+	var serializedDiff map[string]string
+	if detailedDiff != nil {
+		serializedDiff = make(map[string]string)
+		for path, pd := range detailedDiff {
+			serializedDiff[path] = pd.Kind.String()
+		}
+	}
+
+	return SerializedStep{
+		Op:          op,
+		URN:         urn,
+		Type:        t,
+		Provider:    provider,
+		Old:         serializeInputs(oldInputs),
+		New:         serializeInputs(newInputs),
+		Diffs:       diffs,
+		ReplaceKeys: replaceKeys,
+
+		DetailedDiff: serializedDiff,
+	}
+}
+
+// SerializeStep creates the machine-readable form of the given step.
+func SerializeStep(step Step) SerializedStep {
+	var oldInputs, newInputs resource.PropertyMap
+	if old := step.Old(); old != nil {
+		oldInputs = old.Inputs
+	}
+	if new := step.New(); new != nil {
+		newInputs = new.Inputs
+	}
+
+	var keys, diffs []resource.PropertyKey
+	var detailedDiff map[string]plugin.PropertyDiff
+	switch s := step.(type) {
+	case *CreateStep:
+		keys, diffs, detailedDiff = s.Keys(), s.Diffs(), s.DetailedDiff()
+	case *ReplaceStep:
+		keys, diffs, detailedDiff = s.Keys(), s.Diffs(), s.DetailedDiff()
+	case *UpdateStep:
+		diffs, detailedDiff = s.Diffs(), s.DetailedDiff()
+	}
+
+	return NewSerializedStep(step.Op(), step.URN(), step.Type(), step.Provider(), oldInputs, newInputs, keys,
+		diffs, detailedDiff)
+}
+
+// serializeInputs turns a property map into a JSON-friendly object, replacing unknowns with a sentinel value.
+func serializeInputs(inputs resource.PropertyMap) map[string]interface{} {
+	if inputs == nil {
+		return nil
+	}
+	return inputs.MapRepl(nil, func(v resource.PropertyValue) (interface{}, bool) {
+		switch {
+		case v.IsComputed() || v.IsOutput():
+			return plugin.UnknownStringValue, true
+		case v.IsAsset():
+			return v.AssetValue().Serialize(), true
+		case v.IsArchive():
+			return v.ArchiveValue().Serialize(), true
+		}
+		return nil, false
+	})
+}
+
+// recordSteps appends the given steps to the plan's list of issued steps, if the plan keeps them.
+func (p *Plan) recordSteps(steps ...Step) {
+	if !p.record {
+		return
+	}
+	p.stepsLock.Lock()
+	defer p.stepsLock.Unlock()
+	p.steps = append(p.steps, steps...)
+}
+
+// Steps returns the steps this plan has issued so far, in the order in which they were issued.  Steps are only kept
+// if the plan was executed with Options.RecordSteps set.
+func (p *Plan) Steps() []Step {
+	p.stepsLock.Lock()
+	defer p.stepsLock.Unlock()
+	return append([]Step(nil), p.steps...)
+}
+
+// Serialize returns a JSON document describing every step this plan has issued so far, in order.  This is typically
+// called after the plan has been executed as a preview with Options.RecordSteps set, in order to hand the plan to
+// other tools.
+func (p *Plan) Serialize() ([]byte, error) {
+	steps := p.Steps()
+	plan := SerializedPlan{Steps: make([]SerializedStep, len(steps))}
+	for i, step := range steps {
+		plan.Steps[i] = SerializeStep(step)
+	}
+	return json.Marshal(plan)
+}
+
+// timestampRegexp matches the RFC3339 timestamps that StableText elides from property values.
+var timestampRegexp = regexp.MustCompile(
+	`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// elidedTimestamp replaces each timestamp elided by StableText.
+const elidedTimestamp = "<timestamp>"
+
+// StableText renders the plan in a textual form that does not depend on the order in which independent steps were
+// issued, nor on the time at which the plan was made, so that it can be compared against golden files. Steps are
+// sorted by URN, keeping the steps for each resource in the order in which they were issued; changed and replacement
+// keys are sorted; provider references are reduced to the providers' URNs, since provider IDs are assigned afresh by
+// each deployment; and timestamps in property values are elided.
+func (plan SerializedPlan) StableText() ([]byte, error) {
+	steps := make([]SerializedStep, len(plan.Steps))
+	for i, step := range plan.Steps {
+		steps[i] = step.stable()
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].URN < steps[j].URN
+	})
+
+	var text bytes.Buffer
+	encoder := json.NewEncoder(&text)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(SerializedPlan{Steps: steps}); err != nil {
+		return nil, err
+	}
+	return text.Bytes(), nil
+}
+
+// stable returns a copy of this step normalized as described by StableText.
+func (step SerializedStep) stable() SerializedStep {
+	if ref, err := providers.ParseReference(step.Provider); err == nil {
+		step.Provider = string(ref.URN())
+	}
+	step.Old = elideTimestamps(step.Old).(map[string]interface{})
+	step.New = elideTimestamps(step.New).(map[string]interface{})
+	step.Diffs = sortedKeys(step.Diffs)
+	step.ReplaceKeys = sortedKeys(step.ReplaceKeys)
+	return step
+}
+
+// elideTimestamps returns a copy of the given JSON-friendly value with the timestamps in its strings elided.
+func elideTimestamps(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return timestampRegexp.ReplaceAllString(v, elidedTimestamp)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, e := range v {
+			result[i] = elideTimestamps(e)
+		}
+		return result
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		result := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			result[k] = elideTimestamps(e)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// sortedKeys returns a sorted copy of the given property keys.
+func sortedKeys(keys []resource.PropertyKey) []resource.PropertyKey {
+	if keys == nil {
+		return nil
+	}
+	result := append([]resource.PropertyKey(nil), keys...)
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
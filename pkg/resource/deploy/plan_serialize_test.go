@@ -0,0 +1,141 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+func TestPlanSerialize(t *testing.T) {
+	plan := &Plan{record: true}
+
+	oldA := newResource("a")
+	oldA.Inputs["foo"] = resource.NewStringProperty("bar")
+	oldA.Inputs["same"] = resource.NewNumberProperty(1)
+	newA := newResource("a")
+	newA.Inputs["foo"] = resource.NewStringProperty("baz")
+	newA.Inputs["same"] = resource.NewNumberProperty(1)
+	newA.Inputs["later"] = resource.MakeComputed(resource.NewStringProperty(""))
+
+	oldB := newResource("b")
+	newB := newResource("b")
+	newB.Inputs["name"] = resource.NewStringProperty("b2")
+
+	oldC := newResource("c")
+
+	plan.recordSteps(NewUpdateStep(plan, nil, oldA, newA, nil, nil, nil))
+	plan.recordSteps(
+		NewReplaceStep(plan, oldB, newB, []resource.PropertyKey{"name"}, []resource.PropertyKey{"name"},
+			map[string]plugin.PropertyDiff{"name": {Kind: plugin.DiffAddReplace}}, true),
+		NewDeleteStep(plan, oldC))
+
+	bytes, err := plan.Serialize()
+	assert.NoError(t, err)
+
+	var serialized SerializedPlan
+	err = json.Unmarshal(bytes, &serialized)
+	assert.NoError(t, err)
+	if !assert.Len(t, serialized.Steps, 3) {
+		t.FailNow()
+	}
+
+	// Steps must come back in the order in which they were issued.
+	update, replace, del := serialized.Steps[0], serialized.Steps[1], serialized.Steps[2]
+	assert.Equal(t, OpUpdate, update.Op)
+	assert.Equal(t, oldA.URN, update.URN)
+	assert.Equal(t, []resource.PropertyKey{"foo", "later"}, update.Diffs)
+	assert.Equal(t, "bar", update.Old["foo"])
+	assert.Equal(t, "baz", update.New["foo"])
+	assert.Equal(t, plugin.UnknownStringValue, update.New["later"])
+	assert.Empty(t, update.ReplaceKeys)
+	assert.Nil(t, update.DetailedDiff)
+
+	assert.Equal(t, OpReplace, replace.Op)
+	assert.Equal(t, oldB.URN, replace.URN)
+	assert.Equal(t, []resource.PropertyKey{"name"}, replace.Diffs)
+	assert.Equal(t, []resource.PropertyKey{"name"}, replace.ReplaceKeys)
+	assert.Equal(t, map[string]string{"name": "add-replace"}, replace.DetailedDiff)
+
+	assert.Equal(t, OpDelete, del.Op)
+	assert.Equal(t, oldC.URN, del.URN)
+	assert.Nil(t, del.New)
+	assert.Empty(t, del.Diffs)
+
+	// Plans that do not record their steps do not keep them.
+	plan = &Plan{}
+	plan.recordSteps(NewDeleteStep(plan, oldC))
+	assert.Empty(t, plan.Steps())
+}
+
+func TestPlanStableText(t *testing.T) {
+	provider := "urn:pulumi:test::test::pulumi:providers:pkgA::default::8c1b0e5d-52a4-4f5e-9b5c-3a1d1e6f2a7b"
+	plan := SerializedPlan{Steps: []SerializedStep{
+		NewSerializedStep(OpCreate, "urn:pulumi:test::test::pkgA:m:typA::resB", "pkgA:m:typA", provider, nil,
+			resource.NewPropertyMapFromMap(map[string]interface{}{
+				"expires": []interface{}{"at 2019-03-04T05:06:07.123Z"},
+			}), nil, nil, nil),
+		NewSerializedStep(OpUpdate, "urn:pulumi:test::test::pkgA:m:typA::resA", "pkgA:m:typA", provider,
+			resource.NewPropertyMapFromMap(map[string]interface{}{"a": 1, "b": 1}),
+			resource.NewPropertyMapFromMap(map[string]interface{}{"a": 2, "b": 2}),
+			nil, []resource.PropertyKey{"b", "a"}, nil),
+	}}
+
+	text, err := plan.StableText()
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "steps": [
+    {
+      "op": "update",
+      "urn": "urn:pulumi:test::test::pkgA:m:typA::resA",
+      "type": "pkgA:m:typA",
+      "provider": "urn:pulumi:test::test::pulumi:providers:pkgA::default",
+      "old": {
+        "a": 1,
+        "b": 1
+      },
+      "new": {
+        "a": 2,
+        "b": 2
+      },
+      "diffs": [
+        "a",
+        "b"
+      ]
+    },
+    {
+      "op": "create",
+      "urn": "urn:pulumi:test::test::pkgA:m:typA::resB",
+      "type": "pkgA:m:typA",
+      "provider": "urn:pulumi:test::test::pulumi:providers:pkgA::default",
+      "new": {
+        "expires": [
+          "at <timestamp>"
+        ]
+      }
+    }
+  ]
+}
+`, string(text))
+
+	// The original plan is left untouched.
+	assert.Equal(t, []resource.PropertyKey{"b", "a"}, plan.Steps[1].Diffs)
+	assert.Equal(t, provider, plan.Steps[0].Provider)
+}
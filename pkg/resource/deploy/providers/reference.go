@@ -46,6 +46,16 @@ func MakeProviderType(pkg tokens.Package) tokens.Type {
 	return tokens.Type("pulumi:providers:" + pkg)
 }
 
+// IsDefaultProvider returns true if the supplied URN refers to a default provider, whether that of the stack or that of
+// one of its fan-out instances.
+func IsDefaultProvider(urn resource.URN) bool {
+	if !IsProviderType(urn.Type()) {
+		return false
+	}
+	_, name, _ := resource.SplitInstanceName(urn.Name())
+	return name == "default"
+}
+
 func getProviderPackage(typ tokens.Type) tokens.Package {
 	contract.Require(IsProviderType(typ), "typ")
 	return tokens.Package(typ.Name())
@@ -0,0 +1,289 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// rateLimitMessageDelay is the shortest wait for a rate limit that is reported to the user. Shorter waits are only
+// logged and recorded in the rate limit metrics.
+const rateLimitMessageDelay = time.Second
+
+// RateLimit bounds the rate at which operations are issued to a provider using a token bucket: each operation takes a
+// token from the bucket, which holds at most Burst tokens and is refilled at Rate tokens per second. Operations that
+// find the bucket empty wait their turn, in the order in which they arrived.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`            // the sustained number of operations per second.
+	Burst int     `json:"burst,omitempty"` // the number of operations that may be issued at once; zero means one.
+}
+
+// validate ensures that the limit allows operations to proceed at all.
+func (l *RateLimit) validate() error {
+	if l.Rate <= 0 || math.IsInf(l.Rate, 0) || math.IsNaN(l.Rate) {
+		return errors.Errorf("rate must be a positive number of operations per second, not %v", l.Rate)
+	}
+	if l.Burst < 0 {
+		return errors.Errorf("burst must not be negative, not %v", l.Burst)
+	}
+	return nil
+}
+
+// RateLimits maps providers and resource types to limits on the rate at which resource operations are issued to them.
+// An operation on a resource is subject to both the limit for its provider's package and the limit for its type, so
+// the limit for a package is shared by all of the package's types, including those with limits of their own.
+type RateLimits struct {
+	Providers map[tokens.Package]*RateLimit `json:"providers,omitempty"` // limits shared by each package's resources.
+	Types     map[tokens.Type]*RateLimit    `json:"types,omitempty"`     // limits for individual resource types.
+}
+
+// Validate ensures that each of the limits is valid.
+func (rl *RateLimits) Validate() error {
+	if rl == nil {
+		return nil
+	}
+	for pkg, l := range rl.Providers {
+		if l == nil {
+			continue
+		}
+		if err := l.validate(); err != nil {
+			return errors.Wrapf(err, "rate limit for provider '%s'", pkg)
+		}
+	}
+	for typ, l := range rl.Types {
+		if l == nil {
+			continue
+		}
+		if err := l.validate(); err != nil {
+			return errors.Wrapf(err, "rate limit for type '%s'", typ)
+		}
+	}
+	return nil
+}
+
+// merge returns a set of limits that holds each of the limits in this set, overridden by those in the other set.
+func (rl *RateLimits) merge(other *RateLimits) *RateLimits {
+	switch {
+	case rl == nil:
+		return other
+	case other == nil:
+		return rl
+	}
+
+	result := &RateLimits{
+		Providers: make(map[tokens.Package]*RateLimit),
+		Types:     make(map[tokens.Type]*RateLimit),
+	}
+	for _, limits := range []*RateLimits{rl, other} {
+		for pkg, l := range limits.Providers {
+			result.Providers[pkg] = l
+		}
+		for typ, l := range limits.Types {
+			result.Types[typ] = l
+		}
+	}
+	return result
+}
+
+// tokenBucket is the state of a single rate limit.
+type tokenBucket struct {
+	name  string  // the name of the limit, which labels its metrics.
+	rate  float64 // the number of tokens added to the bucket each second.
+	burst float64 // the most tokens that the bucket can hold.
+
+	lock   sync.Mutex
+	tokens float64   // the tokens in the bucket as of last; negative if operations are waiting for tokens.
+	last   time.Time // the time at which tokens was last brought up to date.
+	queued int       // the number of operations waiting for tokens.
+}
+
+func newTokenBucket(name string, limit *RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{name: name, rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// reserve takes a token from the bucket at the given time. It returns how long the caller must wait before using the
+// token, and if that is not zero, the number of operations, including the caller, that are waiting for tokens. A
+// caller that waits must call dequeue once it stops waiting.
+func (b *tokenBucket) reserve(now time.Time) (time.Duration, int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, 0
+	}
+
+	b.queued++
+	providerRateLimitQueued.Add(1, b.name)
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), b.queued
+}
+
+// dequeue records that a caller has stopped waiting for a token. If the caller gave up before its token became
+// available, the token is returned to the bucket.
+func (b *tokenBucket) dequeue(canceled bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.queued--
+	providerRateLimitQueued.Add(-1, b.name)
+	if canceled {
+		b.tokens++
+	}
+}
+
+// rateLimiter holds the token buckets for a plan's rate limits.
+type rateLimiter struct {
+	diag      diag.Sink
+	providers map[tokens.Package]*tokenBucket
+	types     map[tokens.Type]*tokenBucket
+}
+
+// newRateLimiter returns a rate limiter that enforces the given limits, or nil if there are none.
+func newRateLimiter(limits *RateLimits, sink diag.Sink) *rateLimiter {
+	if limits == nil || len(limits.Providers) == 0 && len(limits.Types) == 0 {
+		return nil
+	}
+
+	l := &rateLimiter{
+		diag:      sink,
+		providers: make(map[tokens.Package]*tokenBucket),
+		types:     make(map[tokens.Type]*tokenBucket),
+	}
+	for pkg, limit := range limits.Providers {
+		if limit != nil {
+			l.providers[pkg] = newTokenBucket(string(pkg), limit)
+		}
+	}
+	for typ, limit := range limits.Types {
+		if limit != nil {
+			l.types[typ] = newTokenBucket(string(typ), limit)
+		}
+	}
+	return l
+}
+
+// wait blocks until the limits on the given resource's package and type allow an operation on it to proceed, or
+// until the context is canceled.
+func (l *rateLimiter) wait(ctx context.Context, urn resource.URN, method string) error {
+	typ := urn.Type()
+	var buckets []*tokenBucket
+	if b, ok := l.providers[typ.Package()]; ok {
+		buckets = append(buckets, b)
+	}
+	if b, ok := l.types[typ]; ok {
+		buckets = append(buckets, b)
+	}
+
+	for _, b := range buckets {
+		delay, queued := b.reserve(time.Now())
+		providerRateLimitWaits.Observe(delay.Seconds(), b.name)
+		if delay == 0 {
+			continue
+		}
+
+		logging.V(7).Infof("%s of %s waiting %v for the %s rate limit (%d queued)", method, urn, delay, b.name, queued)
+		if delay >= rateLimitMessageDelay {
+			l.diag.Infof(diag.GetProviderRateLimitedMessage(urn),
+				method, urn.Name(), delay.Round(time.Millisecond), b.name, queued)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			b.dequeue(false)
+		case <-ctx.Done():
+			timer.Stop()
+			b.dequeue(true)
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// wrap returns a provider whose resource operations are subject to this limiter. If the limiter is nil, the provider
+// is returned unchanged.
+func (l *rateLimiter) wrap(ctx context.Context, prov plugin.Provider) plugin.Provider {
+	if l == nil {
+		return prov
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &rateLimitedProvider{Provider: prov, ctx: ctx, limiter: l}
+}
+
+// rateLimitedProvider wraps a provider s.t. its Create, Read, Update, and Delete calls wait for the plan's rate limits.
+// Check and Diff are not limited, as providers generally implement them without calling their backing service.
+type rateLimitedProvider struct {
+	plugin.Provider
+
+	ctx     context.Context
+	limiter *rateLimiter
+}
+
+func (p *rateLimitedProvider) Create(urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+	if err := p.limiter.wait(p.ctx, urn, "create"); err != nil {
+		return "", nil, resource.StatusOK, err
+	}
+	return p.Provider.Create(urn, news, timeout)
+}
+
+func (p *rateLimitedProvider) Read(urn resource.URN, id resource.ID,
+	props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+	if err := p.limiter.wait(p.ctx, urn, "read"); err != nil {
+		return nil, resource.StatusOK, err
+	}
+	return p.Provider.Read(urn, id, props)
+}
+
+func (p *rateLimitedProvider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
+
+	if err := p.limiter.wait(p.ctx, urn, "update"); err != nil {
+		return nil, resource.StatusOK, err
+	}
+	return p.Provider.Update(urn, id, oldInputs, oldOutputs, newInputs, timeout)
+}
+
+func (p *rateLimitedProvider) Delete(urn resource.URN, id resource.ID,
+	props resource.PropertyMap, timeout float64) (resource.Status, error) {
+
+	if err := p.limiter.wait(p.ctx, urn, "delete"); err != nil {
+		return resource.StatusOK, err
+	}
+	return p.Provider.Delete(urn, id, props, timeout)
+}
@@ -0,0 +1,196 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func TestGetRateLimits(t *testing.T) {
+	target := &Target{Config: config.Map{}}
+	limits, err := target.GetRateLimits()
+	assert.NoError(t, err)
+	assert.Nil(t, limits)
+
+	target.Config[rateLimitsKey] = config.NewValue(
+		`{"providers":{"aws":{"rate":10,"burst":20}},"types":{"aws:ec2/instance:Instance":{"rate":0.5}}}`)
+	limits, err = target.GetRateLimits()
+	assert.NoError(t, err)
+	assert.Equal(t, &RateLimits{
+		Providers: map[tokens.Package]*RateLimit{"aws": {Rate: 10, Burst: 20}},
+		Types:     map[tokens.Type]*RateLimit{"aws:ec2/instance:Instance": {Rate: 0.5}},
+	}, limits)
+
+	for _, invalid := range []string{
+		`[]`,
+		`{"providers":{"aws":{"rate":0}}}`,
+		`{"types":{"aws:s3/bucket:Bucket":{"rate":1,"burst":-1}}}`,
+	} {
+		target.Config[rateLimitsKey] = config.NewValue(invalid)
+		_, err = target.GetRateLimits()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMergeRateLimits(t *testing.T) {
+	slow, fast := &RateLimit{Rate: 1}, &RateLimit{Rate: 100}
+	stack := &RateLimits{
+		Providers: map[tokens.Package]*RateLimit{"aws": slow, "gcp": slow},
+	}
+	caller := &RateLimits{
+		Providers: map[tokens.Package]*RateLimit{"aws": fast},
+		Types:     map[tokens.Type]*RateLimit{"gcp:compute:Instance": fast},
+	}
+
+	var none *RateLimits
+	assert.Equal(t, stack, stack.merge(none))
+	assert.Equal(t, caller, none.merge(caller))
+	assert.Equal(t, &RateLimits{
+		Providers: map[tokens.Package]*RateLimit{"aws": fast, "gcp": slow},
+		Types:     map[tokens.Type]*RateLimit{"gcp:compute:Instance": fast},
+	}, stack.merge(caller))
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket("test", &RateLimit{Rate: 2, Burst: 2})
+	now := b.last
+
+	// The bucket starts full, so the first two operations proceed immediately.
+	for i := 0; i < 2; i++ {
+		delay, _ := b.reserve(now)
+		assert.Equal(t, time.Duration(0), delay)
+	}
+
+	// Later operations queue up behind one another, one every half second.
+	delay, queued := b.reserve(now)
+	assert.Equal(t, 500*time.Millisecond, delay)
+	assert.Equal(t, 1, queued)
+	delay, queued = b.reserve(now)
+	assert.Equal(t, time.Second, delay)
+	assert.Equal(t, 2, queued)
+
+	// An operation that gives up returns its token to the bucket.
+	b.dequeue(false)
+	b.dequeue(true)
+	delay, queued = b.reserve(now)
+	assert.Equal(t, time.Second, delay)
+	assert.Equal(t, 1, queued)
+	b.dequeue(false)
+
+	// Once the bucket refills, operations proceed immediately again, but no more than a burst's worth of them.
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		delay, _ = b.reserve(now)
+		assert.Equal(t, time.Duration(0), delay)
+	}
+	delay, _ = b.reserve(now)
+	assert.Equal(t, 500*time.Millisecond, delay)
+	b.dequeue(false)
+}
+
+func TestRateLimitedProvider(t *testing.T) {
+	creates := 0
+	prov := &deploytest.Provider{
+		CreateF: func(urn resource.URN,
+			news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+			creates++
+			return "id", nil, resource.StatusOK, nil
+		},
+	}
+
+	assert.Equal(t, plugin.Provider(prov), newRateLimiter(nil, cmdutil.Diag()).wrap(context.Background(), prov))
+
+	limiter := newRateLimiter(&RateLimits{
+		Providers: map[tokens.Package]*RateLimit{"pkgA": {Rate: 1000, Burst: 10}},
+		Types:     map[tokens.Type]*RateLimit{"pkgA:m:typA": {Rate: 0.001}},
+	}, cmdutil.Diag())
+
+	// Resources of other types are only subject to their package's limit.
+	ctx, cancel := context.WithCancel(context.Background())
+	limited := limiter.wrap(ctx, prov)
+	for i := 0; i < 5; i++ {
+		_, _, _, err := limited.Create("urn:pulumi:stack::proj::pkgA:m:typB::resB", nil, 0)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 5, creates)
+
+	// The first resource of the limited type is created immediately, but the next must wait.
+	_, _, _, err := limited.Create("urn:pulumi:stack::proj::pkgA:m:typA::resA", nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, creates)
+
+	done := make(chan error)
+	go func() {
+		_, _, _, err := limited.Create("urn:pulumi:stack::proj::pkgA:m:typA::resA", nil, 0)
+		done <- err
+	}()
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Equal(t, 6, creates)
+	assert.Equal(t, 0, limiter.types["pkgA:m:typA"].queued)
+}
+
+func TestRateLimitedReadBatch(t *testing.T) {
+	batches := 0
+	prov := &deploytest.BatchProvider{
+		ReadBatchF: func(reads []plugin.BatchRead) ([]plugin.BatchReadResult, error) {
+			batches++
+			return make([]plugin.BatchReadResult, len(reads)), nil
+		},
+	}
+	limiter := newRateLimiter(&RateLimits{
+		Types: map[tokens.Type]*RateLimit{"pkgA:m:typA": {Rate: 0.001}},
+	}, cmdutil.Diag())
+
+	batch := func(names ...string) readBatch {
+		b := readBatch{prov: prov, limiter: limiter}
+		for _, name := range names {
+			urn := resource.URN("urn:pulumi:stack::proj::pkgA:m:typA::" + name)
+			b.steps = append(b.steps, &RefreshStep{old: &resource.State{URN: urn, ID: resource.ID(name)}})
+		}
+		return b
+	}
+
+	// Each resource in a batch takes a token, so a batch of two must wait for the second, and is not read if the
+	// context is canceled while it waits.
+	b := batch("resA")
+	b.read(context.Background())
+	assert.Equal(t, 1, batches)
+	assert.NotNil(t, b.steps[0].prefetched)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b = batch("resB", "resC")
+	done := make(chan bool)
+	go func() {
+		b.read(ctx)
+		close(done)
+	}()
+	cancel()
+	<-done
+	assert.Equal(t, 1, batches)
+	assert.Nil(t, b.steps[0].prefetched)
+}
@@ -0,0 +1,276 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/schema"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+const (
+	// DefaultReadyTimeout is how long a resource may take to satisfy its ready conditions if the plan sets no timeout.
+	DefaultReadyTimeout = 10 * time.Minute
+	// DefaultReadyPollInterval is how often a resource is refreshed while waiting for it to become ready if the plan
+	// sets no interval.
+	DefaultReadyPollInterval = 5 * time.Second
+)
+
+// ReadyCondition is a condition on a resource's outputs that must hold before the resource is considered ready. A
+// condition is either a property path, which requires that the property be present and truthy, or a property path
+// compared against a value with == or !=, as in `status.phase == Running`. The value may be quoted.
+type ReadyCondition struct {
+	Path  resource.PropertyPath // the path of the property to check.
+	Op    string                // the comparison to perform: "==", "!=", or "" to check truthiness.
+	Value string                // the value to compare the property against.
+
+	text string // the original text of the condition.
+}
+
+// ParseReadyCondition parses the text of a ready condition.  The condition is split at the first comparison operator
+// that appears outside of the brackets and quotes of its property path, so the value may itself contain "==" or "!=".
+func ParseReadyCondition(text string) (ReadyCondition, error) {
+	path, op, value := text, "", ""
+	if i := indexReadyOp(text); i != -1 {
+		path, op, value = text[:i], text[i:i+2], strings.TrimSpace(text[i+2:])
+	}
+	if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+		value = value[1 : n-1]
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ReadyCondition{}, errors.Errorf("ready condition %q does not name a property", text)
+	}
+	p, err := resource.ParsePropertyPath(path)
+	if err != nil {
+		return ReadyCondition{}, errors.Wrapf(err, "ready condition %q", text)
+	}
+	return ReadyCondition{Path: p, Op: op, Value: value, text: text}, nil
+}
+
+// indexReadyOp returns the index of the first "==" or "!=" in the given condition that is not within brackets or
+// quotes, or -1 if there is none.
+func indexReadyOp(text string) int {
+	depth, quote := 0, byte(0)
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0 && (c == '=' || c == '!') && i+1 < len(text) && text[i+1] == '=':
+			return i
+		}
+	}
+	return -1
+}
+
+func (c ReadyCondition) String() string {
+	return c.text
+}
+
+// Satisfied returns true if the condition holds for the given outputs. Conditions on unknown values never hold.
+func (c ReadyCondition) Satisfied(outputs resource.PropertyMap) bool {
+	v, ok := c.Path.Get(outputs)
+	if ok && (v.IsComputed() || v.IsOutput()) {
+		return false
+	}
+
+	switch c.Op {
+	case "==":
+		return ok && c.matches(v)
+	case "!=":
+		return !ok || !c.matches(v)
+	default:
+		switch {
+		case !ok || v.IsNull():
+			return false
+		case v.IsBool():
+			return v.BoolValue()
+		case v.IsNumber():
+			return v.NumberValue() != 0
+		case v.IsString():
+			return v.StringValue() != ""
+		default:
+			return true
+		}
+	}
+}
+
+// matches returns true if the given value is equal to the condition's value.
+func (c ReadyCondition) matches(v resource.PropertyValue) bool {
+	switch {
+	case v.IsNull():
+		return c.Value == "null"
+	case v.IsBool():
+		return strconv.FormatBool(v.BoolValue()) == c.Value
+	case v.IsNumber():
+		n, err := strconv.ParseFloat(c.Value, 64)
+		return err == nil && n == v.NumberValue()
+	case v.IsString():
+		return v.StringValue() == c.Value
+	default:
+		return false
+	}
+}
+
+// readinessDefaults caches the ready conditions and timeouts that providers declare for their resource types in their
+// schemas (see schema.Resource), so that each provider's schema is only fetched once per plan.  Schemas are keyed by
+// provider reference rather than by provider, since the plan hands out a fresh wrapper around a provider each time it
+// is asked for one.
+type readinessDefaults struct {
+	lock     sync.Mutex
+	packages map[string]*schema.Package // the schema of each provider, or nil if it publishes none.
+}
+
+// get returns the schema of the resource type with the given token that the given provider, whose reference is ref,
+// declares, if any.
+func (d *readinessDefaults) get(prov plugin.Provider, ref string, t tokens.Type) (*schema.Resource, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	pkg, has := d.packages[ref]
+	if !has {
+		data, err := prov.GetSchema(schema.FormatVersion)
+		switch {
+		case err == plugin.ErrSchemaUnsupported:
+			logging.V(7).Infof("provider for %s publishes no schema; it declares no readiness defaults", t)
+		case err != nil:
+			return nil, errors.Wrapf(err, "fetching the readiness defaults of %s", t)
+		default:
+			if pkg, err = schema.ParsePackage(data); err != nil {
+				return nil, errors.Wrapf(err, "fetching the readiness defaults of %s", t)
+			}
+		}
+		if d.packages == nil {
+			d.packages = make(map[string]*schema.Package)
+		}
+		d.packages[ref] = pkg
+	}
+	if pkg == nil {
+		return nil, nil
+	}
+	return pkg.Resources[t], nil
+}
+
+// readiness returns the ready conditions of a resource of the given type, along with how long it may take to satisfy
+// them.  Conditions registered with the resource take precedence over those that its provider, whose reference is
+// ref, declares for its type.  The plan's timeout takes precedence over the provider's, and either takes precedence
+// over DefaultReadyTimeout.
+func (p *Plan) readiness(prov plugin.Provider, ref string, t tokens.Type, conditions []string) ([]string,
+	time.Duration, error) {
+
+	timeout := p.readyTimeout
+	if len(conditions) == 0 || timeout <= 0 {
+		res, err := p.readinessDefaults.get(prov, ref, t)
+		if err != nil {
+			return nil, 0, err
+		}
+		if res != nil {
+			if len(conditions) == 0 {
+				conditions = res.ReadyConditions
+			}
+			if timeout <= 0 {
+				timeout = time.Duration(res.ReadyTimeout * float64(time.Second))
+			}
+		}
+	}
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+	return conditions, timeout, nil
+}
+
+// awaitReady refreshes a newly created or updated resource until all of its ready conditions hold. It returns the
+// most recent outputs of the resource, which satisfy the conditions unless an error is also returned. A progress
+// message is issued each time the resource is found not to be ready yet.
+func (p *Plan) awaitReady(prov plugin.Provider, state *resource.State, conditions []string,
+	timeout time.Duration) (resource.PropertyMap, error) {
+
+	conds := make([]ReadyCondition, len(conditions))
+	for i, text := range conditions {
+		c, err := ParseReadyCondition(text)
+		if err != nil {
+			return state.Outputs, err
+		}
+		conds[i] = c
+	}
+
+	ctx, interval := p.cancelCtx, p.readyPollInterval
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if interval <= 0 {
+		interval = DefaultReadyPollInterval
+	}
+
+	start := time.Now()
+	outputs := state.Outputs
+	for {
+		var unmet []string
+		for _, c := range conds {
+			if !c.Satisfied(outputs) {
+				unmet = append(unmet, c.String())
+			}
+		}
+		if len(unmet) == 0 {
+			return outputs, nil
+		}
+
+		elapsed := time.Since(start)
+		if elapsed >= timeout {
+			return outputs, errors.Errorf("resource %s did not become ready within %v: %s not satisfied",
+				state.URN, timeout, strings.Join(unmet, ", "))
+		}
+		logging.V(7).Infof("resource %s is not ready after %v: %v", state.URN, elapsed, unmet)
+		p.Diag().Infof(diag.GetResourceAwaitingReadinessMessage(state.URN),
+			state.URN.Name(), strings.Join(unmet, ", "), elapsed.Round(time.Second))
+
+		select {
+		case <-ctx.Done():
+			return outputs, errors.Errorf("canceled while waiting for resource %s to become ready", state.URN)
+		case <-time.After(interval):
+		}
+
+		refreshed, _, err := prov.Read(state.URN, state.ID, outputs)
+		if err != nil {
+			return outputs, err
+		}
+		if refreshed == nil {
+			return outputs, errors.Errorf("resource %s was deleted while waiting for it to become ready", state.URN)
+		}
+		outputs = refreshed
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestParseReadyCondition(t *testing.T) {
+	c, err := ParseReadyCondition("status.phase == Running")
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyPath{"status", "phase"}, c.Path)
+	assert.Equal(t, "==", c.Op)
+	assert.Equal(t, "Running", c.Value)
+	assert.Equal(t, "status.phase == Running", c.String())
+
+	c, err = ParseReadyCondition(`endpoints[0].address != ""`)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyPath{"endpoints", 0, "address"}, c.Path)
+	assert.Equal(t, "!=", c.Op)
+	assert.Equal(t, "", c.Value)
+
+	// Conditions are split at their first operator, outside of any quoted keys.
+	c, err = ParseReadyCondition(`status.phase != "a==b"`)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyPath{"status", "phase"}, c.Path)
+	assert.Equal(t, "!=", c.Op)
+	assert.Equal(t, "a==b", c.Value)
+	c, err = ParseReadyCondition(`labels["a==b"] == yes`)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.PropertyPath{"labels", "a==b"}, c.Path)
+	assert.Equal(t, "==", c.Op)
+	assert.Equal(t, "yes", c.Value)
+
+	c, err = ParseReadyCondition("ready")
+	assert.NoError(t, err)
+	assert.Equal(t, "", c.Op)
+
+	_, err = ParseReadyCondition("== Running")
+	assert.Error(t, err)
+	_, err = ParseReadyCondition("status[ == Running")
+	assert.Error(t, err)
+}
+
+func TestReadyConditionSatisfied(t *testing.T) {
+	outputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"status":   map[string]interface{}{"phase": "Running", "replicas": 3},
+		"ready":    true,
+		"pending":  false,
+		"endpoint": "",
+	})
+	outputs["unknown"] = resource.MakeComputed(resource.NewStringProperty(""))
+
+	cases := map[string]bool{
+		"status.phase == Running":   true,
+		"status.phase == 'Running'": true,
+		"status.phase != Running":   false,
+		"status.phase == Pending":   false,
+		"status.replicas == 3":      true,
+		"status.replicas == 3.0":    true,
+		"status.replicas == three":  false,
+		"ready":                     true,
+		"ready == true":             true,
+		"pending":                   false,
+		"endpoint":                  false,
+		"missing":                   false,
+		"missing != foo":            true,
+		"unknown":                   false,
+		"unknown != foo":            false,
+	}
+	for text, expected := range cases {
+		c, err := ParseReadyCondition(text)
+		if assert.NoError(t, err, text) {
+			assert.Equal(t, expected, c.Satisfied(outputs), text)
+		}
+	}
+}
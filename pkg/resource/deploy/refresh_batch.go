@@ -0,0 +1,135 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// DefaultRefreshBatchSize is the largest number of resources that a refresh reads in a single call to a provider if
+// the plan sets no batch size.
+const DefaultRefreshBatchSize = 100
+
+// readBatch is a set of refresh steps whose resources are read with a single call to their provider.
+type readBatch struct {
+	prov    plugin.BatchProvider
+	limiter *rateLimiter
+	steps   []*RefreshStep
+}
+
+// prefetchReads reads the current state of the resources refreshed by the given steps in batches, issuing at most
+// opts.DegreeOfParallelism() batches at once. Only the resources of batch providers are prefetched. A step whose
+// resource was not prefetched, or whose batched read failed, reads its resource individually when it is applied, so
+// that the usual retry policies apply. Each resource in a batch counts against the plan's rate limits as a read.
+func (pe *planExecutor) prefetchReads(ctx context.Context, opts Options, steps []Step) {
+	size := opts.RefreshBatchSize
+	if size == 0 {
+		size = DefaultRefreshBatchSize
+	} else if size < 0 {
+		return
+	}
+
+	// Group the steps by provider, keeping them in snapshot order, and split each group into batches.
+	var refs []string
+	groups := make(map[string][]*RefreshStep)
+	for _, s := range steps {
+		rs, ok := s.(*RefreshStep)
+		if !ok || !rs.refreshable() {
+			continue
+		}
+		if _, has := groups[rs.old.Provider]; !has {
+			refs = append(refs, rs.old.Provider)
+		}
+		groups[rs.old.Provider] = append(groups[rs.old.Provider], rs)
+	}
+	var batches []readBatch
+	for _, ref := range refs {
+		providerRef, err := providers.ParseReference(ref)
+		if err != nil {
+			continue
+		}
+		prov, ok := pe.plan.providers.GetProvider(providerRef)
+		if !ok {
+			continue
+		}
+		bp, ok := prov.(plugin.BatchProvider)
+		if !ok {
+			continue
+		}
+		for group := groups[ref]; len(group) > 0; {
+			n := size
+			if n > len(group) {
+				n = len(group)
+			}
+			batches = append(batches, readBatch{prov: bp, limiter: pe.plan.limiter, steps: group[:n]})
+			group = group[n:]
+		}
+	}
+	if len(batches) == 0 {
+		return
+	}
+
+	parallel := opts.DegreeOfParallelism()
+	if parallel > len(batches) {
+		parallel = len(batches)
+	}
+	logging.V(7).Infof("planExecutor.prefetchReads(...): reading %d batches, %d at a time", len(batches), parallel)
+
+	sem := make(chan bool, parallel)
+	var wg sync.WaitGroup
+	for _, b := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- true
+		wg.Add(1)
+		go func(b readBatch) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			b.read(ctx)
+		}(b)
+	}
+	wg.Wait()
+}
+
+// read reads the batch's resources and records the result of each read with its step. The batch is not read if the
+// context is canceled while it waits for the plan's rate limits.
+func (b readBatch) read(ctx context.Context) {
+	reads := make([]plugin.BatchRead, len(b.steps))
+	for i, s := range b.steps {
+		if b.limiter != nil {
+			if err := b.limiter.wait(ctx, s.old.URN, "read"); err != nil {
+				return
+			}
+		}
+		reads[i] = plugin.BatchRead{URN: s.old.URN, ID: s.old.ID, Props: s.old.Outputs}
+	}
+	results, err := b.prov.ReadBatch(reads)
+	if err != nil {
+		logging.V(7).Infof("batched read of %d resources failed; reading them individually: %v", len(reads), err)
+		return
+	}
+	for i, s := range b.steps {
+		result := results[i]
+		s.prefetched = &result
+	}
+}
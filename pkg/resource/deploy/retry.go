@@ -0,0 +1,300 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/retry"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+)
+
+// ErrorClass classifies a transient provider failure.
+type ErrorClass string
+
+const (
+	ErrorClassThrottled   ErrorClass = "throttled"   // the provider's backing service is rate-limiting requests.
+	ErrorClassUnavailable ErrorClass = "unavailable" // the provider or its backing service is temporarily unavailable.
+	ErrorClassTimeout     ErrorClass = "timeout"     // the request timed out before it could complete.
+	ErrorClassConflict    ErrorClass = "conflict"    // the resource was in a conflicting state that may clear up.
+	ErrorClassRetryable   ErrorClass = "retryable"   // the provider reported that the failure is transient.
+)
+
+// ClassifyError returns the class of the given provider error, or false if the error is not known to be transient.
+// Providers that describe their failures with a plugin.ProviderError decide for themselves which are transient; other
+// errors are classified by their gRPC status codes alone, since their messages may come from anywhere.
+func ClassifyError(err error) (ErrorClass, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	if perr, ok := plugin.GetProviderError(err); ok {
+		switch {
+		case !perr.IsRetryable():
+			return "", false
+		case perr.Class == plugin.ProviderErrorThrottled:
+			return ErrorClassThrottled, true
+		case perr.Class == plugin.ProviderErrorConflict:
+			return ErrorClassConflict, true
+		default:
+			return ErrorClassRetryable, true
+		}
+	}
+
+	if rpcErr, ok := rpcerror.FromError(err); ok && rpcErr != nil {
+		switch rpcErr.Code() {
+		case codes.ResourceExhausted:
+			return ErrorClassThrottled, true
+		case codes.Unavailable:
+			return ErrorClassUnavailable, true
+		case codes.DeadlineExceeded:
+			return ErrorClassTimeout, true
+		}
+	}
+	return "", false
+}
+
+// RetryPolicy controls how failed provider operations are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // the maximum number of attempts, including the first (<=1 disables retries).
+	Delay       time.Duration // the base delay between attempts, before backoff is applied.
+	Backoff     float64       // the multiplier applied to the delay after each retry.
+	MaxDelay    time.Duration // the maximum delay between retries.
+	Jitter      float64       // the fraction by which each delay is randomly perturbed.
+	Classes     []ErrorClass  // the classes of errors to retry; if empty, all transient errors are retried.
+
+	// RetryMutations allows creates, updates, and deletes that fail because the provider was unavailable or timed out
+	// to be retried. Such a failure does not reveal whether the operation took effect, so retrying it could, e.g.,
+	// create a duplicate cloud resource. Without this option, mutations are only retried if they were throttled or if
+	// the provider reported that they may be retried.
+	RetryMutations bool
+}
+
+// DefaultRetryPolicy is the policy used for resource types that have no policy of their own.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	Delay:       time.Second,
+	Backoff:     2,
+	MaxDelay:    30 * time.Second,
+	Jitter:      0.2,
+}
+
+// Retries returns true if this policy retries errors of the given class.
+func (p RetryPolicy) Retries(class ErrorClass) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+	if len(p.Classes) == 0 {
+		return true
+	}
+	for _, c := range p.Classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// RetriesMutation returns true if this policy retries creates, updates, and deletes that fail with errors of the
+// given class.
+func (p RetryPolicy) RetriesMutation(class ErrorClass) bool {
+	if !p.RetryMutations && (class == ErrorClassUnavailable || class == ErrorClassTimeout) {
+		return false
+	}
+	return p.Retries(class)
+}
+
+// RetryPolicies maps resource types to the policies used to retry their provider operations.
+type RetryPolicies struct {
+	Default *RetryPolicy                 // the policy for types without an entry in Types; nil uses DefaultRetryPolicy.
+	Types   map[tokens.Type]*RetryPolicy // per-type policies.
+}
+
+// PolicyFor returns the retry policy for the given resource type.
+func (rp *RetryPolicies) PolicyFor(t tokens.Type) RetryPolicy {
+	if rp != nil {
+		if p, ok := rp.Types[t]; ok && p != nil {
+			return *p
+		}
+		if rp.Default != nil {
+			return *rp.Default
+		}
+	}
+	return DefaultRetryPolicy
+}
+
+// retryingProvider wraps a provider s.t. its resource operations are retried according to the plan's retry policies.
+// Operations are only retried if they fail with a transient error and report that the resource was left untouched.
+// Checks, diffs, and reads are retried on any transient error; see RetryPolicy.RetryMutations for the others.
+type retryingProvider struct {
+	plugin.Provider
+
+	ctx      context.Context
+	diag     diag.Sink
+	policies *RetryPolicies
+}
+
+func newRetryingProvider(ctx context.Context, prov plugin.Provider, sink diag.Sink,
+	policies *RetryPolicies) plugin.Provider {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &retryingProvider{Provider: prov, ctx: ctx, diag: sink, policies: policies}
+}
+
+// retry invokes op until it succeeds, fails with an error that the policy for the given resource does not retry, or
+// the policy's attempts are exhausted. If mutation is true, op may modify the resource.
+func (p *retryingProvider) retry(urn resource.URN, method string, mutation bool,
+	op func() (resource.Status, error)) error {
+
+	policy := p.policies.PolicyFor(urn.Type())
+
+	delay, backoff, maxDelay, jitter := policy.Delay, policy.Backoff, policy.MaxDelay, policy.Jitter
+	var lastErr error
+	_, _, _ = retry.Until(p.ctx, retry.Acceptor{
+		Delay:    &delay,
+		Backoff:  &backoff,
+		MaxDelay: &maxDelay,
+		Jitter:   &jitter,
+		Accept: func(try int, nextRetryTime time.Duration) (bool, interface{}, error) {
+			status, err := op()
+			lastErr = err
+			if err == nil {
+				return true, nil, nil
+			}
+
+			class, transient := ClassifyError(err)
+			retries := policy.Retries
+			if mutation {
+				retries = policy.RetriesMutation
+			}
+			if !transient || status != resource.StatusOK || !retries(class) || try+1 >= policy.MaxAttempts {
+				return true, nil, nil
+			}
+
+			// If the provider suggested a longer delay than the policy's, wait out the difference before the retry. The
+			// policy's maximum delay still applies, so that a provider cannot stall the update indefinitely.
+			wait := nextRetryTime
+			if perr, ok := plugin.GetProviderError(err); ok && perr.RetryAfter > wait {
+				wait = perr.RetryAfter
+				if maxDelay > 0 && wait > maxDelay {
+					wait = maxDelay
+				}
+				if wait < nextRetryTime {
+					wait = nextRetryTime
+				}
+			}
+
+			logging.V(7).Infof("%s of %s failed with %s error; retrying in %v: %v", method, urn, class, wait, err)
+			providerRetries.Inc(string(class))
+			p.diag.Warningf(diag.GetProviderRetryWarning(urn),
+				method, urn.Name(), err, wait, try+2, policy.MaxAttempts)
+			if wait > nextRetryTime {
+				select {
+				case <-time.After(wait - nextRetryTime):
+				case <-p.ctx.Done():
+				}
+			}
+			return false, nil, nil
+		},
+	})
+	return lastErr
+}
+
+func (p *retryingProvider) Check(urn resource.URN, olds, news resource.PropertyMap,
+	allowUnknowns bool) (resource.PropertyMap, []plugin.CheckFailure, error) {
+
+	var inputs resource.PropertyMap
+	var failures []plugin.CheckFailure
+	err := p.retry(urn, "check", false, func() (resource.Status, error) {
+		var err error
+		inputs, failures, err = p.Provider.Check(urn, olds, news, allowUnknowns)
+		return resource.StatusOK, err
+	})
+	return inputs, failures, err
+}
+
+func (p *retryingProvider) Diff(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, allowUnknowns bool) (plugin.DiffResult, error) {
+
+	var diff plugin.DiffResult
+	err := p.retry(urn, "diff", false, func() (resource.Status, error) {
+		var err error
+		diff, err = p.Provider.Diff(urn, id, oldInputs, oldOutputs, newInputs, allowUnknowns)
+		return resource.StatusOK, err
+	})
+	return diff, err
+}
+
+func (p *retryingProvider) Create(urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+	var id resource.ID
+	var outs resource.PropertyMap
+	var status resource.Status
+	err := p.retry(urn, "create", true, func() (resource.Status, error) {
+		var err error
+		id, outs, status, err = p.Provider.Create(urn, news, timeout)
+		return status, err
+	})
+	return id, outs, status, err
+}
+
+func (p *retryingProvider) Read(urn resource.URN, id resource.ID,
+	props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+	var outs resource.PropertyMap
+	var status resource.Status
+	err := p.retry(urn, "read", false, func() (resource.Status, error) {
+		var err error
+		outs, status, err = p.Provider.Read(urn, id, props)
+		return status, err
+	})
+	return outs, status, err
+}
+
+func (p *retryingProvider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
+
+	var outs resource.PropertyMap
+	var status resource.Status
+	err := p.retry(urn, "update", true, func() (resource.Status, error) {
+		var err error
+		outs, status, err = p.Provider.Update(urn, id, oldInputs, oldOutputs, newInputs, timeout)
+		return status, err
+	})
+	return outs, status, err
+}
+
+func (p *retryingProvider) Delete(urn resource.URN, id resource.ID,
+	props resource.PropertyMap, timeout float64) (resource.Status, error) {
+
+	var status resource.Status
+	err := p.retry(urn, "delete", true, func() (resource.Status, error) {
+		var err error
+		status, err = p.Provider.Delete(urn, id, props, timeout)
+		return status, err
+	})
+	return status, err
+}
@@ -0,0 +1,174 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err       error
+		class     ErrorClass
+		transient bool
+	}{
+		{nil, "", false},
+		{errors.New("boom"), "", false},
+		{rpcerror.New(codes.InvalidArgument, "bad input"), "", false},
+		{rpcerror.New(codes.ResourceExhausted, "slow down"), ErrorClassThrottled, true},
+		{rpcerror.New(codes.Unavailable, "connection refused"), ErrorClassUnavailable, true},
+		{rpcerror.New(codes.DeadlineExceeded, "timed out"), ErrorClassTimeout, true},
+		{errors.New("Throttling: Rate exceeded"), "", false},
+		{errors.New("error 429: Too Many Requests"), "", false},
+		{&plugin.ProviderError{Class: plugin.ProviderErrorThrottled}, ErrorClassThrottled, true},
+		{&plugin.ProviderError{Class: plugin.ProviderErrorConflict, Retryable: true}, ErrorClassConflict, true},
+		{&plugin.ProviderError{Class: plugin.ProviderErrorConflict}, "", false},
+		{errors.Wrap(&plugin.ProviderError{Class: plugin.ProviderErrorRetryable}, "create"), ErrorClassRetryable, true},
+		{&plugin.ProviderError{Class: plugin.ProviderErrorAuth, Message: "429 Too Many Requests"}, "", false},
+	}
+	for _, c := range cases {
+		class, transient := ClassifyError(c.err)
+		assert.Equal(t, c.class, class)
+		assert.Equal(t, c.transient, transient)
+	}
+}
+
+func TestRetryPolicies(t *testing.T) {
+	var nilPolicies *RetryPolicies
+	assert.Equal(t, DefaultRetryPolicy, nilPolicies.PolicyFor("pkgA:m:typA"))
+
+	never := &RetryPolicy{MaxAttempts: 1}
+	throttling := &RetryPolicy{MaxAttempts: 3, Classes: []ErrorClass{ErrorClassThrottled}}
+	policies := &RetryPolicies{
+		Default: never,
+		Types:   map[tokens.Type]*RetryPolicy{"pkgA:m:typB": throttling},
+	}
+	assert.Equal(t, *never, policies.PolicyFor("pkgA:m:typA"))
+	assert.Equal(t, *throttling, policies.PolicyFor("pkgA:m:typB"))
+
+	assert.False(t, never.Retries(ErrorClassThrottled))
+	assert.True(t, throttling.Retries(ErrorClassThrottled))
+	assert.False(t, throttling.Retries(ErrorClassUnavailable))
+	assert.True(t, DefaultRetryPolicy.Retries(ErrorClassTimeout))
+
+	// Mutations are only retried on errors that show that they did not take effect, unless the policy opts in.
+	assert.True(t, DefaultRetryPolicy.RetriesMutation(ErrorClassThrottled))
+	assert.True(t, DefaultRetryPolicy.RetriesMutation(ErrorClassRetryable))
+	assert.False(t, DefaultRetryPolicy.RetriesMutation(ErrorClassUnavailable))
+	assert.False(t, DefaultRetryPolicy.RetriesMutation(ErrorClassTimeout))
+	mutations := &RetryPolicy{MaxAttempts: 3, RetryMutations: true}
+	assert.True(t, mutations.RetriesMutation(ErrorClassTimeout))
+	assert.False(t, throttling.RetriesMutation(ErrorClassTimeout))
+}
+
+func TestRetryMutations(t *testing.T) {
+	reads, creates := 0, 0
+	prov := &deploytest.Provider{
+		ReadF: func(urn resource.URN, id resource.ID,
+			props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+			reads++
+			if reads == 1 {
+				return nil, resource.StatusOK, rpcerror.New(codes.Unavailable, "connection reset")
+			}
+			return props, resource.StatusOK, nil
+		},
+		CreateF: func(urn resource.URN,
+			news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+			creates++
+			return "", nil, resource.StatusOK, rpcerror.New(codes.Unavailable, "connection reset")
+		},
+	}
+
+	// The read is retried, but the create may have taken effect and is not.
+	const urn = resource.URN("urn:pulumi:stack::proj::pkgA:m:typA::resA")
+	retrying := newRetryingProvider(context.Background(), prov, cmdutil.Diag(),
+		&RetryPolicies{Default: &RetryPolicy{MaxAttempts: 3}})
+	_, _, err := retrying.Read(urn, "id", resource.PropertyMap{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reads)
+	_, _, _, err = retrying.Create(urn, resource.PropertyMap{}, 0)
+	assert.Error(t, err)
+	assert.Equal(t, 1, creates)
+
+	// Policies that opt in retry the create as well.
+	retrying = newRetryingProvider(context.Background(), prov, cmdutil.Diag(),
+		&RetryPolicies{Default: &RetryPolicy{MaxAttempts: 3, RetryMutations: true}})
+	_, _, _, err = retrying.Create(urn, resource.PropertyMap{}, 0)
+	assert.Error(t, err)
+	assert.Equal(t, 4, creates)
+}
+
+func TestRetryAfter(t *testing.T) {
+	attempts := 0
+	prov := &deploytest.Provider{
+		DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+			attempts++
+			if attempts == 1 {
+				return resource.StatusOK, &plugin.ProviderError{
+					Class:      plugin.ProviderErrorThrottled,
+					RetryAfter: 50 * time.Millisecond,
+				}
+			}
+			return resource.StatusOK, nil
+		},
+	}
+
+	// The policy would retry immediately, but the provider asked for a longer delay.
+	retrying := newRetryingProvider(context.Background(), prov, cmdutil.Diag(),
+		&RetryPolicies{Default: &RetryPolicy{MaxAttempts: 2}})
+	start := time.Now()
+	_, err := retrying.Delete("urn:pulumi:stack::proj::pkgA:m:typA::resA", "id", nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestRetryAfterCappedAtMaxDelay(t *testing.T) {
+	attempts := 0
+	prov := &deploytest.Provider{
+		DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+			attempts++
+			if attempts == 1 {
+				return resource.StatusOK, &plugin.ProviderError{
+					Class:      plugin.ProviderErrorThrottled,
+					RetryAfter: time.Hour,
+				}
+			}
+			return resource.StatusOK, nil
+		},
+	}
+
+	// The provider asked for a far longer delay than the policy allows, so the policy's maximum is used instead.
+	retrying := newRetryingProvider(context.Background(), prov, cmdutil.Diag(),
+		&RetryPolicies{Default: &RetryPolicy{MaxAttempts: 2, MaxDelay: 50 * time.Millisecond}})
+	start := time.Now()
+	_, err := retrying.Delete("urn:pulumi:stack::proj::pkgA:m:typA::resA", "id", nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.True(t, time.Since(start) < time.Minute)
+}
@@ -17,10 +17,9 @@ package deploy
 import (
 	"crypto/sha256"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/pkg/errors"
-
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/workspace"
@@ -62,6 +61,51 @@ func NewSnapshot(manifest Manifest, resources []*resource.State, ops []resource.
 	}
 }
 
+// IntegrityProblemKind classifies the ways in which a snapshot can fail integrity verification.
+type IntegrityProblemKind string
+
+const (
+	// IntegrityMagicMismatch indicates that the manifest's magic cookie does not match its contents.
+	IntegrityMagicMismatch IntegrityProblemKind = "magic-mismatch"
+	// IntegrityUnreferenceableProvider indicates that a provider resource cannot be referred to by other resources.
+	IntegrityUnreferenceableProvider IntegrityProblemKind = "unreferenceable-provider"
+	// IntegrityInvalidProviderReference indicates that a resource's provider reference cannot be parsed.
+	IntegrityInvalidProviderReference IntegrityProblemKind = "invalid-provider-reference"
+	// IntegrityProviderOutOfOrder indicates that a resource's provider comes after it in the resource list.
+	IntegrityProviderOutOfOrder IntegrityProblemKind = "provider-out-of-order"
+	// IntegrityMissingProvider indicates that a resource refers to a provider that is not in the snapshot.
+	IntegrityMissingProvider IntegrityProblemKind = "missing-provider"
+	// IntegrityParentOutOfOrder indicates that a resource's parent comes after it in the resource list.
+	IntegrityParentOutOfOrder IntegrityProblemKind = "parent-out-of-order"
+	// IntegrityMissingParent indicates that a resource refers to a parent that is not in the snapshot.
+	IntegrityMissingParent IntegrityProblemKind = "missing-parent"
+	// IntegrityDependencyOutOfOrder indicates that one of a resource's dependencies comes after it in the resource
+	// list.
+	IntegrityDependencyOutOfOrder IntegrityProblemKind = "dependency-out-of-order"
+	// IntegrityMissingDependency indicates that a resource depends on a resource that is not in the snapshot.
+	IntegrityMissingDependency IntegrityProblemKind = "missing-dependency"
+	// IntegrityDuplicateURN indicates that more than one resource with the same URN is not pending deletion.
+	IntegrityDuplicateURN IntegrityProblemKind = "duplicate-urn"
+	// IntegrityInvalidPendingOperation indicates that a pending operation does not agree with the resource list.
+	IntegrityInvalidPendingOperation IntegrityProblemKind = "invalid-pending-operation"
+	// IntegrityDanglingPropertyReference indicates that a property refers to the URN of a resource in this stack that
+	// is not in the snapshot.
+	IntegrityDanglingPropertyReference IntegrityProblemKind = "dangling-property-reference"
+)
+
+// IntegrityProblem describes a single integrity failure found by Verify.
+type IntegrityProblem struct {
+	Kind     IntegrityProblemKind // the kind of problem.
+	Resource *resource.State      // the resource that has the problem, if any.
+	Related  resource.URN         // the URN the resource refers to, for problems with references.
+	Warning  bool                 // true if the problem does not prevent the snapshot from being used.
+	Message  string               // a human-readable description of the problem.
+}
+
+func (p IntegrityProblem) Error() string {
+	return p.Message
+}
+
 // VerifyIntegrity checks a snapshot to ensure it is well-formed.  Because of the cost of this operation,
 // integrity verification is only performed on demand, and not automatically during snapshot construction.
 //
@@ -72,71 +116,178 @@ func NewSnapshot(manifest Manifest, resources []*resource.State, ops []resource.
 //  4. Dependents must precede their dependencies in the resource list
 //  5. For every URN in the snapshot, there must be at most one resource with that URN that is not pending deletion
 //  6. The magic manifest number should change every time the snapshot is mutated
+//
+// Only the first violation is returned; use Verify to find all of them.
 func (snap *Snapshot) VerifyIntegrity() error {
-	if snap != nil {
-		// Ensure the magic cookie checks out.
-		if snap.Manifest.Magic != snap.Manifest.NewMagic() {
-			return errors.Errorf("magic cookie mismatch; possible tampering/corruption detected")
+	for _, problem := range snap.Verify() {
+		if !problem.Warning {
+			return problem
 		}
+	}
+	return nil
+}
 
-		// Now check the resources.  For now, we just verify that parents come before children, and that there aren't
-		// any duplicate URNs.
-		urns := make(map[resource.URN]*resource.State)
-		provs := make(map[providers.Reference]struct{})
-		for i, state := range snap.Resources {
-			urn := state.URN
-
-			if providers.IsProviderType(state.Type) {
-				ref, err := providers.NewReference(urn, state.ID)
-				if err != nil {
-					return errors.Errorf("provider %s is not referenceable: %v", urn, err)
-				}
+// Verify checks a snapshot for every violation of the invariants enforced by VerifyIntegrity, and additionally
+// reports pending operations that do not agree with the resource list and URN-valued properties that refer to
+// resources in this stack that no longer exist.  The latter two are reported as warnings, since the engine tolerates
+// them.  Problems are returned in the order in which they occur in the snapshot.
+func (snap *Snapshot) Verify() []IntegrityProblem {
+	if snap == nil {
+		return nil
+	}
+
+	var problems []IntegrityProblem
+	report := func(kind IntegrityProblemKind, state *resource.State, related resource.URN, warning bool,
+		format string, args ...interface{}) {
+		problems = append(problems, IntegrityProblem{
+			Kind:     kind,
+			Resource: state,
+			Related:  related,
+			Warning:  warning,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// Ensure the magic cookie checks out.
+	if snap.Manifest.Magic != snap.Manifest.NewMagic() {
+		report(IntegrityMagicMismatch, nil, "", false, "magic cookie mismatch; possible tampering/corruption detected")
+	}
+
+	// comesLater returns true if a resource with the given URN appears after the i'th resource.
+	comesLater := func(i int, urn resource.URN) bool {
+		for _, other := range snap.Resources[i+1:] {
+			if other.URN == urn {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Now check the resources.  We verify that providers, parents and dependencies come before the resources that
+	// refer to them, and that there aren't any duplicate URNs.
+	urns := make(map[resource.URN]*resource.State)
+	provs := make(map[providers.Reference]struct{})
+	for i, state := range snap.Resources {
+		urn := state.URN
+
+		if providers.IsProviderType(state.Type) {
+			ref, err := providers.NewReference(urn, state.ID)
+			if err != nil {
+				report(IntegrityUnreferenceableProvider, state, "", false,
+					"provider %s is not referenceable: %v", urn, err)
+			} else {
 				provs[ref] = struct{}{}
 			}
-			if provider := state.Provider; provider != "" {
-				ref, err := providers.ParseReference(provider)
-				if err != nil {
-					return errors.Errorf("failed to parse provider reference for resource %s: %v", urn, err)
+		}
+		if provider := state.Provider; provider != "" {
+			ref, err := providers.ParseReference(provider)
+			switch {
+			case err != nil:
+				report(IntegrityInvalidProviderReference, state, "", false,
+					"failed to parse provider reference for resource %s: %v", urn, err)
+			case comesLater(i, ref.URN()):
+				if _, has := provs[ref]; !has {
+					report(IntegrityProviderOutOfOrder, state, ref.URN(), false,
+						"resource %s's provider %s comes after it", urn, ref)
 				}
+			default:
 				if _, has := provs[ref]; !has {
-					return errors.Errorf("resource %s refers to unknown provider %s", urn, ref)
+					report(IntegrityMissingProvider, state, ref.URN(), false,
+						"resource %s refers to unknown provider %s", urn, ref)
 				}
 			}
+		}
 
-			if par := state.Parent; par != "" {
-				if _, has := urns[par]; !has {
-					// The parent isn't there; to give a good error message, see whether it's missing entirely, or
-					// whether it comes later in the snapshot (neither of which should ever happen).
-					for _, other := range snap.Resources[i+1:] {
-						if other.URN == par {
-							return errors.Errorf("child resource %s's parent %s comes after it", urn, par)
-						}
-					}
-					return errors.Errorf("child resource %s refers to missing parent %s", urn, par)
+		if par := state.Parent; par != "" {
+			if _, has := urns[par]; !has {
+				// The parent isn't there; to give a good error message, see whether it's missing entirely, or
+				// whether it comes later in the snapshot (neither of which should ever happen).
+				if comesLater(i, par) {
+					report(IntegrityParentOutOfOrder, state, par, false,
+						"child resource %s's parent %s comes after it", urn, par)
+				} else {
+					report(IntegrityMissingParent, state, par, false,
+						"child resource %s refers to missing parent %s", urn, par)
 				}
 			}
+		}
 
-			for _, dep := range state.Dependencies {
-				if _, has := urns[dep]; !has {
-					// same as above - doing this for better error messages
-					for _, other := range snap.Resources[i+1:] {
-						if other.URN == dep {
-							return errors.Errorf("resource %s's dependency %s comes after it", urn, other.URN)
-						}
-					}
-
-					return errors.Errorf("resource %s dependency %s refers to missing resource", urn, dep)
+		for _, dep := range state.Dependencies {
+			if _, has := urns[dep]; !has {
+				// same as above - doing this for better error messages
+				if comesLater(i, dep) {
+					report(IntegrityDependencyOutOfOrder, state, dep, false,
+						"resource %s's dependency %s comes after it", urn, dep)
+				} else {
+					report(IntegrityMissingDependency, state, dep, false,
+						"resource %s dependency %s refers to missing resource", urn, dep)
 				}
 			}
+		}
+
+		if _, has := urns[urn]; has && !state.Delete {
+			// The only time we should have duplicate URNs is when all but one of them are marked for deletion.
+			report(IntegrityDuplicateURN, state, "", false, "duplicate resource %s (not marked for deletion)", urn)
+		}
+
+		urns[urn] = state
+	}
 
-			if _, has := urns[urn]; has && !state.Delete {
-				// The only time we should have duplicate URNs is when all but one of them are marked for deletion.
-				return errors.Errorf("duplicate resource %s (not marked for deletion)", urn)
+	// Pending operations must refer to resources, and updates and deletes must refer to resources that exist.
+	for _, op := range snap.PendingOperations {
+		switch {
+		case op.Resource == nil:
+			report(IntegrityInvalidPendingOperation, nil, "", true,
+				"pending %s operation does not refer to a resource", op.Type)
+		case op.Type == resource.OperationTypeUpdating || op.Type == resource.OperationTypeDeleting:
+			if _, has := urns[op.Resource.URN]; !has {
+				report(IntegrityInvalidPendingOperation, op.Resource, op.Resource.URN, true,
+					"pending %s operation refers to missing resource %s", op.Type, op.Resource.URN)
 			}
+		}
+	}
 
-			urns[urn] = state
+	// Finally, look for properties that hold the URN of a resource in this stack that no longer exists.  These are
+	// most often left behind by manual edits to the state.
+	for _, state := range snap.Resources {
+		for _, props := range []resource.PropertyMap{state.Inputs, state.Outputs} {
+			for _, key := range props.StableKeys() {
+				for _, ref := range danglingURNs(props[key], state.URN, urns) {
+					report(IntegrityDanglingPropertyReference, state, ref, true,
+						"resource %s property %s refers to missing resource %s", state.URN, key, ref)
+				}
+			}
 		}
 	}
 
+	return problems
+}
+
+// danglingURNs returns the URNs held by v that belong to the same stack and project as the given URN but do not
+// refer to any resource in the given set.
+func danglingURNs(v resource.PropertyValue, urn resource.URN, urns map[resource.URN]*resource.State) []resource.URN {
+	switch {
+	case v.IsString():
+		ref := resource.URN(v.StringValue())
+		if strings.HasPrefix(string(ref), resource.URNPrefix) &&
+			ref.Stack() == urn.Stack() && ref.Project() == urn.Project() {
+			if _, has := urns[ref]; !has {
+				return []resource.URN{ref}
+			}
+		}
+	case v.IsArray():
+		var refs []resource.URN
+		for _, e := range v.ArrayValue() {
+			refs = append(refs, danglingURNs(e, urn, urns)...)
+		}
+		return refs
+	case v.IsObject():
+		var refs []resource.URN
+		obj := v.ObjectValue()
+		for _, k := range obj.StableKeys() {
+			refs = append(refs, danglingURNs(obj[k], urn, urns)...)
+		}
+		return refs
+	}
 	return nil
 }
@@ -82,15 +82,46 @@ func (src *evalSource) Stack() tokens.QName {
 
 func (src *evalSource) Info() interface{} { return src.runinfo }
 
-// Iterate will spawn an evaluator coroutine and prepare to interact with it on subsequent calls to Next.
+// Iterate will spawn an evaluator coroutine and prepare to interact with it on subsequent calls to Next.  If the
+// target fans out to several instances, the program is evaluated once for each of them, and the events of every
+// evaluation are merged.
 func (src *evalSource) Iterate(ctx context.Context, opts Options, providers ProviderSource) (SourceIterator, error) {
 	contract.Ignore(ctx) // TODO[pulumi/pulumi#1714]
 
+	instances, err := src.runinfo.Target.GetInstances()
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return src.iterate(opts, providers, nil, &hostRefs{count: 1})
+	}
+
+	// The evaluations share the language host, so it must outlive all of them.
+	refs := &hostRefs{count: len(instances)}
+	iters := make([]SourceIterator, 0, len(instances))
+	for i := range instances {
+		iter, err := src.iterate(opts, providers, &instances[i], refs)
+		if err != nil {
+			for _, it := range iters {
+				contract.IgnoreError(it.Close())
+			}
+			return nil, err
+		}
+		iters = append(iters, iter)
+	}
+	return newFanOutIterator(iters), nil
+}
+
+// iterate spawns an evaluation of the program for the given fan-out instance, or for the stack itself if the instance
+// is nil.
+func (src *evalSource) iterate(opts Options, providers ProviderSource, instance *FanOutInstance,
+	refs *hostRefs) (SourceIterator, error) {
+
 	// First, fire up a resource monitor that will watch for and record resource creation.
 	regChan := make(chan *registerResourceEvent)
 	regOutChan := make(chan *registerResourceOutputsEvent)
 	regReadChan := make(chan *readResourceEvent)
-	mon, err := newResourceMonitor(src, providers, regChan, regOutChan, regReadChan)
+	mon, err := newResourceMonitor(src, providers, instance, regChan, regOutChan, regReadChan)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start resource monitor")
 	}
@@ -99,6 +130,8 @@ func (src *evalSource) Iterate(ctx context.Context, opts Options, providers Prov
 	iter := &evalSourceIterator{
 		mon:         mon,
 		src:         src,
+		instance:    instance,
+		refs:        refs,
 		regChan:     regChan,
 		regOutChan:  regOutChan,
 		regReadChan: regReadChan,
@@ -116,6 +149,8 @@ func (src *evalSource) Iterate(ctx context.Context, opts Options, providers Prov
 type evalSourceIterator struct {
 	mon         *resmon                            // the resource monitor, per iterator.
 	src         *evalSource                        // the owning eval source object.
+	instance    *FanOutInstance                    // the fan-out instance being evaluated, if any.
+	refs        *hostRefs                          // the evaluations that share the language host.
 	regChan     chan *registerResourceEvent        // the channel that contains resource registrations.
 	regOutChan  chan *registerResourceOutputsEvent // the channel that contains resource completions.
 	regReadChan chan *readResourceEvent            // the channel that contains read resource requests.
@@ -172,18 +207,22 @@ func (iter *evalSourceIterator) forkRun(opts Options) {
 			rt := iter.src.runinfo.Proj.Runtime.Name()
 			langhost, err := iter.src.plugctx.Host.LanguageRuntime(rt)
 			if err != nil {
+				iter.refs.release(nil)
 				return errors.Wrapf(err, "failed to launch language host %s", rt)
 			}
 			contract.Assertf(langhost != nil, "expected non-nil language host %s", rt)
 
 			// Make sure to clean up before exiting.
-			defer contract.IgnoreClose(langhost)
+			defer iter.refs.release(langhost)
 
-			// Decrypt the configuration.
+			// Decrypt the configuration, and apply the instance's, if any.
 			config, err := iter.src.runinfo.Target.Config.Decrypt(iter.src.runinfo.Target.Decrypter)
 			if err != nil {
 				return err
 			}
+			if iter.instance != nil {
+				config = iter.instance.programConfig(config)
+			}
 
 			// Now run the actual program.
 			var progerr string
@@ -217,6 +256,7 @@ type defaultProviders struct {
 	versions  map[tokens.Package]*semver.Version
 	providers map[tokens.Package]providers.Reference
 	config    plugin.ConfigSource
+	instance  *FanOutInstance
 
 	requests chan defaultProviderRequest
 	regChan  chan<- *registerResourceEvent
@@ -253,10 +293,17 @@ func (d *defaultProviders) newRegisterDefaultProviderEvent(
 		inputs["version"] = resource.NewStringProperty(version.String())
 	}
 
+	// Each fan-out instance has default providers of its own.
+	name := tokens.QName("default")
+	if d.instance != nil {
+		name = resource.InstanceName(d.instance.Name, name)
+	}
+
 	// Create the result channel and the event.
 	done := make(chan *RegisterResult)
 	event := &registerResourceEvent{
-		goal: resource.NewGoal(providers.MakeProviderType(pkg), "default", true, inputs, "", false, nil, "", nil, nil, false),
+		goal: resource.NewGoal(providers.MakeProviderType(pkg), name, true, inputs, "", false, nil, "", nil, nil,
+			false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		done: done,
 	}
 	return event, done, nil
@@ -343,6 +390,7 @@ func (d *defaultProviders) getDefaultProviderRef(pkg tokens.Package) (providers.
 type resmon struct {
 	src              *evalSource                        // the evaluation source.
 	providers        ProviderSource                     // the provider source itself.
+	instance         *FanOutInstance                    // the fan-out instance being evaluated, if any.
 	defaultProviders *defaultProviders                  // the default provider manager.
 	regChan          chan *registerResourceEvent        // the channel to send resource registrations to.
 	regOutChan       chan *registerResourceOutputsEvent // the channel to send resource output registrations to.
@@ -353,17 +401,24 @@ type resmon struct {
 }
 
 // newResourceMonitor creates a new resource monitor RPC server.
-func newResourceMonitor(src *evalSource, provs ProviderSource, regChan chan *registerResourceEvent,
-	regOutChan chan *registerResourceOutputsEvent, regReadChan chan *readResourceEvent) (*resmon, error) {
+func newResourceMonitor(src *evalSource, provs ProviderSource, instance *FanOutInstance,
+	regChan chan *registerResourceEvent, regOutChan chan *registerResourceOutputsEvent,
+	regReadChan chan *readResourceEvent) (*resmon, error) {
 
 	// Create our cancellation channel.
 	cancel := make(chan bool)
 
-	// Create a new default provider manager.
+	// Create a new default provider manager.  The default providers of a fan-out instance are configured by the
+	// instance's configuration as well as the stack's.
+	var config plugin.ConfigSource = src.runinfo.Target
+	if instance != nil {
+		config = &instanceConfigSource{base: config, instance: instance}
+	}
 	d := &defaultProviders{
 		versions:  src.defaultProviderVersions,
 		providers: make(map[tokens.Package]providers.Reference),
-		config:    src.runinfo.Target,
+		config:    config,
+		instance:  instance,
 		requests:  make(chan defaultProviderRequest),
 		regChan:   regChan,
 		cancel:    cancel,
@@ -373,6 +428,7 @@ func newResourceMonitor(src *evalSource, provs ProviderSource, regChan chan *reg
 	resmon := &resmon{
 		src:              src,
 		providers:        provs,
+		instance:         instance,
 		defaultProviders: d,
 		regChan:          regChan,
 		regOutChan:       regOutChan,
@@ -399,6 +455,14 @@ func newResourceMonitor(src *evalSource, provs ProviderSource, regChan chan *reg
 	return resmon, nil
 }
 
+// instanceName qualifies the given resource name by the name of the monitor's fan-out instance, if any.
+func (rm *resmon) instanceName(name tokens.QName) tokens.QName {
+	if rm.instance == nil {
+		return name
+	}
+	return resource.InstanceName(rm.instance.Name, name)
+}
+
 // Address returns the address at which the monitor's RPC server may be reached.
 func (rm *resmon) Address() string {
 	return rm.addr
@@ -457,10 +521,21 @@ func (rm *resmon) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pu
 	label := fmt.Sprintf("ResourceMonitor.Invoke(%s)", tok)
 
 	args, err := plugin.UnmarshalProperties(
-		req.GetArgs(), plugin.MarshalOptions{Label: label, KeepUnknowns: true})
+		req.GetArgs(), plugin.MarshalOptions{Label: label, KeepUnknowns: true,
+			Redaction: rm.src.plugctx.Redaction})
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to unmarshal %v args", tok)
 	}
+	if args, err = args.Resolve(); err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate %v args", tok)
+	}
+
+	// If any of the arguments are unknown, then so is the result. Rather than passing incomplete arguments to the
+	// provider, tell the language host that the result is unknown.
+	if args.ContainsUnknowns() {
+		logging.V(5).Infof("ResourceMonitor.Invoke received unknown args: tok=%v; skipping invoke", tok)
+		return &pulumirpc.InvokeResponse{Unknown: true}, nil
+	}
 
 	// Do the invoke and then return the arguments.
 	logging.V(5).Infof("ResourceMonitor.Invoke received: tok=%v #args=%v", tok, len(args))
@@ -468,7 +543,8 @@ func (rm *resmon) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pu
 	if err != nil {
 		return nil, errors.Wrapf(err, "invocation of %v returned an error", tok)
 	}
-	mret, err := plugin.MarshalProperties(ret, plugin.MarshalOptions{Label: label, KeepUnknowns: true})
+	mret, err := plugin.MarshalProperties(ret, plugin.MarshalOptions{Label: label, KeepUnknowns: true,
+		Redaction: rm.src.plugctx.Redaction})
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to marshal %v return", tok)
 	}
@@ -491,7 +567,7 @@ func (rm *resmon) ReadResource(ctx context.Context,
 		return nil, rpcerror.New(codes.InvalidArgument, err.Error())
 	}
 
-	name := tokens.QName(req.GetName())
+	name := rm.instanceName(tokens.QName(req.GetName()))
 	parent := resource.URN(req.GetParent())
 
 	provider := req.GetProvider()
@@ -511,12 +587,19 @@ func (rm *resmon) ReadResource(ctx context.Context,
 	}
 
 	props, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
-		Label:        label,
-		KeepUnknowns: true,
+		Label:         label,
+		KeepUnknowns:  true,
+		KeepSecrets:   true,
+		Interner:      rm.src.plugctx.Interner,
+		Redaction:     rm.src.plugctx.Redaction,
+		RedactionType: t,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if props, err = props.Resolve(); err != nil {
+		return nil, err
+	}
 
 	event := &readResourceEvent{
 		id:           id,
@@ -546,8 +629,10 @@ func (rm *resmon) ReadResource(ctx context.Context,
 
 	contract.Assert(result != nil)
 	marshaled, err := plugin.MarshalProperties(result.State.Outputs, plugin.MarshalOptions{
-		Label:        label,
-		KeepUnknowns: true,
+		Label:         label,
+		KeepUnknowns:  true,
+		Redaction:     rm.src.plugctx.Redaction,
+		RedactionType: t,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to marshal %s return state", result.State.URN)
@@ -565,11 +650,12 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 
 	// Communicate the type, name, and object information to the iterator that is awaiting us.
 
-	name := tokens.QName(req.GetName())
+	name := rm.instanceName(tokens.QName(req.GetName()))
 	custom := req.GetCustom()
 	parent := resource.URN(req.GetParent())
 	protect := req.GetProtect()
 	deleteBeforeReplace := req.GetDeleteBeforeReplace()
+	retainOnDelete := req.GetRetainOnDelete()
 	var t tokens.Type
 
 	// Custom resources must have a three-part type so that we can 1) identify if they are providers and 2) retrieve the
@@ -600,13 +686,79 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 		dependencies = append(dependencies, resource.URN(dependingURN))
 	}
 
+	// Provider resources are referenced by URN from the state of the resources they manage, so they may not be
+	// aliased.
+	var aliases []resource.URN
+	if len(req.GetAliases()) > 0 && providers.IsProviderType(t) {
+		return nil, rpcerror.New(codes.InvalidArgument, "provider resources may not be aliased")
+	}
+	for _, aliasURN := range req.GetAliases() {
+		aliases = append(aliases, resource.URN(aliasURN))
+	}
+
+	// Ensure that each of the paths whose changes should be ignored or should force a replacement is well-formed.
+	ignoreChanges, replaceOnChanges := req.GetIgnoreChanges(), req.GetReplaceOnChanges()
+	for _, paths := range [][]string{ignoreChanges, replaceOnChanges} {
+		for _, path := range paths {
+			if _, err := resource.ParsePropertyPath(path); err != nil {
+				return nil, rpcerror.New(codes.InvalidArgument, err.Error())
+			}
+		}
+	}
+
+	// Likewise, ensure that each of the resource's ready conditions is well-formed.
+	readyConditions := req.GetReadyConditions()
+	if len(readyConditions) > 0 && !custom {
+		return nil, rpcerror.New(codes.InvalidArgument, "only custom resources may have ready conditions")
+	}
+	for _, cond := range readyConditions {
+		if _, err := ParseReadyCondition(cond); err != nil {
+			return nil, rpcerror.New(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// Parse the resource's custom timeouts, if any.
+	var customTimeouts resource.CustomTimeouts
+	if ct := req.GetCustomTimeouts(); ct != nil {
+		parsed, err := resource.ParseCustomTimeouts(ct.GetCreate(), ct.GetUpdate(), ct.GetDelete())
+		if err != nil {
+			return nil, rpcerror.New(codes.InvalidArgument, err.Error())
+		}
+		customTimeouts = parsed
+	}
+
+	// Parse the resource's hooks, if any.  Because a deleted resource is no longer registered, its hooks cannot run
+	// when it is deleted.
+	var hooks []Hook
+	for _, h := range req.GetHooks() {
+		hook := Hook{Name: h.GetName(), Command: h.GetCommand(), Advisory: h.GetAdvisory(), Timeout: h.GetTimeout()}
+		for _, e := range h.GetEvents() {
+			event := HookEvent(e)
+			if event == BeforeDelete || event == AfterDelete {
+				return nil, rpcerror.New(codes.InvalidArgument,
+					fmt.Sprintf("hook '%v' may not run at %v; configure it for the stack instead", hook.name(), e))
+			}
+			hook.Events = append(hook.Events, event)
+		}
+		if err := hook.validate(); err != nil {
+			return nil, rpcerror.New(codes.InvalidArgument, err.Error())
+		}
+		hooks = append(hooks, hook)
+	}
+
 	props, err := plugin.UnmarshalProperties(
-		req.GetObject(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true})
+		req.GetObject(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true,
+			KeepSecrets: true, Interner: rm.src.plugctx.Interner, Redaction: rm.src.plugctx.Redaction,
+			RedactionType: t})
 	if err != nil {
 		return nil, err
 	}
+	if props, err = props.Resolve(); err != nil {
+		return nil, err
+	}
 
 	propertyDependencies := make(map[resource.PropertyKey][]resource.URN)
+	var propertyReads resource.PropertyReads
 	if len(req.GetPropertyDependencies()) == 0 {
 		// If this request did not specify property dependencies, treat each property as depending on every resource
 		// in the request's dependency list.
@@ -620,20 +772,41 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 			for _, d := range pd.Urns {
 				deps = append(deps, resource.URN(d))
 			}
-			propertyDependencies[resource.PropertyKey(pk)] = deps
+			key := resource.PropertyKey(pk)
+			propertyDependencies[key] = deps
+
+			// If the language host recorded which outputs of these dependencies the property read, keep those too.
+			for _, read := range pd.GetReads() {
+				for _, p := range read.GetPaths() {
+					if _, err := resource.ParsePropertyPath(p); err != nil {
+						return nil, rpcerror.New(codes.InvalidArgument, err.Error())
+					}
+				}
+				if propertyReads == nil {
+					propertyReads = make(resource.PropertyReads)
+				}
+				if propertyReads[key] == nil {
+					propertyReads[key] = make(map[resource.URN][]string)
+				}
+				propertyReads[key][resource.URN(read.GetUrn())] = read.GetPaths()
+			}
 		}
 	}
 
 	logging.V(5).Infof(
 		"ResourceMonitor.RegisterResource received: t=%v, name=%v, custom=%v, #props=%v, parent=%v, protect=%v, "+
-			"provider=%v, deps=%v, deleteBeforeReplace=%v",
-		t, name, custom, len(props), parent, protect, provider, dependencies, deleteBeforeReplace)
+			"provider=%v, deps=%v, deleteBeforeReplace=%v, retainOnDelete=%v, aliases=%v, ignoreChanges=%v, "+
+			"replaceOnChanges=%v, readyConditions=%v, customTimeouts=%v",
+		t, name, custom, len(props), parent, protect, provider, dependencies, deleteBeforeReplace, retainOnDelete,
+		aliases, ignoreChanges, replaceOnChanges, readyConditions, customTimeouts)
 
 	// Send the goal state to the engine.
 	step := &registerResourceEvent{
 		goal: resource.NewGoal(t, name, custom, props, parent, protect, dependencies, provider, nil,
-			propertyDependencies, deleteBeforeReplace),
-		done: make(chan *RegisterResult),
+			propertyDependencies, deleteBeforeReplace, retainOnDelete, aliases, ignoreChanges, replaceOnChanges,
+			readyConditions, customTimeouts, propertyReads),
+		hooks: hooks,
+		done:  make(chan *RegisterResult),
 	}
 
 	select {
@@ -665,7 +838,8 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 
 	// Finally, unpack the response into properties that we can return to the language runtime.  This mostly includes
 	// an ID, URN, and defaults and output properties that will all be blitted back onto the runtime object.
-	obj, err := plugin.MarshalProperties(props, plugin.MarshalOptions{Label: label, KeepUnknowns: true})
+	obj, err := plugin.MarshalProperties(props, plugin.MarshalOptions{Label: label, KeepUnknowns: true,
+		Redaction: rm.src.plugctx.Redaction, RedactionType: state.Type})
 	if err != nil {
 		return nil, err
 	}
@@ -690,10 +864,14 @@ func (rm *resmon) RegisterResourceOutputs(ctx context.Context,
 	}
 	label := fmt.Sprintf("ResourceMonitor.RegisterResourceOutputs(%s)", urn)
 	outs, err := plugin.UnmarshalProperties(
-		req.GetOutputs(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true})
+		req.GetOutputs(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true,
+			Interner: rm.src.plugctx.Interner, Redaction: rm.src.plugctx.Redaction, RedactionType: urn.Type()})
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot unmarshal output properties")
 	}
+	if outs, err = outs.Resolve(); err != nil {
+		return nil, errors.Wrapf(err, "cannot evaluate output properties")
+	}
 	logging.V(5).Infof("ResourceMonitor.RegisterResourceOutputs received: urn=%v, #outs=%v", urn, len(outs))
 
 	// Now send the step over to the engine to perform.
@@ -724,8 +902,9 @@ func (rm *resmon) RegisterResourceOutputs(ctx context.Context,
 }
 
 type registerResourceEvent struct {
-	goal *resource.Goal       // the resource goal state produced by the iterator.
-	done chan *RegisterResult // the channel to communicate with after the resource state is available.
+	goal  *resource.Goal       // the resource goal state produced by the iterator.
+	hooks []Hook               // the hooks that the program registered for the resource.
+	done  chan *RegisterResult // the channel to communicate with after the resource state is available.
 }
 
 var _ RegisterResourceEvent = (*registerResourceEvent)(nil)
@@ -55,13 +55,15 @@ func fixedProgram(steps []RegisterResourceEvent) deploytest.ProgramFunc {
 		for _, s := range steps {
 			g := s.Goal()
 			urn, id, outs, err := resmon.RegisterResource(g.Type, string(g.Name), g.Custom, g.Parent, g.Protect,
-				g.Dependencies, g.Provider, g.Properties, g.PropertyDependencies, false)
+				g.Dependencies, g.Provider, g.Properties, g.PropertyDependencies, false, false, g.Aliases,
+				g.IgnoreChanges, g.ReplaceOnChanges, nil, nil, g.PropertyReads)
 			if err != nil {
 				return err
 			}
 			s.Done(&RegisterResult{
 				State: resource.NewState(g.Type, urn, g.Custom, false, id, g.Properties, outs, g.Parent, g.Protect,
-					false, g.Dependencies, nil, g.Provider, g.PropertyDependencies, false),
+					false, g.Dependencies, nil, g.Provider, g.PropertyDependencies, false, false, nil, resource.CustomTimeouts{},
+					nil, nil),
 			})
 		}
 		return nil
@@ -143,16 +145,16 @@ func TestRegisterNoDefaultProviders(t *testing.T) {
 		// Register a component resource.
 		&testRegEvent{
 			goal: resource.NewGoal(componentURN.Type(), componentURN.Name(), false, resource.PropertyMap{}, "", false,
-				nil, "", []string{}, nil, false),
+				nil, "", []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		// Register a couple resources using provider A.
 		&testRegEvent{
 			goal: resource.NewGoal("pkgA:index:typA", "res1", true, resource.PropertyMap{}, componentURN, false, nil,
-				providerARef.String(), []string{}, nil, false),
+				providerARef.String(), []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		&testRegEvent{
 			goal: resource.NewGoal("pkgA:index:typA", "res2", true, resource.PropertyMap{}, componentURN, false, nil,
-				providerARef.String(), []string{}, nil, false),
+				providerARef.String(), []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		// Register two more providers.
 		newProviderEvent("pkgA", "providerB", nil, ""),
@@ -160,11 +162,11 @@ func TestRegisterNoDefaultProviders(t *testing.T) {
 		// Register a few resources that use the new providers.
 		&testRegEvent{
 			goal: resource.NewGoal("pkgB:index:typB", "res3", true, resource.PropertyMap{}, "", false, nil,
-				providerBRef.String(), []string{}, nil, false),
+				providerBRef.String(), []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		&testRegEvent{
 			goal: resource.NewGoal("pkgB:index:typC", "res4", true, resource.PropertyMap{}, "", false, nil,
-				providerCRef.String(), []string{}, nil, false),
+				providerCRef.String(), []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 	}
 
@@ -198,7 +200,7 @@ func TestRegisterNoDefaultProviders(t *testing.T) {
 		reg.Done(&RegisterResult{
 			State: resource.NewState(goal.Type, urn, goal.Custom, false, id, goal.Properties, resource.PropertyMap{},
 				goal.Parent, goal.Protect, false, goal.Dependencies, nil, goal.Provider, goal.PropertyDependencies,
-				false),
+				false, false, nil, resource.CustomTimeouts{}, nil, nil),
 		})
 
 		processed++
@@ -227,25 +229,25 @@ func TestRegisterDefaultProviders(t *testing.T) {
 		// Register a component resource.
 		&testRegEvent{
 			goal: resource.NewGoal(componentURN.Type(), componentURN.Name(), false, resource.PropertyMap{}, "", false,
-				nil, "", []string{}, nil, false),
+				nil, "", []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		// Register a couple resources from package A.
 		&testRegEvent{
 			goal: resource.NewGoal("pkgA:m:typA", "res1", true, resource.PropertyMap{},
-				componentURN, false, nil, "", []string{}, nil, false),
+				componentURN, false, nil, "", []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		&testRegEvent{
 			goal: resource.NewGoal("pkgA:m:typA", "res2", true, resource.PropertyMap{},
-				componentURN, false, nil, "", []string{}, nil, false),
+				componentURN, false, nil, "", []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		// Register a few resources from other packages.
 		&testRegEvent{
 			goal: resource.NewGoal("pkgB:m:typB", "res3", true, resource.PropertyMap{}, "", false,
-				nil, "", []string{}, nil, false),
+				nil, "", []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 		&testRegEvent{
 			goal: resource.NewGoal("pkgB:m:typC", "res4", true, resource.PropertyMap{}, "", false,
-				nil, "", []string{}, nil, false),
+				nil, "", []string{}, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil),
 		},
 	}
 
@@ -290,7 +292,7 @@ func TestRegisterDefaultProviders(t *testing.T) {
 		reg.Done(&RegisterResult{
 			State: resource.NewState(goal.Type, urn, goal.Custom, false, id, goal.Properties, resource.PropertyMap{},
 				goal.Parent, goal.Protect, false, goal.Dependencies, nil, goal.Provider, goal.PropertyDependencies,
-				false),
+				false, false, nil, resource.CustomTimeouts{}, nil, nil),
 		})
 
 		processed++
@@ -380,7 +382,7 @@ func TestReadInvokeNoDefaultProviders(t *testing.T) {
 		read.Done(&ReadResult{
 			State: resource.NewState(read.Type(), urn, true, false, read.ID(), read.Properties(),
 				resource.PropertyMap{}, read.Parent(), false, false, read.Dependencies(), nil, read.Provider(), nil,
-				false),
+				false, false, nil, resource.CustomTimeouts{}, nil, nil),
 		})
 		reads++
 	}
@@ -465,7 +467,7 @@ func TestReadInvokeDefaultProviders(t *testing.T) {
 			e.Done(&RegisterResult{
 				State: resource.NewState(goal.Type, urn, goal.Custom, false, id, goal.Properties, resource.PropertyMap{},
 					goal.Parent, goal.Protect, false, goal.Dependencies, nil, goal.Provider, goal.PropertyDependencies,
-					false),
+					false, false, nil, resource.CustomTimeouts{}, nil, nil),
 			})
 			registers++
 
@@ -473,7 +475,8 @@ func TestReadInvokeDefaultProviders(t *testing.T) {
 			urn := newURN(e.Type(), string(e.Name()), e.Parent())
 			e.Done(&ReadResult{
 				State: resource.NewState(e.Type(), urn, true, false, e.ID(), e.Properties(),
-					resource.PropertyMap{}, e.Parent(), false, false, e.Dependencies(), nil, e.Provider(), nil, false),
+					resource.PropertyMap{}, e.Parent(), false, false, e.Dependencies(), nil, e.Provider(), nil, false, false,
+					nil, resource.CustomTimeouts{}, nil, nil),
 			})
 			reads++
 		}
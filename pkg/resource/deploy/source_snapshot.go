@@ -0,0 +1,324 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// NewSnapshotSource returns a planning source that registers each resource in the given snapshot with the inputs
+// recorded in the snapshot, as if a program had registered them.  Planning against this source converges a stack's
+// resources back to the state captured by the snapshot: resources that have since been deleted are re-created,
+// resources that have since been added are deleted, and resources whose inputs have since changed are updated (or
+// replaced) to use their old inputs.
+func NewSnapshotSource(project tokens.PackageName, snap *Snapshot) Source {
+	return &snapshotSource{project: project, snap: snap}
+}
+
+// A snapshotSource registers the resources recorded in a snapshot.
+type snapshotSource struct {
+	project tokens.PackageName
+	snap    *Snapshot
+}
+
+func (src *snapshotSource) Close() error                { return nil }
+func (src *snapshotSource) Project() tokens.PackageName { return src.project }
+func (src *snapshotSource) Info() interface{}           { return nil }
+
+func (src *snapshotSource) Iterate(ctx context.Context, opts Options,
+	providers ProviderSource) (SourceIterator, error) {
+
+	var resources []*resource.State
+	if src.snap != nil {
+		resources = src.snap.Resources
+	}
+
+	// Record which resources are depended upon by others: their registrations must complete before their dependents
+	// are produced, so that the dependents' inputs can be checked against their new IDs and outputs.
+	dependents := make(map[resource.URN]bool)
+	for _, res := range resources {
+		if res.Delete {
+			continue
+		}
+		for _, dep := range res.Dependencies {
+			dependents[dep] = true
+		}
+		for _, deps := range res.PropertyDependencies {
+			for _, dep := range deps {
+				dependents[dep] = true
+			}
+		}
+	}
+
+	return &snapshotSourceIterator{
+		ctx:        ctx,
+		resources:  resources,
+		refs:       make(map[string]string),
+		dependents: dependents,
+		changed:    make(map[resource.URN]changedResource),
+	}, nil
+}
+
+// snapshotSourceIterator produces an event for each live resource in a snapshot, in snapshot order.  Because the
+// snapshot is in dependency order, every resource is registered after the resources it depends upon.
+type snapshotSourceIterator struct {
+	ctx        context.Context
+	resources  []*resource.State
+	current    int
+	refs       map[string]string                // a map from the snapshot's provider references to the new ones.
+	dependents map[resource.URN]bool            // the resources upon which other resources depend.
+	changed    map[resource.URN]changedResource // the resources whose IDs or outputs differ from the snapshot's.
+
+	// the last registration or read, if its result must be observed before the next resource is produced.
+	waiting     *registerResourceEvent
+	waitingRead *readResourceEvent
+	waitingRes  *resource.State
+}
+
+// changedResource pairs a resource's state in the snapshot with the state that it was registered with.
+type changedResource struct {
+	old *resource.State
+	new *resource.State
+}
+
+func (iter *snapshotSourceIterator) Close() error {
+	return nil // nothing to do.
+}
+
+func (iter *snapshotSourceIterator) Next() (SourceEvent, error) {
+	// If the last resource was a provider, a component with outputs, or a dependency of some later resource, wait for
+	// it to complete first.  Providers must be registered before they can be referenced, a component's outputs may
+	// only be registered once the component itself has been registered, and dependents must see their dependencies'
+	// new IDs and outputs.
+	if iter.waitingRes != nil {
+		res := iter.waitingRes
+		state, err := iter.wait()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case providers.IsProviderType(res.Type):
+			oldRef, err := providers.NewReference(res.URN, res.ID)
+			if err != nil {
+				return nil, err
+			}
+			newRef, err := providers.NewReference(state.URN, state.ID)
+			if err != nil {
+				return nil, err
+			}
+			iter.refs[oldRef.String()] = newRef.String()
+			logging.V(7).Infof("snapshotSource: provider %v registered as %v", oldRef, newRef)
+		case res.Custom:
+			if state.ID != res.ID || !state.Outputs.DeepEquals(res.Outputs) {
+				logging.V(7).Infof("snapshotSource: resource %v changed since the snapshot was taken", res.URN)
+				iter.changed[res.URN] = changedResource{old: res, new: state}
+			}
+		case len(res.Outputs) > 0:
+			return &registerResourceOutputsEvent{
+				urn:     state.URN,
+				outputs: res.Outputs,
+				done:    make(chan bool, 1),
+			}, nil
+		}
+	}
+
+	for iter.current < len(iter.resources) {
+		res := iter.resources[iter.current]
+		iter.current++
+
+		// Resources that were pending deletion when the snapshot was taken are not part of its desired state.
+		if res.Delete {
+			continue
+		}
+
+		inputs, err := iter.resolveInputs(res)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.External {
+			event := &readResourceEvent{
+				id:           res.ID,
+				name:         res.URN.Name(),
+				baseType:     res.Type,
+				provider:     iter.providerRef(res.Provider),
+				parent:       res.Parent,
+				props:        inputs,
+				dependencies: res.Dependencies,
+				done:         make(chan *ReadResult, 1),
+			}
+			if iter.dependents[res.URN] {
+				iter.waitingRead, iter.waitingRes = event, res
+			}
+			return event, nil
+		}
+
+		event := &registerResourceEvent{
+			goal: resource.NewGoal(res.Type, res.URN.Name(), res.Custom, inputs, res.Parent, res.Protect,
+				res.Dependencies, iter.providerRef(res.Provider), nil, res.PropertyDependencies, false,
+				res.RetainOnDelete, nil, nil, nil, nil, res.CustomTimeouts, res.PropertyReads),
+			done: make(chan *RegisterResult, 1),
+		}
+		if providers.IsProviderType(res.Type) || iter.dependents[res.URN] || !res.Custom && len(res.Outputs) > 0 {
+			iter.waiting, iter.waitingRes = event, res
+		}
+		return event, nil
+	}
+
+	return nil, nil // means "done"
+}
+
+// wait waits for the result of the registration or read that the iterator is waiting on and returns its state.
+func (iter *snapshotSourceIterator) wait() (*resource.State, error) {
+	res, register, read := iter.waitingRes, iter.waiting, iter.waitingRead
+	iter.waiting, iter.waitingRead, iter.waitingRes = nil, nil, nil
+
+	var state *resource.State
+	if register != nil {
+		select {
+		case result := <-register.done:
+			if result != nil {
+				state = result.State
+			}
+		case <-iter.ctx.Done():
+			return nil, iter.ctx.Err()
+		}
+	} else {
+		select {
+		case result := <-read.done:
+			if result != nil {
+				state = result.State
+			}
+		case <-iter.ctx.Done():
+			return nil, iter.ctx.Err()
+		}
+	}
+	if state == nil {
+		return nil, errors.Errorf("failed to register resource '%v'", res.URN)
+	}
+	return state, nil
+}
+
+// resolveInputs returns the inputs with which to register the given resource.  The snapshot recorded these inputs
+// when the resource's dependencies had the IDs and outputs that the snapshot also recorded; if a dependency has since
+// been registered with a different ID or outputs (e.g. because it had to be replaced), each input that was computed
+// from the dependency is rewritten to use its new values instead.  If an input was computed from a dependency that
+// changed but cannot be rewritten, the resource cannot be restored and an error is returned.
+func (iter *snapshotSourceIterator) resolveInputs(res *resource.State) (resource.PropertyMap, error) {
+	if len(iter.changed) == 0 {
+		return res.Inputs, nil
+	}
+
+	inputs := res.Inputs.Copy()
+	for _, k := range res.Inputs.StableKeys() {
+		// If the snapshot did not record the dependencies of each property, assume that each depends on every one of
+		// the resource's dependencies.
+		deps, has := res.PropertyDependencies[k]
+		if !has && len(res.PropertyDependencies) == 0 {
+			deps = res.Dependencies
+		}
+
+		v := res.Inputs[k]
+		for _, dep := range deps {
+			change, has := iter.changed[dep]
+			if !has {
+				continue
+			}
+			resolved, ok := change.resolve(v)
+			if !ok {
+				return nil, errors.Errorf("cannot restore '%v': its input '%v' was computed from '%v', which has "+
+					"changed since the snapshot was taken", res.URN, k, dep)
+			}
+			v = resolved
+		}
+		inputs[k] = v
+	}
+	return inputs, nil
+}
+
+// resolve rewrites any references to the old resource's ID or outputs in the given value to refer to the new
+// resource's.  Strings that match the old ID or an old string output are rewritten; if a string merely contains one
+// of them, it cannot be rewritten and resolve returns false.
+func (c changedResource) resolve(v resource.PropertyValue) (resource.PropertyValue, bool) {
+	switch {
+	case v.IsString():
+		s := v.StringValue()
+		if s == "" {
+			return v, true
+		}
+		if s == string(c.old.ID) {
+			if c.new.ID == "" {
+				return resource.MakeComputed(resource.NewStringProperty("")), true
+			}
+			return resource.NewStringProperty(string(c.new.ID)), true
+		}
+		for _, k := range c.old.Outputs.StableKeys() {
+			if old := c.old.Outputs[k]; old.IsString() && old.StringValue() == s {
+				if nv, has := c.new.Outputs[k]; has {
+					return nv, true
+				}
+				return resource.MakeComputed(resource.NewStringProperty("")), true
+			}
+		}
+		if c.old.ID != "" && strings.Contains(s, string(c.old.ID)) {
+			return v, false
+		}
+		for _, old := range c.old.Outputs {
+			if old.IsString() && old.StringValue() != "" && strings.Contains(s, old.StringValue()) {
+				return v, false
+			}
+		}
+		return v, true
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			resolved, ok := c.resolve(e)
+			if !ok {
+				return v, false
+			}
+			arr[i] = resolved
+		}
+		return resource.NewArrayProperty(arr), true
+	case v.IsObject():
+		obj := make(resource.PropertyMap)
+		for k, e := range v.ObjectValue() {
+			resolved, ok := c.resolve(e)
+			if !ok {
+				return v, false
+			}
+			obj[k] = resolved
+		}
+		return resource.NewObjectProperty(obj), true
+	default:
+		return v, true
+	}
+}
+
+// providerRef returns the current reference for the provider with the given reference in the snapshot.
+func (iter *snapshotSourceIterator) providerRef(ref string) string {
+	if newRef, has := iter.refs[ref]; has {
+		return newRef
+	}
+	return ref
+}
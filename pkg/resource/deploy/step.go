@@ -15,14 +15,21 @@
 package deploy
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 
+	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
 )
 
 // StepCompleteFunc is the type of functions returned from Step.Apply. These functions are to be called
@@ -33,11 +40,11 @@ type StepCompleteFunc func()
 type Step interface {
 	// Apply applies or previews this step. It returns the status of the resource after the step application,
 	// a function to call to signal that this step has fully completed, and an error, if one occurred while applying
-	// the step.
+	// the step. The provider operations that the step performs are traced as children of the span in ctx, if any.
 	//
 	// The returned StepCompleteFunc, if not nil, must be called after committing the results of this step into
 	// the state of the deployment.
-	Apply(preview bool) (resource.Status, StepCompleteFunc, error) // applies or previews this step.
+	Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) // applies or previews this step.
 
 	Op() StepOp           // the operation performed by this step.
 	URN() resource.URN    // the resource URN (for before and after).
@@ -80,32 +87,34 @@ func NewSameStep(plan *Plan, reg RegisterResourceEvent, old *resource.State, new
 
 func (s *SameStep) Op() StepOp           { return OpSame }
 func (s *SameStep) Plan() *Plan          { return s.plan }
-func (s *SameStep) Type() tokens.Type    { return s.old.Type }
+func (s *SameStep) Type() tokens.Type    { return s.new.Type }
 func (s *SameStep) Provider() string     { return s.old.Provider }
-func (s *SameStep) URN() resource.URN    { return s.old.URN }
+func (s *SameStep) URN() resource.URN    { return s.new.URN }
 func (s *SameStep) Old() *resource.State { return s.old }
 func (s *SameStep) New() *resource.State { return s.new }
 func (s *SameStep) Res() *resource.State { return s.new }
 func (s *SameStep) Logical() bool        { return true }
 
-func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
-	// Retain the URN, ID, and outputs:
-	s.new.URN = s.old.URN
+func (s *SameStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
+	// Retain the ID and outputs.  The URN is not retained, as the resource may have been renamed via an alias.
 	s.new.ID = s.old.ID
 	s.new.Outputs = s.old.Outputs
+	s.new.Provenance = s.old.Provenance
 	complete := func() { s.reg.Done(&RegisterResult{State: s.new, Stable: true}) }
 	return resource.StatusOK, complete, nil
 }
 
 // CreateStep is a mutating step that creates an entirely new resource.
 type CreateStep struct {
-	plan          *Plan                  // the current plan.
-	reg           RegisterResourceEvent  // the registration intent to convey a URN back to.
-	old           *resource.State        // the state of the existing resource (only for replacements).
-	new           *resource.State        // the state of the resource after this step.
-	keys          []resource.PropertyKey // the keys causing replacement (only for replacements).
-	replacing     bool                   // true if this is a create due to a replacement.
-	pendingDelete bool                   // true if this replacement should create a pending delete.
+	plan          *Plan                          // the current plan.
+	reg           RegisterResourceEvent          // the registration intent to convey a URN back to.
+	old           *resource.State                // the state of the existing resource (only for replacements).
+	new           *resource.State                // the state of the resource after this step.
+	keys          []resource.PropertyKey         // the keys causing replacement (only for replacements).
+	diffs         []resource.PropertyKey         // the keys causing a diff (only for replacements).
+	detailedDiff  map[string]plugin.PropertyDiff // the structured property diff (only for replacements).
+	replacing     bool                           // true if this is a create due to a replacement.
+	pendingDelete bool                           // true if this replacement should create a pending delete.
 }
 
 var _ Step = (*CreateStep)(nil)
@@ -125,8 +134,8 @@ func NewCreateStep(plan *Plan, reg RegisterResourceEvent, new *resource.State) S
 	}
 }
 
-func NewCreateReplacementStep(plan *Plan, reg RegisterResourceEvent,
-	old *resource.State, new *resource.State, keys []resource.PropertyKey, pendingDelete bool) Step {
+func NewCreateReplacementStep(plan *Plan, reg RegisterResourceEvent, old *resource.State, new *resource.State,
+	keys, diffs []resource.PropertyKey, detailedDiff map[string]plugin.PropertyDiff, pendingDelete bool) Step {
 	contract.Assert(reg != nil)
 	contract.Assert(old != nil)
 	contract.Assert(old.URN != "")
@@ -137,7 +146,7 @@ func NewCreateReplacementStep(plan *Plan, reg RegisterResourceEvent,
 	contract.Assert(new.ID == "")
 	contract.Assert(!new.Custom || new.Provider != "" || providers.IsProviderType(new.Type))
 	contract.Assert(!new.Delete)
-	contract.Assert(old.Type == new.Type)
+	contract.Assert(old.Type == new.Type || old.URN != new.URN)
 	contract.Assert(!new.External)
 	return &CreateStep{
 		plan:          plan,
@@ -145,6 +154,8 @@ func NewCreateReplacementStep(plan *Plan, reg RegisterResourceEvent,
 		old:           old,
 		new:           new,
 		keys:          keys,
+		diffs:         diffs,
+		detailedDiff:  detailedDiff,
 		replacing:     true,
 		pendingDelete: pendingDelete,
 	}
@@ -156,27 +167,40 @@ func (s *CreateStep) Op() StepOp {
 	}
 	return OpCreate
 }
-func (s *CreateStep) Plan() *Plan                  { return s.plan }
-func (s *CreateStep) Type() tokens.Type            { return s.new.Type }
-func (s *CreateStep) Provider() string             { return s.new.Provider }
-func (s *CreateStep) URN() resource.URN            { return s.new.URN }
-func (s *CreateStep) Old() *resource.State         { return s.old }
-func (s *CreateStep) New() *resource.State         { return s.new }
-func (s *CreateStep) Res() *resource.State         { return s.new }
-func (s *CreateStep) Keys() []resource.PropertyKey { return s.keys }
-func (s *CreateStep) Logical() bool                { return !s.replacing }
-
-func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *CreateStep) Plan() *Plan                                  { return s.plan }
+func (s *CreateStep) Type() tokens.Type                            { return s.new.Type }
+func (s *CreateStep) Provider() string                             { return s.new.Provider }
+func (s *CreateStep) URN() resource.URN                            { return s.new.URN }
+func (s *CreateStep) Old() *resource.State                         { return s.old }
+func (s *CreateStep) New() *resource.State                         { return s.new }
+func (s *CreateStep) Res() *resource.State                         { return s.new }
+func (s *CreateStep) Keys() []resource.PropertyKey                 { return s.keys }
+func (s *CreateStep) Diffs() []resource.PropertyKey                { return s.diffs }
+func (s *CreateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+func (s *CreateStep) Logical() bool                                { return !s.replacing }
+
+func (s *CreateStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	var resourceError error
 	resourceStatus := resource.StatusOK
 	if !preview {
+		if err := s.plan.runHooks(s.reg, BeforeCreate, s.URN(), s.new.Inputs); err != nil {
+			return resource.StatusOK, nil, err
+		}
+
 		if s.new.Custom {
 			// Invoke the Create RPC function for this provider:
-			prov, err := getProvider(s)
+			prov, err := getProvider(ctx, s)
 			if err != nil {
 				return resource.StatusOK, nil, err
 			}
-			id, outs, rst, err := prov.Create(s.URN(), s.new.Inputs)
+			var id resource.ID
+			var outs resource.PropertyMap
+			rst, err := withTimeout(s.new, resource.OperationTypeCreating, func() (resource.Status, error) {
+				var rst resource.Status
+				var err error
+				id, outs, rst, err = prov.Create(s.URN(), s.new.Inputs, s.new.CustomTimeouts.Create)
+				return rst, err
+			})
 			if err != nil {
 				if rst != resource.StatusPartialFailure {
 					return rst, nil, err
@@ -194,8 +218,19 @@ func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 
 			// Copy any of the default and output properties on the live object state.
 			s.new.ID = id
-			s.new.Outputs = outs
+			s.new.Outputs = resource.RestoreFileRefs(s.new.Inputs, outs)
+
+			if resourceError == nil {
+				resourceStatus, resourceError = awaitReady(s.plan, s.reg, s.new, prov)
+			}
+			recordProvenance(s.Op(), nil, s.new)
+		}
+
+		if resourceError == nil {
+			resourceStatus, resourceError = runAfterHooks(s.plan, s.reg, AfterCreate, s.new)
 		}
+	} else if s.new.ID == "" {
+		s.new.ID = s.plan.placeholderID(s.new)
 	}
 
 	// Mark the old resource as pending deletion if necessary.
@@ -280,25 +315,36 @@ func (s *DeleteStep) New() *resource.State { return nil }
 func (s *DeleteStep) Res() *resource.State { return s.old }
 func (s *DeleteStep) Logical() bool        { return !s.replacing }
 
-func (s *DeleteStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
-	// Refuse to delete protected resources.
-	if s.old.Protect {
-		return resource.StatusOK, nil,
-			errors.Errorf("refusing to delete protected resource '%s'", s.old.URN)
-	}
+func (s *DeleteStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
+	// Deleting an External resource is a no-op, since Pulumi does not own the lifecycle. Likewise, deleting a resource
+	// that is retained on delete only removes it from the snapshot. Note that the step generator has already refused
+	// to delete protected resources unless the plan was asked to ignore protection.
+	if !preview && !s.old.External && !s.old.RetainOnDelete {
+		if err := s.plan.runHooks(nil, BeforeDelete, s.URN(), s.old.All()); err != nil {
+			return resource.StatusOK, nil, err
+		}
 
-	// Deleting an External resource is a no-op, since Pulumi does not own the lifecycle.
-	if !preview && !s.old.External {
 		if s.old.Custom {
 			// Invoke the Delete RPC function for this provider:
-			prov, err := getProvider(s)
+			prov, err := getProvider(ctx, s)
 			if err != nil {
 				return resource.StatusOK, nil, err
 			}
-			if rst, err := prov.Delete(s.URN(), s.old.ID, s.old.All()); err != nil {
+			rst, err := withTimeout(s.old, resource.OperationTypeDeleting, func() (resource.Status, error) {
+				return prov.Delete(s.URN(), s.old.ID, s.old.All(), s.old.CustomTimeouts.Delete)
+			})
+			if plugin.IsNotFound(err) {
+				// A resource that no longer exists has nothing left to delete.
+				s.plan.Diag().Warningf(diag.GetResourceAlreadyDeletedWarning(s.URN()), s.URN(), err)
+			} else if err != nil {
 				return rst, nil, err
 			}
 		}
+
+		// The resource is gone, so it is removed from the snapshot even if an after-delete hook fails.
+		if err := s.plan.runHooks(nil, AfterDelete, s.URN(), s.old.All()); err != nil {
+			return resource.StatusOK, func() {}, &stepCompletedError{err: err}
+		}
 	}
 
 	return resource.StatusOK, func() {}, nil
@@ -330,23 +376,25 @@ func (s *RemovePendingReplaceStep) New() *resource.State { return nil }
 func (s *RemovePendingReplaceStep) Res() *resource.State { return s.old }
 func (s *RemovePendingReplaceStep) Logical() bool        { return false }
 
-func (s *RemovePendingReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *RemovePendingReplaceStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	return resource.StatusOK, nil, nil
 }
 
 // UpdateStep is a mutating step that updates an existing resource's state.
 type UpdateStep struct {
-	plan    *Plan                  // the current plan.
-	reg     RegisterResourceEvent  // the registration intent to convey a URN back to.
-	old     *resource.State        // the state of the existing resource.
-	new     *resource.State        // the newly computed state of the resource after updating.
-	stables []resource.PropertyKey // an optional list of properties that won't change during this update.
+	plan         *Plan                          // the current plan.
+	reg          RegisterResourceEvent          // the registration intent to convey a URN back to.
+	old          *resource.State                // the state of the existing resource.
+	new          *resource.State                // the newly computed state of the resource after updating.
+	stables      []resource.PropertyKey         // an optional list of properties that won't change during this update.
+	diffs        []resource.PropertyKey         // the keys causing a diff.
+	detailedDiff map[string]plugin.PropertyDiff // the structured diff.
 }
 
 var _ Step = (*UpdateStep)(nil)
 
-func NewUpdateStep(plan *Plan, reg RegisterResourceEvent, old *resource.State,
-	new *resource.State, stables []resource.PropertyKey) Step {
+func NewUpdateStep(plan *Plan, reg RegisterResourceEvent, old *resource.State, new *resource.State,
+	stables, diffs []resource.PropertyKey, detailedDiff map[string]plugin.PropertyDiff) Step {
 	contract.Assert(old != nil)
 	contract.Assert(old.URN != "")
 	contract.Assert(old.ID != "" || !old.Custom)
@@ -356,45 +404,60 @@ func NewUpdateStep(plan *Plan, reg RegisterResourceEvent, old *resource.State,
 	contract.Assert(new.URN != "")
 	contract.Assert(new.ID == "")
 	contract.Assert(!new.Delete)
-	contract.Assert(old.Type == new.Type)
+	contract.Assert(old.Type == new.Type || old.URN != new.URN)
 	contract.Assert(!new.External)
 	contract.Assert(!old.External)
 	return &UpdateStep{
-		plan:    plan,
-		reg:     reg,
-		old:     old,
-		new:     new,
-		stables: stables,
-	}
-}
-
-func (s *UpdateStep) Op() StepOp           { return OpUpdate }
-func (s *UpdateStep) Plan() *Plan          { return s.plan }
-func (s *UpdateStep) Type() tokens.Type    { return s.old.Type }
-func (s *UpdateStep) Provider() string     { return s.old.Provider }
-func (s *UpdateStep) URN() resource.URN    { return s.old.URN }
-func (s *UpdateStep) Old() *resource.State { return s.old }
-func (s *UpdateStep) New() *resource.State { return s.new }
-func (s *UpdateStep) Res() *resource.State { return s.new }
-func (s *UpdateStep) Logical() bool        { return true }
-
-func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
-	// Always propagate the URN and ID, even in previews and refreshes.
-	s.new.URN = s.old.URN
+		plan:         plan,
+		reg:          reg,
+		old:          old,
+		new:          new,
+		stables:      stables,
+		diffs:        diffs,
+		detailedDiff: detailedDiff,
+	}
+}
+
+func (s *UpdateStep) Op() StepOp                                   { return OpUpdate }
+func (s *UpdateStep) Plan() *Plan                                  { return s.plan }
+func (s *UpdateStep) Type() tokens.Type                            { return s.new.Type }
+func (s *UpdateStep) Provider() string                             { return s.old.Provider }
+func (s *UpdateStep) URN() resource.URN                            { return s.new.URN }
+func (s *UpdateStep) Old() *resource.State                         { return s.old }
+func (s *UpdateStep) New() *resource.State                         { return s.new }
+func (s *UpdateStep) Res() *resource.State                         { return s.new }
+func (s *UpdateStep) Logical() bool                                { return true }
+func (s *UpdateStep) Diffs() []resource.PropertyKey                { return s.diffs }
+func (s *UpdateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+
+func (s *UpdateStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
+	// Always propagate the ID, even in previews and refreshes.  The URN is not propagated, as the resource may have
+	// been renamed via an alias.
 	s.new.ID = s.old.ID
 
 	var resourceError error
 	resourceStatus := resource.StatusOK
 	if !preview {
+		if err := s.plan.runHooks(s.reg, BeforeUpdate, s.URN(), s.new.Inputs); err != nil {
+			return resource.StatusOK, nil, err
+		}
+
 		if s.new.Custom {
 			// Invoke the Update RPC function for this provider:
-			prov, err := getProvider(s)
+			prov, err := getProvider(ctx, s)
 			if err != nil {
 				return resource.StatusOK, nil, err
 			}
 
 			// Update to the combination of the old "all" state (including outputs), but overwritten with new inputs.
-			outs, rst, upderr := prov.Update(s.URN(), s.old.ID, s.old.All(), s.new.Inputs)
+			var outs resource.PropertyMap
+			rst, upderr := withTimeout(s.new, resource.OperationTypeUpdating, func() (resource.Status, error) {
+				var rst resource.Status
+				var err error
+				outs, rst, err = prov.Update(s.URN(), s.old.ID, s.old.Inputs, s.old.Outputs, s.new.Inputs,
+					s.new.CustomTimeouts.Update)
+				return rst, err
+			})
 			if upderr != nil {
 				if rst != resource.StatusPartialFailure {
 					return rst, nil, upderr
@@ -409,8 +472,23 @@ func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 			}
 
 			// Now copy any output state back in case the update triggered cascading updates to other properties.
-			s.new.Outputs = outs
+			s.new.Outputs = resource.RestoreFileRefs(s.new.Inputs, outs)
+
+			if resourceError == nil {
+				resourceStatus, resourceError = awaitReady(s.plan, s.reg, s.new, prov)
+			}
+			recordProvenance(s.Op(), s.old, s.new)
 		}
+
+		if resourceError == nil {
+			resourceStatus, resourceError = runAfterHooks(s.plan, s.reg, AfterUpdate, s.new)
+		}
+	} else if s.new.Custom {
+		// The provider has promised that the stable outputs will not change during this update, so report their old
+		// values rather than leaving them unknown, along with any other outputs that the provider predicted (which
+		// the step generator leaves in the new state's outputs).  This keeps resources that refer to them from
+		// appearing to change during the preview.
+		s.new.Outputs = previewOutputs(stableOutputs(s.old.Outputs, s.stables), s.new.Outputs)
 	}
 
 	// Finally, mark this operation as complete.
@@ -425,17 +503,19 @@ func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 // a creation of the new resource, any number of intervening updates of dependents to the new resource, and then
 // a deletion of the now-replaced old resource.  This logical step is primarily here for tools and visualization.
 type ReplaceStep struct {
-	plan          *Plan                  // the current plan.
-	old           *resource.State        // the state of the existing resource.
-	new           *resource.State        // the new state snapshot.
-	keys          []resource.PropertyKey // the keys causing replacement.
-	pendingDelete bool                   // true if a pending deletion should happen.
+	plan          *Plan                          // the current plan.
+	old           *resource.State                // the state of the existing resource.
+	new           *resource.State                // the new state snapshot.
+	keys          []resource.PropertyKey         // the keys causing replacement.
+	diffs         []resource.PropertyKey         // the keys causing a diff.
+	detailedDiff  map[string]plugin.PropertyDiff // the structured property diff.
+	pendingDelete bool                           // true if a pending deletion should happen.
 }
 
 var _ Step = (*ReplaceStep)(nil)
 
-func NewReplaceStep(plan *Plan, old *resource.State, new *resource.State,
-	keys []resource.PropertyKey, pendingDelete bool) Step {
+func NewReplaceStep(plan *Plan, old *resource.State, new *resource.State, keys, diffs []resource.PropertyKey,
+	detailedDiff map[string]plugin.PropertyDiff, pendingDelete bool) Step {
 	contract.Assert(old != nil)
 	contract.Assert(old.URN != "")
 	contract.Assert(old.ID != "" || !old.Custom)
@@ -449,22 +529,26 @@ func NewReplaceStep(plan *Plan, old *resource.State, new *resource.State,
 		old:           old,
 		new:           new,
 		keys:          keys,
+		diffs:         diffs,
+		detailedDiff:  detailedDiff,
 		pendingDelete: pendingDelete,
 	}
 }
 
-func (s *ReplaceStep) Op() StepOp                   { return OpReplace }
-func (s *ReplaceStep) Plan() *Plan                  { return s.plan }
-func (s *ReplaceStep) Type() tokens.Type            { return s.old.Type }
-func (s *ReplaceStep) Provider() string             { return s.old.Provider }
-func (s *ReplaceStep) URN() resource.URN            { return s.old.URN }
-func (s *ReplaceStep) Old() *resource.State         { return s.old }
-func (s *ReplaceStep) New() *resource.State         { return s.new }
-func (s *ReplaceStep) Res() *resource.State         { return s.new }
-func (s *ReplaceStep) Keys() []resource.PropertyKey { return s.keys }
-func (s *ReplaceStep) Logical() bool                { return true }
-
-func (s *ReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *ReplaceStep) Op() StepOp                                   { return OpReplace }
+func (s *ReplaceStep) Plan() *Plan                                  { return s.plan }
+func (s *ReplaceStep) Type() tokens.Type                            { return s.new.Type }
+func (s *ReplaceStep) Provider() string                             { return s.old.Provider }
+func (s *ReplaceStep) URN() resource.URN                            { return s.new.URN }
+func (s *ReplaceStep) Old() *resource.State                         { return s.old }
+func (s *ReplaceStep) New() *resource.State                         { return s.new }
+func (s *ReplaceStep) Res() *resource.State                         { return s.new }
+func (s *ReplaceStep) Keys() []resource.PropertyKey                 { return s.keys }
+func (s *ReplaceStep) Diffs() []resource.PropertyKey                { return s.diffs }
+func (s *ReplaceStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+func (s *ReplaceStep) Logical() bool                                { return true }
+
+func (s *ReplaceStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	// If this is a pending delete, we should have marked the old resource for deletion in the CreateReplacement step.
 	contract.Assert(!s.pendingDelete || s.old.Delete)
 	return resource.StatusOK, func() {}, nil
@@ -479,6 +563,9 @@ func (s *ReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 // target of a RegisterResource in the next plan, a CreateReplacement step will be issued to indicate the transition
 // from external to owned. If a URN that was previously not marked "External" is the target of a ReadResource in the
 // next plan, a ReadReplacement step will be issued to indicate the transition from owned to external.
+//
+// External resources are never updated or deleted: when a program stops reading one, or its stack is destroyed, it is
+// discarded from the snapshot (see DeleteStep) but left as it is in the cloud.
 type ReadStep struct {
 	plan      *Plan             // the plan that produced this read
 	event     ReadResourceEvent // the event that should be signaled upon completion
@@ -542,7 +629,7 @@ func (s *ReadStep) New() *resource.State { return s.new }
 func (s *ReadStep) Res() *resource.State { return s.new }
 func (s *ReadStep) Logical() bool        { return !s.replacing }
 
-func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+func (s *ReadStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	urn := s.new.URN
 	id := s.new.ID
 
@@ -553,7 +640,7 @@ func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 	if id == "" || id == plugin.UnknownStringValue {
 		s.new.Outputs = resource.PropertyMap{}
 	} else {
-		prov, err := getProvider(s)
+		prov, err := getProvider(ctx, s)
 		if err != nil {
 			return resource.StatusOK, nil, err
 		}
@@ -570,9 +657,14 @@ func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 			if initErr, isInitErr := err.(*plugin.InitError); isInitErr {
 				s.new.InitErrors = initErr.Reasons
 			}
+		} else if result == nil {
+			// The provider reports that there is no such resource. Rather than track a resource that does not exist,
+			// fail the read, so that the resources that depend upon its outputs are not planned against nothing.
+			return resource.StatusOK, nil, errors.Errorf("resource '%s' does not exist", id)
 		}
 
 		s.new.Outputs = result
+		recordProvenance(s.Op(), s.old, s.new)
 	}
 
 	// If we were asked to replace an existing, non-External resource, pend the
@@ -592,10 +684,11 @@ func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 // resource by reading its current state from its provider plugin. These steps are not issued by the step generator;
 // instead, they are issued by the plan executor as the optional first step in plan execution.
 type RefreshStep struct {
-	plan *Plan           // the plan that produced this refresh
-	old  *resource.State // the old resource state, if one exists for this urn
-	new  *resource.State // the new resource state, to be used to query the provider
-	done chan<- bool     // the channel to use to signal completion, if any
+	plan       *Plan                   // the plan that produced this refresh
+	old        *resource.State         // the old resource state, if one exists for this urn
+	new        *resource.State         // the new resource state, to be used to query the provider
+	done       chan<- bool             // the channel to use to signal completion, if any
+	prefetched *plugin.BatchReadResult // the result of reading the resource as part of a batch, if any
 }
 
 // NewRefreshStep creates a new Refresh step.
@@ -633,25 +726,45 @@ func (s *RefreshStep) ResultOp() StepOp {
 	return OpUpdate
 }
 
-func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+// refreshable returns true if the step's resource is read from its provider. Component, provider, and pending-replace
+// resources never change with a refresh, and neither do resources whose providers cannot read them.
+func (s *RefreshStep) refreshable() bool {
+	if !s.old.Custom || providers.IsProviderType(s.old.Type) || s.old.PendingReplacement {
+		return false
+	}
+	if !s.plan.providerCapabilities(s.old.Provider).Read {
+		logging.V(7).Infof("RefreshStep(%v): provider cannot read resources; leaving resource as is", s.old.URN)
+		return false
+	}
+	return true
+}
+
+func (s *RefreshStep) Apply(ctx context.Context, preview bool) (resource.Status, StepCompleteFunc, error) {
 	var complete func()
 	if s.done != nil {
 		complete = func() { close(s.done) }
 	}
 
-	// Component, provider, and pending-replace resources never change with a refresh; just return the current state.
-	if !s.old.Custom || providers.IsProviderType(s.old.Type) || s.old.PendingReplacement {
+	// Resources that are not read from their providers never change with a refresh; just return the current state.
+	if !s.refreshable() {
 		return resource.StatusOK, complete, nil
 	}
 
-	// For a custom resource, fetch the resource's provider and read the resource's current state.
-	prov, err := getProvider(s)
+	// For a custom resource, fetch the resource's provider and read the resource's current state, unless the
+	// resource was successfully read as part of a batch.
+	prov, err := getProvider(ctx, s)
 	if err != nil {
 		return resource.StatusOK, nil, err
 	}
 
 	var initErrors []string
-	refreshed, rst, err := prov.Read(s.old.URN, s.old.ID, s.old.Outputs)
+	var refreshed resource.PropertyMap
+	var rst resource.Status
+	if r := s.prefetched; r != nil && (r.Err == nil || r.Status == resource.StatusPartialFailure) {
+		refreshed, rst, err = r.Outputs, r.Status, r.Err
+	} else {
+		refreshed, rst, err = prov.Read(s.old.URN, s.old.ID, s.old.Outputs)
+	}
 	if err != nil {
 		if rst != resource.StatusPartialFailure {
 			return rst, nil, err
@@ -662,9 +775,19 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 	}
 
 	if refreshed != nil {
+		refreshed = resource.RestoreFileRefs(s.old.Outputs, refreshed)
+		if s.plan.merge && err == nil {
+			if refreshed, rst, err = s.mergeRefreshed(prov, refreshed, preview); err != nil &&
+				rst != resource.StatusPartialFailure {
+				return rst, nil, err
+			}
+		}
 		s.new = resource.NewState(s.old.Type, s.old.URN, s.old.Custom, s.old.Delete, s.old.ID, s.old.Inputs, refreshed,
 			s.old.Parent, s.old.Protect, s.old.External, s.old.Dependencies, initErrors, s.old.Provider,
-			s.old.PropertyDependencies, s.old.PendingReplacement)
+			s.old.PropertyDependencies, s.old.PendingReplacement, s.old.RetainOnDelete, s.old.Defaults,
+			s.old.CustomTimeouts, s.old.PropertyReads, nil)
+		s.new.InputHashes = s.old.InputHashes
+		recordProvenance(s.Op(), s.old, s.new)
 	} else {
 		s.new = nil
 	}
@@ -672,6 +795,39 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 	return rst, complete, err
 }
 
+// mergeRefreshed merges the state read by a refresh with the resource's last recorded state, treating the properties
+// that the program sets as managed. Changes made out of band to unmanaged properties are kept, while changes to managed
+// properties are reverted by updating the resource with its inputs. If the step is being previewed, the resource is not
+// updated, and the reverted values are simply reported.
+func (s *RefreshStep) mergeRefreshed(prov plugin.Provider, refreshed resource.PropertyMap,
+	preview bool) (resource.PropertyMap, resource.Status, error) {
+
+	managed := func(path resource.PropertyPath) bool {
+		_, has := s.old.Inputs[resource.PropertyKey(path[0].(string))]
+		return has
+	}
+	merge := resource.Merge3(s.old.Outputs, refreshed, s.old.Outputs, managed)
+	reasserted := merge.Reasserted()
+	if len(merge.Changes) > len(reasserted) {
+		logging.V(7).Infof("RefreshStep(%v): keeping %d out-of-band changes to unmanaged properties",
+			s.old.URN, len(merge.Changes)-len(reasserted))
+	}
+	if len(reasserted) == 0 {
+		return merge.Merged, resource.StatusOK, nil
+	}
+
+	paths := make([]string, len(reasserted))
+	for i, c := range reasserted {
+		paths[i] = c.Path.String()
+	}
+	s.plan.Ctx().StatusDiag.Infof(diag.RawMessage(s.old.URN,
+		fmt.Sprintf("reverting out-of-band changes to %s", strings.Join(paths, ", "))))
+	if preview {
+		return merge.Merged, resource.StatusOK, nil
+	}
+	return prov.Update(s.old.URN, s.old.ID, s.old.Inputs, refreshed, s.old.Inputs, s.old.CustomTimeouts.Update)
+}
+
 // StepOp represents the kind of operation performed by a step.  It evaluates to its string label.
 type StepOp string
 
@@ -801,8 +957,9 @@ func (op StepOp) Suffix() string {
 	return ""
 }
 
-// getProvider fetches the provider for the given step.
-func getProvider(s Step) (plugin.Provider, error) {
+// getProvider fetches the provider for the given step. The provider's operations are traced as children of the span
+// in ctx, if any.
+func getProvider(ctx context.Context, s Step) (plugin.Provider, error) {
 	if providers.IsProviderType(s.Type()) {
 		return s.Plan().providers, nil
 	}
@@ -810,9 +967,93 @@ func getProvider(s Step) (plugin.Provider, error) {
 	if err != nil {
 		return nil, errors.Errorf("bad provider reference '%v' for resource %v: %v", s.Provider(), s.URN(), err)
 	}
-	provider, ok := s.Plan().GetProvider(ref)
+	provider, ok := s.Plan().getProvider(ctx, ref)
 	if !ok {
 		return nil, errors.Errorf("unknown provider '%v' for resource %v", s.Provider(), s.URN())
 	}
 	return provider, nil
 }
+
+// stableOutputs returns the subset of the given outputs whose keys are stable, or nil if there are none.
+func stableOutputs(outputs resource.PropertyMap, stables []resource.PropertyKey) resource.PropertyMap {
+	var result resource.PropertyMap
+	for _, k := range stables {
+		if v, has := outputs[k]; has {
+			if result == nil {
+				result = make(resource.PropertyMap)
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// previewOutputs returns the given known outputs, along with those of the given predicted outputs that are not known.
+func previewOutputs(known, predicted resource.PropertyMap) resource.PropertyMap {
+	if len(predicted) == 0 {
+		return known
+	}
+	result := predicted.Copy()
+	for k, v := range known {
+		result[k] = v
+	}
+	return result
+}
+
+// recordProvenance attributes the outputs of the new state that differ from those of the old state, if any, to the
+// given operation.
+func recordProvenance(op StepOp, old, new *resource.State) {
+	var olds resource.PropertyMap
+	var provenance resource.PropertyProvenance
+	if old != nil {
+		olds, provenance = old.Outputs, old.Provenance
+	}
+	new.Provenance = provenance.Update(olds, new.Outputs, resource.PropertySource{Op: string(op), Time: time.Now()})
+}
+
+// awaitReady waits for a newly created or updated resource to satisfy its ready conditions, if any, so that the
+// resources that depend on it do not proceed until it is ready. The resource's own conditions are used if it has any;
+// otherwise, those that its provider declares for its type are. A resource that never becomes ready has nonetheless
+// been created or updated, so it is recorded in the snapshot, with the outputs last read from its provider, and the
+// step fails as a partial failure.
+func awaitReady(plan *Plan, reg RegisterResourceEvent, state *resource.State,
+	prov plugin.Provider) (resource.Status, error) {
+
+	if reg == nil || reg.Goal() == nil {
+		return resource.StatusOK, nil
+	}
+	conditions, timeout, err := plan.readiness(prov, state.Provider, state.Type, reg.Goal().ReadyConditions)
+	if err != nil {
+		return resource.StatusPartialFailure, err
+	}
+	if len(conditions) == 0 {
+		return resource.StatusOK, nil
+	}
+	outs, err := plan.awaitReady(prov, state, conditions, timeout)
+	if outs != nil {
+		state.Outputs = outs
+	}
+	if err != nil {
+		return resource.StatusPartialFailure, err
+	}
+	return resource.StatusOK, nil
+}
+
+// runAfterHooks runs the hooks that follow the creation or update of a resource. Like a resource that never becomes
+// ready, a resource whose hooks fail has nonetheless been created or updated, so the step fails as a partial failure.
+func runAfterHooks(plan *Plan, reg RegisterResourceEvent, event HookEvent,
+	state *resource.State) (resource.Status, error) {
+
+	if err := plan.runHooks(reg, event, state.URN, state.All()); err != nil {
+		return resource.StatusPartialFailure, err
+	}
+	return resource.StatusOK, nil
+}
+
+// stepCompletedError is returned by a step that failed after it had already completed, e.g. a delete whose after-delete
+// hooks failed. The step executor saves the results of such a step as if it had succeeded before reporting its error.
+type stepCompletedError struct {
+	err error
+}
+
+func (e *stepCompletedError) Error() string { return e.err.Error() }
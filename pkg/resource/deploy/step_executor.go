@@ -20,6 +20,8 @@ import (
 	"sync"
 	"sync/atomic"
 
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -267,7 +269,32 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	}
 
 	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
-	status, stepComplete, err := step.Apply(se.preview)
+	// The step's provider operations are traced as children of the step's span.
+	span, spanCtx := opentracing.StartSpanFromContext(se.ctx, "pulumi-step", opentracing.Tags{
+		"op":      string(step.Op()),
+		"urn":     string(step.URN()),
+		"preview": se.preview,
+	})
+	status, stepComplete, err := step.Apply(spanCtx, se.preview)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("error", err.Error())
+	}
+	span.Finish()
+
+	// A step that failed after it completed is saved as a success; its error is reported once its results are saved.
+	var completedErr error
+	if cerr, ok := err.(*stepCompletedError); ok {
+		completedErr, err = cerr.err, nil
+	}
+
+	if !se.preview {
+		result := "succeeded"
+		if err != nil {
+			result = "failed"
+		}
+		stepsExecuted.Inc(string(step.Op()), result)
+	}
 
 	if err == nil {
 		// If we have a state object, and this is a create or update, remember it, as we may need to update it later.
@@ -295,6 +322,10 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 		stepComplete()
 	}
 
+	if completedErr != nil {
+		se.plan.Diag().Errorf(diag.GetPreviewFailedError(step.URN()), completedErr)
+		err = completedErr
+	}
 	if err != nil {
 		se.log(workerID, "step %v on %v failed with an error: %v", step.Op(), step.URN(), err)
 		return errStepApplyFailed
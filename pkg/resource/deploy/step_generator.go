@@ -15,12 +15,16 @@
 package deploy
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/resource/graph"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/policy"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
@@ -35,24 +39,32 @@ type stepGenerator struct {
 	plan *Plan   // the plan to which this step generator belongs
 	opts Options // options for this step generator
 
-	urns           map[resource.URN]bool    // set of URNs discovered for this plan
-	reads          map[resource.URN]bool    // set of URNs read for this plan
-	deletes        map[resource.URN]bool    // set of URNs deleted in this plan
-	replaces       map[resource.URN]bool    // set of URNs replaced in this plan
-	updates        map[resource.URN]bool    // set of URNs updated in this plan
-	creates        map[resource.URN]bool    // set of URNs created in this plan
-	sames          map[resource.URN]bool    // set of URNs that were not changed in this plan
-	pendingDeletes map[*resource.State]bool // set of resources (not URNs!) that are pending deletion
+	urns           map[resource.URN]bool         // set of URNs discovered for this plan
+	reads          map[resource.URN]bool         // set of URNs read for this plan
+	deletes        map[resource.URN]bool         // set of URNs deleted in this plan
+	replaces       map[resource.URN]bool         // set of URNs replaced in this plan
+	updates        map[resource.URN]bool         // set of URNs updated in this plan
+	creates        map[resource.URN]bool         // set of URNs created in this plan
+	sames          map[resource.URN]bool         // set of URNs that were not changed in this plan
+	pendingDeletes map[*resource.State]bool      // set of resources (not URNs!) that are pending deletion
+	aliased        map[resource.URN]resource.URN // set of old URNs that were aliased, mapped to their new URNs
+	targets        map[resource.URN]bool         // set of URNs targeted by this plan, or nil if all resources are targeted
+	replaceTargets map[resource.URN]bool         // set of URNs that must be replaced regardless of their diffs
+	parents        map[resource.URN]resource.URN // the parent of each resource registered or read by this plan
 
 	// a map from URN to a list of property keys that caused the replacement of a dependent resource during a
 	// delete-before-replace.
 	dependentReplaceKeys map[resource.URN][]resource.PropertyKey
+
+	// the URN generated for each registration, after any transformations were applied to its goal.
+	eventURNs map[RegisterResourceEvent]resource.URN
 }
 
 // GenerateReadSteps is responsible for producing one or more steps required to service
 // a ReadResourceEvent coming from the language host.
 func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, *result.Result) {
 	urn := sg.plan.generateURN(event.Parent(), event.Type(), event.Name())
+	sg.parents[urn] = event.Parent()
 	newState := resource.NewState(event.Type(),
 		urn,
 		true,  /*custom*/
@@ -66,8 +78,9 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, *re
 		event.Dependencies(),
 		nil, /* initErrors */
 		event.Provider(),
-		nil, /* propertyDependencies */
-		false)
+		nil,   /* propertyDependencies */
+		false, /*pendingReplacement*/
+		false /*retainOnDelete*/, nil, resource.CustomTimeouts{}, nil, nil)
 	old, hasOld := sg.plan.Olds()[urn]
 
 	// If the snapshot has an old resource for this URN and it's not external, we're going
@@ -89,7 +102,7 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, *re
 		sg.replaces[urn] = true
 		return []Step{
 			NewReadReplacementStep(sg.plan, event, old, newState),
-			NewReplaceStep(sg.plan, old, newState, nil, true),
+			NewReplaceStep(sg.plan, old, newState, nil, nil, nil, true),
 		}, nil
 	}
 
@@ -112,9 +125,23 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, *re
 func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *result.Result) {
 	var invalid bool // will be set to true if this object fails validation.
 
-	goal := event.Goal()
+	// Apply any transformations before the goal is inspected.
+	goal, err := sg.opts.Transformations.apply(event.Goal(), sg.parents)
+	if err != nil {
+		return nil, result.FromError(err)
+	}
+
+	// Merge the target's default tags into the goal if the resource is taggable.
+	taggable := sg.opts.TaggableTypes
+	if taggable == nil {
+		taggable = DefaultTaggableTypes
+	}
+	goal, tagsKey, injectedTags := injectDefaultTags(goal, sg.plan.defaultTags, taggable)
+
 	// generate an URN for this new resource.
 	urn := sg.plan.generateURN(goal.Parent, goal.Type, goal.Name)
+	sg.parents[urn] = goal.Parent
+	sg.eventURNs[event] = urn
 	if sg.urns[urn] {
 		invalid = true
 		// TODO[pulumi/pulumi-framework#19]: improve this error message!
@@ -122,8 +149,48 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 	}
 	sg.urns[urn] = true
 
-	// Check for an old resource so that we can figure out if this is a create, delete, etc., and/or to diff.
+	// Members of an indexed collection are identified by their index, so each index must have exactly one spelling.
+	if resource.IsMalformedIndexedName(goal.Name) {
+		invalid = true
+		sg.plan.Diag().Errorf(diag.GetMalformedCollectionIndexError(urn), urn)
+	}
+
+	// A child must be registered after its parent so that the parent precedes it in the snapshot.
+	if goal.Parent != "" && !sg.urns[goal.Parent] && !sg.reads[goal.Parent] {
+		invalid = true
+		sg.plan.Diag().Errorf(diag.GetResourceParentNotFoundError(urn), urn, goal.Parent)
+	}
+
+	// Check for an old resource so that we can figure out if this is a create, delete, etc., and/or to diff.  We look
+	// up first by URN and then by any aliases.  If the old resource is found by an alias, record that alias so that
+	// the old resource is not deleted.
 	old, hasOld := sg.plan.Olds()[urn]
+	if hasOld {
+		// Another resource may have already claimed this resource's old state by alias.
+		if previous, aliased := sg.aliased[urn]; aliased {
+			invalid = true
+			sg.plan.Diag().Errorf(diag.GetDuplicateResourceAliasError(urn), urn, urn, previous)
+		}
+	} else {
+		for _, alias := range goal.Aliases {
+			if old, hasOld = sg.plan.Olds()[alias]; !hasOld {
+				continue
+			}
+
+			// The old resource may have already been claimed by its own URN or by another resource's alias.
+			if previous, aliased := sg.aliased[alias]; aliased {
+				invalid = true
+				sg.plan.Diag().Errorf(diag.GetDuplicateResourceAliasError(urn), alias, urn, previous)
+			} else if sg.urns[alias] {
+				invalid = true
+				sg.plan.Diag().Errorf(diag.GetDuplicateResourceAliasError(urn), alias, urn, alias)
+			}
+
+			logging.V(7).Infof("Planner matched '%v' to old resource '%v' by alias", urn, alias)
+			sg.aliased[alias] = urn
+			break
+		}
+	}
 	var oldInputs resource.PropertyMap
 	var oldOutputs resource.PropertyMap
 	if hasOld {
@@ -131,11 +198,38 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 		oldOutputs = old.Outputs
 	}
 
+	// If this plan is restricted to a set of targets that does not include this resource, leave the resource exactly
+	// as it is. Providers are always considered targeted, as targeted resources may need them.
+	if !sg.isTargeted(urn) && !providers.IsProviderType(goal.Type) {
+		if !hasOld {
+			sg.plan.Diag().Errorf(diag.GetUntargetedCreateError(urn), urn)
+			return nil, result.Bail()
+		}
+
+		logging.V(7).Infof("Planner decided not to update '%v' (not targeted)", urn)
+		sg.sames[urn] = true
+		new := resource.NewState(old.Type, urn, old.Custom, false, "", old.Inputs, nil, old.Parent, old.Protect,
+			old.External, old.Dependencies, old.InitErrors, old.Provider, old.PropertyDependencies,
+			old.PendingReplacement, old.RetainOnDelete, old.Defaults, old.CustomTimeouts, old.PropertyReads,
+			old.Provenance)
+		new.InputHashes = old.InputHashes
+		return []Step{NewSameStep(sg.plan, event, old, new)}, nil
+	}
+
 	// Produce a new state object that we'll build up as operations are performed.  Ultimately, this is what will
 	// get serialized into the checkpoint file.
 	inputs := goal.Properties
+
+	// Carry over the old values of any properties whose changes should be ignored.
+	if hasOld && !old.External && len(goal.IgnoreChanges) > 0 {
+		if inputs, err = processIgnoreChanges(inputs, oldInputs, goal.IgnoreChanges); err != nil {
+			return nil, result.FromError(err)
+		}
+	}
+
 	new := resource.NewState(goal.Type, urn, goal.Custom, false, "", inputs, nil, goal.Parent, goal.Protect, false,
-		goal.Dependencies, goal.InitErrors, goal.Provider, goal.PropertyDependencies, false)
+		goal.Dependencies, goal.InitErrors, goal.Provider, goal.PropertyDependencies, false, goal.RetainOnDelete, nil,
+		goal.CustomTimeouts, goal.PropertyReads, nil)
 
 	// Fetch the provider for this resource.
 	prov, err := sg.getResourceProvider(urn, goal.Custom, goal.Provider, goal.Type)
@@ -147,22 +241,38 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 	allowUnknowns := sg.plan.preview
 
 	// We may be re-creating this resource if it got deleted earlier in the execution of this plan.
-	_, recreating := sg.deletes[urn]
+	recreating := hasOld && sg.deletes[old.URN]
 
 	// We may be creating this resource if it previously existed in the snapshot as an External resource
 	wasExternal := hasOld && old.External
 
 	// Ensure the provider is okay with this resource and fetch the inputs to pass to subsequent methods.
+	nameKey := sg.autoNamedTypes()[goal.Type]
+	var previews resource.PropertyMap
 	if prov != nil {
 		var failures []plugin.CheckFailure
+		programInputs := inputs
+
+		// If the target enables auto-naming and the program did not name this resource, generate a name, reusing
+		// the name generated for the old resource unless its old inputs are being discarded. Names are generated from
+		// the logical URN, so that each fan-out instance names its resources alike.
+		logical := sg.plan.logicalURN(urn)
+		if hasOld && !recreating && !wasExternal {
+			inputs, _, err = autoName(sg.plan.autoNaming, logical, goal.Type, nameKey, inputs, oldInputs, old.Defaults)
+		} else {
+			inputs, _, err = autoName(sg.plan.autoNaming, logical, goal.Type, nameKey, inputs, nil, nil)
+		}
+		if err != nil {
+			return nil, result.FromError(err)
+		}
 
 		// If we are re-creating this resource because it was deleted earlier, the old inputs are now
 		// invalid (they got deleted) so don't consider them. Similarly, if the old resource was External,
 		// don't consider those inputs since Pulumi does not own them.
 		if recreating || wasExternal {
-			inputs, failures, err = prov.Check(urn, nil, goal.Properties, allowUnknowns)
+			inputs, previews, failures, err = plugin.CheckPreview(prov, urn, nil, inputs, allowUnknowns)
 		} else {
-			inputs, failures, err = prov.Check(urn, oldInputs, inputs, allowUnknowns)
+			inputs, previews, failures, err = plugin.CheckPreview(prov, urn, oldInputs, inputs, allowUnknowns)
 		}
 
 		if err != nil {
@@ -170,9 +280,50 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 		} else if sg.issueCheckErrors(new, urn, failures) {
 			invalid = true
 		}
+		inputs = resource.RestoreFileRefs(programInputs, inputs)
+
+		// Record which inputs the provider defaulted. Defaults that the provider also chose for the old inputs keep
+		// their old values, so that providers that pick a fresh default on every check do not cause perpetual diffs.
+		if hasOld && !recreating && !wasExternal {
+			inputs, new.Defaults = processDefaults(programInputs, inputs, oldInputs, old.Defaults)
+		} else {
+			inputs, new.Defaults = processDefaults(programInputs, inputs, nil, nil)
+		}
+		new.Inputs = inputs
+	}
+
+	// If the only changes to the resource's inputs are to injected default tags, leave its inputs as they were.
+	if hasOld && !recreating && !wasExternal && len(injectedTags) > 0 {
+		inputs = suppressDefaultTagChanges(oldInputs, inputs, tagsKey, injectedTags)
+		new.Inputs = inputs
+	}
+
+	// Sort any arrays that this resource's type treats as sets, so that reordering their elements is not a change.
+	if paths := sg.unorderedProperties(goal.Type); len(paths) > 0 {
+		inputs = inputs.SortSets(paths)
+		new.Inputs = inputs
+		oldInputs, oldOutputs = oldInputs.SortSets(paths), oldOutputs.SortSets(paths)
+	}
+
+	// Compare any properties that hold JSON documents by their documents rather than by their text.
+	if paths := sg.jsonProperties(goal.Type); len(paths) > 0 {
+		inputs = inputs.MarkJSON(paths)
 		new.Inputs = inputs
+		oldInputs, oldOutputs = oldInputs.MarkJSON(paths), oldOutputs.MarkJSON(paths)
+	}
+
+	// Keep the old values of any properties whose new values differ from them only in representation.
+	if hasOld && !recreating && !wasExternal {
+		if normalizers := sg.propertyNormalizers(goal.Type); len(normalizers) > 0 {
+			inputs = suppressNormalizedChanges(oldInputs, inputs, normalizers)
+			new.Inputs = inputs
+		}
 	}
 
+	// Now that the inputs are final, fingerprint them and their large subtrees. The fingerprints are saved with the
+	// resource, so that the next plan need not hash its old inputs to tell whether they have changed.
+	new.InputHashes = inputs.SubtreeHashes(resource.DefaultSubtreeHashThreshold)
+
 	// Next, give each analyzer -- if any -- a chance to inspect the resource too.
 	for _, a := range sg.plan.analyzers {
 		var analyzer plugin.Analyzer
@@ -194,6 +345,25 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 		}
 	}
 
+	// Finally, evaluate each policy pack -- if any -- against the resource's planned state. Each pack is given its own
+	// copy of the inputs, so that it cannot change the properties that are sent to the provider.
+	for _, pack := range sg.plan.policies {
+		var violations []policy.Violation
+		violations, err = pack.Validate(policy.Resource{Type: new.Type, URN: urn, Properties: inputs.DeepCopy()})
+		if err != nil {
+			return nil, result.FromError(err)
+		}
+		for _, v := range violations {
+			d := diag.GetPolicyViolationError(urn)
+			if v.EnforcementLevel == policy.Mandatory {
+				invalid = true
+				sg.plan.Diag().Errorf(d, v.Policy, pack.Name(), v.EnforcementLevel, urn, v.Property, v.Message)
+			} else {
+				sg.plan.Diag().Warningf(d, v.Policy, pack.Name(), v.EnforcementLevel, urn, v.Property, v.Message)
+			}
+		}
+	}
+
 	// If the resource isn't valid, don't proceed any further.
 	if invalid {
 		return nil, result.Bail()
@@ -218,12 +388,13 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 		logging.V(7).Infof("Planner decided to re-create replaced resource '%v' deleted due to dependent DBR", urn)
 
 		// Unmark this resource as deleted, we now know it's being replaced instead.
-		delete(sg.deletes, urn)
+		delete(sg.deletes, old.URN)
 		sg.replaces[urn] = true
-		keys := sg.dependentReplaceKeys[urn]
+		keys := sg.dependentReplaceKeys[old.URN]
+		sg.setPreviewOutputs(new, previews)
 		return []Step{
-			NewReplaceStep(sg.plan, old, new, nil, false),
-			NewCreateReplacementStep(sg.plan, event, old, new, keys, false),
+			NewReplaceStep(sg.plan, old, new, nil, nil, nil, false),
+			NewCreateReplacementStep(sg.plan, event, old, new, keys, nil, nil, false),
 		}, nil
 	}
 
@@ -241,10 +412,11 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 		if err != nil {
 			return nil, result.FromError(err)
 		}
+		sg.setPreviewOutputs(new, previews)
 
 		return []Step{
-			NewCreateReplacementStep(sg.plan, event, old, new, nil, true),
-			NewReplaceStep(sg.plan, old, new, nil, true),
+			NewCreateReplacementStep(sg.plan, event, old, new, nil, nil, nil, true),
+			NewReplaceStep(sg.plan, old, new, nil, nil, nil, true),
 		}, nil
 	}
 
@@ -258,14 +430,15 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 	//  - Otherwise, we invoke the resource's provider's `Diff` method. If this method indicates that the resource must
 	//    be replaced, we do so. If it does not, we update the resource in place.
 	if hasOld {
-		contract.Assert(old != nil && old.Type == new.Type)
+		contract.Assert(old != nil && (old.Type == new.Type || old.URN != urn))
 
 		var diff plugin.DiffResult
 		if old.Provider != new.Provider {
 			diff = plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"provider"}}
 		} else {
 			// Determine whether the change resulted in a diff.
-			d, diffErr := sg.diff(urn, old.ID, oldInputs, oldOutputs, inputs, prov, allowUnknowns)
+			d, diffErr := sg.diff(urn, old.ID, oldInputs, oldOutputs, inputs, old.InputHashes, new.InputHashes, prov,
+				allowUnknowns)
 			if diffErr != nil {
 				// If the plugin indicated that the diff is unavailable, assume that the resource will be updated and
 				// report the message contained in the error.
@@ -285,28 +458,68 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 				"unrecognized diff state for %s: %d", urn, diff.Changes)
 		}
 
+		// If any of the properties whose changes force a replacement have changed, replace the resource even if its
+		// provider would have updated it in place.
+		if len(goal.ReplaceOnChanges) > 0 {
+			replaceKeys, err := processReplaceOnChanges(oldInputs, inputs, diff, goal.ReplaceOnChanges)
+			if err != nil {
+				return nil, result.FromError(err)
+			}
+			if len(replaceKeys) > 0 {
+				logging.V(7).Infof("Planner decided to replace '%v' due to changes to %v", urn, replaceKeys)
+				diff.Changes, diff.ReplaceKeys = plugin.DiffSome, append(diff.ReplaceKeys, replaceKeys...)
+			}
+		}
+
+		// If the resource was marked for replacement, replace it even if its provider reported no changes. Any
+		// dependents that must be replaced along with it are found in the same way as for any other replacement.
+		if sg.replaceTargets[urn] && !diff.Replace() {
+			logging.V(7).Infof("Planner decided to replace '%v' because it was marked for replacement", urn)
+			diff.Changes, diff.ReplaceKeys = plugin.DiffSome, []resource.PropertyKey{"id"}
+		}
+
 		// If there were changes, check for a replacement vs. an in-place update.
 		if diff.Changes == plugin.DiffSome {
 			if diff.Replace() {
+				// Replacing a resource deletes the old one, so refuse to do so if it is protected.
+				if !sg.canDelete(old) {
+					return nil, result.Bail()
+				}
 				sg.replaces[urn] = true
 
 				// If we are going to perform a replacement, we need to recompute the default values.  The above logic
 				// had assumed that we were going to carry them over from the old resource, which is no longer true.
 				if prov != nil {
+					// The replacement exists alongside the old resource unless the old one is deleted first, so it
+					// needs a fresh name if it is auto-named.
+					replacementInputs, _, err := autoName(
+						sg.plan.autoNaming, urn, goal.Type, nameKey, goal.Properties, nil, nil)
+					if err != nil {
+						return nil, result.FromError(err)
+					}
+
 					var failures []plugin.CheckFailure
-					inputs, failures, err = prov.Check(urn, nil, goal.Properties, allowUnknowns)
+					inputs, previews, failures, err = plugin.CheckPreview(
+						prov, urn, nil, replacementInputs, allowUnknowns)
 					if err != nil {
 						return nil, result.FromError(err)
 					} else if sg.issueCheckErrors(new, urn, failures) {
 						return nil, result.Bail()
 					}
+					inputs = resource.RestoreFileRefs(goal.Properties, inputs)
+					inputs, new.Defaults = processDefaults(goal.Properties, inputs, nil, nil)
 					new.Inputs = inputs
+					new.InputHashes = inputs.SubtreeHashes(resource.DefaultSubtreeHashThreshold)
 				}
 
 				if logging.V(7) {
 					logging.V(7).Infof("Planner decided to replace '%v' (oldprops=%v inputs=%v)",
-						urn, oldInputs, new.Inputs)
+						urn, sg.loggable(urn, oldInputs), sg.loggable(urn, new.Inputs))
+				}
+				if diff.PreviewOutputs != nil {
+					previews = diff.PreviewOutputs
 				}
+				sg.setPreviewOutputs(new, previews)
 
 				// We have two approaches to performing replacements:
 				//
@@ -334,7 +547,7 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 					// trustworthy, which is interpreted by the DependencyGraph type.
 					var steps []Step
 					if sg.opts.TrustDependencies {
-						toReplace, err := sg.calculateDependentReplacements(old)
+						toReplace, err := sg.calculateDependentReplacements(old, new)
 						if err != nil {
 							return nil, result.FromError(err)
 						}
@@ -348,6 +561,9 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 							if sg.deletes[dependentResource.URN] {
 								continue
 							}
+							if !sg.canDelete(dependentResource) {
+								return nil, result.Bail()
+							}
 
 							sg.dependentReplaceKeys[dependentResource.URN] = toReplace[i].keys
 
@@ -363,37 +579,45 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 
 					return append(steps,
 						NewDeleteReplacementStep(sg.plan, old, true),
-						NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, false),
-						NewCreateReplacementStep(sg.plan, event, old, new, diff.ReplaceKeys, false),
+						NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, diff.ChangedKeys, diff.DetailedDiff, false),
+						NewCreateReplacementStep(
+							sg.plan, event, old, new, diff.ReplaceKeys, diff.ChangedKeys, diff.DetailedDiff, false),
 					), nil
 				}
 
 				return []Step{
-					NewCreateReplacementStep(sg.plan, event, old, new, diff.ReplaceKeys, true),
-					NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, true),
+					NewCreateReplacementStep(
+						sg.plan, event, old, new, diff.ReplaceKeys, diff.ChangedKeys, diff.DetailedDiff, true),
+					NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, diff.ChangedKeys, diff.DetailedDiff, true),
 					// note that the delete step is generated "later" on, after all creates/updates finish.
 				}, nil
 			}
 
 			// If we fell through, it's an update.
 			sg.updates[urn] = true
+			sg.setPreviewOutputs(new, diff.PreviewOutputs)
 			if logging.V(7) {
-				logging.V(7).Infof("Planner decided to update '%v' (oldprops=%v inputs=%v", urn, oldInputs, new.Inputs)
+				logging.V(7).Infof("Planner decided to update '%v' (oldprops=%v inputs=%v)",
+					urn, sg.loggable(urn, oldInputs), sg.loggable(urn, new.Inputs))
 			}
-			return []Step{NewUpdateStep(sg.plan, event, old, new, diff.StableKeys)}, nil
+			return []Step{
+				NewUpdateStep(sg.plan, event, old, new, diff.StableKeys, diff.ChangedKeys, diff.DetailedDiff),
+			}, nil
 		}
 
 		// If resource was unchanged, but there were initialization errors, generate an empty update
 		// step to attempt to "continue" awaiting initialization.
 		if len(old.InitErrors) > 0 {
 			sg.updates[urn] = true
-			return []Step{NewUpdateStep(sg.plan, event, old, new, diff.StableKeys)}, nil
+			return []Step{
+				NewUpdateStep(sg.plan, event, old, new, diff.StableKeys, diff.ChangedKeys, diff.DetailedDiff),
+			}, nil
 		}
 
 		// No need to update anything, the properties didn't change.
 		sg.sames[urn] = true
 		if logging.V(7) {
-			logging.V(7).Infof("Planner decided not to update '%v' (same) (inputs=%v)", urn, new.Inputs)
+			logging.V(7).Infof("Planner decided not to update '%v' (same) (inputs=%v)", urn, sg.loggable(urn, new.Inputs))
 		}
 		return []Step{NewSameStep(sg.plan, event, old, new)}, nil
 	}
@@ -402,16 +626,29 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, *re
 	//  If a resource isn't being recreated and it's not being updated or replaced,
 	//  it's just being created.
 	sg.creates[urn] = true
-	logging.V(7).Infof("Planner decided to create '%v' (inputs=%v)", urn, new.Inputs)
+	if logging.V(7) {
+		logging.V(7).Infof("Planner decided to create '%v' (inputs=%v)", urn, sg.loggable(urn, new.Inputs))
+	}
+	sg.setPreviewOutputs(new, previews)
 	return []Step{NewCreateStep(sg.plan, event, new)}, nil
 }
 
-func (sg *stepGenerator) GenerateDeletes() []Step {
+// setPreviewOutputs reports the outputs that a resource's provider predicted for it, if this plan is a preview.  The
+// steps that create or update the resource leave these outputs in place of those that they would have set.
+func (sg *stepGenerator) setPreviewOutputs(new *resource.State, previews resource.PropertyMap) {
+	if sg.plan.preview && new.Custom && len(previews) > 0 {
+		logging.V(7).Infof("Planner reporting %d predicted outputs for '%v'", len(previews), new.URN)
+		new.Outputs = previews
+	}
+}
+
+func (sg *stepGenerator) GenerateDeletes() ([]Step, *result.Result) {
 	// To compute the deletion list, we must walk the list of old resources *backwards*.  This is because the list is
 	// stored in dependency order, and earlier elements are possibly leaf nodes for later elements.  We must not delete
 	// dependencies prior to their dependent nodes.
 	var dels []Step
 	if prev := sg.plan.prev; prev != nil {
+		kept := sg.keptProtectedResources()
 		for i := len(prev.Resources) - 1; i >= 0; i-- {
 			// If this resource is explicitly marked for deletion or wasn't seen at all, delete it.
 			res := prev.Resources[i]
@@ -437,6 +674,10 @@ func (sg *stepGenerator) GenerateDeletes() []Step {
 						"Planner ignoring pending-delete resource (%v, %v) that was already deleted", res.URN, res.ID)
 					continue
 				}
+				if !sg.isTargeted(res.URN) {
+					logging.V(7).Infof("Planner ignoring pending-delete resource '%v' (not targeted)", res.URN)
+					continue
+				}
 
 				if sg.deletes[res.URN] {
 					logging.V(7).Infof(
@@ -446,9 +687,25 @@ func (sg *stepGenerator) GenerateDeletes() []Step {
 				logging.V(7).Infof("Planner decided to delete '%v' due to replacement", res.URN)
 				sg.deletes[res.URN] = true
 				dels = append(dels, NewDeleteReplacementStep(sg.plan, res, false))
-			} else if !sg.sames[res.URN] && !sg.updates[res.URN] && !sg.replaces[res.URN] && !sg.reads[res.URN] {
+			} else if _, aliased := sg.aliased[res.URN]; !sg.sames[res.URN] && !sg.updates[res.URN] &&
+				!sg.replaces[res.URN] && !sg.reads[res.URN] && !aliased {
 				// NOTE: we deliberately do not check sg.deletes here, as it is possible for us to issue multiple
 				// delete steps for the same URN if the old checkpoint contained pending deletes.
+				if !sg.isTargeted(res.URN) {
+					logging.V(7).Infof("Planner decided not to delete '%v' (not targeted)", res.URN)
+					continue
+				}
+				if kept[res] {
+					logging.V(7).Infof("Planner decided not to delete '%v' (protected, or needed by a protected resource)",
+						res.URN)
+					continue
+				}
+				if !res.PendingReplacement && !sg.canDelete(res) {
+					return nil, result.Bail()
+				}
+				if bail := sg.checkUntargetedDependents(res); bail != nil {
+					return nil, bail
+				}
 				logging.V(7).Infof("Planner decided to delete '%v'", res.URN)
 				sg.deletes[res.URN] = true
 				if !res.PendingReplacement {
@@ -459,7 +716,7 @@ func (sg *stepGenerator) GenerateDeletes() []Step {
 			}
 		}
 	}
-	return dels
+	return dels, nil
 }
 
 // GeneratePendingDeletes generates delete steps for all resources that are pending deletion. This function should be
@@ -568,9 +825,10 @@ func (sg *stepGenerator) ScheduleDeletes(deleteSteps []Step) []antichain {
 	return antichains
 }
 
-// diff returns a DiffResult for the given resource.
+// diff returns a DiffResult for the given resource. The hashes of the old and new inputs are used to recognize
+// unchanged inputs when they are known.
 func (sg *stepGenerator) diff(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
-	prov plugin.Provider, allowUnknowns bool) (plugin.DiffResult, error) {
+	oldHashes, newHashes resource.PropertyHashes, prov plugin.Provider, allowUnknowns bool) (plugin.DiffResult, error) {
 
 	// Workaround #1251: unexpected replaces.
 	//
@@ -579,8 +837,17 @@ func (sg *stepGenerator) diff(urn resource.URN, id resource.ID, oldInputs, oldOu
 	// of the resource to Diff, which includes calculated/output properties that may differ from those present
 	// in the input properties. This can cause unexpected diffs.
 	//
-	// For now, simply apply the legacy diffing behavior before deferring to the provider.
-	if oldInputs.DeepEquals(newInputs) {
+	// For now, simply apply the legacy diffing behavior before deferring to the provider. Comparing fingerprints
+	// first lets us recognize bit-identical inputs without hashing the contents of any assets they contain. The new
+	// inputs were fingerprinted along with their subtrees, and the old inputs' fingerprint is usually saved in the
+	// snapshot, so neither is encoded again here unless the snapshot predates fingerprints.
+	newFingerprint := newHashes.Root()
+	contract.Assertf(newFingerprint != "", "the new inputs of %s have not been fingerprinted", urn)
+	oldFingerprint := oldHashes.Root()
+	if oldFingerprint == "" {
+		oldFingerprint = oldInputs.Fingerprint().String()
+	}
+	if oldFingerprint == newFingerprint || oldInputs.DeepEquals(newInputs) {
 		return plugin.DiffResult{Changes: plugin.DiffNone}, nil
 	}
 
@@ -591,16 +858,256 @@ func (sg *stepGenerator) diff(urn resource.URN, id resource.ID, oldInputs, oldOu
 
 	// Grab the diff from the provider. At this point we know that there were changes to the Pulumi inputs, so if the
 	// provider returns an "unknown" diff result, pretend it returned "diffs exist".
-	diff, err := prov.Diff(urn, id, oldOutputs, newInputs, allowUnknowns)
+	diff, err := prov.Diff(urn, id, oldInputs, oldOutputs, newInputs, allowUnknowns)
 	if err != nil {
 		return diff, err
 	}
+	if diff.DetailedDiff != nil {
+		diff = applyDetailedDiff(diff)
+	}
 	if diff.Changes == plugin.DiffUnknown {
 		diff.Changes = plugin.DiffSome
 	}
 	return diff, nil
 }
 
+// applyDetailedDiff uses a provider's detailed diff to fill in whichever of the overall kind of change, the changed
+// keys, and the replacement keys the provider did not report itself, rather than leaving the engine to guess them.
+func applyDetailedDiff(diff plugin.DiffResult) plugin.DiffResult {
+	if diff.Changes == plugin.DiffUnknown {
+		if len(diff.DetailedDiff) == 0 {
+			diff.Changes = plugin.DiffNone
+		} else {
+			diff.Changes = plugin.DiffSome
+		}
+	}
+
+	paths := make([]string, 0, len(diff.DetailedDiff))
+	for path := range diff.DetailedDiff {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	inferChanged, inferReplace := len(diff.ChangedKeys) == 0, len(diff.ReplaceKeys) == 0
+	changed, replace := make(map[resource.PropertyKey]bool), make(map[resource.PropertyKey]bool)
+	for _, path := range paths {
+		key := rootPropertyKey(path)
+		if inferChanged && !changed[key] {
+			changed[key] = true
+			diff.ChangedKeys = append(diff.ChangedKeys, key)
+		}
+		if inferReplace && diff.DetailedDiff[path].Kind.IsReplace() && !replace[key] {
+			replace[key] = true
+			diff.ReplaceKeys = append(diff.ReplaceKeys, key)
+		}
+	}
+	return diff
+}
+
+// rootPropertyKey returns the top-level property key named by the given property path. Paths that cannot be parsed
+// are treated as a single key.
+func rootPropertyKey(path string) resource.PropertyKey {
+	if p, err := resource.ParsePropertyPath(path); err == nil && len(p) > 0 {
+		if key, ok := p[0].(string); ok {
+			return resource.PropertyKey(key)
+		}
+	}
+	return resource.PropertyKey(path)
+}
+
+// isTargeted returns true if the resource with the given URN may be operated upon by this plan.
+func (sg *stepGenerator) isTargeted(urn resource.URN) bool {
+	return sg.targets == nil || sg.targets[urn]
+}
+
+// checkUntargetedDependents ensures that deleting the given resource will not strand any untargeted resources that
+// depend upon it, since those resources are left in the snapshot.
+func (sg *stepGenerator) checkUntargetedDependents(res *resource.State) *result.Result {
+	if sg.targets == nil || sg.plan.depGraph == nil {
+		return nil
+	}
+	for _, dep := range sg.plan.depGraph.DependingOn(res) {
+		if !sg.isTargeted(dep.URN) && !dep.Delete {
+			sg.plan.Diag().Errorf(diag.GetUntargetedDependentError(res.URN), res.URN, dep.URN)
+			return result.Bail()
+		}
+	}
+	return nil
+}
+
+// computeTargets computes the full set of URNs targeted by a plan with the given options, or nil if the plan is not
+// restricted to a set of targets.
+func computeTargets(plan *Plan, opts Options) map[resource.URN]bool {
+	if len(opts.Targets) == 0 {
+		return nil
+	}
+
+	targets := make(map[resource.URN]bool)
+	for _, urn := range opts.Targets {
+		targets[urn] = true
+	}
+	for _, urn := range opts.ReplaceTargets {
+		targets[urn] = true
+	}
+	if plan.prev == nil || plan.depGraph == nil || (!opts.TargetDependencies && !opts.TargetDependents) {
+		return targets
+	}
+
+	// Expand the set of targets using the dependency information in the previous snapshot. Resources that do not yet
+	// exist have no recorded dependencies, so they contribute nothing beyond themselves.
+	expanded := make(map[resource.URN]bool)
+	if opts.TargetDependents {
+		for _, res := range plan.prev.Resources {
+			if targets[res.URN] {
+				for _, dep := range plan.depGraph.DependingOn(res) {
+					expanded[dep.URN] = true
+				}
+			}
+		}
+	}
+	if opts.TargetDependencies {
+		// Walk the snapshot in reverse so that each resource is visited after everything that depends on it.
+		closure := make(map[resource.URN]bool)
+		for urn := range targets {
+			closure[urn] = true
+		}
+		for i := len(plan.prev.Resources) - 1; i >= 0; i-- {
+			res := plan.prev.Resources[i]
+			if closure[res.URN] {
+				for dep := range plan.depGraph.DependenciesOf(res) {
+					closure[dep.URN] = true
+					expanded[dep.URN] = true
+				}
+			}
+		}
+	}
+	for urn := range expanded {
+		targets[urn] = true
+	}
+	return targets
+}
+
+// keptProtectedResources returns the resources of the old snapshot that must be left in place because the plan was
+// asked to exclude protected resources from deletion: the protected resources themselves, and everything that they
+// depend on, directly or indirectly. It returns nil unless the plan excludes protected resources.
+func (sg *stepGenerator) keptProtectedResources() graph.ResourceSet {
+	prev := sg.plan.prev
+	if !sg.opts.ExcludeProtected || sg.opts.ForceUnprotect || prev == nil {
+		return nil
+	}
+
+	// Resources are stored in dependency order, so walking them backwards visits each resource after everything that
+	// depends on it, by which time we know whether it must be kept.
+	kept := make(graph.ResourceSet)
+	for i := len(prev.Resources) - 1; i >= 0; i-- {
+		res := prev.Resources[i]
+		if res.Delete || (!res.Protect && !kept[res]) {
+			continue
+		}
+		kept[res] = true
+		for dep := range sg.plan.depGraph.DependenciesOf(res) {
+			kept[dep] = true
+		}
+	}
+	return kept
+}
+
+// canDelete returns true if the planner may delete the given resource. Protected resources may only be deleted if
+// the plan was asked to ignore protection, and guarded resources only if the plan was allowed to delete them;
+// otherwise, canDelete reports an error to the diagnostics sink.
+func (sg *stepGenerator) canDelete(res *resource.State) bool {
+	if res.Protect && !sg.opts.ForceUnprotect {
+		sg.plan.Diag().Errorf(diag.GetProtectedResourceDeleteError(res.URN), res.URN)
+		return false
+	}
+	return sg.plan.checkGuardrails(res)
+}
+
+// processIgnoreChanges returns a copy of the new inputs in which each of the given property paths has been set to its
+// value in the old inputs, or removed if it has no old value.
+func processIgnoreChanges(news, olds resource.PropertyMap, ignoreChanges []string) (resource.PropertyMap, error) {
+	ignored := news.DeepCopy()
+	for _, p := range ignoreChanges {
+		path, err := resource.ParsePropertyPath(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if old, has := path.Get(olds); has {
+			if !path.Set(ignored, old) {
+				return nil, errors.Errorf("cannot ignore changes to '%v': its parent is missing or has a different shape", p)
+			}
+		} else if _, has := path.Get(ignored); has && !path.Delete(ignored) {
+			return nil, errors.Errorf("cannot ignore changes to '%v': array elements may not be removed", p)
+		}
+	}
+	return ignored, nil
+}
+
+// autoNamedTypes returns the map from each resource type that may be auto-named to the property that holds its name.
+func (sg *stepGenerator) autoNamedTypes() map[tokens.Type]resource.PropertyKey {
+	if sg.opts.AutoNamedTypes != nil {
+		return sg.opts.AutoNamedTypes
+	}
+	return DefaultAutoNamedTypes
+}
+
+// processDefaults returns the keys of the checked inputs whose values were supplied by the provider because the
+// program left them unset. Any of these that the provider also defaulted in the old inputs is reset to its old value.
+func processDefaults(programInputs, inputs, olds resource.PropertyMap,
+	oldDefaults []resource.PropertyKey) (resource.PropertyMap, []resource.PropertyKey) {
+
+	wasDefault := make(map[resource.PropertyKey]bool)
+	for _, k := range oldDefaults {
+		wasDefault[k] = true
+	}
+
+	result, copied := inputs, false
+	var defaults []resource.PropertyKey
+	for _, k := range inputs.StableKeys() {
+		if inputs[k].IsNull() || programInputs.HasValue(k) {
+			continue
+		}
+		defaults = append(defaults, k)
+
+		if old, has := olds[k]; has && wasDefault[k] && !old.DeepEquals(inputs[k]) {
+			if !copied {
+				result, copied = inputs.Copy(), true
+			}
+			result[k] = old
+		}
+	}
+	return result, defaults
+}
+
+// processReplaceOnChanges returns the paths among the given property paths whose values differ between the old and
+// new inputs. The path `*` matches any change. The paths are returned as property keys so that they may be reported
+// alongside the keys that the provider reported as requiring a replacement.
+func processReplaceOnChanges(olds, news resource.PropertyMap, diff plugin.DiffResult,
+	replaceOnChanges []string) ([]resource.PropertyKey, error) {
+
+	var keys []resource.PropertyKey
+	for _, p := range replaceOnChanges {
+		if p == "*" {
+			if diff.Changes == plugin.DiffSome {
+				keys = append(keys, "*")
+			}
+			continue
+		}
+
+		path, err := resource.ParsePropertyPath(p)
+		if err != nil {
+			return nil, err
+		}
+		oldValue, hasOld := path.Get(olds)
+		newValue, hasNew := path.Get(news)
+		if hasOld != hasNew || hasOld && !oldValue.DeepEquals(newValue) {
+			keys = append(keys, resource.PropertyKey(path.String()))
+		}
+	}
+	return keys, nil
+}
+
 // issueCheckErrors prints any check errors to the diagnostics sink.
 func (sg *stepGenerator) issueCheckErrors(new *resource.State, urn resource.URN,
 	failures []plugin.CheckFailure) bool {
@@ -608,7 +1115,10 @@ func (sg *stepGenerator) issueCheckErrors(new *resource.State, urn resource.URN,
 		return false
 	}
 	inputs := new.Inputs
-	for _, failure := range failures {
+
+	// A single failure is reported on its own; multiple failures are grouped into a single diagnostic.
+	if len(failures) == 1 {
+		failure := failures[0]
 		if failure.Property != "" {
 			sg.plan.Diag().Errorf(diag.GetResourcePropertyInvalidValueError(urn),
 				new.Type, urn.Name(), failure.Property, inputs[failure.Property], failure.Reason)
@@ -616,7 +1126,27 @@ func (sg *stepGenerator) issueCheckErrors(new *resource.State, urn resource.URN,
 			sg.plan.Diag().Errorf(
 				diag.GetResourceInvalidError(urn), new.Type, urn.Name(), failure.Reason)
 		}
+		return true
+	}
+
+	var errs diag.StructuredErrors
+	for _, failure := range failures {
+		if failure.Property != "" {
+			errs = append(errs, &diag.StructuredError{
+				ID:      diag.GetResourcePropertyInvalidValueError(urn).ID,
+				URN:     urn,
+				Path:    string(failure.Property),
+				Message: fmt.Sprintf("value %v has a problem: %v", inputs[failure.Property], failure.Reason),
+			})
+		} else {
+			errs = append(errs, &diag.StructuredError{
+				ID:      diag.GetResourceInvalidError(urn).ID,
+				URN:     urn,
+				Message: failure.Reason,
+			})
+		}
 	}
+	sg.plan.Diag().Errorf(diag.GetResourceInvalidPropertiesError(urn), new.Type, urn.Name(), len(errs), errs.Details())
 	return true
 }
 
@@ -651,7 +1181,9 @@ type dependentReplace struct {
 	keys []resource.PropertyKey
 }
 
-func (sg *stepGenerator) calculateDependentReplacements(root *resource.State) ([]dependentReplace, error) {
+func (sg *stepGenerator) calculateDependentReplacements(
+	root, replacement *resource.State) ([]dependentReplace, error) {
+
 	// We need to compute the set of resources that may be replaced by a change to the resource under consideration.
 	// We do this by taking the complete set of transitive dependents on the resource under consideration and
 	// removing any resources that would not be replaced by changes to their dependencies. We determine whether or not
@@ -672,6 +1204,28 @@ func (sg *stepGenerator) calculateDependentReplacements(root *resource.State) ([
 	// such that a change to B does not actually influence any of B's input properties.  More commonly, the edge from B
 	// to A may be due to a property from A being used as the input to a property of B that does not require B to be
 	// replaced upon a change. In these cases, neither B nor D would need to be deleted before A could be deleted.
+	//
+	// Finally, if the language host recorded which of A's outputs a property of B read, and the replacement for A is
+	// expected to keep all of those outputs, then that property of B will not change at all. A replacement is expected
+	// to keep an output that echoes an input whose value is the same in the replacement. Its ID and any outputs that
+	// are computed by its provider are expected to change.
+	keeps := func(path resource.PropertyPath) bool {
+		// Paths are validated as they are registered, but a checkpoint may still hold a path that does not begin with
+		// a property name. Such a read is not known to be kept.
+		name, isKey := path[0].(string)
+		if !isKey || name == "id" {
+			return false
+		}
+		key := resource.PropertyKey(name)
+		input, hasInput := root.Inputs[key]
+		output, hasOutput := root.Outputs[key]
+		if !hasInput || !hasOutput || !input.DeepEquals(output) {
+			return false
+		}
+		newInput, hasNewInput := replacement.Inputs[key]
+		return hasNewInput && newInput.DeepEquals(input)
+	}
+
 	var toReplace []dependentReplace
 	replaceSet := map[resource.URN]bool{root.URN: true}
 
@@ -697,10 +1251,16 @@ func (sg *stepGenerator) calculateDependentReplacements(root *resource.State) ([
 		hasDependencyInReplaceSet, inputsForDiff := false, resource.PropertyMap{}
 		for pk, pv := range r.Inputs {
 			for _, propertyDep := range r.PropertyDependencies[pk] {
-				if replaceSet[propertyDep] {
-					hasDependencyInReplaceSet = true
-					pv = resource.MakeComputed(resource.NewStringProperty("<unknown>"))
+				if !replaceSet[propertyDep] {
+					continue
+				}
+				if propertyDep == root.URN && r.PropertyReads.ReadsOnly(pk, root.URN, keeps) {
+					logging.V(7).Infof("Planner decided that '%v' of '%v' is unaffected by the replacement of '%v'",
+						pk, r.URN, root.URN)
+					continue
 				}
+				hasDependencyInReplaceSet = true
+				pv = resource.MakeComputed(resource.NewStringProperty("<unknown>"))
 			}
 			inputsForDiff[pk] = pv
 		}
@@ -720,7 +1280,7 @@ func (sg *stepGenerator) calculateDependentReplacements(root *resource.State) ([
 		contract.Assert(prov != nil)
 
 		// Call the provider's `Diff` method and return.
-		diff, err := prov.Diff(r.URN, r.ID, r.Outputs, inputsForDiff, true)
+		diff, err := prov.Diff(r.URN, r.ID, r.Inputs, r.Outputs, inputsForDiff, true)
 		if err != nil {
 			return false, nil, err
 		}
@@ -744,6 +1304,11 @@ func (sg *stepGenerator) calculateDependentReplacements(root *resource.State) ([
 
 // newStepGenerator creates a new step generator that operates on the given plan.
 func newStepGenerator(plan *Plan, opts Options) *stepGenerator {
+	replaceTargets := make(map[resource.URN]bool)
+	for _, urn := range opts.ReplaceTargets {
+		replaceTargets[urn] = true
+	}
+
 	return &stepGenerator{
 		plan:                 plan,
 		opts:                 opts,
@@ -755,6 +1320,32 @@ func newStepGenerator(plan *Plan, opts Options) *stepGenerator {
 		updates:              make(map[resource.URN]bool),
 		deletes:              make(map[resource.URN]bool),
 		pendingDeletes:       make(map[*resource.State]bool),
+		aliased:              make(map[resource.URN]resource.URN),
+		targets:              computeTargets(plan, opts),
+		replaceTargets:       replaceTargets,
 		dependentReplaceKeys: make(map[resource.URN][]resource.PropertyKey),
+		parents:              make(map[resource.URN]resource.URN),
+		eventURNs:            make(map[RegisterResourceEvent]resource.URN),
+	}
+}
+
+// loggable returns the given properties of the resource with the given URN as they may be logged, redacted according
+// to the plan's redaction policy.
+func (sg *stepGenerator) loggable(urn resource.URN, props resource.PropertyMap) resource.PropertyMap {
+	var policy resource.RedactionPolicy
+	if sg.plan.ctx != nil {
+		policy = sg.plan.ctx.Redaction
+	}
+	return props.Redacted(urn.Type(), policy)
+}
+
+// generateEventURN returns the URN of the resource associated with the given event. Registrations that have already
+// been transformed use the URN of their transformed goal.
+func (sg *stepGenerator) generateEventURN(event SourceEvent) resource.URN {
+	if reg, ok := event.(RegisterResourceEvent); ok {
+		if urn, has := sg.eventURNs[reg]; has {
+			return urn
+		}
 	}
+	return sg.plan.generateEventURN(event)
 }
@@ -0,0 +1,116 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// DefaultTaggableTypes maps each resource type that supports tags to the property that holds its tags. Default tags are
+// merged into these properties unless a plan's options supply a different set of taggable types.
+var DefaultTaggableTypes = map[tokens.Type]resource.PropertyKey{
+	"aws:dynamodb/table:Table":               "tags",
+	"aws:ec2/instance:Instance":              "tags",
+	"aws:ec2/securityGroup:SecurityGroup":    "tags",
+	"aws:ec2/subnet:Subnet":                  "tags",
+	"aws:ec2/vpc:Vpc":                        "tags",
+	"aws:iam/role:Role":                      "tags",
+	"aws:kinesis/stream:Stream":              "tags",
+	"aws:lambda/function:Function":           "tags",
+	"aws:s3/bucket:Bucket":                   "tags",
+	"aws:sns/topic:Topic":                    "tags",
+	"aws:sqs/queue:Queue":                    "tags",
+	"azure:core/resourceGroup:ResourceGroup": "tags",
+	"azure:storage/account:Account":          "tags",
+	"gcp:compute/instance:Instance":          "labels",
+	"gcp:storage/bucket:Bucket":              "labels",
+}
+
+// injectDefaultTags merges the given default tags into the tags property of a taggable resource. Tags set by the
+// program take precedence over default tags. It returns the resulting goal, the tags property, and the keys of the
+// tags that were injected; if no tags were injected, the goal is returned unchanged.
+func injectDefaultTags(goal *resource.Goal, tags map[string]string,
+	types map[tokens.Type]resource.PropertyKey) (*resource.Goal, resource.PropertyKey, []resource.PropertyKey) {
+
+	key, taggable := types[goal.Type]
+	if !taggable || len(tags) == 0 {
+		return goal, "", nil
+	}
+
+	// Leave computed or otherwise unrecognized tags alone.
+	var programTags resource.PropertyMap
+	if v, has := goal.Properties[key]; has && !v.IsNull() {
+		if !v.IsObject() {
+			return goal, "", nil
+		}
+		programTags = v.ObjectValue()
+	}
+
+	merged := programTags.Copy()
+	var injected []resource.PropertyKey
+	for k, v := range tags {
+		if pk := resource.PropertyKey(k); !programTags.HasKey(pk) {
+			merged[pk] = resource.NewStringProperty(v)
+			injected = append(injected, pk)
+		}
+	}
+	if len(injected) == 0 {
+		return goal, "", nil
+	}
+
+	result := *goal
+	result.Properties = goal.Properties.Copy()
+	result.Properties[key] = resource.NewObjectProperty(merged)
+	return &result, key, injected
+}
+
+// suppressDefaultTagChanges returns the old inputs if the only differences between them and the new inputs are in the
+// injected default tags, so that changing a stack's default tags does not by itself cause resources to be updated.
+// Otherwise, the new inputs are returned unchanged.
+func suppressDefaultTagChanges(olds, news resource.PropertyMap, key resource.PropertyKey,
+	injected []resource.PropertyKey) resource.PropertyMap {
+
+	v, has := news[key]
+	if !has || !v.IsObject() {
+		return news
+	}
+
+	var oldTags resource.PropertyMap
+	if ov, has := olds[key]; has && ov.IsObject() {
+		oldTags = ov.ObjectValue()
+	}
+
+	// Revert each injected tag to its old value (or absence) and see if anything else changed.
+	reverted := v.ObjectValue().Copy()
+	for _, k := range injected {
+		if ov, has := oldTags[k]; has {
+			reverted[k] = ov
+		} else {
+			delete(reverted, k)
+		}
+	}
+	candidate := news.Copy()
+	if len(reverted) == 0 && !olds.HasKey(key) {
+		delete(candidate, key)
+	} else {
+		candidate[key] = resource.NewObjectProperty(reverted)
+	}
+
+	if candidate.DeepEquals(olds) {
+		return olds
+	}
+	return news
+}
@@ -0,0 +1,77 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestInjectDefaultTags(t *testing.T) {
+	types := map[tokens.Type]resource.PropertyKey{"pkgA:m:typA": "tags"}
+	tags := map[string]string{"env": "dev", "owner": "ops"}
+
+	// Tags set by the program take precedence over default tags.
+	goal := resource.NewGoal("pkgA:m:typA", "resA", true, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"env": "prod"},
+	}), "", false, nil, "", nil, nil, false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil)
+	injected, key, keys := injectDefaultTags(goal, tags, types)
+	assert.Equal(t, resource.PropertyKey("tags"), key)
+	assert.Equal(t, []resource.PropertyKey{"owner"}, keys)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"env": "prod", "owner": "ops"},
+	}), injected.Properties)
+
+	// The program's goal is left untouched.
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"env": "prod"},
+	}), goal.Properties)
+
+	// Resources that are not taggable are left alone.
+	other := resource.NewGoal("pkgA:m:typB", "resB", true, resource.PropertyMap{}, "", false, nil, "", nil, nil,
+		false, false, nil, nil, nil, nil, resource.CustomTimeouts{}, nil)
+	injected, _, keys = injectDefaultTags(other, tags, types)
+	assert.Equal(t, other, injected)
+	assert.Empty(t, keys)
+}
+
+func TestSuppressDefaultTagChanges(t *testing.T) {
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":  "bar",
+		"tags": map[string]interface{}{"env": "dev"},
+	})
+
+	// A change to an injected tag alone is suppressed.
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":  "bar",
+		"tags": map[string]interface{}{"env": "prod"},
+	})
+	assert.Equal(t, olds, suppressDefaultTagChanges(olds, news, "tags", []resource.PropertyKey{"env"}))
+
+	// So is the addition of injected tags to a resource that had none.
+	untagged := resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"})
+	assert.Equal(t, untagged, suppressDefaultTagChanges(untagged, news, "tags", []resource.PropertyKey{"env"}))
+
+	// Any other change is not.
+	news = resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo":  "baz",
+		"tags": map[string]interface{}{"env": "prod"},
+	})
+	assert.Equal(t, news, suppressDefaultTagChanges(olds, news, "tags", []resource.PropertyKey{"env"}))
+}
@@ -15,6 +15,10 @@
 package deploy
 
 import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/tokens"
 )
@@ -45,3 +49,152 @@ func (t *Target) GetPackageConfig(pkg tokens.Package) (map[config.Key]string, er
 	}
 	return result, nil
 }
+
+// defaultTagsKey is the configuration key that holds a stack's default tags as a JSON object of tag names to values.
+var defaultTagsKey = config.MustMakeKey("pulumi", "tags")
+
+// GetDefaultTags returns the default tags configured for this target, if any.
+func (t *Target) GetDefaultTags() (map[string]string, error) {
+	c, has := t.Config[defaultTagsKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(t.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags map[string]string
+	if err = json.Unmarshal([]byte(v), &tags); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON object of tag names to string values", defaultTagsKey)
+	}
+	return tags, nil
+}
+
+// autoNamingKey is the configuration key that holds a stack's auto-naming configuration as a JSON object.
+var autoNamingKey = config.MustMakeKey("pulumi", "autonaming")
+
+// GetAutoNaming returns the auto-naming configuration for this target, or nil if auto-naming is not enabled.
+func (t *Target) GetAutoNaming() (*AutoNamingConfig, error) {
+	c, has := t.Config[autoNamingKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(t.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var autoNaming AutoNamingConfig
+	if err = json.Unmarshal([]byte(v), &autoNaming); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON object describing an auto-naming strategy", autoNamingKey)
+	}
+	return &autoNaming, nil
+}
+
+// hooksKey is the configuration key that holds a stack's resource lifecycle hooks as a JSON array.
+var hooksKey = config.MustMakeKey("pulumi", "hooks")
+
+// GetHooks returns the resource lifecycle hooks configured for this target, if any.
+func (t *Target) GetHooks() ([]Hook, error) {
+	c, has := t.Config[hooksKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(t.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var hooks []Hook
+	if err = json.Unmarshal([]byte(v), &hooks); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON array of hooks", hooksKey)
+	}
+	for i := range hooks {
+		if err = hooks[i].validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid %v", hooksKey)
+		}
+	}
+	return hooks, nil
+}
+
+// instancesKey is the configuration key that holds a stack's fan-out instances as a JSON array.
+var instancesKey = config.MustMakeKey("pulumi", "instances")
+
+// GetInstances returns the fan-out instances configured for this target, if any.
+func (t *Target) GetInstances() ([]FanOutInstance, error) {
+	c, has := t.Config[instancesKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(t.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []FanOutInstance
+	if err = json.Unmarshal([]byte(v), &instances); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON array of instances", instancesKey)
+	}
+	names := make(map[string]bool)
+	for _, inst := range instances {
+		if err = inst.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid %v", instancesKey)
+		}
+		if names[inst.Name] {
+			return nil, errors.Errorf("invalid %v: duplicate instance '%s'", instancesKey, inst.Name)
+		}
+		names[inst.Name] = true
+	}
+	return instances, nil
+}
+
+// rateLimitsKey is the configuration key that holds a stack's provider rate limits as a JSON object.
+var rateLimitsKey = config.MustMakeKey("pulumi", "rateLimits")
+
+// GetRateLimits returns the provider rate limits configured for this target, if any.
+func (t *Target) GetRateLimits() (*RateLimits, error) {
+	c, has := t.Config[rateLimitsKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(t.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits RateLimits
+	if err = json.Unmarshal([]byte(v), &limits); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON object of provider and type rate limits", rateLimitsKey)
+	}
+	if err = limits.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid %v", rateLimitsKey)
+	}
+	return &limits, nil
+}
+
+// guardrailsKey is the configuration key that holds a stack's destructive-change guardrails as a JSON array.
+var guardrailsKey = config.MustMakeKey("pulumi", "guardrails")
+
+// GetGuardrails returns the guardrails configured for this target, if any.
+func (t *Target) GetGuardrails() ([]Guardrail, error) {
+	c, has := t.Config[guardrailsKey]
+	if !has {
+		return nil, nil
+	}
+	v, err := c.Value(t.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	var guardrails []Guardrail
+	if err = json.Unmarshal([]byte(v), &guardrails); err != nil {
+		return nil, errors.Wrapf(err, "%v must be a JSON array of guardrails", guardrailsKey)
+	}
+	for i := range guardrails {
+		if err = guardrails[i].validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid %v", guardrailsKey)
+		}
+	}
+	return guardrails, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// OperationTimeoutError is returned by a step whose provider operation did not complete within the resource's custom
+// timeout. Because the provider may still be performing the operation, its outcome is unknown: the engine leaves the
+// operation recorded as pending in the snapshot rather than assuming that it either succeeded or failed.
+type OperationTimeoutError struct {
+	URN     resource.URN           // the URN of the resource the operation was applied to.
+	Op      resource.OperationType // the kind of operation that timed out.
+	Timeout time.Duration          // the timeout that expired.
+}
+
+func (e *OperationTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %v while %s %s; the outcome of the operation is unknown, so it has been "+
+		"recorded as pending and the resource must be refreshed or its pending operation resolved before it is "+
+		"updated again", e.Timeout, e.Op, e.URN)
+}
+
+// IsOperationTimeoutError returns true if the given error is an OperationTimeoutError.
+func IsOperationTimeoutError(err error) bool {
+	_, ok := err.(*OperationTimeoutError)
+	return ok
+}
+
+// withTimeout runs a provider operation on the given resource, failing with an OperationTimeoutError if the operation
+// does not complete within the resource's custom timeout for that kind of operation. An operation that times out is
+// abandoned rather than canceled: any results that it eventually produces are discarded.
+func withTimeout(state *resource.State, op resource.OperationType,
+	fn func() (resource.Status, error)) (resource.Status, error) {
+
+	timeout := state.CustomTimeouts.Timeout(op)
+	if timeout == 0 {
+		return fn()
+	}
+
+	type result struct {
+		status resource.Status
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		status, err := fn()
+		done <- result{status: status, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.status, r.err
+	case <-timer.C:
+		return resource.StatusUnknown, &OperationTimeoutError{URN: state.URN, Op: op, Timeout: timeout}
+	}
+}
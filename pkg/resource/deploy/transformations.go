@@ -0,0 +1,77 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// A ResourceTransformation may modify the goal state of a resource before the planner sees it. It receives a copy of
+// the goal registered by the program, and may change the resource's name, properties, and options (e.g. its parent,
+// protection, dependencies, or provider) in place. It may not change the resource's type or whether the resource is
+// custom. Returning an error fails the resource's registration.
+type ResourceTransformation func(goal *resource.Goal) error
+
+// Transformations is the set of resource transformations to apply during planning.
+type Transformations struct {
+	// Stack is the set of transformations to apply to every resource in the stack.
+	Stack []ResourceTransformation
+	// Parents maps a parent's URN to the set of transformations to apply to all of its descendants.
+	Parents map[resource.URN][]ResourceTransformation
+}
+
+// apply runs the applicable transformations against the given goal and returns the transformed goal. The
+// transformations registered for the resource's parent run first, followed by those for each of its more distant
+// ancestors, and finally those for the stack. The given parents map records the parent of each registered resource.
+func (ts *Transformations) apply(goal *resource.Goal, parents map[resource.URN]resource.URN) (*resource.Goal, error) {
+	if ts == nil || len(ts.Stack) == 0 && len(ts.Parents) == 0 {
+		return goal, nil
+	}
+
+	var transformations []ResourceTransformation
+	for parent := goal.Parent; parent != ""; parent = parents[parent] {
+		transformations = append(transformations, ts.Parents[parent]...)
+	}
+	transformations = append(transformations, ts.Stack...)
+	if len(transformations) == 0 {
+		return goal, nil
+	}
+
+	// Transform a copy of the goal so that the program's registration is left untouched.
+	transformed := *goal
+	transformed.Properties = goal.Properties.DeepCopy()
+	transformed.Dependencies = append([]resource.URN(nil), goal.Dependencies...)
+	transformed.Aliases = append([]resource.URN(nil), goal.Aliases...)
+	transformed.IgnoreChanges = append([]string(nil), goal.IgnoreChanges...)
+	transformed.ReplaceOnChanges = append([]string(nil), goal.ReplaceOnChanges...)
+	transformed.ReadyConditions = append([]string(nil), goal.ReadyConditions...)
+	if goal.PropertyDependencies != nil {
+		transformed.PropertyDependencies = make(map[resource.PropertyKey][]resource.URN)
+		for k, deps := range goal.PropertyDependencies {
+			transformed.PropertyDependencies[k] = append([]resource.URN(nil), deps...)
+		}
+	}
+	for _, transform := range transformations {
+		if err := transform(&transformed); err != nil {
+			return nil, errors.Wrapf(err, "transforming resource '%v'", goal.Name)
+		}
+	}
+	if transformed.Type != goal.Type || transformed.Custom != goal.Custom {
+		return nil, errors.Errorf("a transformation may not change the type of resource '%v'", goal.Name)
+	}
+	return &transformed, nil
+}
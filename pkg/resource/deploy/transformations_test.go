@@ -0,0 +1,54 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestTransformationsLeaveGoalUntouched(t *testing.T) {
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"env": "dev"},
+		"list": []interface{}{"a"},
+	})
+	goal := resource.NewGoal("pkgA:m:typA", "resA", true, props, "", false, []resource.URN{"urnB"}, "", nil,
+		map[resource.PropertyKey][]resource.URN{"list": {"urnB"}}, false, false, nil, nil, nil, nil,
+		resource.CustomTimeouts{}, nil)
+	original := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"env": "dev"},
+		"list": []interface{}{"a"},
+	})
+
+	// Transformations that modify nested values and options in place must not modify the program's goal.
+	ts := &Transformations{Stack: []ResourceTransformation{func(goal *resource.Goal) error {
+		goal.Properties["tags"].ObjectValue()["env"] = resource.NewStringProperty("prod")
+		goal.Properties["list"].ArrayValue()[0] = resource.NewStringProperty("b")
+		goal.Dependencies[0] = "urnC"
+		goal.PropertyDependencies["list"][0] = "urnC"
+		return nil
+	}}}
+	transformed, err := ts.apply(goal, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", transformed.Properties["tags"].ObjectValue()["env"].StringValue())
+	assert.Equal(t, []resource.URN{"urnC"}, transformed.Dependencies)
+
+	assert.Equal(t, original, goal.Properties)
+	assert.Equal(t, []resource.URN{"urnB"}, goal.Dependencies)
+	assert.Equal(t, []resource.URN{"urnB"}, goal.PropertyDependencies["list"])
+}
@@ -0,0 +1,38 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// DefaultUnorderedProperties maps resource types to the paths of their array-valued properties whose element order is
+// insignificant to the cloud provider.  These arrays are treated as sets unless a plan's options supply a different
+// set of unordered properties.
+var DefaultUnorderedProperties = map[tokens.Type][]resource.PropertyPath{
+	"aws:ec2/instance:Instance":           {{"securityGroups"}, {"vpcSecurityGroupIds"}},
+	"aws:ec2/securityGroup:SecurityGroup": {{"egress"}, {"ingress"}},
+	"aws:lambda/function:Function":        {{"layers"}},
+	"gcp:compute/instance:Instance":       {{"tags"}},
+}
+
+// unorderedProperties returns the paths of the given resource type's array-valued properties that are treated as sets.
+func (sg *stepGenerator) unorderedProperties(t tokens.Type) []resource.PropertyPath {
+	if sg.opts.UnorderedProperties != nil {
+		return sg.opts.UnorderedProperties[t]
+	}
+	return DefaultUnorderedProperties[t]
+}
@@ -39,3 +39,13 @@ type ResourceProtectedError struct {
 func (ResourceProtectedError) Error() string {
 	return "Can't delete protected resource"
 }
+
+// ResourceAlreadyExistsError is returned by RenameResource if a resource with the requested URN already exists in the
+// snapshot.
+type ResourceAlreadyExistsError struct {
+	URN resource.URN
+}
+
+func (r ResourceAlreadyExistsError) Error() string {
+	return fmt.Sprintf("A resource with URN %q already exists", r.URN)
+}
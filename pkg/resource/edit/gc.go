@@ -0,0 +1,106 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// UnreachableResources returns the resources in the snapshot that cannot be reached from the root stack resource, in
+// snapshot order. A resource is reachable if it is the root stack resource, if its parent is reachable, or if a
+// reachable resource refers to it as its parent, a dependency, or its provider. Unreachable resources are typically
+// left behind by interrupted updates or by hand edits to the state. If the snapshot has no root stack resource, no
+// resources are considered unreachable, since there is nothing to measure reachability from.
+func UnreachableResources(snap *deploy.Snapshot) []*resource.State {
+	contract.Require(snap != nil, "snap")
+
+	byURN := make(map[resource.URN][]*resource.State)
+	children := make(map[resource.URN][]*resource.State)
+	var worklist []*resource.State
+	for _, res := range snap.Resources {
+		byURN[res.URN] = append(byURN[res.URN], res)
+		if res.Parent != "" {
+			children[res.Parent] = append(children[res.Parent], res)
+		}
+		if res.Type == resource.RootStackType {
+			worklist = append(worklist, res)
+		}
+	}
+	if len(worklist) == 0 {
+		return nil
+	}
+
+	reachable := make(map[*resource.State]bool)
+	for len(worklist) > 0 {
+		res := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		if reachable[res] {
+			continue
+		}
+		reachable[res] = true
+
+		refs := append([]resource.URN{res.Parent}, res.Dependencies...)
+		for _, deps := range res.PropertyDependencies {
+			refs = append(refs, deps...)
+		}
+		if res.Provider != "" {
+			if ref, err := providers.ParseReference(res.Provider); err == nil {
+				refs = append(refs, ref.URN())
+			}
+		}
+		for _, urn := range refs {
+			worklist = append(worklist, byURN[urn]...)
+		}
+		worklist = append(worklist, children[res.URN]...)
+	}
+
+	var unreachable []*resource.State
+	for _, res := range snap.Resources {
+		if !reachable[res] {
+			unreachable = append(unreachable, res)
+		}
+	}
+	return unreachable
+}
+
+// DeleteResources removes the given resources from the snapshot, along with any pending operations on them. Unlike
+// DeleteResource, it does not check whether other resources depend on or descend from the condemned resources, so it
+// should only be used to remove a set of resources that is closed under those relationships, such as the set returned
+// by UnreachableResources.
+func DeleteResources(snap *deploy.Snapshot, condemned []*resource.State) {
+	contract.Require(snap != nil, "snap")
+
+	remove := make(map[*resource.State]bool)
+	for _, res := range condemned {
+		remove[res] = true
+	}
+
+	var resources []*resource.State
+	for _, res := range snap.Resources {
+		if !remove[res] {
+			resources = append(resources, res)
+		}
+	}
+	var ops []resource.Operation
+	for _, op := range snap.PendingOperations {
+		if !remove[op.Resource] {
+			ops = append(ops, op)
+		}
+	}
+	snap.Resources, snap.PendingOperations = resources, ops
+}
@@ -0,0 +1,65 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestUnreachableResources(t *testing.T) {
+	root := NewResource("root", nil)
+	root.Type = resource.RootStackType
+
+	// The provider and the dependency are reachable only through the resources that refer to them.
+	provider := NewProviderResource("a", "default", "0")
+	dependency := NewResource("dependency", nil)
+	child := NewResource("child", provider, dependency.URN)
+	child.Parent = root.URN
+	grandchild := NewResource("grandchild", provider)
+	grandchild.Parent = child.URN
+
+	// An orphan with children of its own, and an orphaned provider that only the orphans use.
+	orphanProvider := NewProviderResource("b", "orphaned", "1")
+	orphan := NewResource("orphan", orphanProvider)
+	orphanChild := NewResource("orphan-child", orphanProvider)
+	orphanChild.Parent = orphan.URN
+
+	snap := NewSnapshot([]*resource.State{
+		root, provider, dependency, child, grandchild, orphanProvider, orphan, orphanChild,
+	})
+	snap.PendingOperations = []resource.Operation{
+		resource.NewOperation(child, resource.OperationTypeUpdating),
+		resource.NewOperation(orphanChild, resource.OperationTypeCreating),
+	}
+
+	unreachable := UnreachableResources(snap)
+	assert.Equal(t, []*resource.State{orphanProvider, orphan, orphanChild}, unreachable)
+
+	DeleteResources(snap, unreachable)
+	assert.Equal(t, []*resource.State{root, provider, dependency, child, grandchild}, snap.Resources)
+	assert.Len(t, snap.PendingOperations, 1)
+	assert.Equal(t, child, snap.PendingOperations[0].Resource)
+	assert.NoError(t, snap.VerifyIntegrity())
+	assert.Empty(t, UnreachableResources(snap))
+}
+
+func TestUnreachableResourcesWithoutRoot(t *testing.T) {
+	snap := NewSnapshot([]*resource.State{NewResource("a", nil), NewResource("b", nil)})
+	assert.Empty(t, UnreachableResources(snap))
+}
@@ -15,8 +15,13 @@
 package edit
 
 import (
+	"strings"
+
+	"github.com/pkg/errors"
+
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/resource/graph"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 )
@@ -75,6 +80,113 @@ func UnprotectResource(_ *deploy.Snapshot, res *resource.State) error {
 	return nil
 }
 
+// RenameResource changes the URN of a given resource in the snapshot. Only the name portion of the URN may change; the
+// stack, project, and qualified type must be the same as the resource's current URN. Every reference to the old URN
+// held by other resources in the snapshot (parents, dependencies, property dependencies, and provider references) is
+// rewritten to refer to the new URN. If a resource with the new URN already exists, RenameResource will return an error
+// instance of `ResourceAlreadyExistsError`.
+func RenameResource(snapshot *deploy.Snapshot, res *resource.State, newURN resource.URN) error {
+	contract.Require(snapshot != nil, "snapshot")
+	contract.Require(res != nil, "state")
+
+	oldURN := res.URN
+	if !strings.HasPrefix(string(newURN), resource.URNPrefix) ||
+		len(strings.Split(newURN.URNName(), resource.URNNameDelimiter)) != 4 {
+		return errors.Errorf("%q is not a valid resource URN", newURN)
+	}
+	if newURN.Stack() != oldURN.Stack() || newURN.Project() != oldURN.Project() ||
+		newURN.QualifiedType() != oldURN.QualifiedType() {
+		return errors.Errorf("%q must have the same stack, project, and type as %q", newURN, oldURN)
+	}
+	if newURN == oldURN {
+		return nil
+	}
+	for _, other := range snapshot.Resources {
+		if other.URN == newURN && !other.Delete {
+			return ResourceAlreadyExistsError{URN: newURN}
+		}
+	}
+
+	// Provider references include the provider's URN, so resources that refer to a renamed provider must be updated.
+	var oldRef, newRef string
+	if providers.IsProviderType(res.Type) {
+		ref, err := providers.NewReference(oldURN, res.ID)
+		if err != nil {
+			return err
+		}
+		oldRef = ref.String()
+		ref, err = providers.NewReference(newURN, res.ID)
+		contract.AssertNoError(err)
+		newRef = ref.String()
+	}
+
+	replace := func(urns []resource.URN) {
+		for i, urn := range urns {
+			if urn == oldURN {
+				urns[i] = newURN
+			}
+		}
+	}
+	for _, other := range snapshot.Resources {
+		if other.Parent == oldURN {
+			other.Parent = newURN
+		}
+		replace(other.Dependencies)
+		for _, deps := range other.PropertyDependencies {
+			replace(deps)
+		}
+		if oldRef != "" && other.Provider == oldRef {
+			other.Provider = newRef
+		}
+
+		// Properties may also refer to the resource by its URN (or, for a provider, by its provider reference).
+		renames := map[string]string{string(oldURN): string(newURN)}
+		if oldRef != "" {
+			renames[oldRef] = newRef
+		}
+		if renameReferences(other.Inputs, renames) {
+			// The fingerprints of the inputs no longer match them.
+			other.InputHashes = nil
+		}
+		renameReferences(other.Outputs, renames)
+	}
+	res.URN = newURN
+	return nil
+}
+
+// renameReferences replaces, in place, each string within the given properties that is a key of renames with its
+// corresponding value, and returns true if any were replaced.
+func renameReferences(props resource.PropertyMap, renames map[string]string) bool {
+	renamed := false
+	for k, v := range props {
+		v, changed := renameReferencesIn(v, renames)
+		props[k], renamed = v, renamed || changed
+	}
+	return renamed
+}
+
+func renameReferencesIn(v resource.PropertyValue, renames map[string]string) (resource.PropertyValue, bool) {
+	switch {
+	case v.IsString():
+		if renamed, has := renames[v.StringValue()]; has {
+			return resource.NewStringProperty(renamed), true
+		}
+	case v.IsArray():
+		renamed, arr := false, v.ArrayValue()
+		for i, elem := range arr {
+			elem, changed := renameReferencesIn(elem, renames)
+			arr[i], renamed = elem, renamed || changed
+		}
+		return v, renamed
+	case v.IsObject():
+		return v, renameReferences(v.ObjectValue(), renames)
+	case v.IsSecret():
+		elem, renamed := renameReferencesIn(v.SecretValue().Element, renames)
+		return resource.MakeSecret(elem), renamed
+	}
+	return v, false
+}
+
 // LocateResource returns all resources in the given shapshot that have the given URN.
 func LocateResource(snap *deploy.Snapshot, urn resource.URN) []*resource.State {
 	contract.Require(snap != nil, "snap")
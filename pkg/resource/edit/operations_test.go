@@ -258,3 +258,87 @@ func TestLocateResourceExact(t *testing.T) {
 	assert.Len(t, resList, 1)
 	assert.Contains(t, resList, a)
 }
+
+func TestRenameResource(t *testing.T) {
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	b.Parent = a.URN
+	b.PropertyDependencies = map[resource.PropertyKey][]resource.URN{"foo": {a.URN}}
+	b.Inputs["foo"] = resource.NewStringProperty(string(a.URN))
+	b.Outputs["nested"] = resource.NewObjectProperty(resource.PropertyMap{
+		"urns": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewStringProperty(string(a.URN)),
+			resource.MakeSecret(resource.NewStringProperty(string(a.URN))),
+		}),
+	})
+	b.InputHashes = b.Inputs.SubtreeHashes(0)
+	c := NewResource("c", pA)
+	c.Inputs["foo"] = resource.NewStringProperty("a")
+	c.InputHashes = c.Inputs.SubtreeHashes(0)
+	snap := NewSnapshot([]*resource.State{
+		pA,
+		a,
+		b,
+		c,
+	})
+
+	newURN := resource.NewURN("test", "test", "", a.Type, "a2")
+	err := RenameResource(snap, a, newURN)
+	assert.NoError(t, err)
+	assert.Equal(t, newURN, a.URN)
+	assert.Equal(t, newURN, b.Parent)
+	assert.Equal(t, []resource.URN{newURN}, b.Dependencies)
+	assert.Equal(t, []resource.URN{newURN}, b.PropertyDependencies["foo"])
+
+	// URNs within other resources' properties are renamed as well, but other strings are left alone.
+	assert.Equal(t, resource.NewStringProperty(string(newURN)), b.Inputs["foo"])
+	urns := b.Outputs["nested"].ObjectValue()["urns"].ArrayValue()
+	assert.Equal(t, resource.NewStringProperty(string(newURN)), urns[0])
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty(string(newURN))), urns[1])
+	assert.Equal(t, resource.NewStringProperty("a"), c.Inputs["foo"])
+
+	// The fingerprints of inputs that were renamed are dropped, since they no longer match.
+	assert.Nil(t, b.InputHashes)
+	assert.Equal(t, c.Inputs.SubtreeHashes(0), c.InputHashes)
+}
+
+func TestRenameProviderResource(t *testing.T) {
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	a.Inputs["provider"] = resource.NewStringProperty(a.Provider)
+	snap := NewSnapshot([]*resource.State{
+		pA,
+		a,
+	})
+
+	newURN := resource.NewURN("test", "test", "", pA.Type, "p2")
+	err := RenameResource(snap, pA, newURN)
+	assert.NoError(t, err)
+	ref, err := providers.ParseReference(a.Provider)
+	assert.NoError(t, err)
+	assert.Equal(t, newURN, ref.URN())
+	assert.Equal(t, resource.NewStringProperty(a.Provider), a.Inputs["provider"])
+}
+
+func TestFailedRenameResource(t *testing.T) {
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA)
+	snap := NewSnapshot([]*resource.State{
+		pA,
+		a,
+		b,
+	})
+
+	// The new URN may not belong to an existing resource.
+	err := RenameResource(snap, a, b.URN)
+	_, ok := err.(ResourceAlreadyExistsError)
+	assert.True(t, ok)
+
+	// The new URN must be well-formed and may only change the resource's name.
+	assert.Error(t, RenameResource(snap, a, "a2"))
+	assert.Error(t, RenameResource(snap, a, resource.NewURN("test", "test", "", "d:e:f", "a2")))
+	assert.Error(t, RenameResource(snap, a, resource.NewURN("other", "test", "", a.Type, "a2")))
+	assert.Equal(t, resource.NewURN("test", "test", "", a.Type, "a"), a.URN)
+}
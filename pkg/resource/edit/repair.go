@@ -0,0 +1,143 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// Repair is an automatic fix for a single snapshot integrity problem.
+type Repair struct {
+	Description string                 // a human-readable description of the fix.
+	Apply       func(*deploy.Snapshot) // applies the fix to the snapshot in-place.
+}
+
+// RepairFor returns the repair for the given integrity problem, or nil if the problem cannot be fixed automatically.
+// Problems that would require guessing at the intended state of a resource, such as a missing provider, are left for
+// the user to fix by hand.
+func RepairFor(problem deploy.IntegrityProblem) *Repair {
+	res := problem.Resource
+	switch problem.Kind {
+	case deploy.IntegrityMagicMismatch:
+		return &Repair{
+			Description: "recompute the manifest's magic cookie",
+			Apply: func(snap *deploy.Snapshot) {
+				snap.Manifest.Magic = snap.Manifest.NewMagic()
+			},
+		}
+	case deploy.IntegrityProviderOutOfOrder, deploy.IntegrityParentOutOfOrder, deploy.IntegrityDependencyOutOfOrder:
+		return &Repair{
+			Description: fmt.Sprintf("reorder the snapshot's resources so that %s precedes %s", problem.Related, res.URN),
+			Apply:       SortResources,
+		}
+	case deploy.IntegrityMissingParent:
+		return &Repair{
+			Description: fmt.Sprintf("remove %s's reference to its missing parent", res.URN),
+			Apply: func(_ *deploy.Snapshot) {
+				res.Parent = ""
+			},
+		}
+	case deploy.IntegrityMissingDependency:
+		return &Repair{
+			Description: fmt.Sprintf("remove %s's dependency on %s", res.URN, problem.Related),
+			Apply: func(_ *deploy.Snapshot) {
+				var deps []resource.URN
+				for _, dep := range res.Dependencies {
+					if dep != problem.Related {
+						deps = append(deps, dep)
+					}
+				}
+				res.Dependencies = deps
+			},
+		}
+	case deploy.IntegrityDuplicateURN:
+		return &Repair{
+			// The live copy of a resource always precedes any copies that are pending deletion, so the copy that was
+			// reported is the one to condemn.
+			Description: fmt.Sprintf("mark the later copy of %s for deletion", res.URN),
+			Apply: func(_ *deploy.Snapshot) {
+				res.Delete = true
+			},
+		}
+	case deploy.IntegrityInvalidPendingOperation:
+		return &Repair{
+			Description: "discard the pending operation",
+			Apply: func(snap *deploy.Snapshot) {
+				var ops []resource.Operation
+				for _, op := range snap.PendingOperations {
+					if op.Resource != res {
+						ops = append(ops, op)
+					}
+				}
+				snap.PendingOperations = ops
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// SortResources reorders the snapshot's resources so that every resource follows its provider, its parent, and its
+// dependencies. Resources that are already correctly ordered keep their relative order. Cycles cannot be ordered and
+// are left as they are.
+func SortResources(snap *deploy.Snapshot) {
+	contract.Require(snap != nil, "snap")
+
+	// Prefer the live copy of a resource when resolving references to a URN that appears more than once.
+	byURN := make(map[resource.URN]*resource.State)
+	for _, res := range snap.Resources {
+		if old, has := byURN[res.URN]; !has || old.Delete {
+			byURN[res.URN] = res
+		}
+	}
+
+	sorted := make([]*resource.State, 0, len(snap.Resources))
+	visited := make(map[*resource.State]bool)
+	var visit func(res *resource.State)
+	visitURN := func(urn resource.URN) {
+		if ref, has := byURN[urn]; has {
+			visit(ref)
+		}
+	}
+	visit = func(res *resource.State) {
+		if _, has := visited[res]; has {
+			return
+		}
+		visited[res] = true
+
+		if res.Provider != "" {
+			if ref, err := providers.ParseReference(res.Provider); err == nil {
+				visitURN(ref.URN())
+			}
+		}
+		if res.Parent != "" {
+			visitURN(res.Parent)
+		}
+		for _, dep := range res.Dependencies {
+			visitURN(dep)
+		}
+		sorted = append(sorted, res)
+	}
+	for _, res := range snap.Resources {
+		visit(res)
+	}
+
+	snap.Resources = sorted
+}
@@ -0,0 +1,99 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestRepairSnapshot(t *testing.T) {
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	c := NewResource("c", pA, "urn:pulumi:test::test::a:b:c::missing")
+	c.Parent = "urn:pulumi:test::test::a:b:c::gone"
+	dup := NewResource("a", pA)
+	snap := NewSnapshot([]*resource.State{b, pA, a, c, dup})
+	snap.Manifest.Magic = "bogus"
+
+	problems := snap.Verify()
+	var kinds []deploy.IntegrityProblemKind
+	for _, p := range problems {
+		kinds = append(kinds, p.Kind)
+		if r := RepairFor(p); assert.NotNil(t, r, p.Message) {
+			r.Apply(snap)
+		}
+	}
+	assert.Equal(t, []deploy.IntegrityProblemKind{
+		deploy.IntegrityMagicMismatch,
+		deploy.IntegrityProviderOutOfOrder,
+		deploy.IntegrityDependencyOutOfOrder,
+		deploy.IntegrityMissingParent,
+		deploy.IntegrityMissingDependency,
+		deploy.IntegrityDuplicateURN,
+	}, kinds)
+
+	assert.NoError(t, snap.VerifyIntegrity())
+	assert.Equal(t, []*resource.State{pA, a, b, c, dup}, snap.Resources)
+	assert.False(t, a.Delete)
+	assert.True(t, dup.Delete)
+	assert.Equal(t, resource.URN(""), c.Parent)
+	assert.Empty(t, c.Dependencies)
+}
+
+func TestRepairPendingOperation(t *testing.T) {
+	a := NewResource("a", nil)
+	gone := NewResource("gone", nil)
+	snap := NewSnapshot([]*resource.State{a})
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+	snap.PendingOperations = []resource.Operation{
+		resource.NewOperation(a, resource.OperationTypeUpdating),
+		resource.NewOperation(gone, resource.OperationTypeDeleting),
+	}
+
+	problems := snap.Verify()
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, deploy.IntegrityInvalidPendingOperation, problems[0].Kind)
+		assert.True(t, problems[0].Warning)
+		RepairFor(problems[0]).Apply(snap)
+	}
+	assert.Len(t, snap.PendingOperations, 1)
+	assert.Empty(t, snap.Verify())
+}
+
+func TestUnrepairableProblems(t *testing.T) {
+	a := NewResource("a", nil)
+	a.Provider = "urn:pulumi:test::test::pulumi:providers:a::p1::0"
+	a.Outputs["ref"] = resource.NewStringProperty("urn:pulumi:test::test::a:b:c::missing")
+	snap := NewSnapshot([]*resource.State{a})
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+
+	problems := snap.Verify()
+	if assert.Len(t, problems, 2) {
+		assert.Equal(t, deploy.IntegrityMissingProvider, problems[0].Kind)
+		assert.Nil(t, RepairFor(problems[0]))
+		assert.Equal(t, deploy.IntegrityDanglingPropertyReference, problems[1].Kind)
+		assert.True(t, problems[1].Warning)
+		assert.Nil(t, RepairFor(problems[1]))
+	}
+	assert.EqualError(t, snap.VerifyIntegrity(),
+		"resource urn:pulumi:test::test::a:b:c::a refers to unknown provider "+
+			"urn:pulumi:test::test::pulumi:providers:a::p1::0")
+}
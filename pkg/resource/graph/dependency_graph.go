@@ -3,6 +3,8 @@
 package graph
 
 import (
+	"strings"
+
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -10,8 +12,9 @@ import (
 
 // DependencyGraph represents a dependency graph encoded within a resource snapshot.
 type DependencyGraph struct {
-	index     map[*resource.State]int // A mapping of resource pointers to indexes within the snapshot
-	resources []*resource.State       // The list of resources, obtained from the snapshot
+	index        map[*resource.State]int // A mapping of resource pointers to indexes within the snapshot
+	resources    []*resource.State       // The list of resources, obtained from the snapshot
+	dependencies []map[resource.URN]bool // The URNs each resource depends on, aside from its parent, by index
 }
 
 // DependingOn returns a slice containing all resources that directly or indirectly
@@ -29,15 +32,8 @@ func (dg *DependencyGraph) DependingOn(res *resource.State) []*resource.State {
 	contract.Assert(ok)
 	dependentSet[res.URN] = true
 
-	isDependent := func(candidate *resource.State) bool {
-		if candidate.Provider != "" {
-			ref, err := providers.ParseReference(candidate.Provider)
-			contract.Assert(err == nil)
-			if dependentSet[ref.URN()] {
-				return true
-			}
-		}
-		for _, dependency := range candidate.Dependencies {
+	isDependent := func(i int) bool {
+		for dependency := range dg.dependencies[i] {
 			if dependentSet[dependency] {
 				return true
 			}
@@ -60,7 +56,7 @@ func (dg *DependencyGraph) DependingOn(res *resource.State) []*resource.State {
 	// onto `dependents`.
 	for i := cursorIndex + 1; i < len(dg.resources); i++ {
 		candidate := dg.resources[i]
-		if isDependent(candidate) {
+		if isDependent(i) {
 			dependents = append(dependents, candidate)
 			dependentSet[candidate.URN] = true
 		}
@@ -74,19 +70,10 @@ func (dg *DependencyGraph) DependingOn(res *resource.State) []*resource.State {
 func (dg *DependencyGraph) DependenciesOf(res *resource.State) ResourceSet {
 	set := make(ResourceSet)
 
-	dependentUrns := make(map[resource.URN]bool)
-	for _, dep := range res.Dependencies {
-		dependentUrns[dep] = true
-	}
-
-	if res.Provider != "" {
-		ref, err := providers.ParseReference(res.Provider)
-		contract.Assert(err == nil)
-		dependentUrns[ref.URN()] = true
-	}
-
 	cursorIndex, ok := dg.index[res]
 	contract.Assert(ok)
+
+	dependentUrns := dg.dependencies[cursorIndex]
 	for i := cursorIndex - 1; i >= 0; i-- {
 		candidate := dg.resources[i]
 		if dependentUrns[candidate.URN] || candidate.URN == res.Parent {
@@ -97,13 +84,63 @@ func (dg *DependencyGraph) DependenciesOf(res *resource.State) ResourceSet {
 	return set
 }
 
+// dependencyURNs returns the URNs of the resources that the given resource depends on, aside from its parent: its
+// explicit dependencies, the dependencies of its properties, its provider, and any resources whose URNs appear among
+// its inputs. Programs that pass a resource's URN to another resource need not also declare the dependency.
+func dependencyURNs(res *resource.State) map[resource.URN]bool {
+	urns := make(map[resource.URN]bool)
+	for _, dep := range res.Dependencies {
+		urns[dep] = true
+	}
+	for _, deps := range res.PropertyDependencies {
+		for _, dep := range deps {
+			urns[dep] = true
+		}
+	}
+
+	if res.Provider != "" {
+		ref, err := providers.ParseReference(res.Provider)
+		contract.Assert(err == nil)
+		urns[ref.URN()] = true
+	}
+
+	for _, v := range res.Inputs {
+		addReferencedURNs(v, urns)
+	}
+	return urns
+}
+
+// addReferencedURNs adds the URNs that appear as strings within the given value to the given set.
+func addReferencedURNs(v resource.PropertyValue, urns map[resource.URN]bool) {
+	switch {
+	case v.IsString():
+		if s := v.StringValue(); strings.HasPrefix(s, resource.URNPrefix) {
+			urns[resource.URN(s)] = true
+		}
+	case v.IsArray():
+		for _, elem := range v.ArrayValue() {
+			addReferencedURNs(elem, urns)
+		}
+	case v.IsObject():
+		for _, elem := range v.ObjectValue() {
+			addReferencedURNs(elem, urns)
+		}
+	case v.IsSecret():
+		addReferencedURNs(v.SecretValue().Element, urns)
+	}
+}
+
 // NewDependencyGraph creates a new DependencyGraph from a list of resources.
 // The resources should be in topological order with respect to their dependencies.
+//
+// The dependencies of each resource are computed once here, since finding them requires a walk of its inputs.
 func NewDependencyGraph(resources []*resource.State) *DependencyGraph {
 	index := make(map[*resource.State]int)
+	dependencies := make([]map[resource.URN]bool, len(resources))
 	for idx, res := range resources {
 		index[res] = idx
+		dependencies[idx] = dependencyURNs(res)
 	}
 
-	return &DependencyGraph{index, resources}
+	return &DependencyGraph{index, resources, dependencies}
 }
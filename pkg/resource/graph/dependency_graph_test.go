@@ -137,3 +137,24 @@ func TestDependenciesOf(t *testing.T) {
 	assert.False(t, dDepends[b])
 	assert.False(t, dDepends[c])
 }
+
+func TestPropertyReferenceDependencies(t *testing.T) {
+	a := NewResource("a", nil)
+	b := NewResource("b", nil)
+	b.PropertyDependencies = map[resource.PropertyKey][]resource.URN{"bucket": {a.URN}}
+	c := NewResource("c", nil)
+	c.Inputs["policy"] = resource.NewObjectProperty(resource.PropertyMap{
+		"targets": resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty(string(b.URN))}),
+	})
+	d := NewResource("d", nil)
+	d.Inputs["name"] = resource.NewStringProperty("urn-like but not a URN")
+
+	dg := NewDependencyGraph([]*resource.State{a, b, c, d})
+	assert.Equal(t, []*resource.State{b, c}, dg.DependingOn(a))
+	assert.Equal(t, []*resource.State{c}, dg.DependingOn(b))
+	assert.Empty(t, dg.DependingOn(c))
+
+	assert.True(t, dg.DependenciesOf(c)[b])
+	assert.False(t, dg.DependenciesOf(c)[a])
+	assert.Empty(t, dg.DependenciesOf(d))
+}
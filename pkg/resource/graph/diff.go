@@ -0,0 +1,264 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// DiffKind describes how a node or edge differs between two resource graphs.
+type DiffKind string
+
+const (
+	DiffSame    DiffKind = "same"    // the node or edge is in both graphs and is unchanged.
+	DiffAdded   DiffKind = "added"   // the node or edge is only in the new graph.
+	DiffRemoved DiffKind = "removed" // the node or edge is only in the old graph.
+	DiffChanged DiffKind = "changed" // the node is in both graphs, but its properties differ.
+)
+
+// EdgeKind is the relationship between two resources that an edge represents.
+type EdgeKind string
+
+const (
+	DependencyEdge EdgeKind = "dependency" // from a resource to a resource that depends on it.
+	ParentEdge     EdgeKind = "parent"     // from a resource to its parent.
+	ProviderEdge   EdgeKind = "provider"   // from a provider to a resource that it manages.
+)
+
+// DigestChange records the old and new fingerprints of a set of properties that changed.
+type DigestChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// NodeDiff describes how a resource differs between two graphs. Only the digests of the resource's properties are
+// recorded, so a diff may be shared without revealing any property values.
+type NodeDiff struct {
+	URN     resource.URN  `json:"urn"`               // the resource's URN.
+	Type    tokens.Type   `json:"type"`              // the resource's type.
+	Diff    DiffKind      `json:"diff"`              // how the resource differs.
+	Inputs  *DigestChange `json:"inputs,omitempty"`  // the digests of the resource's inputs, if they changed.
+	Outputs *DigestChange `json:"outputs,omitempty"` // the digests of the resource's outputs, if they changed.
+}
+
+// EdgeDiff describes how an edge differs between two graphs.
+type EdgeDiff struct {
+	From resource.URN `json:"from"` // the URN of the resource that the edge leaves.
+	To   resource.URN `json:"to"`   // the URN of the resource that the edge enters.
+	Kind EdgeKind     `json:"kind"` // the relationship that the edge represents.
+	Diff DiffKind     `json:"diff"` // how the edge differs.
+}
+
+// Diff is the difference between the resource graphs of two snapshots. Nodes are ordered as the new snapshot orders
+// them, followed by the nodes that were removed in the order of the old snapshot; edges are sorted.
+type Diff struct {
+	Nodes []NodeDiff `json:"nodes"`
+	Edges []EdgeDiff `json:"edges"`
+}
+
+type edgeKey struct {
+	from, to resource.URN
+	kind     EdgeKind
+}
+
+// liveResources returns the resources that a snapshot will retain, in order and indexed by URN. Resources that are
+// pending deletion are omitted.
+func liveResources(resources []*resource.State) ([]*resource.State, map[resource.URN]*resource.State) {
+	var live []*resource.State
+	byURN := make(map[resource.URN]*resource.State)
+	for _, res := range resources {
+		if res.Delete {
+			continue
+		}
+		if _, has := byURN[res.URN]; !has {
+			live = append(live, res)
+		}
+		byURN[res.URN] = res
+	}
+	return live, byURN
+}
+
+// graphEdges returns the edges between the given resources.
+func graphEdges(resources []*resource.State, byURN map[resource.URN]*resource.State) map[edgeKey]bool {
+	edges := make(map[edgeKey]bool)
+	add := func(from, to resource.URN, kind EdgeKind) {
+		if from != to && byURN[from] != nil && byURN[to] != nil {
+			edges[edgeKey{from: from, to: to, kind: kind}] = true
+		}
+	}
+
+	for _, res := range resources {
+		var provider resource.URN
+		if res.Provider != "" {
+			ref, err := providers.ParseReference(res.Provider)
+			contract.Assert(err == nil)
+			provider = ref.URN()
+			add(provider, res.URN, ProviderEdge)
+		}
+		if res.Parent != "" {
+			add(res.URN, res.Parent, ParentEdge)
+		}
+		for dep := range dependencyURNs(res) {
+			if dep != provider {
+				add(dep, res.URN, DependencyEdge)
+			}
+		}
+	}
+	return edges
+}
+
+// DiffResources computes the difference between the resource graphs of two snapshots, given their resources.
+func DiffResources(olds, news []*resource.State) *Diff {
+	oldLive, oldByURN := liveResources(olds)
+	newLive, newByURN := liveResources(news)
+
+	diff := &Diff{}
+	for _, res := range newLive {
+		node := NodeDiff{URN: res.URN, Type: res.Type, Diff: DiffAdded}
+		if old, has := oldByURN[res.URN]; has {
+			node.Diff = DiffSame
+			if o, n := old.Inputs.Fingerprint().String(), res.Inputs.Fingerprint().String(); o != n {
+				node.Diff, node.Inputs = DiffChanged, &DigestChange{Old: o, New: n}
+			}
+			if o, n := old.Outputs.Fingerprint().String(), res.Outputs.Fingerprint().String(); o != n {
+				node.Diff, node.Outputs = DiffChanged, &DigestChange{Old: o, New: n}
+			}
+		}
+		diff.Nodes = append(diff.Nodes, node)
+	}
+	for _, res := range oldLive {
+		if _, has := newByURN[res.URN]; !has {
+			diff.Nodes = append(diff.Nodes, NodeDiff{URN: res.URN, Type: res.Type, Diff: DiffRemoved})
+		}
+	}
+
+	oldEdges, newEdges := graphEdges(oldLive, oldByURN), graphEdges(newLive, newByURN)
+	for e := range newEdges {
+		kind := DiffAdded
+		if oldEdges[e] {
+			kind = DiffSame
+		}
+		diff.Edges = append(diff.Edges, EdgeDiff{From: e.from, To: e.to, Kind: e.kind, Diff: kind})
+	}
+	for e := range oldEdges {
+		if !newEdges[e] {
+			diff.Edges = append(diff.Edges, EdgeDiff{From: e.from, To: e.to, Kind: e.kind, Diff: DiffRemoved})
+		}
+	}
+	sort.Slice(diff.Edges, func(i, j int) bool {
+		a, b := diff.Edges[i], diff.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+	return diff
+}
+
+// HasChanges returns true if any node or edge differs between the two graphs.
+func (d *Diff) HasChanges() bool {
+	for _, n := range d.Nodes {
+		if n.Diff != DiffSame {
+			return true
+		}
+	}
+	for _, e := range d.Edges {
+		if e.Diff != DiffSame {
+			return true
+		}
+	}
+	return false
+}
+
+// Changes returns the parts of the diff that changed: the nodes and edges that differ, along with the unchanged nodes
+// that the edges which differ connect.
+func (d *Diff) Changes() *Diff {
+	keep := make(map[resource.URN]bool)
+	changes := &Diff{}
+	for _, e := range d.Edges {
+		if e.Diff != DiffSame {
+			changes.Edges = append(changes.Edges, e)
+			keep[e.From], keep[e.To] = true, true
+		}
+	}
+	for _, n := range d.Nodes {
+		if n.Diff != DiffSame || keep[n.URN] {
+			changes.Nodes = append(changes.Nodes, n)
+		}
+	}
+	return changes
+}
+
+// diffColors are the colors with which PrintDOT draws the nodes and edges of each kind of difference.
+var diffColors = map[DiffKind]string{
+	DiffAdded:   "#2E7D32", // green
+	DiffRemoved: "#C62828", // red
+	DiffChanged: "#EF6C00", // orange
+}
+
+// dotQuote quotes a string for use as a DOT identifier or attribute value.
+func dotQuote(s string) string {
+	return `"` + strings.Replace(strings.Replace(s, `\`, `\\`, -1), `"`, `\"`, -1) + `"`
+}
+
+// PrintDOT prints the diff as a DOT digraph. Nodes are identified by their URNs and labeled with their types and
+// names. Added nodes and edges are green, changed nodes are orange, and removed nodes and edges are red and dashed.
+// Parent and provider edges are labeled as such.
+func (d *Diff) PrintDOT(w io.Writer) error {
+	// As with dotconv.Print, write errors are latched by the buffer and reported when it is flushed.
+	b := bufio.NewWriter(w)
+	writeAttrs := func(diff DiffKind, attrs []string) {
+		if color, has := diffColors[diff]; has {
+			attrs = append(attrs, "color="+dotQuote(color))
+		}
+		if diff == DiffRemoved {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, " [%s]", strings.Join(attrs, ", "))
+		}
+		fmt.Fprint(b, ";\n")
+	}
+
+	fmt.Fprint(b, "strict digraph {\n")
+	for _, n := range d.Nodes {
+		label := dotQuote(string(n.Type))
+		label = label[:len(label)-1] + `\n` + dotQuote(string(n.URN.Name()))[1:]
+		fmt.Fprintf(b, "    %s", dotQuote(string(n.URN)))
+		writeAttrs(n.Diff, []string{"label=" + label})
+	}
+	for _, e := range d.Edges {
+		fmt.Fprintf(b, "    %s -> %s", dotQuote(string(e.From)), dotQuote(string(e.To)))
+		var attrs []string
+		if e.Kind != DependencyEdge {
+			attrs = append(attrs, "label="+dotQuote(string(e.Kind)))
+		}
+		writeAttrs(e.Diff, attrs)
+	}
+	fmt.Fprint(b, "}\n")
+	return b.Flush()
+}
@@ -0,0 +1,97 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func TestDiffResources(t *testing.T) {
+	p := NewProviderResource("test", "p", "0")
+	a := NewResource("a", p)
+	b := NewResource("b", p, a.URN)
+	c := NewResource("c", nil, b.URN)
+	f := NewResource("f", nil)
+	pendingDelete := NewResource("d", nil)
+	pendingDelete.Delete = true
+
+	// In the new graph, a's inputs change, b is removed, c depends on a instead, and e is added as a child of c.
+	a2 := NewResource("a", p)
+	a2.Inputs = resource.PropertyMap{"size": resource.NewNumberProperty(2)}
+	c2 := NewResource("c", nil, a.URN)
+	e := NewResource("e", nil)
+	e.Parent = c.URN
+
+	diff := DiffResources([]*resource.State{p, a, b, c, f, pendingDelete}, []*resource.State{p, a2, c2, e, f})
+	assert.True(t, diff.HasChanges())
+
+	inputs := &DigestChange{
+		Old: a.Inputs.Fingerprint().String(),
+		New: a2.Inputs.Fingerprint().String(),
+	}
+	assert.Equal(t, []NodeDiff{
+		{URN: p.URN, Type: p.Type, Diff: DiffSame},
+		{URN: a.URN, Type: a.Type, Diff: DiffChanged, Inputs: inputs},
+		{URN: c.URN, Type: c.Type, Diff: DiffSame},
+		{URN: e.URN, Type: e.Type, Diff: DiffAdded},
+		{URN: f.URN, Type: f.Type, Diff: DiffSame},
+		{URN: b.URN, Type: b.Type, Diff: DiffRemoved},
+	}, diff.Nodes)
+
+	assert.Equal(t, []EdgeDiff{
+		{From: p.URN, To: a.URN, Kind: ProviderEdge, Diff: DiffSame},
+		{From: p.URN, To: b.URN, Kind: ProviderEdge, Diff: DiffRemoved},
+		{From: a.URN, To: b.URN, Kind: DependencyEdge, Diff: DiffRemoved},
+		{From: a.URN, To: c.URN, Kind: DependencyEdge, Diff: DiffAdded},
+		{From: b.URN, To: c.URN, Kind: DependencyEdge, Diff: DiffRemoved},
+		{From: e.URN, To: c.URN, Kind: ParentEdge, Diff: DiffAdded},
+	}, diff.Edges)
+
+	// The unchanged nodes that changed edges connect are kept along with the changes, but f is dropped.
+	changes := diff.Changes()
+	assert.Equal(t, []resource.URN{p.URN, a.URN, c.URN, e.URN, b.URN}, nodeURNs(changes.Nodes))
+	assert.Equal(t, 5, len(changes.Edges))
+
+	same := DiffResources([]*resource.State{p, a, b}, []*resource.State{p, a, b})
+	assert.False(t, same.HasChanges())
+	assert.Equal(t, 0, len(same.Changes().Nodes))
+}
+
+func nodeURNs(nodes []NodeDiff) []resource.URN {
+	var urns []resource.URN
+	for _, n := range nodes {
+		urns = append(urns, n.URN)
+	}
+	return urns
+}
+
+func TestDiffPrintDOT(t *testing.T) {
+	a := NewResource("a", nil)
+	b := NewResource("b", nil, a.URN)
+
+	var buf bytes.Buffer
+	assert.NoError(t, DiffResources([]*resource.State{a}, []*resource.State{a, b}).PrintDOT(&buf))
+	assert.Equal(t, "strict digraph {\n"+
+		`    "urn:pulumi:test::test::test:test:test::a" [label="test:test:test\na"];`+"\n"+
+		`    "urn:pulumi:test::test::test:test:test::b" [label="test:test:test\nb", color="#2E7D32"];`+"\n"+
+		`    "urn:pulumi:test::test::test:test:test::a" -> "urn:pulumi:test::test::test:test:test::b"`+
+		` [color="#2E7D32"];`+"\n"+
+		"}\n", buf.String())
+}
@@ -0,0 +1,57 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// A fan-out deployment evaluates a stack's program once for each of several instances, each with its own provider
+// configuration (e.g. one instance per region).  The resources of each instance are told apart by their names, which
+// are qualified by the name of the instance, e.g. `east@bucket`, so that each instance's resources have URNs of their
+// own even though every instance registers the same logical resources.
+
+// InstanceDelimiter separates the name of a fan-out instance from the name of a resource within it.
+const InstanceDelimiter = "@"
+
+// instanceNameRegexp matches valid fan-out instance names.
+var instanceNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// IsInstanceName returns true if the given string is a valid fan-out instance name.
+func IsInstanceName(s string) bool {
+	return instanceNameRegexp.MatchString(s)
+}
+
+// InstanceName returns the name of the resource with the given name within the fan-out instance with the given name.
+func InstanceName(instance string, name tokens.QName) tokens.QName {
+	contract.Requiref(IsInstanceName(instance), "instance", "must be a valid instance name")
+	return tokens.QName(instance + InstanceDelimiter + string(name))
+}
+
+// SplitInstanceName returns the fan-out instance and the unqualified name of a resource whose name was qualified by
+// InstanceName.  The last result is false if the name does not look like a qualified name.  Because the names that
+// programs choose may contain the delimiter, callers that know the instances of a deployment should check that the
+// instance is one of them.
+func SplitInstanceName(name tokens.QName) (string, tokens.QName, bool) {
+	ix := strings.Index(string(name), InstanceDelimiter)
+	if ix <= 0 || ix == len(name)-len(InstanceDelimiter) || !IsInstanceName(string(name[:ix])) {
+		return "", name, false
+	}
+	return string(name[:ix]), name[ix+len(InstanceDelimiter):], true
+}
@@ -0,0 +1,45 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestInstanceName(t *testing.T) {
+	name := InstanceName("us-east-1", "bucket")
+	assert.Equal(t, tokens.QName("us-east-1@bucket"), name)
+
+	instance, unqualified, ok := SplitInstanceName(name)
+	assert.True(t, ok)
+	assert.Equal(t, "us-east-1", instance)
+	assert.Equal(t, tokens.QName("bucket"), unqualified)
+
+	// Names are split at the first delimiter, so that resource names may themselves contain one.
+	instance, unqualified, ok = SplitInstanceName("east@user@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "east", instance)
+	assert.Equal(t, tokens.QName("user@example.com"), unqualified)
+
+	for _, unqualified := range []tokens.QName{"bucket", "@bucket", "east@", "a b@bucket"} {
+		_, name, ok := SplitInstanceName(unqualified)
+		assert.False(t, ok, string(unqualified))
+		assert.Equal(t, unqualified, name)
+	}
+}
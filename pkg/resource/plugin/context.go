@@ -20,6 +20,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/util/rpcutil"
 )
 
@@ -31,6 +32,12 @@ type Context struct {
 	Host       Host      // the host that can be used to fetch providers.
 	Pwd        string    // the working directory to spawn all plugins in.
 
+	// Redaction, if non-nil, decides which property values must not be revealed when properties are logged.
+	Redaction resource.RedactionPolicy
+	// Interner deduplicates the keys and strings of the property values that are unmarshaled from programs and
+	// providers, which are shared by many of a large stack's resources.
+	Interner *resource.Interner
+
 	tracingSpan opentracing.Span // the OpenTracing span to parent requests within.
 }
 
@@ -43,6 +50,7 @@ func NewContext(d, statusD diag.Sink, host Host, cfg ConfigSource, events Events
 		StatusDiag:  statusD,
 		Host:        host,
 		Pwd:         pwd,
+		Interner:    resource.NewInterner(),
 		tracingSpan: parentSpan,
 	}
 	if host == nil {
@@ -34,6 +34,9 @@ import (
 type Host interface {
 	// ServerAddr returns the address at which the host's RPC interface may be found.
 	ServerAddr() string
+	// ProviderServerAddr returns the address at which resource providers may find the host's RPC interface. If
+	// providers are configured to use mutual TLS, this address requires it.
+	ProviderServerAddr() string
 
 	// Log logs a message, including errors and warnings.  Messages can have a resource URN
 	// associated with them.  If no urn is provided, the message is global.
@@ -156,6 +159,10 @@ func (host *defaultHost) ServerAddr() string {
 	return host.server.Address()
 }
 
+func (host *defaultHost) ProviderServerAddr() string {
+	return host.server.ProviderAddress()
+}
+
 func (host *defaultHost) Log(sev diag.Severity, urn resource.URN, msg string, streamID int32) {
 	host.ctx.Diag.Logf(sev, diag.StreamMessage(urn, msg, streamID))
 }
@@ -216,8 +223,16 @@ func (host *defaultHost) Analyzer(name tokens.QName) (Analyzer, error) {
 
 func (host *defaultHost) Provider(pkg tokens.Package, version *semver.Version) (Provider, error) {
 	plugin, err := host.loadPlugin(func() (interface{}, error) {
-		// Try to load and bind to a plugin.
+		// Try to load and bind to a plugin.  If the plugin is missing, try to download it and then load it again.
 		plug, err := NewProvider(host, host.ctx, pkg, version)
+		if missing, isMissing := err.(*MissingError); isMissing && host.canAcquirePlugin(missing.Info) {
+			if err = host.acquirePlugin(missing.Info); err != nil {
+				missing.DownloadError = err
+				err = missing
+			} else {
+				plug, err = NewProvider(host, host.ctx, pkg, version)
+			}
+		}
 		if err == nil && plug != nil {
 			info, infoerr := plug.GetPluginInfo()
 			if infoerr != nil {
@@ -266,6 +281,19 @@ func (host *defaultHost) Provider(pkg tokens.Package, version *semver.Version) (
 	return plugin.(Provider), nil
 }
 
+// canAcquirePlugin returns true if the given missing plugin may be downloaded automatically.  Only plugins with a
+// specific version can be downloaded, and automatic downloads may be disabled entirely using an environment variable.
+func (host *defaultHost) canAcquirePlugin(info workspace.PluginInfo) bool {
+	return info.Version != nil && !cmdutil.IsTruthy(os.Getenv(workspace.DisableAutomaticPluginAcquisitionEnvVar))
+}
+
+// acquirePlugin downloads the given plugin from the release URL and installs it into the plugin cache.
+func (host *defaultHost) acquirePlugin(info workspace.PluginInfo) error {
+	url := workspace.GetPluginDownloadURL()
+	host.ctx.Diag.Infoerrf(diag.Message("", "downloading %s plugin %s from %s"), info.Kind, info, url)
+	return workspace.InstallPlugin(info, url)
+}
+
 func (host *defaultHost) LanguageRuntime(runtime string) (LanguageRuntime, error) {
 	plugin, err := host.loadPlugin(func() (interface{}, error) {
 		// First see if we already loaded this plugin.
@@ -25,6 +25,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/rpcutil"
 	lumirpc "github.com/pulumi/pulumi/sdk/proto/go"
 )
@@ -37,6 +38,11 @@ type hostServer struct {
 	cancel chan bool  // a channel that can cancel the server.
 	done   chan error // a channel that resolves when the server completes.
 
+	// If providers are configured to use mutual TLS, the host also listens for them on a second, secure address.
+	providerAddr   string     // the address at which providers reach the host.
+	providerCancel chan bool  // a channel that can cancel the secure server, if any.
+	providerDone   chan error // a channel that resolves when the secure server completes, if any.
+
 	// hostServer contains little bits of state that can't be saved in the language host.
 	rootUrn atomic.Value // a root resource URN that has been saved via SetRootResource
 }
@@ -51,20 +57,38 @@ func newHostServer(host Host, ctx *Context) (*hostServer, error) {
 	}
 
 	// Fire up a gRPC server and start listening for incomings.
-	port, done, err := rpcutil.Serve(0, engine.cancel, []func(*grpc.Server) error{
+	registers := []func(*grpc.Server) error{
 		func(srv *grpc.Server) error {
 			lumirpc.RegisterEngineServer(srv, engine)
 			return nil
 		},
-	})
+	}
+	port, done, err := rpcutil.Serve(0, engine.cancel, registers)
 	if err != nil {
 		return nil, err
 	}
 
 	engine.addr = fmt.Sprintf("127.0.0.1:%d", port)
 	engine.done = done
+	engine.providerAddr = engine.addr
 	engine.rootUrn.Store("")
 
+	// Language hosts always connect without transport security, so providers that use mutual TLS are given their own
+	// server. Malformed transport options are reported when providers are loaded; see rpcutil.TransportOptionsFromEnv.
+	if transport, envErr := rpcutil.TransportOptionsFromEnv(); envErr == nil && transport.TLS != nil {
+		serverOpts, err := transport.ServerOptions()
+		if err == nil {
+			engine.providerCancel = make(chan bool)
+			port, engine.providerDone, err = rpcutil.ServeWithOptions(0, engine.providerCancel, registers,
+				serverOpts...)
+		}
+		if err != nil {
+			contract.IgnoreError(engine.Cancel())
+			return nil, err
+		}
+		engine.providerAddr = fmt.Sprintf("127.0.0.1:%d", port)
+	}
+
 	return engine, nil
 }
 
@@ -73,8 +97,18 @@ func (eng *hostServer) Address() string {
 	return eng.addr
 }
 
+// ProviderAddress returns the address at which resource providers may reach the engine's RPC server.
+func (eng *hostServer) ProviderAddress() string {
+	return eng.providerAddr
+}
+
 // Cancel signals that the engine should be terminated, awaits its termination, and returns any errors that result.
 func (eng *hostServer) Cancel() error {
+	if eng.providerDone != nil {
+		eng.providerCancel <- true
+		contract.IgnoreError(<-eng.providerDone)
+		eng.providerDone = nil
+	}
 	eng.cancel <- true
 	return <-eng.done
 }
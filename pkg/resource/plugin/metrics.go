@@ -0,0 +1,57 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/metrics"
+)
+
+var (
+	providerCallDuration = metrics.Default.NewHistogram("pulumi_provider_call_duration_seconds",
+		"The time taken by provider operations, including the marshaling of their arguments and results.", nil,
+		"pkg", "method")
+	marshaledBytes = metrics.Default.NewCounter("pulumi_provider_marshaled_bytes_total",
+		"The size of the encoded properties sent to and received from providers.", "direction")
+	unknownProperties = metrics.Default.NewCounter("pulumi_provider_unknown_properties_total",
+		"The number of unknown property values sent to providers.", "method")
+)
+
+// countUnknowns returns the number of unknown values in the given property map, including those nested within arrays
+// and objects.
+func countUnknowns(props resource.PropertyMap) int {
+	count := 0
+	for _, v := range props {
+		count += countUnknownsInValue(v)
+	}
+	return count
+}
+
+func countUnknownsInValue(v resource.PropertyValue) int {
+	switch {
+	case v.IsComputed() || v.IsOutput():
+		return 1
+	case v.IsArray():
+		count := 0
+		for _, e := range v.ArrayValue() {
+			count += countUnknownsInValue(e)
+		}
+		return count
+	case v.IsObject():
+		return countUnknowns(v.ObjectValue())
+	default:
+		return 0
+	}
+}
@@ -45,6 +45,8 @@ import (
 type MissingError struct {
 	// Info contains information about the plugin that was not found.
 	Info workspace.PluginInfo
+	// DownloadError, if non-nil, is the error that prevented the plugin from being downloaded automatically.
+	DownloadError error
 }
 
 // NewMissingError allocates a new error indicating the given plugin info was not found.
@@ -55,19 +57,31 @@ func NewMissingError(info workspace.PluginInfo) error {
 }
 
 func (err *MissingError) Error() string {
+	// Tell the user where we looked, since that is the first thing they will need to know to fix the problem.
+	where := "in the workspace"
+	if dir, dirErr := workspace.GetPluginDir(); dirErr == nil {
+		where = fmt.Sprintf("in the workspace (%s)", dir)
+	}
+
+	var msg string
 	if err.Info.Version != nil {
-		return fmt.Sprintf("no %[1]s plugin '%[2]s-v%[3]s' found in the workspace or on your $PATH, "+
+		msg = fmt.Sprintf("no %[1]s plugin '%[2]s-v%[3]s' found %[4]s or on your $PATH, "+
 			"install the plugin using `pulumi plugin install %[1]s %[2]s v%[3]s`",
-			err.Info.Kind, err.Info.Name, err.Info.Version)
+			err.Info.Kind, err.Info.Name, err.Info.Version, where)
+	} else {
+		msg = fmt.Sprintf("no %s plugin '%s' found %s or on your $PATH",
+			err.Info.Kind, err.Info.String(), where)
 	}
-
-	return fmt.Sprintf("no %s plugin '%s' found in the workspace or on your $PATH",
-		err.Info.Kind, err.Info.String())
+	if err.DownloadError != nil {
+		msg += fmt.Sprintf(" (automatic download failed: %v)", err.DownloadError)
+	}
+	return msg
 }
 
 type plugin struct {
 	stdoutDone <-chan bool
 	stderrDone <-chan bool
+	exited     <-chan bool // closed once the plugin's process has exited.
 
 	Bin    string
 	Args   []string
@@ -86,7 +100,9 @@ var pluginRPCConnectionTimeout = time.Second * 10
 // time.
 var nextStreamID int32
 
-func newPlugin(ctx *Context, bin string, prefix string, args []string) (*plugin, error) {
+func newPlugin(ctx *Context, bin string, prefix string, args []string,
+	transport rpcutil.TransportOptions) (*plugin, error) {
+
 	if logging.V(9) {
 		var argstr string
 		for i, arg := range args {
@@ -176,9 +192,12 @@ func newPlugin(ctx *Context, bin string, prefix string, args []string) (*plugin,
 	go runtrace(plug.Stdout, false, stdoutDone)
 
 	// Now that we have the port, go ahead and create a gRPC client connection to it.
-	conn, err := grpc.Dial("127.0.0.1:"+port, grpc.WithInsecure(), grpc.WithUnaryInterceptor(
-		rpcutil.OpenTracingClientInterceptor(),
-	))
+	dialOpts, err := transport.DialOptions()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not configure RPC transport for plugin [%v]", bin)
+	}
+	dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(rpcutil.OpenTracingClientInterceptor()))
+	conn, err := grpc.Dial("127.0.0.1:"+port, dialOpts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not dial plugin [%v] over RPC", bin)
 	}
@@ -257,7 +276,16 @@ func execPlugin(bin string, pluginArgs []string, pwd string) (*plugin, error) {
 		return nil, err
 	}
 
+	// Watch for the process to exit so that a plugin that crashes can be told apart from one that merely failed an RPC.
+	exited := make(chan bool)
+	go func() {
+		_, waitErr := cmd.Process.Wait()
+		contract.IgnoreError(waitErr)
+		close(exited)
+	}()
+
 	return &plugin{
+		exited: exited,
 		Bin:    bin,
 		Args:   args,
 		Proc:   cmd.Process,
@@ -267,6 +295,30 @@ func execPlugin(bin string, pluginArgs []string, pwd string) (*plugin, error) {
 	}, nil
 }
 
+// waitForExit returns true if the plugin's process exits within the given grace period.
+func (p *plugin) waitForExit(grace time.Duration) bool {
+	if p.exited == nil {
+		return false
+	}
+	select {
+	case <-p.exited:
+		return true
+	default:
+		if grace == 0 {
+			return false
+		}
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-p.exited:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
 func (p *plugin) Close() error {
 	if p.Conn != nil {
 		closerr := p.Conn.Close()
@@ -282,8 +334,9 @@ func (p *plugin) Close() error {
 		result = multierror.Append(result, err)
 	}
 
-	// IDEA: consider a more graceful termination than just SIGKILL.
-	if err := p.Proc.Kill(); err != nil {
+	// IDEA: consider a more graceful termination than just SIGKILL.  A plugin that has already exited (e.g. because
+	// it crashed) cannot be killed, and that is not an error.
+	if err := p.Proc.Kill(); err != nil && !p.waitForExit(0) {
 		result = multierror.Append(result, err)
 	}
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
@@ -49,21 +50,27 @@ type Provider interface {
 	// that should be passed to successive calls to Diff, Create, or Update for this resource.
 	Check(urn resource.URN, olds, news resource.PropertyMap,
 		allowUnknowns bool) (resource.PropertyMap, []CheckFailure, error)
-	// Diff checks what impacts a hypothetical update will have on the resource's properties.
-	Diff(urn resource.URN, id resource.ID, olds resource.PropertyMap, news resource.PropertyMap,
+	// Diff checks what impacts a hypothetical update will have on the resource's properties.  The resource's old
+	// inputs (as last specified by the program) and old outputs (as last returned by the provider) are passed
+	// separately; the new inputs are the result of a prior call to Check.
+	Diff(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
 		allowUnknowns bool) (DiffResult, error)
-	// Create allocates a new instance of the provided resource and returns its unique resource.ID.
-	Create(urn resource.URN, news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error)
+	// Create allocates a new instance of the provided resource and returns its unique resource.ID.  If timeout is
+	// non-zero, it is the number of seconds the provider has to complete the operation.
+	Create(urn resource.URN, news resource.PropertyMap,
+		timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error)
 	// Read the current live state associated with a resource.  Enough state must be include in the inputs to uniquely
 	// identify the resource; this is typically just the resource ID, but may also include some properties.  If the
 	// resource is missing (for instance, because it has been deleted), the resulting property map will be nil.
 	Read(urn resource.URN, id resource.ID,
 		props resource.PropertyMap) (resource.PropertyMap, resource.Status, error)
-	// Update updates an existing resource with new values.
-	Update(urn resource.URN, id resource.ID,
-		olds resource.PropertyMap, news resource.PropertyMap) (resource.PropertyMap, resource.Status, error)
-	// Delete tears down an existing resource.
-	Delete(urn resource.URN, id resource.ID, props resource.PropertyMap) (resource.Status, error)
+	// Update updates an existing resource with new values.  As with Diff, the resource's old inputs and old outputs are
+	// passed separately.  The returned property map holds the resource's new outputs.  As with Create, a non-zero
+	// timeout bounds the operation.
+	Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
+		timeout float64) (resource.PropertyMap, resource.Status, error)
+	// Delete tears down an existing resource.  As with Create, a non-zero timeout bounds the operation.
+	Delete(urn resource.URN, id resource.ID, props resource.PropertyMap, timeout float64) (resource.Status, error)
 	// Invoke dynamically executes a built-in function in the provider.
 	Invoke(tok tokens.ModuleMember, args resource.PropertyMap) (resource.PropertyMap, []CheckFailure, error)
 	// GetPluginInfo returns this plugin's information.
@@ -95,12 +102,65 @@ const (
 	DiffSome DiffChanges = 2
 )
 
+// DiffKind represents the kind of change that applies to a single property.
+type DiffKind int
+
+const (
+	// DiffAdd indicates that the property was added.
+	DiffAdd DiffKind = 0
+	// DiffAddReplace indicates that the property was added and requires that the resource be replaced.
+	DiffAddReplace DiffKind = 1
+	// DiffDelete indicates that the property was deleted.
+	DiffDelete DiffKind = 2
+	// DiffDeleteReplace indicates that the property was deleted and requires that the resource be replaced.
+	DiffDeleteReplace DiffKind = 3
+	// DiffUpdate indicates that the property was updated.
+	DiffUpdate DiffKind = 4
+	// DiffUpdateReplace indicates that the property was updated and requires that the resource be replaced.
+	DiffUpdateReplace DiffKind = 5
+)
+
+// String returns a human-readable name for this kind of change.
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdd:
+		return "add"
+	case DiffAddReplace:
+		return "add-replace"
+	case DiffDelete:
+		return "delete"
+	case DiffDeleteReplace:
+		return "delete-replace"
+	case DiffUpdate:
+		return "update"
+	case DiffUpdateReplace:
+		return "update-replace"
+	default:
+		contract.Failf("Unknown diff kind %v", int(k))
+		return ""
+	}
+}
+
+// IsReplace returns true if this kind of change requires that the resource be replaced.
+func (k DiffKind) IsReplace() bool {
+	return k == DiffAddReplace || k == DiffDeleteReplace || k == DiffUpdateReplace
+}
+
+// PropertyDiff records the kind of change made to a single property.
+type PropertyDiff struct {
+	Kind      DiffKind // the kind of change.
+	InputDiff bool     // true if this change is between old and new inputs rather than old state and new inputs.
+}
+
 // DiffResult indicates whether an operation should replace or update an existing resource.
 type DiffResult struct {
-	Changes             DiffChanges            // true if this diff represents a changed resource.
-	ReplaceKeys         []resource.PropertyKey // an optional list of replacement keys.
-	StableKeys          []resource.PropertyKey // an optional list of property keys that are stable.
-	DeleteBeforeReplace bool                   // if true, this resource must be deleted before recreating it.
+	Changes             DiffChanges             // true if this diff represents a changed resource.
+	ReplaceKeys         []resource.PropertyKey  // an optional list of replacement keys.
+	StableKeys          []resource.PropertyKey  // an optional list of output keys that will not change unless replaced.
+	ChangedKeys         []resource.PropertyKey  // an optional list of property keys that changed.
+	DetailedDiff        map[string]PropertyDiff // an optional structured diff, keyed by property path.
+	DeleteBeforeReplace bool                    // if true, this resource must be deleted before recreating it.
+	PreviewOutputs      resource.PropertyMap    // an optional prediction of the outputs after the change, for previews.
 }
 
 // Replace returns true if this diff represents a replacement.
@@ -122,3 +182,81 @@ func DiffUnavailable(reason string) DiffUnavailableError {
 func (e DiffUnavailableError) Error() string {
 	return e.reason
 }
+
+// BatchProvider is implemented by providers that can read or diff many resources in a single call, which saves a
+// round trip per resource when refreshing large stacks.  The results correspond one-to-one with the requests, and each
+// holds what the equivalent call to Read or Diff would have returned; an error returned alongside the results applies
+// to the batch as a whole.
+type BatchProvider interface {
+	Provider
+
+	// ReadBatch reads the current live state of each of the given resources.
+	ReadBatch(reads []BatchRead) ([]BatchReadResult, error)
+	// DiffBatch checks what impacts each of the given hypothetical updates will have on its resource's properties.
+	DiffBatch(diffs []BatchDiff, allowUnknowns bool) ([]BatchDiffResult, error)
+}
+
+// BatchRead identifies a resource to read as part of a batch; its fields are the arguments to Read.
+type BatchRead struct {
+	URN   resource.URN
+	ID    resource.ID
+	Props resource.PropertyMap
+}
+
+// BatchReadResult is the outcome of reading a single resource as part of a batch; its fields are the results of Read.
+type BatchReadResult struct {
+	Outputs resource.PropertyMap // the resource's live state, or nil if the resource is missing.
+	Status  resource.Status      // the status of the resource.
+	Err     error                // non-nil if the resource could not be read.
+}
+
+// BatchDiff describes an update to diff as part of a batch; its fields are the arguments to Diff.
+type BatchDiff struct {
+	URN        resource.URN
+	ID         resource.ID
+	OldInputs  resource.PropertyMap
+	OldOutputs resource.PropertyMap
+	NewInputs  resource.PropertyMap
+}
+
+// BatchDiffResult is the outcome of diffing a single resource as part of a batch; its fields are the results of Diff.
+type BatchDiffResult struct {
+	Diff DiffResult // the impacts of the update.
+	Err  error      // non-nil if the resource could not be diffed.
+}
+
+// ReadBatch reads the current live state of each of the given resources using the given provider.  If the provider is
+// not a BatchProvider, each resource is read individually.
+func ReadBatch(prov Provider, reads []BatchRead) ([]BatchReadResult, error) {
+	if bp, ok := prov.(BatchProvider); ok {
+		return bp.ReadBatch(reads)
+	}
+	return readEach(prov, reads), nil
+}
+
+// DiffBatch diffs each of the given updates using the given provider.  If the provider is not a BatchProvider, each
+// update is diffed individually.
+func DiffBatch(prov Provider, diffs []BatchDiff, allowUnknowns bool) ([]BatchDiffResult, error) {
+	if bp, ok := prov.(BatchProvider); ok {
+		return bp.DiffBatch(diffs, allowUnknowns)
+	}
+	return diffEach(prov, diffs, allowUnknowns), nil
+}
+
+// readEach reads each of the given resources individually.
+func readEach(prov Provider, reads []BatchRead) []BatchReadResult {
+	results := make([]BatchReadResult, len(reads))
+	for i, r := range reads {
+		results[i].Outputs, results[i].Status, results[i].Err = prov.Read(r.URN, r.ID, r.Props)
+	}
+	return results
+}
+
+// diffEach diffs each of the given updates individually.
+func diffEach(prov Provider, diffs []BatchDiff, allowUnknowns bool) []BatchDiffResult {
+	results := make([]BatchDiffResult, len(diffs))
+	for i, d := range diffs {
+		results[i].Diff, results[i].Err = prov.Diff(d.URN, d.ID, d.OldInputs, d.OldOutputs, d.NewInputs, allowUnknowns)
+	}
+	return results
+}
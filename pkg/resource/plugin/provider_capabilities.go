@@ -0,0 +1,78 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// ProviderCapabilities describes the optional features that a provider supports.  Providers advertise their
+// capabilities when they are loaded, and the engine adapts how it talks to each provider accordingly.
+type ProviderCapabilities struct {
+	// Advertised is true if the provider advertised its capabilities.  If it is false, the provider predates
+	// capabilities, and the other fields hold what the engine assumes of it (see DefaultCapabilities).
+	Advertised bool
+	// AcceptSecrets is true if the provider accepts secret values, rather than only the values they wrap.
+	AcceptSecrets bool
+	// TypedUnknowns is true if the provider understands unknowns that carry the type of their eventual value.  Such
+	// providers are sent typed unknowns even if TypedUnknownsEnvVar is not set.
+	TypedUnknowns bool
+	// DetailedDiff is true if the provider's diffs describe the change to each property.
+	DetailedDiff bool
+	// Read is true if the provider can read the live state of its resources.  Resources whose providers cannot are
+	// left as they are by a refresh.
+	Read bool
+	// Batch is true if the provider implements ReadBatch and DiffBatch.  Providers that do not are never asked to.
+	Batch bool
+	// Int64Values is true if the provider accepts integers too large to be represented exactly by a double, encoded as
+	// strings.
+	Int64Values bool
+}
+
+// DefaultCapabilities are the capabilities assumed of providers that do not advertise their own.  Such providers are
+// asked to read resources and to batch their calls just as they always have been, and those that turn out not to
+// support batches are remembered as they fail; the features that older providers cannot be relied upon to understand
+// are left off.
+var DefaultCapabilities = ProviderCapabilities{Read: true, Batch: true}
+
+// CapabilityProvider is implemented by providers that advertise which optional features they support.
+type CapabilityProvider interface {
+	Provider
+
+	// Capabilities returns the optional features that the provider supports.
+	Capabilities() ProviderCapabilities
+}
+
+// GetCapabilities returns the capabilities of the given provider, or DefaultCapabilities if it is not a
+// CapabilityProvider.
+func GetCapabilities(prov Provider) ProviderCapabilities {
+	if cp, ok := prov.(CapabilityProvider); ok {
+		return cp.Capabilities()
+	}
+	return DefaultCapabilities
+}
+
+// unmarshalCapabilities converts the capabilities that a plugin advertised over RPC.
+func unmarshalCapabilities(caps *pulumirpc.ProviderCapabilities) ProviderCapabilities {
+	return ProviderCapabilities{
+		Advertised:    true,
+		AcceptSecrets: caps.GetAcceptSecrets(),
+		TypedUnknowns: caps.GetTypedUnknowns(),
+		DetailedDiff:  caps.GetDetailedDiff(),
+		Read:          caps.GetRead(),
+		Batch:         caps.GetBatch(),
+		Int64Values:   caps.GetInt64Values(),
+	}
+}
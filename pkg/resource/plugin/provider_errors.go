@@ -0,0 +1,162 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// ProviderErrorClass is the kind of failure that a provider reported.
+type ProviderErrorClass string
+
+const (
+	ProviderErrorUnknown   ProviderErrorClass = "unknown"   // the failure is not classified.
+	ProviderErrorRetryable ProviderErrorClass = "retryable" // the failure is transient.
+	ProviderErrorThrottled ProviderErrorClass = "throttled" // the provider's cloud is rate-limiting its requests.
+	ProviderErrorConflict  ProviderErrorClass = "conflict"  // the resource's state conflicts with the operation.
+	ProviderErrorNotFound  ProviderErrorClass = "not-found" // the resource does not exist.
+	ProviderErrorAuth      ProviderErrorClass = "auth"      // the provider's credentials are missing or insufficient.
+	ProviderErrorInvalid   ProviderErrorClass = "invalid"   // the request is invalid.
+)
+
+// defaultAuthRemediation is the remediation reported for authorization failures whose providers did not suggest one.
+const defaultAuthRemediation = "Check that the credentials configured for this provider are valid and are " +
+	"permitted to perform this operation."
+
+// ProviderError is a failure that a provider described in a structured way, so that the engine can act upon it:
+// retryable failures are retried, deleting a resource that is not found succeeds, and authorization failures are
+// reported along with how to correct them.  Providers return a ProviderError from any resource operation.
+type ProviderError struct {
+	Class       ProviderErrorClass // the kind of failure.
+	Message     string             // the failure's message.
+	Code        string             // the cloud's own code for the error, e.g. "ThrottlingException", if any.
+	Retryable   bool               // true if the operation may succeed if it is retried.
+	RetryAfter  time.Duration      // how long to wait before retrying, if the cloud suggested a delay.
+	Remediation string             // how to correct the failure, if known.
+}
+
+var _ error = (*ProviderError)(nil)
+
+func (e *ProviderError) Error() string {
+	msg := e.Message
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Code)
+	}
+	if remediation := e.remediation(); remediation != "" {
+		msg = fmt.Sprintf("%s\n%s", msg, remediation)
+	}
+	return msg
+}
+
+// remediation returns the remediation to report with the error.  Authorization failures are always reported with
+// one, since their fix is rarely found in the provider's code.
+func (e *ProviderError) remediation() string {
+	if e.Remediation == "" && e.Class == ProviderErrorAuth {
+		return defaultAuthRemediation
+	}
+	return e.Remediation
+}
+
+// IsRetryable returns true if the provider reported that the failed operation may succeed if it is retried.
+func (e *ProviderError) IsRetryable() bool {
+	return e.Retryable || e.Class == ProviderErrorRetryable || e.Class == ProviderErrorThrottled
+}
+
+// GetProviderError returns the ProviderError that caused the given error, if any.
+func GetProviderError(err error) (*ProviderError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	perr, ok := errors.Cause(err).(*ProviderError)
+	return perr, ok
+}
+
+// IsNotFound returns true if the given error is a provider's report that a resource does not exist.
+func IsNotFound(err error) bool {
+	perr, ok := GetProviderError(err)
+	return ok && perr.Class == ProviderErrorNotFound
+}
+
+// providerErrorClasses maps the classes of the provider protocol to ProviderErrorClasses.
+var providerErrorClasses = map[pulumirpc.ErrorProviderFailure_Class]ProviderErrorClass{
+	pulumirpc.ErrorProviderFailure_UNKNOWN:   ProviderErrorUnknown,
+	pulumirpc.ErrorProviderFailure_RETRYABLE: ProviderErrorRetryable,
+	pulumirpc.ErrorProviderFailure_THROTTLED: ProviderErrorThrottled,
+	pulumirpc.ErrorProviderFailure_CONFLICT:  ProviderErrorConflict,
+	pulumirpc.ErrorProviderFailure_NOT_FOUND: ProviderErrorNotFound,
+	pulumirpc.ErrorProviderFailure_AUTH:      ProviderErrorAuth,
+	pulumirpc.ErrorProviderFailure_INVALID:   ProviderErrorInvalid,
+}
+
+// providerErrorCodes are the gRPC status codes with which failures of each class are sent, so that engines that do
+// not understand ErrorProviderFailure details still see a sensible status.
+var providerErrorCodes = map[ProviderErrorClass]codes.Code{
+	ProviderErrorRetryable: codes.Unavailable,
+	ProviderErrorThrottled: codes.ResourceExhausted,
+	ProviderErrorConflict:  codes.Aborted,
+	ProviderErrorNotFound:  codes.NotFound,
+	ProviderErrorAuth:      codes.PermissionDenied,
+	ProviderErrorInvalid:   codes.InvalidArgument,
+}
+
+// MarshalProviderError converts a ProviderError into the RPC error that describes it to the engine.
+func MarshalProviderError(e *ProviderError) error {
+	code, ok := providerErrorCodes[e.Class]
+	if !ok {
+		code = codes.Unknown
+	}
+	class := pulumirpc.ErrorProviderFailure_UNKNOWN
+	for c, pc := range providerErrorClasses {
+		if pc == e.Class {
+			class = c
+		}
+	}
+	return rpcerror.WithDetails(rpcerror.New(code, e.Message), &pulumirpc.ErrorProviderFailure{
+		Class:       class,
+		Code:        e.Code,
+		Retryable:   e.Retryable,
+		RetryAfter:  e.RetryAfter.Seconds(),
+		Remediation: e.Remediation,
+	})
+}
+
+// providerError returns the ProviderError that the given RPC error describes, or the RPC error itself if it does not
+// describe one.
+func providerError(rpcErr *rpcerror.Error) error {
+	for _, detail := range rpcErr.Details() {
+		if failure, ok := detail.(*pulumirpc.ErrorProviderFailure); ok {
+			class, ok := providerErrorClasses[failure.GetClass()]
+			if !ok {
+				class = ProviderErrorUnknown
+			}
+			return &ProviderError{
+				Class:       class,
+				Message:     rpcErr.Message(),
+				Code:        failure.GetCode(),
+				Retryable:   failure.GetRetryable(),
+				RetryAfter:  time.Duration(failure.GetRetryAfter() * float64(time.Second)),
+				Remediation: failure.GetRemediation(),
+			}
+		}
+	}
+	return rpcErr
+}
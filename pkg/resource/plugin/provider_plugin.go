@@ -15,65 +15,169 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	pbempty "github.com/golang/protobuf/ptypes/empty"
 	_struct "github.com/golang/protobuf/ptypes/struct"
 	multierror "github.com/hashicorp/go-multierror"
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 
+	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil"
 	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
 	"github.com/pulumi/pulumi/pkg/workspace"
 	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
 )
 
+// maxProviderRestarts is the number of times a provider's plugin is restarted after crashing before its crashes are
+// treated as fatal.
+const maxProviderRestarts = 3
+
+// pluginExitGracePeriod is how long a failed RPC waits for the plugin's process to exit before concluding that the
+// plugin did not crash.  A crashing plugin's connection may close slightly before its process is reaped.
+var pluginExitGracePeriod = time.Second
+
 // provider reflects a resource plugin, loaded dynamically for a single package.
 type provider struct {
 	ctx       *Context                         // a plugin context for caching, etc.
 	pkg       tokens.Package                   // the Pulumi package containing this provider's resources.
+	path      string                           // the path to the plugin's binary.
+	args      []string                         // the arguments with which the plugin is launched.
+	transport rpcutil.TransportOptions         // the options for the plugin's RPC transport.
+	lock      sync.Mutex                       // guards the plugin and client, which change if the plugin restarts.
 	plug      *plugin                          // the actual plugin process wrapper.
 	clientRaw pulumirpc.ResourceProviderClient // the raw provider client; usually unsafe to use directly.
+	config    map[string]string                // the variables with which the plugin was configured, if any.
+	restarts  int                              // the number of times the plugin has been restarted after crashing.
+	closed    bool                             // true once the provider has been closed.
 	cfgerr    error                            // non-nil if a configure call fails.
 	cfgknown  bool                             // true if all configuration values are known.
 	cfgdone   chan bool                        // closed when configuration has completed.
+	typed     bool                             // true if unknowns are sent to the plugin as typed unknowns.
+	compat    bool                             // true if the plugin uses the compatible wire encoding.
+	large     LargeValueStore                  // the side channel for large property values, if any.
+	threshold int                              // the size in bytes above which values are sent over the side channel.
+	nobatch   bool                             // true once the plugin has reported that it lacks the batch RPCs.
+	caps      ProviderCapabilities             // the optional features that the plugin supports.
+}
+
+// ProviderCrashError is returned when a provider's plugin crashes during an operation that cannot be retried, either
+// because the operation may have changed the resource it was applied to or because the plugin could not be restarted.
+type ProviderCrashError struct {
+	Pkg        tokens.Package // the package whose provider crashed.
+	Operation  string         // the operation that was in flight when the provider crashed.
+	Mutation   bool           // true if the operation may have changed its resource, so its outcome is unknown.
+	RestartErr error          // non-nil if the provider could not be restarted.
+}
+
+func (e *ProviderCrashError) Error() string {
+	msg := fmt.Sprintf("the %s provider crashed during the %s", e.Pkg, e.Operation)
+	if e.Mutation {
+		msg += ", so the outcome of the operation is unknown"
+	}
+	if e.RestartErr != nil {
+		return fmt.Sprintf("%s; the provider could not be restarted: %v", msg, e.RestartErr)
+	}
+	return msg + "; the provider has been restarted"
 }
 
 // NewProvider attempts to bind to a given package's resource plugin and then creates a gRPC connection to it.  If the
 // plugin could not be found, or an error occurs while creating the child process, an error is returned.
 func NewProvider(host Host, ctx *Context, pkg tokens.Package, version *semver.Version) (Provider, error) {
 	// Load the plugin's path by using the standard workspace logic.
-	_, path, err := workspace.GetPluginPath(
-		workspace.ResourcePlugin, strings.Replace(string(pkg), tokens.QNameDelimiter, "_", -1), version)
+	name := strings.Replace(string(pkg), tokens.QNameDelimiter, "_", -1)
+	_, path, err := workspace.GetPluginPath(workspace.ResourcePlugin, name, version)
 	if err != nil {
 		return nil, err
 	} else if path == "" {
 		return nil, NewMissingError(workspace.PluginInfo{
 			Kind:    workspace.ResourcePlugin,
-			Name:    string(pkg),
+			Name:    name,
 			Version: version,
 		})
 	}
 
-	plug, err := newPlugin(ctx, path, fmt.Sprintf("%v (resource)", pkg), []string{host.ServerAddr()})
+	// Providers may be configured to use keepalives and mutual TLS; see rpcutil.TransportOptionsFromEnv.
+	transport, err := rpcutil.TransportOptionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// Large property values may be exchanged with providers out of band; see LargeValueDirEnvVar.
+	large, threshold, err := LargeValueStoreFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{host.ProviderServerAddr()}
+	plug, err := newPlugin(ctx, path, fmt.Sprintf("%v (resource)", pkg), args, transport)
 	if err != nil {
 		return nil, err
 	}
 	contract.Assertf(plug != nil, "unexpected nil resource plugin for %s", pkg)
 
-	return &provider{
+	p := &provider{
 		ctx:       ctx,
 		pkg:       pkg,
+		path:      path,
+		args:      args,
+		transport: transport,
 		plug:      plug,
 		clientRaw: pulumirpc.NewResourceProviderClient(plug.Conn),
 		cfgdone:   make(chan bool),
-	}, nil
+		typed:     cmdutil.IsTruthy(os.Getenv(TypedUnknownsEnvVar)),
+		compat:    cmdutil.IsTruthy(os.Getenv(CompatibleWireEnvVar)),
+		large:     large,
+		threshold: threshold,
+	}
+
+	// Ask the plugin which optional features it supports, and adapt to them.
+	p.caps = p.getCapabilities()
+	p.typed = p.typed || p.caps.TypedUnknowns
+	p.nobatch = !p.caps.Batch
+	return p, nil
+}
+
+// getCapabilities asks the plugin which optional features it supports.  Plugins that do not implement the
+// GetCapabilities RPC, or that fail to answer it, are assumed to have DefaultCapabilities.
+func (p *provider) getCapabilities() ProviderCapabilities {
+	label := fmt.Sprintf("%s.GetCapabilities()", p.label())
+	logging.V(7).Infof("%s executing", label)
+
+	// Like GetPluginInfo, GetCapabilities is called immediately after loading and does not require configuration.
+	var resp *pulumirpc.ProviderCapabilities
+	err := p.call(label, true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.GetCapabilities(p.ctx.Request(), &pbempty.Empty{})
+		return err
+	})
+	if err != nil {
+		logging.V(7).Infof("%s failed: err=%v; assuming default capabilities", label, rpcerror.Convert(err).Message())
+		return DefaultCapabilities
+	}
+
+	caps := unmarshalCapabilities(resp)
+	logging.V(7).Infof("%s success: %+v", label, caps)
+	return caps
+}
+
+// Capabilities returns the optional features that the plugin advertised when it was loaded.
+func (p *provider) Capabilities() ProviderCapabilities {
+	return p.caps
 }
 
 func (p *provider) Pkg() tokens.Package { return p.pkg }
@@ -83,6 +187,93 @@ func (p *provider) label() string {
 	return fmt.Sprintf("Provider[%s, %p]", p.pkg, p)
 }
 
+// marshalOptions returns the options used to exchange the properties of a resource of the given type with the plugin,
+// extending those in opts, which are specific to the operation.  An empty type indicates that the properties do not
+// belong to a resource.
+func (p *provider) marshalOptions(label string, typ tokens.Type, opts MarshalOptions) MarshalOptions {
+	opts.Label = label
+	opts.CompatibleWire = p.compat
+	opts.Redaction = p.ctx.Redaction
+	opts.RedactionType = typ
+	opts.LargeValueStore = p.large
+	opts.LargeValueThreshold = p.threshold
+	opts.Interner = p.ctx.Interner
+	return opts
+}
+
+// client returns the provider's current plugin and its RPC client.
+func (p *provider) client() (*plugin, pulumirpc.ResourceProviderClient) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.plug, p.clientRaw
+}
+
+// call issues an RPC to the provider's plugin.  If the plugin crashes during the RPC, it is restarted.  Idempotent
+// RPCs are then retried once against the restarted plugin; for any other RPC, the outcome is unknown, so a
+// ProviderCrashError is returned instead.
+func (p *provider) call(operation string, idempotent bool,
+	rpc func(client pulumirpc.ResourceProviderClient) error) error {
+
+	for retried := false; ; retried = true {
+		plug, client := p.client()
+		err := rpc(client)
+		if err == nil || !p.crashed(plug, err) {
+			return err
+		}
+
+		logging.V(5).Infof("%s: plugin crashed: %v", operation, err)
+		restartErr := p.restart(plug)
+		if !idempotent || retried || restartErr != nil {
+			return &ProviderCrashError{Pkg: p.pkg, Operation: operation, Mutation: !idempotent, RestartErr: restartErr}
+		}
+	}
+}
+
+// crashed returns true if the given RPC error was caused by the given plugin's process exiting unexpectedly.
+func (p *provider) crashed(plug *plugin, err error) bool {
+	if rpcErr, ok := rpcerror.FromError(err); !ok || rpcErr.Code() != codes.Unavailable {
+		return false
+	}
+
+	p.lock.Lock()
+	closed := p.closed
+	p.lock.Unlock()
+	return !closed && plug.waitForExit(pluginExitGracePeriod)
+}
+
+// restart replaces the given plugin, which has crashed, with a new instance of the provider's plugin and configures
+// the new instance as the crashed one was configured.  If another operation has already restarted the crashed plugin,
+// restart does nothing.
+func (p *provider) restart(crashed *plugin) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.plug != crashed {
+		return nil
+	}
+	if p.restarts >= maxProviderRestarts {
+		return errors.Errorf("the provider crashed %d times", p.restarts+1)
+	}
+	p.restarts++
+
+	contract.IgnoreError(crashed.Close())
+	plug, err := newPlugin(p.ctx, p.path, fmt.Sprintf("%v (resource)", p.pkg), p.args, p.transport)
+	if err != nil {
+		return err
+	}
+	client := pulumirpc.NewResourceProviderClient(plug.Conn)
+	if p.config != nil {
+		if _, err := client.Configure(p.ctx.Request(), &pulumirpc.ConfigureRequest{Variables: p.config}); err != nil {
+			contract.IgnoreError(plug.Close())
+			return createConfigureError(rpcerror.Convert(err))
+		}
+	}
+
+	p.ctx.Diag.Warningf(diag.Message("" /*urn*/, "the %s provider crashed and has been restarted"), p.pkg)
+	p.plug, p.clientRaw = plug, client
+	return nil
+}
+
 // CheckConfig validates the configuration for this resource provider.
 func (p *provider) CheckConfig(olds, news resource.PropertyMap) (resource.PropertyMap, []CheckFailure, error) {
 	// Ensure that all config values are strings or unknowns.
@@ -118,15 +309,6 @@ func (p *provider) DiffConfig(olds, news resource.PropertyMap) (DiffResult, erro
 	return DiffResult{Changes: DiffUnknown, ReplaceKeys: nil}, nil
 }
 
-// getClient returns the client, and ensures that the target provider has been configured.  This just makes it safer
-// to use without forgetting to call ensureConfigured manually.
-func (p *provider) getClient() (pulumirpc.ResourceProviderClient, error) {
-	if err := p.ensureConfigured(); err != nil {
-		return nil, err
-	}
-	return p.clientRaw, nil
-}
-
 // ensureConfigured blocks waiting for the plugin to be configured.  To improve parallelism, all Configure RPCs
 // occur in parallel, and we await the completion of them at the last possible moment.  This does mean, however, that
 // we might discover failures later than we would have otherwise, but the caller of ensureConfigured will get them.
@@ -165,12 +347,18 @@ func (p *provider) Configure(inputs resource.PropertyMap) error {
 
 	// Spawn the configure to happen in parallel.  This ensures that we remain responsive elsewhere that might
 	// want to make forward progress, even as the configure call is happening.
+	_, client := p.client()
 	go func() {
-		_, err := p.clientRaw.Configure(p.ctx.Request(), &pulumirpc.ConfigureRequest{Variables: config})
+		_, err := client.Configure(p.ctx.Request(), &pulumirpc.ConfigureRequest{Variables: config})
 		if err != nil {
 			rpcError := rpcerror.Convert(err)
 			logging.V(7).Infof("%s failed: err=%v", label, rpcError.Message())
 			err = createConfigureError(rpcError)
+		} else {
+			// Remember the configuration so that the plugin can be reconfigured if it crashes and must be restarted.
+			p.lock.Lock()
+			p.config = config
+			p.lock.Unlock()
 		}
 		// Acquire the lock, publish the results, and notify any waiters.
 		p.cfgknown, p.cfgerr = true, err
@@ -183,50 +371,68 @@ func (p *provider) Configure(inputs resource.PropertyMap) error {
 // Check validates that the given property bag is valid for a resource of the given type.
 func (p *provider) Check(urn resource.URN,
 	olds, news resource.PropertyMap, allowUnknowns bool) (resource.PropertyMap, []CheckFailure, error) {
+	inputs, _, failures, err := p.CheckPreview(urn, olds, news, allowUnknowns)
+	return inputs, failures, err
+}
+
+// CheckPreview validates that the given property bag is valid for a resource of the given type, and returns any
+// outputs that the plugin predicted for the resource.
+func (p *provider) CheckPreview(urn resource.URN, olds, news resource.PropertyMap,
+	allowUnknowns bool) (resource.PropertyMap, resource.PropertyMap, []CheckFailure, error) {
 	label := fmt.Sprintf("%s.Check(%s)", p.label(), urn)
+	span, rpcCtx := p.startRPCSpan(p.ctx.Request(), "check", urn)
+	defer span.Finish()
 	logging.V(7).Infof("%s executing (#olds=%d,#news=%d", label, len(olds), len(news))
 
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
-	if err != nil {
-		return nil, nil, err
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
+		return nil, nil, nil, err
 	}
 
 	// If the configuration for this provider was not fully known--e.g. if we are doing a preview and some input
 	// property was sourced from another resource's output properties--don't call into the underlying provider.
 	if !p.cfgknown {
-		return news, nil, nil
+		return news, nil, nil, nil
 	}
 
-	molds, err := MarshalProperties(olds, MarshalOptions{Label: fmt.Sprintf("%s.olds", label),
-		KeepUnknowns: allowUnknowns})
+	molds, err := marshalTraced(span, "olds", olds, p.marshalOptions(
+		fmt.Sprintf("%s.olds", label), urn.Type(), MarshalOptions{KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, diag.AttachURN(err, urn)
 	}
-	mnews, err := MarshalProperties(news, MarshalOptions{Label: fmt.Sprintf("%s.news", label),
-		KeepUnknowns: allowUnknowns})
+	mnews, err := marshalTraced(span, "news", news, p.marshalOptions(
+		fmt.Sprintf("%s.news", label), urn.Type(), MarshalOptions{KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, diag.AttachURN(err, urn)
 	}
 
-	resp, err := client.Check(p.ctx.Request(), &pulumirpc.CheckRequest{
-		Urn:  string(urn),
-		Olds: molds,
-		News: mnews,
+	var resp *pulumirpc.CheckResponse
+	err = p.call(fmt.Sprintf("check of %s", urn), true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.Check(rpcCtx, &pulumirpc.CheckRequest{
+			Urn:  string(urn),
+			Olds: molds,
+			News: mnews,
+		})
+		return err
 	})
-	if err != nil {
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, nil, nil, crashErr
+	} else if err != nil {
 		rpcError := rpcerror.Convert(err)
 		logging.V(7).Infof("%s failed: err=%v", label, rpcError.Message())
-		return nil, nil, rpcError
+		return nil, nil, nil, providerError(rpcError)
 	}
 
 	// Unmarshal the provider inputs.
 	var inputs resource.PropertyMap
 	if ins := resp.GetInputs(); ins != nil {
-		inputs, err = UnmarshalProperties(ins, MarshalOptions{
-			Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: allowUnknowns, RejectUnknowns: !allowUnknowns})
+		inputs, err = unmarshalTraced(span, "inputs", ins, p.marshalOptions(
+			fmt.Sprintf("%s.inputs", label), urn.Type(),
+			MarshalOptions{KeepUnknowns: allowUnknowns, RejectUnknowns: !allowUnknowns}))
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, diag.AttachURN(err, urn)
 		}
 	}
 
@@ -236,24 +442,37 @@ func (p *provider) Check(urn resource.URN,
 		failures = append(failures, CheckFailure{resource.PropertyKey(failure.Property), failure.Reason})
 	}
 
-	logging.V(7).Infof("%s success: inputs=#%d failures=#%d", label, len(inputs), len(failures))
-	return inputs, failures, nil
+	// Unmarshal any outputs that the plugin predicted.  These may always contain unknowns.
+	var previews resource.PropertyMap
+	if outs := resp.GetPreviewOutputs(); outs != nil {
+		previews, err = unmarshalTraced(span, "previewOutputs", outs, p.marshalOptions(
+			fmt.Sprintf("%s.previewOutputs", label), urn.Type(), MarshalOptions{KeepUnknowns: true}))
+		if err != nil {
+			return nil, nil, nil, diag.AttachURN(err, urn)
+		}
+	}
+
+	logging.V(7).Infof("%s success: inputs=#%d previews=#%d failures=#%d",
+		label, len(inputs), len(previews), len(failures))
+	return inputs, previews, failures, nil
 }
 
 // Diff checks what impacts a hypothetical update will have on the resource's properties.
 func (p *provider) Diff(urn resource.URN, id resource.ID,
-	olds resource.PropertyMap, news resource.PropertyMap, allowUnknowns bool) (DiffResult, error) {
+	oldInputs, oldOutputs, newInputs resource.PropertyMap, allowUnknowns bool) (DiffResult, error) {
 	contract.Assert(urn != "")
 	contract.Assert(id != "")
-	contract.Assert(news != nil)
-	contract.Assert(olds != nil)
+	contract.Assert(newInputs != nil)
+	contract.Assert(oldOutputs != nil)
 
 	label := fmt.Sprintf("%s.Diff(%s,%s)", p.label(), urn, id)
-	logging.V(7).Infof("%s: executing (#olds=%d,#news=%d)", label, len(olds), len(news))
+	span, rpcCtx := p.startRPCSpan(p.ctx.Request(), "diff", urn)
+	defer span.Finish()
+	logging.V(7).Infof("%s: executing (#oldInputs=%d,#oldOutputs=%d,#newInputs=%d)",
+		label, len(oldInputs), len(oldOutputs), len(newInputs))
 
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
-	if err != nil {
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
 		return DiffResult{}, err
 	}
 
@@ -267,29 +486,50 @@ func (p *provider) Diff(urn resource.URN, id resource.ID,
 		return DiffResult{}, DiffUnavailable(message)
 	}
 
-	molds, err := MarshalProperties(olds, MarshalOptions{
-		Label: fmt.Sprintf("%s.olds", label), ElideAssetContents: true, KeepUnknowns: allowUnknowns})
+	molds, err := marshalTraced(span, "oldOutputs", oldOutputs, p.marshalOptions(
+		fmt.Sprintf("%s.olds", label), urn.Type(),
+		MarshalOptions{ElideAssetContents: true, KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
+	if err != nil {
+		return DiffResult{}, err
+	}
+	moldInputs, err := marshalTraced(span, "oldInputs", oldInputs, p.marshalOptions(
+		fmt.Sprintf("%s.oldInputs", label), urn.Type(),
+		MarshalOptions{ElideAssetContents: true, KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
 	if err != nil {
 		return DiffResult{}, err
 	}
-	mnews, err := MarshalProperties(news, MarshalOptions{Label: fmt.Sprintf("%s.news", label),
-		KeepUnknowns: allowUnknowns})
+	mnews, err := marshalTraced(span, "newInputs", newInputs, p.marshalOptions(
+		fmt.Sprintf("%s.news", label), urn.Type(), MarshalOptions{KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
 	if err != nil {
 		return DiffResult{}, err
 	}
 
-	resp, err := client.Diff(p.ctx.Request(), &pulumirpc.DiffRequest{
-		Id:   string(id),
-		Urn:  string(urn),
-		Olds: molds,
-		News: mnews,
+	var resp *pulumirpc.DiffResponse
+	err = p.call(fmt.Sprintf("diff of %s", urn), true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.Diff(rpcCtx, &pulumirpc.DiffRequest{
+			Id:        string(id),
+			Urn:       string(urn),
+			Olds:      molds,
+			News:      mnews,
+			OldInputs: moldInputs,
+		})
+		return err
 	})
-	if err != nil {
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return DiffResult{}, crashErr
+	} else if err != nil {
 		rpcError := rpcerror.Convert(err)
 		logging.V(7).Infof("%s failed: %v", label, rpcError.Message())
-		return DiffResult{}, rpcError
+		return DiffResult{}, providerError(rpcError)
 	}
 
+	return p.diffResult(label, urn, resp)
+}
+
+// diffResult converts a plugin's response to a Diff request into a DiffResult.
+func (p *provider) diffResult(label string, urn resource.URN, resp *pulumirpc.DiffResponse) (DiffResult, error) {
 	var replaces []resource.PropertyKey
 	for _, replace := range resp.GetReplaces() {
 		replaces = append(replaces, resource.PropertyKey(replace))
@@ -298,35 +538,74 @@ func (p *provider) Diff(urn resource.URN, id resource.ID,
 	for _, stable := range resp.GetStables() {
 		stables = append(stables, resource.PropertyKey(stable))
 	}
+	var diffs []resource.PropertyKey
+	for _, diff := range resp.GetDiffs() {
+		diffs = append(diffs, resource.PropertyKey(diff))
+	}
+	var detailedDiff map[string]PropertyDiff
+	if resp.GetHasDetailedDiff() {
+		detailedDiff = make(map[string]PropertyDiff)
+		for path, pd := range resp.GetDetailedDiff() {
+			kind := DiffKind(pd.GetKind())
+			if kind < DiffAdd || kind > DiffUpdateReplace {
+				return DiffResult{}, errors.Errorf("%s returned an unknown kind of change (%d) for property '%s'",
+					label, int(kind), path)
+			}
+			detailedDiff[path] = PropertyDiff{
+				Kind:      kind,
+				InputDiff: pd.GetInputDiff(),
+			}
+		}
+	}
+	var previews resource.PropertyMap
+	if outs := resp.GetPreviewOutputs(); outs != nil {
+		var err error
+		previews, err = UnmarshalProperties(outs, p.marshalOptions(
+			fmt.Sprintf("%s.previewOutputs", label), urn.Type(), MarshalOptions{KeepUnknowns: true}))
+		if err != nil {
+			return DiffResult{}, err
+		}
+	}
 	changes := resp.GetChanges()
 	deleteBeforeReplace := resp.GetDeleteBeforeReplace()
-	logging.V(7).Infof("%s success: changes=%d #replaces=%v #stables=%v delbefrepl=%v",
-		label, changes, replaces, stables, deleteBeforeReplace)
+	logging.V(7).Infof("%s success: changes=%d #replaces=%v #stables=%v #diffs=%v #detailed=%v delbefrepl=%v",
+		label, changes, replaces, stables, diffs, len(detailedDiff), deleteBeforeReplace)
 	return DiffResult{
 		Changes:             DiffChanges(changes),
 		ReplaceKeys:         replaces,
 		StableKeys:          stables,
+		ChangedKeys:         diffs,
+		DetailedDiff:        detailedDiff,
 		DeleteBeforeReplace: deleteBeforeReplace,
+		PreviewOutputs:      previews,
 	}, nil
 }
 
 // Create allocates a new instance of the provided resource and assigns its unique resource.ID and outputs afterwards.
-func (p *provider) Create(urn resource.URN, props resource.PropertyMap) (resource.ID,
+func (p *provider) Create(urn resource.URN, props resource.PropertyMap, timeout float64) (resource.ID,
 	resource.PropertyMap, resource.Status, error) {
+	return p.create(p.ctx.Request(), urn, props, timeout)
+}
+
+// create is Create, tracing the operation as a child of the span in the given context.
+func (p *provider) create(ctx context.Context, urn resource.URN, props resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, resource.Status, error) {
 	contract.Assert(urn != "")
 	contract.Assert(props != nil)
 
 	label := fmt.Sprintf("%s.Create(%s)", p.label(), urn)
+	span, rpcCtx := p.startRPCSpan(ctx, "create", urn)
+	defer span.Finish()
 	logging.V(7).Infof("%s executing (#props=%v)", label, len(props))
 
-	mprops, err := MarshalProperties(props, MarshalOptions{Label: fmt.Sprintf("%s.inputs", label)})
+	mprops, err := marshalTraced(span, "inputs", props, p.marshalOptions(
+		fmt.Sprintf("%s.inputs", label), urn.Type(), MarshalOptions{}))
 	if err != nil {
 		return "", nil, resource.StatusOK, err
 	}
 
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
-	if err != nil {
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
 		return "", nil, resource.StatusOK, err
 	}
 
@@ -337,11 +616,20 @@ func (p *provider) Create(urn resource.URN, props resource.PropertyMap) (resourc
 	var liveObject *_struct.Struct
 	var resourceError error
 	var resourceStatus = resource.StatusOK
-	resp, err := client.Create(p.ctx.Request(), &pulumirpc.CreateRequest{
-		Urn:        string(urn),
-		Properties: mprops,
+	var resp *pulumirpc.CreateResponse
+	err = p.call(fmt.Sprintf("create of %s", urn), false, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.Create(rpcCtx, &pulumirpc.CreateRequest{
+			Urn:        string(urn),
+			Properties: mprops,
+			Timeout:    timeout,
+		})
+		return err
 	})
-	if err != nil {
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return "", nil, resource.StatusUnknown, crashErr
+	} else if err != nil {
 		resourceStatus, id, liveObject, resourceError = parseError(err)
 		logging.V(7).Infof("%s failed: %v", label, resourceError)
 
@@ -359,8 +647,8 @@ func (p *provider) Create(urn resource.URN, props resource.PropertyMap) (resourc
 			errors.Errorf("plugin for package '%v' returned empty resource.ID from create '%v'", p.pkg, urn)
 	}
 
-	outs, err := UnmarshalProperties(liveObject, MarshalOptions{
-		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true})
+	outs, err := unmarshalTraced(span, "outputs", liveObject, p.marshalOptions(
+		fmt.Sprintf("%s.outputs", label), urn.Type(), MarshalOptions{RejectUnknowns: true}))
 	if err != nil {
 		return "", nil, resourceStatus, err
 	}
@@ -377,15 +665,22 @@ func (p *provider) Create(urn resource.URN, props resource.PropertyMap) (resourc
 func (p *provider) Read(
 	urn resource.URN, id resource.ID, props resource.PropertyMap,
 ) (resource.PropertyMap, resource.Status, error) {
+	return p.read(p.ctx.Request(), urn, id, props)
+}
+
+// read is Read, tracing the operation as a child of the span in the given context.
+func (p *provider) read(ctx context.Context, urn resource.URN, id resource.ID,
+	props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
 	contract.Assert(urn != "")
 	contract.Assert(id != "")
 
 	label := fmt.Sprintf("%s.Read(%s,%s)", p.label(), id, urn)
+	span, rpcCtx := p.startRPCSpan(ctx, "read", urn)
+	defer span.Finish()
 	logging.V(7).Infof("%s executing (#props=%v)", label, len(props))
 
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
-	if err != nil {
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
 		return nil, resource.StatusUnknown, err
 	}
 
@@ -394,8 +689,13 @@ func (p *provider) Read(
 		return resource.PropertyMap{}, resource.StatusUnknown, nil
 	}
 
+	if p.caps.Advertised && !p.caps.Read {
+		return nil, resource.StatusOK, errors.Errorf("the %s provider does not support reading resources", p.pkg)
+	}
+
 	// Marshal the input state so we can perform the RPC.
-	marshaled, err := MarshalProperties(props, MarshalOptions{Label: label, ElideAssetContents: true})
+	marshaled, err := marshalTraced(span, "props", props, p.marshalOptions(
+		label, urn.Type(), MarshalOptions{ElideAssetContents: true}))
 	if err != nil {
 		return nil, resource.StatusUnknown, err
 	}
@@ -405,12 +705,20 @@ func (p *provider) Read(
 	var liveObject *_struct.Struct
 	var resourceError error
 	var resourceStatus = resource.StatusOK
-	resp, err := client.Read(p.ctx.Request(), &pulumirpc.ReadRequest{
-		Id:         string(id),
-		Urn:        string(urn),
-		Properties: marshaled,
+	var resp *pulumirpc.ReadResponse
+	err = p.call(fmt.Sprintf("read of %s", urn), true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.Read(rpcCtx, &pulumirpc.ReadRequest{
+			Id:         string(id),
+			Urn:        string(urn),
+			Properties: marshaled,
+		})
+		return err
 	})
-	if err != nil {
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, resource.StatusUnknown, crashErr
+	} else if err != nil {
 		resourceStatus, readID, liveObject, resourceError = parseError(err)
 		logging.V(7).Infof("%s failed: %v", label, err)
 
@@ -423,6 +731,15 @@ func (p *provider) Read(
 		liveObject = resp.GetProperties()
 	}
 
+	return p.readResult(span, label, urn, id, readID, liveObject, resourceStatus, resourceError)
+}
+
+// readResult interprets the ID and live state that the plugin returned from reading the resource with the given URN
+// and ID.  An empty ID means that the resource is missing, in which case the resulting property map is nil.
+func (p *provider) readResult(span *rpcSpan, label string, urn resource.URN, id, readID resource.ID,
+	liveObject *_struct.Struct, resourceStatus resource.Status,
+	resourceError error) (resource.PropertyMap, resource.Status, error) {
+
 	// If the resource was missing, simply return a nil property map.
 	if string(readID) == "" {
 		return nil, resourceStatus, nil
@@ -432,8 +749,8 @@ func (p *provider) Read(
 	}
 
 	// Finally, unmarshal the resulting state properties and return them.
-	results, err := UnmarshalProperties(liveObject, MarshalOptions{
-		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true})
+	results, err := unmarshalTraced(span, "outputs", liveObject, p.marshalOptions(
+		fmt.Sprintf("%s.outputs", label), urn.Type(), MarshalOptions{RejectUnknowns: true}))
 	if err != nil {
 		return nil, resourceStatus, err
 	}
@@ -443,45 +760,69 @@ func (p *provider) Read(
 }
 
 // Update updates an existing resource with new values.
-func (p *provider) Update(urn resource.URN, id resource.ID,
-	olds resource.PropertyMap, news resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+func (p *provider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
+	timeout float64) (resource.PropertyMap, resource.Status, error) {
+	return p.update(p.ctx.Request(), urn, id, oldInputs, oldOutputs, newInputs, timeout)
+}
+
+// update is Update, tracing the operation as a child of the span in the given context.
+func (p *provider) update(ctx context.Context, urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
 	contract.Assert(urn != "")
 	contract.Assert(id != "")
-	contract.Assert(news != nil)
-	contract.Assert(olds != nil)
+	contract.Assert(newInputs != nil)
+	contract.Assert(oldOutputs != nil)
 
 	label := fmt.Sprintf("%s.Update(%s,%s)", p.label(), id, urn)
-	logging.V(7).Infof("%s executing (#olds=%v,#news=%v)", label, len(olds), len(news))
+	span, rpcCtx := p.startRPCSpan(ctx, "update", urn)
+	defer span.Finish()
+	logging.V(7).Infof("%s executing (#oldInputs=%v,#oldOutputs=%v,#newInputs=%v)",
+		label, len(oldInputs), len(oldOutputs), len(newInputs))
 
-	molds, err := MarshalProperties(olds, MarshalOptions{
-		Label: fmt.Sprintf("%s.olds", label), ElideAssetContents: true})
+	molds, err := marshalTraced(span, "oldOutputs", oldOutputs, p.marshalOptions(
+		fmt.Sprintf("%s.olds", label), urn.Type(), MarshalOptions{ElideAssetContents: true}))
 	if err != nil {
 		return nil, resource.StatusOK, err
 	}
-	mnews, err := MarshalProperties(news, MarshalOptions{Label: fmt.Sprintf("%s.news", label)})
+	moldInputs, err := marshalTraced(span, "oldInputs", oldInputs, p.marshalOptions(
+		fmt.Sprintf("%s.oldInputs", label), urn.Type(), MarshalOptions{ElideAssetContents: true}))
 	if err != nil {
 		return nil, resource.StatusOK, err
 	}
-
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
+	mnews, err := marshalTraced(span, "newInputs", newInputs, p.marshalOptions(
+		fmt.Sprintf("%s.news", label), urn.Type(), MarshalOptions{}))
 	if err != nil {
 		return nil, resource.StatusOK, err
 	}
 
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
+		return nil, resource.StatusOK, err
+	}
+
 	// We should only be calling {Create,Update,Delete} if the provider is fully configured.
 	contract.Assert(p.cfgknown)
 
 	var liveObject *_struct.Struct
 	var resourceError error
 	var resourceStatus = resource.StatusOK
-	resp, err := client.Update(p.ctx.Request(), &pulumirpc.UpdateRequest{
-		Id:   string(id),
-		Urn:  string(urn),
-		Olds: molds,
-		News: mnews,
+	var resp *pulumirpc.UpdateResponse
+	err = p.call(fmt.Sprintf("update of %s", urn), false, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.Update(rpcCtx, &pulumirpc.UpdateRequest{
+			Id:        string(id),
+			Urn:       string(urn),
+			Olds:      molds,
+			News:      mnews,
+			OldInputs: moldInputs,
+			Timeout:   timeout,
+		})
+		return err
 	})
-	if err != nil {
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, resource.StatusUnknown, crashErr
+	} else if err != nil {
 		resourceStatus, _, liveObject, resourceError = parseError(err)
 		logging.V(7).Infof("%s failed: %v", label, resourceError)
 
@@ -493,8 +834,8 @@ func (p *provider) Update(urn resource.URN, id resource.ID,
 		liveObject = resp.GetProperties()
 	}
 
-	outs, err := UnmarshalProperties(liveObject, MarshalOptions{
-		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true})
+	outs, err := unmarshalTraced(span, "outputs", liveObject, p.marshalOptions(
+		fmt.Sprintf("%s.outputs", label), urn.Type(), MarshalOptions{RejectUnknowns: true}))
 	if err != nil {
 		return nil, resourceStatus, err
 	}
@@ -507,52 +848,255 @@ func (p *provider) Update(urn resource.URN, id resource.ID,
 }
 
 // Delete tears down an existing resource.
-func (p *provider) Delete(urn resource.URN, id resource.ID, props resource.PropertyMap) (resource.Status, error) {
+func (p *provider) Delete(urn resource.URN, id resource.ID, props resource.PropertyMap,
+	timeout float64) (resource.Status, error) {
+	return p.delete(p.ctx.Request(), urn, id, props, timeout)
+}
+
+// delete is Delete, tracing the operation as a child of the span in the given context.
+func (p *provider) delete(ctx context.Context, urn resource.URN, id resource.ID, props resource.PropertyMap,
+	timeout float64) (resource.Status, error) {
 	contract.Assert(urn != "")
 	contract.Assert(id != "")
 
 	label := fmt.Sprintf("%s.Delete(%s,%s)", p.label(), urn, id)
+	span, rpcCtx := p.startRPCSpan(ctx, "delete", urn)
+	defer span.Finish()
 	logging.V(7).Infof("%s executing (#props=%d)", label, len(props))
 
-	mprops, err := MarshalProperties(props, MarshalOptions{Label: label, ElideAssetContents: true})
+	mprops, err := marshalTraced(span, "props", props, p.marshalOptions(
+		label, urn.Type(), MarshalOptions{ElideAssetContents: true}))
 	if err != nil {
 		return resource.StatusOK, err
 	}
 
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
-	if err != nil {
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
 		return resource.StatusOK, err
 	}
 
 	// We should only be calling {Create,Update,Delete} if the provider is fully configured.
 	contract.Assert(p.cfgknown)
 
-	if _, err := client.Delete(p.ctx.Request(), &pulumirpc.DeleteRequest{
-		Id:         string(id),
-		Urn:        string(urn),
-		Properties: mprops,
-	}); err != nil {
+	err = p.call(fmt.Sprintf("delete of %s", urn), false, func(client pulumirpc.ResourceProviderClient) error {
+		_, err := client.Delete(rpcCtx, &pulumirpc.DeleteRequest{
+			Id:         string(id),
+			Urn:        string(urn),
+			Properties: mprops,
+			Timeout:    timeout,
+		})
+		return err
+	})
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return resource.StatusUnknown, crashErr
+	} else if err != nil {
 		resourceStatus, rpcErr := resourceStateAndError(err)
 		logging.V(7).Infof("%s failed: %v", label, rpcErr)
-		return resourceStatus, rpcErr
+		return resourceStatus, providerError(rpcErr)
 	}
 
 	logging.V(7).Infof("%s success", label)
 	return resource.StatusOK, nil
 }
 
+// batchUnimplemented records whether or not the given error reports that the plugin does not implement a batch RPC.
+// Once a plugin has reported this, the batch operations read or diff each resource individually instead.
+func (p *provider) batchUnimplemented(err error) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err != nil {
+		if rpcErr, ok := rpcerror.FromError(err); ok && rpcErr.Code() == codes.Unimplemented {
+			p.nobatch = true
+		}
+	}
+	return p.nobatch
+}
+
+// ReadBatch reads the current live state of each of the given resources in a single RPC.  If the plugin does not
+// implement the ReadBatch RPC, each resource is read individually.
+func (p *provider) ReadBatch(reads []BatchRead) ([]BatchReadResult, error) {
+	label := fmt.Sprintf("%s.ReadBatch(#reads=%d)", p.label(), len(reads))
+	logging.V(7).Infof("%s executing", label)
+
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
+		return nil, err
+	}
+
+	// Individual reads already handle providers whose configuration is not fully known.
+	if !p.cfgknown || p.batchUnimplemented(nil) {
+		return readEach(p, reads), nil
+	}
+
+	span, rpcCtx := p.startRPCSpan(p.ctx.Request(), "readBatch", "")
+	defer span.Finish()
+
+	req := &pulumirpc.ReadBatchRequest{Requests: make([]*pulumirpc.ReadRequest, len(reads))}
+	for i, r := range reads {
+		contract.Assert(r.URN != "")
+		contract.Assert(r.ID != "")
+		marshaled, err := MarshalProperties(r.Props, p.marshalOptions(
+			fmt.Sprintf("%s.props(%s)", label, r.URN), r.URN.Type(), MarshalOptions{ElideAssetContents: true}))
+		if err != nil {
+			return nil, err
+		}
+		req.Requests[i] = &pulumirpc.ReadRequest{Id: string(r.ID), Urn: string(r.URN), Properties: marshaled}
+	}
+
+	var resp *pulumirpc.ReadBatchResponse
+	operation := fmt.Sprintf("read of %d resources", len(reads))
+	err := p.call(operation, true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.ReadBatch(rpcCtx, req)
+		return err
+	})
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, crashErr
+	} else if err != nil {
+		if p.batchUnimplemented(err) {
+			logging.V(7).Infof("%s: plugin does not implement ReadBatch; reading each resource", label)
+			return readEach(p, reads), nil
+		}
+		rpcError := rpcerror.Convert(err)
+		logging.V(7).Infof("%s failed: %v", label, rpcError.Message())
+		return nil, rpcError
+	}
+	if len(resp.GetResults()) != len(reads) {
+		return nil, errors.Errorf("plugin for package '%v' returned %d results from a batch of %d reads",
+			p.pkg, len(resp.GetResults()), len(reads))
+	}
+
+	// Interpret each result just as Read would have: an initialization failure is a partial failure, and an empty ID
+	// means that the resource is missing.
+	results := make([]BatchReadResult, len(reads))
+	for i, r := range resp.GetResults() {
+		urn, id := reads[i].URN, reads[i].ID
+		itemLabel := fmt.Sprintf("%s.Read(%s,%s)", p.label(), id, urn)
+		if msg := r.GetError(); msg != "" && r.GetInitFailed() == nil {
+			logging.V(7).Infof("%s failed: %v", itemLabel, msg)
+			results[i] = BatchReadResult{Status: resource.StatusOK, Err: errors.New(msg)}
+			continue
+		}
+
+		readID, liveObject := resource.ID(r.GetResponse().GetId()), r.GetResponse().GetProperties()
+		var resourceError error
+		var resourceStatus = resource.StatusOK
+		if initErr := r.GetInitFailed(); initErr != nil {
+			readID, liveObject = resource.ID(initErr.GetId()), initErr.GetProperties()
+			resourceStatus, resourceError = resource.StatusPartialFailure, &InitError{Reasons: initErr.GetReasons()}
+		}
+		results[i].Outputs, results[i].Status, results[i].Err = p.readResult(span, itemLabel, urn, id, readID,
+			liveObject, resourceStatus, resourceError)
+	}
+	return results, nil
+}
+
+// DiffBatch checks what impacts each of the given hypothetical updates will have on its resource's properties in a
+// single RPC.  If the plugin does not implement the DiffBatch RPC, each update is diffed individually.
+func (p *provider) DiffBatch(diffs []BatchDiff, allowUnknowns bool) ([]BatchDiffResult, error) {
+	label := fmt.Sprintf("%s.DiffBatch(#diffs=%d)", p.label(), len(diffs))
+	logging.V(7).Infof("%s executing", label)
+
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
+		return nil, err
+	}
+
+	// Individual diffs already handle providers whose configuration is not fully known.
+	if !p.cfgknown || p.batchUnimplemented(nil) {
+		return diffEach(p, diffs, allowUnknowns), nil
+	}
+
+	span, rpcCtx := p.startRPCSpan(p.ctx.Request(), "diffBatch", "")
+	defer span.Finish()
+
+	req := &pulumirpc.DiffBatchRequest{Requests: make([]*pulumirpc.DiffRequest, len(diffs))}
+	for i, d := range diffs {
+		contract.Assert(d.URN != "")
+		contract.Assert(d.ID != "")
+		contract.Assert(d.NewInputs != nil)
+		contract.Assert(d.OldOutputs != nil)
+
+		itemLabel := fmt.Sprintf("%s.Diff(%s,%s)", p.label(), d.URN, d.ID)
+		molds, err := MarshalProperties(d.OldOutputs, p.marshalOptions(
+			fmt.Sprintf("%s.olds", itemLabel), d.URN.Type(),
+			MarshalOptions{ElideAssetContents: true, KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
+		if err != nil {
+			return nil, err
+		}
+		moldInputs, err := MarshalProperties(d.OldInputs, p.marshalOptions(
+			fmt.Sprintf("%s.oldInputs", itemLabel), d.URN.Type(),
+			MarshalOptions{ElideAssetContents: true, KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
+		if err != nil {
+			return nil, err
+		}
+		mnews, err := MarshalProperties(d.NewInputs, p.marshalOptions(
+			fmt.Sprintf("%s.news", itemLabel), d.URN.Type(),
+			MarshalOptions{KeepUnknowns: allowUnknowns, KeepUnknownTypes: p.typed}))
+		if err != nil {
+			return nil, err
+		}
+		req.Requests[i] = &pulumirpc.DiffRequest{
+			Id:        string(d.ID),
+			Urn:       string(d.URN),
+			Olds:      molds,
+			News:      mnews,
+			OldInputs: moldInputs,
+		}
+	}
+
+	var resp *pulumirpc.DiffBatchResponse
+	operation := fmt.Sprintf("diff of %d resources", len(diffs))
+	err := p.call(operation, true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.DiffBatch(rpcCtx, req)
+		return err
+	})
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, crashErr
+	} else if err != nil {
+		if p.batchUnimplemented(err) {
+			logging.V(7).Infof("%s: plugin does not implement DiffBatch; diffing each resource", label)
+			return diffEach(p, diffs, allowUnknowns), nil
+		}
+		rpcError := rpcerror.Convert(err)
+		logging.V(7).Infof("%s failed: %v", label, rpcError.Message())
+		return nil, rpcError
+	}
+	if len(resp.GetResults()) != len(diffs) {
+		return nil, errors.Errorf("plugin for package '%v' returned %d results from a batch of %d diffs",
+			p.pkg, len(resp.GetResults()), len(diffs))
+	}
+
+	results := make([]BatchDiffResult, len(diffs))
+	for i, r := range resp.GetResults() {
+		itemLabel := fmt.Sprintf("%s.Diff(%s,%s)", p.label(), diffs[i].URN, diffs[i].ID)
+		if msg := r.GetError(); msg != "" {
+			logging.V(7).Infof("%s failed: %v", itemLabel, msg)
+			results[i].Err = errors.New(msg)
+			continue
+		}
+		results[i].Diff, results[i].Err = p.diffResult(itemLabel, diffs[i].URN, r.GetResponse())
+	}
+	return results, nil
+}
+
 // Invoke dynamically executes a built-in function in the provider.
 func (p *provider) Invoke(tok tokens.ModuleMember, args resource.PropertyMap) (resource.PropertyMap,
 	[]CheckFailure, error) {
 	contract.Assert(tok != "")
 
 	label := fmt.Sprintf("%s.Invoke(%s)", p.label(), tok)
+	span, rpcCtx := p.startRPCSpan(p.ctx.Request(), "invoke", "", opentracing.Tag{Key: "tok", Value: string(tok)})
+	defer span.Finish()
 	logging.V(7).Infof("%s executing (#args=%d)", label, len(args))
 
-	// Get the RPC client and ensure it's configured.
-	client, err := p.getClient()
-	if err != nil {
+	// Ensure that the provider is configured.
+	if err := p.ensureConfigured(); err != nil {
 		return nil, nil, err
 	}
 
@@ -561,21 +1105,29 @@ func (p *provider) Invoke(tok tokens.ModuleMember, args resource.PropertyMap) (r
 		return resource.PropertyMap{}, nil, nil
 	}
 
-	margs, err := MarshalProperties(args, MarshalOptions{Label: fmt.Sprintf("%s.args", label)})
+	margs, err := marshalTraced(span, "args", args, p.marshalOptions(fmt.Sprintf("%s.args", label), "", MarshalOptions{}))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	resp, err := client.Invoke(p.ctx.Request(), &pulumirpc.InvokeRequest{Tok: string(tok), Args: margs})
-	if err != nil {
+	var resp *pulumirpc.InvokeResponse
+	err = p.call(fmt.Sprintf("invoke of %s", tok), true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.Invoke(rpcCtx, &pulumirpc.InvokeRequest{Tok: string(tok), Args: margs})
+		return err
+	})
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, nil, crashErr
+	} else if err != nil {
 		rpcError := rpcerror.Convert(err)
 		logging.V(7).Infof("%s failed: %v", label, rpcError.Message())
 		return nil, nil, rpcError
 	}
 
 	// Unmarshal any return values.
-	ret, err := UnmarshalProperties(resp.GetReturn(), MarshalOptions{
-		Label: fmt.Sprintf("%s.returns", label), RejectUnknowns: true})
+	ret, err := unmarshalTraced(span, "return", resp.GetReturn(), p.marshalOptions(
+		fmt.Sprintf("%s.returns", label), "", MarshalOptions{RejectUnknowns: true}))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -596,8 +1148,9 @@ func (p *provider) GetPluginInfo() (workspace.PluginInfo, error) {
 	logging.V(7).Infof("%s executing", label)
 
 	// Calling GetPluginInfo happens immediately after loading, and does not require configuration to proceed.
-	// Thus, we access the clientRaw property, rather than calling getClient.
-	resp, err := p.clientRaw.GetPluginInfo(p.ctx.Request(), &pbempty.Empty{})
+	// Thus, we do not wait for the provider to be configured.
+	plug, client := p.client()
+	resp, err := client.GetPluginInfo(p.ctx.Request(), &pbempty.Empty{})
 	if err != nil {
 		rpcError := rpcerror.Convert(err)
 		logging.V(7).Infof("%s failed: err=%v", label, rpcError.Message())
@@ -615,14 +1168,44 @@ func (p *provider) GetPluginInfo() (workspace.PluginInfo, error) {
 
 	return workspace.PluginInfo{
 		Name:    string(p.pkg),
-		Path:    p.plug.Bin,
+		Path:    plug.Bin,
 		Kind:    workspace.ResourcePlugin,
 		Version: version,
 	}, nil
 }
 
+// GetSchema asks the plugin for its schema.  Plugins that do not implement the GetSchema RPC return
+// ErrSchemaUnsupported.
+func (p *provider) GetSchema(version int) ([]byte, error) {
+	label := fmt.Sprintf("%s.GetSchema(%d)", p.label(), version)
+	logging.V(7).Infof("%s executing", label)
+
+	// Like GetPluginInfo, GetSchema describes the plugin rather than any resources, and does not require configuration.
+	var resp *pulumirpc.GetSchemaResponse
+	err := p.call(label, true, func(client pulumirpc.ResourceProviderClient) error {
+		var err error
+		resp, err = client.GetSchema(p.ctx.Request(), &pulumirpc.GetSchemaRequest{Version: int32(version)})
+		return err
+	})
+	if crashErr, isCrash := err.(*ProviderCrashError); isCrash {
+		logging.V(7).Infof("%s failed: %v", label, crashErr)
+		return nil, crashErr
+	} else if err != nil {
+		rpcError := rpcerror.Convert(err)
+		logging.V(7).Infof("%s failed: err=%v", label, rpcError.Message())
+		if rpcError.Code() == codes.Unimplemented {
+			return nil, ErrSchemaUnsupported
+		}
+		return nil, rpcError
+	}
+
+	logging.V(7).Infof("%s success: %d bytes", label, len(resp.GetSchema()))
+	return []byte(resp.GetSchema()), nil
+}
+
 func (p *provider) SignalCancellation() error {
-	_, err := p.clientRaw.Cancel(p.ctx.Request(), &pbempty.Empty{})
+	_, client := p.client()
+	_, err := client.Cancel(p.ctx.Request(), &pbempty.Empty{})
 	if err != nil {
 		rpcError := rpcerror.Convert(err)
 		logging.V(8).Infof("provider received rpc error `%s`: `%s`", rpcError.Code(),
@@ -639,7 +1222,19 @@ func (p *provider) SignalCancellation() error {
 
 // Close tears down the underlying plugin RPC connection and process.
 func (p *provider) Close() error {
-	return p.plug.Close()
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.closed = true
+	err := p.plug.Close()
+
+	// Now that the plugin has exited, remove any large values that were sent to it.
+	if closer, ok := p.large.(io.Closer); ok {
+		if cerr := closer.Close(); cerr != nil {
+			err = multierror.Append(err, cerr)
+		}
+	}
+	return err
 }
 
 // createConfigureError creates a nice error message from an RPC error that
@@ -706,8 +1301,8 @@ func parseError(err error) (
 	contract.Assert(responseErr != nil)
 
 	// If resource was successfully created but failed to initialize, the error will be packed
-	// with the live properties of the object.
-	resourceErr = responseErr
+	// with the live properties of the object. Otherwise, the provider may have described the failure.
+	resourceErr = providerError(responseErr)
 	for _, detail := range responseErr.Details() {
 		if initErr, ok := detail.(*pulumirpc.ErrorResourceInitFailed); ok {
 			id = resource.ID(initErr.GetId())
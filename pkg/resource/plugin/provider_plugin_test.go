@@ -0,0 +1,343 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+func TestProviderCrashes(t *testing.T) {
+	pluginExitGracePeriod = 10 * time.Millisecond
+
+	exited := make(chan bool)
+	close(exited)
+	unavailable := status.Error(codes.Unavailable, "transport is closing")
+	failWith := func(err error) func(pulumirpc.ResourceProviderClient) error {
+		return func(pulumirpc.ResourceProviderClient) error { return err }
+	}
+
+	// A plugin whose process has exited has crashed. This provider has already restarted its plugin as many times as
+	// it may, so the crash is reported rather than retried, and a mutation's outcome is reported as unknown.
+	p := &provider{pkg: "pkgA", plug: &plugin{exited: exited}, restarts: maxProviderRestarts}
+	err := p.call("create of resA", false, failWith(unavailable))
+	crashErr, isCrash := err.(*ProviderCrashError)
+	if assert.True(t, isCrash) {
+		assert.True(t, crashErr.Mutation)
+		assert.Error(t, crashErr.RestartErr)
+		assert.Contains(t, crashErr.Error(), "the pkgA provider crashed during the create of resA")
+		assert.Contains(t, crashErr.Error(), "the outcome of the operation is unknown")
+	}
+
+	err = p.call("read of resA", true, failWith(unavailable))
+	crashErr, isCrash = err.(*ProviderCrashError)
+	if assert.True(t, isCrash) {
+		assert.False(t, crashErr.Mutation)
+		assert.NotContains(t, crashErr.Error(), "outcome")
+	}
+
+	// Errors from a plugin that is still running are returned as-is.
+	p.plug = &plugin{exited: make(chan bool)}
+	assert.Equal(t, unavailable, p.call("create of resA", false, failWith(unavailable)))
+
+	// As are errors other than a lost connection, even if the plugin has exited.
+	p.plug = &plugin{exited: exited}
+	internal := status.Error(codes.Internal, "internal error")
+	assert.Equal(t, internal, p.call("create of resA", false, failWith(internal)))
+	other := errors.New("not an RPC error")
+	assert.Equal(t, other, p.call("create of resA", false, failWith(other)))
+
+	// A plugin that exits because its provider was closed has not crashed.
+	p.closed = true
+	assert.Equal(t, unavailable, p.call("create of resA", false, failWith(unavailable)))
+}
+
+// batchClient is a provider client whose resources each have a single `name` output.  Only the reads that the tests
+// exercise are implemented; if batch is false, the ReadBatch RPC is unimplemented.
+type batchClient struct {
+	pulumirpc.ResourceProviderClient
+
+	batch       bool
+	reads       int
+	batchReads  int
+	initFailure string
+}
+
+func (c *batchClient) read(req *pulumirpc.ReadRequest) *pulumirpc.ReadBatchResponse_Result {
+	if req.GetId() == "missing" {
+		return &pulumirpc.ReadBatchResponse_Result{Response: &pulumirpc.ReadResponse{}}
+	}
+	props, err := MarshalProperties(resource.PropertyMap{"name": resource.NewStringProperty(req.GetId())},
+		MarshalOptions{})
+	if err != nil {
+		return &pulumirpc.ReadBatchResponse_Result{Error: err.Error()}
+	}
+	if req.GetId() == c.initFailure {
+		return &pulumirpc.ReadBatchResponse_Result{InitFailed: &pulumirpc.ErrorResourceInitFailed{
+			Id:         req.GetId(),
+			Properties: props,
+			Reasons:    []string{"not healthy"},
+		}}
+	}
+	return &pulumirpc.ReadBatchResponse_Result{Response: &pulumirpc.ReadResponse{Id: req.GetId(), Properties: props}}
+}
+
+func (c *batchClient) Read(ctx context.Context, req *pulumirpc.ReadRequest,
+	opts ...grpc.CallOption) (*pulumirpc.ReadResponse, error) {
+
+	c.reads++
+	return c.read(req).GetResponse(), nil
+}
+
+func (c *batchClient) ReadBatch(ctx context.Context, req *pulumirpc.ReadBatchRequest,
+	opts ...grpc.CallOption) (*pulumirpc.ReadBatchResponse, error) {
+
+	if !c.batch {
+		return nil, status.Error(codes.Unimplemented, "unknown method ReadBatch")
+	}
+	c.batchReads++
+	var results []*pulumirpc.ReadBatchResponse_Result
+	for _, r := range req.GetRequests() {
+		if r.GetId() == "broken" {
+			results = append(results, &pulumirpc.ReadBatchResponse_Result{Error: "access denied"})
+		} else {
+			results = append(results, c.read(r))
+		}
+	}
+	return &pulumirpc.ReadBatchResponse{Results: results}, nil
+}
+
+func TestProviderReadBatch(t *testing.T) {
+	cfgdone := make(chan bool)
+	close(cfgdone)
+	newProvider := func(client *batchClient) *provider {
+		return &provider{ctx: &Context{}, pkg: "pkgA", plug: &plugin{}, clientRaw: client, cfgdone: cfgdone,
+			cfgknown: true}
+	}
+	urn := resource.NewURN("stack", "proj", "", "pkgA:index:Thing", "thing")
+	reads := []BatchRead{{URN: urn, ID: "a"}, {URN: urn, ID: "missing"}, {URN: urn, ID: "b"}, {URN: urn, ID: "broken"}}
+
+	// A plugin that implements ReadBatch reads every resource in one call, and each result is interpreted as Read's
+	// would be: an empty ID means that the resource is missing, and an initialization failure is a partial failure.
+	client := &batchClient{batch: true, initFailure: "b"}
+	results, err := newProvider(client).ReadBatch(reads)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.batchReads)
+	assert.Equal(t, 0, client.reads)
+	if assert.Len(t, results, 4) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, resource.PropertyMap{"name": resource.NewStringProperty("a")}, results[0].Outputs)
+		assert.NoError(t, results[1].Err)
+		assert.Nil(t, results[1].Outputs)
+		assert.Equal(t, resource.StatusPartialFailure, results[2].Status)
+		assert.Equal(t, &InitError{Reasons: []string{"not healthy"}}, results[2].Err)
+		assert.Equal(t, resource.PropertyMap{"name": resource.NewStringProperty("b")}, results[2].Outputs)
+		assert.EqualError(t, results[3].Err, "access denied")
+	}
+
+	// A plugin that does not implement ReadBatch has each resource read individually, and is not asked again.
+	client = &batchClient{}
+	prov := newProvider(client)
+	for i := 0; i < 2; i++ {
+		results, err = prov.ReadBatch(reads[:3])
+		assert.NoError(t, err)
+		if assert.Len(t, results, 3) {
+			assert.Equal(t, resource.PropertyMap{"name": resource.NewStringProperty("a")}, results[0].Outputs)
+			assert.Nil(t, results[1].Outputs)
+			assert.Equal(t, resource.PropertyMap{"name": resource.NewStringProperty("b")}, results[2].Outputs)
+		}
+	}
+	assert.Equal(t, 6, client.reads)
+	assert.True(t, prov.nobatch)
+}
+
+// capabilitiesClient is a provider client that advertises the given capabilities, or that does not implement the
+// GetCapabilities RPC if they are nil.
+type capabilitiesClient struct {
+	pulumirpc.ResourceProviderClient
+
+	caps *pulumirpc.ProviderCapabilities
+}
+
+func (c *capabilitiesClient) GetCapabilities(ctx context.Context, in *pbempty.Empty,
+	opts ...grpc.CallOption) (*pulumirpc.ProviderCapabilities, error) {
+
+	if c.caps == nil {
+		return nil, status.Error(codes.Unimplemented, "unknown method GetCapabilities")
+	}
+	return c.caps, nil
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	cfgdone := make(chan bool)
+	close(cfgdone)
+	newProvider := func(client *capabilitiesClient) *provider {
+		return &provider{ctx: &Context{}, pkg: "pkgA", plug: &plugin{}, clientRaw: client, cfgdone: cfgdone,
+			cfgknown: true}
+	}
+
+	// A plugin that does not advertise its capabilities is assumed to have the default ones.
+	assert.Equal(t, DefaultCapabilities, newProvider(&capabilitiesClient{}).getCapabilities())
+
+	// Otherwise, the plugin's own capabilities are used, and it is not asked to do what it cannot.
+	p := newProvider(&capabilitiesClient{caps: &pulumirpc.ProviderCapabilities{TypedUnknowns: true}})
+	p.caps = p.getCapabilities()
+	assert.Equal(t, ProviderCapabilities{Advertised: true, TypedUnknowns: true}, p.caps)
+	assert.Equal(t, p.caps, GetCapabilities(p))
+
+	urn := resource.NewURN("stack", "proj", "", "pkgA:index:Thing", "thing")
+	_, _, err := p.Read(urn, "a", resource.PropertyMap{})
+	assert.EqualError(t, err, "the pkgA provider does not support reading resources")
+}
+
+// schemaClient is a provider client that publishes the given schema, or that does not implement the GetSchema RPC if
+// it is empty.
+type schemaClient struct {
+	pulumirpc.ResourceProviderClient
+
+	schema  string
+	version int32
+}
+
+func (c *schemaClient) GetSchema(ctx context.Context, in *pulumirpc.GetSchemaRequest,
+	opts ...grpc.CallOption) (*pulumirpc.GetSchemaResponse, error) {
+
+	if c.schema == "" {
+		return nil, status.Error(codes.Unimplemented, "unknown method GetSchema")
+	}
+	c.version = in.GetVersion()
+	return &pulumirpc.GetSchemaResponse{Schema: c.schema}, nil
+}
+
+func TestProviderSchema(t *testing.T) {
+	newProvider := func(client *schemaClient) *provider {
+		return &provider{ctx: &Context{}, pkg: "pkgA", plug: &plugin{}, clientRaw: client}
+	}
+
+	// Plugins that do not implement GetSchema do not publish a schema.
+	_, err := GetSchema(newProvider(&schemaClient{}), 1)
+	assert.Equal(t, ErrSchemaUnsupported, err)
+
+	// Otherwise, the plugin is asked for the requested version of its schema, and need not be configured first.
+	client := &schemaClient{schema: `{"name":"pkgA"}`}
+	data, err := GetSchema(newProvider(client), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"pkgA"}`, string(data))
+	assert.Equal(t, int32(1), client.version)
+}
+
+func TestProviderErrors(t *testing.T) {
+	// A failure that a provider describes survives the trip over RPC.
+	perr := &ProviderError{
+		Class:       ProviderErrorThrottled,
+		Message:     "slow down",
+		Code:        "ThrottlingException",
+		Retryable:   true,
+		RetryAfter:  1500 * time.Millisecond,
+		Remediation: "Request a higher limit.",
+	}
+	rpcErr := rpcerror.Convert(MarshalProviderError(perr))
+	assert.Equal(t, codes.ResourceExhausted, rpcErr.Code())
+	assert.Equal(t, perr, providerError(rpcErr))
+	assert.Equal(t, "slow down (ThrottlingException)\nRequest a higher limit.", perr.Error())
+	assert.True(t, perr.IsRetryable())
+
+	// Authorization failures are always reported with a remediation.
+	auth := &ProviderError{Class: ProviderErrorAuth, Message: "access denied"}
+	assert.Equal(t, "access denied\n"+defaultAuthRemediation, auth.Error())
+	assert.False(t, auth.IsRetryable())
+
+	notFound := &ProviderError{Class: ProviderErrorNotFound, Message: "no such bucket"}
+	status, err := resourceStateAndError(MarshalProviderError(notFound))
+	assert.Equal(t, resource.StatusOK, status)
+	assert.True(t, IsNotFound(providerError(err)))
+	assert.True(t, IsNotFound(pkgerrors.Wrap(notFound, "deleting")))
+	assert.False(t, IsNotFound(auth))
+
+	// Errors that do not describe a failure are returned as-is.
+	plain := rpcerror.Convert(rpcerror.New(codes.Unavailable, "try again"))
+	assert.Equal(t, plain, providerError(plain))
+	_, ok := GetProviderError(plain)
+	assert.False(t, ok)
+}
+
+func TestProviderDiffKinds(t *testing.T) {
+	p := &provider{ctx: &Context{}, pkg: "pkgA", plug: &plugin{}}
+
+	diff, err := p.diffResult("diff", "", &pulumirpc.DiffResponse{
+		HasDetailedDiff: true,
+		DetailedDiff: map[string]*pulumirpc.PropertyDiff{
+			"foo": {Kind: pulumirpc.PropertyDiff_UPDATE_REPLACE},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]PropertyDiff{"foo": {Kind: DiffUpdateReplace}}, diff.DetailedDiff)
+
+	// A kind of change that the engine does not know is an error rather than a crash.
+	_, err = p.diffResult("diff", "", &pulumirpc.DiffResponse{
+		HasDetailedDiff: true,
+		DetailedDiff: map[string]*pulumirpc.PropertyDiff{
+			"foo": {Kind: pulumirpc.PropertyDiff_Kind(42)},
+		},
+	})
+	assert.EqualError(t, err, "diff returned an unknown kind of change (42) for property 'foo'")
+}
+
+// echoClient is a provider client whose creates return their inputs as outputs.
+type echoClient struct {
+	pulumirpc.ResourceProviderClient
+
+	req *pulumirpc.CreateRequest
+}
+
+func (c *echoClient) Create(ctx context.Context, req *pulumirpc.CreateRequest,
+	opts ...grpc.CallOption) (*pulumirpc.CreateResponse, error) {
+
+	c.req = req
+	return &pulumirpc.CreateResponse{Id: "a", Properties: req.GetProperties()}, nil
+}
+
+func TestProviderLargeValues(t *testing.T) {
+	cfgdone := make(chan bool)
+	close(cfgdone)
+	client := &echoClient{}
+	p := &provider{ctx: &Context{}, pkg: "pkgA", plug: &plugin{}, clientRaw: client, cfgdone: cfgdone,
+		cfgknown: true, large: NewMemoryLargeValueStore(), threshold: 16}
+
+	// Values over the threshold are sent to the plugin as references, and references in its results are resolved.
+	urn := resource.NewURN("stack", "proj", "", "pkgA:index:Thing", "thing")
+	code := strings.Repeat("x", 64)
+	props := resource.PropertyMap{"code": resource.NewStringProperty(code), "name": resource.NewStringProperty("a")}
+	_, outs, _, err := p.Create(urn, props, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, props, outs)
+	sent := client.req.GetProperties().GetFields()
+	assert.Equal(t, LargeValueSig, sent["code"].GetStructValue().GetFields()[resource.SigKey].GetStringValue())
+	assert.Equal(t, "a", sent["name"].GetStringValue())
+}
@@ -0,0 +1,44 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// PreviewProvider is implemented by providers that can predict some of the outputs of the resources that they have yet
+// to create, such as identifiers that are derived from their inputs.  Reporting these outputs during previews, rather
+// than leaving them unknown, keeps unknowns from cascading through the resources that depend on them.  Providers
+// predict the outputs of updates and replacements through DiffResult.PreviewOutputs.
+type PreviewProvider interface {
+	Provider
+
+	// CheckPreview checks a resource just as Check does, and also returns the outputs that the provider expects the
+	// resource to have once it is created.  Outputs that cannot be predicted are left out.
+	CheckPreview(urn resource.URN, olds, news resource.PropertyMap,
+		allowUnknowns bool) (resource.PropertyMap, resource.PropertyMap, []CheckFailure, error)
+}
+
+// CheckPreview checks a resource using the given provider.  If the provider is a PreviewProvider, the outputs that it
+// predicts for the resource are returned along with its inputs; otherwise, they are nil.
+func CheckPreview(prov Provider, urn resource.URN, olds, news resource.PropertyMap,
+	allowUnknowns bool) (resource.PropertyMap, resource.PropertyMap, []CheckFailure, error) {
+
+	if pp, ok := prov.(PreviewProvider); ok {
+		return pp.CheckPreview(urn, olds, news, allowUnknowns)
+	}
+	inputs, failures, err := prov.Check(urn, olds, news, allowUnknowns)
+	return inputs, nil, failures, err
+}
@@ -0,0 +1,40 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"github.com/pkg/errors"
+)
+
+// SchemaProvider is implemented by providers that can describe the resource types that they manage and the properties
+// of those types.  The schema is an opaque JSON document as far as this package is concerned; the schema package
+// defines its format and generates code from it.
+type SchemaProvider interface {
+	Provider
+
+	// GetSchema returns the provider's schema, encoded in the given version of the schema format.
+	GetSchema(version int) ([]byte, error)
+}
+
+// ErrSchemaUnsupported is returned by GetSchema for providers that do not publish a schema.
+var ErrSchemaUnsupported = errors.New("provider does not publish a schema")
+
+// GetSchema returns the schema of the given provider, or ErrSchemaUnsupported if it is not a SchemaProvider.
+func GetSchema(prov Provider, version int) ([]byte, error) {
+	if sp, ok := prov.(SchemaProvider); ok {
+		return sp.GetSchema(version)
+	}
+	return nil, ErrSchemaUnsupported
+}
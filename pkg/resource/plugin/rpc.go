@@ -15,13 +15,16 @@
 package plugin
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 
 	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/pkg/errors"
 
+	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 )
@@ -29,11 +32,33 @@ import (
 // MarshalOptions controls the marshaling of RPC structures.
 type MarshalOptions struct {
 	Label              string // an optional label for debugging.
-	SkipNulls          bool   // true to skip nulls altogether in the resulting map.
+	SkipNulls          bool   // true to skip nulls altogether (this erases the distinction between null and absent).
+	ElideNulls         bool   // true to leave nulls out of arrays, as well as objects, when marshaling.
 	KeepUnknowns       bool   // true if we are keeping unknown values (otherwise we skip them).
+	KeepOutputs        bool   // true to treat output values as unknowns (otherwise we skip them).
 	RejectUnknowns     bool   // true if we should return errors on unknown values. Takes precedence over KeepUnknowns.
+	KeepUnknownTypes   bool   // true if kept unknowns should be marshaled as typed unknowns rather than sentinels.
 	ElideAssetContents bool   // true if we are eliding the contents of assets.
 	ComputeAssetHashes bool   // true if we are computing missing asset hashes on the fly.
+	CompatibleWire     bool   // true to use the encoding that other Pulumi SDKs understand (see rpc_compat.go).
+	KeepSecrets        bool   // true to preserve secrets (otherwise they are sent and received as plain values).
+
+	// LargeValueThreshold, if positive, is the size in bytes above which string values are offloaded to
+	// LargeValueStore rather than being sent inline.  Offloaded values are replaced by a small reference object.
+	LargeValueThreshold int
+	// LargeValueStore is the side channel used to offload and resolve large values.
+	LargeValueStore LargeValueStore
+	// Interner, if non-nil, is used to deduplicate the keys and strings of unmarshaled values.
+	Interner *resource.Interner
+	// UnorderedPaths names array-valued properties whose element order is insignificant.  Before a property map is
+	// marshaled, the arrays at these paths are sorted by element hash, so reordering them does not change the payload.
+	UnorderedPaths []resource.PropertyPath
+	// Redaction, if non-nil, decides which property values are replaced by their digests when properties are logged.
+	Redaction resource.RedactionPolicy
+	// RedactionType is the type of the resource whose properties are being marshaled, if any, so that the parts of the
+	// Redaction policy that apply to that type take effect.  If it is empty, only the parts of the policy that apply
+	// to resources of every type do.
+	RedactionType tokens.Type
 }
 
 const (
@@ -60,20 +85,61 @@ const (
 	UnknownObjectValue = "dd056dcd-154b-4c76-9bd3-c8f88648b5ff"
 )
 
-// MarshalProperties marshals a resource's property map as a "JSON-like" protobuf structure.
+// loggable returns the value of the given top-level property as it may be logged, with any sensitive parts redacted.
+func loggable(key resource.PropertyKey, v resource.PropertyValue, opts MarshalOptions) resource.PropertyValue {
+	if opts.Redaction == nil {
+		return v
+	}
+	return resource.PropertyMap{key: v}.Redacted(opts.RedactionType, opts.Redaction)[key]
+}
+
+// dropsValue returns true if MarshalPropertyValue marshals the given value to nil.  Unknown values are dropped unless
+// they are kept or rejected, and output values are dropped unless they are kept and so are unknowns (or unknowns are
+// rejected).
+func (opts MarshalOptions) dropsValue(v resource.PropertyValue) bool {
+	if v.IsComputed() {
+		return !opts.KeepUnknowns && !opts.RejectUnknowns
+	} else if v.IsOutput() {
+		return !opts.KeepOutputs || (!opts.KeepUnknowns && !opts.RejectUnknowns)
+	}
+	return false
+}
+
+// skipsProperty returns true if an object property with the given value is left out of the marshaled object.  This
+// applies at every depth, not just to the top-level properties of a resource.
+func (opts MarshalOptions) skipsProperty(v resource.PropertyValue) bool {
+	return opts.dropsValue(v) || (v.IsNull() && (opts.SkipNulls || opts.ElideNulls))
+}
+
+// elidesElement returns true if an array element with the given value is left out of the marshaled array.  Unless
+// ElideNulls is set, elements that are dropped are marshaled as nulls instead, so that the other elements keep their
+// indices.
+func (opts MarshalOptions) elidesElement(v resource.PropertyValue) bool {
+	return opts.ElideNulls && (v.IsNull() || opts.dropsValue(v))
+}
+
+// MarshalProperties marshals a resource's property map as a "JSON-like" protobuf structure.  Output values, unknown
+// values that are not kept, and nulls (if SkipNulls or ElideNulls is set) are left out of the structure, as well as
+// out of any objects nested within it.
 func MarshalProperties(props resource.PropertyMap, opts MarshalOptions) (*structpb.Struct, error) {
+	// Unordered paths are relative to the top-level map, so sort them once here rather than in nested objects.
+	if len(opts.UnorderedPaths) > 0 {
+		props = props.SortSets(opts.UnorderedPaths)
+		opts.UnorderedPaths = nil
+	}
+
 	fields := make(map[string]*structpb.Value)
 	for _, key := range props.StableKeys() {
 		v := props[key]
-		logging.V(9).Infof("Marshaling property for RPC[%s]: %s=%v", opts.Label, key, v)
-		if v.IsOutput() {
+		logging.V(9).Infof("Marshaling property for RPC[%s]: %s=%v", opts.Label, key, loggable(key, v, opts))
+		if v.IsOutput() && opts.skipsProperty(v) {
 			logging.V(9).Infof("Skipping output property for RPC[%s]: %v", opts.Label, key)
-		} else if opts.SkipNulls && v.IsNull() {
+		} else if v.IsNull() && opts.skipsProperty(v) {
 			logging.V(9).Infof("Skipping null property for RPC[%s]: %s (as requested)", opts.Label, key)
 		} else {
 			m, err := MarshalPropertyValue(v, opts)
 			if err != nil {
-				return nil, err
+				return nil, diag.PrefixPath(err, string(key))
 			} else if m != nil {
 				fields[string(key)] = m
 			}
@@ -101,13 +167,59 @@ func MarshalPropertyValue(v resource.PropertyValue, opts MarshalOptions) (*struc
 			},
 		}, nil
 	} else if v.IsString() {
-		return MarshalString(v.StringValue(), opts), nil
+		s := v.StringValue()
+		if opts.shouldOffload(s) {
+			return marshalLargeValue(s, opts)
+		}
+		return MarshalString(s, opts), nil
+	} else if v.IsJSON() {
+		// JSON values are sent to providers as the strings that hold them.
+		return MarshalPropertyValue(resource.NewStringProperty(v.JSONValue().Raw), opts)
+	} else if v.IsApplied() || (v.IsFormat() && opts.CompatibleWire) {
+		// Applied values cannot be sent as-is, so they are resolved when they are marshaled.  Neither can formats
+		// sent to other SDKs.
+		resolved, err := v.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		return MarshalPropertyValue(resolved, opts)
+	} else if v.IsFileRef() {
+		// File references are sent as the strings that they refer to, which are only read at this point.
+		s, err := v.FileRefValue().Read()
+		if err != nil {
+			return nil, err
+		}
+		return MarshalPropertyValue(resource.NewStringProperty(s), opts)
+	} else if v.IsSecret() {
+		// Secrets are sent as objects that wrap their values if they are kept, and as the plain values otherwise.
+		// Unknown secrets are sent as plain unknowns, which are kept or dropped like any other.
+		elem := v.SecretValue().Element
+		if !opts.KeepSecrets || elem.IsComputed() || elem.IsOutput() {
+			return MarshalPropertyValue(elem, opts)
+		}
+		return MarshalPropertyValue(resource.NewObjectProperty(resource.PropertyMap{
+			resource.SigKey: resource.NewStringProperty(resource.SecretSig),
+			secretValueKey:  elem,
+		}), opts)
+	} else if v.IsFormat() {
+		// Formats are sent as objects that carry their template and arguments so the engine can evaluate them.
+		f := v.FormatValue()
+		return MarshalPropertyValue(resource.NewObjectProperty(resource.PropertyMap{
+			resource.SigKey: resource.NewStringProperty(resource.FormatSig),
+			"template":      resource.NewStringProperty(f.Template),
+			"args":          resource.NewArrayProperty(f.Args),
+		}), opts)
 	} else if v.IsArray() {
 		var elems []*structpb.Value
-		for _, elem := range v.ArrayValue() {
+		for i, elem := range v.ArrayValue() {
+			if opts.elidesElement(elem) {
+				continue
+			}
 			e, err := MarshalPropertyValue(elem, opts)
 			if err != nil {
-				return nil, err
+				return nil, diag.PrefixIndex(err, i)
+			} else if e == nil {
+				e = MarshalNull(opts)
 			}
 			elems = append(elems, e)
 		}
@@ -128,15 +240,25 @@ func MarshalPropertyValue(v resource.PropertyValue, opts MarshalOptions) (*struc
 		return MarshalStruct(obj, opts), nil
 	} else if v.IsComputed() {
 		if opts.RejectUnknowns {
-			return nil, errors.New("unexpected unknown property value")
+			return nil, newUnexpectedUnknownError()
 		} else if opts.KeepUnknowns {
+			if opts.KeepUnknownTypes && !opts.CompatibleWire {
+				return marshalTypedUnknown(v.Input().Element, opts)
+			}
 			return marshalUnknownProperty(v.Input().Element, opts), nil
 		}
 		return nil, nil // return nil and the caller will ignore it.
 	} else if v.IsOutput() {
 		// Note that at the moment we don't differentiate between computed and output properties on the wire.  As
 		// a result, they will show up as computed on the other end.  This distinction isn't currently interesting.
-		if opts.KeepUnknowns {
+		if !opts.KeepOutputs {
+			return nil, nil
+		} else if opts.RejectUnknowns {
+			return nil, newUnexpectedUnknownError()
+		} else if opts.KeepUnknowns {
+			if opts.KeepUnknownTypes && !opts.CompatibleWire {
+				return marshalTypedUnknown(v.OutputValue().Element, opts)
+			}
 			return marshalUnknownProperty(v.OutputValue().Element, opts), nil
 		}
 		return nil, nil // return nil and the caller will ignore it.
@@ -196,12 +318,12 @@ func UnmarshalProperties(props *structpb.Struct, opts MarshalOptions) (resource.
 
 	// And now unmarshal every field it into the map.
 	for _, key := range keys {
-		pk := resource.PropertyKey(key)
+		pk := opts.Interner.Key(key)
 		v, err := UnmarshalPropertyValue(props.Fields[key], opts)
 		if err != nil {
-			return nil, err
+			return nil, diag.PrefixPath(err, key)
 		} else if v != nil {
-			logging.V(9).Infof("Unmarshaling property for RPC[%s]: %s=%v", opts.Label, key, v)
+			logging.V(9).Infof("Unmarshaling property for RPC[%s]: %s=%v", opts.Label, key, loggable(pk, *v, opts))
 			if opts.SkipNulls && v.IsNull() {
 				logging.V(9).Infof("Skipping unmarshaling for RPC[%s]: %s is null", opts.Label, key)
 			} else {
@@ -219,35 +341,38 @@ func UnmarshalPropertyValue(v *structpb.Value, opts MarshalOptions) (*resource.P
 
 	switch v.Kind.(type) {
 	case *structpb.Value_NullValue:
-		m := resource.NewNullProperty()
+		m := opts.Interner.Null()
 		return &m, nil
 	case *structpb.Value_BoolValue:
-		m := resource.NewBoolProperty(v.GetBoolValue())
+		m := opts.Interner.Bool(v.GetBoolValue())
 		return &m, nil
 	case *structpb.Value_NumberValue:
-		m := resource.NewNumberProperty(v.GetNumberValue())
+		m := opts.Interner.Number(v.GetNumberValue())
 		return &m, nil
 	case *structpb.Value_StringValue:
 		// If it's a string, it could be an unknown property, or just a regular string.
 		s := v.GetStringValue()
 		if unk, isunk := unmarshalUnknownPropertyValue(s, opts); isunk {
 			if opts.RejectUnknowns {
-				return nil, errors.New("unexpected unknown property value")
+				return nil, newUnexpectedUnknownError()
 			} else if opts.KeepUnknowns {
 				return &unk, nil
 			}
 			return nil, nil
 		}
-		m := resource.NewStringProperty(s)
+		m := opts.Interner.String(s)
 		return &m, nil
 	case *structpb.Value_ListValue:
 		// If there's already an array, prefer to swap elements within it.
 		var elems []resource.PropertyValue
 		lst := v.GetListValue()
+		if len(lst.GetValues()) > 0 {
+			elems = make([]resource.PropertyValue, 0, len(lst.GetValues()))
+		}
 		for i, elem := range lst.GetValues() {
 			e, err := UnmarshalPropertyValue(elem, opts)
 			if err != nil {
-				return nil, err
+				return nil, diag.PrefixIndex(err, i)
 			} else if e != nil {
 				if i == len(elems) {
 					elems = append(elems, *e)
@@ -277,7 +402,7 @@ func UnmarshalPropertyValue(v *structpb.Value, opts MarshalOptions) (*resource.P
 				contract.Assert(isasset)
 				if opts.ComputeAssetHashes {
 					if err = asset.EnsureHash(); err != nil {
-						return nil, errors.Wrapf(err, "failed to compute asset hash")
+						return nil, newAssetHashError("asset", err)
 					}
 				}
 				m := resource.NewAssetProperty(asset)
@@ -290,15 +415,69 @@ func UnmarshalPropertyValue(v *structpb.Value, opts MarshalOptions) (*resource.P
 				contract.Assert(isarchive)
 				if opts.ComputeAssetHashes {
 					if err = archive.EnsureHash(); err != nil {
-						return nil, errors.Wrapf(err, "failed to compute archive hash")
+						return nil, newAssetHashError("archive", err)
 					}
 				}
 				m := resource.NewArchiveProperty(archive)
 				return &m, nil
+			case resource.FormatSig:
+				m, err := unmarshalFormat(obj)
+				if err != nil {
+					return nil, err
+				}
+				return &m, nil
+			case resource.FileRefSig:
+				ref, isref, err := resource.DeserializeFileRef(objmap)
+				if err != nil {
+					return nil, err
+				}
+				contract.Assert(isref)
+				m := resource.NewFileRefProperty(ref.Path, ref.Hash)
+				return &m, nil
 			case resource.SecretSig:
-				return nil, errors.New("this version of the Pulumi SDK does not support first-class secrets")
+				if opts.KeepSecrets {
+					elem, has := obj[secretValueKey]
+					if !has {
+						return nil, errors.New("secret is missing its value")
+					}
+					m := resource.MakeSecret(elem)
+					return &m, nil
+				} else if opts.CompatibleWire {
+					m, err := unmarshalCompatibleSecret(obj, opts)
+					if err != nil {
+						return nil, err
+					}
+					return &m, nil
+				}
+				return nil, diag.NewStructuredError(diag.GetUnsupportedSecretError("")).WithHint(
+					"upgrade the Pulumi CLI, or remove the secret from this resource's inputs")
+			case ResourceReferenceSig:
+				if opts.CompatibleWire {
+					return unmarshalCompatibleResourceReference(obj, opts)
+				}
+				return nil, diag.NewStructuredError(diag.GetUnrecognizedSignatureError(""), sig).WithHint(
+					"set " + CompatibleWireEnvVar + " to accept resource references from other Pulumi SDKs")
+			case UnknownSig:
+				if opts.RejectUnknowns {
+					return nil, newUnexpectedUnknownError()
+				} else if !opts.KeepUnknowns {
+					return nil, nil
+				}
+				m, err := unmarshalTypedUnknown(obj)
+				if err != nil {
+					return nil, err
+				}
+				return &m, nil
+			case LargeValueSig:
+				s, err := unmarshalLargeValue(objmap, opts)
+				if err != nil {
+					return nil, err
+				}
+				m := resource.NewStringProperty(s)
+				return &m, nil
 			default:
-				return nil, errors.Errorf("unrecognized signature '%v' in property map", sig)
+				return nil, diag.NewStructuredError(diag.GetUnrecognizedSignatureError(""), sig).WithHint(
+					"this value may have been produced by a newer version of Pulumi; upgrade the Pulumi CLI")
 			}
 		}
 
@@ -311,6 +490,36 @@ func UnmarshalPropertyValue(v *structpb.Value, opts MarshalOptions) (*resource.P
 	}
 }
 
+// unmarshalFormat recovers a format from the object that carries its template and arguments.
+func unmarshalFormat(obj resource.PropertyMap) (resource.PropertyValue, error) {
+	template, args := obj["template"], obj["args"]
+	if !template.IsString() {
+		return resource.PropertyValue{}, errors.New("format is missing its template")
+	}
+	if !args.IsNull() && !args.IsArray() {
+		return resource.PropertyValue{}, errors.New("format arguments must be an array")
+	}
+	var f resource.Format
+	f.Template = template.StringValue()
+	if args.IsArray() {
+		f.Args = args.ArrayValue()
+	}
+	return resource.NewFormatProperty(f), nil
+}
+
+// newUnexpectedUnknownError returns the error reported when an unknown value is marshaled with RejectUnknowns set.
+func newUnexpectedUnknownError() error {
+	return diag.NewStructuredError(diag.GetUnexpectedUnknownValueError("")).WithHint(
+		"this value is not known until the resources it depends on are created; " +
+			"avoid using it where a known value is required during a preview")
+}
+
+// newAssetHashError returns the error reported when the hash of an asset or archive cannot be computed.
+func newAssetHashError(kind string, err error) error {
+	return diag.NewStructuredError(diag.GetAssetHashError(""), kind, err).WithHint(
+		fmt.Sprintf("check that the %s's source exists and is readable", kind))
+}
+
 func unmarshalUnknownPropertyValue(s string, opts MarshalOptions) (resource.PropertyValue, bool) {
 	var elem resource.PropertyValue
 	var unknown bool
@@ -374,7 +583,7 @@ func MarshalAsset(v *resource.Asset, opts MarshalOptions) (*structpb.Value, erro
 		// Ensure a hash is present if needed.
 		if v.Hash == "" && opts.ComputeAssetHashes {
 			if err := v.EnsureHash(); err != nil {
-				return nil, errors.Wrapf(err, "failed to compute asset hash")
+				return nil, newAssetHashError("asset", err)
 			}
 		}
 	}
@@ -395,7 +604,7 @@ func MarshalArchive(v *resource.Archive, opts MarshalOptions) (*structpb.Value,
 		// Ensure a hash is present if needed.
 		if v.Hash == "" && opts.ComputeAssetHashes {
 			if err := v.EnsureHash(); err != nil {
-				return nil, errors.Wrapf(err, "failed to compute archive hash")
+				return nil, newAssetHashError("archive", err)
 			}
 		}
 	}
@@ -0,0 +1,183 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// largePropertyMap returns a property map resembling a resource with inline code and a large JSON policy document.
+func largePropertyMap(size int) resource.PropertyMap {
+	code := strings.Repeat("exports.handler = function(ev, ctx, cb) { cb(null, \"ok\"); };\n", size/64)
+	var statements []interface{}
+	for i := 0; i < size/1024; i++ {
+		statements = append(statements, map[string]interface{}{
+			"Effect":   "Allow",
+			"Action":   []interface{}{"s3:GetObject", "s3:PutObject"},
+			"Resource": "arn:aws:s3:::bucket/" + strings.Repeat("k", 32),
+		})
+	}
+	return resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name":    "my-function",
+		"runtime": "nodejs8.10",
+		"code":    code,
+		"policy":  map[string]interface{}{"Version": "2012-10-17", "Statement": statements},
+	})
+}
+
+func benchmarkMarshalProperties(b *testing.B, size int, opts MarshalOptions) {
+	props := largePropertyMap(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m, err := MarshalProperties(props, opts)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = proto.Marshal(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkStreamProperties(b *testing.B, size int, opts MarshalOptions) {
+	props := largePropertyMap(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := StreamProperties(ioutil.Discard, props, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalProperties64K(b *testing.B) {
+	benchmarkMarshalProperties(b, 64<<10, MarshalOptions{})
+}
+func BenchmarkMarshalProperties4M(b *testing.B) {
+	benchmarkMarshalProperties(b, 4<<20, MarshalOptions{})
+}
+
+func BenchmarkMarshalPropertiesOffload4M(b *testing.B) {
+	benchmarkMarshalProperties(b, 4<<20,
+		MarshalOptions{LargeValueThreshold: 64 << 10, LargeValueStore: NewMemoryLargeValueStore()})
+}
+
+func BenchmarkStreamProperties64K(b *testing.B) {
+	benchmarkStreamProperties(b, 64<<10, MarshalOptions{})
+}
+func BenchmarkStreamProperties4M(b *testing.B) { benchmarkStreamProperties(b, 4<<20, MarshalOptions{}) }
+
+func benchmarkUnmarshalProperties(b *testing.B, size int, opts MarshalOptions) {
+	m, err := MarshalProperties(largePropertyMap(size), MarshalOptions{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalProperties(m, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalProperties4M(b *testing.B) {
+	benchmarkUnmarshalProperties(b, 4<<20, MarshalOptions{})
+}
+
+func BenchmarkUnmarshalPropertiesInterned4M(b *testing.B) {
+	benchmarkUnmarshalProperties(b, 4<<20, MarshalOptions{Interner: resource.NewInterner()})
+}
+
+// syntheticPropertyMap returns a property map with the given number of top-level keys, whose values cycle through
+// strings, numbers, nested objects, arrays, and (if requested) unknowns.
+func syntheticPropertyMap(keys int, unknowns bool) resource.PropertyMap {
+	props := make(resource.PropertyMap, keys)
+	for i := 0; i < keys; i++ {
+		k := resource.PropertyKey(fmt.Sprintf("key%d", i))
+		switch i % 5 {
+		case 0:
+			props[k] = resource.NewStringProperty(fmt.Sprintf("value-%d", i))
+		case 1:
+			props[k] = resource.NewNumberProperty(float64(i))
+		case 2:
+			props[k] = resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+				"name": fmt.Sprintf("name-%d", i), "enabled": i%2 == 0,
+			}))
+		case 3:
+			props[k] = resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewStringProperty("a"), resource.NewNumberProperty(float64(i)),
+			})
+		case 4:
+			if unknowns {
+				props[k] = resource.MakeComputed(resource.NewStringProperty(""))
+			} else {
+				props[k] = resource.NewBoolProperty(true)
+			}
+		}
+	}
+	return props
+}
+
+func benchmarkMarshalPropertiesWithUnknowns(b *testing.B, keys int) {
+	props := syntheticPropertyMap(keys, true)
+	opts := MarshalOptions{KeepUnknowns: true}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MarshalProperties(props, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalPropertiesWithUnknowns10(b *testing.B) {
+	benchmarkMarshalPropertiesWithUnknowns(b, 10)
+}
+
+func BenchmarkMarshalPropertiesWithUnknowns1K(b *testing.B) {
+	benchmarkMarshalPropertiesWithUnknowns(b, 1000)
+}
+
+func BenchmarkMarshalPropertiesWithUnknowns100K(b *testing.B) {
+	benchmarkMarshalPropertiesWithUnknowns(b, 100000)
+}
+
+func benchmarkUnmarshalSyntheticProperties(b *testing.B, keys int) {
+	opts := MarshalOptions{KeepUnknowns: true}
+	m, err := MarshalProperties(syntheticPropertyMap(keys, true), opts)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalProperties(m, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalProperties10(b *testing.B)   { benchmarkUnmarshalSyntheticProperties(b, 10) }
+func BenchmarkUnmarshalProperties1K(b *testing.B)   { benchmarkUnmarshalSyntheticProperties(b, 1000) }
+func BenchmarkUnmarshalProperties100K(b *testing.B) { benchmarkUnmarshalSyntheticProperties(b, 100000) }
@@ -0,0 +1,84 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// The compatible wire encoding is the subset of the property encoding that other Pulumi-style SDKs, such as the
+// Node.js SDK and the providers built with it, understand.  Assets, archives, and sentinel unknowns are encoded the
+// same way in both; the differences are:
+//
+//   - unknowns are always sent as the string sentinel, never as typed unknowns;
+//   - formats are evaluated and large values are sent inline, since other SDKs cannot interpret them; and
+//   - secrets and resource references, which this engine does not yet support, are accepted and downgraded to plain
+//     values: a secret to the value it wraps, and a resource reference to its ID (or its URN, for components).
+
+// CompatibleWireEnvVar is the environment variable that, when truthy, asks the engine to use the compatible wire
+// encoding when talking to resource providers.
+const CompatibleWireEnvVar = "PULUMI_COMPATIBLE_WIRE"
+
+// ResourceReferenceSig is the unique signature that other SDKs use for references to resources.
+const ResourceReferenceSig = "5cf8f73096256a8f31e491e813e4eb8e"
+
+const (
+	secretValueKey = "value" // the key holding the wrapped value in a secret object.
+	resourceURNKey = "urn"   // the key holding the URN in a resource reference object.
+	resourceIDKey  = "id"    // the key holding the ID, if any, in a resource reference object.
+)
+
+// unmarshalCompatibleSecret downgrades a secret object to the value that it wraps.
+func unmarshalCompatibleSecret(obj resource.PropertyMap, opts MarshalOptions) (resource.PropertyValue, error) {
+	v, has := obj[secretValueKey]
+	if !has {
+		return resource.PropertyValue{}, errors.New("secret is missing its value")
+	}
+	logging.V(7).Infof("Unmarshaling secret for RPC[%s] as a plain value", opts.Label)
+	return v, nil
+}
+
+// unmarshalCompatibleResourceReference downgrades a resource reference object to the ID of the resource it refers to.
+// References to components, which have no ID, become their URNs; an empty ID means that the ID is not yet known.
+func unmarshalCompatibleResourceReference(obj resource.PropertyMap,
+	opts MarshalOptions) (*resource.PropertyValue, error) {
+
+	urn := obj[resourceURNKey]
+	if !urn.IsString() {
+		return nil, errors.New("resource reference is missing its URN")
+	}
+	id, has := obj[resourceIDKey]
+	if !has {
+		m := resource.NewStringProperty(urn.StringValue())
+		return &m, nil
+	}
+	if id.IsComputed() || (id.IsString() && id.StringValue() == "") {
+		if opts.RejectUnknowns {
+			return nil, newUnexpectedUnknownError()
+		} else if !opts.KeepUnknowns {
+			return nil, nil
+		}
+		m := resource.MakeComputed(resource.NewStringProperty(""))
+		return &m, nil
+	}
+	if !id.IsString() {
+		return nil, errors.Errorf("resource reference to %s has an ID that is not a string", urn.StringValue())
+	}
+	m := resource.NewStringProperty(id.StringValue())
+	return &m, nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// LargeValueSig is the unique signature for a reference to a value that was offloaded to a side channel.
+const LargeValueSig = "9c6a4b0e1d5e4b4fa2f6c41b8a3e2d17"
+
+// LargeValueDirEnvVar is the environment variable that, when set, names a directory through which string property
+// values larger than the large value threshold are exchanged with resource providers, rather than being sent inline.
+// Plugins inherit the variable, so providers built on pkg/resource/provider resolve such values transparently.  Only
+// providers that understand large value references should be used when it is set.
+const LargeValueDirEnvVar = "PULUMI_LARGE_VALUE_DIR"
+
+// LargeValueThresholdEnvVar is the environment variable that overrides DefaultLargeValueThreshold.
+const LargeValueThresholdEnvVar = "PULUMI_LARGE_VALUE_THRESHOLD"
+
+// DefaultLargeValueThreshold is the size in bytes above which values are offloaded if LargeValueDirEnvVar is set.
+const DefaultLargeValueThreshold = 1 << 20
+
+const (
+	largeValueRefKey  = "ref"  // the key holding the side channel reference in a large value object.
+	largeValueSizeKey = "size" // the key holding the original size of the value in a large value object.
+)
+
+// LargeValueStore is a side channel used to move very large property values (e.g., inline Lambda code or big JSON
+// blobs) out of band, rather than materializing them inside of RPC payloads.  Put returns an opaque reference that
+// Get can later resolve, possibly on the other side of an RPC boundary.
+type LargeValueStore interface {
+	// Put stores the given data and returns a reference that can be used to fetch it later.
+	Put(data []byte) (string, error)
+	// Get returns the data previously stored under the given reference.
+	Get(ref string) ([]byte, error)
+}
+
+// shouldOffload returns true if the given string should be sent over the large value side channel.  Other SDKs cannot
+// resolve large value references, so nothing is offloaded when using the compatible wire encoding.
+func (opts MarshalOptions) shouldOffload(s string) bool {
+	return opts.LargeValueStore != nil && opts.LargeValueThreshold > 0 && len(s) > opts.LargeValueThreshold &&
+		!opts.CompatibleWire
+}
+
+// LargeValueStoreFromEnv returns the large value store and threshold requested by LargeValueDirEnvVar and
+// LargeValueThresholdEnvVar.  If no directory is set, the store is nil and nothing is offloaded.
+func LargeValueStoreFromEnv() (LargeValueStore, int, error) {
+	dir := os.Getenv(LargeValueDirEnvVar)
+	if dir == "" {
+		return nil, 0, nil
+	}
+
+	threshold := DefaultLargeValueThreshold
+	if v := os.Getenv(LargeValueThresholdEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, 0, errors.Errorf("%s must be a positive number of bytes; got '%s'", LargeValueThresholdEnvVar, v)
+		}
+		threshold = n
+	}
+	store, err := NewFileLargeValueStore(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	return store, threshold, nil
+}
+
+// marshalLargeValue offloads a string to the large value store and returns a reference object in its place.
+func marshalLargeValue(s string, opts MarshalOptions) (*structpb.Value, error) {
+	ref, err := opts.LargeValueStore.Put([]byte(s))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to offload large value")
+	}
+	logging.V(9).Infof("Offloaded large value for RPC[%s]: %d bytes as %s", opts.Label, len(s), ref)
+
+	return MarshalStruct(&structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			resource.SigKey:   MarshalString(LargeValueSig, opts),
+			largeValueRefKey:  MarshalString(ref, opts),
+			largeValueSizeKey: {Kind: &structpb.Value_NumberValue{NumberValue: float64(len(s))}},
+		},
+	}, opts), nil
+}
+
+// unmarshalLargeValue resolves a large value reference object back into the string it stands for.
+func unmarshalLargeValue(obj map[string]interface{}, opts MarshalOptions) (string, error) {
+	ref, ok := obj[largeValueRefKey].(string)
+	if !ok {
+		return "", errors.New("large value reference is missing its 'ref' field")
+	}
+	if opts.LargeValueStore == nil {
+		return "", errors.Errorf("cannot resolve large value '%s' without a large value store", ref)
+	}
+	data, err := opts.LargeValueStore.Get(ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve large value '%s'", ref)
+	}
+	if size, hasSize := obj[largeValueSizeKey].(float64); hasSize && int(size) != len(data) {
+		return "", errors.Errorf("large value '%s' is %d bytes; expected %d", ref, len(data), int(size))
+	}
+	return string(data), nil
+}
+
+// largeValueRef computes the content-addressed reference for a large value.
+func largeValueRef(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewMemoryLargeValueStore returns a large value store that keeps values in memory.  This is suitable when both
+// ends of an RPC connection live in the same process, and for testing.
+func NewMemoryLargeValueStore() LargeValueStore {
+	return &memoryLargeValueStore{values: make(map[string][]byte)}
+}
+
+type memoryLargeValueStore struct {
+	values map[string][]byte
+	lock   sync.RWMutex
+}
+
+func (s *memoryLargeValueStore) Put(data []byte) (string, error) {
+	ref := largeValueRef(data)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.values[ref] = data
+	return ref, nil
+}
+
+func (s *memoryLargeValueStore) Get(ref string) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	data, has := s.values[ref]
+	if !has {
+		return nil, errors.Errorf("unknown large value '%s'", ref)
+	}
+	return data, nil
+}
+
+// NewFileLargeValueStore returns a large value store that writes each value to a file in the given directory.  Values
+// are content-addressed, so storing the same value twice only writes it once.  Because the directory may be shared,
+// this store can be used to hand values to a plugin running in another process.  The store is an io.Closer; closing
+// it removes the files that it wrote, so it should only be closed once the values are no longer needed.
+func NewFileLargeValueStore(dir string) (LargeValueStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "creating large value directory %s", dir)
+	}
+	return &fileLargeValueStore{dir: dir, written: make(map[string]bool)}, nil
+}
+
+type fileLargeValueStore struct {
+	dir     string
+	written map[string]bool // the paths of the files written by this store.
+	lock    sync.Mutex      // guards written.
+}
+
+func (s *fileLargeValueStore) Put(data []byte) (string, error) {
+	ref := largeValueRef(data)
+	path := filepath.Join(s.dir, ref)
+	if _, err := os.Stat(path); err == nil {
+		return ref, nil
+	}
+
+	// Write to a temporary file first and then rename it into place, so readers never observe a partial value.
+	tmp, err := ioutil.TempFile(s.dir, ref+".tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		contract.IgnoreClose(tmp)
+		contract.IgnoreError(os.Remove(tmp.Name()))
+		return "", err
+	}
+	if err = tmp.Close(); err != nil {
+		contract.IgnoreError(os.Remove(tmp.Name()))
+		return "", err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		contract.IgnoreError(os.Remove(tmp.Name()))
+		return "", err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.written[path] = true
+	return ref, nil
+}
+
+func (s *fileLargeValueStore) Get(ref string) ([]byte, error) {
+	// References are hex digests; refuse anything that could escape the store's directory.
+	if _, err := hex.DecodeString(ref); err != nil {
+		return nil, errors.Errorf("malformed large value reference '%s'", ref)
+	}
+	return ioutil.ReadFile(filepath.Join(s.dir, ref))
+}
+
+// Close removes the files written by this store.  Values that it found already present were written by someone else,
+// and are left in place.
+func (s *fileLargeValueStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var result error
+	for path := range s.written {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			result = multierror.Append(result, err)
+		}
+		delete(s.written, path)
+	}
+	return result
+}
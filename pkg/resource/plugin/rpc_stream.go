@@ -0,0 +1,310 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// StreamProperties writes the "JSON-like" form of a property map to w as a JSON document.  The document is identical
+// to the one produced by calling MarshalProperties and then encoding the resulting structure as JSON; however, no
+// intermediate structpb tree is materialized, and string values are escaped directly into w rather than copied.  This
+// makes it suitable for property maps that carry megabytes of data.
+func StreamProperties(w io.Writer, props resource.PropertyMap, opts MarshalOptions) error {
+	if len(opts.UnorderedPaths) > 0 {
+		props = props.SortSets(opts.UnorderedPaths)
+		opts.UnorderedPaths = nil
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := streamProperties(bw, props, opts); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// StreamPropertyValue writes the "JSON-like" form of a single property value to w.  See StreamProperties for details.
+func StreamPropertyValue(w io.Writer, v resource.PropertyValue, opts MarshalOptions) error {
+	bw := bufio.NewWriter(w)
+	if opts.dropsValue(v) {
+		if _, err := bw.WriteString("null"); err != nil {
+			return err
+		}
+	} else if err := streamPropertyValue(bw, v, opts); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func streamProperties(w *bufio.Writer, props resource.PropertyMap, opts MarshalOptions) error {
+	if err := w.WriteByte('{'); err != nil {
+		return err
+	}
+	first := true
+	for _, key := range props.StableKeys() {
+		v := props[key]
+		if opts.skipsProperty(v) {
+			continue
+		}
+		if !first {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		if err := streamString(w, string(key)); err != nil {
+			return err
+		}
+		if err := w.WriteByte(':'); err != nil {
+			return err
+		}
+		if err := streamPropertyValue(w, v, opts); err != nil {
+			return err
+		}
+	}
+	return w.WriteByte('}')
+}
+
+func streamPropertyValue(w *bufio.Writer, v resource.PropertyValue, opts MarshalOptions) error {
+	switch {
+	case v.IsNull():
+		_, err := w.WriteString("null")
+		return err
+	case v.IsBool():
+		_, err := w.WriteString(strconv.FormatBool(v.BoolValue()))
+		return err
+	case v.IsNumber():
+		return streamNumber(w, v.NumberValue())
+	case v.IsString():
+		if opts.shouldOffload(v.StringValue()) {
+			m, err := marshalLargeValue(v.StringValue(), opts)
+			if err != nil {
+				return err
+			}
+			return streamStructpbValue(w, m)
+		}
+		return streamString(w, v.StringValue())
+	case v.IsJSON():
+		// JSON values are sent to providers as the strings that hold them.
+		return streamPropertyValue(w, resource.NewStringProperty(v.JSONValue().Raw), opts)
+	case v.IsFormat() || v.IsApplied() || v.IsFileRef() || v.IsSecret():
+		m, err := MarshalPropertyValue(v, opts)
+		if err != nil {
+			return err
+		} else if m == nil {
+			// Applied values that resolve to unknowns are dropped unless unknowns are kept.
+			_, err = w.WriteString("null")
+			return err
+		}
+		return streamStructpbValue(w, m)
+	case v.IsArray():
+		if err := w.WriteByte('['); err != nil {
+			return err
+		}
+		first := true
+		for _, elem := range v.ArrayValue() {
+			if opts.elidesElement(elem) {
+				continue
+			}
+			if !first {
+				if err := w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			first = false
+			if opts.dropsValue(elem) {
+				if _, err := w.WriteString("null"); err != nil {
+					return err
+				}
+			} else if err := streamPropertyValue(w, elem, opts); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte(']')
+	case v.IsAsset():
+		// Assets are small once serialized (their contents live elsewhere unless they are text), so simply reuse the
+		// ordinary marshaling logic for them.
+		m, err := MarshalAsset(v.AssetValue(), opts)
+		if err != nil {
+			return err
+		}
+		return streamStructpbValue(w, m)
+	case v.IsArchive():
+		m, err := MarshalArchive(v.ArchiveValue(), opts)
+		if err != nil {
+			return err
+		}
+		return streamStructpbValue(w, m)
+	case v.IsObject():
+		return streamProperties(w, v.ObjectValue(), opts)
+	case v.IsComputed():
+		if opts.RejectUnknowns {
+			return errors.New("unexpected unknown property value")
+		}
+		return streamStructpbValue(w, marshalUnknownProperty(v.Input().Element, opts))
+	case v.IsOutput():
+		if opts.RejectUnknowns {
+			return errors.New("unexpected unknown property value")
+		}
+		return streamStructpbValue(w, marshalUnknownProperty(v.OutputValue().Element, opts))
+	}
+
+	contract.Failf("Unrecognized property value in RPC[%s]: %v (type=%v)", opts.Label, v.V, reflect.TypeOf(v.V))
+	return nil
+}
+
+// streamStructpbValue writes an already-marshaled protobuf value to w.
+func streamStructpbValue(w *bufio.Writer, v *structpb.Value) error {
+	switch k := v.Kind.(type) {
+	case *structpb.Value_NullValue:
+		_, err := w.WriteString("null")
+		return err
+	case *structpb.Value_BoolValue:
+		_, err := w.WriteString(strconv.FormatBool(k.BoolValue))
+		return err
+	case *structpb.Value_NumberValue:
+		return streamNumber(w, k.NumberValue)
+	case *structpb.Value_StringValue:
+		return streamString(w, k.StringValue)
+	case *structpb.Value_ListValue:
+		if err := w.WriteByte('['); err != nil {
+			return err
+		}
+		for i, elem := range k.ListValue.GetValues() {
+			if i > 0 {
+				if err := w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			if err := streamStructpbValue(w, elem); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte(']')
+	case *structpb.Value_StructValue:
+		fields := k.StructValue.GetFields()
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		if err := w.WriteByte('{'); err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if i > 0 {
+				if err := w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			if err := streamString(w, key); err != nil {
+				return err
+			}
+			if err := w.WriteByte(':'); err != nil {
+				return err
+			}
+			if err := streamStructpbValue(w, fields[key]); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte('}')
+	}
+
+	contract.Failf("Unrecognized structpb value kind: %v", reflect.TypeOf(v.Kind))
+	return nil
+}
+
+// streamNumber writes a number to w, rejecting values that JSON cannot represent.
+func streamNumber(w *bufio.Writer, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return errors.Errorf("unsupported number value: %v", f)
+	}
+	var buf [32]byte
+	_, err := w.Write(strconv.AppendFloat(buf[:0], f, 'g', -1, 64))
+	return err
+}
+
+const hexDigits = "0123456789abcdef"
+
+// streamString writes a quoted, escaped JSON string to w without allocating a copy of s.
+func streamString(w *bufio.Writer, s string) error {
+	if err := w.WriteByte('"'); err != nil {
+		return err
+	}
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			if _, err := w.WriteString(s[start:i]); err != nil {
+				return err
+			}
+			var err error
+			switch b {
+			case '"', '\\':
+				_, err = w.Write([]byte{'\\', b})
+			case '\n':
+				_, err = w.WriteString(`\n`)
+			case '\r':
+				_, err = w.WriteString(`\r`)
+			case '\t':
+				_, err = w.WriteString(`\t`)
+			default:
+				_, err = w.Write([]byte{'\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF]})
+			}
+			if err != nil {
+				return err
+			}
+			i++
+			start = i
+			continue
+		}
+
+		// Replace invalid UTF-8 with the replacement character, as encoding/json does.
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			if _, err := w.WriteString(s[start:i]); err != nil {
+				return err
+			}
+			if _, err := w.WriteString(`�`); err != nil {
+				return err
+			}
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	if _, err := w.WriteString(s[start:]); err != nil {
+		return err
+	}
+	return w.WriteByte('"')
+}
@@ -15,13 +15,26 @@
 package plugin
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"runtime"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/jsonpb"
+	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
 func TestAssetSerialize(t *testing.T) {
@@ -95,6 +108,47 @@ func TestComputedSerialize(t *testing.T) {
 	}
 }
 
+func TestTypedUnknownSerialize(t *testing.T) {
+	// Ensure that typed unknowns keep the types and placeholders of their eventual values across round trips.
+	opts := MarshalOptions{KeepUnknowns: true, KeepUnknownTypes: true}
+	for _, elem := range []resource.PropertyValue{
+		resource.NewBoolProperty(false),
+		resource.NewNumberProperty(0),
+		resource.NewNumberProperty(42),
+		resource.NewStringProperty("arn:placeholder"),
+		resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("a")}),
+		resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{"port": 80})),
+		resource.NewAssetProperty(&resource.Asset{}),
+	} {
+		cprop, err := MarshalPropertyValue(resource.MakeComputed(elem), opts)
+		assert.Nil(t, err)
+		assert.NotNil(t, cprop.GetStructValue())
+		cpropU, err := UnmarshalPropertyValue(cprop, opts)
+		assert.Nil(t, err)
+		if assert.True(t, cpropU.IsComputed()) {
+			assert.True(t, elem.DeepEquals(cpropU.Input().Element), "%v != %v", elem, cpropU.Input().Element)
+		}
+	}
+
+	// Typed unknowns are skipped or rejected like any other unknown.
+	cprop, err := MarshalPropertyValue(resource.MakeComputed(resource.NewNumberProperty(0)), opts)
+	assert.Nil(t, err)
+	cpropU, err := UnmarshalPropertyValue(cprop, MarshalOptions{})
+	assert.Nil(t, err)
+	assert.Nil(t, cpropU)
+	_, err = UnmarshalPropertyValue(cprop, MarshalOptions{RejectUnknowns: true})
+	assert.NotNil(t, err)
+
+	// Placeholders must match the type of the unknown.
+	bad := MarshalStruct(&structpb.Struct{Fields: map[string]*structpb.Value{
+		resource.SigKey:       MarshalString(UnknownSig, opts),
+		unknownTypeKey:        MarshalString("number", opts),
+		unknownPlaceholderKey: MarshalString("oops", opts),
+	}}, opts)
+	_, err = UnmarshalPropertyValue(bad, opts)
+	assert.NotNil(t, err)
+}
+
 func TestComputedSkip(t *testing.T) {
 	// Ensure that computed properties are skipped when KeepUnknowns == false.
 	opts := MarshalOptions{KeepUnknowns: false}
@@ -135,6 +189,24 @@ func TestComputedReject(t *testing.T) {
 	}
 }
 
+func TestComputedRejectPath(t *testing.T) {
+	// Ensure that rejected unknowns report the path of the offending property.
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"port": 80},
+			map[string]interface{}{"port": resource.Computed{Element: resource.NewNumberProperty(0)}},
+		},
+	})
+	_, err := MarshalProperties(props, MarshalOptions{RejectUnknowns: true})
+	assert.Error(t, err)
+	derr, ok := err.(*diag.StructuredError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "rules[1].port", derr.Path)
+		assert.Equal(t, diag.GetUnexpectedUnknownValueError("").ID, derr.ID)
+		assert.NotEmpty(t, derr.Hint)
+	}
+}
+
 func TestUnsupportedSecret(t *testing.T) {
 	rawProp := resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
 		resource.SigKey: resource.SecretSig,
@@ -145,6 +217,37 @@ func TestUnsupportedSecret(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestKeepSecrets(t *testing.T) {
+	props := resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"key": resource.MakeSecret(resource.NewNumberProperty(42)),
+		}),
+	}
+
+	// Kept secrets survive a round trip.
+	opts := MarshalOptions{KeepSecrets: true}
+	marshaled, err := MarshalProperties(props, opts)
+	assert.Nil(t, err)
+	unmarshaled, err := UnmarshalProperties(marshaled, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, props, unmarshaled)
+
+	// Otherwise they are sent as their plain values.
+	marshaled, err = MarshalProperties(props, MarshalOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "hunter2", marshaled.Fields["password"].GetStringValue())
+
+	// A kept secret must carry its value.
+	rawProp := resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+		resource.SigKey: resource.SecretSig,
+	}))
+	prop, err := MarshalPropertyValue(rawProp, MarshalOptions{})
+	assert.Nil(t, err)
+	_, err = UnmarshalPropertyValue(prop, opts)
+	assert.Error(t, err)
+}
+
 func TestUnknownSig(t *testing.T) {
 	rawProp := resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
 		resource.SigKey: "foobar",
@@ -155,3 +258,357 @@ func TestUnknownSig(t *testing.T) {
 	assert.Error(t, err)
 
 }
+
+func TestLargeValueOffload(t *testing.T) {
+	large := strings.Repeat("x", 1024)
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"small": "abc",
+		"large": large,
+		"nested": map[string]interface{}{
+			"values": []interface{}{large, "def"},
+		},
+	})
+
+	store := NewMemoryLargeValueStore()
+	opts := MarshalOptions{LargeValueThreshold: 512, LargeValueStore: store}
+	marshaled, err := MarshalProperties(props, opts)
+	assert.Nil(t, err)
+
+	// The large value should have been replaced with a reference, and the small ones left alone.
+	assert.Equal(t, "abc", marshaled.Fields["small"].GetStringValue())
+	ref := marshaled.Fields["large"].GetStructValue()
+	assert.NotNil(t, ref)
+	assert.Equal(t, LargeValueSig, ref.Fields[resource.SigKey].GetStringValue())
+
+	// Round-tripping through the same store recovers the original values.
+	unmarshaled, err := UnmarshalProperties(marshaled, opts)
+	assert.Nil(t, err)
+	assert.True(t, props.DeepEquals(unmarshaled))
+
+	// Without a store, the reference cannot be resolved.
+	_, err = UnmarshalProperties(marshaled, MarshalOptions{})
+	assert.Error(t, err)
+}
+
+func TestFileLargeValueStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "largevalues")
+	assert.Nil(t, err)
+	defer func() { contract.IgnoreError(os.RemoveAll(dir)) }()
+
+	store, err := NewFileLargeValueStore(dir)
+	assert.Nil(t, err)
+
+	ref, err := store.Put([]byte("hello"))
+	assert.Nil(t, err)
+	ref2, err := store.Put([]byte("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, ref, ref2)
+
+	data, err := store.Get(ref)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = store.Get("../escape")
+	assert.Error(t, err)
+
+	// Closing the store removes the values that it wrote, but not those that another store wrote.
+	other, err := NewFileLargeValueStore(dir)
+	assert.Nil(t, err)
+	_, err = other.Put([]byte("hello"))
+	assert.Nil(t, err)
+	ref3, err := other.Put([]byte("world"))
+	assert.Nil(t, err)
+	assert.Nil(t, other.(io.Closer).Close())
+	_, err = store.Get(ref)
+	assert.Nil(t, err)
+	_, err = store.Get(ref3)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Nil(t, store.(io.Closer).Close())
+	_, err = store.Get(ref)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStreamPropertiesMatchesMarshal(t *testing.T) {
+	asset, err := resource.NewTextAsset("an asset")
+	assert.Nil(t, err)
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"bool":    true,
+		"number":  42.5,
+		"string":  "quotes \" and \\ and \n and   and \x01",
+		"null":    nil,
+		"array":   []interface{}{1, "two", false},
+		"object":  map[string]interface{}{"a": "b"},
+		"asset":   asset,
+		"unknown": resource.Computed{Element: resource.NewStringProperty("")},
+		"output":  resource.Output{Element: resource.NewStringProperty("")},
+	})
+
+	props["nested"] = resource.NewObjectProperty(resource.PropertyMap{
+		"output": props["output"],
+		"array":  resource.NewArrayProperty([]resource.PropertyValue{props["output"], props["null"], props["unknown"]}),
+	})
+
+	for _, opts := range []MarshalOptions{
+		{}, {KeepUnknowns: true}, {SkipNulls: true}, {ElideNulls: true}, {KeepUnknowns: true, KeepOutputs: true},
+		{KeepOutputs: true, ElideNulls: true},
+	} {
+		marshaled, err := MarshalProperties(props, opts)
+		assert.Nil(t, err)
+		expected, err := (&jsonpb.Marshaler{}).MarshalToString(marshaled)
+		assert.Nil(t, err)
+
+		var buf bytes.Buffer
+		err = StreamProperties(&buf, props, opts)
+		assert.Nil(t, err)
+
+		var expectedJSON, actualJSON interface{}
+		assert.Nil(t, json.Unmarshal([]byte(expected), &expectedJSON))
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &actualJSON))
+		assert.Equal(t, expectedJSON, actualJSON)
+	}
+}
+
+func TestUnorderedPaths(t *testing.T) {
+	props1 := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"set":    []interface{}{"a", "b", "c"},
+		"object": map[string]interface{}{"set": []interface{}{"x", "y"}},
+	})
+	props2 := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"set":    []interface{}{"c", "a", "b"},
+		"object": map[string]interface{}{"set": []interface{}{"y", "x"}},
+	})
+	opts := MarshalOptions{UnorderedPaths: []resource.PropertyPath{{"set"}}}
+
+	// Only the top-level set is sorted; the nested array with the same name keeps its order.
+	m1, err := MarshalProperties(props1, opts)
+	assert.Nil(t, err)
+	m2, err := MarshalProperties(props2, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, m1.Fields["set"], m2.Fields["set"])
+	assert.NotEqual(t, m1.Fields["object"], m2.Fields["object"])
+
+	// The streaming marshaler agrees.
+	var b1, b2 bytes.Buffer
+	assert.Nil(t, StreamProperties(&b1, props1, opts))
+	assert.Nil(t, StreamProperties(&b2, props2, opts))
+	var j1, j2 map[string]interface{}
+	assert.Nil(t, json.Unmarshal(b1.Bytes(), &j1))
+	assert.Nil(t, json.Unmarshal(b2.Bytes(), &j2))
+	assert.Equal(t, j1["set"], j2["set"])
+}
+
+func TestNullAndAbsentRoundTrip(t *testing.T) {
+	props := resource.PropertyMap{
+		"null": resource.NewNullProperty(),
+		"object": resource.NewObjectProperty(resource.PropertyMap{
+			"null":  resource.NewNullProperty(),
+			"value": resource.NewStringProperty("x"),
+		}),
+		"array": resource.NewArrayProperty([]resource.PropertyValue{resource.NewNullProperty()}),
+	}
+
+	// By default, null properties survive a round trip, and absent properties remain absent.
+	marshaled, err := MarshalProperties(props, MarshalOptions{})
+	assert.Nil(t, err)
+	unmarshaled, err := UnmarshalProperties(marshaled, MarshalOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, props, unmarshaled)
+	assert.Equal(t, resource.PropertyNull, unmarshaled.State("null"))
+	assert.Equal(t, resource.PropertyAbsent, unmarshaled.State("missing"))
+	obj, _ := unmarshaled.GetObject("object")
+	assert.Equal(t, resource.PropertyNull, obj.State("null"))
+
+	// Skipping nulls erases the distinction.
+	marshaled, err = MarshalProperties(props, MarshalOptions{SkipNulls: true})
+	assert.Nil(t, err)
+	unmarshaled, err = UnmarshalProperties(marshaled, MarshalOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, resource.PropertyAbsent, unmarshaled.State("null"))
+	obj, _ = unmarshaled.GetObject("object")
+	assert.Equal(t, resource.PropertyAbsent, obj.State("null"))
+}
+
+func TestOutputsAtEveryDepth(t *testing.T) {
+	output := resource.MakeOutput(resource.NewStringProperty(""))
+	props := resource.PropertyMap{
+		"output": output,
+		"object": resource.NewObjectProperty(resource.PropertyMap{"output": output}),
+		"array":  resource.NewArrayProperty([]resource.PropertyValue{output, resource.NewStringProperty("x")}),
+	}
+
+	// By default, outputs are left out of objects at every depth, and are nulls in arrays, even if unknowns are kept.
+	for _, opts := range []MarshalOptions{{}, {KeepUnknowns: true}} {
+		marshaled, err := MarshalProperties(props, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"array", "object"}, structKeys(marshaled))
+		assert.Empty(t, marshaled.Fields["object"].GetStructValue().Fields)
+		elems := marshaled.Fields["array"].GetListValue().Values
+		assert.Equal(t, []*structpb.Value{MarshalNull(opts), MarshalString("x", opts)}, elems)
+	}
+
+	// If outputs are kept, they are unknowns at every depth.
+	opts := MarshalOptions{KeepUnknowns: true, KeepOutputs: true}
+	marshaled, err := MarshalProperties(props, opts)
+	assert.Nil(t, err)
+	unknown := MarshalString(UnknownStringValue, opts)
+	assert.Equal(t, unknown, marshaled.Fields["output"])
+	assert.Equal(t, unknown, marshaled.Fields["object"].GetStructValue().Fields["output"])
+	assert.Equal(t, unknown, marshaled.Fields["array"].GetListValue().Values[0])
+
+	// Kept outputs are still subject to the options for unknowns.
+	_, err = MarshalProperties(props, MarshalOptions{KeepOutputs: true, RejectUnknowns: true})
+	assert.NotNil(t, err)
+	marshaled, err = MarshalProperties(props, MarshalOptions{KeepOutputs: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"array", "object"}, structKeys(marshaled))
+}
+
+func TestElideNulls(t *testing.T) {
+	props := resource.PropertyMap{
+		"null": resource.NewNullProperty(),
+		"object": resource.NewObjectProperty(resource.PropertyMap{
+			"null":  resource.NewNullProperty(),
+			"value": resource.NewStringProperty("x"),
+		}),
+		"array": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewNullProperty(),
+			resource.NewStringProperty("x"),
+			resource.MakeComputed(resource.NewStringProperty("")),
+			resource.NewObjectProperty(resource.PropertyMap{"null": resource.NewNullProperty()}),
+		}),
+	}
+
+	// Skipping nulls leaves them out of objects, but keeps them in arrays, as it does dropped unknowns.
+	opts := MarshalOptions{SkipNulls: true}
+	marshaled, err := MarshalProperties(props, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"array", "object"}, structKeys(marshaled))
+	assert.Equal(t, []string{"value"}, structKeys(marshaled.Fields["object"].GetStructValue()))
+	elems := marshaled.Fields["array"].GetListValue().Values
+	assert.Len(t, elems, 4)
+	assert.Equal(t, MarshalNull(opts), elems[0])
+	assert.Equal(t, MarshalNull(opts), elems[2])
+	assert.Empty(t, elems[3].GetStructValue().Fields)
+
+	// Eliding nulls leaves them out of arrays as well.
+	opts = MarshalOptions{ElideNulls: true}
+	marshaled, err = MarshalProperties(props, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"array", "object"}, structKeys(marshaled))
+	assert.Equal(t, []string{"value"}, structKeys(marshaled.Fields["object"].GetStructValue()))
+	elems = marshaled.Fields["array"].GetListValue().Values
+	assert.Len(t, elems, 2)
+	assert.Equal(t, MarshalString("x", opts), elems[0])
+	assert.Empty(t, elems[1].GetStructValue().Fields)
+
+	// Unlike skipping nulls, eliding them does not affect unmarshaling.
+	marshaled, err = MarshalProperties(props, MarshalOptions{})
+	assert.Nil(t, err)
+	unmarshaled, err := UnmarshalProperties(marshaled, MarshalOptions{ElideNulls: true})
+	assert.Nil(t, err)
+	assert.Equal(t, resource.PropertyNull, unmarshaled.State("null"))
+}
+
+// structKeys returns the sorted keys of the given structure's fields.
+func structKeys(s *structpb.Struct) []string {
+	var keys []string
+	for k := range s.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestFileRefs(t *testing.T) {
+	const contents = "apiVersion: v1\nkind: ConfigMap\n"
+	f, err := ioutil.TempFile("", "fileref")
+	assert.NoError(t, err)
+	defer func() { contract.IgnoreError(os.Remove(f.Name())) }()
+	_, err = f.WriteString(contents)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	// File references are sent to providers as the values that they refer to...
+	sum := sha256.Sum256([]byte(contents))
+	ref := resource.NewFileRefProperty(f.Name(), hex.EncodeToString(sum[:]))
+	marshaled, err := MarshalProperties(resource.PropertyMap{"manifest": ref}, MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, contents, marshaled.Fields["manifest"].GetStringValue())
+
+	// ...but programs send them to the engine as references.
+	marshaled, err = MarshalProperties(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"manifest": ref.FileRefValue().Serialize(),
+	}), MarshalOptions{})
+	assert.NoError(t, err)
+	unmarshaled, err := UnmarshalProperties(marshaled, MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, ref, unmarshaled["manifest"])
+
+	// A reference whose file has changed cannot be sent.
+	stale := resource.NewFileRefProperty(f.Name(), "0000")
+	_, err = MarshalProperties(resource.PropertyMap{"manifest": stale}, MarshalOptions{})
+	assert.Error(t, err)
+}
+
+func TestCompatibleWire(t *testing.T) {
+	opts := MarshalOptions{KeepUnknowns: true, KeepUnknownTypes: true, CompatibleWire: true,
+		LargeValueThreshold: 8, LargeValueStore: NewMemoryLargeValueStore()}
+
+	// Unknowns are sent as sentinels, formats as their results, and large values inline.
+	props := resource.PropertyMap{
+		"unknown": resource.MakeComputed(resource.NewNumberProperty(0)),
+		"format": resource.NewFormatProperty(resource.Format{
+			Template: "%s-%s",
+			Args:     []resource.PropertyValue{resource.NewStringProperty("a"), resource.NewNumberProperty(1)},
+		}),
+		"large": resource.NewStringProperty(strings.Repeat("x", 16)),
+	}
+	marshaled, err := MarshalProperties(props, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, UnknownStringValue, marshaled.Fields["unknown"].GetStringValue())
+	assert.Equal(t, "a-1", marshaled.Fields["format"].GetStringValue())
+	assert.Equal(t, strings.Repeat("x", 16), marshaled.Fields["large"].GetStringValue())
+
+	// Secrets and resource references from other SDKs are downgraded to plain values.
+	sent := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"secret": map[string]interface{}{
+			resource.SigKey: resource.SecretSig,
+			"value":         "hunter2",
+		},
+		"custom": map[string]interface{}{
+			resource.SigKey:  ResourceReferenceSig,
+			"urn":            "urn:pulumi:stack::project::test:index:Bucket::bucket",
+			"id":             "bucket-1234",
+			"packageVersion": "1.0.0",
+		},
+		"unknownID": map[string]interface{}{
+			resource.SigKey: ResourceReferenceSig,
+			"urn":           "urn:pulumi:stack::project::test:index:Bucket::bucket",
+			"id":            "",
+		},
+		"component": map[string]interface{}{
+			resource.SigKey: ResourceReferenceSig,
+			"urn":           "urn:pulumi:stack::project::test:index:Group::group",
+		},
+	})
+	marshaled, err = MarshalProperties(sent, MarshalOptions{})
+	assert.Nil(t, err)
+	unmarshaled, err := UnmarshalProperties(marshaled, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, resource.NewStringProperty("hunter2"), unmarshaled["secret"])
+	assert.Equal(t, resource.NewStringProperty("bucket-1234"), unmarshaled["custom"])
+	assert.True(t, unmarshaled["unknownID"].IsComputed())
+	assert.Equal(t, resource.NewStringProperty("urn:pulumi:stack::project::test:index:Group::group"),
+		unmarshaled["component"])
+
+	// Unknown IDs are rejected like any other unknown.
+	_, err = UnmarshalProperties(marshaled, MarshalOptions{CompatibleWire: true, RejectUnknowns: true})
+	assert.Error(t, err)
+
+	// Without the compatible encoding, both are rejected.
+	for _, k := range []resource.PropertyKey{"secret", "custom"} {
+		_, err = UnmarshalPropertyValue(marshaled.Fields[string(k)], MarshalOptions{})
+		assert.Error(t, err, string(k))
+	}
+}
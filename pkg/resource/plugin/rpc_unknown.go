@@ -0,0 +1,124 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// UnknownSig is the unique signature for an unknown value that carries the type of its eventual value.
+const UnknownSig = "5f7b2c1e9a3d4e6f8b0c2d4e6f8a0b1c"
+
+// TypedUnknownsEnvVar is the environment variable that, when truthy, asks the engine to send unknown values to
+// resource providers as typed unknowns rather than as sentinel strings.  Only providers that understand typed unknowns
+// should be used when it is set.
+const TypedUnknownsEnvVar = "PULUMI_TYPED_UNKNOWNS"
+
+const (
+	unknownTypeKey        = "type"        // the key holding the eventual type in a typed unknown object.
+	unknownPlaceholderKey = "placeholder" // the key holding the optional example value in a typed unknown object.
+)
+
+// unknownElements maps the type names used by typed unknowns to the zero values of those types.
+var unknownElements = map[string]resource.PropertyValue{
+	"bool":    resource.NewBoolProperty(false),
+	"number":  resource.NewNumberProperty(0),
+	"string":  resource.NewStringProperty(""),
+	"array":   resource.NewArrayProperty([]resource.PropertyValue{}),
+	"asset":   resource.NewAssetProperty(&resource.Asset{}),
+	"archive": resource.NewArchiveProperty(&resource.Archive{}),
+	"object":  resource.NewObjectProperty(resource.PropertyMap{}),
+}
+
+// unknownTypeName returns the type name used by typed unknowns for the given element.  JSON values are sent to
+// providers as strings, so their unknowns are strings as well.
+func unknownTypeName(elem resource.PropertyValue) (string, bool) {
+	switch {
+	case elem.IsBool():
+		return "bool", true
+	case elem.IsNumber():
+		return "number", true
+	case elem.IsString(), elem.IsJSON():
+		return "string", true
+	case elem.IsArray():
+		return "array", true
+	case elem.IsAsset():
+		return "asset", true
+	case elem.IsArchive():
+		return "archive", true
+	case elem.IsObject():
+		return "object", true
+	default:
+		return "", false
+	}
+}
+
+// marshalTypedUnknown marshals an unknown value as an object that records the type of its eventual value.  If the
+// element is more than the zero value of its type, it is sent along as an example placeholder.
+func marshalTypedUnknown(elem resource.PropertyValue, opts MarshalOptions) (*structpb.Value, error) {
+	// If for some reason we end up with a recursive computed/output, just keep digging.
+	if elem.IsComputed() {
+		return marshalTypedUnknown(elem.Input().Element, opts)
+	} else if elem.IsOutput() {
+		return marshalTypedUnknown(elem.OutputValue().Element, opts)
+	}
+
+	// Finally, if a null, we can guess its value!  (the one and only...)
+	if elem.IsNull() {
+		return MarshalNull(opts), nil
+	}
+
+	typ, ok := unknownTypeName(elem)
+	contract.Assertf(ok, "Unexpected output/computed property element in RPC[%s]: %v", opts.Label, elem)
+
+	fields := map[string]*structpb.Value{
+		resource.SigKey: MarshalString(UnknownSig, opts),
+		unknownTypeKey:  MarshalString(typ, opts),
+	}
+	if !elem.IsAsset() && !elem.IsArchive() && !elem.DeepEquals(unknownElements[typ]) {
+		placeholder, err := MarshalPropertyValue(elem, opts)
+		if err != nil {
+			return nil, err
+		}
+		fields[unknownPlaceholderKey] = placeholder
+	}
+	return MarshalStruct(&structpb.Struct{Fields: fields}, opts), nil
+}
+
+// unmarshalTypedUnknown turns a typed unknown object back into a computed value whose element is either the example
+// placeholder, if one was sent, or the zero value of the unknown's type.
+func unmarshalTypedUnknown(obj resource.PropertyMap) (resource.PropertyValue, error) {
+	typ := obj[unknownTypeKey]
+	if !typ.IsString() {
+		return resource.PropertyValue{}, errors.New("typed unknown value is missing its 'type' field")
+	}
+	elem, ok := unknownElements[typ.StringValue()]
+	if !ok {
+		return resource.PropertyValue{}, errors.Errorf("unrecognized type '%s' for unknown value", typ.StringValue())
+	}
+
+	if placeholder, has := obj[unknownPlaceholderKey]; has && !placeholder.IsNull() {
+		if placeholderType, _ := unknownTypeName(placeholder); placeholderType != typ.StringValue() {
+			return resource.PropertyValue{}, errors.Errorf("placeholder for unknown %s value is a %s",
+				typ.StringValue(), placeholder.TypeString())
+		}
+		elem = placeholder
+	}
+	return resource.MakeComputed(elem), nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	opentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// startRPCSpan starts a span covering a provider operation, including the marshaling of its arguments and results, as
+// a child of the span in the given context.  The RPC itself must be issued with the returned context, so that its span
+// is a child of the operation's span; the trace context is then propagated to the provider through the RPC's metadata.
+//
+// Finishing the span also records the operation's latency in the provider metrics.
+func (p *provider) startRPCSpan(ctx context.Context, method string, urn resource.URN,
+	opts ...opentracing.StartSpanOption) (*rpcSpan, context.Context) {
+
+	opts = append(opts, opentracing.Tag{Key: "pkg", Value: string(p.pkg)})
+	if urn != "" {
+		opts = append(opts, opentracing.Tag{Key: "urn", Value: string(urn)})
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, "pulumi-provider-"+method, opts...)
+	return &rpcSpan{Span: span, pkg: string(p.pkg), method: method, start: time.Now()}, ctx
+}
+
+// WithTraceContext returns a view of the given provider whose creates, reads, updates, and deletes are traced as
+// children of the span in the given context, e.g. the span of the plan step that performs them, rather than of the
+// plugin context's span.  Providers other than plugins, and contexts that hold no span, leave the provider as it is.
+func WithTraceContext(ctx context.Context, prov Provider) Provider {
+	p, ok := prov.(*provider)
+	if !ok || opentracing.SpanFromContext(ctx) == nil {
+		return prov
+	}
+	return &tracedProvider{provider: p, trace: ctx}
+}
+
+// tracedProvider is a provider plugin whose resource operations are traced as children of the span in trace.
+type tracedProvider struct {
+	*provider
+
+	trace context.Context
+}
+
+func (p *tracedProvider) Create(urn resource.URN, props resource.PropertyMap, timeout float64) (resource.ID,
+	resource.PropertyMap, resource.Status, error) {
+	return p.create(p.trace, urn, props, timeout)
+}
+
+func (p *tracedProvider) Read(urn resource.URN, id resource.ID,
+	props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+	return p.read(p.trace, urn, id, props)
+}
+
+func (p *tracedProvider) Update(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
+	newInputs resource.PropertyMap, timeout float64) (resource.PropertyMap, resource.Status, error) {
+	return p.update(p.trace, urn, id, oldInputs, oldOutputs, newInputs, timeout)
+}
+
+func (p *tracedProvider) Delete(urn resource.URN, id resource.ID, props resource.PropertyMap,
+	timeout float64) (resource.Status, error) {
+	return p.delete(p.trace, urn, id, props, timeout)
+}
+
+// rpcSpan is a span that records the latency of the provider operation it covers when it is finished.
+type rpcSpan struct {
+	opentracing.Span
+
+	pkg    string
+	method string
+	start  time.Time
+}
+
+func (s *rpcSpan) Finish() {
+	s.Span.Finish()
+	providerCallDuration.ObserveSince(s.start, s.pkg, s.method)
+}
+
+// tagMarshaled records the number of properties in a marshaled property map, the size of their encoding, and the time
+// taken to marshal or unmarshal them as tags on the given span.
+func tagMarshaled(span opentracing.Span, name string, props resource.PropertyMap, m *_struct.Struct, start time.Time) {
+	span.SetTag(name+".properties", len(props))
+	span.SetTag(name+".bytes", proto.Size(m))
+	span.SetTag(name+".marshal_ms", float64(time.Since(start).Nanoseconds())/float64(time.Millisecond))
+}
+
+// marshalTraced marshals the given properties, recording their size and the time taken to marshal them on the span
+// and in the provider metrics.
+func marshalTraced(span *rpcSpan, name string, props resource.PropertyMap,
+	opts MarshalOptions) (*_struct.Struct, error) {
+
+	start := time.Now()
+	m, err := MarshalProperties(props, opts)
+	if err == nil {
+		tagMarshaled(span, name, props, m, start)
+		marshaledBytes.Add(float64(proto.Size(m)), "sent")
+		if opts.KeepUnknowns {
+			unknownProperties.Add(float64(countUnknowns(props)), span.method)
+		}
+	}
+	return m, err
+}
+
+// unmarshalTraced unmarshals the given properties, recording their size and the time taken to unmarshal them on the
+// span and in the provider metrics.
+func unmarshalTraced(span *rpcSpan, name string, m *_struct.Struct,
+	opts MarshalOptions) (resource.PropertyMap, error) {
+
+	start := time.Now()
+	props, err := UnmarshalProperties(m, opts)
+	if err == nil {
+		tagMarshaled(span, name, props, m, start)
+		marshaledBytes.Add(float64(proto.Size(m)), "received")
+	}
+	return props, err
+}
@@ -0,0 +1,57 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+type taggedSpan struct {
+	opentracing.Span
+
+	tags map[string]interface{}
+}
+
+func (s *taggedSpan) SetTag(key string, value interface{}) opentracing.Span {
+	s.tags[key] = value
+	return s
+}
+
+func TestMarshalTraced(t *testing.T) {
+	tagged := &taggedSpan{Span: opentracing.NoopTracer{}.StartSpan("test"), tags: map[string]interface{}{}}
+	span := &rpcSpan{Span: tagged, pkg: "pkgA", method: "create"}
+
+	props := resource.PropertyMap{
+		"a": resource.NewStringProperty("hello"),
+		"b": resource.NewNumberProperty(42),
+	}
+	m, err := marshalTraced(span, "inputs", props, MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tagged.tags["inputs.properties"])
+	assert.True(t, tagged.tags["inputs.bytes"].(int) > 0)
+	assert.Contains(t, tagged.tags, "inputs.marshal_ms")
+
+	outs, err := unmarshalTraced(span, "outputs", m, MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, props, outs)
+	assert.Equal(t, 2, tagged.tags["outputs.properties"])
+	assert.Equal(t, tagged.tags["inputs.bytes"], tagged.tags["outputs.bytes"])
+	assert.Contains(t, tagged.tags, "outputs.marshal_ms")
+}
@@ -0,0 +1,107 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy implements policy packs: sets of rules that are evaluated against the planned state of each
+// resource before any changes are made. Violations of advisory policies are reported as warnings; violations of
+// mandatory policies are reported as errors and prevent the update from proceeding.
+package policy
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// EnforcementLevel indicates how a policy violation is handled.
+type EnforcementLevel string
+
+const (
+	// Advisory policy violations are reported as warnings and do not block the update.
+	Advisory EnforcementLevel = "advisory"
+	// Mandatory policy violations are reported as errors and block the update.
+	Mandatory EnforcementLevel = "mandatory"
+)
+
+// Resource describes the planned state of a resource that is presented to a policy.
+type Resource struct {
+	Type       tokens.Type          // the resource's type.
+	URN        resource.URN         // the resource's URN.
+	Properties resource.PropertyMap // the resource's planned input properties, as returned by its provider's Check.
+}
+
+// Violation describes a resource's violation of a single policy.
+type Violation struct {
+	Policy           string               // the name of the violated policy.
+	Message          string               // a message describing the violation.
+	Property         resource.PropertyKey // the offending property, if any.
+	EnforcementLevel EnforcementLevel     // the enforcement level of the violated policy.
+}
+
+// Pack is a named set of policies.
+type Pack interface {
+	// Name returns the name of the policy pack.
+	Name() string
+	// Validate evaluates the pack's policies against the given resource and returns any violations.
+	Validate(res Resource) ([]Violation, error)
+}
+
+// Policy is a single rule within a policy pack.
+type Policy struct {
+	Name             string           // the name of the policy.
+	Description      string           // a description of the policy.
+	EnforcementLevel EnforcementLevel // the enforcement level of the policy; defaults to Advisory.
+
+	// Validate evaluates the policy against the given resource. Each violation must set its Message and may set its
+	// Property; the Policy and EnforcementLevel fields are filled in from the policy itself.
+	Validate func(res Resource) ([]Violation, error)
+}
+
+// NewPack creates a policy pack with the given name from a set of policies.
+func NewPack(name string, policies ...Policy) Pack {
+	return &pack{name: name, policies: policies}
+}
+
+type pack struct {
+	name     string
+	policies []Policy
+}
+
+func (p *pack) Name() string {
+	return p.name
+}
+
+func (p *pack) Validate(res Resource) ([]Violation, error) {
+	var violations []Violation
+	for _, policy := range p.policies {
+		if policy.Validate == nil {
+			continue
+		}
+
+		found, err := policy.Validate(res)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluating policy '%v'", policy.Name)
+		}
+
+		level := policy.EnforcementLevel
+		if level == "" {
+			level = Advisory
+		}
+		for _, v := range found {
+			v.Policy, v.EnforcementLevel = policy.Name, level
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
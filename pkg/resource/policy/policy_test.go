@@ -0,0 +1,103 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestPackValidate(t *testing.T) {
+	violate := func(message string) func(Resource) ([]Violation, error) {
+		return func(Resource) ([]Violation, error) {
+			return []Violation{{Message: message}}, nil
+		}
+	}
+
+	pack := NewPack("pack",
+		Policy{Name: "advisory", Validate: violate("a")},
+		Policy{Name: "mandatory", EnforcementLevel: Mandatory, Validate: violate("m")},
+		Policy{Name: "empty"})
+	assert.Equal(t, "pack", pack.Name())
+
+	violations, err := pack.Validate(Resource{Type: "pkgA:m:typA", URN: "urn", Properties: resource.PropertyMap{}})
+	assert.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Policy: "advisory", Message: "a", EnforcementLevel: Advisory},
+		{Policy: "mandatory", Message: "m", EnforcementLevel: Mandatory},
+	}, violations)
+
+	failing := NewPack("failing", Policy{Name: "broken", Validate: func(Resource) ([]Violation, error) {
+		return nil, errors.New("oops")
+	}})
+	_, err = failing.Validate(Resource{})
+	assert.EqualError(t, err, "evaluating policy 'broken': oops")
+}
+
+func TestPackFromSpec(t *testing.T) {
+	pack, err := NewPackFromSpec(PackSpec{
+		Name: "spec",
+		Policies: []PolicySpec{
+			{
+				Name:             "private-buckets",
+				EnforcementLevel: Mandatory,
+				ResourceTypes:    []tokens.Type{"aws:s3/bucket:Bucket"},
+				Property:         "acl",
+				Required:         true,
+				AllowedValues:    []interface{}{"private"},
+			},
+			{Name: "no-public", Property: "public", DisallowedValues: []interface{}{true}},
+		},
+	})
+	assert.NoError(t, err)
+
+	validate := func(typ tokens.Type, props resource.PropertyMap) []Violation {
+		violations, err := pack.Validate(Resource{Type: typ, Properties: props})
+		assert.NoError(t, err)
+		return violations
+	}
+
+	assert.Empty(t, validate("aws:s3/bucket:Bucket", resource.PropertyMap{"acl": resource.NewStringProperty("private")}))
+	assert.Empty(t, validate("aws:s3/bucket:Bucket", resource.PropertyMap{
+		"acl": resource.MakeComputed(resource.NewStringProperty("")),
+	}))
+	assert.Empty(t, validate("aws:sqs/queue:Queue", resource.PropertyMap{}))
+	assert.Equal(t, []Violation{{
+		Policy:           "private-buckets",
+		Message:          "the property is required",
+		Property:         "acl",
+		EnforcementLevel: Mandatory,
+	}}, validate("aws:s3/bucket:Bucket", resource.PropertyMap{}))
+	assert.Equal(t, []Violation{{
+		Policy:           "no-public",
+		Message:          "the property's value is not allowed",
+		Property:         "public",
+		EnforcementLevel: Advisory,
+	}}, validate("aws:sqs/queue:Queue", resource.PropertyMap{"public": resource.NewBoolProperty(true)}))
+	violations := validate("aws:s3/bucket:Bucket", resource.PropertyMap{"acl": resource.NewStringProperty("public")})
+	assert.Len(t, violations, 1)
+
+	_, err = NewPackFromSpec(PackSpec{Name: "bad", Policies: []PolicySpec{{Name: "p"}}})
+	assert.Error(t, err)
+	_, err = NewPackFromSpec(PackSpec{Name: "bad", Policies: []PolicySpec{
+		{Name: "p", Property: "a", AllowedValues: []interface{}{[]interface{}{}}},
+	}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/encoding"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// PackSpec is the serialized form of a policy pack whose policies constrain the values of resource properties.  Such
+// packs are loaded from JSON or YAML files by LoadPack, so that policies may be enforced without writing any Go.
+type PackSpec struct {
+	Name     string       `json:"name" yaml:"name"`         // the name of the policy pack.
+	Policies []PolicySpec `json:"policies" yaml:"policies"` // the policies in the pack.
+}
+
+// PolicySpec is the serialized form of a policy that constrains a single property of resources.
+type PolicySpec struct {
+	Name             string               `json:"name" yaml:"name"`
+	Description      string               `json:"description,omitempty" yaml:"description,omitempty"`
+	EnforcementLevel EnforcementLevel     `json:"enforcementLevel,omitempty" yaml:"enforcementLevel,omitempty"`
+	ResourceTypes    []tokens.Type        `json:"resourceTypes,omitempty" yaml:"resourceTypes,omitempty"`
+	Property         resource.PropertyKey `json:"property" yaml:"property"`
+	Required         bool                 `json:"required,omitempty" yaml:"required,omitempty"`
+	AllowedValues    []interface{}        `json:"allowedValues,omitempty" yaml:"allowedValues,omitempty"`
+	DisallowedValues []interface{}        `json:"disallowedValues,omitempty" yaml:"disallowedValues,omitempty"`
+}
+
+// LoadPack loads a policy pack from the JSON or YAML file at the given path (see PackSpec).
+func LoadPack(path string) (Pack, error) {
+	m, _ := encoding.Detect(path)
+	if m == nil {
+		return nil, errors.Errorf("unrecognized policy pack file extension for %s", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec PackSpec
+	if err = m.Unmarshal(b, &spec); err != nil {
+		return nil, errors.Wrapf(err, "loading policy pack %s", path)
+	}
+	pack, err := NewPackFromSpec(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading policy pack %s", path)
+	}
+	return pack, nil
+}
+
+// NewPackFromSpec creates a policy pack from its serialized form.  Each policy applies to the resources of the types
+// that it lists, or to all resources if it lists none, and reports a violation if its property is required but not
+// set, or is set to a value that is not allowed.  Properties whose values are not yet known are not judged.
+func NewPackFromSpec(spec PackSpec) (Pack, error) {
+	if spec.Name == "" {
+		return nil, errors.New("policy pack has no name")
+	}
+	policies := make([]Policy, len(spec.Policies))
+	for i, p := range spec.Policies {
+		policy, err := p.policy()
+		if err != nil {
+			return nil, errors.Wrapf(err, "policy %d", i)
+		}
+		policies[i] = policy
+	}
+	return NewPack(spec.Name, policies...), nil
+}
+
+func (spec PolicySpec) policy() (Policy, error) {
+	switch {
+	case spec.Name == "":
+		return Policy{}, errors.New("policy has no name")
+	case spec.Property == "":
+		return Policy{}, errors.Errorf("policy '%v' does not name a property", spec.Name)
+	case spec.EnforcementLevel != "" && spec.EnforcementLevel != Advisory && spec.EnforcementLevel != Mandatory:
+		return Policy{}, errors.Errorf("policy '%v' has unrecognized enforcement level '%v'",
+			spec.Name, spec.EnforcementLevel)
+	}
+
+	allowed, err := specValues(spec.AllowedValues)
+	if err != nil {
+		return Policy{}, errors.Wrapf(err, "policy '%v'", spec.Name)
+	}
+	disallowed, err := specValues(spec.DisallowedValues)
+	if err != nil {
+		return Policy{}, errors.Wrapf(err, "policy '%v'", spec.Name)
+	}
+	types := make(map[tokens.Type]bool)
+	for _, t := range spec.ResourceTypes {
+		types[t] = true
+	}
+
+	violation := func(message string) ([]Violation, error) {
+		return []Violation{{Message: message, Property: spec.Property}}, nil
+	}
+	return Policy{
+		Name:             spec.Name,
+		Description:      spec.Description,
+		EnforcementLevel: spec.EnforcementLevel,
+		Validate: func(res Resource) ([]Violation, error) {
+			if len(types) > 0 && !types[res.Type] {
+				return nil, nil
+			}
+
+			v, has := res.Properties[spec.Property]
+			switch {
+			case !has || v.IsNull():
+				if spec.Required {
+					return violation("the property is required")
+				}
+				return nil, nil
+			case v.ContainsUnknowns():
+				return nil, nil
+			case len(allowed) > 0 && !containsValue(allowed, v):
+				return violation("the property's value is not one of the allowed values")
+			case containsValue(disallowed, v):
+				return violation("the property's value is not allowed")
+			}
+			return nil, nil
+		},
+	}, nil
+}
+
+// specValues converts the allowed or disallowed values of a policy to property values.  Only booleans, numbers, and
+// strings may be listed.
+func specValues(values []interface{}) ([]resource.PropertyValue, error) {
+	var result []resource.PropertyValue
+	for _, v := range values {
+		switch v := v.(type) {
+		case bool:
+			result = append(result, resource.NewBoolProperty(v))
+		case int:
+			result = append(result, resource.NewNumberProperty(float64(v)))
+		case float64:
+			result = append(result, resource.NewNumberProperty(v))
+		case string:
+			result = append(result, resource.NewStringProperty(v))
+		default:
+			return nil, errors.Errorf("value %v is not a boolean, number, or string", v)
+		}
+	}
+	return result, nil
+}
+
+func containsValue(values []resource.PropertyValue, v resource.PropertyValue) bool {
+	for _, candidate := range values {
+		if candidate.DeepEquals(v) {
+			return true
+		}
+	}
+	return false
+}
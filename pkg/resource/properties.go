@@ -86,6 +86,22 @@ type Output struct {
 	Element PropertyValue // the eventual value (type) of the output property.
 }
 
+// Secret is a property value whose contents must never be displayed or logged.  Secrets are unwrapped before they are
+// passed to providers, so a provider sees the underlying value, but they are preserved in checkpoints.
+type Secret struct {
+	Element PropertyValue // the underlying value of the secret.
+}
+
+// String implements the fmt.Stringer interface so that formatting a secret never reveals its contents.
+func (s Secret) String() string { return "[secret]" }
+
+// JSON is a string property value that holds a JSON document.  JSON values are compared by the documents they hold
+// rather than by their text, so that differences in whitespace or in the order of object keys are insignificant.  They
+// are passed to providers and saved as ordinary strings.
+type JSON struct {
+	Raw string // the text of the document.
+}
+
 type ReqError struct {
 	K PropertyKey
 }
@@ -107,6 +123,132 @@ func (m PropertyMap) HasValue(k PropertyKey) bool {
 	return has && v.HasValue()
 }
 
+// HasKey returns true if the map contains the given key, even if its value is null.  Use this to distinguish a
+// property that was explicitly set to null (for example, to clear a field) from one that was left unset.
+func (m PropertyMap) HasKey(k PropertyKey) bool {
+	_, has := m[k]
+	return has
+}
+
+// GetOK returns the value for the given key and true if the map contains the key, even if its value is null.
+func (m PropertyMap) GetOK(k PropertyKey) (PropertyValue, bool) {
+	v, has := m[k]
+	return v, has
+}
+
+// TriState describes the presence of a property in a map: absent, present but null, or present with a value.
+type TriState int
+
+const (
+	// PropertyAbsent indicates that the map does not contain the property.
+	PropertyAbsent TriState = iota
+	// PropertyNull indicates that the map contains the property, but that its value is null.
+	PropertyNull
+	// PropertyPresent indicates that the map contains the property with a non-null value.
+	PropertyPresent
+	// PropertyMismatch indicates that the map contains the property, but that its value is not of the type that was
+	// asked for (for instance, because it is unknown).  Only the typed getters, such as GetString, report it.
+	PropertyMismatch
+)
+
+func (s TriState) String() string {
+	switch s {
+	case PropertyAbsent:
+		return "absent"
+	case PropertyNull:
+		return "null"
+	case PropertyPresent:
+		return "present"
+	case PropertyMismatch:
+		return "mismatch"
+	default:
+		contract.Failf("Unrecognized tri-state %d", int(s))
+		return ""
+	}
+}
+
+// State returns the tri-state of the given key.
+func (m PropertyMap) State(k PropertyKey) TriState {
+	v, has := m[k]
+	switch {
+	case !has:
+		return PropertyAbsent
+	case v.IsNull():
+		return PropertyNull
+	default:
+		return PropertyPresent
+	}
+}
+
+// getTyped returns the value for the given key along with its tri-state.  If the property is set to a value that does
+// not satisfy is, the state is PropertyMismatch; property maps often come from programs and providers, so a value of
+// the wrong type is not a programming error.
+func (m PropertyMap) getTyped(k PropertyKey, is func(PropertyValue) bool) (PropertyValue, TriState) {
+	state := m.State(k)
+	if state != PropertyPresent {
+		return PropertyValue{}, state
+	}
+	if v := m[k]; is(v) {
+		return v, state
+	}
+	return PropertyValue{}, PropertyMismatch
+}
+
+// GetBool returns the bool value for the given key, along with its tri-state.  The value is only meaningful if the
+// state is PropertyPresent.  The state is PropertyMismatch if the property is set to a value that is not a bool
+// (including an unknown).
+func (m PropertyMap) GetBool(k PropertyKey) (bool, TriState) {
+	v, state := m.getTyped(k, PropertyValue.IsBool)
+	if state != PropertyPresent {
+		return false, state
+	}
+	return v.BoolValue(), state
+}
+
+// GetNumber returns the number value for the given key, along with its tri-state.  The value is only meaningful if
+// the state is PropertyPresent.  The state is PropertyMismatch if the property is set to a value that is not a number
+// (including an unknown).
+func (m PropertyMap) GetNumber(k PropertyKey) (float64, TriState) {
+	v, state := m.getTyped(k, PropertyValue.IsNumber)
+	if state != PropertyPresent {
+		return 0, state
+	}
+	return v.NumberValue(), state
+}
+
+// GetString returns the string value for the given key, along with its tri-state.  The value is only meaningful if
+// the state is PropertyPresent.  The state is PropertyMismatch if the property is set to a value that is not a string
+// (including an unknown).
+func (m PropertyMap) GetString(k PropertyKey) (string, TriState) {
+	v, state := m.getTyped(k, PropertyValue.IsString)
+	if state != PropertyPresent {
+		return "", state
+	}
+	return v.StringValue(), state
+}
+
+// GetArray returns the array value for the given key, along with its tri-state.  The value is only meaningful if the
+// state is PropertyPresent.  The state is PropertyMismatch if the property is set to a value that is not an array
+// (including an unknown).
+func (m PropertyMap) GetArray(k PropertyKey) ([]PropertyValue, TriState) {
+	v, state := m.getTyped(k, PropertyValue.IsArray)
+	if state != PropertyPresent {
+		return nil, state
+	}
+	return v.ArrayValue(), state
+}
+
+// GetObject returns the object value for the given key, along with its tri-state.  The value is only meaningful if
+// the state is PropertyPresent.  The state is PropertyMismatch if the property is set to a value that is not an object
+// (including an unknown).
+func (m PropertyMap) GetObject(k PropertyKey) (PropertyMap, TriState) {
+	v, state := m.getTyped(k, PropertyValue.IsObject)
+	if state != PropertyPresent {
+		return nil, state
+	}
+	return v.ObjectValue(), state
+}
+
 // ContainsUnknowns returns true if the property map contains at least one unknown value.
 func (m PropertyMap) ContainsUnknowns() bool {
 	for _, v := range m {
@@ -117,6 +259,16 @@ func (m PropertyMap) ContainsUnknowns() bool {
 	return false
 }
 
+// ContainsSecrets returns true if the property map contains at least one secret value.
+func (m PropertyMap) ContainsSecrets() bool {
+	for _, v := range m {
+		if v.ContainsSecrets() {
+			return true
+		}
+	}
+	return false
+}
+
 // Mappable returns a mapper-compatible object map, suitable for deserialization into structures.
 func (m PropertyMap) Mappable() map[string]interface{} {
 	return m.MapRepl(nil, nil)
@@ -139,15 +291,27 @@ func (m PropertyMap) MapRepl(replk func(string) (string, bool),
 	return obj
 }
 
-// Copy makes a shallow copy of the map.
+// Copy makes a shallow copy of the map.  Any arrays, objects, assets, or archives in the map are shared with the copy.
 func (m PropertyMap) Copy() PropertyMap {
-	new := make(PropertyMap)
+	new := make(PropertyMap, len(m))
 	for k, v := range m {
 		new[k] = v
 	}
 	return new
 }
 
+// DeepCopy makes a deep copy of the map.  The result shares no mutable state with the original.
+func (m PropertyMap) DeepCopy() PropertyMap {
+	if m == nil {
+		return nil
+	}
+	new := make(PropertyMap, len(m))
+	for k, v := range m {
+		new[k] = v.DeepCopy()
+	}
+	return new
+}
+
 // Merge simply merges in another map atop another, and returns the result.
 func (m PropertyMap) Merge(other PropertyMap) PropertyMap {
 	new := m.Copy()
@@ -182,6 +346,10 @@ func NewArchiveProperty(v *Archive) PropertyValue      { return PropertyValue{v}
 func NewObjectProperty(v PropertyMap) PropertyValue    { return PropertyValue{v} }
 func NewComputedProperty(v Computed) PropertyValue     { return PropertyValue{v} }
 func NewOutputProperty(v Output) PropertyValue         { return PropertyValue{v} }
+func NewSecretProperty(v Secret) PropertyValue         { return PropertyValue{v} }
+func NewJSONProperty(raw string) PropertyValue         { return PropertyValue{JSON{Raw: raw}} }
+func NewFormatProperty(v Format) PropertyValue         { return PropertyValue{v} }
+func NewAppliedProperty(v Applied) PropertyValue       { return PropertyValue{v} }
 
 func MakeComputed(v PropertyValue) PropertyValue {
 	return NewComputedProperty(Computed{Element: v})
@@ -191,6 +359,10 @@ func MakeOutput(v PropertyValue) PropertyValue {
 	return NewOutputProperty(Output{Element: v})
 }
 
+func MakeSecret(v PropertyValue) PropertyValue {
+	return NewSecretProperty(Secret{Element: v})
+}
+
 // NewPropertyValue turns a value into a property value, provided it is of a legal "JSON-like" kind.
 func NewPropertyValue(v interface{}) PropertyValue {
 	return NewPropertyValueRepl(v, nil, nil)
@@ -242,6 +414,16 @@ func NewPropertyValueRepl(v interface{},
 		return NewComputedProperty(t)
 	case Output:
 		return NewOutputProperty(t)
+	case Secret:
+		return NewSecretProperty(t)
+	case JSON:
+		return NewJSONProperty(t.Raw)
+	case Format:
+		return NewFormatProperty(t)
+	case Applied:
+		return NewAppliedProperty(t)
+	case FileRef:
+		return PropertyValue{t}
 	}
 
 	// Next, see if it's an array, slice, pointer or struct, and handle each accordingly.
@@ -313,6 +495,40 @@ func (v PropertyValue) ContainsUnknowns() bool {
 		}
 	} else if v.IsObject() {
 		return v.ObjectValue().ContainsUnknowns()
+	} else if v.IsSecret() {
+		return v.SecretValue().Element.ContainsUnknowns()
+	} else if v.IsFormat() {
+		for _, arg := range v.FormatValue().Args {
+			if arg.ContainsUnknowns() {
+				return true
+			}
+		}
+	} else if v.IsApplied() {
+		for _, arg := range v.AppliedValue().Args {
+			if arg.ContainsUnknowns() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ContainsSecrets returns true if the property value contains at least one secret (deeply).
+func (v PropertyValue) ContainsSecrets() bool {
+	if v.IsSecret() {
+		return true
+	} else if v.IsArray() {
+		for _, e := range v.ArrayValue() {
+			if e.ContainsSecrets() {
+				return true
+			}
+		}
+	} else if v.IsObject() {
+		return v.ObjectValue().ContainsSecrets()
+	} else if v.IsComputed() {
+		return v.Input().Element.ContainsSecrets()
+	} else if v.IsOutput() {
+		return v.OutputValue().Element.ContainsSecrets()
 	}
 	return false
 }
@@ -344,6 +560,21 @@ func (v PropertyValue) Input() Computed { return v.V.(Computed) }
 // OutputValue fetches the underlying output value (panicking if it isn't a output).
 func (v PropertyValue) OutputValue() Output { return v.V.(Output) }
 
+// SecretValue fetches the underlying secret value (panicking if it isn't a secret).
+func (v PropertyValue) SecretValue() Secret { return v.V.(Secret) }
+
+// JSONValue fetches the underlying JSON value (panicking if it isn't a JSON value).
+func (v PropertyValue) JSONValue() JSON { return v.V.(JSON) }
+
+// FormatValue fetches the underlying format value (panicking if it isn't a format).
+func (v PropertyValue) FormatValue() Format { return v.V.(Format) }
+
+// AppliedValue fetches the underlying applied value (panicking if it isn't an applied value).
+func (v PropertyValue) AppliedValue() Applied { return v.V.(Applied) }
+
+// FileRefValue fetches the underlying file reference (panicking if it isn't a file reference).
+func (v PropertyValue) FileRefValue() FileRef { return v.V.(FileRef) }
+
 // IsNull returns true if the underlying value is a null.
 func (v PropertyValue) IsNull() bool {
 	return v.V == nil
@@ -403,6 +634,36 @@ func (v PropertyValue) IsOutput() bool {
 	return is
 }
 
+// IsJSON returns true if the underlying value is a JSON value.
+func (v PropertyValue) IsJSON() bool {
+	_, is := v.V.(JSON)
+	return is
+}
+
+// IsFormat returns true if the underlying value is a format.
+func (v PropertyValue) IsFormat() bool {
+	_, is := v.V.(Format)
+	return is
+}
+
+// IsApplied returns true if the underlying value is an applied value.
+func (v PropertyValue) IsApplied() bool {
+	_, is := v.V.(Applied)
+	return is
+}
+
+// IsSecret returns true if the underlying value is a secret value.
+func (v PropertyValue) IsSecret() bool {
+	_, is := v.V.(Secret)
+	return is
+}
+
+// IsFileRef returns true if the underlying value is a file reference.
+func (v PropertyValue) IsFileRef() bool {
+	_, is := v.V.(FileRef)
+	return is
+}
+
 // TypeString returns a type representation of the property value's holder type.
 func (v PropertyValue) TypeString() string {
 	if v.IsNull() {
@@ -413,6 +674,14 @@ func (v PropertyValue) TypeString() string {
 		return "number"
 	} else if v.IsString() {
 		return "string"
+	} else if v.IsJSON() {
+		return "json"
+	} else if v.IsFormat() {
+		return "format"
+	} else if v.IsApplied() {
+		return "applied"
+	} else if v.IsFileRef() {
+		return "fileref"
 	} else if v.IsArray() {
 		return "[]"
 	} else if v.IsAsset() {
@@ -425,6 +694,8 @@ func (v PropertyValue) TypeString() string {
 		return "output<" + v.Input().Element.TypeString() + ">"
 	} else if v.IsOutput() {
 		return "output<" + v.OutputValue().Element.TypeString() + ">"
+	} else if v.IsSecret() {
+		return "secret<" + v.SecretValue().Element.TypeString() + ">"
 	}
 	contract.Failf("Unrecognized PropertyValue type")
 	return ""
@@ -452,6 +723,14 @@ func (v PropertyValue) MapRepl(replk func(string) (string, bool),
 		return v.NumberValue()
 	} else if v.IsString() {
 		return v.StringValue()
+	} else if v.IsJSON() {
+		return v.JSONValue().Raw
+	} else if v.IsFormat() {
+		return v.FormatValue()
+	} else if v.IsApplied() {
+		return v.AppliedValue()
+	} else if v.IsFileRef() {
+		return v.FileRefValue()
 	} else if v.IsArray() {
 		var arr []interface{}
 		for _, e := range v.ArrayValue() {
@@ -466,11 +745,58 @@ func (v PropertyValue) MapRepl(replk func(string) (string, bool),
 		return v.Input()
 	} else if v.IsOutput() {
 		return v.OutputValue()
+	} else if v.IsSecret() {
+		return v.SecretValue().Element.MapRepl(replk, replv)
 	}
 	contract.Assertf(v.IsObject(), "v is not Object '%v' instead", v.TypeString())
 	return v.ObjectValue().MapRepl(replk, replv)
 }
 
+// DeepCopy makes a deep copy of the value.  The result shares no mutable state with the original.
+func (v PropertyValue) DeepCopy() PropertyValue {
+	switch {
+	case v.IsArray():
+		arr := v.ArrayValue()
+		if arr == nil {
+			return v
+		}
+		new := make([]PropertyValue, len(arr))
+		for i, e := range arr {
+			new[i] = e.DeepCopy()
+		}
+		return NewArrayProperty(new)
+	case v.IsObject():
+		return NewObjectProperty(v.ObjectValue().DeepCopy())
+	case v.IsAsset():
+		return NewAssetProperty(v.AssetValue().deepCopy())
+	case v.IsArchive():
+		return NewArchiveProperty(v.ArchiveValue().deepCopy())
+	case v.IsComputed():
+		return NewComputedProperty(Computed{Element: v.Input().Element.DeepCopy()})
+	case v.IsOutput():
+		return NewOutputProperty(Output{Element: v.OutputValue().Element.DeepCopy()})
+	case v.IsSecret():
+		return MakeSecret(v.SecretValue().Element.DeepCopy())
+	case v.IsFormat():
+		f := v.FormatValue()
+		args := make([]PropertyValue, len(f.Args))
+		for i, arg := range f.Args {
+			args[i] = arg.DeepCopy()
+		}
+		return NewFormatProperty(Format{Template: f.Template, Args: args})
+	case v.IsApplied():
+		a := v.AppliedValue()
+		args := make([]PropertyValue, len(a.Args))
+		for i, arg := range a.Args {
+			args[i] = arg.DeepCopy()
+		}
+		return NewAppliedProperty(Applied{Args: args, Fn: a.Fn})
+	default:
+		// Nulls, bools, numbers, strings, and JSON values are immutable.
+		return v
+	}
+}
+
 // merge simply merges the value of other into v. Merging proceeds as follows:
 // - If other is null, v is returned.
 // - If v and other are both arrays, the corresponding elements are recurively merged. Any unmerged elements in v or
@@ -512,6 +838,9 @@ func (v PropertyValue) String() string {
 	if v.IsComputed() || v.IsOutput() {
 		// For computed and output properties, show their type followed by an empty object string.
 		return fmt.Sprintf("%v{}", v.TypeString())
+	} else if v.IsSecret() {
+		// Never display the contents of a secret.
+		return "{[secret]}"
 	}
 	// For all others, just display the underlying property value.
 	return fmt.Sprintf("{%v}", v.V)
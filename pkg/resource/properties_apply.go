@@ -0,0 +1,152 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Applied is a property value that is derived from other property values by a function, such as a connection string
+// built from the host and port outputs of a database.  The function is not run until the value is resolved, and it is
+// only run if none of the values it is derived from are unknown; otherwise the applied value is itself unknown.
+type Applied struct {
+	Args []PropertyValue                                   // the values from which this value is derived.
+	Fn   func(args []PropertyValue) (PropertyValue, error) // the function that derives this value.
+}
+
+// Apply returns a value that is derived from this one by the given function.  The function receives this value after
+// it has been resolved, and it is not run if that value contains unknowns.
+func (v PropertyValue) Apply(applier func(PropertyValue) (PropertyValue, error)) PropertyValue {
+	return NewAppliedProperty(Applied{
+		Args: []PropertyValue{v},
+		Fn:   func(args []PropertyValue) (PropertyValue, error) { return applier(args[0]) },
+	})
+}
+
+// All returns a value that resolves to an array of the given values.  It is unknown if any of the values are unknown,
+// and so may be combined with Apply to derive a value from several others.
+func All(values ...PropertyValue) PropertyValue {
+	return NewAppliedProperty(Applied{
+		Args: values,
+		Fn:   func(args []PropertyValue) (PropertyValue, error) { return NewArrayProperty(args), nil },
+	})
+}
+
+// Resolve runs the applied value's function over its resolved arguments.  If any argument contains unknowns, the
+// function is not run and the result is an unknown value.
+func (a Applied) Resolve() (PropertyValue, error) {
+	args := make([]PropertyValue, len(a.Args))
+	for i, arg := range a.Args {
+		resolved, err := arg.Resolve()
+		if err != nil {
+			return PropertyValue{}, err
+		}
+		if resolved.ContainsUnknowns() {
+			return MakeComputed(NewStringProperty("")), nil
+		}
+		args[i] = resolved
+	}
+	result, err := a.Fn(args)
+	if err != nil {
+		return PropertyValue{}, err
+	}
+	// The function may itself return a lazy value, so resolve its result too.
+	return result.Resolve()
+}
+
+// Resolve returns the value with every format and applied value, however deeply nested, replaced by its result.
+func (v PropertyValue) Resolve() (PropertyValue, error) {
+	if !v.containsLazyValues() {
+		return v, nil
+	}
+	return v.resolve()
+}
+
+// Resolve returns a copy of the map in which every format and applied value, however deeply nested, is replaced by
+// its result.  If the map holds no such values, the map itself is returned.
+func (m PropertyMap) Resolve() (PropertyMap, error) {
+	if !NewObjectProperty(m).containsLazyValues() {
+		return m, nil
+	}
+	return m.resolve()
+}
+
+func (m PropertyMap) resolve() (PropertyMap, error) {
+	result := make(PropertyMap, len(m))
+	for _, k := range m.StableKeys() {
+		v, err := m[k].resolve()
+		if err != nil {
+			return nil, errors.Wrapf(err, "property %s", k)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+func (v PropertyValue) resolve() (PropertyValue, error) {
+	switch {
+	case v.IsFormat():
+		return v.FormatValue().Evaluate()
+	case v.IsApplied():
+		return v.AppliedValue().Resolve()
+	case v.IsArray():
+		arr := make([]PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			ev, err := e.resolve()
+			if err != nil {
+				return PropertyValue{}, errors.Wrapf(err, "element %d", i)
+			}
+			arr[i] = ev
+		}
+		return NewArrayProperty(arr), nil
+	case v.IsObject():
+		obj, err := v.ObjectValue().resolve()
+		if err != nil {
+			return PropertyValue{}, err
+		}
+		return NewObjectProperty(obj), nil
+	case v.IsSecret():
+		elem, err := v.SecretValue().Element.resolve()
+		if err != nil {
+			return PropertyValue{}, err
+		}
+		return MakeSecret(elem), nil
+	default:
+		return v, nil
+	}
+}
+
+// containsLazyValues returns true if the value is or contains a format or an applied value.
+func (v PropertyValue) containsLazyValues() bool {
+	switch {
+	case v.IsFormat() || v.IsApplied():
+		return true
+	case v.IsArray():
+		for _, e := range v.ArrayValue() {
+			if e.containsLazyValues() {
+				return true
+			}
+		}
+	case v.IsObject():
+		for _, e := range v.ObjectValue() {
+			if e.containsLazyValues() {
+				return true
+			}
+		}
+	case v.IsSecret():
+		return v.SecretValue().Element.containsLazyValues()
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// Kind tags used by the canonical encoding.  Each encoded value begins with exactly one of these.
+const (
+	canonicalNull     = 'n'
+	canonicalFalse    = 'f'
+	canonicalTrue     = 't'
+	canonicalNumber   = 'd'
+	canonicalString   = 's'
+	canonicalArray    = 'a'
+	canonicalObject   = 'o'
+	canonicalAsset    = 'A'
+	canonicalArchive  = 'R'
+	canonicalComputed = 'c'
+	canonicalOutput   = 'u'
+	canonicalJSON     = 'j'
+	canonicalJSONText = 'J'
+	canonicalFileRef  = 'F'
+)
+
+// Fingerprint is the SHA-256 digest of a property map's canonical encoding.
+type Fingerprint [sha256.Size]byte
+
+// String returns the hex encoding of the fingerprint.
+func (f Fingerprint) String() string {
+	return hex.EncodeToString(f[:])
+}
+
+// CanonicalBytes returns a deterministic byte encoding of the property map.  Two maps that are equal produce the same
+// bytes regardless of map iteration order or how their values were constructed.  The encoding is normalized as follows:
+//
+//   - keys are sorted and properties without a value (nulls and outputs) are omitted, just as DeepEquals ignores them;
+//   - numbers are encoded as IEEE-754 bits, with negative zero folded into zero and all NaNs folded into one;
+//   - every value carries an explicit kind tag, so that, e.g., the string "1" and the number 1 differ;
+//   - assets and archives are encoded by their content hash when one is known, and by their source otherwise;
+//   - JSON values are encoded by the documents they hold, or by their text if they do not hold a valid document;
+//   - file references are encoded as the strings they refer to, or by their hash if their files cannot be read.
+//
+// The encoding is intended for hashing and comparison only; it is not meant to be decoded.
+func (m PropertyMap) CanonicalBytes() []byte {
+	var buf bytes.Buffer
+	writeCanonicalMap(&buf, m)
+	return buf.Bytes()
+}
+
+// Fingerprint returns the SHA-256 digest of the property map's canonical encoding.  Maps with equal fingerprints
+// are bit-identical after normalization.
+func (m PropertyMap) Fingerprint() Fingerprint {
+	return sha256.Sum256(m.CanonicalBytes())
+}
+
+// CanonicalBytes returns a deterministic byte encoding of the property value.  See PropertyMap.CanonicalBytes.
+func (v PropertyValue) CanonicalBytes() []byte {
+	var buf bytes.Buffer
+	writeCanonicalValue(&buf, v)
+	return buf.Bytes()
+}
+
+func writeCanonicalLength(buf *bytes.Buffer, n int) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], uint64(n))])
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	writeCanonicalLength(buf, len(s))
+	buf.WriteString(s)
+}
+
+func writeCanonicalMap(buf *bytes.Buffer, m PropertyMap) {
+	var keys []PropertyKey
+	for _, k := range m.StableKeys() {
+		if m[k].HasValue() {
+			keys = append(keys, k)
+		}
+	}
+
+	buf.WriteByte(canonicalObject)
+	writeCanonicalLength(buf, len(keys))
+	for _, k := range keys {
+		writeCanonicalString(buf, string(k))
+		writeCanonicalValue(buf, m[k])
+	}
+}
+
+func writeCanonicalValue(buf *bytes.Buffer, v PropertyValue) {
+	switch {
+	case v.IsNull():
+		buf.WriteByte(canonicalNull)
+	case v.IsBool():
+		if v.BoolValue() {
+			buf.WriteByte(canonicalTrue)
+		} else {
+			buf.WriteByte(canonicalFalse)
+		}
+	case v.IsNumber():
+		n := v.NumberValue()
+		switch {
+		case n == 0:
+			n = 0 // fold -0 into +0.
+		case math.IsNaN(n):
+			n = math.NaN()
+		}
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(n))
+		buf.WriteByte(canonicalNumber)
+		buf.Write(b[:])
+	case v.IsString():
+		buf.WriteByte(canonicalString)
+		writeCanonicalString(buf, v.StringValue())
+	case v.IsJSON():
+		writeCanonicalJSON(buf, v.JSONValue())
+	case v.IsFileRef():
+		writeCanonicalFileRef(buf, v.FileRefValue())
+	case v.IsArray():
+		arr := v.ArrayValue()
+		buf.WriteByte(canonicalArray)
+		writeCanonicalLength(buf, len(arr))
+		for _, e := range arr {
+			writeCanonicalValue(buf, e)
+		}
+	case v.IsAsset():
+		writeCanonicalAsset(buf, v.AssetValue())
+	case v.IsArchive():
+		writeCanonicalArchive(buf, v.ArchiveValue())
+	case v.IsObject():
+		writeCanonicalMap(buf, v.ObjectValue())
+	case v.IsComputed():
+		buf.WriteByte(canonicalComputed)
+		writeCanonicalValue(buf, v.Input().Element)
+	case v.IsOutput():
+		buf.WriteByte(canonicalOutput)
+		writeCanonicalValue(buf, v.OutputValue().Element)
+	case v.IsSecret():
+		// Secrets hash like their values, just as they compare equal to them (see DeepEquals).
+		writeCanonicalValue(buf, v.SecretValue().Element)
+	default:
+		contract.Failf("Unrecognized property value: %v (type=%v)", v.V, reflect.TypeOf(v.V))
+	}
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, j JSON) {
+	doc, err := j.Document()
+	if err != nil {
+		buf.WriteByte(canonicalJSONText)
+		writeCanonicalString(buf, j.Raw)
+		return
+	}
+	buf.WriteByte(canonicalJSON)
+	writeCanonicalValue(buf, doc)
+}
+
+// writeCanonicalFileRef encodes a file reference as the string that it refers to, so that it hashes like the strings
+// that it is equal to.  A reference whose file is missing or has changed is encoded by its hash instead.
+func writeCanonicalFileRef(buf *bytes.Buffer, r FileRef) {
+	if s, err := r.Read(); err == nil {
+		buf.WriteByte(canonicalString)
+		writeCanonicalString(buf, s)
+		return
+	}
+	buf.WriteByte(canonicalFileRef)
+	writeCanonicalString(buf, r.Hash)
+}
+
+func writeCanonicalAsset(buf *bytes.Buffer, a *Asset) {
+	buf.WriteByte(canonicalAsset)
+	if a.Hash != "" {
+		buf.WriteByte('h')
+		writeCanonicalString(buf, a.Hash)
+		return
+	}
+	buf.WriteByte('s')
+	writeCanonicalString(buf, a.Text)
+	writeCanonicalString(buf, a.Path)
+	writeCanonicalString(buf, a.URI)
+}
+
+func writeCanonicalArchive(buf *bytes.Buffer, a *Archive) {
+	buf.WriteByte(canonicalArchive)
+	if a.Hash != "" {
+		buf.WriteByte('h')
+		writeCanonicalString(buf, a.Hash)
+		return
+	}
+	buf.WriteByte('s')
+	writeCanonicalString(buf, a.Path)
+	writeCanonicalString(buf, a.URI)
+
+	keys := make([]string, 0, len(a.Assets))
+	for k := range a.Assets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	writeCanonicalLength(buf, len(keys))
+	for _, k := range keys {
+		writeCanonicalString(buf, k)
+		switch t := a.Assets[k].(type) {
+		case *Asset:
+			writeCanonicalAsset(buf, t)
+		case *Archive:
+			writeCanonicalArchive(buf, t)
+		default:
+			contract.Failf("Unrecognized asset map type %v", reflect.TypeOf(t))
+		}
+	}
+}
+
+// SortedSet returns a copy of an array value whose elements are sorted by the SHA-256 digests of their canonical
+// encodings, so that two arrays holding the same elements in different orders become equal.  Values that are not
+// arrays are returned unchanged.
+func (v PropertyValue) SortedSet() PropertyValue {
+	if !v.IsArray() {
+		return v
+	}
+
+	arr := v.ArrayValue()
+	hashes := make([][sha256.Size]byte, len(arr))
+	indices := make([]int, len(arr))
+	for i, e := range arr {
+		hashes[i], indices[i] = sha256.Sum256(e.CanonicalBytes()), i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return bytes.Compare(hashes[indices[i]][:], hashes[indices[j]][:]) < 0
+	})
+
+	sorted := make([]PropertyValue, len(arr))
+	for i, idx := range indices {
+		sorted[i] = arr[idx]
+	}
+	return NewArrayProperty(sorted)
+}
+
+// SortSets returns a copy of the map in which the array at each of the given paths has been replaced by its
+// SortedSet.  Paths that are absent or that do not name arrays are ignored.  If no path names an array, the map
+// itself is returned.
+func (m PropertyMap) SortSets(paths []PropertyPath) PropertyMap {
+	result, copied := m, false
+	for _, path := range paths {
+		v, has := path.Get(result)
+		if !has || !v.IsArray() {
+			continue
+		}
+		if !copied {
+			result, copied = m.DeepCopy(), true
+		}
+		path.Set(result, v.SortedSet())
+	}
+	return result
+}
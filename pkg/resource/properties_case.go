@@ -0,0 +1,237 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// KeyCase is a naming convention for property keys.
+type KeyCase int
+
+const (
+	CamelCase  KeyCase = iota // e.g. `instanceType`, the convention of Pulumi's own property keys.
+	SnakeCase                 // e.g. `instance_type`.
+	PascalCase                // e.g. `InstanceType`.
+)
+
+func (c KeyCase) String() string {
+	switch c {
+	case CamelCase:
+		return "camelCase"
+	case SnakeCase:
+		return "snake_case"
+	case PascalCase:
+		return "PascalCase"
+	default:
+		return "unknown"
+	}
+}
+
+// Convert converts a key written in any of the conventions to this one.  Leading underscores are kept as they are.
+func (c KeyCase) Convert(key string) string {
+	trimmed := strings.TrimLeft(key, "_")
+	prefix, words := key[:len(key)-len(trimmed)], SplitKeyWords(trimmed)
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i, w := range words {
+		switch {
+		case c == SnakeCase:
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteString(w)
+		case c == CamelCase && i == 0:
+			b.WriteString(w)
+		default:
+			r := []rune(w)
+			b.WriteRune(unicode.ToUpper(r[0]))
+			b.WriteString(string(r[1:]))
+		}
+	}
+	return b.String()
+}
+
+// SplitKeyWords splits a key written in any of the conventions into its words, in lower case.  Words are separated by
+// underscores, hyphens, or changes from lower to upper case.  A run of upper case letters is an acronym, which ends
+// before the last of them if that letter begins a lower case word, so `HTTPEndpoint` is `http` and `endpoint`.  Digits
+// belong to the word that they follow, so `ipv6Address` is `ipv6` and `address`.
+func SplitKeyWords(key string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// KeyMapper converts the keys of property maps, including those of nested objects, from one naming convention to
+// another.  Providers that bridge APIs whose conventions differ from Pulumi's can use a pair of mappers, one for each
+// direction, rather than converting each property by hand.
+type KeyMapper struct {
+	// Case is the convention to which keys are converted.
+	Case KeyCase
+	// Overrides maps keys, at any depth, to the keys that they are converted to, for keys whose conversion cannot be
+	// derived from their words (e.g. `HTTPEndpoint`, whose camelCase form loses the acronym).
+	Overrides map[PropertyKey]PropertyKey
+	// Opaque lists the paths of objects whose keys are data rather than names, such as a resource's tags, and so are
+	// kept as they are.  Paths are written with the keys of the unconverted map and without array indices, so
+	// `rules.labels` names the labels of every element of the `rules` array.
+	Opaque []PropertyPath
+}
+
+// MapKey converts a single key.  Keys that begin with two underscores are reserved for use by the engine and by
+// providers (e.g. `__defaults`), and are never converted.
+func (m KeyMapper) MapKey(key PropertyKey) PropertyKey {
+	if to, has := m.Overrides[key]; has {
+		return to
+	}
+	if strings.HasPrefix(string(key), "__") {
+		return key
+	}
+	return PropertyKey(m.Case.Convert(string(key)))
+}
+
+// Map returns a copy of the given properties whose keys have been converted.  It is an error for two keys of the same
+// object to be converted to the same key.
+func (m KeyMapper) Map(props PropertyMap) (PropertyMap, error) {
+	return m.mapObject(nil, props)
+}
+
+// Inverse returns a mapper that converts keys that this mapper has converted back to the given convention.  Overrides
+// are inverted, and opaque paths are converted, so that a map that has been converted by this mapper and then by the
+// inverse has the keys it began with, as long as those keys followed the given convention.
+func (m KeyMapper) Inverse(from KeyCase) KeyMapper {
+	inverse := KeyMapper{Case: from}
+	if m.Overrides != nil {
+		inverse.Overrides = make(map[PropertyKey]PropertyKey, len(m.Overrides))
+		for k, v := range m.Overrides {
+			inverse.Overrides[v] = k
+		}
+	}
+	for _, path := range m.Opaque {
+		mapped := make(PropertyPath, len(path))
+		for i, elem := range path {
+			if key, ok := elem.(string); ok {
+				mapped[i] = string(m.MapKey(PropertyKey(key)))
+			} else {
+				mapped[i] = elem
+			}
+		}
+		inverse.Opaque = append(inverse.Opaque, mapped)
+	}
+	return inverse
+}
+
+func (m KeyMapper) mapObject(path PropertyPath, props PropertyMap) (PropertyMap, error) {
+	if props == nil {
+		return nil, nil
+	}
+
+	result := make(PropertyMap, len(props))
+	from := make(map[PropertyKey]PropertyKey, len(props))
+	for _, k := range props.StableKeys() {
+		to := m.MapKey(k)
+		if other, has := from[to]; has {
+			return nil, errors.Errorf("properties '%v' and '%v' would both be converted to '%v'", other, k, to)
+		}
+		from[to] = k
+
+		p := append(path[:len(path):len(path)], string(k))
+		if m.opaque(p) {
+			result[to] = props[k]
+			continue
+		}
+		v, err := m.mapValue(p, props[k])
+		if err != nil {
+			return nil, err
+		}
+		result[to] = v
+	}
+	return result, nil
+}
+
+func (m KeyMapper) mapValue(path PropertyPath, v PropertyValue) (PropertyValue, error) {
+	switch {
+	case v.IsObject():
+		obj, err := m.mapObject(path, v.ObjectValue())
+		if err != nil {
+			return PropertyValue{}, err
+		}
+		return NewObjectProperty(obj), nil
+	case v.IsArray():
+		arr := make([]PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			mapped, err := m.mapValue(path, elem)
+			if err != nil {
+				return PropertyValue{}, err
+			}
+			arr[i] = mapped
+		}
+		return NewArrayProperty(arr), nil
+	default:
+		return v, nil
+	}
+}
+
+// opaque returns true if the keys of the value at the given path, which holds no array indices, are kept as they are.
+func (m KeyMapper) opaque(path PropertyPath) bool {
+	for _, o := range m.Opaque {
+		keys := make(PropertyPath, 0, len(o))
+		for _, elem := range o {
+			if _, ok := elem.(string); ok {
+				keys = append(keys, elem)
+			}
+		}
+		if len(keys) != len(path) {
+			continue
+		}
+		matches := true
+		for i := range keys {
+			if keys[i] != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
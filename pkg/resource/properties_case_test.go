@@ -0,0 +1,96 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyCaseConvert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key    string
+		camel  string
+		snake  string
+		pascal string
+	}{
+		{"instanceType", "instanceType", "instance_type", "InstanceType"},
+		{"instance_type", "instanceType", "instance_type", "InstanceType"},
+		{"InstanceType", "instanceType", "instance_type", "InstanceType"},
+		{"HTTPEndpoint", "httpEndpoint", "http_endpoint", "HttpEndpoint"},
+		{"vpcID", "vpcId", "vpc_id", "VpcId"},
+		{"ipv6Address", "ipv6Address", "ipv6_address", "Ipv6Address"},
+		{"s3-bucket", "s3Bucket", "s3_bucket", "S3Bucket"},
+		{"_private", "_private", "_private", "_Private"},
+		{"name", "name", "name", "Name"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.camel, CamelCase.Convert(test.key), test.key)
+		assert.Equal(t, test.snake, SnakeCase.Convert(test.key), test.key)
+		assert.Equal(t, test.pascal, PascalCase.Convert(test.key), test.key)
+	}
+}
+
+func TestKeyMapper(t *testing.T) {
+	t.Parallel()
+
+	props := NewPropertyMapFromMap(map[string]interface{}{
+		"instanceType": "t2.micro",
+		"httpEndpoint": "enabled",
+		"__defaults":   []interface{}{"instanceType"},
+		"tags":         map[string]interface{}{"ownerName": "me"},
+		"blockDevices": []interface{}{
+			map[string]interface{}{"deviceName": "sda", "volumeSize": 8},
+			map[string]interface{}{"deviceName": "sdb", "labels": map[string]interface{}{"diskKind": "ssd"}},
+		},
+	})
+	props["subnetId"] = MakeComputed(NewStringProperty(""))
+
+	toPascal := KeyMapper{
+		Case:      PascalCase,
+		Overrides: map[PropertyKey]PropertyKey{"httpEndpoint": "HTTPEndpoint"},
+		Opaque:    []PropertyPath{{"tags"}, {"blockDevices", 0, "labels"}},
+	}
+	pascal, err := toPascal.Map(props)
+	assert.NoError(t, err)
+
+	expected := NewPropertyMapFromMap(map[string]interface{}{
+		"InstanceType": "t2.micro",
+		"HTTPEndpoint": "enabled",
+		"__defaults":   []interface{}{"instanceType"},
+		"Tags":         map[string]interface{}{"ownerName": "me"},
+		"BlockDevices": []interface{}{
+			map[string]interface{}{"DeviceName": "sda", "VolumeSize": 8},
+			map[string]interface{}{"DeviceName": "sdb", "Labels": map[string]interface{}{"diskKind": "ssd"}},
+		},
+	})
+	expected["SubnetId"] = MakeComputed(NewStringProperty(""))
+	assert.Equal(t, expected, pascal)
+
+	// The inverse mapper restores the original keys, including those that were overridden.
+	camel, err := toPascal.Inverse(CamelCase).Map(pascal)
+	assert.NoError(t, err)
+	assert.Equal(t, props, camel)
+
+	// Keys that would collide are rejected.
+	_, err = KeyMapper{Case: SnakeCase}.Map(NewPropertyMapFromMap(map[string]interface{}{
+		"vpcId":  "a",
+		"vpc_id": "b",
+	}))
+	assert.Error(t, err)
+}
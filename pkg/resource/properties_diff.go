@@ -55,6 +55,73 @@ func (diff *ObjectDiff) Same(k PropertyKey) bool {
 	return !diff.Changed(k)
 }
 
+// ChangedKeys returns a stable snapshot of all keys that were added, deleted, or updated.  Unlike Keys, it omits the
+// keys that are the same on both sides of the diff.
+func (diff *ObjectDiff) ChangedKeys() []PropertyKey {
+	var ks []PropertyKey
+	for _, k := range diff.Keys() {
+		if diff.Changed(k) {
+			ks = append(ks, k)
+		}
+	}
+	return ks
+}
+
+// ChangedPaths returns a stable snapshot of the paths to every property that was added, deleted, or updated.  Changes
+// inside of nested objects and arrays are reported at the innermost element that changed.
+func (diff *ObjectDiff) ChangedPaths() []PropertyPath {
+	var paths []PropertyPath
+	for _, k := range diff.ChangedKeys() {
+		paths = append(paths, diff.changedPaths(PropertyPath{string(k)}, k)...)
+	}
+	return paths
+}
+
+// changedPaths returns the paths beneath the given key, rooted at prefix, that have changed.
+func (diff *ObjectDiff) changedPaths(prefix PropertyPath, k PropertyKey) []PropertyPath {
+	if update, has := diff.Updates[k]; has {
+		if nested := update.changedPaths(prefix); len(nested) > 0 {
+			return nested
+		}
+	}
+	return []PropertyPath{prefix}
+}
+
+// changedPaths returns the paths beneath prefix that have changed within a nested object or array.  It returns nil
+// for primitive values, in which case prefix itself is the innermost change.
+func (diff *ValueDiff) changedPaths(prefix PropertyPath) []PropertyPath {
+	var paths []PropertyPath
+	switch {
+	case diff.Object != nil:
+		for _, p := range diff.Object.ChangedPaths() {
+			paths = append(paths, joinPaths(prefix, p...))
+		}
+	case diff.Array != nil:
+		for i := 0; i < diff.Array.Len(); i++ {
+			elem := joinPaths(prefix, i)
+			if update, has := diff.Array.Updates[i]; has {
+				if nested := update.changedPaths(elem); len(nested) > 0 {
+					paths = append(paths, nested...)
+					continue
+				}
+				paths = append(paths, elem)
+			} else if _, has := diff.Array.Adds[i]; has {
+				paths = append(paths, elem)
+			} else if _, has := diff.Array.Deletes[i]; has {
+				paths = append(paths, elem)
+			}
+		}
+	}
+	return paths
+}
+
+// joinPaths returns a new path consisting of prefix followed by elems, without aliasing prefix's storage.
+func joinPaths(prefix PropertyPath, elems ...interface{}) PropertyPath {
+	path := make(PropertyPath, 0, len(prefix)+len(elems))
+	path = append(path, prefix...)
+	return append(path, elems...)
+}
+
 // Keys returns a stable snapshot of all keys known to this object, across adds, deletes, sames, and updates.
 func (diff *ObjectDiff) Keys() []PropertyKey {
 	var ks []PropertyKey
@@ -118,6 +185,10 @@ func (diff *ArrayDiff) Len() int {
 
 // Diff returns a diffset by comparing the property map to another; it returns nil if there are no diffs.
 func (props PropertyMap) Diff(other PropertyMap) *ObjectDiff {
+	return props.diff(other, nil, nil)
+}
+
+func (props PropertyMap) diff(other PropertyMap, hashes *diffHashes, path PropertyPath) *ObjectDiff {
 	adds := make(PropertyMap)
 	deletes := make(PropertyMap)
 	sames := make(PropertyMap)
@@ -127,9 +198,10 @@ func (props PropertyMap) Diff(other PropertyMap) *ObjectDiff {
 	for k, old := range props {
 		if new, has := other[k]; has {
 			// If a new exists, use it; for output properties, however, ignore differences.
-			if new.IsOutput() {
+			elemPath := hashes.elem(path, string(k))
+			if new.IsOutput() || hashes.same(elemPath, old) {
 				sames[k] = old
-			} else if diff := old.Diff(new); diff != nil {
+			} else if diff := old.diff(new, hashes, elemPath); diff != nil {
 				if !old.HasValue() {
 					adds[k] = new
 				} else if !new.HasValue() {
@@ -167,6 +239,13 @@ func (props PropertyMap) Diff(other PropertyMap) *ObjectDiff {
 
 // Diff returns a diff by comparing a single property value to another; it returns nil if there are no diffs.
 func (v PropertyValue) Diff(other PropertyValue) *ValueDiff {
+	return v.diff(other, nil, nil)
+}
+
+func (v PropertyValue) diff(other PropertyValue, hashes *diffHashes, path PropertyPath) *ValueDiff {
+	if v.IsJSON() || other.IsJSON() {
+		return jsonDiff(v, other)
+	}
 	if v.IsArray() && other.IsArray() {
 		old := v.ArrayValue()
 		new := other.ArrayValue()
@@ -184,7 +263,10 @@ func (v PropertyValue) Diff(other PropertyValue) *ValueDiff {
 		sames := make(map[int]PropertyValue)
 		updates := make(map[int]ValueDiff)
 		for i := 0; i < len(old) && i < len(new); i++ {
-			if diff := old[i].Diff(new[i]); diff != nil {
+			elemPath := hashes.elem(path, i)
+			if hashes.same(elemPath, old[i]) {
+				sames[i] = old[i]
+			} else if diff := old[i].diff(new[i], hashes, elemPath); diff != nil {
 				updates[i] = *diff
 			} else {
 				sames[i] = old[i]
@@ -208,7 +290,7 @@ func (v PropertyValue) Diff(other PropertyValue) *ValueDiff {
 	if v.IsObject() && other.IsObject() {
 		old := v.ObjectValue()
 		new := other.ObjectValue()
-		if diff := old.Diff(new); diff != nil {
+		if diff := old.diff(new, hashes, path); diff != nil {
 			return &ValueDiff{
 				Old:    v,
 				New:    other,
@@ -251,6 +333,23 @@ func (props PropertyMap) DeepEquals(other PropertyMap) bool {
 
 // DeepEquals returns true if this property map is deeply equal to the other property map; and false otherwise.
 func (v PropertyValue) DeepEquals(other PropertyValue) bool {
+	// Secrets are equal if their underlying values are; whether a value is secret does not change the value itself.
+	if v.IsSecret() {
+		return v.SecretValue().Element.DeepEquals(other)
+	} else if other.IsSecret() {
+		return v.DeepEquals(other.SecretValue().Element)
+	}
+
+	// JSON values are equal to JSON values and strings that hold the same document.
+	if v.IsJSON() || other.IsJSON() {
+		return jsonEquals(v, other)
+	}
+
+	// File references are equal to file references and strings with the same hash.
+	if v.IsFileRef() || other.IsFileRef() {
+		return fileRefEquals(v, other)
+	}
+
 	// Arrays are equal if they are both of the same size and elements are deeply equal.
 	if v.IsArray() {
 		if !other.IsArray() {
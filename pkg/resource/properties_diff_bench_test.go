@@ -0,0 +1,106 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"testing"
+)
+
+// syntheticDiffMaps returns a pair of property maps with the given number of top-level keys, of which roughly one
+// in ten differ between the two maps, either by value or by presence.
+func syntheticDiffMaps(keys int) (PropertyMap, PropertyMap) {
+	olds, news := make(PropertyMap, keys), make(PropertyMap, keys)
+	for i := 0; i < keys; i++ {
+		k := PropertyKey(fmt.Sprintf("key%d", i))
+		var v PropertyValue
+		switch i % 3 {
+		case 0:
+			v = NewStringProperty(fmt.Sprintf("value-%d", i))
+		case 1:
+			v = NewObjectProperty(NewPropertyMapFromMap(map[string]interface{}{
+				"name": fmt.Sprintf("name-%d", i), "tags": []interface{}{"a", "b"},
+			}))
+		case 2:
+			v = NewArrayProperty([]PropertyValue{NewNumberProperty(float64(i)), NewBoolProperty(true)})
+		}
+
+		olds[k] = v
+		switch i % 10 {
+		case 0:
+			news[k] = NewStringProperty(fmt.Sprintf("changed-%d", i))
+		case 5:
+			// Deleted from the new map.
+		default:
+			news[k] = v.DeepCopy()
+		}
+	}
+	return olds, news
+}
+
+func benchmarkDiff(b *testing.B, keys int) {
+	olds, news := syntheticDiffMaps(keys)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if olds.Diff(news) == nil {
+			b.Fatal("expected a diff")
+		}
+	}
+}
+
+func BenchmarkDiff10(b *testing.B) {
+	benchmarkDiff(b, 10)
+}
+
+func BenchmarkDiff1K(b *testing.B) {
+	benchmarkDiff(b, 1000)
+}
+
+func BenchmarkDiff100K(b *testing.B) {
+	benchmarkDiff(b, 100000)
+}
+
+// benchmarkDiffWithHashes diffs maps whose unchanged values are large objects, as are the properties of resources
+// with very large property trees, using hashes computed ahead of time as a plan would find them in the snapshot.
+func benchmarkDiffWithHashes(b *testing.B, keys int) {
+	olds, news := syntheticDiffMaps(keys)
+	for k, v := range olds {
+		if v.IsObject() && news[k].IsObject() {
+			olds[k] = NewObjectProperty(NewPropertyMapFromMap(map[string]interface{}{
+				"name": string(k), "items": make([]interface{}, 256),
+			}))
+			news[k] = olds[k].DeepCopy()
+		}
+	}
+	oldHashes := olds.SubtreeHashes(0)
+	newHashes := news.SubtreeHashes(0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if olds.DiffWithHashes(news, oldHashes, newHashes) == nil {
+			b.Fatal("expected a diff")
+		}
+	}
+}
+
+func BenchmarkDiffWithHashes10(b *testing.B) {
+	benchmarkDiffWithHashes(b, 10)
+}
+
+func BenchmarkDiffWithHashes1K(b *testing.B) {
+	benchmarkDiffWithHashes(b, 1000)
+}
@@ -283,6 +283,43 @@ func TestObjectPropertyValueDiffs(t *testing.T) {
 	}
 }
 
+func TestObjectDiffChangedPaths(t *testing.T) {
+	t.Parallel()
+	old := PropertyMap{
+		"same":    NewStringProperty("s"),
+		"deleted": NewStringProperty("d"),
+		"updated": NewNumberProperty(1),
+		"nested": NewObjectProperty(PropertyMap{
+			"inner": NewStringProperty("a"),
+			"same":  NewStringProperty("s"),
+		}),
+		"list": NewArrayProperty([]PropertyValue{
+			NewStringProperty("a"),
+			NewObjectProperty(PropertyMap{"key": NewStringProperty("b")}),
+		}),
+	}
+	new := PropertyMap{
+		"same":    NewStringProperty("s"),
+		"added":   NewBoolProperty(true),
+		"updated": NewNumberProperty(2),
+		"nested": NewObjectProperty(PropertyMap{
+			"inner": NewStringProperty("b"),
+			"same":  NewStringProperty("s"),
+		}),
+		"list": NewArrayProperty([]PropertyValue{
+			NewStringProperty("a"),
+			NewObjectProperty(PropertyMap{"key": NewStringProperty("c")}),
+			NewStringProperty("d"),
+		}),
+	}
+
+	var paths []string
+	for _, p := range old.Diff(new).ChangedPaths() {
+		paths = append(paths, p.String())
+	}
+	assert.Equal(t, []string{"added", "deleted", "list[1].key", "list[2]", "nested.inner", "updated"}, paths)
+}
+
 func TestAssetPropertyValueDiffs(t *testing.T) {
 	t.Parallel()
 	a1, err := NewTextAsset("test")
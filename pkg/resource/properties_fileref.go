@@ -0,0 +1,172 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// FileRefSig is the unique file reference signature.
+const FileRefSig = "82e9bc30b221968556dd59ad0f1f12f8"
+
+const (
+	FileRefPathProperty = "path" // the dynamic property for a file reference's path.
+	FileRefHashProperty = "hash" // the dynamic property for a file reference's SHA-256 hash.
+)
+
+// FileRef is a string property value whose contents are held in a file rather than in memory, such as a rendered
+// template or a certificate.  A reference is identified by the SHA-256 hash of its contents: references with the same
+// hash are equal, wherever their files are, and a reference is equal to a string with the same hash.  The file is read
+// only when the value is sent to a provider or fingerprinted, so neither programs nor snapshots need to hold the
+// contents.
+type FileRef struct {
+	Path string // the path to the file that holds the value.
+	Hash string // the hex-encoded SHA-256 hash of the file's contents.
+}
+
+// NewFileRefProperty returns a property value that refers to the string held in the file at the given path, whose
+// contents have the given hex-encoded SHA-256 hash.
+func NewFileRefProperty(path, sha256 string) PropertyValue {
+	return PropertyValue{FileRef{Path: path, Hash: sha256}}
+}
+
+// Read reads the value that the reference refers to, and checks that it has the expected hash.
+func (r FileRef) Read() (string, error) {
+	b, err := ioutil.ReadFile(r.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading file reference %s", r.Path)
+	}
+	if hash := hashString(string(b)); hash != r.Hash {
+		return "", errors.Errorf("file %s has hash %s, but the reference expects %s", r.Path, hash, r.Hash)
+	}
+	return string(b), nil
+}
+
+// Matches returns true if the given string is the value that the reference refers to.
+func (r FileRef) Matches(s string) bool {
+	return hashString(s) == r.Hash
+}
+
+// Serialize returns a weakly typed map that contains the right signature for serialization purposes.
+func (r FileRef) Serialize() map[string]interface{} {
+	return map[string]interface{}{
+		SigKey:              FileRefSig,
+		FileRefPathProperty: r.Path,
+		FileRefHashProperty: r.Hash,
+	}
+}
+
+// DeserializeFileRef checks to see if the map contains a file reference, using its signature, and if so deserializes
+// it.
+func DeserializeFileRef(obj map[string]interface{}) (FileRef, bool, error) {
+	if obj[SigKey] != FileRefSig {
+		return FileRef{}, false, nil
+	}
+	path, ok := obj[FileRefPathProperty].(string)
+	if !ok || path == "" {
+		return FileRef{}, false, errors.New("file reference is missing its path")
+	}
+	hash, ok := obj[FileRefHashProperty].(string)
+	if !ok || hash == "" {
+		return FileRef{}, false, errors.New("file reference is missing its hash")
+	}
+	return FileRef{Path: path, Hash: hash}, true, nil
+}
+
+// RestoreFileRefs returns a copy of the values in which each string that is the value of the file reference at the
+// same path in refs is replaced by that reference.  Providers see the contents of references, so the values that they
+// return, such as checked inputs and outputs that echo them, hold the contents; this keeps those values small.
+func RestoreFileRefs(refs, values PropertyMap) PropertyMap {
+	if values == nil || !refs.ContainsFileRefs() {
+		return values
+	}
+	result := make(PropertyMap, len(values))
+	for k, v := range values {
+		result[k] = restoreFileRefs(refs[k], v)
+	}
+	return result
+}
+
+func restoreFileRefs(ref, v PropertyValue) PropertyValue {
+	switch {
+	case ref.IsFileRef():
+		if v.IsString() && ref.FileRefValue().Matches(v.StringValue()) {
+			return ref
+		}
+	case ref.IsArray() && v.IsArray():
+		refs, values := ref.ArrayValue(), v.ArrayValue()
+		arr := make([]PropertyValue, len(values))
+		for i, elem := range values {
+			if i < len(refs) {
+				elem = restoreFileRefs(refs[i], elem)
+			}
+			arr[i] = elem
+		}
+		return NewArrayProperty(arr)
+	case ref.IsObject() && v.IsObject():
+		return NewObjectProperty(RestoreFileRefs(ref.ObjectValue(), v.ObjectValue()))
+	}
+	return v
+}
+
+// ContainsFileRefs returns true if the map contains any file references.
+func (m PropertyMap) ContainsFileRefs() bool {
+	for _, v := range m {
+		if v.ContainsFileRefs() {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsFileRefs returns true if the value is or contains a file reference.
+func (v PropertyValue) ContainsFileRefs() bool {
+	switch {
+	case v.IsFileRef():
+		return true
+	case v.IsArray():
+		for _, elem := range v.ArrayValue() {
+			if elem.ContainsFileRefs() {
+				return true
+			}
+		}
+	case v.IsObject():
+		return v.ObjectValue().ContainsFileRefs()
+	}
+	return false
+}
+
+// fileRefEquals returns true if v and other are file references or strings with the same hash, one of them being a
+// file reference.
+func fileRefEquals(v, other PropertyValue) bool {
+	switch {
+	case v.IsFileRef() && other.IsFileRef():
+		return v.FileRefValue().Hash == other.FileRefValue().Hash
+	case v.IsFileRef() && other.IsString():
+		return v.FileRefValue().Matches(other.StringValue())
+	case v.IsString() && other.IsFileRef():
+		return other.FileRefValue().Matches(v.StringValue())
+	}
+	return false
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func TestFileRef(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "fileref")
+	assert.NoError(t, err)
+	defer func() { contract.IgnoreError(os.RemoveAll(dir)) }()
+
+	const contents = "-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"
+	path := filepath.Join(dir, "cert.pem")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	hash := hashString(contents)
+
+	ref := NewFileRefProperty(path, hash)
+	assert.True(t, ref.IsFileRef())
+	s, err := ref.FileRefValue().Read()
+	assert.NoError(t, err)
+	assert.Equal(t, contents, s)
+
+	// A file whose contents do not match the hash is rejected.
+	_, err = FileRef{Path: path, Hash: hashString("other")}.Read()
+	assert.Error(t, err)
+
+	// References are compared by hash, with each other and with strings.
+	assert.True(t, ref.DeepEquals(NewFileRefProperty("elsewhere.pem", hash)))
+	assert.True(t, ref.DeepEquals(NewStringProperty(contents)))
+	assert.True(t, NewStringProperty(contents).DeepEquals(ref))
+	assert.False(t, ref.DeepEquals(NewStringProperty("other")))
+	assert.Nil(t, PropertyMap{"cert": ref}.Diff(PropertyMap{"cert": NewStringProperty(contents)}))
+
+	// References fingerprint like the strings that they are equal to, including within subtrees.
+	assert.Equal(t, PropertyMap{"cert": NewStringProperty(contents)}.Fingerprint(), PropertyMap{"cert": ref}.Fingerprint())
+	assert.Equal(t,
+		PropertyMap{"tls": NewObjectProperty(PropertyMap{"cert": NewStringProperty(contents)})}.SubtreeHashes(0),
+		PropertyMap{"tls": NewObjectProperty(PropertyMap{"cert": ref})}.SubtreeHashes(0))
+	missing := PropertyMap{"cert": NewFileRefProperty(filepath.Join(dir, "missing.pem"), hash)}
+	assert.NotEqual(t, PropertyMap{"cert": NewStringProperty(contents)}.Fingerprint(), missing.Fingerprint())
+
+	// Values that a provider returns with the contents of references get the references back.
+	refs := PropertyMap{
+		"cert":  ref,
+		"chain": NewArrayProperty([]PropertyValue{ref}),
+		"tls":   NewObjectProperty(PropertyMap{"cert": ref}),
+	}
+	values := PropertyMap{
+		"cert":  NewStringProperty(contents),
+		"chain": NewArrayProperty([]PropertyValue{NewStringProperty("other")}),
+		"tls":   NewObjectProperty(PropertyMap{"cert": NewStringProperty(contents)}),
+		"id":    NewStringProperty("cert-1"),
+	}
+	assert.Equal(t, PropertyMap{
+		"cert":  ref,
+		"chain": NewArrayProperty([]PropertyValue{NewStringProperty("other")}),
+		"tls":   NewObjectProperty(PropertyMap{"cert": ref}),
+		"id":    NewStringProperty("cert-1"),
+	}, RestoreFileRefs(refs, values))
+}
@@ -0,0 +1,124 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FormatSig is the unique format signature.
+const FormatSig = "7d2d6bc3f0a1e06e4ea1f1a5e2b27d3c"
+
+// Format is a string property value that is built by interpolating other property values into a template.  Formats
+// are evaluated by the engine rather than by programs, so that values that are not yet known, such as the outputs of
+// resources that have not been created, yield an unknown string instead of a string that holds a placeholder.
+//
+// Each `%s` in the template is replaced by the next argument, and each `%%` by a single `%`.  Strings are inserted
+// as-is; numbers, bools, and JSON values by their text; formats by their result; and arrays and objects by their JSON
+// encoding.
+type Format struct {
+	Template string          // the template into which the arguments are interpolated.
+	Args     []PropertyValue // the values to interpolate.
+}
+
+// Evaluate interpolates the format's arguments into its template.  If any argument contains an unknown value, the
+// result is an unknown string.
+func (f Format) Evaluate() (PropertyValue, error) {
+	args := make([]string, len(f.Args))
+	unknown := false
+	for i, arg := range f.Args {
+		arg, err := arg.Resolve()
+		if err != nil {
+			return PropertyValue{}, err
+		}
+		if arg.ContainsUnknowns() {
+			// Keep checking the remaining arguments so that malformed formats are always reported.
+			unknown = true
+			continue
+		}
+		s, err := formatArg(arg)
+		if err != nil {
+			return PropertyValue{}, errors.Wrapf(err, "format argument %d", i)
+		}
+		args[i] = s
+	}
+
+	var buf bytes.Buffer
+	next := 0
+	for rest := f.Template; rest != ""; {
+		i := strings.IndexByte(rest, '%')
+		if i == -1 || i == len(rest)-1 {
+			if i != -1 {
+				return PropertyValue{}, errors.Errorf("format %q ends with a lone '%%'", f.Template)
+			}
+			buf.WriteString(rest)
+			break
+		}
+		buf.WriteString(rest[:i])
+		switch rest[i+1] {
+		case '%':
+			buf.WriteByte('%')
+		case 's':
+			if next == len(args) {
+				return PropertyValue{}, errors.Errorf("format %q has more placeholders than its %d arguments",
+					f.Template, len(args))
+			}
+			buf.WriteString(args[next])
+			next++
+		default:
+			return PropertyValue{}, errors.Errorf("format %q contains unsupported verb '%%%c'", f.Template,
+				rest[i+1])
+		}
+		rest = rest[i+2:]
+	}
+	if next != len(args) {
+		return PropertyValue{}, errors.Errorf("format %q has fewer placeholders than its %d arguments",
+			f.Template, len(args))
+	}
+
+	if unknown {
+		return MakeComputed(NewStringProperty("")), nil
+	}
+	return NewStringProperty(buf.String()), nil
+}
+
+// formatArg returns the text that represents a known argument to a format.
+func formatArg(v PropertyValue) (string, error) {
+	switch {
+	case v.IsNull():
+		return "", nil
+	case v.IsString():
+		return v.StringValue(), nil
+	case v.IsNumber():
+		return strconv.FormatFloat(v.NumberValue(), 'f', -1, 64), nil
+	case v.IsBool():
+		return strconv.FormatBool(v.BoolValue()), nil
+	case v.IsJSON():
+		return v.JSONValue().Raw, nil
+	case v.IsArray() || v.IsObject():
+		b, err := json.Marshal(v.Mappable())
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", errors.Errorf("values of type %s cannot be formatted", v.TypeString())
+	}
+}
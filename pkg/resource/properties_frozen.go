@@ -0,0 +1,79 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// FrozenPropertyMap is a read-only view of a property map.  The view owns a private deep copy of the map it was created
+// from, so later changes to the original are not visible through the view, and values read from the view are copied
+// out so that changes to them are not visible through the view either.  Attempts to mutate the view itself panic.
+//
+// Frozen maps allow property maps to be handed to in-process providers and event sinks without risking accidental
+// aliasing between the planner's state and the recipient's.
+type FrozenPropertyMap struct {
+	m PropertyMap
+}
+
+// Freeze returns a read-only view of the map.
+func (m PropertyMap) Freeze() FrozenPropertyMap {
+	return FrozenPropertyMap{m: m.DeepCopy()}
+}
+
+// Len returns the number of properties in the map.
+func (f FrozenPropertyMap) Len() int {
+	return len(f.m)
+}
+
+// Has returns true if the map contains the given key.
+func (f FrozenPropertyMap) Has(k PropertyKey) bool {
+	_, has := f.m[k]
+	return has
+}
+
+// Get returns a copy of the value for the given key, if any.
+func (f FrozenPropertyMap) Get(k PropertyKey) (PropertyValue, bool) {
+	v, has := f.m[k]
+	if !has {
+		return PropertyValue{}, false
+	}
+	return v.DeepCopy(), true
+}
+
+// StableKeys returns all of the map's keys in a stable order.
+func (f FrozenPropertyMap) StableKeys() []PropertyKey {
+	return f.m.StableKeys()
+}
+
+// Mappable returns a mapper-compatible object map, suitable for deserialization into structures.
+func (f FrozenPropertyMap) Mappable() map[string]interface{} {
+	return f.m.Mappable()
+}
+
+// Thaw returns a mutable deep copy of the map.
+func (f FrozenPropertyMap) Thaw() PropertyMap {
+	return f.m.DeepCopy()
+}
+
+// Set panics: a frozen map may not be mutated.  Thaw the map and mutate the result instead.
+func (f FrozenPropertyMap) Set(k PropertyKey, v PropertyValue) {
+	contract.Failf("cannot set property '%v' of a frozen property map", k)
+}
+
+// Delete panics: a frozen map may not be mutated.  Thaw the map and mutate the result instead.
+func (f FrozenPropertyMap) Delete(k PropertyKey) {
+	contract.Failf("cannot delete property '%v' of a frozen property map", k)
+}
@@ -0,0 +1,173 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+// PropertyGetter is a chainable accessor for nested property values.  Unlike the typed getters of PropertyMap, it
+// never panics: a missing key, an index out of range, or a value of the wrong type simply leaves the getter empty, and
+// every getter that follows from an empty one is empty too.  Chains end in one of the `As` methods, which return the
+// value if it has the asked-for type, or a default otherwise:
+//
+//	size := props.Get("disk").Get("size").AsNumberOr(8)
+//
+// Null and unknown values are treated as missing.
+type PropertyGetter struct {
+	v  PropertyValue
+	ok bool
+}
+
+// Get returns a getter for the value of the given key.
+func (m PropertyMap) Get(k PropertyKey) PropertyGetter {
+	return NewObjectProperty(m).Get(k)
+}
+
+// Get returns a getter for the value of the given key of this value, which is empty if this value is not an object.
+func (v PropertyValue) Get(k PropertyKey) PropertyGetter {
+	return PropertyGetter{v: v, ok: true}.Get(k)
+}
+
+// Get returns a getter for the value of the given key of the current value, which is empty if the current value is not
+// an object or does not contain the key.
+func (g PropertyGetter) Get(k PropertyKey) PropertyGetter {
+	if !g.ok || !g.v.IsObject() {
+		return PropertyGetter{}
+	}
+	return newPropertyGetter(g.v.ObjectValue()[k])
+}
+
+// Index returns a getter for the given element of the current value, which is empty if the current value is not an
+// array or the index is out of range.
+func (g PropertyGetter) Index(i int) PropertyGetter {
+	if !g.ok || !g.v.IsArray() {
+		return PropertyGetter{}
+	}
+	arr := g.v.ArrayValue()
+	if i < 0 || i >= len(arr) {
+		return PropertyGetter{}
+	}
+	return newPropertyGetter(arr[i])
+}
+
+// Path returns a getter for the value at the given path of the current value.
+func (g PropertyGetter) Path(path PropertyPath) PropertyGetter {
+	for _, elem := range path {
+		switch e := elem.(type) {
+		case string:
+			g = g.Get(PropertyKey(e))
+		case int:
+			g = g.Index(e)
+		default:
+			return PropertyGetter{}
+		}
+	}
+	return g
+}
+
+// Exists returns true if the current value is present and is neither null nor unknown.
+func (g PropertyGetter) Exists() bool {
+	return g.ok
+}
+
+// Value returns the current value and true, or false if the getter is empty.
+func (g PropertyGetter) Value() (PropertyValue, bool) {
+	return g.v, g.ok
+}
+
+// AsBool returns the current value and true if it is a bool.
+func (g PropertyGetter) AsBool() (bool, bool) {
+	if !g.ok || !g.v.IsBool() {
+		return false, false
+	}
+	return g.v.BoolValue(), true
+}
+
+// AsBoolOr returns the current value if it is a bool, and def otherwise.
+func (g PropertyGetter) AsBoolOr(def bool) bool {
+	if v, ok := g.AsBool(); ok {
+		return v
+	}
+	return def
+}
+
+// AsNumber returns the current value and true if it is a number.
+func (g PropertyGetter) AsNumber() (float64, bool) {
+	if !g.ok || !g.v.IsNumber() {
+		return 0, false
+	}
+	return g.v.NumberValue(), true
+}
+
+// AsNumberOr returns the current value if it is a number, and def otherwise.
+func (g PropertyGetter) AsNumberOr(def float64) float64 {
+	if v, ok := g.AsNumber(); ok {
+		return v
+	}
+	return def
+}
+
+// AsString returns the current value and true if it is a string.
+func (g PropertyGetter) AsString() (string, bool) {
+	if !g.ok || !g.v.IsString() {
+		return "", false
+	}
+	return g.v.StringValue(), true
+}
+
+// AsStringOr returns the current value if it is a string, and def otherwise.
+func (g PropertyGetter) AsStringOr(def string) string {
+	if v, ok := g.AsString(); ok {
+		return v
+	}
+	return def
+}
+
+// AsArray returns the current value and true if it is an array.
+func (g PropertyGetter) AsArray() ([]PropertyValue, bool) {
+	if !g.ok || !g.v.IsArray() {
+		return nil, false
+	}
+	return g.v.ArrayValue(), true
+}
+
+// AsArrayOr returns the current value if it is an array, and def otherwise.
+func (g PropertyGetter) AsArrayOr(def []PropertyValue) []PropertyValue {
+	if v, ok := g.AsArray(); ok {
+		return v
+	}
+	return def
+}
+
+// AsObject returns the current value and true if it is an object.
+func (g PropertyGetter) AsObject() (PropertyMap, bool) {
+	if !g.ok || !g.v.IsObject() {
+		return nil, false
+	}
+	return g.v.ObjectValue(), true
+}
+
+// AsObjectOr returns the current value if it is an object, and def otherwise.
+func (g PropertyGetter) AsObjectOr(def PropertyMap) PropertyMap {
+	if v, ok := g.AsObject(); ok {
+		return v
+	}
+	return def
+}
+
+// newPropertyGetter returns a getter for the given value, which is empty if the value is null or unknown.
+func newPropertyGetter(v PropertyValue) PropertyGetter {
+	if v.IsNull() || v.IsComputed() || v.IsOutput() {
+		return PropertyGetter{}
+	}
+	return PropertyGetter{v: v, ok: true}
+}
@@ -0,0 +1,60 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyGetter(t *testing.T) {
+	t.Parallel()
+
+	props := NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"disk": map[string]interface{}{
+			"size":      100,
+			"encrypted": true,
+			"tags":      []interface{}{"a", "b"},
+		},
+		"zone": nil,
+	})
+	props["address"] = MakeComputed(NewStringProperty(""))
+
+	assert.Equal(t, "web", props.Get("name").AsStringOr("default"))
+	assert.Equal(t, float64(100), props.Get("disk").Get("size").AsNumberOr(8))
+	assert.True(t, props.Get("disk").Get("encrypted").AsBoolOr(false))
+	assert.Equal(t, "b", props.Get("disk").Get("tags").Index(1).AsStringOr(""))
+	assert.Equal(t, "a", props.Get("disk").Path(PropertyPath{"tags", 0}).AsStringOr(""))
+
+	// Missing keys, out-of-range indices, and values of the wrong type fall back to the default.
+	assert.Equal(t, float64(8), props.Get("volume").Get("size").AsNumberOr(8))
+	assert.Equal(t, "none", props.Get("disk").Get("tags").Index(2).AsStringOr("none"))
+	assert.Equal(t, "none", props.Get("name").Get("first").AsStringOr("none"))
+	assert.Equal(t, float64(8), props.Get("name").AsNumberOr(8))
+	assert.True(t, props.Get("disk").Get("size").Exists())
+
+	// Null and unknown values are treated as missing.
+	assert.False(t, props.Get("zone").Exists())
+	assert.Equal(t, "us-west-2a", props.Get("zone").AsStringOr("us-west-2a"))
+	assert.False(t, props.Get("address").Exists())
+	_, ok := props.Get("address").AsString()
+	assert.False(t, ok)
+
+	// Getters on nil maps are empty.
+	var empty PropertyMap
+	assert.Nil(t, empty.Get("disk").AsObjectOr(nil))
+}
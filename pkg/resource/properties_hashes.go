@@ -0,0 +1,137 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// PropertyHashes maps the paths of the objects and arrays in a property map to the hex-encoded fingerprints of their
+// canonical encodings.  The empty path names the map itself.  A diff that is given the hashes of both of the maps it
+// compares skips the values at paths whose fingerprints are the same, rather than comparing them structurally.
+//
+// Hashes describe the map that they were computed from, and nothing else; anything that changes the map must discard
+// them or compute them anew.
+type PropertyHashes map[string]string
+
+// DefaultSubtreeHashThreshold is the size, in bytes of canonical encoding, of the smallest objects and arrays whose
+// fingerprints are recorded by SubtreeHashes.  Smaller values are as cheap to compare as their fingerprints are.
+const DefaultSubtreeHashThreshold = 1024
+
+// SubtreeHashes returns the fingerprints of the map and of each object and array within it whose canonical encoding
+// is at least threshold bytes long.  The map's own fingerprint, which is always recorded, is its Fingerprint.  The
+// map is encoded only once, however many fingerprints are recorded.
+func (m PropertyMap) SubtreeHashes(threshold int) PropertyHashes {
+	h := &subtreeHasher{threshold: threshold, hashes: make(PropertyHashes)}
+	h.writeMap(nil, m)
+	return h.hashes
+}
+
+// Root returns the fingerprint of the map that the hashes were computed from, or "" if it is not known.
+func (h PropertyHashes) Root() string {
+	return h[""]
+}
+
+// DiffWithHashes is like Diff, but skips the values at paths that have the same fingerprints in oldHashes, which
+// describe this map, and newHashes, which describe the other.  Values at other paths are compared structurally, so
+// either set of hashes may be nil.
+func (props PropertyMap) DiffWithHashes(other PropertyMap, oldHashes, newHashes PropertyHashes) *ObjectDiff {
+	hashes := &diffHashes{olds: oldHashes, news: newHashes}
+	if hashes.same(nil, NewObjectProperty(props)) {
+		return nil
+	}
+	return props.diff(other, hashes, nil)
+}
+
+// diffHashes holds the hashes of the two maps that a diff compares.  A nil *diffHashes is a diff without hashes, which
+// tracks no paths.
+type diffHashes struct {
+	olds PropertyHashes
+	news PropertyHashes
+}
+
+// elem returns the path of an element of the value at the given path, or nil if the diff has no hashes.
+func (h *diffHashes) elem(path PropertyPath, elem interface{}) PropertyPath {
+	if h == nil {
+		return nil
+	}
+	return append(path[:len(path):len(path)], elem)
+}
+
+// same returns true if the old value at the given path has the same fingerprint as the new value.
+func (h *diffHashes) same(path PropertyPath, old PropertyValue) bool {
+	if h == nil || len(h.olds) == 0 || len(h.news) == 0 || (!old.IsObject() && !old.IsArray()) {
+		return false
+	}
+	key := path.String()
+	oldHash, has := h.olds[key]
+	return has && oldHash == h.news[key]
+}
+
+// subtreeHasher writes the canonical encoding of a property map, fingerprinting the objects and arrays within it as
+// it goes.  The bytes that it writes for each value are those that writeCanonicalValue would write.
+type subtreeHasher struct {
+	buf       bytes.Buffer
+	threshold int
+	hashes    PropertyHashes
+}
+
+func (h *subtreeHasher) writeMap(path PropertyPath, m PropertyMap) {
+	start := h.buf.Len()
+
+	var keys []PropertyKey
+	for _, k := range m.StableKeys() {
+		if m[k].HasValue() {
+			keys = append(keys, k)
+		}
+	}
+
+	h.buf.WriteByte(canonicalObject)
+	writeCanonicalLength(&h.buf, len(keys))
+	for _, k := range keys {
+		writeCanonicalString(&h.buf, string(k))
+		h.writeValue(append(path[:len(path):len(path)], string(k)), m[k])
+	}
+	h.record(path, start)
+}
+
+func (h *subtreeHasher) writeValue(path PropertyPath, v PropertyValue) {
+	switch {
+	case v.IsObject():
+		h.writeMap(path, v.ObjectValue())
+	case v.IsArray():
+		start, arr := h.buf.Len(), v.ArrayValue()
+		h.buf.WriteByte(canonicalArray)
+		writeCanonicalLength(&h.buf, len(arr))
+		for i, e := range arr {
+			h.writeValue(append(path[:len(path):len(path)], i), e)
+		}
+		h.record(path, start)
+	default:
+		writeCanonicalValue(&h.buf, v)
+	}
+}
+
+// record fingerprints the value at the given path, whose encoding began at the given offset, if it is the map itself
+// or if its encoding is long enough.
+func (h *subtreeHasher) record(path PropertyPath, start int) {
+	if len(path) > 0 && h.buf.Len()-start < h.threshold {
+		return
+	}
+	sum := sha256.Sum256(h.buf.Bytes()[start:])
+	h.hashes[path.String()] = hex.EncodeToString(sum[:])
+}
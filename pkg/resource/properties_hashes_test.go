@@ -0,0 +1,77 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hashedTestProps(policy string) PropertyMap {
+	return NewPropertyMapFromMap(map[string]interface{}{
+		"name": "a",
+		"policy": map[string]interface{}{
+			"document": policy,
+			"tags":     []interface{}{"x", "y"},
+		},
+		"rules": []interface{}{
+			map[string]interface{}{"body": strings.Repeat("r", 2048)},
+			map[string]interface{}{"body": "small"},
+		},
+	})
+}
+
+func TestSubtreeHashes(t *testing.T) {
+	props := hashedTestProps(strings.Repeat("p", 2048))
+	hashes := props.SubtreeHashes(DefaultSubtreeHashThreshold)
+
+	// The map's own fingerprint is always recorded, as are those of objects and arrays that are large enough.
+	assert.Equal(t, props.Fingerprint().String(), hashes.Root())
+	assert.Equal(t, props["policy"].ObjectValue().Fingerprint().String(), hashes["policy"])
+	assert.Contains(t, hashes, "rules")
+	assert.Contains(t, hashes, "rules[0]")
+	assert.NotContains(t, hashes, "rules[1]")
+	assert.NotContains(t, hashes, "policy.tags")
+	assert.Len(t, hashes, 4)
+
+	// Equal maps have equal hashes, however they were constructed.
+	assert.Equal(t, hashes, props.DeepCopy().SubtreeHashes(DefaultSubtreeHashThreshold))
+
+	// A threshold of zero records every object and array.
+	assert.Len(t, props.SubtreeHashes(0), 6)
+}
+
+func TestDiffWithHashes(t *testing.T) {
+	olds := hashedTestProps(strings.Repeat("p", 2048))
+	news := hashedTestProps(strings.Repeat("q", 2048))
+	news["name"] = NewStringProperty("b")
+	oldHashes := olds.SubtreeHashes(DefaultSubtreeHashThreshold)
+	newHashes := news.SubtreeHashes(DefaultSubtreeHashThreshold)
+
+	// With accurate hashes, or without hashes, the diff is the same as a structural diff.
+	expected := olds.Diff(news)
+	assert.Equal(t, expected, olds.DiffWithHashes(news, oldHashes, newHashes))
+	assert.Equal(t, expected, olds.DiffWithHashes(news, nil, newHashes))
+	assert.Equal(t, []PropertyKey{"name", "policy"}, expected.ChangedKeys())
+
+	// Equal maps do not differ, and are recognized by their fingerprints alone.
+	assert.Nil(t, olds.DiffWithHashes(olds.DeepCopy(), oldHashes, oldHashes))
+
+	// Values whose fingerprints are the same are not compared at all.
+	newHashes["policy"] = oldHashes["policy"]
+	assert.Equal(t, []PropertyKey{"name"}, olds.DiffWithHashes(news, oldHashes, newHashes).ChangedKeys())
+}
@@ -0,0 +1,139 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"sync"
+)
+
+// MaxInternedStringLength is the length above which strings are not interned.  Long strings (inline code, policy
+// documents, and so on) are rarely duplicated, and keeping them alive for the lifetime of an interner would cost more
+// memory than it saves.
+const MaxInternedStringLength = 256
+
+// The common-value pool: property values whose boxed representations are allocated once and shared by every caller.
+// Storing a bool, number, or string in a PropertyValue boxes it in an interface, which usually allocates; handing out
+// pre-boxed values for the most common cases avoids those allocations entirely.
+var (
+	nullPropertyValue        = NewNullProperty()
+	truePropertyValue        = NewBoolProperty(true)
+	falsePropertyValue       = NewBoolProperty(false)
+	emptyStringPropertyValue = NewStringProperty("")
+	smallNumberValues        = makeSmallNumberValues(smallNumberMin, 1024)
+)
+
+// smallNumberMin is the smallest integer in the common-value pool.
+const smallNumberMin = -1
+
+func makeSmallNumberValues(min, max int) []PropertyValue {
+	values := make([]PropertyValue, max-min+1)
+	for i := range values {
+		values[i] = NewNumberProperty(float64(min + i))
+	}
+	return values
+}
+
+// Interner deduplicates the strings and property values produced while deserializing large numbers of resources. A
+// nil *Interner is valid, and only draws on the common-value pool.  An Interner is safe for concurrent use.
+type Interner struct {
+	lock    sync.RWMutex
+	strings map[string]string        // interned raw strings (keys, URNs, types, and so on).
+	values  map[string]PropertyValue // interned, pre-boxed string property values.
+}
+
+// NewInterner creates a new, empty interner.
+func NewInterner() *Interner {
+	return &Interner{
+		strings: make(map[string]string),
+		values:  make(map[string]PropertyValue),
+	}
+}
+
+// Intern returns a canonical copy of the given string.
+func (in *Interner) Intern(s string) string {
+	if in == nil || len(s) > MaxInternedStringLength {
+		return s
+	}
+
+	in.lock.RLock()
+	is, ok := in.strings[s]
+	in.lock.RUnlock()
+	if ok {
+		return is
+	}
+
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if is, ok = in.strings[s]; !ok {
+		in.strings[s], is = s, s
+	}
+	return is
+}
+
+// Key returns a canonical copy of the given property key.
+func (in *Interner) Key(k string) PropertyKey {
+	return PropertyKey(in.Intern(k))
+}
+
+// Null returns the shared null property value.
+func (in *Interner) Null() PropertyValue {
+	return nullPropertyValue
+}
+
+// Bool returns the shared property value for the given bool.
+func (in *Interner) Bool(b bool) PropertyValue {
+	if b {
+		return truePropertyValue
+	}
+	return falsePropertyValue
+}
+
+// Number returns a property value for the given number, drawing on the common-value pool for small integers.
+func (in *Interner) Number(n float64) PropertyValue {
+	if i := int(n); float64(i) == n && i >= smallNumberMin && i-smallNumberMin < len(smallNumberValues) {
+		return smallNumberValues[i-smallNumberMin]
+	}
+	return NewNumberProperty(n)
+}
+
+// String returns a property value for the given string. If the interner is non-nil and the string is short, the
+// returned value shares both its string data and its boxed representation with every other value for that string.
+func (in *Interner) String(s string) PropertyValue {
+	if s == "" {
+		return emptyStringPropertyValue
+	}
+	if in == nil || len(s) > MaxInternedStringLength {
+		return NewStringProperty(s)
+	}
+
+	in.lock.RLock()
+	v, ok := in.values[s]
+	in.lock.RUnlock()
+	if ok {
+		return v
+	}
+
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if v, ok = in.values[s]; !ok {
+		is, has := in.strings[s]
+		if !has {
+			in.strings[s], is = s, s
+		}
+		v = NewStringProperty(is)
+		in.values[s] = v
+	}
+	return v
+}
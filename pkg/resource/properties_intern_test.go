@@ -0,0 +1,74 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stringData returns a pointer to the bytes backing the given string.
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestInterner(t *testing.T) {
+	in := NewInterner()
+
+	// Strings that are equal but separately allocated are interned to a single copy.
+	a, b := string([]byte("us-west-2")), string([]byte("us-west-2"))
+	assert.NotEqual(t, stringData(a), stringData(b))
+	assert.Equal(t, stringData(in.Intern(a)), stringData(in.Intern(b)))
+	assert.Equal(t, stringData(in.Intern(a)), stringData(in.String(b).StringValue()))
+	assert.Equal(t, PropertyKey("us-west-2"), in.Key(b))
+
+	// Long strings are left alone.
+	long := strings.Repeat("x", MaxInternedStringLength+1)
+	long2 := string([]byte(long))
+	assert.Equal(t, stringData(long2), stringData(in.Intern(long2)))
+	assert.Equal(t, NewStringProperty(long), in.String(long2))
+
+	// Values from the common-value pool are equal to their freshly-constructed counterparts.
+	assert.Equal(t, NewNullProperty(), in.Null())
+	assert.Equal(t, NewBoolProperty(true), in.Bool(true))
+	assert.Equal(t, NewBoolProperty(false), in.Bool(false))
+	assert.Equal(t, NewStringProperty(""), in.String(""))
+	for _, n := range []float64{-2, -1, 0, 1, 42, 1024, 1025, 1.5, 1e300} {
+		assert.Equal(t, NewNumberProperty(n), in.Number(n))
+	}
+
+	// A nil interner still produces the right values.
+	var nilInterner *Interner
+	assert.Equal(t, "us-west-2", nilInterner.Intern(a))
+	assert.Equal(t, NewStringProperty("us-west-2"), nilInterner.String(a))
+	assert.Equal(t, NewNumberProperty(8), nilInterner.Number(8))
+}
+
+func TestInternerAllocations(t *testing.T) {
+	in := NewInterner()
+	s := string([]byte("t2.micro"))
+	in.String(s)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = in.String(s)
+		_ = in.Number(80)
+		_ = in.Bool(true)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
@@ -0,0 +1,106 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"encoding/json"
+)
+
+// Document parses the JSON document held by this value into a property value.  Objects become property maps, arrays
+// become property arrays, and all numbers become floating point numbers.
+func (j JSON) Document() (PropertyValue, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(j.Raw), &doc); err != nil {
+		return PropertyValue{}, err
+	}
+	return NewPropertyValue(doc), nil
+}
+
+// MarkJSON returns a copy of the map in which the string values at each of the given paths are replaced with JSON
+// values holding the same text, so that they are compared as JSON documents.  Values that are not strings are left
+// alone.  If no values are replaced, the map itself is returned.
+func (m PropertyMap) MarkJSON(paths []PropertyPath) PropertyMap {
+	result, copied := m, false
+	for _, path := range paths {
+		v, has := path.Get(result)
+		if !has || !v.IsString() {
+			continue
+		}
+		if !copied {
+			result, copied = m.DeepCopy(), true
+		}
+		path.Set(result, NewJSONProperty(v.StringValue()))
+	}
+	return result
+}
+
+// jsonDocument returns the document held by a JSON value or by a string that holds a valid JSON document.
+func jsonDocument(v PropertyValue) (PropertyValue, bool) {
+	var j JSON
+	switch {
+	case v.IsJSON():
+		j = v.JSONValue()
+	case v.IsString():
+		j = JSON{Raw: v.StringValue()}
+	default:
+		return PropertyValue{}, false
+	}
+	doc, err := j.Document()
+	if err != nil {
+		return PropertyValue{}, false
+	}
+	return doc, true
+}
+
+// jsonText returns the text of a JSON value or a string.
+func jsonText(v PropertyValue) (string, bool) {
+	switch {
+	case v.IsJSON():
+		return v.JSONValue().Raw, true
+	case v.IsString():
+		return v.StringValue(), true
+	default:
+		return "", false
+	}
+}
+
+// jsonEquals compares two values, at least one of which is a JSON value.  If both hold valid JSON documents, they are
+// equal if their documents are.  Otherwise, they are equal if their texts are.
+func jsonEquals(v, other PropertyValue) bool {
+	if vd, ok := jsonDocument(v); ok {
+		if od, ok := jsonDocument(other); ok {
+			return vd.DeepEquals(od)
+		}
+	}
+	vt, vok := jsonText(v)
+	ot, ook := jsonText(other)
+	return vok && ook && vt == ot
+}
+
+// jsonDiff diffs two values, at least one of which is a JSON value.  If both hold valid JSON objects or arrays, the
+// diff of their documents is returned, so that it can be rendered element by element.
+func jsonDiff(v, other PropertyValue) *ValueDiff {
+	if jsonEquals(v, other) {
+		return nil
+	}
+	if vd, ok := jsonDocument(v); ok {
+		if od, ok := jsonDocument(other); ok {
+			if d := vd.Diff(od); d != nil && (d.Array != nil || d.Object != nil) {
+				return &ValueDiff{Old: v, New: other, Array: d.Array, Object: d.Object}
+			}
+		}
+	}
+	return &ValueDiff{Old: v, New: other}
+}
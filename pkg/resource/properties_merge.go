@@ -0,0 +1,162 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"sort"
+)
+
+// MergeOutcome classifies how a three-way merge resolved a single property path.
+type MergeOutcome int
+
+const (
+	// MergeOurs means that only our side changed the value, or that both sides changed it in the same way.
+	MergeOurs MergeOutcome = iota
+	// MergeTheirs means that only their side changed the value.
+	MergeTheirs
+	// MergeConflict means that both sides changed the value, but in different ways.
+	MergeConflict
+)
+
+func (o MergeOutcome) String() string {
+	switch o {
+	case MergeOurs:
+		return "ours"
+	case MergeTheirs:
+		return "theirs"
+	case MergeConflict:
+		return "conflict"
+	default:
+		return "unknown"
+	}
+}
+
+// MergeChange records how a three-way merge resolved a path at which either side changed the base value.
+type MergeChange struct {
+	Path    PropertyPath // the path of the value.
+	Outcome MergeOutcome // whose change was taken, or whether the changes conflicted.
+	// Reasserted is true if their change was discarded in favor of our unchanged value. This only happens for paths
+	// that the caller asked to reassert.
+	Reasserted bool
+}
+
+// PropertyMerge is the result of a three-way merge of property maps.
+type PropertyMerge struct {
+	Merged  PropertyMap   // the merged properties.
+	Changes []MergeChange // the paths at which either side changed the base value, sorted by path.
+}
+
+// Conflicts returns the changes at which both sides changed the base value in different ways.
+func (m *PropertyMerge) Conflicts() []MergeChange {
+	var conflicts []MergeChange
+	for _, c := range m.Changes {
+		if c.Outcome == MergeConflict {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// Reasserted returns the changes at which their change was discarded in favor of our unchanged value.
+func (m *PropertyMerge) Reasserted() []MergeChange {
+	var reasserted []MergeChange
+	for _, c := range m.Changes {
+		if c.Reasserted {
+			reasserted = append(reasserted, c)
+		}
+	}
+	return reasserted
+}
+
+// Merge3 merges two sets of changes, theirs and mine, made to the same base properties. Objects are merged key by key;
+// all other values, including arrays, are merged as a whole. At each path:
+//
+//   - if neither side changed the value, or both changed it in the same way, that value is taken;
+//   - if only one side changed the value, that side's value is taken;
+//   - if both sides changed the value in different ways, the changes conflict and our value is taken.
+//
+// A path that is missing from one side was deleted by it. If reassert is non-nil, it is called for each path that only
+// their side changed; if it returns true, their change is discarded and our value is kept. This lets a caller keep
+// the values it manages while accepting changes to everything else.
+func Merge3(base, theirs, mine PropertyMap, reassert func(path PropertyPath) bool) *PropertyMerge {
+	m := &PropertyMerge{}
+	m.Merged = m.mergeObjects(nil, base, theirs, mine, reassert)
+	sort.SliceStable(m.Changes, func(i, j int) bool {
+		return m.Changes[i].Path.String() < m.Changes[j].Path.String()
+	})
+	return m
+}
+
+func (m *PropertyMerge) mergeObjects(path PropertyPath, base, theirs, mine PropertyMap,
+	reassert func(path PropertyPath) bool) PropertyMap {
+
+	keys := make(map[PropertyKey]bool)
+	for _, props := range []PropertyMap{base, theirs, mine} {
+		for k := range props {
+			keys[k] = true
+		}
+	}
+
+	merged := make(PropertyMap)
+	for k := range keys {
+		b, hasBase := base[k]
+		t, hasTheirs := theirs[k]
+		o, hasMine := mine[k]
+		p := append(path[:len(path):len(path)], string(k))
+		if v, ok := m.mergeValues(p, b, hasBase, t, hasTheirs, o, hasMine, reassert); ok {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergeValues merges a single value and reports whether the merged value exists.
+func (m *PropertyMerge) mergeValues(path PropertyPath, base PropertyValue, hasBase bool, theirs PropertyValue,
+	hasTheirs bool, mine PropertyValue, hasMine bool, reassert func(path PropertyPath) bool) (PropertyValue, bool) {
+
+	same := func(a PropertyValue, hasA bool, b PropertyValue, hasB bool) bool {
+		if !hasA || !hasB {
+			return hasA == hasB
+		}
+		return a.DeepEquals(b)
+	}
+
+	switch {
+	case same(theirs, hasTheirs, mine, hasMine):
+		if !same(base, hasBase, mine, hasMine) {
+			m.Changes = append(m.Changes, MergeChange{Path: path, Outcome: MergeOurs})
+		}
+		return mine, hasMine
+	case same(base, hasBase, theirs, hasTheirs):
+		m.Changes = append(m.Changes, MergeChange{Path: path, Outcome: MergeOurs})
+		return mine, hasMine
+	case hasTheirs && hasMine && theirs.IsObject() && mine.IsObject() && (!hasBase || base.IsObject()):
+		var baseObj PropertyMap
+		if hasBase {
+			baseObj = base.ObjectValue()
+		}
+		return NewObjectProperty(m.mergeObjects(path, baseObj, theirs.ObjectValue(), mine.ObjectValue(), reassert)), true
+	case same(base, hasBase, mine, hasMine):
+		if reassert != nil && reassert(path) {
+			m.Changes = append(m.Changes, MergeChange{Path: path, Outcome: MergeTheirs, Reasserted: true})
+			return mine, hasMine
+		}
+		m.Changes = append(m.Changes, MergeChange{Path: path, Outcome: MergeTheirs})
+		return theirs, hasTheirs
+	default:
+		m.Changes = append(m.Changes, MergeChange{Path: path, Outcome: MergeConflict})
+		return mine, hasMine
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	t.Parallel()
+
+	base := NewPropertyMapFromMap(map[string]interface{}{
+		"same":     "a",
+		"ours":     "a",
+		"theirs":   "a",
+		"both":     "a",
+		"conflict": "a",
+		"deleted":  "a",
+		"tags":     map[string]interface{}{"owner": "a", "env": "a"},
+	})
+	theirs := NewPropertyMapFromMap(map[string]interface{}{
+		"same":     "a",
+		"ours":     "a",
+		"theirs":   "b",
+		"both":     "b",
+		"conflict": "b",
+		"tags":     map[string]interface{}{"owner": "b", "env": "a", "team": "b"},
+	})
+	mine := NewPropertyMapFromMap(map[string]interface{}{
+		"same":     "a",
+		"ours":     "c",
+		"theirs":   "a",
+		"both":     "b",
+		"conflict": "c",
+		"deleted":  "a",
+		"tags":     map[string]interface{}{"owner": "a", "env": "c"},
+	})
+
+	m := Merge3(base, theirs, mine, nil)
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"same":     "a",
+		"ours":     "c",
+		"theirs":   "b",
+		"both":     "b",
+		"conflict": "c",
+		"tags":     map[string]interface{}{"owner": "b", "env": "c", "team": "b"},
+	}), m.Merged)
+
+	outcomes := make(map[string]MergeOutcome)
+	for _, c := range m.Changes {
+		outcomes[c.Path.String()] = c.Outcome
+	}
+	assert.Equal(t, map[string]MergeOutcome{
+		"ours":       MergeOurs,
+		"theirs":     MergeTheirs,
+		"both":       MergeOurs,
+		"conflict":   MergeConflict,
+		"deleted":    MergeTheirs,
+		"tags.owner": MergeTheirs,
+		"tags.env":   MergeOurs,
+		"tags.team":  MergeTheirs,
+	}, outcomes)
+	assert.Equal(t, []MergeChange{{Path: PropertyPath{"conflict"}, Outcome: MergeConflict}}, m.Conflicts())
+	assert.Empty(t, m.Reasserted())
+
+	// Paths that are reasserted keep our value even if only their side changed it.
+	m = Merge3(base, theirs, mine, func(path PropertyPath) bool { return path[0] == "tags" })
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"owner": "a", "env": "c",
+	}), m.Merged["tags"].ObjectValue())
+	assert.Equal(t, "b", m.Merged["theirs"].StringValue())
+	assert.Equal(t, []MergeChange{
+		{Path: PropertyPath{"tags", "owner"}, Outcome: MergeTheirs, Reasserted: true},
+		{Path: PropertyPath{"tags", "team"}, Outcome: MergeTheirs, Reasserted: true},
+	}, m.Reasserted())
+}
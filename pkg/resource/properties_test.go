@@ -15,9 +15,11 @@
 package resource
 
 import (
+	"math"
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -158,3 +160,352 @@ func TestCopy(t *testing.T) {
 	src["c"] = NewNumberProperty(99.99)
 	assert.Equal(t, 2, len(dst))
 }
+
+// TestCanonicalBytes ensures that the canonical encoding is insensitive to construction order and normalizes values,
+// while still distinguishing values of different kinds.
+func TestCanonicalBytes(t *testing.T) {
+	m1 := NewPropertyMapFromMap(map[string]interface{}{
+		"a": "x",
+		"b": float64(1),
+		"c": []interface{}{true, "y", map[string]interface{}{"z": float64(0)}},
+	})
+	m2 := PropertyMap{
+		"c": NewArrayProperty([]PropertyValue{
+			NewBoolProperty(true),
+			NewStringProperty("y"),
+			NewObjectProperty(PropertyMap{"z": NewNumberProperty(math.Copysign(0, -1))}),
+		}),
+		"b": NewNumberProperty(1),
+		"a": NewStringProperty("x"),
+		"d": NewNullProperty(),
+	}
+	assert.Equal(t, m1.CanonicalBytes(), m2.CanonicalBytes())
+	assert.Equal(t, m1.Fingerprint(), m2.Fingerprint())
+
+	// Values of different kinds never collide.
+	m3 := m1.Copy()
+	m3["b"] = NewStringProperty("1")
+	assert.NotEqual(t, m1.Fingerprint(), m3.Fingerprint())
+
+	// Computed values are distinct from their elements.
+	m4 := m1.Copy()
+	m4["a"] = MakeComputed(NewStringProperty("x"))
+	assert.NotEqual(t, m1.Fingerprint(), m4.Fingerprint())
+
+	// Length prefixes keep adjacent strings from running together.
+	a1 := NewArrayProperty([]PropertyValue{NewStringProperty("ab"), NewStringProperty("c")})
+	a2 := NewArrayProperty([]PropertyValue{NewStringProperty("a"), NewStringProperty("bc")})
+	assert.NotEqual(t, a1.CanonicalBytes(), a2.CanonicalBytes())
+
+	assert.Len(t, m1.Fingerprint().String(), 64)
+}
+
+// TestSortSets ensures that arrays treated as sets compare equal regardless of element order, and that only the arrays
+// at the given paths are sorted.
+func TestSortSets(t *testing.T) {
+	rule := func(port float64) interface{} {
+		return map[string]interface{}{"port": port, "protocol": "tcp"}
+	}
+	m1 := NewPropertyMapFromMap(map[string]interface{}{
+		"ingress": []interface{}{rule(80), rule(443), rule(22)},
+		"nested":  map[string]interface{}{"tags": []interface{}{"a", "b", "c"}},
+		"ordered": []interface{}{"x", "y"},
+	})
+	m2 := NewPropertyMapFromMap(map[string]interface{}{
+		"ingress": []interface{}{rule(22), rule(80), rule(443)},
+		"nested":  map[string]interface{}{"tags": []interface{}{"c", "a", "b"}},
+		"ordered": []interface{}{"y", "x"},
+	})
+	assert.False(t, m1.DeepEquals(m2))
+
+	paths := []PropertyPath{{"ingress"}, {"nested", "tags"}, {"missing"}}
+	s1, s2 := m1.SortSets(paths), m2.SortSets(paths)
+	assert.True(t, s1["ingress"].DeepEquals(s2["ingress"]))
+	assert.True(t, s1["nested"].DeepEquals(s2["nested"]))
+	assert.False(t, s1["ordered"].DeepEquals(s2["ordered"]))
+
+	// The original maps are left untouched.
+	assert.Equal(t, "c", m2["nested"].ObjectValue()["tags"].ArrayValue()[0].StringValue())
+
+	// Without any arrays to sort, the map itself is returned.
+	assert.Equal(t, m1, m1.SortSets([]PropertyPath{{"missing"}}))
+	assert.Equal(t, NewStringProperty("x"), NewStringProperty("x").SortedSet())
+}
+
+// TestJSON ensures that JSON values are compared and diffed by the documents they hold rather than by their text.
+func TestJSON(t *testing.T) {
+	a := NewJSONProperty(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow"}]}`)
+	b := NewJSONProperty(`{"Statement":[{"Effect":"Allow"}],"Version":"2012-10-17"}`)
+	c := NewJSONProperty(`{"Statement":[{"Effect":"Deny"}],"Version":"2012-10-17"}`)
+	assert.True(t, a.DeepEquals(b))
+	assert.Nil(t, a.Diff(b))
+	assert.False(t, a.DeepEquals(c))
+	assert.Equal(t, a.CanonicalBytes(), b.CanonicalBytes())
+	assert.NotEqual(t, a.CanonicalBytes(), c.CanonicalBytes())
+
+	// JSON values compare with strings that hold the same document in either direction.
+	s := NewStringProperty(b.JSONValue().Raw)
+	assert.True(t, a.DeepEquals(s))
+	assert.True(t, s.DeepEquals(a))
+
+	// Diffs are computed element by element.
+	diff := a.Diff(c)
+	if assert.NotNil(t, diff) && assert.NotNil(t, diff.Object) {
+		assert.Equal(t, []PropertyKey{"Statement"}, diff.Object.ChangedKeys())
+	}
+
+	// Invalid documents are compared by their text.
+	assert.True(t, NewJSONProperty("{").DeepEquals(NewStringProperty("{")))
+	assert.False(t, NewJSONProperty("{").DeepEquals(NewJSONProperty("{ ")))
+	assert.NotEqual(t, NewJSONProperty(`"{"`).CanonicalBytes(), NewJSONProperty("{").CanonicalBytes())
+
+	// JSON values map to their text.
+	assert.Equal(t, b.JSONValue().Raw, b.Mappable())
+	assert.Equal(t, "json", b.TypeString())
+}
+
+// TestMarkJSON ensures that only the string values at the given paths are marked as JSON values.
+func TestMarkJSON(t *testing.T) {
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"policy": `{"a": 1}`,
+		"nested": map[string]interface{}{"doc": `[1, 2]`, "other": `{}`},
+		"count":  float64(42),
+	})
+	marked := m.MarkJSON([]PropertyPath{{"policy"}, {"nested", "doc"}, {"count"}, {"missing"}})
+	assert.True(t, marked["policy"].IsJSON())
+	assert.True(t, marked["nested"].ObjectValue()["doc"].IsJSON())
+	assert.True(t, marked["nested"].ObjectValue()["other"].IsString())
+	assert.True(t, marked["count"].IsNumber())
+
+	// The original map is left untouched.
+	assert.True(t, m["policy"].IsString())
+
+	// Without any strings to mark, the map itself is returned.
+	assert.Equal(t, m, m.MarkJSON([]PropertyPath{{"count"}}))
+}
+
+func TestDeepCopy(t *testing.T) {
+	m := PropertyMap{
+		"a": NewStringProperty("x"),
+		"b": NewArrayProperty([]PropertyValue{NewNumberProperty(1), NewObjectProperty(PropertyMap{
+			"c": NewBoolProperty(true),
+		})}),
+		"d": NewObjectProperty(PropertyMap{"e": NewNullProperty()}),
+		"f": NewAssetProperty(&Asset{Sig: AssetSig, Text: "hello"}),
+		"g": NewArchiveProperty(&Archive{Sig: ArchiveSig, Assets: map[string]interface{}{
+			"h": &Asset{Sig: AssetSig, Text: "world"},
+		}}),
+		"i": MakeComputed(NewObjectProperty(PropertyMap{})),
+	}
+
+	// A shallow copy shares nested values with the original.
+	shallow := m.Copy()
+	assert.Equal(t, m, shallow)
+	shallow["d"].ObjectValue()["e"] = NewStringProperty("shared")
+	assert.Equal(t, NewStringProperty("shared"), m["d"].ObjectValue()["e"])
+	m["d"].ObjectValue()["e"] = NewNullProperty()
+
+	// A deep copy does not.
+	deep := m.DeepCopy()
+	assert.Equal(t, m, deep)
+	deep["a"] = NewStringProperty("y")
+	deep["b"].ArrayValue()[0] = NewNumberProperty(2)
+	deep["b"].ArrayValue()[1].ObjectValue()["c"] = NewBoolProperty(false)
+	deep["d"].ObjectValue()["e"] = NewStringProperty("z")
+	deep["f"].AssetValue().Text = "goodbye"
+	deep["g"].ArchiveValue().Assets["h"].(*Asset).Text = "moon"
+	deep["i"].Input().Element.ObjectValue()["j"] = NewStringProperty("k")
+
+	assert.Equal(t, NewStringProperty("x"), m["a"])
+	assert.Equal(t, NewNumberProperty(1), m["b"].ArrayValue()[0])
+	assert.Equal(t, NewBoolProperty(true), m["b"].ArrayValue()[1].ObjectValue()["c"])
+	assert.Equal(t, NewNullProperty(), m["d"].ObjectValue()["e"])
+	assert.Equal(t, "hello", m["f"].AssetValue().Text)
+	assert.Equal(t, "world", m["g"].ArchiveValue().Assets["h"].(*Asset).Text)
+	assert.Len(t, m["i"].Input().Element.ObjectValue(), 0)
+
+	assert.Nil(t, PropertyMap(nil).DeepCopy())
+}
+
+func TestFreeze(t *testing.T) {
+	m := PropertyMap{
+		"a": NewStringProperty("x"),
+		"b": NewObjectProperty(PropertyMap{"c": NewNumberProperty(1)}),
+	}
+	frozen := m.Freeze()
+
+	// Changes to the original are not visible through the view.
+	m["a"] = NewStringProperty("y")
+	m["b"].ObjectValue()["c"] = NewNumberProperty(2)
+
+	assert.Equal(t, 2, frozen.Len())
+	assert.True(t, frozen.Has("a"))
+	assert.False(t, frozen.Has("z"))
+	assert.Equal(t, []PropertyKey{"a", "b"}, frozen.StableKeys())
+
+	a, ok := frozen.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, NewStringProperty("x"), a)
+
+	// Changes to values read from the view are not visible through the view either.
+	b, ok := frozen.Get("b")
+	assert.True(t, ok)
+	b.ObjectValue()["c"] = NewNumberProperty(3)
+	b, _ = frozen.Get("b")
+	assert.Equal(t, NewNumberProperty(1), b.ObjectValue()["c"])
+
+	_, ok = frozen.Get("z")
+	assert.False(t, ok)
+
+	// The view itself may not be mutated, but a thawed copy may.
+	assert.Panics(t, func() { frozen.Set("a", NewNullProperty()) })
+	assert.Panics(t, func() { frozen.Delete("a") })
+	thawed := frozen.Thaw()
+	thawed["a"] = NewNullProperty()
+	a, _ = frozen.Get("a")
+	assert.Equal(t, NewStringProperty("x"), a)
+}
+
+func TestTriState(t *testing.T) {
+	m := PropertyMap{
+		"null":   NewNullProperty(),
+		"bool":   NewBoolProperty(false),
+		"number": NewNumberProperty(0),
+		"string": NewStringProperty(""),
+		"array":  NewArrayProperty(nil),
+		"object": NewObjectProperty(PropertyMap{}),
+		"unk":    MakeComputed(NewStringProperty("")),
+	}
+
+	assert.True(t, m.HasKey("null"))
+	assert.False(t, m.HasValue("null"))
+	assert.False(t, m.HasKey("missing"))
+
+	v, ok := m.GetOK("null")
+	assert.True(t, ok)
+	assert.True(t, v.IsNull())
+	_, ok = m.GetOK("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, PropertyAbsent, m.State("missing"))
+	assert.Equal(t, PropertyNull, m.State("null"))
+	assert.Equal(t, PropertyPresent, m.State("string"))
+	assert.Equal(t, PropertyPresent, m.State("unk"))
+	assert.Equal(t, "null", m.State("null").String())
+
+	// Zero values are distinguishable from null and absent values.
+	b, state := m.GetBool("bool")
+	assert.Equal(t, PropertyPresent, state)
+	assert.False(t, b)
+	n, state := m.GetNumber("number")
+	assert.Equal(t, PropertyPresent, state)
+	assert.Equal(t, float64(0), n)
+	s, state := m.GetString("string")
+	assert.Equal(t, PropertyPresent, state)
+	assert.Equal(t, "", s)
+	_, state = m.GetArray("array")
+	assert.Equal(t, PropertyPresent, state)
+	_, state = m.GetObject("object")
+	assert.Equal(t, PropertyPresent, state)
+
+	_, state = m.GetString("null")
+	assert.Equal(t, PropertyNull, state)
+	_, state = m.GetString("missing")
+	assert.Equal(t, PropertyAbsent, state)
+
+	// Asking for a property as the wrong type reports a mismatch.
+	_, state = m.GetString("bool")
+	assert.Equal(t, PropertyMismatch, state)
+	_, state = m.GetString("unk")
+	assert.Equal(t, PropertyMismatch, state)
+	assert.Equal(t, "mismatch", state.String())
+}
+
+func TestFormatEvaluate(t *testing.T) {
+	bucket := NewStringProperty("my-bucket")
+	f := Format{Template: "arn:aws:s3:::%s/%s (100%%)", Args: []PropertyValue{bucket, NewNumberProperty(42)}}
+	v, err := f.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, NewStringProperty("arn:aws:s3:::my-bucket/42 (100%)"), v)
+
+	// Unknown arguments, including those of nested formats, make the result unknown.
+	nested := NewFormatProperty(Format{Template: "%s", Args: []PropertyValue{MakeComputed(NewStringProperty(""))}})
+	f = Format{Template: "%s-%s", Args: []PropertyValue{bucket, nested}}
+	v, err = f.Evaluate()
+	assert.NoError(t, err)
+	assert.True(t, v.IsComputed())
+
+	// Arrays and objects are inserted as JSON.
+	f = Format{Template: "%s", Args: []PropertyValue{NewPropertyValue(map[string]interface{}{"a": []interface{}{1}})}}
+	v, err = f.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":[1]}`, v.StringValue())
+
+	// Mismatched placeholders are errors even when the result would be unknown.
+	for _, template := range []string{"%s", "%s %s %s", "%d %s", "%s %"} {
+		f = Format{Template: template, Args: []PropertyValue{bucket, MakeComputed(NewStringProperty(""))}}
+		_, err = f.Evaluate()
+		assert.Error(t, err, template)
+	}
+
+	// Formats are evaluated wherever they appear in a property map.
+	props := PropertyMap{
+		"name": NewFormatProperty(Format{Template: "%s-log", Args: []PropertyValue{bucket}}),
+		"tags": NewArrayProperty([]PropertyValue{
+			NewFormatProperty(Format{Template: "owner=%s", Args: []PropertyValue{NewStringProperty("ops")}}),
+		}),
+	}
+	evaluated, err := props.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"name": "my-bucket-log",
+		"tags": []interface{}{"owner=ops"},
+	}), evaluated)
+	plain := NewPropertyMapFromMap(map[string]interface{}{"name": "x"})
+	evaluated, err = plain.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, plain, evaluated)
+}
+
+func TestApply(t *testing.T) {
+	host, port := NewStringProperty("db.local"), NewNumberProperty(5432)
+	connect := func(v PropertyValue) (PropertyValue, error) {
+		arr := v.ArrayValue()
+		return NewFormatProperty(Format{Template: "postgres://%s:%s", Args: arr}), nil
+	}
+
+	// Appliers run lazily, once their arguments are resolved.
+	runs := 0
+	counted := host.Apply(func(v PropertyValue) (PropertyValue, error) {
+		runs++
+		return NewStringProperty(strings.ToUpper(v.StringValue())), nil
+	})
+	assert.Equal(t, 0, runs)
+	v, err := counted.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, NewStringProperty("DB.LOCAL"), v)
+	assert.Equal(t, 1, runs)
+
+	// All combines several values, and the result of an applier is itself resolved.
+	v, err = All(host, port).Apply(connect).Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, NewStringProperty("postgres://db.local:5432"), v)
+
+	// An unknown argument makes the result unknown without running the applier.
+	runs = 0
+	unknown := All(host, MakeComputed(NewNumberProperty(0))).Apply(func(v PropertyValue) (PropertyValue, error) {
+		runs++
+		return connect(v)
+	})
+	assert.True(t, unknown.ContainsUnknowns())
+	v, err = unknown.Resolve()
+	assert.NoError(t, err)
+	assert.True(t, v.IsComputed())
+	assert.Equal(t, 0, runs)
+
+	// Errors from appliers are reported along with the property that holds them.
+	_, err = PropertyMap{"url": host.Apply(func(PropertyValue) (PropertyValue, error) {
+		return PropertyValue{}, errors.New("boom")
+	})}.Resolve()
+	assert.EqualError(t, err, "property url: boom")
+}
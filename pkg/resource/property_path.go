@@ -0,0 +1,321 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// PropertyPath identifies a value nested within a PropertyMap. Each element of a path is either a string, which
+// names a key of an object, or an int, which indexes an array.
+type PropertyPath []interface{}
+
+// ParsePropertyPath parses a property path of the form `a.b[0].c`. Keys that contain characters other than letters,
+// digits, and underscores may be written as quoted strings within brackets, e.g. `tags["kubernetes.io/name"]`.
+func ParsePropertyPath(path string) (PropertyPath, error) {
+	var result PropertyPath
+	for i := 0; i < len(path); {
+		switch c := path[i]; {
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, errors.Errorf("property path '%v' is missing a closing ']'", path)
+			}
+			elem := path[i+1 : i+end]
+			if len(elem) > 1 && elem[0] == '"' {
+				key, err := strconv.Unquote(elem)
+				if err != nil {
+					return nil, errors.Errorf("property path '%v' has an invalid key %v", path, elem)
+				}
+				result = append(result, key)
+			} else {
+				index, err := strconv.Atoi(elem)
+				if err != nil || index < 0 {
+					return nil, errors.Errorf("property path '%v' has an invalid index [%v]", path, elem)
+				}
+				result = append(result, index)
+			}
+			i += end + 1
+		case c == '.' && i > 0:
+			i++
+			fallthrough
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end == -1 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, errors.Errorf("property path '%v' has an empty key", path)
+			}
+			result = append(result, path[i:i+end])
+			i += end
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("property paths may not be empty")
+	}
+	if _, ok := result[0].(string); !ok {
+		return nil, errors.Errorf("property path '%v' must begin with a key", path)
+	}
+	return result, nil
+}
+
+// Get returns the value at this path in the given map, if any.
+func (p PropertyPath) Get(m PropertyMap) (PropertyValue, bool) {
+	v := NewObjectProperty(m)
+	for _, elem := range p {
+		switch elem := elem.(type) {
+		case string:
+			if !v.IsObject() {
+				return PropertyValue{}, false
+			}
+			child, has := v.ObjectValue()[PropertyKey(elem)]
+			if !has {
+				return PropertyValue{}, false
+			}
+			v = child
+		case int:
+			if !v.IsArray() || elem >= len(v.ArrayValue()) {
+				return PropertyValue{}, false
+			}
+			v = v.ArrayValue()[elem]
+		}
+	}
+	return v, true
+}
+
+// Set sets the value at this path in the given map, creating any missing intermediate objects. It returns false if
+// the path cannot be set, e.g. because an intermediate value is not an object or an array index is out of range.
+// Set modifies the given map in place, including any nested objects and arrays.
+func (p PropertyPath) Set(m PropertyMap, value PropertyValue) bool {
+	parent, ok := p.parent(m, true)
+	if !ok {
+		return false
+	}
+	switch elem := p[len(p)-1].(type) {
+	case string:
+		if !parent.IsObject() {
+			return false
+		}
+		parent.ObjectValue()[PropertyKey(elem)] = value
+	case int:
+		if !parent.IsArray() || elem >= len(parent.ArrayValue()) {
+			return false
+		}
+		parent.ArrayValue()[elem] = value
+	}
+	return true
+}
+
+// Delete removes the value at this path from the given map. Array elements cannot be removed. Delete modifies the
+// given map in place and returns false if the value could not be removed.
+func (p PropertyPath) Delete(m PropertyMap) bool {
+	parent, ok := p.parent(m, false)
+	if !ok {
+		return false
+	}
+	key, isKey := p[len(p)-1].(string)
+	if !isKey || !parent.IsObject() {
+		return false
+	}
+	delete(parent.ObjectValue(), PropertyKey(key))
+	return true
+}
+
+// parent returns the value that contains the last element of this path, optionally creating missing objects.
+func (p PropertyPath) parent(m PropertyMap, create bool) (PropertyValue, bool) {
+	v := NewObjectProperty(m)
+	for i, elem := range p[:len(p)-1] {
+		switch elem := elem.(type) {
+		case string:
+			if !v.IsObject() {
+				return PropertyValue{}, false
+			}
+			obj := v.ObjectValue()
+			child, has := obj[PropertyKey(elem)]
+			if !has || child.IsNull() {
+				if !create {
+					return PropertyValue{}, false
+				}
+				if _, isIndex := p[i+1].(int); isIndex {
+					return PropertyValue{}, false
+				}
+				child = NewObjectProperty(PropertyMap{})
+				obj[PropertyKey(elem)] = child
+			}
+			v = child
+		case int:
+			if !v.IsArray() || elem >= len(v.ArrayValue()) {
+				return PropertyValue{}, false
+			}
+			v = v.ArrayValue()[elem]
+		}
+	}
+	return v, true
+}
+
+// String returns the canonical textual form of this path.
+func (p PropertyPath) String() string {
+	var buf bytes.Buffer
+	for i, elem := range p {
+		switch elem := elem.(type) {
+		case string:
+			if isSimplePathKey(elem) {
+				if i > 0 {
+					buf.WriteByte('.')
+				}
+				buf.WriteString(elem)
+			} else {
+				fmt.Fprintf(&buf, "[%q]", elem)
+			}
+		case int:
+			fmt.Fprintf(&buf, "[%d]", elem)
+		}
+	}
+	return buf.String()
+}
+
+// isSimplePathKey returns true if the given key may be written without brackets and quotes.
+func isSimplePathKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Project returns a new map that holds only the values at the given paths, along with the objects that contain them.
+// Because arrays cannot be partially populated, a path that passes through an array element projects the entire
+// array.  Paths that are absent from the map are ignored.  The values in the result are deep copies.
+func (m PropertyMap) Project(paths []PropertyPath) PropertyMap {
+	result := make(PropertyMap)
+	for _, path := range paths {
+		for i, elem := range path {
+			if _, isIndex := elem.(int); isIndex {
+				path = path[:i]
+				break
+			}
+		}
+		if len(path) == 0 {
+			continue
+		}
+		if v, has := path.Get(m); has {
+			path.Set(result, v.DeepCopy())
+		}
+	}
+	return result
+}
+
+// Omit returns a deep copy of the map from which the values at the given paths have been removed.  Array elements
+// cannot be removed without renumbering their siblings, so they are replaced with nulls instead.  Paths that are
+// absent from the map are ignored.
+func (m PropertyMap) Omit(paths []PropertyPath) PropertyMap {
+	result := m.DeepCopy()
+	for _, path := range paths {
+		if _, has := path.Get(result); !has {
+			continue
+		}
+		if _, isIndex := path[len(path)-1].(int); isIndex {
+			path.Set(result, NewNullProperty())
+		} else {
+			path.Delete(result)
+		}
+	}
+	return result
+}
+
+// Redact returns a deep copy of the map in which the values at the given paths have been replaced by their digests
+// (see RedactValue).  Paths that are absent from the map are ignored.
+func (m PropertyMap) Redact(paths []PropertyPath) PropertyMap {
+	result := m.DeepCopy()
+	for _, path := range paths {
+		if v, has := path.Get(result); has && !v.IsNull() {
+			path.Set(result, RedactValue(v))
+		}
+	}
+	return result
+}
+
+// redactionKey keys the digests of redacted values.  It is generated anew for each process, so that a digest cannot be
+// matched against the digests of guessed values.
+var redactionKey = func() []byte {
+	key := make([]byte, sha256.Size)
+	_, err := rand.Read(key)
+	contract.AssertNoErrorf(err, "generating a redaction key")
+	return key
+}()
+
+// RedactValue returns a string that stands in for the given value without revealing it.  The string holds an HMAC of
+// the value's JSON encoding, so that values redacted by the same process may still be compared with one another, e.g.
+// to tell whether a redacted value has changed.  Because the HMAC's key is never revealed, a digest cannot be used to
+// confirm a guess of the value behind it.
+func RedactValue(v PropertyValue) PropertyValue {
+	b, err := json.Marshal(v.Mappable())
+	contract.AssertNoErrorf(err, "marshaling a property value to redact it")
+	mac := hmac.New(sha256.New, redactionKey)
+	_, err = mac.Write(b)
+	contract.AssertNoError(err)
+	return NewStringProperty(fmt.Sprintf("[redacted %x]", mac.Sum(nil)))
+}
+
+// Filter returns a new map that holds the values for which pred returns true.  The predicate is called with the path
+// to each value, starting at the top-level keys; the elements of objects and arrays are only visited if pred accepts
+// their container.  Rejected array elements are removed, so the indices of later elements shift.
+func (m PropertyMap) Filter(pred func(PropertyPath, PropertyValue) bool) PropertyMap {
+	return filterObject(nil, m, pred)
+}
+
+func filterObject(path PropertyPath, m PropertyMap, pred func(PropertyPath, PropertyValue) bool) PropertyMap {
+	result := make(PropertyMap)
+	for _, k := range m.StableKeys() {
+		p := append(path[:len(path):len(path)], string(k))
+		if v := m[k]; pred(p, v) {
+			result[k] = filterValue(p, v, pred)
+		}
+	}
+	return result
+}
+
+func filterValue(path PropertyPath, v PropertyValue, pred func(PropertyPath, PropertyValue) bool) PropertyValue {
+	switch {
+	case v.IsObject():
+		return NewObjectProperty(filterObject(path, v.ObjectValue(), pred))
+	case v.IsArray():
+		result := []PropertyValue{}
+		for i, e := range v.ArrayValue() {
+			if p := append(path[:len(path):len(path)], i); pred(p, e) {
+				result = append(result, filterValue(p, e, pred))
+			}
+		}
+		return NewArrayProperty(result)
+	default:
+		return v.DeepCopy()
+	}
+}
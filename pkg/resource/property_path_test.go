@@ -0,0 +1,167 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePropertyPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected PropertyPath
+	}{
+		{"foo", PropertyPath{"foo"}},
+		{"foo.bar", PropertyPath{"foo", "bar"}},
+		{"foo[0].bar", PropertyPath{"foo", 0, "bar"}},
+		{"foo[1][2]", PropertyPath{"foo", 1, 2}},
+		{`tags["kubernetes.io/name"]`, PropertyPath{"tags", "kubernetes.io/name"}},
+		{`["foo.bar"].baz`, PropertyPath{"foo.bar", "baz"}},
+	}
+	for _, c := range cases {
+		path, err := ParsePropertyPath(c.path)
+		assert.NoError(t, err, c.path)
+		assert.Equal(t, c.expected, path, c.path)
+		assert.Equal(t, c.path, path.String())
+	}
+
+	for _, invalid := range []string{"", ".foo", "foo.", "foo..bar", "foo[", "foo[-1]", "foo[bar]", "[0]"} {
+		_, err := ParsePropertyPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPropertyPathGetSetDelete(t *testing.T) {
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{"a", "b"},
+		},
+	})
+
+	v, ok := PropertyPath{"foo", "bar", 1}.Get(m)
+	assert.True(t, ok)
+	assert.Equal(t, NewStringProperty("b"), v)
+	_, ok = PropertyPath{"foo", "baz"}.Get(m)
+	assert.False(t, ok)
+	_, ok = PropertyPath{"foo", "bar", 2}.Get(m)
+	assert.False(t, ok)
+
+	assert.True(t, PropertyPath{"foo", "bar", 0}.Set(m, NewStringProperty("c")))
+	assert.True(t, PropertyPath{"qux", "quux"}.Set(m, NewNumberProperty(42)))
+	assert.False(t, PropertyPath{"foo", "bar", 2}.Set(m, NewStringProperty("d")))
+	assert.False(t, PropertyPath{"foo", "bar", "baz"}.Set(m, NewStringProperty("d")))
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{"c", "b"},
+		},
+		"qux": map[string]interface{}{
+			"quux": 42,
+		},
+	}), m)
+
+	assert.True(t, PropertyPath{"qux", "quux"}.Delete(m))
+	assert.False(t, PropertyPath{"foo", "bar", 0}.Delete(m))
+	assert.False(t, PropertyPath{"missing", "key"}.Delete(m))
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{"c", "b"},
+		},
+		"qux": map[string]interface{}{},
+	}), m)
+}
+
+func TestPropertyMapProjectOmitFilter(t *testing.T) {
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"spec": map[string]interface{}{
+			"ports":    []interface{}{float64(80), float64(443)},
+			"password": "hunter2",
+		},
+		"tags": map[string]interface{}{"env": "dev"},
+	})
+
+	// Projection keeps only the named values and their containers; paths through arrays keep the whole array.
+	projected := m.Project([]PropertyPath{{"spec", "password"}, {"spec", "ports", 1}, {"missing"}})
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ports":    []interface{}{float64(80), float64(443)},
+			"password": "hunter2",
+		},
+	}), projected)
+
+	// Omission removes object keys and nulls out array elements.
+	omitted := m.Omit([]PropertyPath{{"spec", "password"}, {"spec", "ports", 0}, {"tags"}, {"missing"}})
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"spec": map[string]interface{}{
+			"ports": []interface{}{nil, float64(443)},
+		},
+	}), omitted)
+
+	// Filtering visits every value by path, and drops rejected array elements entirely.
+	var visited []string
+	filtered := m.Filter(func(path PropertyPath, v PropertyValue) bool {
+		visited = append(visited, path.String())
+		return path.String() != "spec.password" && !(v.IsNumber() && v.NumberValue() == 80)
+	})
+	assert.Equal(t, NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"spec": map[string]interface{}{
+			"ports": []interface{}{float64(443)},
+		},
+		"tags": map[string]interface{}{"env": "dev"},
+	}), filtered)
+	assert.Equal(t, []string{
+		"name", "spec", "spec.password", "spec.ports", "spec.ports[0]", "spec.ports[1]", "tags", "tags.env",
+	}, visited)
+
+	// None of the helpers modifies the original map.
+	password, _ := PropertyPath{"spec", "password"}.Get(m)
+	assert.Equal(t, NewStringProperty("hunter2"), password)
+	port, _ := PropertyPath{"spec", "ports", 0}.Get(m)
+	assert.Equal(t, NewNumberProperty(80), port)
+}
+
+func TestPropertyMapRedact(t *testing.T) {
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"spec": map[string]interface{}{
+			"password": "hunter2",
+			"ports":    []interface{}{float64(80), float64(443)},
+		},
+	})
+
+	redacted := m.Redact([]PropertyPath{{"spec", "password"}, {"spec", "ports", 1}, {"missing"}})
+	assert.Equal(t, NewStringProperty("web"), redacted["name"])
+	password, _ := PropertyPath{"spec", "password"}.Get(redacted)
+	assert.Equal(t, RedactValue(NewStringProperty("hunter2")), password)
+	assert.True(t, strings.HasPrefix(password.StringValue(), "[redacted "))
+	assert.NotContains(t, password.StringValue(), "hunter2")
+	port, _ := PropertyPath{"spec", "ports", 0}.Get(redacted)
+	assert.Equal(t, NewNumberProperty(80), port)
+	port, _ = PropertyPath{"spec", "ports", 1}.Get(redacted)
+	assert.Equal(t, RedactValue(NewNumberProperty(443)), port)
+
+	// Equal values have equal digests, and different values different ones.
+	assert.Equal(t, RedactValue(NewNumberProperty(443)), RedactValue(NewNumberProperty(443)))
+	assert.NotEqual(t, RedactValue(NewNumberProperty(443)), RedactValue(NewNumberProperty(80)))
+
+	// The original map is left untouched.
+	password, _ = PropertyPath{"spec", "password"}.Get(m)
+	assert.Equal(t, NewStringProperty("hunter2"), password)
+}
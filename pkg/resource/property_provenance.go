@@ -0,0 +1,66 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"time"
+)
+
+// PropertySource identifies the provider operation that last set the value of an output property.
+type PropertySource struct {
+	Op   string    `json:"op" yaml:"op"`     // the operation, e.g. "create", "update", "read", or "refresh".
+	Time time.Time `json:"time" yaml:"time"` // when the operation set the value.
+}
+
+// PropertyProvenance maps the paths of a resource's output properties to the operations that last set their values.
+// The properties of objects are tracked individually; other values, including arrays, are tracked as a whole.
+type PropertyProvenance map[string]PropertySource
+
+// Update returns the provenance of the new outputs of a resource whose old outputs had this provenance.  Values that
+// the operation changed or added are attributed to the given source, and values that it left unchanged keep their
+// sources, if they are known.  Paths that no longer exist are dropped.
+func (p PropertyProvenance) Update(olds, news PropertyMap, source PropertySource) PropertyProvenance {
+	if len(news) == 0 {
+		return nil
+	}
+	result := make(PropertyProvenance)
+	p.updateObject(result, nil, olds, news, source)
+	return result
+}
+
+func (p PropertyProvenance) updateObject(result PropertyProvenance, path PropertyPath, olds, news PropertyMap,
+	source PropertySource) {
+
+	for _, k := range news.StableKeys() {
+		elemPath := append(append(PropertyPath{}, path...), string(k))
+		old, hasOld := olds[k]
+		new := news[k]
+		if new.IsObject() && len(new.ObjectValue()) > 0 {
+			var oldObj PropertyMap
+			if hasOld && old.IsObject() {
+				oldObj = old.ObjectValue()
+			}
+			p.updateObject(result, elemPath, oldObj, new.ObjectValue(), source)
+			continue
+		}
+
+		key := elemPath.String()
+		if !hasOld || !old.DeepEquals(new) {
+			result[key] = source
+		} else if prior, has := p[key]; has {
+			result[key] = prior
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyProvenanceUpdate(t *testing.T) {
+	t.Parallel()
+
+	created := PropertySource{Op: "create", Time: time.Unix(100, 0)}
+	updated := PropertySource{Op: "update", Time: time.Unix(200, 0)}
+
+	olds := NewPropertyMapFromMap(map[string]interface{}{
+		"name": "a",
+		"size": 1,
+		"tags": map[string]interface{}{"owner": "me", "env": "dev"},
+		"ips":  []interface{}{"10.0.0.1"},
+	})
+	provenance := PropertyProvenance(nil).Update(nil, olds, created)
+	assert.Equal(t, PropertyProvenance{
+		"name":       created,
+		"size":       created,
+		"tags.owner": created,
+		"tags.env":   created,
+		"ips":        created,
+	}, provenance)
+
+	// Only the values that changed are attributed to the update; removed values are dropped.
+	news := NewPropertyMapFromMap(map[string]interface{}{
+		"name": "a",
+		"tags": map[string]interface{}{"owner": "me", "env": "prod", "kubernetes.io/name": "a"},
+		"ips":  []interface{}{"10.0.0.1", "10.0.0.2"},
+	})
+	assert.Equal(t, PropertyProvenance{
+		"name":                       created,
+		"tags.owner":                 created,
+		"tags.env":                   updated,
+		`tags["kubernetes.io/name"]`: updated,
+		"ips":                        updated,
+	}, provenance.Update(olds, news, updated))
+
+	// Unchanged values whose sources are unknown stay unknown.
+	assert.Equal(t, PropertyProvenance{"tags.env": updated, `tags["kubernetes.io/name"]`: updated, "ips": updated},
+		PropertyProvenance(nil).Update(olds, news, updated))
+
+	assert.Nil(t, provenance.Update(olds, nil, updated))
+}
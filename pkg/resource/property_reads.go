@@ -0,0 +1,37 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+// PropertyReads records the outputs of other resources that a resource's input properties were computed from.  For each
+// input property, it maps the URN of each resource that the property depends on to the paths of the outputs that were
+// read from that resource.  A property that depends on a resource for which no paths are recorded is assumed to have
+// read all of that resource's outputs.
+type PropertyReads map[PropertyKey]map[URN][]string
+
+// ReadsOnly returns true if the paths that the property k read from the resource urn are known, and if pred returns
+// true for each of them.  It returns false if the paths are unknown or if any of them cannot be parsed.
+func (r PropertyReads) ReadsOnly(k PropertyKey, urn URN, pred func(path PropertyPath) bool) bool {
+	paths := r[k][urn]
+	if len(paths) == 0 {
+		return false
+	}
+	for _, p := range paths {
+		path, err := ParsePropertyPath(p)
+		if err != nil || !pred(path) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyReadsReadsOnly(t *testing.T) {
+	t.Parallel()
+
+	urnA, urnB := URN("urn:pulumi:test::test::pkgA:m:typA::resA"), URN("urn:pulumi:test::test::pkgA:m:typA::resB")
+	reads := PropertyReads{
+		"name":  {urnA: {"name"}},
+		"arn":   {urnA: {"name", "arn"}},
+		"tags":  {urnA: {"tags.owner"}, urnB: {}},
+		"oops":  {urnA: {"[oops"}},
+		"empty": {},
+	}
+	stable := func(path PropertyPath) bool { return path[0] == "name" || path[0] == "tags" }
+
+	assert.True(t, reads.ReadsOnly("name", urnA, stable))
+	assert.False(t, reads.ReadsOnly("arn", urnA, stable))
+	assert.True(t, reads.ReadsOnly("tags", urnA, stable))
+
+	// Unknown or unparseable reads are never narrowed.
+	assert.False(t, reads.ReadsOnly("tags", urnB, stable))
+	assert.False(t, reads.ReadsOnly("oops", urnA, stable))
+	assert.False(t, reads.ReadsOnly("empty", urnA, stable))
+	assert.False(t, reads.ReadsOnly("missing", urnA, stable))
+	assert.False(t, PropertyReads(nil).ReadsOnly("name", urnA, stable))
+}
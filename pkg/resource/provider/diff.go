@@ -0,0 +1,82 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+// DiffProperties compares a resource's old inputs with its new inputs and returns a detailed diff.  Changes to the
+// properties that the schema marks with Replace require replacement; the schema may be nil.
+//
+// The old inputs are compared rather than the old outputs, because outputs typically hold properties that the
+// provider computed and that will never appear in the inputs.
+func DiffProperties(oldInputs, newInputs resource.PropertyMap, schema Schema) plugin.DiffResult {
+	diff := oldInputs.Diff(newInputs)
+	if diff == nil {
+		return plugin.DiffResult{Changes: plugin.DiffNone}
+	}
+
+	replace := make(map[resource.PropertyKey]bool)
+	for _, k := range schema.ReplaceKeys() {
+		replace[k] = true
+	}
+
+	result := plugin.DiffResult{
+		Changes:      plugin.DiffSome,
+		DetailedDiff: make(map[string]plugin.PropertyDiff),
+	}
+	for _, k := range diff.ChangedKeys() {
+		result.ChangedKeys = append(result.ChangedKeys, k)
+		if replace[k] {
+			result.ReplaceKeys = append(result.ReplaceKeys, k)
+		}
+	}
+	for _, path := range diff.ChangedPaths() {
+		k := resource.PropertyKey(path[0].(string))
+
+		// Nested changes are reported as updates of the top-level property that contains them.
+		kind := plugin.DiffUpdate
+		if len(path) == 1 {
+			switch {
+			case diff.Added(k):
+				kind = plugin.DiffAdd
+			case diff.Deleted(k):
+				kind = plugin.DiffDelete
+			}
+		}
+		if replace[k] {
+			kind = replaceKind(kind)
+		}
+		result.DetailedDiff[path.String()] = plugin.PropertyDiff{Kind: kind, InputDiff: true}
+	}
+	if len(result.ChangedKeys) == 0 {
+		return plugin.DiffResult{Changes: plugin.DiffNone}
+	}
+	return result
+}
+
+// replaceKind returns the replacing variant of the given kind of change.
+func replaceKind(kind plugin.DiffKind) plugin.DiffKind {
+	switch kind {
+	case plugin.DiffAdd:
+		return plugin.DiffAddReplace
+	case plugin.DiffDelete:
+		return plugin.DiffDeleteReplace
+	default:
+		return plugin.DiffUpdateReplace
+	}
+}
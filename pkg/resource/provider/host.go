@@ -33,9 +33,17 @@ type HostClient struct {
 
 // NewHostClient dials the target address, connects over gRPC, and returns a client interface.
 func NewHostClient(addr string) (*HostClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithUnaryInterceptor(
-		rpcutil.OpenTracingClientInterceptor(),
-	))
+	return NewHostClientWithOptions(addr, rpcutil.TransportOptions{})
+}
+
+// NewHostClientWithOptions is like NewHostClient, but connects using the given transport options.
+func NewHostClientWithOptions(addr string, transport rpcutil.TransportOptions) (*HostClient, error) {
+	dialOpts, err := transport.DialOptions()
+	if err != nil {
+		return nil, err
+	}
+	dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(rpcutil.OpenTracingClientInterceptor()))
+	conn, err := grpc.Dial(addr, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 	"github.com/pulumi/pulumi/pkg/util/rpcutil"
@@ -46,13 +47,25 @@ func Main(name string, provMaker func(*HostClient) (pulumirpc.ResourceProviderSe
 	if len(args) == 0 {
 		return errors.New("fatal: could not connect to host RPC; missing argument")
 	}
-	host, err := NewHostClient(args[0])
+
+	// Configure the transport; the engine may ask for keepalives or mutual TLS through the environment. The same
+	// options secure both the connection to the engine and the engine's connections to this provider.
+	transport, err := rpcutil.TransportOptionsFromEnv()
+	if err != nil {
+		return errors.Errorf("fatal: %v", err)
+	}
+	host, err := NewHostClientWithOptions(args[0], transport)
 	if err != nil {
 		return errors.Errorf("fatal: could not connect to host RPC: %v", err)
 	}
 
+	serverOpts, err := transport.ServerOptions()
+	if err != nil {
+		return errors.Errorf("fatal: %v", err)
+	}
+
 	// Fire up a gRPC server, letting the kernel choose a free port for us.
-	port, done, err := rpcutil.Serve(0, nil, []func(*grpc.Server) error{
+	port, done, err := rpcutil.ServeWithOptions(0, nil, []func(*grpc.Server) error{
 		func(srv *grpc.Server) error {
 			prov, proverr := provMaker(host)
 			if proverr != nil {
@@ -61,7 +74,7 @@ func Main(name string, provMaker func(*HostClient) (pulumirpc.ResourceProviderSe
 			pulumirpc.RegisterResourceProviderServer(srv, prov)
 			return nil
 		},
-	})
+	}, serverOpts...)
 	if err != nil {
 		return errors.Errorf("fatal: %v", err)
 	}
@@ -76,3 +89,20 @@ func Main(name string, provMaker func(*HostClient) (pulumirpc.ResourceProviderSe
 
 	return nil
 }
+
+// MainProvider is the entrypoint for a resource provider plugin that implements the Provider interface.  It serves
+// the provider returned by provMaker, reporting the given version to the engine.  If the engine exchanges large values
+// out of band (see plugin.LargeValueDirEnvVar), the provider does as well.
+func MainProvider(name, version string, provMaker func(*HostClient) (Provider, error)) error {
+	return Main(name, func(host *HostClient) (pulumirpc.ResourceProviderServer, error) {
+		large, threshold, err := plugin.LargeValueStoreFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		prov, err := provMaker(host)
+		if err != nil {
+			return nil, err
+		}
+		return newLargeValueServer(name, version, prov, large, threshold), nil
+	})
+}
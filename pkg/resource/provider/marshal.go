@@ -0,0 +1,51 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// Unmarshal decodes the given property map into the struct, map, or other value pointed to by dest.  The value is
+// decoded as if the property map were a JSON object, so struct fields are matched by their `json` tags.  Property maps
+// that contain unknown values cannot be decoded, so Unmarshal should not be used on inputs during previews.
+func Unmarshal(props resource.PropertyMap, dest interface{}) error {
+	if props.ContainsUnknowns() {
+		return errors.New("cannot unmarshal a property map that contains unknown values")
+	}
+	b, err := json.Marshal(props.Mappable())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dest)
+}
+
+// Marshal encodes the given struct, map, or other value as a property map.  The value is encoded as if it were
+// marshaled to a JSON object, so struct fields are named by their `json` tags.
+func Marshal(v interface{}) (resource.PropertyMap, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrap(err, "value must marshal to a JSON object")
+	}
+	return resource.NewPropertyMapFromMap(m), nil
+}
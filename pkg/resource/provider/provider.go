@@ -0,0 +1,92 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/schema"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// Provider is the interface implemented by resource providers written in Go.  Unlike pulumirpc.ResourceProviderServer,
+// it deals in property maps rather than gRPC structs; NewServer adapts a Provider to the gRPC interface, and
+// MainProvider serves one as a plugin.
+//
+// Providers that also need configuration, functions, cancellation, output previews, or a published schema implement
+// Configurer, Invoker, Canceler, Previewer, or Describer.
+type Provider interface {
+	// Check validates the new inputs for a resource of the given type and returns the inputs that should be passed to
+	// Diff, Create, and Update.  The old inputs are nil if the resource is being created.  During previews the new
+	// inputs may contain unknown values.
+	Check(urn resource.URN, olds, news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error)
+	// Diff reports the changes between a resource's old outputs and its new inputs.  The old inputs are also passed.
+	Diff(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult,
+		error)
+	// Create creates a resource and returns its ID and outputs.  A non-zero timeout is the number of seconds the
+	// operation may take.
+	Create(urn resource.URN, news resource.PropertyMap, timeout float64) (resource.ID, resource.PropertyMap, error)
+	// Read returns the current outputs of a resource, or nil if the resource no longer exists.
+	Read(urn resource.URN, id resource.ID, props resource.PropertyMap) (resource.PropertyMap, error)
+	// Update updates a resource and returns its new outputs.
+	Update(urn resource.URN, id resource.ID, oldOutputs, newInputs resource.PropertyMap,
+		timeout float64) (resource.PropertyMap, error)
+	// Delete deletes a resource.
+	Delete(urn resource.URN, id resource.ID, props resource.PropertyMap, timeout float64) error
+}
+
+// Configurer is implemented by providers that accept configuration.  The variables are keyed by their names within
+// the provider's package, e.g. "region" for "aws:region".
+type Configurer interface {
+	Configure(vars map[string]string) error
+}
+
+// Invoker is implemented by providers that offer functions.
+type Invoker interface {
+	Invoke(tok tokens.ModuleMember, args resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, error)
+}
+
+// Canceler is implemented by providers that can abort their outstanding operations.
+type Canceler interface {
+	Cancel() error
+}
+
+// Previewer is implemented by providers that can predict some of the outputs of a resource from its checked inputs,
+// such as identifiers that are derived from its name.  The engine reports these outputs during previews in place of
+// unknowns.  Providers predict the outputs of updates and replacements through DiffResult.PreviewOutputs.
+type Previewer interface {
+	PreviewOutputs(urn resource.URN, inputs resource.PropertyMap) (resource.PropertyMap, error)
+}
+
+// Describer is implemented by providers that publish a schema of the resource types that they manage, from which
+// `pulumi plugin gen` generates typed structs for the programs and tools that consume them.
+type Describer interface {
+	Describe() (*schema.Package, error)
+}
+
+// InitError may be returned by Create or Update if the resource was created or updated but failed to initialize.  The
+// resource's ID and outputs are recorded so that the engine can track the resource.
+type InitError struct {
+	ID      resource.ID
+	Outputs resource.PropertyMap
+	Reasons []string
+}
+
+func (err *InitError) Error() string {
+	if len(err.Reasons) == 0 {
+		return "resource failed to initialize"
+	}
+	return (&plugin.InitError{Reasons: err.Reasons}).Error()
+}
@@ -0,0 +1,157 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+// PropertyType is the type of a property in a schema.
+type PropertyType string
+
+const (
+	TypeAny     PropertyType = ""        // any value is accepted.
+	TypeBool    PropertyType = "bool"    // a boolean.
+	TypeNumber  PropertyType = "number"  // a number.
+	TypeString  PropertyType = "string"  // a string.
+	TypeArray   PropertyType = "array"   // an array.
+	TypeObject  PropertyType = "object"  // an object.
+	TypeAsset   PropertyType = "asset"   // an asset.
+	TypeArchive PropertyType = "archive" // an archive.
+)
+
+// PropertySchema describes a single input property of a resource.
+type PropertySchema struct {
+	Type     PropertyType    // the type of the property's values.
+	Required bool            // true if the property must be set.
+	Default  interface{}     // an optional value to use when the property is not set.
+	Replace  bool            // true if changing the property requires the resource to be replaced.
+	Elements *PropertySchema // an optional schema for the elements of an array property.
+	Object   Schema          // an optional schema for the properties of an object property.
+}
+
+// Schema describes the input properties of a resource type.  Check and Diff use it to validate inputs and to decide
+// which changes require replacement, so that simple providers need not hand-write either.
+type Schema map[resource.PropertyKey]PropertySchema
+
+// Check validates the given inputs against the schema and returns them with defaults applied.  Unknown values are
+// accepted wherever a value is expected.  Properties not described by the schema are reported as failures.
+func (s Schema) Check(news resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure) {
+	var failures []plugin.CheckFailure
+	inputs := s.check("", news, &failures)
+	return inputs, failures
+}
+
+func (s Schema) check(prefix string, props resource.PropertyMap,
+	failures *[]plugin.CheckFailure) resource.PropertyMap {
+
+	fail := func(k resource.PropertyKey, format string, args ...interface{}) {
+		*failures = append(*failures, plugin.CheckFailure{
+			Property: resource.PropertyKey(prefix + string(k)),
+			Reason:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	result := props.Copy()
+	for _, k := range props.StableKeys() {
+		if _, has := s[k]; !has {
+			fail(k, "unknown property '%s'", prefix+string(k))
+		}
+	}
+
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		k := resource.PropertyKey(key)
+		ps := s[k]
+		v, has := props[k]
+		if !has || v.IsNull() {
+			switch {
+			case ps.Default != nil:
+				result[k] = resource.NewPropertyValue(ps.Default)
+			case ps.Required:
+				fail(k, "missing required property '%s'", prefix+key)
+			}
+			continue
+		}
+		result[k] = ps.check(prefix+key, v, func(format string, args ...interface{}) { fail(k, format, args...) },
+			failures)
+	}
+	return result
+}
+
+func (ps PropertySchema) check(path string, v resource.PropertyValue, fail func(string, ...interface{}),
+	failures *[]plugin.CheckFailure) resource.PropertyValue {
+
+	if v.ContainsUnknowns() && !v.IsArray() && !v.IsObject() {
+		return v
+	}
+
+	var ok bool
+	switch ps.Type {
+	case TypeAny:
+		ok = true
+	case TypeBool:
+		ok = v.IsBool()
+	case TypeNumber:
+		ok = v.IsNumber()
+	case TypeString:
+		ok = v.IsString()
+	case TypeArray:
+		ok = v.IsArray()
+	case TypeObject:
+		ok = v.IsObject()
+	case TypeAsset:
+		ok = v.IsAsset()
+	case TypeArchive:
+		ok = v.IsArchive()
+	}
+	if !ok {
+		fail("property '%s' must be of type %s; got %s", path, ps.Type, v.TypeString())
+		return v
+	}
+
+	switch {
+	case v.IsArray() && ps.Elements != nil:
+		elems := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			elems[i] = ps.Elements.check(elemPath, elem, fail, failures)
+		}
+		return resource.NewArrayProperty(elems)
+	case v.IsObject() && ps.Object != nil:
+		return resource.NewObjectProperty(ps.Object.check(path+".", v.ObjectValue(), failures))
+	}
+	return v
+}
+
+// ReplaceKeys returns the keys of the properties that the schema marks as requiring replacement when changed.
+func (s Schema) ReplaceKeys() []resource.PropertyKey {
+	var keys []resource.PropertyKey
+	for k, ps := range s {
+		if ps.Replace {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
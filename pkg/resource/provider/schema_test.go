@@ -0,0 +1,113 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+var testSchema = Schema{
+	"name":  {Type: TypeString, Required: true, Replace: true},
+	"size":  {Type: TypeNumber, Default: 1.0},
+	"tags":  {Type: TypeArray, Elements: &PropertySchema{Type: TypeString}},
+	"owner": {Type: TypeObject, Object: Schema{"email": {Type: TypeString, Required: true}}},
+}
+
+func TestSchemaCheck(t *testing.T) {
+	inputs, failures := testSchema.Check(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"tags": []interface{}{"a", "b"},
+	}))
+	assert.Empty(t, failures)
+	assert.Equal(t, resource.NewNumberProperty(1), inputs["size"])
+
+	// Unknown values are accepted wherever a value is expected.
+	_, failures = testSchema.Check(resource.PropertyMap{
+		"name": resource.MakeComputed(resource.NewStringProperty("")),
+		"tags": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.MakeComputed(resource.NewStringProperty("")),
+		}),
+	})
+	assert.Empty(t, failures)
+
+	_, failures = testSchema.Check(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"size":  "big",
+		"tags":  []interface{}{"a", 2},
+		"owner": map[string]interface{}{},
+		"color": "red",
+	}))
+	var reasons []string
+	for _, f := range failures {
+		reasons = append(reasons, f.Reason)
+	}
+	sort.Strings(reasons)
+	assert.Equal(t, []string{
+		"missing required property 'name'",
+		"missing required property 'owner.email'",
+		"property 'size' must be of type number; got string",
+		"property 'tags[1]' must be of type string; got number",
+		"unknown property 'color'",
+	}, reasons)
+}
+
+func TestDiffProperties(t *testing.T) {
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{"name": "web", "size": 1, "tags": []interface{}{"a"}})
+
+	diff := DiffProperties(olds, olds.Copy(), testSchema)
+	assert.Equal(t, plugin.DiffNone, diff.Changes)
+
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{"name": "web", "size": 2, "tags": []interface{}{"b"}})
+	diff = DiffProperties(olds, news, testSchema)
+	assert.Equal(t, plugin.DiffSome, diff.Changes)
+	assert.False(t, diff.Replace())
+	assert.Equal(t, []resource.PropertyKey{"size", "tags"}, diff.ChangedKeys)
+	assert.Equal(t, map[string]plugin.PropertyDiff{
+		"size":    {Kind: plugin.DiffUpdate, InputDiff: true},
+		"tags[0]": {Kind: plugin.DiffUpdate, InputDiff: true},
+	}, diff.DetailedDiff)
+
+	news = resource.NewPropertyMapFromMap(map[string]interface{}{"name": "api", "size": 1, "tags": []interface{}{"a"}})
+	diff = DiffProperties(olds, news, testSchema)
+	assert.True(t, diff.Replace())
+	assert.Equal(t, plugin.DiffUpdateReplace, diff.DetailedDiff["name"].Kind)
+}
+
+func TestMarshal(t *testing.T) {
+	type args struct {
+		Name string   `json:"name"`
+		Size int      `json:"size,omitempty"`
+		Tags []string `json:"tags,omitempty"`
+	}
+
+	props, err := Marshal(args{Name: "web", Tags: []string{"a"}})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "web",
+		"tags": []interface{}{"a"},
+	}), props)
+
+	var decoded args
+	assert.NoError(t, Unmarshal(props, &decoded))
+	assert.Equal(t, args{Name: "web", Tags: []string{"a"}}, decoded)
+
+	props["size"] = resource.MakeComputed(resource.NewStringProperty(""))
+	assert.Error(t, Unmarshal(props, &decoded))
+}
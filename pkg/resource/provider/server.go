@@ -0,0 +1,408 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/schema"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// server adapts a Provider to the gRPC resource provider interface.
+type server struct {
+	name      string
+	version   string
+	provider  Provider
+	large     plugin.LargeValueStore // the side channel for large property values, if any.
+	threshold int                    // the size in bytes above which values are sent over the side channel.
+}
+
+// NewServer returns a gRPC resource provider server for the given provider.  The name is used to label property maps
+// in logs, and the version is reported to the engine.
+func NewServer(name, version string, provider Provider) pulumirpc.ResourceProviderServer {
+	return &server{name: name, version: version, provider: provider}
+}
+
+// newLargeValueServer returns a server like NewServer's that exchanges string values larger than the threshold with
+// the engine over the given large value store (see plugin.LargeValueDirEnvVar).
+func newLargeValueServer(name, version string, provider Provider, large plugin.LargeValueStore,
+	threshold int) pulumirpc.ResourceProviderServer {
+
+	return &server{name: name, version: version, provider: provider, large: large, threshold: threshold}
+}
+
+func (s *server) unmarshal(label string, props *structpb.Struct, keepUnknowns bool) (resource.PropertyMap, error) {
+	return plugin.UnmarshalProperties(props, plugin.MarshalOptions{
+		Label:           fmt.Sprintf("%s.%s", s.name, label),
+		KeepUnknowns:    keepUnknowns,
+		LargeValueStore: s.large,
+	})
+}
+
+func (s *server) marshal(label string, props resource.PropertyMap, keepUnknowns bool) (*structpb.Struct, error) {
+	return plugin.MarshalProperties(props, plugin.MarshalOptions{
+		Label:               fmt.Sprintf("%s.%s", s.name, label),
+		KeepUnknowns:        keepUnknowns,
+		LargeValueStore:     s.large,
+		LargeValueThreshold: s.threshold,
+	})
+}
+
+// CheckConfig is not implemented, so that the engine falls back to its default validation of configuration.
+func (s *server) CheckConfig(context.Context, *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	return nil, rpcerror.New(codes.Unimplemented, "CheckConfig is not yet implemented")
+}
+
+// DiffConfig is not implemented, so that the engine falls back to its default diffing of configuration.
+func (s *server) DiffConfig(context.Context, *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	return nil, rpcerror.New(codes.Unimplemented, "DiffConfig is not yet implemented")
+}
+
+func (s *server) Configure(ctx context.Context, req *pulumirpc.ConfigureRequest) (*pbempty.Empty, error) {
+	configurer, ok := s.provider.(Configurer)
+	if !ok {
+		return &pbempty.Empty{}, nil
+	}
+
+	// The engine may send either spelling of a configuration key, e.g. "aws:region" or "aws:config:region".
+	vars := make(map[string]string)
+	for k, v := range req.GetVariables() {
+		key, err := config.ParseKey(k)
+		if err != nil {
+			return nil, rpcerror.Wrapf(codes.InvalidArgument, err, "invalid configuration key '%s'", k)
+		}
+		vars[key.Name()] = v
+	}
+	if err := configurer.Configure(vars); err != nil {
+		return nil, err
+	}
+	return &pbempty.Empty{}, nil
+}
+
+func (s *server) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
+	invoker, ok := s.provider.(Invoker)
+	if !ok {
+		return nil, rpcerror.Newf(codes.Unimplemented, "unknown function '%s'", req.GetTok())
+	}
+
+	args, err := s.unmarshal("args", req.GetArgs(), false)
+	if err != nil {
+		return nil, err
+	}
+	ret, failures, err := invoker.Invoke(tokens.ModuleMember(req.GetTok()), args)
+	if err != nil {
+		return nil, err
+	}
+	mret, err := s.marshal("return", ret, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.InvokeResponse{Return: mret, Failures: marshalCheckFailures(failures)}, nil
+}
+
+func (s *server) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	olds, err := s.unmarshal("olds", req.GetOlds(), true)
+	if err != nil {
+		return nil, err
+	}
+	news, err := s.unmarshal("news", req.GetNews(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, failures, err := s.provider.Check(urn, olds, news)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	minputs, err := s.marshal("inputs", inputs, true)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pulumirpc.CheckResponse{Inputs: minputs, Failures: marshalCheckFailures(failures)}
+
+	if previewer, ok := s.provider.(Previewer); ok && len(failures) == 0 {
+		previews, err := previewer.PreviewOutputs(urn, inputs)
+		if err != nil {
+			return nil, err
+		}
+		if previews != nil {
+			if resp.PreviewOutputs, err = s.marshal("previewOutputs", previews, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (s *server) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	urn, id := resource.URN(req.GetUrn()), resource.ID(req.GetId())
+	oldOutputs, err := s.unmarshal("olds", req.GetOlds(), true)
+	if err != nil {
+		return nil, err
+	}
+	oldInputs, err := s.unmarshal("oldInputs", req.GetOldInputs(), true)
+	if err != nil {
+		return nil, err
+	}
+	newInputs, err := s.unmarshal("news", req.GetNews(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := s.provider.Diff(urn, id, oldInputs, oldOutputs, newInputs)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	resp := marshalDiffResult(diff)
+	if diff.PreviewOutputs != nil {
+		if resp.PreviewOutputs, err = s.marshal("previewOutputs", diff.PreviewOutputs, true); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *server) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	news, err := s.unmarshal("news", req.GetProperties(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	id, outs, err := s.provider.Create(urn, news, req.GetTimeout())
+	if err != nil {
+		return nil, s.initError(err)
+	}
+	mouts, err := s.marshal("outputs", outs, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.CreateResponse{Id: string(id), Properties: mouts}, nil
+}
+
+func (s *server) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	urn, id := resource.URN(req.GetUrn()), resource.ID(req.GetId())
+	props, err := s.unmarshal("props", req.GetProperties(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	outs, err := s.provider.Read(urn, id, props)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	if outs == nil {
+		// The resource no longer exists.
+		return &pulumirpc.ReadResponse{}, nil
+	}
+	mouts, err := s.marshal("outputs", outs, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.ReadResponse{Id: string(id), Properties: mouts}, nil
+}
+
+func (s *server) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+	urn, id := resource.URN(req.GetUrn()), resource.ID(req.GetId())
+	olds, err := s.unmarshal("olds", req.GetOlds(), false)
+	if err != nil {
+		return nil, err
+	}
+	news, err := s.unmarshal("news", req.GetNews(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	outs, err := s.provider.Update(urn, id, olds, news, req.GetTimeout())
+	if err != nil {
+		return nil, s.initError(err)
+	}
+	mouts, err := s.marshal("outputs", outs, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.UpdateResponse{Properties: mouts}, nil
+}
+
+func (s *server) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
+	urn, id := resource.URN(req.GetUrn()), resource.ID(req.GetId())
+	props, err := s.unmarshal("props", req.GetProperties(), false)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.provider.Delete(urn, id, props, req.GetTimeout()); err != nil {
+		return nil, providerError(err)
+	}
+	return &pbempty.Empty{}, nil
+}
+
+// ReadBatch reads each of the requested resources in turn.  A failure to read one resource is reported in its result
+// rather than failing the whole batch.
+func (s *server) ReadBatch(ctx context.Context,
+	req *pulumirpc.ReadBatchRequest) (*pulumirpc.ReadBatchResponse, error) {
+
+	results := make([]*pulumirpc.ReadBatchResponse_Result, len(req.GetRequests()))
+	for i, r := range req.GetRequests() {
+		resp, err := s.Read(ctx, r)
+		if err != nil {
+			results[i] = &pulumirpc.ReadBatchResponse_Result{Error: err.Error()}
+		} else {
+			results[i] = &pulumirpc.ReadBatchResponse_Result{Response: resp}
+		}
+	}
+	return &pulumirpc.ReadBatchResponse{Results: results}, nil
+}
+
+// DiffBatch diffs each of the requested resources in turn.  A failure to diff one resource is reported in its result
+// rather than failing the whole batch.
+func (s *server) DiffBatch(ctx context.Context,
+	req *pulumirpc.DiffBatchRequest) (*pulumirpc.DiffBatchResponse, error) {
+
+	results := make([]*pulumirpc.DiffBatchResponse_Result, len(req.GetRequests()))
+	for i, r := range req.GetRequests() {
+		resp, err := s.Diff(ctx, r)
+		if err != nil {
+			results[i] = &pulumirpc.DiffBatchResponse_Result{Error: err.Error()}
+		} else {
+			results[i] = &pulumirpc.DiffBatchResponse_Result{Response: resp}
+		}
+	}
+	return &pulumirpc.DiffBatchResponse{Results: results}, nil
+}
+
+func (s *server) Cancel(context.Context, *pbempty.Empty) (*pbempty.Empty, error) {
+	if canceler, ok := s.provider.(Canceler); ok {
+		if err := canceler.Cancel(); err != nil {
+			return nil, err
+		}
+	}
+	return &pbempty.Empty{}, nil
+}
+
+func (s *server) GetPluginInfo(context.Context, *pbempty.Empty) (*pulumirpc.PluginInfo, error) {
+	return &pulumirpc.PluginInfo{Version: s.version}, nil
+}
+
+// GetCapabilities advertises the optional features that the server supports.  It reads and diffs resources in batches,
+// understands typed unknowns, and returns detailed diffs for providers whose diffs include them.
+func (s *server) GetCapabilities(context.Context, *pbempty.Empty) (*pulumirpc.ProviderCapabilities, error) {
+	return &pulumirpc.ProviderCapabilities{
+		TypedUnknowns: true,
+		DetailedDiff:  true,
+		Read:          true,
+		Batch:         true,
+	}, nil
+}
+
+// GetSchema returns the schema published by a provider that is a Describer, encoded in the requested version of the
+// schema format.  Providers that are not Describers do not implement it.
+func (s *server) GetSchema(ctx context.Context,
+	req *pulumirpc.GetSchemaRequest) (*pulumirpc.GetSchemaResponse, error) {
+
+	describer, ok := s.provider.(Describer)
+	if !ok {
+		return nil, rpcerror.New(codes.Unimplemented, "GetSchema is not yet implemented")
+	}
+	if v := int(req.GetVersion()); v != schema.FormatVersion {
+		return nil, rpcerror.Newf(codes.InvalidArgument, "unsupported schema version %d (expected %d)",
+			v, schema.FormatVersion)
+	}
+
+	pkg, err := describer.Describe()
+	if err != nil {
+		return nil, err
+	}
+	data, err := pkg.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.GetSchemaResponse{Schema: string(data)}, nil
+}
+
+// initError converts an InitError returned by Create or Update into the error that tells the engine that the resource
+// exists but failed to initialize.  Other errors are converted by providerError.
+func (s *server) initError(err error) error {
+	initErr, ok := err.(*InitError)
+	if !ok {
+		return providerError(err)
+	}
+	mouts, merr := s.marshal("outputs", initErr.Outputs, false)
+	if merr != nil {
+		return merr
+	}
+	return rpcerror.WithDetails(
+		rpcerror.New(codes.Unknown, initErr.Error()),
+		&pulumirpc.ErrorResourceInitFailed{
+			Id:         string(initErr.ID),
+			Properties: mouts,
+			Reasons:    initErr.Reasons,
+		})
+}
+
+// providerError converts a plugin.ProviderError returned by the provider into the error that describes the failure to
+// the engine.  Other errors are returned as-is.
+func providerError(err error) error {
+	if perr, ok := err.(*plugin.ProviderError); ok {
+		return plugin.MarshalProviderError(perr)
+	}
+	return err
+}
+
+func marshalCheckFailures(failures []plugin.CheckFailure) []*pulumirpc.CheckFailure {
+	var result []*pulumirpc.CheckFailure
+	for _, f := range failures {
+		result = append(result, &pulumirpc.CheckFailure{Property: string(f.Property), Reason: f.Reason})
+	}
+	return result
+}
+
+func marshalDiffResult(diff plugin.DiffResult) *pulumirpc.DiffResponse {
+	resp := &pulumirpc.DiffResponse{
+		Changes:             pulumirpc.DiffResponse_DiffChanges(diff.Changes),
+		DeleteBeforeReplace: diff.DeleteBeforeReplace,
+	}
+	for _, k := range diff.ReplaceKeys {
+		resp.Replaces = append(resp.Replaces, string(k))
+	}
+	for _, k := range diff.StableKeys {
+		resp.Stables = append(resp.Stables, string(k))
+	}
+	for _, k := range diff.ChangedKeys {
+		resp.Diffs = append(resp.Diffs, string(k))
+	}
+	if diff.DetailedDiff != nil {
+		resp.HasDetailedDiff = true
+		resp.DetailedDiff = make(map[string]*pulumirpc.PropertyDiff)
+		for path, pd := range diff.DetailedDiff {
+			resp.DetailedDiff[path] = &pulumirpc.PropertyDiff{
+				Kind:      pulumirpc.PropertyDiff_Kind(pd.Kind),
+				InputDiff: pd.InputDiff,
+			}
+		}
+	}
+	return resp
+}
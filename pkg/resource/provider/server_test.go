@@ -0,0 +1,236 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/schema"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/rpcutil/rpcerror"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// testProvider is a provider whose resources are checked against testSchema and held in memory.
+type testProvider struct {
+	config    map[string]string
+	resources map[resource.ID]resource.PropertyMap
+	failNext  bool
+}
+
+func (p *testProvider) Configure(vars map[string]string) error {
+	p.config = vars
+	return nil
+}
+
+func (p *testProvider) Check(urn resource.URN, olds, news resource.PropertyMap) (resource.PropertyMap,
+	[]plugin.CheckFailure, error) {
+	inputs, failures := testSchema.Check(news)
+	return inputs, failures, nil
+}
+
+func (p *testProvider) Diff(urn resource.URN, id resource.ID,
+	oldInputs, oldOutputs, newInputs resource.PropertyMap) (plugin.DiffResult, error) {
+	return DiffProperties(oldInputs, newInputs, testSchema), nil
+}
+
+func (p *testProvider) Create(urn resource.URN, news resource.PropertyMap,
+	timeout float64) (resource.ID, resource.PropertyMap, error) {
+	id := resource.ID(news["name"].StringValue())
+	p.resources[id] = news
+	if p.failNext {
+		return "", nil, &InitError{ID: id, Outputs: news, Reasons: []string{"not healthy"}}
+	}
+	return id, news, nil
+}
+
+func (p *testProvider) Read(urn resource.URN, id resource.ID, props resource.PropertyMap) (resource.PropertyMap,
+	error) {
+	return p.resources[id], nil
+}
+
+func (p *testProvider) Update(urn resource.URN, id resource.ID, oldOutputs, newInputs resource.PropertyMap,
+	timeout float64) (resource.PropertyMap, error) {
+	p.resources[id] = newInputs
+	return newInputs, nil
+}
+
+func (p *testProvider) Delete(urn resource.URN, id resource.ID, props resource.PropertyMap, timeout float64) error {
+	if _, has := p.resources[id]; !has {
+		return &plugin.ProviderError{Class: plugin.ProviderErrorNotFound, Message: "no such thing", Code: "NotFound"}
+	}
+	delete(p.resources, id)
+	return nil
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	prov := &testProvider{resources: make(map[resource.ID]resource.PropertyMap)}
+	srv := NewServer("test", "1.2.3", prov)
+	urn := string(resource.NewURN("stack", "proj", "", "test:index:Thing", "thing"))
+
+	info, err := srv.GetPluginInfo(ctx, &pbempty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", info.GetVersion())
+
+	caps, err := srv.GetCapabilities(ctx, &pbempty.Empty{})
+	assert.NoError(t, err)
+	assert.True(t, caps.GetRead())
+	assert.True(t, caps.GetBatch())
+	assert.False(t, caps.GetAcceptSecrets())
+
+	_, err = srv.Configure(ctx, &pulumirpc.ConfigureRequest{Variables: map[string]string{"test:config:region": "west"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "west"}, prov.config)
+
+	marshal := func(m map[string]interface{}) *pulumirpc.CheckRequest {
+		props, err := plugin.MarshalProperties(resource.NewPropertyMapFromMap(m), plugin.MarshalOptions{})
+		assert.NoError(t, err)
+		return &pulumirpc.CheckRequest{Urn: urn, News: props}
+	}
+
+	// Check applies the schema's defaults and reports its failures.
+	check, err := srv.Check(ctx, marshal(map[string]interface{}{"name": "web"}))
+	assert.NoError(t, err)
+	assert.Empty(t, check.GetFailures())
+	assert.Equal(t, 1.0, check.GetInputs().Fields["size"].GetNumberValue())
+	check, err = srv.Check(ctx, marshal(map[string]interface{}{"size": 2}))
+	assert.NoError(t, err)
+	assert.Len(t, check.GetFailures(), 1)
+	assert.Equal(t, "name", check.GetFailures()[0].GetProperty())
+
+	// Create, diff, and delete a resource.
+	create, err := srv.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: marshal(map[string]interface{}{
+		"name": "web", "size": 1,
+	}).News})
+	assert.NoError(t, err)
+	assert.Equal(t, "web", create.GetId())
+
+	// Explicit nulls reach the provider, so that it can tell them apart from absent properties.
+	_, err = srv.Update(ctx, &pulumirpc.UpdateRequest{Urn: urn, Id: "web", Olds: create.GetProperties(),
+		News: marshal(map[string]interface{}{"name": "web", "size": nil}).News})
+	assert.NoError(t, err)
+	size, has := prov.resources["web"]["size"]
+	assert.True(t, has)
+	assert.True(t, size.IsNull())
+
+	diff, err := srv.Diff(ctx, &pulumirpc.DiffRequest{
+		Urn:       urn,
+		Id:        "web",
+		Olds:      create.GetProperties(),
+		OldInputs: create.GetProperties(),
+		News:      marshal(map[string]interface{}{"name": "api", "size": 1}).News,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"name"}, diff.GetReplaces())
+	assert.True(t, diff.GetHasDetailedDiff())
+	assert.Equal(t, pulumirpc.PropertyDiff_UPDATE_REPLACE, diff.GetDetailedDiff()["name"].GetKind())
+
+	_, err = srv.Delete(ctx, &pulumirpc.DeleteRequest{Urn: urn, Id: "web", Properties: create.GetProperties()})
+	assert.NoError(t, err)
+	read, err := srv.Read(ctx, &pulumirpc.ReadRequest{Urn: urn, Id: "web"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", read.GetId())
+
+	// Failures that the provider describes are sent along with their descriptions.
+	_, err = srv.Delete(ctx, &pulumirpc.DeleteRequest{Urn: urn, Id: "web", Properties: create.GetProperties()})
+	rpcErr := rpcerror.Convert(err)
+	assert.Equal(t, codes.NotFound, rpcErr.Code())
+	if assert.Len(t, rpcErr.Details(), 1) {
+		failure, ok := rpcErr.Details()[0].(*pulumirpc.ErrorProviderFailure)
+		assert.True(t, ok)
+		assert.Equal(t, pulumirpc.ErrorProviderFailure_NOT_FOUND, failure.GetClass())
+		assert.Equal(t, "NotFound", failure.GetCode())
+	}
+
+	// Batched reads report the result of each read separately.
+	batch, err := srv.ReadBatch(ctx, &pulumirpc.ReadBatchRequest{Requests: []*pulumirpc.ReadRequest{
+		{Urn: urn, Id: "web"},
+		{Urn: urn, Id: "web", Properties: &structpb.Struct{Fields: map[string]*structpb.Value{"x": {
+			Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+				resource.SigKey: {Kind: &structpb.Value_StringValue{StringValue: "unknown signature"}},
+			}}},
+		}}}},
+	}})
+	assert.NoError(t, err)
+	if assert.Len(t, batch.GetResults(), 2) {
+		assert.Equal(t, "", batch.GetResults()[0].GetResponse().GetId())
+		assert.Empty(t, batch.GetResults()[0].GetError())
+		assert.NotEmpty(t, batch.GetResults()[1].GetError())
+	}
+
+	// A resource that fails to initialize is reported along with its ID and outputs.
+	prov.failNext = true
+	_, err = srv.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: marshal(map[string]interface{}{
+		"name": "broken",
+	}).News})
+	assert.Error(t, err)
+	details := rpcerror.Convert(err).Details()
+	assert.Len(t, details, 1)
+	initErr, ok := details[0].(*pulumirpc.ErrorResourceInitFailed)
+	assert.True(t, ok)
+	assert.Equal(t, "broken", initErr.GetId())
+	assert.Equal(t, []string{"not healthy"}, initErr.GetReasons())
+
+	// Functions are not supported by this provider, and nor are schemas.
+	_, err = srv.Invoke(ctx, &pulumirpc.InvokeRequest{Tok: "test:index:getThing"})
+	assert.Error(t, err)
+	_, err = srv.GetSchema(ctx, &pulumirpc.GetSchemaRequest{Version: schema.FormatVersion})
+	assert.Equal(t, codes.Unimplemented, rpcerror.Convert(err).Code())
+}
+
+// describingProvider is a testProvider that publishes a schema.
+type describingProvider struct {
+	testProvider
+	pkg *schema.Package
+}
+
+func (p *describingProvider) Describe() (*schema.Package, error) {
+	return p.pkg, nil
+}
+
+func TestServerSchema(t *testing.T) {
+	ctx := context.Background()
+	pkg := &schema.Package{
+		Name: "test",
+		Resources: map[tokens.Type]*schema.Resource{
+			"test:index:Thing": {Inputs: &schema.Type{
+				Kind:       schema.ObjectKind,
+				Properties: map[resource.PropertyKey]*schema.Type{"name": {Kind: schema.StringKind}},
+				Required:   []resource.PropertyKey{"name"},
+			}},
+		},
+	}
+	srv := NewServer("test", "1.2.3", &describingProvider{pkg: pkg})
+
+	resp, err := srv.GetSchema(ctx, &pulumirpc.GetSchemaRequest{Version: schema.FormatVersion})
+	assert.NoError(t, err)
+	parsed, err := schema.ParsePackage([]byte(resp.GetSchema()))
+	assert.NoError(t, err)
+	assert.Equal(t, pkg, parsed)
+
+	// Versions of the schema format other than the current one are refused.
+	_, err = srv.GetSchema(ctx, &pulumirpc.GetSchemaRequest{Version: schema.FormatVersion + 1})
+	assert.Equal(t, codes.InvalidArgument, rpcerror.Convert(err).Code())
+}
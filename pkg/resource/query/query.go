@@ -0,0 +1,469 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package query implements a small language for selecting resources from a stack's state, e.g.
+//
+//	type == aws:ec2/instance:Instance && props.tags.env == "prod"
+//
+// A query is a boolean expression built from comparisons, `&&`, `||`, `!`, and parentheses. The left-hand side of a
+// comparison names a field of a resource: one of `urn`, `type`, `name`, `id`, `parent`, `provider`, `custom`,
+// `protect`, `delete`, or `external`, or a property path beneath `inputs` or `outputs` (for which `props` is a
+// shorthand), such as `outputs.tags["kubernetes.io/name"]` or `inputs.rules[0].port`. The right-hand side is a value:
+// a quoted string, or a bare word, which is compared as a number, a bool, or `null` if the field holds one, and as a
+// string otherwise. The comparison operators are `==` (or `=`), `!=`, `<`, `<=`, `>`, `>=`, and `=~`, which matches
+// a string field against a regular expression. A field that appears on its own is true if it is present and is not
+// null, false, or the empty string.
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+// Query is a parsed query.
+type Query struct {
+	src  string
+	expr node
+}
+
+// Parse parses the given query.
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, t.unexpected()
+	}
+	return &Query{src: src, expr: expr}, nil
+}
+
+// String returns the source of the query.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Matches returns true if the given resource satisfies the query.
+func (q *Query) Matches(res *resource.State) bool {
+	return q.expr.eval(res)
+}
+
+// Select returns the resources that satisfy the query, in their original order.
+func (q *Query) Select(resources []*resource.State) []*resource.State {
+	var selected []*resource.State
+	for _, res := range resources {
+		if q.Matches(res) {
+			selected = append(selected, res)
+		}
+	}
+	return selected
+}
+
+type node interface {
+	eval(res *resource.State) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(res *resource.State) bool { return n.left.eval(res) && n.right.eval(res) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(res *resource.State) bool { return n.left.eval(res) || n.right.eval(res) }
+
+type notNode struct{ expr node }
+
+func (n notNode) eval(res *resource.State) bool { return !n.expr.eval(res) }
+
+// testNode is a field on its own, which is true if the field holds a value other than null, false, or "".
+type testNode struct{ field field }
+
+func (n testNode) eval(res *resource.State) bool {
+	v, has := n.field.value(res)
+	switch {
+	case !has || v.IsNull():
+		return false
+	case v.IsBool():
+		return v.BoolValue()
+	case v.IsString():
+		return v.StringValue() != ""
+	default:
+		return true
+	}
+}
+
+// compareNode compares a field with a value.
+type compareNode struct {
+	field  field
+	op     string
+	value  string
+	quoted bool
+	re     *regexp.Regexp // the compiled value, for `=~`.
+}
+
+func (n compareNode) eval(res *resource.State) bool {
+	v, has := n.field.value(res)
+	switch n.op {
+	case "=~":
+		return has && v.IsString() && n.re.MatchString(v.StringValue())
+	case "==", "=":
+		return n.equals(v, has)
+	case "!=":
+		return !n.equals(v, has)
+	}
+
+	var cmp int
+	switch {
+	case !has:
+		return false
+	case v.IsString():
+		cmp = strings.Compare(v.StringValue(), n.value)
+	case v.IsNumber() && !n.quoted:
+		f, err := strconv.ParseFloat(n.value, 64)
+		if err != nil {
+			return false
+		}
+		switch x := v.NumberValue(); {
+		case x < f:
+			cmp = -1
+		case x > f:
+			cmp = 1
+		}
+	default:
+		return false
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func (n compareNode) equals(v resource.PropertyValue, has bool) bool {
+	if !n.quoted && n.value == "null" {
+		return !has || v.IsNull()
+	}
+	switch {
+	case !has:
+		return false
+	case v.IsString():
+		return v.StringValue() == n.value
+	case v.IsNumber() && !n.quoted:
+		f, err := strconv.ParseFloat(n.value, 64)
+		return err == nil && v.NumberValue() == f
+	case v.IsBool() && !n.quoted:
+		b, err := strconv.ParseBool(n.value)
+		return err == nil && v.BoolValue() == b
+	default:
+		return false
+	}
+}
+
+// field names a value of a resource: either one of its attributes or a property path beneath its inputs or outputs.
+type field struct {
+	name string                // the attribute, or `inputs` or `outputs`.
+	path resource.PropertyPath // the path of the property, if any.
+}
+
+var attributes = map[string]func(res *resource.State) resource.PropertyValue{
+	"urn":      func(res *resource.State) resource.PropertyValue { return str(string(res.URN)) },
+	"type":     func(res *resource.State) resource.PropertyValue { return str(string(res.Type)) },
+	"name":     func(res *resource.State) resource.PropertyValue { return str(string(res.URN.Name())) },
+	"id":       func(res *resource.State) resource.PropertyValue { return str(string(res.ID)) },
+	"parent":   func(res *resource.State) resource.PropertyValue { return str(string(res.Parent)) },
+	"provider": func(res *resource.State) resource.PropertyValue { return str(res.Provider) },
+	"custom":   func(res *resource.State) resource.PropertyValue { return resource.NewBoolProperty(res.Custom) },
+	"protect":  func(res *resource.State) resource.PropertyValue { return resource.NewBoolProperty(res.Protect) },
+	"delete":   func(res *resource.State) resource.PropertyValue { return resource.NewBoolProperty(res.Delete) },
+	"external": func(res *resource.State) resource.PropertyValue { return resource.NewBoolProperty(res.External) },
+}
+
+// str returns a string property for the given attribute, which is null if the attribute is empty.
+func str(s string) resource.PropertyValue {
+	if s == "" {
+		return resource.NewNullProperty()
+	}
+	return resource.NewStringProperty(s)
+}
+
+func parseField(t token) (field, error) {
+	name := t.text
+	if i := strings.IndexAny(name, ".["); i != -1 {
+		name = t.text[:i]
+	}
+	rest := t.text[len(name):]
+
+	switch name {
+	case "props":
+		name = "outputs"
+		fallthrough
+	case "inputs", "outputs":
+		if rest == "" {
+			return field{name: name}, nil
+		}
+		path, err := resource.ParsePropertyPath(strings.TrimPrefix(rest, "."))
+		if err != nil {
+			return field{}, errors.Wrapf(err, "column %d", t.pos+1)
+		}
+		return field{name: name, path: path}, nil
+	}
+	if _, ok := attributes[name]; !ok || rest != "" {
+		return field{}, errors.Errorf("column %d: unknown field '%s'", t.pos+1, t.text)
+	}
+	return field{name: name}, nil
+}
+
+func (f field) value(res *resource.State) (resource.PropertyValue, bool) {
+	var props resource.PropertyMap
+	switch f.name {
+	case "inputs":
+		props = res.Inputs
+	case "outputs":
+		props = res.Outputs
+	default:
+		return attributes[f.name](res), true
+	}
+	if len(f.path) == 0 {
+		return resource.NewObjectProperty(props), true
+	}
+	return f.path.Get(props)
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch t := p.next(); t.kind {
+	case tokNot:
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{expr}, nil
+	case tokLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, t.unexpected()
+		}
+		return expr, nil
+	case tokWord:
+		f, err := parseField(t)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokOp {
+			return testNode{f}, nil
+		}
+		op, value := p.next(), p.next()
+		if value.kind != tokWord && value.kind != tokString {
+			return nil, value.unexpected()
+		}
+		n := compareNode{field: f, op: op.text, value: value.text, quoted: value.kind == tokString}
+		if n.op == "=~" {
+			if n.re, err = regexp.Compile(n.value); err != nil {
+				return nil, errors.Wrapf(err, "column %d", value.pos+1)
+			}
+		}
+		return n, nil
+	default:
+		return nil, t.unexpected()
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) unexpected() error {
+	if t.kind == tokEOF {
+		return errors.New("unexpected end of query")
+	}
+	return errors.Errorf("column %d: unexpected '%s'", t.pos+1, t.text)
+}
+
+// lex splits a query into tokens. Bare words run until whitespace or punctuation, except that brackets within them
+// may hold quoted keys, so that property paths such as `tags["a b"]` are a single word.
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			kind := tokLParen
+			if c == ')' {
+				kind = tokRParen
+			}
+			toks = append(toks, token{kind: kind, text: string(c), pos: i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			toks = append(toks, token{kind: tokAnd, text: "&&", pos: i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			toks = append(toks, token{kind: tokOr, text: "||", pos: i})
+			i += 2
+		case strings.ContainsRune("=!<>", rune(c)):
+			op := string(c)
+			if i+1 < len(src) && (src[i+1] == '=' || (c == '=' && src[i+1] == '~')) {
+				op = src[i : i+2]
+			}
+			kind := tokOp
+			if op == "!" {
+				kind = tokNot
+			}
+			toks = append(toks, token{kind: kind, text: op, pos: i})
+			i += len(op)
+		case c == '"' || c == '\'':
+			s, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "column %d", i+1)
+			}
+			toks = append(toks, token{kind: tokString, text: s, pos: i})
+			i += n
+		case strings.ContainsRune("&|", rune(c)):
+			return nil, errors.Errorf("column %d: unexpected '%c'", i+1, c)
+		default:
+			start := i
+			for i < len(src) && !strings.ContainsRune(" \t\n\r()=!<>&|\"'", rune(src[i])) {
+				if src[i] == '[' {
+					end, err := lexBracket(src, i)
+					if err != nil {
+						return nil, err
+					}
+					i = end
+					continue
+				}
+				i++
+			}
+			toks = append(toks, token{kind: tokWord, text: src[start:i], pos: start})
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// lexString lexes a quoted string at the start of src, returning its value and its length in src. Backslashes escape
+// the character that follows them.
+func lexString(src string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch src[i] {
+		case src[0]:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(src) {
+				i++
+			}
+		}
+		b.WriteByte(src[i])
+	}
+	return "", 0, errors.Errorf("unterminated string %s", src)
+}
+
+// lexBracket returns the offset just past the bracket that closes the one at src[start].
+func lexBracket(src string, start int) (int, error) {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case ']':
+			return i + 1, nil
+		case '"':
+			_, n, err := lexString(src[i:])
+			if err != nil {
+				return 0, errors.Wrapf(err, "column %d", i+1)
+			}
+			i += n - 1
+		}
+	}
+	return 0, errors.Errorf("column %d: missing ']'", start+1)
+}
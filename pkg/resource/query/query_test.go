@@ -0,0 +1,103 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func newResource(typ tokens.Type, name string, protect bool, outputs map[string]interface{}) *resource.State {
+	urn := resource.NewURN("test", "test", "", typ, tokens.QName(name))
+	return &resource.State{
+		Type:    typ,
+		URN:     urn,
+		Custom:  true,
+		ID:      resource.ID(name + "-id"),
+		Inputs:  resource.PropertyMap{},
+		Outputs: resource.NewPropertyMapFromMap(outputs),
+		Protect: protect,
+	}
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	web := newResource("aws:ec2/instance:Instance", "web", true, map[string]interface{}{
+		"instanceType": "t2.large",
+		"cpus":         4,
+		"tags":         map[string]interface{}{"env": "prod", "kubernetes.io/name": "web"},
+	})
+	worker := newResource("aws:ec2/instance:Instance", "worker", false, map[string]interface{}{
+		"instanceType": "t2.micro",
+		"cpus":         1,
+		"tags":         map[string]interface{}{"env": "dev"},
+	})
+	bucket := newResource("aws:s3/bucket:Bucket", "logs", false, map[string]interface{}{
+		"versioning": map[string]interface{}{"enabled": true},
+	})
+	resources := []*resource.State{web, worker, bucket}
+
+	tests := []struct {
+		query    string
+		expected []*resource.State
+	}{
+		{`type=aws:ec2/instance:Instance && props.tags.env=="prod"`, []*resource.State{web}},
+		{`type == aws:ec2/instance:Instance && !protect`, []*resource.State{worker}},
+		{`props.tags.env != prod`, []*resource.State{worker, bucket}},
+		{`props.tags.env == null`, []*resource.State{bucket}},
+		{`outputs.tags["kubernetes.io/name"] == 'web'`, []*resource.State{web}},
+		{`props.cpus >= 2 || props.versioning.enabled == true`, []*resource.State{web, bucket}},
+		{`props.cpus < 2`, []*resource.State{worker}},
+		{`props.cpus == "4"`, nil},
+		{`props.instanceType =~ "^t2\\.m" || (name == logs && props.versioning.enabled)`,
+			[]*resource.State{worker, bucket}},
+		{`id == web-id`, []*resource.State{web}},
+		{`props.tags`, []*resource.State{web, worker}},
+		{`parent`, nil},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if !assert.NoError(t, err, test.query) {
+			continue
+		}
+		assert.Equal(t, test.expected, q.Select(resources), test.query)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{`colour == red`, "column 1: unknown field 'colour'"},
+		{`type == `, "unexpected end of query"},
+		{`type == a b`, "column 11: unexpected 'b'"},
+		{`(type == a`, "unexpected end of query"},
+		{`type & a`, "column 6: unexpected '&'"},
+		{`name == "web`, "column 9: unterminated string \"web"},
+		{`props.tags[0`, "column 11: missing ']'"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.query)
+		assert.EqualError(t, err, test.err, test.query)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// RedactionPolicy decides which property values are sensitive, so that logs, events, and printed plans can show
+// digests of them (see RedactValue) rather than the values themselves.
+type RedactionPolicy interface {
+	// Sensitive returns true if the value at the given path within the properties of a resource of the given type is
+	// sensitive.  The type is empty if it is not known, as it is when properties are logged on their way to or from a
+	// provider.
+	Sensitive(t tokens.Type, path PropertyPath) bool
+}
+
+// RedactionPolicies combines several policies into one that treats a value as sensitive if any of them does.
+type RedactionPolicies []RedactionPolicy
+
+// Sensitive returns true if any of the policies considers the value at the given path sensitive.
+func (ps RedactionPolicies) Sensitive(t tokens.Type, path PropertyPath) bool {
+	for _, p := range ps {
+		if p != nil && p.Sensitive(t, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyPatternPolicy treats values as sensitive if a key along their path matches one of a set of glob patterns, such as
+// `*password*`.  A pattern is also matched against the whole path (e.g. `db.*`), so it may single out keys that are
+// only sensitive in some places.  Matching ignores case, and a `*` matches any run of characters, including none.
+type KeyPatternPolicy struct {
+	patterns []*regexp.Regexp
+}
+
+// NewKeyPatternPolicy returns a policy that treats values as sensitive if a key along their path, or the path as a
+// whole, matches one of the given glob patterns.
+func NewKeyPatternPolicy(patterns ...string) (*KeyPatternPolicy, error) {
+	policy := &KeyPatternPolicy{}
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return nil, errors.New("redaction patterns must not be empty")
+		}
+		expr := "(?i)^" + strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1) + "$"
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid redaction pattern '%v'", pattern)
+		}
+		policy.patterns = append(policy.patterns, re)
+	}
+	return policy, nil
+}
+
+// Sensitive returns true if a key along the path, or the path as a whole, matches one of the policy's patterns.
+func (p *KeyPatternPolicy) Sensitive(t tokens.Type, path PropertyPath) bool {
+	if len(path) == 0 {
+		return false
+	}
+	s := path.String()
+	for _, re := range p.patterns {
+		if re.MatchString(s) {
+			return true
+		}
+		for _, elem := range path {
+			if key, ok := elem.(string); ok && re.MatchString(key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SensitivePaths is a policy that treats the values at declared paths, and everything nested within them, as
+// sensitive.  Providers declare these paths for the resource types that they manage (see schema.Type.SensitivePaths);
+// the paths listed under the empty type apply to resources of every type.
+type SensitivePaths map[tokens.Type][]PropertyPath
+
+// Sensitive returns true if one of the paths declared for the given type, or for every type, is a prefix of the path.
+func (ps SensitivePaths) Sensitive(t tokens.Type, path PropertyPath) bool {
+	return hasPathPrefix(ps[t], path) || (t != "" && hasPathPrefix(ps[""], path))
+}
+
+func hasPathPrefix(prefixes []PropertyPath, path PropertyPath) bool {
+	for _, prefix := range prefixes {
+		if len(prefix) == 0 || len(prefix) > len(path) {
+			continue
+		}
+		matches := true
+		for i := range prefix {
+			if prefix[i] != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of the map, as the properties of a resource of the given type, in which each value that the
+// policy considers sensitive has been replaced by its digest (see RedactValue).  Null and unknown values reveal
+// nothing and are left as they are.  Secrets are always redacted, whatever the policy; the map itself is returned if
+// the policy is nil and the map holds no secrets.
+func (m PropertyMap) Redacted(t tokens.Type, policy RedactionPolicy) PropertyMap {
+	if m == nil || policy == nil && !m.ContainsSecrets() {
+		return m
+	}
+	return redactObject(t, policy, nil, m)
+}
+
+func redactObject(t tokens.Type, policy RedactionPolicy, path PropertyPath, m PropertyMap) PropertyMap {
+	result := make(PropertyMap, len(m))
+	for k, v := range m {
+		result[k] = redactValue(t, policy, append(path[:len(path):len(path)], string(k)), v)
+	}
+	return result
+}
+
+func redactValue(t tokens.Type, policy RedactionPolicy, path PropertyPath, v PropertyValue) PropertyValue {
+	switch {
+	case v.IsNull() || v.IsComputed() || v.IsOutput():
+		return v
+	case v.IsSecret():
+		if elem := v.SecretValue().Element; elem.IsNull() || elem.ContainsUnknowns() {
+			return v
+		}
+		return RedactValue(v)
+	case policy != nil && policy.Sensitive(t, path):
+		return RedactValue(v)
+	case v.IsObject():
+		return NewObjectProperty(redactObject(t, policy, path, v.ObjectValue()))
+	case v.IsArray():
+		arr := make([]PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			arr[i] = redactValue(t, policy, append(path[:len(path):len(path)], i), elem)
+		}
+		return NewArrayProperty(arr)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPatternPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NewKeyPatternPolicy("*password*", "db.user")
+	assert.NoError(t, err)
+
+	assert.True(t, policy.Sensitive("", PropertyPath{"password"}))
+	assert.True(t, policy.Sensitive("", PropertyPath{"adminPassword"}))
+	assert.True(t, policy.Sensitive("", PropertyPath{"users", 0, "PASSWORD_HASH"}))
+	assert.True(t, policy.Sensitive("", PropertyPath{"db", "user"}))
+	assert.False(t, policy.Sensitive("", PropertyPath{"user"}))
+	assert.False(t, policy.Sensitive("", PropertyPath{"db", "name"}))
+	assert.False(t, policy.Sensitive("", nil))
+
+	_, err = NewKeyPatternPolicy("")
+	assert.Error(t, err)
+}
+
+func TestSensitivePaths(t *testing.T) {
+	t.Parallel()
+
+	policy := SensitivePaths{
+		"aws:rds/instance:Instance": {{"masterPassword"}},
+		"":                          {{"connection", "key"}},
+	}
+
+	assert.True(t, policy.Sensitive("aws:rds/instance:Instance", PropertyPath{"masterPassword"}))
+	assert.False(t, policy.Sensitive("aws:s3/bucket:Bucket", PropertyPath{"masterPassword"}))
+	assert.True(t, policy.Sensitive("aws:s3/bucket:Bucket", PropertyPath{"connection", "key", "pem"}))
+	assert.False(t, policy.Sensitive("aws:s3/bucket:Bucket", PropertyPath{"connection"}))
+}
+
+func TestRedacted(t *testing.T) {
+	t.Parallel()
+
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"name":     "db",
+		"password": "hunter2",
+		"users": []interface{}{
+			map[string]interface{}{"name": "admin", "password": "swordfish"},
+		},
+		"token": nil,
+	})
+	m["apiKey"] = MakeComputed(NewStringProperty(""))
+
+	policies := RedactionPolicies{SensitivePaths{"": {{"apiKey"}, {"token"}}}}
+	keys, err := NewKeyPatternPolicy("*password*")
+	assert.NoError(t, err)
+	policies = append(policies, keys)
+
+	redacted := m.Redacted("pkg:index:Database", policies)
+	assert.Equal(t, m["name"], redacted["name"])
+	assert.Equal(t, RedactValue(m["password"]), redacted["password"])
+	users := redacted["users"].ArrayValue()
+	assert.Equal(t, NewStringProperty("admin"), users[0].ObjectValue()["name"])
+	assert.Equal(t, RedactValue(NewStringProperty("swordfish")), users[0].ObjectValue()["password"])
+	assert.True(t, redacted["token"].IsNull())
+	assert.True(t, redacted["apiKey"].IsComputed())
+
+	// The original map is left as it was.
+	assert.Equal(t, NewStringProperty("hunter2"), m["password"])
+	assert.Equal(t, m, m.Redacted("pkg:index:Database", nil))
+}
+
+func TestRedactedSecrets(t *testing.T) {
+	t.Parallel()
+
+	m := PropertyMap{
+		"name":     NewStringProperty("db"),
+		"password": MakeSecret(NewStringProperty("hunter2")),
+		"nested": NewObjectProperty(PropertyMap{
+			"key": MakeSecret(NewStringProperty("swordfish")),
+		}),
+		"pending": MakeSecret(MakeComputed(NewStringProperty(""))),
+	}
+	assert.True(t, m.ContainsSecrets())
+	assert.False(t, PropertyMap{"name": NewStringProperty("db")}.ContainsSecrets())
+
+	// Secrets are redacted even without a policy.
+	redacted := m.Redacted("pkg:index:Database", nil)
+	assert.Equal(t, m["name"], redacted["name"])
+	assert.Equal(t, RedactValue(NewStringProperty("hunter2")), redacted["password"])
+	assert.Equal(t, RedactValue(NewStringProperty("swordfish")), redacted["nested"].ObjectValue()["key"])
+	assert.True(t, redacted["pending"].IsSecret())
+	assert.NotContains(t, fmt.Sprintf("%v", m), "hunter2")
+	assert.NotContains(t, m["password"].String(), "hunter2")
+}
@@ -32,12 +32,21 @@ type Goal struct {
 	InitErrors           []string              // errors encountered as we attempted to initialize the resource.
 	PropertyDependencies map[PropertyKey][]URN // the set of dependencies that affect each property.
 	DeleteBeforeReplace  bool                  // true if this resource should be deleted prior to replacement.
+	RetainOnDelete       bool                  // true if deleting this resource should only remove it from the state.
+	Aliases              []URN                 // additional URNs that should be treated as this resource's URN.
+	IgnoreChanges        []string              // a list of property paths whose changes should be ignored.
+	ReplaceOnChanges     []string              // a list of property paths whose changes should force a replacement.
+	ReadyConditions      []string              // a list of conditions on the outputs that signal readiness.
+	CustomTimeouts       CustomTimeouts        // the timeouts for provider operations on this resource.
+	PropertyReads        PropertyReads         // the outputs of other resources that each property was computed from.
 }
 
 // NewGoal allocates a new resource goal state.
 func NewGoal(t tokens.Type, name tokens.QName, custom bool, props PropertyMap,
 	parent URN, protect bool, dependencies []URN, provider string, initErrors []string,
-	propertyDependencies map[PropertyKey][]URN, deleteBeforeReplace bool) *Goal {
+	propertyDependencies map[PropertyKey][]URN, deleteBeforeReplace bool, retainOnDelete bool,
+	aliases []URN, ignoreChanges []string, replaceOnChanges []string, readyConditions []string,
+	customTimeouts CustomTimeouts, propertyReads PropertyReads) *Goal {
 
 	return &Goal{
 		Type:                 t,
@@ -51,5 +60,12 @@ func NewGoal(t tokens.Type, name tokens.QName, custom bool, props PropertyMap,
 		InitErrors:           initErrors,
 		PropertyDependencies: propertyDependencies,
 		DeleteBeforeReplace:  deleteBeforeReplace,
+		RetainOnDelete:       retainOnDelete,
+		Aliases:              aliases,
+		IgnoreChanges:        ignoreChanges,
+		ReplaceOnChanges:     replaceOnChanges,
+		ReadyConditions:      readyConditions,
+		CustomTimeouts:       customTimeouts,
+		PropertyReads:        propertyReads,
 	}
 }
@@ -17,6 +17,9 @@ package resource
 import (
 	cryptorand "crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -59,6 +62,79 @@ func MaybeID(s *string) *ID {
 	return ret
 }
 
+// CompositeIDSeparator is the separator between the fields of a composite ID whose format does not choose another.
+const CompositeIDSeparator = "/"
+
+// IDFormat describes a provider-defined composite ID: an ID made of several named fields joined by a separator.  Each
+// field is URL-escaped before it is joined, so fields may themselves contain the separator, and an ID whose separator
+// is URL-safe may be used as-is in a URL.
+type IDFormat struct {
+	Fields    []string // the names of the ID's fields, in order.
+	Separator string   // the separator between fields; defaults to CompositeIDSeparator.
+}
+
+func (f IDFormat) separator() (string, error) {
+	switch {
+	case f.Separator == "":
+		return CompositeIDSeparator, nil
+	case strings.Contains(f.Separator, "%"):
+		return "", errors.Errorf("composite ID separator %q may not contain '%%'", f.Separator)
+	default:
+		return f.Separator, nil
+	}
+}
+
+// Format builds an ID out of the given field values, which must be supplied in the same order as the format's fields.
+func (f IDFormat) Format(values ...string) (ID, error) {
+	sep, err := f.separator()
+	if err != nil {
+		return "", err
+	}
+	if len(values) != len(f.Fields) {
+		return "", errors.Errorf("composite ID requires %d fields (%s); got %d",
+			len(f.Fields), strings.Join(f.Fields, ", "), len(values))
+	}
+
+	// url.PathEscape escapes most separators, but not all of them (e.g. ':').  Escape every character of the separator
+	// so that it can never appear within a field, even when the separator is more than one character long.
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		e := url.PathEscape(v)
+		for _, c := range sep {
+			var pct string
+			for _, b := range []byte(string(c)) {
+				pct += fmt.Sprintf("%%%02X", b)
+			}
+			e = strings.Replace(e, string(c), pct, -1)
+		}
+		escaped[i] = e
+	}
+	return ID(strings.Join(escaped, sep)), nil
+}
+
+// Parse splits an ID into its fields, returning a map from each field's name to its value.
+func (f IDFormat) Parse(id ID) (map[string]string, error) {
+	sep, err := f.separator()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(string(id), sep)
+	if len(parts) != len(f.Fields) {
+		return nil, errors.Errorf("ID %q has %d fields; expected %d (%s)",
+			id, len(parts), len(f.Fields), strings.Join(f.Fields, ", "))
+	}
+	fields := make(map[string]string, len(parts))
+	for i, part := range parts {
+		v, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s of ID %q", f.Fields[i], id)
+		}
+		fields[f.Fields[i]] = v
+	}
+	return fields, nil
+}
+
 // NewUniqueHex generates a new "random" hex string for use by resource providers. It will take the optional prefix
 // and append randlen random characters (defaulting to 8 if not > 0).  The result must not exceed maxlen total
 // characterss (if > 0).  Note that capping to maxlen necessarily increases the risk of collisions.
@@ -86,3 +86,37 @@ func TestNewUniqueDefaultsID(t *testing.T) {
 	assert.Equal(t, len(prefix)+8, len(id))
 	assert.Equal(t, true, strings.HasPrefix(string(id), prefix))
 }
+
+func TestIDFormat(t *testing.T) {
+	f := IDFormat{Fields: []string{"account", "name"}}
+
+	id, err := f.Format("123", "my bucket/logs")
+	assert.NoError(t, err)
+	assert.Equal(t, ID("123/my%20bucket%2Flogs"), id)
+
+	fields, err := f.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"account": "123", "name": "my bucket/logs"}, fields)
+
+	_, err = f.Format("123")
+	assert.Error(t, err)
+	_, err = f.Parse("123/a/b")
+	assert.Error(t, err)
+	_, err = f.Parse("123/%zz")
+	assert.Error(t, err)
+}
+
+func TestIDFormatSeparator(t *testing.T) {
+	f := IDFormat{Fields: []string{"a", "b", "c"}, Separator: "::"}
+
+	id, err := f.Format("x:", ":y", "")
+	assert.NoError(t, err)
+	assert.Equal(t, ID("x%3A::%3Ay::"), id)
+
+	fields, err := f.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "x:", "b": ":y", "c": ""}, fields)
+
+	_, err = IDFormat{Fields: []string{"a"}, Separator: "%"}.Format("x")
+	assert.Error(t, err)
+}
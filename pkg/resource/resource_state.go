@@ -39,13 +39,21 @@ type State struct {
 	Provider             string                // the provider to use for this resource.
 	PropertyDependencies map[PropertyKey][]URN // the set of dependencies that affect each property.
 	PendingReplacement   bool                  // true if this resource was deleted and is awaiting replacement.
+	RetainOnDelete       bool                  // true if deleting this resource should only remove it from the state.
+	Defaults             []PropertyKey         // the input properties whose values were defaulted by the provider.
+	CustomTimeouts       CustomTimeouts        // the timeouts for provider operations on this resource.
+	PropertyReads        PropertyReads         // the outputs of other resources that each property was computed from.
+	Provenance           PropertyProvenance    // the provider operations that last set each output property.
+	InputHashes          PropertyHashes        // the fingerprints of the inputs and their large subtrees, if known.
 }
 
 // NewState creates a new resource value from existing resource state information.
 func NewState(t tokens.Type, urn URN, custom bool, del bool, id ID,
 	inputs PropertyMap, outputs PropertyMap, parent URN, protect bool,
 	external bool, dependencies []URN, initErrors []string, provider string,
-	propertyDependencies map[PropertyKey][]URN, pendingReplacement bool) *State {
+	propertyDependencies map[PropertyKey][]URN, pendingReplacement bool, retainOnDelete bool,
+	defaults []PropertyKey, customTimeouts CustomTimeouts, propertyReads PropertyReads,
+	provenance PropertyProvenance) *State {
 
 	contract.Assertf(t != "", "type was empty")
 	contract.Assertf(custom || id == "", "is custom or had empty ID")
@@ -66,6 +74,11 @@ func NewState(t tokens.Type, urn URN, custom bool, del bool, id ID,
 		Provider:             provider,
 		PropertyDependencies: propertyDependencies,
 		PendingReplacement:   pendingReplacement,
+		RetainOnDelete:       retainOnDelete,
+		Defaults:             defaults,
+		CustomTimeouts:       customTimeouts,
+		PropertyReads:        propertyReads,
+		Provenance:           provenance,
 	}
 }
 
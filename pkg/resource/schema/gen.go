@@ -0,0 +1,259 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// GenerateGo generates the source of a Go package with the given name that declares a struct for the inputs and for
+// the outputs of each of the package's resources, e.g. BucketInputs and BucketOutputs for "aws:s3/bucket:Bucket".
+// Each field is tagged with its property key, so that the structs can be used with the mapper package, and optional
+// properties are tagged as such.  Nested objects are declared as structs of their own, named for the struct and field
+// that hold them.  It is an error for two structs, or two fields of the same struct, to be given the same name.
+func GenerateGo(pkg *Package, goPackage string) ([]byte, error) {
+	if !isGoIdentifier(goPackage) {
+		return nil, errors.Errorf("'%s' is not a valid Go package name", goPackage)
+	}
+
+	toks := make([]string, 0, len(pkg.Resources))
+	for tok := range pkg.Resources {
+		toks = append(toks, string(tok))
+	}
+	sort.Strings(toks)
+
+	g := &goGenerator{origins: make(map[string]string)}
+	for _, tok := range toks {
+		res := pkg.Resources[tokens.Type(tok)]
+		name := goName(string(tokens.Type(tok).Name()))
+		if name == "" {
+			return nil, errors.Errorf("resource '%s' cannot be named in Go", tok)
+		}
+		for _, s := range []struct {
+			suffix, what string
+			t            *Type
+		}{{"Inputs", "inputs", res.Inputs}, {"Outputs", "outputs", res.Outputs}} {
+			if s.t == nil {
+				continue
+			}
+			doc := fmt.Sprintf("%s are the %s of resources of type %s.", name+s.suffix, s.what, tok)
+			if err := g.genStruct(name+s.suffix, tok, joinDocs(doc, res.Description), s.t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	version := pkg.Name
+	if pkg.Version != "" {
+		version += " " + pkg.Version
+	}
+	fmt.Fprintf(&buf, "// Code generated by pulumi plugin gen from the schema of %s; DO NOT EDIT.\n\n", version)
+	fmt.Fprintf(&buf, "package %s\n", goPackage)
+	for _, decl := range g.decls {
+		buf.WriteString("\n")
+		buf.WriteString(decl)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "formatting generated code")
+	}
+	return src, nil
+}
+
+// goGenerator accumulates the declarations of a generated package.
+type goGenerator struct {
+	decls   []string          // the declarations, in the order in which they are emitted.
+	origins map[string]string // the resource for which each declared struct was generated.
+}
+
+// genStruct declares a struct for the given object type, along with structs for the objects nested within it.  The
+// struct precedes those nested within it.
+func (g *goGenerator) genStruct(name, origin, doc string, t *Type) error {
+	if other, has := g.origins[name]; has {
+		return errors.Errorf("resources '%s' and '%s' would both declare the struct %s", other, origin, name)
+	}
+	g.origins[name] = origin
+	if t.Kind != ObjectKind {
+		return errors.Errorf("%s must describe an object, not %v", name, t.Kind)
+	}
+
+	// Reserve this struct's place before generating any structs nested within it.
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	keys := make([]string, 0, len(t.Properties))
+	for k := range t.Properties {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	required := make(map[resource.PropertyKey]bool, len(t.Required))
+	for _, k := range t.Required {
+		required[k] = true
+	}
+
+	var buf bytes.Buffer
+	writeDoc(&buf, "", doc)
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	fields := make(map[string]string, len(keys))
+	for _, key := range keys {
+		field := goName(key)
+		if field == "" || strings.ContainsAny(key, ",\"`") {
+			return errors.Errorf("property '%s' of %s cannot be named in Go", key, name)
+		}
+		if other, has := fields[field]; has {
+			return errors.Errorf("properties '%s' and '%s' of %s would both be named %s", other, key, name, field)
+		}
+		fields[field] = key
+
+		prop := t.Properties[resource.PropertyKey(key)]
+		optional := !required[resource.PropertyKey(key)]
+		doc := fmt.Sprintf("%s describes the %s property of %s.", name+field, key, name)
+		typ, err := g.goType(name+field, origin, doc, prop, optional)
+		if err != nil {
+			return err
+		}
+
+		tag := key
+		if optional {
+			tag += ",optional"
+		}
+		writeDoc(&buf, "\t", fieldDoc(prop))
+		fmt.Fprintf(&buf, "\t%s %s `pulumi:\"%s\"`\n", field, typ, tag)
+	}
+	buf.WriteString("}\n")
+
+	g.decls[index] = buf.String()
+	return nil
+}
+
+// goType returns the Go type of values of the given type, declaring a struct with the given name and documentation if
+// it is an object with known properties.  Optional values whose types have no zero value of their own are pointers.
+func (g *goGenerator) goType(name, origin, doc string, t *Type, optional bool) (string, error) {
+	var typ string
+	pointable := true
+	switch {
+	case t == nil:
+		return "interface{}", nil
+	case t.Kind == BoolKind:
+		typ = "bool"
+	case t.Kind == NumberKind:
+		typ = "float64"
+	case t.Kind == StringKind || t.Kind == EnumKind || t.Kind == JSONKind:
+		typ = "string"
+	case t.Kind == ArrayKind:
+		elem, err := g.goType(name, origin, doc, t.Elements, false)
+		if err != nil {
+			return "", err
+		}
+		typ, pointable = "[]"+elem, false
+	case t.Kind == ObjectKind && len(t.Properties) > 0:
+		if err := g.genStruct(name, origin, joinDocs(doc, t.Description), t); err != nil {
+			return "", err
+		}
+		typ = name
+	case t.Kind == ObjectKind:
+		typ, pointable = "map[string]interface{}", false
+	default:
+		typ, pointable = "interface{}", false
+	}
+	if optional && pointable {
+		typ = "*" + typ
+	}
+	return typ, nil
+}
+
+// fieldDoc returns the documentation of a field of the given type.
+func fieldDoc(t *Type) string {
+	if t == nil {
+		return ""
+	}
+	doc := t.Description
+	if t.Kind == EnumKind && len(t.Values) > 0 {
+		values := make([]string, len(t.Values))
+		for i, v := range t.Values {
+			values[i] = fmt.Sprintf("%q", v)
+		}
+		doc = joinDocs(doc, fmt.Sprintf("One of %s.", strings.Join(values, ", ")))
+	}
+	return doc
+}
+
+// joinDocs joins the non-empty documentation paragraphs.
+func joinDocs(docs ...string) string {
+	var paras []string
+	for _, doc := range docs {
+		if doc = strings.TrimSpace(doc); doc != "" {
+			paras = append(paras, doc)
+		}
+	}
+	return strings.Join(paras, "\n\n")
+}
+
+// writeDoc writes the given documentation as a comment, indented by the given prefix.
+func writeDoc(buf *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if line = strings.TrimRightFunc(line, unicode.IsSpace); line == "" {
+			fmt.Fprintf(buf, "%s//\n", indent)
+		} else {
+			fmt.Fprintf(buf, "%s// %s\n", indent, line)
+		}
+	}
+}
+
+// goName returns the exported Go name for the given key, or "" if it has none.  Characters that cannot appear in Go
+// identifiers are dropped, and names that would begin with a digit are prefixed with P.
+func goName(key string) string {
+	var b strings.Builder
+	for _, r := range resource.PascalCase.Convert(key) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_")
+	switch {
+	case name == "":
+		return ""
+	case unicode.IsDigit([]rune(name)[0]):
+		return "P" + name
+	case !unicode.IsUpper([]rune(name)[0]):
+		return ""
+	}
+	return name
+}
+
+// isGoIdentifier returns true if the given name is a valid Go identifier.
+func isGoIdentifier(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,124 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+var bucketPackage = &Package{
+	Name:    "test",
+	Version: "1.0.0",
+	Resources: map[tokens.Type]*Resource{
+		"test:storage:Bucket": {
+			Description: "A Bucket holds objects.",
+			Inputs: &Type{
+				Kind: ObjectKind,
+				Properties: map[resource.PropertyKey]*Type{
+					"bucketName": {Kind: StringKind},
+					"acl":        {Kind: EnumKind, Values: []string{"private", "public-read"}},
+					"maxSize":    {Kind: NumberKind, Description: "The largest object size, in bytes."},
+					"tags":       {Kind: ObjectKind},
+					"lifecycle_rules": {Kind: ArrayKind, Elements: &Type{
+						Kind: ObjectKind,
+						Properties: map[resource.PropertyKey]*Type{
+							"prefix":         {Kind: StringKind},
+							"expirationDays": {Kind: NumberKind},
+						},
+						Required: []resource.PropertyKey{"prefix"},
+					}},
+					"versioning": {Kind: ObjectKind, Properties: map[resource.PropertyKey]*Type{
+						"enabled": {Kind: BoolKind},
+					}},
+				},
+				Required: []resource.PropertyKey{"bucketName"},
+			},
+			Outputs: &Type{
+				Kind:       ObjectKind,
+				Properties: map[resource.PropertyKey]*Type{"arn": {Kind: StringKind}},
+				Required:   []resource.PropertyKey{"arn"},
+			},
+		},
+	},
+}
+
+func TestGenerateGo(t *testing.T) {
+	src, err := GenerateGo(bucketPackage, "storage")
+	assert.NoError(t, err)
+	// Backquotes are written as single quotes, so that the expected source can be a raw string.
+	assert.Equal(t, strings.Replace(`// Code generated by pulumi plugin gen from the schema of test 1.0.0; DO NOT EDIT.
+
+package storage
+
+// BucketInputs are the inputs of resources of type test:storage:Bucket.
+//
+// A Bucket holds objects.
+type BucketInputs struct {
+	// One of "private", "public-read".
+	Acl            *string                      'pulumi:"acl,optional"'
+	BucketName     string                       'pulumi:"bucketName"'
+	LifecycleRules []BucketInputsLifecycleRules 'pulumi:"lifecycle_rules,optional"'
+	// The largest object size, in bytes.
+	MaxSize    *float64                'pulumi:"maxSize,optional"'
+	Tags       map[string]interface{}  'pulumi:"tags,optional"'
+	Versioning *BucketInputsVersioning 'pulumi:"versioning,optional"'
+}
+
+// BucketInputsLifecycleRules describes the lifecycle_rules property of BucketInputs.
+type BucketInputsLifecycleRules struct {
+	ExpirationDays *float64 'pulumi:"expirationDays,optional"'
+	Prefix         string   'pulumi:"prefix"'
+}
+
+// BucketInputsVersioning describes the versioning property of BucketInputs.
+type BucketInputsVersioning struct {
+	Enabled *bool 'pulumi:"enabled,optional"'
+}
+
+// BucketOutputs are the outputs of resources of type test:storage:Bucket.
+//
+// A Bucket holds objects.
+type BucketOutputs struct {
+	Arn string 'pulumi:"arn"'
+}
+`, "'", "`", -1), string(src))
+}
+
+func TestGenerateGoNameCollisions(t *testing.T) {
+	object := &Type{Kind: ObjectKind}
+
+	// Resources of the same name in different modules would declare the same structs.
+	_, err := GenerateGo(&Package{Name: "test", Resources: map[tokens.Type]*Resource{
+		"test:a:Thing": {Inputs: object},
+		"test:b:Thing": {Inputs: object},
+	}}, "test")
+	assert.EqualError(t, err, "resources 'test:a:Thing' and 'test:b:Thing' would both declare the struct ThingInputs")
+
+	// Keys that differ only in their conventions would be given the same field names.
+	_, err = GenerateGo(&Package{Name: "test", Resources: map[tokens.Type]*Resource{
+		"test:index:Thing": {Inputs: &Type{Kind: ObjectKind, Properties: map[resource.PropertyKey]*Type{
+			"instanceType":  {Kind: StringKind},
+			"instance_type": {Kind: StringKind},
+		}}},
+	}}, "test")
+	assert.EqualError(t, err,
+		"properties 'instanceType' and 'instance_type' of ThingInputs would both be named InstanceType")
+}
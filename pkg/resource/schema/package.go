@@ -0,0 +1,88 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// FormatVersion is the version of the schema format that this package reads and writes.  Providers are asked for a
+// particular version of the format through the GetSchema RPC, and refuse versions that they do not know.
+const FormatVersion = 1
+
+// Package describes the resource types that a provider manages.  Providers publish their packages, encoded as JSON,
+// through the GetSchema RPC, and GenerateGo turns them into typed structs for the programs and tools that consume
+// their resources.
+type Package struct {
+	// Name is the name of the provider's package, e.g. "aws".
+	Name string `json:"name"`
+	// Version is the optional version of the provider that the schema describes.
+	Version string `json:"version,omitempty"`
+	// Resources maps the type token of each of the provider's resource types, e.g. "aws:s3/bucket:Bucket", to its
+	// description.
+	Resources map[tokens.Type]*Resource `json:"resources,omitempty"`
+}
+
+// Resource describes a single resource type.
+type Resource struct {
+	// Description is optional documentation for the resource type.
+	Description string `json:"description,omitempty"`
+	// Inputs is the object type of the resource's inputs.
+	Inputs *Type `json:"inputs,omitempty"`
+	// Outputs is the object type of the resource's outputs.
+	Outputs *Type `json:"outputs,omitempty"`
+	// ReadyConditions are the conditions on the outputs of resources of this type that must hold before they are
+	// considered ready, such as "status.phase == Running".  They apply to the resources that register no ready
+	// conditions of their own.
+	ReadyConditions []string `json:"readyConditions,omitempty"`
+	// ReadyTimeout is the number of seconds that resources of this type may take to satisfy their ready conditions,
+	// unless the update sets a timeout of its own.  If it is zero, the engine's default timeout applies.
+	ReadyTimeout float64 `json:"readyTimeout,omitempty"`
+}
+
+// Marshal encodes the package as JSON, in the current version of the schema format.
+func (pkg *Package) Marshal() ([]byte, error) {
+	return json.Marshal(pkg)
+}
+
+// ParsePackage decodes a package that was encoded as JSON.
+func ParsePackage(data []byte) (*Package, error) {
+	var pkg Package
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, errors.Wrap(err, "parsing schema")
+	}
+	if pkg.Name == "" {
+		return nil, errors.New("parsing schema: missing package name")
+	}
+	for tok, res := range pkg.Resources {
+		if _, err := tokens.ParseTypeToken(string(tok)); err != nil {
+			return nil, errors.Wrap(err, "parsing schema")
+		}
+		if res == nil {
+			return nil, errors.Errorf("parsing schema: resource '%s' has no description", tok)
+		}
+		for _, t := range []*Type{res.Inputs, res.Outputs} {
+			if t != nil && t.Kind != ObjectKind {
+				return nil, errors.Errorf("parsing schema: properties of resource '%s' must be an object, not %v",
+					tok, t.Kind)
+			}
+		}
+	}
+	return &pkg, nil
+}
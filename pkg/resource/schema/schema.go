@@ -0,0 +1,358 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema describes the expected shapes of resource properties, so that providers written in Go can validate
+// their inputs during Check. Values that do not match their type exactly but can be converted without loss -- such as
+// the string "5" given for a number, or "ENABLED" given for an enum whose value is "Enabled" -- are coerced, and each
+// coercion is recorded so that it can be reported alongside any check failures.
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+// Kind identifies the shape of a property value.
+type Kind int
+
+const (
+	// AnyKind accepts any value.
+	AnyKind Kind = iota
+	// BoolKind accepts booleans, and coerces strings such as "true" and "False" without regard to case.
+	BoolKind
+	// NumberKind accepts numbers, and coerces strings that parse as numbers.
+	NumberKind
+	// StringKind accepts strings, and coerces numbers and booleans to their textual forms.
+	StringKind
+	// ArrayKind accepts arrays whose elements all match the type's Elements.
+	ArrayKind
+	// ObjectKind accepts objects whose properties match the type's Properties.
+	ObjectKind
+	// EnumKind accepts one of the type's Values, matched without regard to case.
+	EnumKind
+	// UnionKind accepts a value that matches any one of the type's OneOf alternatives.
+	UnionKind
+	// JSONKind accepts strings that hold JSON documents, and turns them into JSON values so that they are compared by
+	// the documents they hold rather than by their text.
+	JSONKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case AnyKind:
+		return "any"
+	case BoolKind:
+		return "bool"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return "array"
+	case ObjectKind:
+		return "object"
+	case EnumKind:
+		return "enum"
+	case UnionKind:
+		return "union"
+	case JSONKind:
+		return "json"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// MarshalText encodes the kind as its name, e.g. "number".
+func (k Kind) MarshalText() ([]byte, error) {
+	if k < AnyKind || k > JSONKind {
+		return nil, errors.Errorf("unknown kind %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a kind from its name.
+func (k *Kind) UnmarshalText(text []byte) error {
+	for kind := AnyKind; kind <= JSONKind; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return errors.Errorf("unknown kind '%s'", text)
+}
+
+// Type describes the expected shape of a property value. Unknown values are accepted for every type, and nulls are
+// accepted unless an object requires the property that holds them.
+type Type struct {
+	// Kind is the kind of value this type accepts.
+	Kind Kind `json:"kind"`
+	// Description is optional documentation for values of this type, which generated code carries along.
+	Description string `json:"description,omitempty"`
+	// Elements is, for arrays, the type of each element (nil for any).
+	Elements *Type `json:"elements,omitempty"`
+	// Properties are, for objects, the types of known properties; others are unchecked.
+	Properties map[resource.PropertyKey]*Type `json:"properties,omitempty"`
+	// Required lists, for objects, the properties that must be present.
+	Required []resource.PropertyKey `json:"required,omitempty"`
+	// Values are, for enums, the permitted values in their canonical case.
+	Values []string `json:"values,omitempty"`
+	// OneOf holds, for unions, the alternatives, which are tried in order.
+	OneOf []*Type `json:"oneOf,omitempty"`
+	// Secret is true if values of this type are sensitive and must not be revealed.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// SensitivePaths returns the paths to the secret values of this object type, so that a provider can declare them in a
+// resource.SensitivePaths redaction policy.  Paths do not reach into arrays or unions: an array or union that may
+// hold a secret is treated as secret as a whole.
+func (t *Type) SensitivePaths() []resource.PropertyPath {
+	var paths []resource.PropertyPath
+	t.appendSensitivePaths(nil, &paths)
+	return paths
+}
+
+func (t *Type) appendSensitivePaths(path resource.PropertyPath, paths *[]resource.PropertyPath) {
+	if t == nil {
+		return
+	}
+	if t.Secret || (t.Kind != ObjectKind && t.hasSecrets()) {
+		if len(path) > 0 {
+			*paths = append(*paths, path)
+		}
+		return
+	}
+	if t.Kind == ObjectKind {
+		keys := make([]string, 0, len(t.Properties))
+		for k := range t.Properties {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			elem := append(path[:len(path):len(path)], k)
+			t.Properties[resource.PropertyKey(k)].appendSensitivePaths(elem, paths)
+		}
+	}
+}
+
+// hasSecrets returns true if this type, or any type nested within it, is secret.
+func (t *Type) hasSecrets() bool {
+	if t == nil {
+		return false
+	}
+	if t.Secret || t.Elements.hasSecrets() {
+		return true
+	}
+	for _, p := range t.Properties {
+		if p.hasSecrets() {
+			return true
+		}
+	}
+	for _, alt := range t.OneOf {
+		if alt.hasSecrets() {
+			return true
+		}
+	}
+	return false
+}
+
+// Coercion records that a value was converted to match its type.
+type Coercion struct {
+	Path resource.PropertyPath  // the path to the coerced value.
+	From resource.PropertyValue // the value as it was given.
+	To   resource.PropertyValue // the value it was coerced to.
+}
+
+func (c Coercion) String() string {
+	return fmt.Sprintf("%v: coerced %v to %v", c.Path, c.From, c.To)
+}
+
+// Check validates the given properties against this object type. It returns the properties with any coercions
+// applied, a failure for each value that does not match its type, and a record of each coercion.
+func (t *Type) Check(props resource.PropertyMap) (resource.PropertyMap, []plugin.CheckFailure, []Coercion) {
+	c := &checker{coerce: true}
+	result := c.checkObject(nil, t, props)
+	return result, c.failures, c.coercions
+}
+
+// checker accumulates the failures and coercions found while checking a value.
+type checker struct {
+	coerce    bool                  // true if values may be coerced to match their types.
+	failures  []plugin.CheckFailure // the values that do not match their types.
+	coercions []Coercion            // the values that were coerced.
+}
+
+func (c *checker) fail(path resource.PropertyPath, format string, args ...interface{}) {
+	c.failures = append(c.failures, plugin.CheckFailure{
+		Property: resource.PropertyKey(path.String()),
+		Reason:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) coerced(path resource.PropertyPath, from, to resource.PropertyValue) resource.PropertyValue {
+	c.coercions = append(c.coercions, Coercion{Path: path, From: from, To: to})
+	return to
+}
+
+func (c *checker) check(path resource.PropertyPath, t *Type, v resource.PropertyValue) resource.PropertyValue {
+	if t == nil || t.Kind == AnyKind || v.IsNull() || v.IsComputed() || v.IsOutput() {
+		return v
+	}
+
+	switch t.Kind {
+	case BoolKind:
+		if v.IsBool() {
+			return v
+		}
+		if v.IsString() && c.coerce {
+			if b, err := strconv.ParseBool(strings.ToLower(v.StringValue())); err == nil {
+				return c.coerced(path, v, resource.NewBoolProperty(b))
+			}
+		}
+	case NumberKind:
+		if v.IsNumber() {
+			return v
+		}
+		if v.IsString() && c.coerce {
+			if n, err := strconv.ParseFloat(strings.TrimSpace(v.StringValue()), 64); err == nil {
+				return c.coerced(path, v, resource.NewNumberProperty(n))
+			}
+		}
+	case StringKind:
+		if v.IsString() || v.IsJSON() || v.IsFileRef() {
+			return v
+		}
+		if c.coerce {
+			switch {
+			case v.IsNumber():
+				s := strconv.FormatFloat(v.NumberValue(), 'f', -1, 64)
+				return c.coerced(path, v, resource.NewStringProperty(s))
+			case v.IsBool():
+				return c.coerced(path, v, resource.NewStringProperty(strconv.FormatBool(v.BoolValue())))
+			}
+		}
+	case JSONKind:
+		return c.checkJSON(path, v)
+	case EnumKind:
+		return c.checkEnum(path, t, v)
+	case UnionKind:
+		return c.checkUnion(path, t, v)
+	case ArrayKind:
+		if v.IsArray() {
+			arr := v.ArrayValue()
+			result := make([]resource.PropertyValue, len(arr))
+			for i, e := range arr {
+				result[i] = c.check(append(path[:len(path):len(path)], i), t.Elements, e)
+			}
+			return resource.NewArrayProperty(result)
+		}
+	case ObjectKind:
+		if v.IsObject() {
+			return resource.NewObjectProperty(c.checkObject(path, t, v.ObjectValue()))
+		}
+	}
+
+	c.fail(path, "expected %v, got %v", t.Kind, v.TypeString())
+	return v
+}
+
+func (c *checker) checkObject(path resource.PropertyPath, t *Type, props resource.PropertyMap) resource.PropertyMap {
+	result := make(resource.PropertyMap, len(props))
+	for _, k := range props.StableKeys() {
+		result[k] = c.check(append(path[:len(path):len(path)], string(k)), t.Properties[k], props[k])
+	}
+	for _, k := range t.Required {
+		if !props.HasValue(k) {
+			c.fail(append(path[:len(path):len(path)], string(k)), "missing required property")
+		}
+	}
+	return result
+}
+
+func (c *checker) checkJSON(path resource.PropertyPath, v resource.PropertyValue) resource.PropertyValue {
+	var j resource.JSON
+	switch {
+	case v.IsJSON():
+		j = v.JSONValue()
+	case v.IsString():
+		j = resource.JSON{Raw: v.StringValue()}
+	default:
+		c.fail(path, "expected %v, got %v", JSONKind, v.TypeString())
+		return v
+	}
+	if _, err := j.Document(); err != nil {
+		c.fail(path, "invalid JSON document: %v", err)
+		return v
+	}
+	return resource.NewJSONProperty(j.Raw)
+}
+
+func (c *checker) checkEnum(path resource.PropertyPath, t *Type, v resource.PropertyValue) resource.PropertyValue {
+	var given string
+	switch {
+	case v.IsString():
+		given = v.StringValue()
+		for _, value := range t.Values {
+			if given == value {
+				return v
+			}
+		}
+	case v.IsNumber() && c.coerce:
+		given = strconv.FormatFloat(v.NumberValue(), 'f', -1, 64)
+	default:
+		c.fail(path, "expected one of %v, got %v", strings.Join(t.Values, ", "), v.TypeString())
+		return v
+	}
+
+	if c.coerce {
+		for _, value := range t.Values {
+			if strings.EqualFold(given, value) {
+				return c.coerced(path, v, resource.NewStringProperty(value))
+			}
+		}
+	}
+	c.fail(path, "expected one of %v, got %q", strings.Join(t.Values, ", "), given)
+	return v
+}
+
+// checkUnion returns the value checked against the first alternative that it matches exactly. If it matches none of
+// them exactly, it is coerced to the first alternative that it can be coerced to.
+func (c *checker) checkUnion(path resource.PropertyPath, t *Type, v resource.PropertyValue) resource.PropertyValue {
+	for _, coerce := range []bool{false, true} {
+		if coerce && !c.coerce {
+			break
+		}
+		for _, alt := range t.OneOf {
+			trial := &checker{coerce: coerce}
+			result := trial.check(path, alt, v)
+			if len(trial.failures) == 0 {
+				c.coercions = append(c.coercions, trial.coercions...)
+				return result
+			}
+		}
+	}
+
+	kinds := make([]string, len(t.OneOf))
+	for i, alt := range t.OneOf {
+		kinds[i] = alt.Kind.String()
+	}
+	c.fail(path, "expected one of %v, got %v", strings.Join(kinds, " | "), v.TypeString())
+	return v
+}
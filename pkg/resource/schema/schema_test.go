@@ -0,0 +1,184 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+var instanceType = &Type{
+	Kind: ObjectKind,
+	Properties: map[resource.PropertyKey]*Type{
+		"count":   {Kind: NumberKind},
+		"name":    {Kind: StringKind},
+		"public":  {Kind: BoolKind},
+		"tier":    {Kind: EnumKind, Values: []string{"Basic", "Standard", "Premium"}},
+		"ports":   {Kind: ArrayKind, Elements: &Type{Kind: NumberKind}},
+		"timeout": {Kind: UnionKind, OneOf: []*Type{{Kind: NumberKind}, {Kind: EnumKind, Values: []string{"none"}}}},
+		"disk": {
+			Kind:       ObjectKind,
+			Properties: map[resource.PropertyKey]*Type{"size": {Kind: NumberKind}},
+			Required:   []resource.PropertyKey{"size"},
+		},
+	},
+	Required: []resource.PropertyKey{"name"},
+}
+
+func TestCheckCoercesSloppyValues(t *testing.T) {
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"count":   "5",
+		"name":    float64(42),
+		"public":  "TRUE",
+		"tier":    "standard",
+		"ports":   []interface{}{float64(80), "443"},
+		"timeout": "NONE",
+		"disk":    map[string]interface{}{"size": " 100 "},
+		"extra":   "unchecked",
+	})
+
+	checked, failures, coercions := instanceType.Check(props)
+	assert.Empty(t, failures)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"count":   float64(5),
+		"name":    "42",
+		"public":  true,
+		"tier":    "Standard",
+		"ports":   []interface{}{float64(80), float64(443)},
+		"timeout": "none",
+		"disk":    map[string]interface{}{"size": float64(100)},
+		"extra":   "unchecked",
+	}), checked)
+
+	var paths []string
+	for _, c := range coercions {
+		paths = append(paths, c.Path.String())
+	}
+	assert.Equal(t, []string{"count", "disk.size", "name", "ports[1]", "public", "tier", "timeout"}, paths)
+	assert.Equal(t, "count: coerced 5 to 5", coercions[0].String())
+
+	// The input map is left untouched.
+	assert.Equal(t, resource.NewStringProperty("5"), props["count"])
+}
+
+func TestCheckReportsMismatches(t *testing.T) {
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"count":   "five",
+		"public":  "maybe",
+		"tier":    "Gold",
+		"ports":   "80",
+		"timeout": true,
+		"disk":    map[string]interface{}{},
+	})
+
+	_, failures, coercions := instanceType.Check(props)
+	assert.Empty(t, coercions)
+	assert.Equal(t, []plugin.CheckFailure{
+		{Property: "count", Reason: "expected number, got string"},
+		{Property: "disk.size", Reason: "missing required property"},
+		{Property: "ports", Reason: "expected array, got string"},
+		{Property: "public", Reason: "expected bool, got string"},
+		{Property: "tier", Reason: `expected one of Basic, Standard, Premium, got "Gold"`},
+		{Property: "timeout", Reason: "expected one of number | enum, got bool"},
+		{Property: "name", Reason: "missing required property"},
+	}, failures)
+}
+
+func TestCheckJSON(t *testing.T) {
+	doc := &Type{
+		Kind:       ObjectKind,
+		Properties: map[resource.PropertyKey]*Type{"policy": {Kind: JSONKind}},
+	}
+
+	// Strings that hold JSON documents become JSON values.
+	checked, failures, coercions := doc.Check(resource.PropertyMap{"policy": resource.NewStringProperty(`{"a": 1}`)})
+	assert.Empty(t, failures)
+	assert.Empty(t, coercions)
+	assert.Equal(t, resource.NewJSONProperty(`{"a": 1}`), checked["policy"])
+
+	// Anything else is rejected.
+	_, failures, _ = doc.Check(resource.PropertyMap{"policy": resource.NewStringProperty("{")})
+	if assert.Len(t, failures, 1) {
+		assert.Contains(t, failures[0].Reason, "invalid JSON document")
+	}
+	_, failures, _ = doc.Check(resource.PropertyMap{"policy": resource.NewNumberProperty(1)})
+	assert.Equal(t, []plugin.CheckFailure{{Property: "policy", Reason: "expected json, got number"}}, failures)
+}
+
+func TestCheckPrefersExactUnionMatches(t *testing.T) {
+	union := &Type{
+		Kind: ObjectKind,
+		Properties: map[resource.PropertyKey]*Type{
+			"value": {Kind: UnionKind, OneOf: []*Type{{Kind: NumberKind}, {Kind: StringKind}}},
+		},
+	}
+
+	// "5" is a valid string, so it is not coerced to the number alternative that precedes it.
+	checked, failures, coercions := union.Check(resource.PropertyMap{"value": resource.NewStringProperty("5")})
+	assert.Empty(t, failures)
+	assert.Empty(t, coercions)
+	assert.Equal(t, resource.NewStringProperty("5"), checked["value"])
+
+	// Unknown and null values are accepted as they are.
+	props := resource.PropertyMap{
+		"value": resource.MakeComputed(resource.NewStringProperty("")),
+		"other": resource.NewNullProperty(),
+	}
+	checked, failures, coercions = union.Check(props)
+	assert.Empty(t, failures)
+	assert.Empty(t, coercions)
+	assert.Equal(t, props, checked)
+}
+
+func TestSensitivePaths(t *testing.T) {
+	t.Parallel()
+
+	typ := &Type{
+		Kind: ObjectKind,
+		Properties: map[resource.PropertyKey]*Type{
+			"name":     {Kind: StringKind},
+			"password": {Kind: StringKind, Secret: true},
+			"keys":     {Kind: ArrayKind, Elements: &Type{Kind: StringKind, Secret: true}},
+			"login": {
+				Kind: ObjectKind,
+				Properties: map[resource.PropertyKey]*Type{
+					"user":  {Kind: StringKind},
+					"token": {Kind: StringKind, Secret: true},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []resource.PropertyPath{{"keys"}, {"login", "token"}, {"password"}}, typ.SensitivePaths())
+	assert.Nil(t, instanceType.SensitivePaths())
+}
+
+func TestPackageRoundTrip(t *testing.T) {
+	data, err := bucketPackage.Marshal()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"kind":"enum"`)
+
+	parsed, err := ParsePackage(data)
+	assert.NoError(t, err)
+	assert.Equal(t, bucketPackage, parsed)
+
+	_, err = ParsePackage([]byte(`{"name": "test", "resources": {"test:index:Thing": {"inputs": {"kind": "float"}}}}`))
+	assert.Error(t, err)
+	_, err = ParsePackage([]byte(`{"name": "test", "resources": {"test:index:Thing": {"inputs": {"kind": "array"}}}}`))
+	assert.Error(t, err)
+}
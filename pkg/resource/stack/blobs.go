@@ -0,0 +1,161 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+// BlobSig is the unique signature for a reference to a property value that was offloaded from a deployment to a blob
+// store. Checkpoints that contain such references cannot be read without the store, so older versions of the CLI
+// reject them as having an unrecognized signature rather than misreading them.
+const BlobSig = "5a0e4c8f2b7d4e61a3c9f0d8b6e1a274"
+
+const (
+	blobRefKey  = "ref"  // the key holding the blob store reference in a blob reference object.
+	blobSizeKey = "size" // the key holding the original size of the value in a blob reference object.
+)
+
+// OffloadBlobs replaces every string in the given deployment's resource properties whose length exceeds the given
+// threshold, including the text of assets, with a reference to a copy of the string in the given store. The
+// deployment is modified in place. Because the store is content-addressed, a large value that does not change
+// between checkpoints is only stored once.
+func OffloadBlobs(deployment *apitype.DeploymentV3, store plugin.LargeValueStore, threshold int) error {
+	offload := func(v interface{}) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok || len(s) <= threshold {
+			return v, nil
+		}
+		ref, err := store.Put([]byte(s))
+		if err != nil {
+			return nil, errors.Wrap(err, "offloading large value")
+		}
+		return map[string]interface{}{
+			resource.SigKey: BlobSig,
+			blobRefKey:      ref,
+			blobSizeKey:     float64(len(s)),
+		}, nil
+	}
+	return rewriteDeploymentProperties(deployment, offload)
+}
+
+// ResolveBlobs replaces every blob reference in the given deployment's resource properties with the value that it
+// refers to, fetched from the given store. The deployment is modified in place. The store may be nil if the deployment
+// is known not to contain any references.
+func ResolveBlobs(deployment *apitype.DeploymentV3, store plugin.LargeValueStore) error {
+	resolve := func(v interface{}) (interface{}, error) {
+		obj, ok := v.(map[string]interface{})
+		if !ok || obj[resource.SigKey] != BlobSig {
+			return v, nil
+		}
+
+		ref, ok := obj[blobRefKey].(string)
+		if !ok {
+			return nil, errors.New("blob reference is missing its 'ref' field")
+		}
+		if store == nil {
+			return nil, errors.Errorf("cannot resolve blob '%s' without a blob store", ref)
+		}
+		data, err := store.Get(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving blob '%s'", ref)
+		}
+		if size, hasSize := obj[blobSizeKey].(float64); hasSize && int(size) != len(data) {
+			return nil, errors.Errorf("blob '%s' is %d bytes; expected %d", ref, len(data), int(size))
+		}
+		return string(data), nil
+	}
+	return rewriteDeploymentProperties(deployment, resolve)
+}
+
+// rewriteDeploymentProperties applies the given rewrite to every value, at every depth, in the inputs and outputs of
+// the given deployment's resources and pending operations, and in its shared values. Each value is rewritten before
+// its elements are visited.
+func rewriteDeploymentProperties(deployment *apitype.DeploymentV3,
+	rewrite func(interface{}) (interface{}, error)) error {
+
+	rewriteResource := func(res *apitype.ResourceV3) error {
+		for _, props := range []map[string]interface{}{res.Inputs, res.Outputs} {
+			if err := rewriteObject(props, rewrite); err != nil {
+				return errors.Wrapf(err, "resource '%s'", res.URN)
+			}
+		}
+		return nil
+	}
+
+	for i := range deployment.Resources {
+		if err := rewriteResource(&deployment.Resources[i]); err != nil {
+			return err
+		}
+	}
+	for i := range deployment.PendingOperations {
+		if err := rewriteResource(&deployment.PendingOperations[i].Resource); err != nil {
+			return err
+		}
+	}
+	for id, v := range deployment.SharedValues {
+		rewritten, err := rewriteValue(v, rewrite)
+		if err != nil {
+			return errors.Wrapf(err, "shared value '%s'", id)
+		}
+		deployment.SharedValues[id] = rewritten
+	}
+	return nil
+}
+
+func rewriteObject(obj map[string]interface{}, rewrite func(interface{}) (interface{}, error)) error {
+	for k, v := range obj {
+		// Signatures identify the kinds of objects, such as assets, so they are never rewritten.
+		if k == resource.SigKey {
+			continue
+		}
+		rewritten, err := rewriteValue(v, rewrite)
+		if err != nil {
+			return err
+		}
+		obj[k] = rewritten
+	}
+	return nil
+}
+
+func rewriteValue(v interface{}, rewrite func(interface{}) (interface{}, error)) (interface{}, error) {
+	v, err := rewrite(v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case []interface{}:
+		for i, elem := range v {
+			rewritten, err := rewriteValue(elem, rewrite)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rewritten
+		}
+	case map[string]interface{}:
+		// Blob and shared value references are opaque, so there is no need to look inside them.
+		if sig := v[resource.SigKey]; sig != BlobSig && sig != SharedValueSig {
+			if err := rewriteObject(v, rewrite); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+func TestOffloadBlobs(t *testing.T) {
+	large := strings.Repeat("x", 64)
+	asset, err := resource.NewTextAsset(large)
+	assert.NoError(t, err)
+
+	inputs := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"small":  "small",
+		"large":  large,
+		"nested": map[string]interface{}{"array": []interface{}{large}},
+		"asset":  asset,
+	})
+	newDeployment := func() *apitype.DeploymentV3 {
+		return &apitype.DeploymentV3{Resources: []apitype.ResourceV3{{
+			URN:     "urn:pulumi:test::test::pkgA:m:typA::resA",
+			Inputs:  SerializeProperties(inputs),
+			Outputs: SerializeProperties(inputs),
+		}}}
+	}
+
+	store := plugin.NewMemoryLargeValueStore()
+	deployment := newDeployment()
+	assert.NoError(t, OffloadBlobs(deployment, store, 32))
+
+	// Large strings, including the text of assets, are replaced by references; everything else is left alone.
+	res := deployment.Resources[0]
+	assert.Equal(t, "small", res.Inputs["small"])
+	ref := res.Inputs["large"].(map[string]interface{})
+	assert.Equal(t, BlobSig, ref[resource.SigKey])
+	assert.Equal(t, ref, res.Inputs["nested"].(map[string]interface{})["array"].([]interface{})[0])
+	assert.Equal(t, ref, res.Outputs["large"])
+	serializedAsset := res.Inputs["asset"].(map[string]interface{})
+	assert.Equal(t, resource.AssetSig, serializedAsset[resource.SigKey])
+	assert.Equal(t, asset.Hash, serializedAsset[resource.AssetHashProperty])
+	assert.Equal(t, ref, serializedAsset[resource.AssetTextProperty])
+
+	// Older readers reject the references rather than misreading them.
+	_, err = DeserializeResource(res)
+	assert.Error(t, err)
+
+	// Resolving the references restores the original deployment.
+	assert.NoError(t, ResolveBlobs(deployment, store))
+	assert.Equal(t, newDeployment(), deployment)
+
+	// References cannot be resolved without the store.
+	deployment = newDeployment()
+	assert.NoError(t, OffloadBlobs(deployment, store, 32))
+	assert.Error(t, ResolveBlobs(deployment, nil))
+	assert.Error(t, ResolveBlobs(deployment, plugin.NewMemoryLargeValueStore()))
+
+	// Deployments without references need no store.
+	deployment = newDeployment()
+	assert.NoError(t, ResolveBlobs(deployment, nil))
+	assert.Equal(t, newDeployment(), deployment)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 )
@@ -86,6 +87,52 @@ func SerializeCheckpoint(stack tokens.QName, config config.Map, snap *deploy.Sna
 	if snap != nil {
 		latest = SerializeDeployment(snap)
 	}
+	return serializeCheckpoint(stack, config, latest)
+}
+
+// SerializeCheckpointWithBlobs turns a snapshot into a data structure suitable for serialization, offloading property
+// values larger than the given threshold to the given blob store as OffloadBlobs does.
+func SerializeCheckpointWithBlobs(stack tokens.QName, config config.Map, snap *deploy.Snapshot,
+	store plugin.LargeValueStore, threshold int) (*apitype.VersionedCheckpoint, error) {
+
+	return SerializeCheckpointWithOptions(stack, config, snap, CheckpointOptions{
+		BlobStore:     store,
+		BlobThreshold: threshold,
+	})
+}
+
+// CheckpointOptions controls how SerializeCheckpointWithOptions shrinks the checkpoints that it serializes.
+type CheckpointOptions struct {
+	BlobStore     plugin.LargeValueStore // the store to offload large values to, if any.
+	BlobThreshold int                    // the size above which values are offloaded to the blob store.
+	Compact       bool                   // true to share repeated values, as CompactDeployment does.
+}
+
+// SerializeCheckpointWithOptions turns a snapshot into a data structure suitable for serialization, offloading large
+// values to a blob store and sharing repeated values as the given options direct. Values are offloaded before repeated
+// values are shared.
+func SerializeCheckpointWithOptions(stack tokens.QName, config config.Map, snap *deploy.Snapshot,
+	opts CheckpointOptions) (*apitype.VersionedCheckpoint, error) {
+
+	var latest *apitype.DeploymentV3
+	if snap != nil {
+		latest = SerializeDeployment(snap)
+		if opts.BlobStore != nil {
+			if err := OffloadBlobs(latest, opts.BlobStore, opts.BlobThreshold); err != nil {
+				return nil, err
+			}
+		}
+		if opts.Compact {
+			if err := CompactDeployment(latest); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return serializeCheckpoint(stack, config, latest), nil
+}
+
+func serializeCheckpoint(stack tokens.QName, config config.Map,
+	latest *apitype.DeploymentV3) *apitype.VersionedCheckpoint {
 
 	b, err := json.Marshal(apitype.CheckpointV3{
 		Stack:  stack,
@@ -111,6 +158,20 @@ func DeserializeCheckpoint(chkpoint *apitype.CheckpointV3) (*deploy.Snapshot, er
 	return nil, nil
 }
 
+// DeserializeCheckpointWithBlobs is like DeserializeCheckpoint, but first resolves any references in the checkpoint to
+// values that were offloaded to the given blob store. The checkpoint is modified in place.
+func DeserializeCheckpointWithBlobs(chkpoint *apitype.CheckpointV3,
+	store plugin.LargeValueStore) (*deploy.Snapshot, error) {
+
+	contract.Require(chkpoint != nil, "chkpoint")
+	if chkpoint.Latest != nil {
+		if err := ResolveBlobs(chkpoint.Latest, store); err != nil {
+			return nil, err
+		}
+	}
+	return DeserializeCheckpoint(chkpoint)
+}
+
 // GetRootStackResource returns the root stack resource from a given snapshot, or nil if not found.  If the stack
 // exists, its output properties, if any, are also returned in the resulting map.
 func GetRootStackResource(snap *deploy.Snapshot) (*resource.State, map[string]interface{}) {
@@ -0,0 +1,193 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// SharedValueSig is the unique signature for a reference to a property value that is shared by several places in a
+// compacted deployment. Older versions of the CLI reject such references as having an unrecognized signature rather
+// than misreading them.
+const SharedValueSig = "8c1f9d2a6e3b4f70b5d4a9e2c7f6183d"
+
+const sharedRefKey = "ref" // the key holding the shared value's ID in a shared value reference object.
+
+// minSharedValueSize is the smallest encoded size of a value that is worth sharing. Smaller values would barely be
+// larger than the references that would replace them.
+const minSharedValueSize = 128
+
+// CompactDeployment replaces every value in the given deployment's resource properties that is repeated, and large
+// enough for sharing it to pay off, with a reference to a single copy of it in the deployment's table of shared
+// values. Values are shared as a whole, so a repeated object or array is replaced rather than each of its elements.
+// The deployment is modified in place. Since shared values are identified by their content, a value that does not
+// change between checkpoints keeps its ID.
+//
+// Compacted deployments are expanded as they are deserialized, or by ExpandDeployment.
+func CompactDeployment(deployment *apitype.DeploymentV3) error {
+	contract.Require(deployment != nil, "deployment")
+
+	// A deployment that is already compacted is expanded first, so that its values are shared afresh.
+	if err := ExpandDeployment(deployment); err != nil {
+		return err
+	}
+
+	// First, count the occurrences of each value.
+	counts := make(map[[sha256.Size]byte]int)
+	count := func(v interface{}) (interface{}, error) {
+		if sum, size := hashValue(v); size >= minSharedValueSize {
+			counts[sum]++
+		}
+		return v, nil
+	}
+	if err := rewriteDeploymentProperties(deployment, count); err != nil {
+		return err
+	}
+
+	// Then replace each value that occurs more than once with a reference to its copy in the table. Values are
+	// visited before their elements, so the largest repeated values are shared, and their elements are left alone.
+	shared := make(map[string]interface{})
+	sums := make(map[string][sha256.Size]byte)
+	share := func(v interface{}) (interface{}, error) {
+		sum, size := hashValue(v)
+		if size < minSharedValueSize || counts[sum] < 2 {
+			return v, nil
+		}
+		id := hex.EncodeToString(sum[:8])
+		if other, has := sums[id]; has && other != sum {
+			// Two different values have the same ID; only the first is shared.
+			return v, nil
+		}
+		sums[id], shared[id] = sum, v
+		return map[string]interface{}{resource.SigKey: SharedValueSig, sharedRefKey: id}, nil
+	}
+	if err := rewriteDeploymentProperties(deployment, share); err != nil {
+		return err
+	}
+
+	if len(shared) > 0 {
+		deployment.SharedValues = shared
+	}
+	return nil
+}
+
+// ExpandDeployment replaces every shared value reference in the given deployment's resource properties with a copy of
+// the value that it refers to, and removes the deployment's table of shared values. The deployment is modified in
+// place.
+func ExpandDeployment(deployment *apitype.DeploymentV3) error {
+	contract.Require(deployment != nil, "deployment")
+	if deployment.SharedValues == nil {
+		return nil
+	}
+
+	shared := deployment.SharedValues
+	deployment.SharedValues = nil
+	expand := func(v interface{}) (interface{}, error) {
+		obj, ok := v.(map[string]interface{})
+		if !ok || obj[resource.SigKey] != SharedValueSig {
+			return v, nil
+		}
+
+		id, ok := obj[sharedRefKey].(string)
+		if !ok {
+			return nil, errors.New("shared value reference is missing its 'ref' field")
+		}
+		value, has := shared[id]
+		if !has {
+			return nil, errors.Errorf("unknown shared value '%s'", id)
+		}
+		return copyValue(value), nil
+	}
+	return rewriteDeploymentProperties(deployment, expand)
+}
+
+// hashValue returns a hash of the given value that is the same for every value with the same content, along with the
+// approximate size of the value's JSON encoding. Objects are hashed independently of the order of their keys.
+func hashValue(v interface{}) ([sha256.Size]byte, int) {
+	h := sha256.New()
+	size := 0
+	write := func(tag byte, data []byte) {
+		var n [binary.MaxVarintLen64]byte
+		_, err := h.Write(append([]byte{tag}, n[:binary.PutUvarint(n[:], uint64(len(data)))]...))
+		contract.IgnoreError(err)
+		_, err = h.Write(data)
+		contract.IgnoreError(err)
+	}
+
+	switch v := v.(type) {
+	case []interface{}:
+		write('a', nil)
+		size += 2
+		for _, elem := range v {
+			sum, n := hashValue(elem)
+			write('e', sum[:])
+			size += n + 1
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		write('o', nil)
+		size += 2
+		for _, k := range keys {
+			sum, n := hashValue(v[k])
+			write('k', []byte(k))
+			write('v', sum[:])
+			size += len(k) + n + 4
+		}
+	default:
+		data, err := json.Marshal(v)
+		contract.AssertNoError(err)
+		write('s', data)
+		size += len(data)
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, size
+}
+
+// copyValue returns a deep copy of the given value.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = copyValue(elem)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			result[k] = copyValue(elem)
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+func TestCompactDeployment(t *testing.T) {
+	tags := map[string]interface{}{"owner": "ops", "team": "infrastructure", "cost-center": "1234"}
+	amis := map[string]interface{}{
+		"us-east-1":      "ami-0123456789abcdef0",
+		"us-west-2":      "ami-0fedcba9876543210",
+		"eu-west-1":      "ami-00112233445566778",
+		"eu-central-1":   "ami-0a1b2c3d4e5f60718",
+		"ap-southeast-2": "ami-08192a3b4c5d6e7f8",
+	}
+	newDeployment := func() *apitype.DeploymentV3 {
+		deployment := &apitype.DeploymentV3{}
+		for i := 0; i < 10; i++ {
+			props := resource.NewPropertyMapFromMap(map[string]interface{}{
+				"name": fmt.Sprintf("res%d", i),
+				"tags": tags,
+				"amis": amis,
+			})
+			deployment.Resources = append(deployment.Resources, apitype.ResourceV3{
+				URN:     resource.URN(fmt.Sprintf("urn:pulumi:test::test::pkgA:m:typA::res%d", i)),
+				Inputs:  SerializeProperties(props),
+				Outputs: SerializeProperties(props),
+			})
+		}
+		return deployment
+	}
+
+	deployment := newDeployment()
+	assert.NoError(t, CompactDeployment(deployment))
+
+	// The repeated maps are each stored once; the unique names and the small tag map are left alone.
+	assert.Len(t, deployment.SharedValues, 1)
+	for _, res := range deployment.Resources {
+		ref := res.Inputs["amis"].(map[string]interface{})
+		assert.Equal(t, SharedValueSig, ref[resource.SigKey])
+		assert.Equal(t, ref, res.Outputs["amis"])
+		assert.Equal(t, amis, deployment.SharedValues[ref[sharedRefKey].(string)])
+		assert.Equal(t, tags, res.Inputs["tags"])
+	}
+
+	compacted, err := json.Marshal(deployment)
+	assert.NoError(t, err)
+	expanded, err := json.Marshal(newDeployment())
+	assert.NoError(t, err)
+	assert.True(t, len(compacted) < len(expanded))
+
+	// Older readers reject the references rather than misreading them.
+	_, err = DeserializeResource(deployment.Resources[0])
+	assert.Error(t, err)
+
+	// Deserializing the deployment expands the references.
+	snap, err := DeserializeDeploymentV3(*deployment)
+	assert.NoError(t, err)
+	expected, err := DeserializeDeploymentV3(*newDeployment())
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Resources, snap.Resources)
+
+	// As does expanding it explicitly, after which it is as it was.
+	deployment = newDeployment()
+	assert.NoError(t, CompactDeployment(deployment))
+	assert.NoError(t, ExpandDeployment(deployment))
+	assert.Equal(t, newDeployment(), deployment)
+
+	// References to unknown values cannot be expanded.
+	deployment = newDeployment()
+	assert.NoError(t, CompactDeployment(deployment))
+	deployment.SharedValues = map[string]interface{}{}
+	assert.Error(t, ExpandDeployment(deployment))
+}
+
+func TestCompactDeploymentWithBlobs(t *testing.T) {
+	large := strings.Repeat("x", 256)
+	props := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"policy": map[string]interface{}{"document": large, "version": "2012-10-17"},
+	})
+	newDeployment := func() *apitype.DeploymentV3 {
+		return &apitype.DeploymentV3{Resources: []apitype.ResourceV3{{
+			URN:     "urn:pulumi:test::test::pkgA:m:typA::resA",
+			Inputs:  SerializeProperties(props),
+			Outputs: SerializeProperties(props),
+		}}}
+	}
+
+	store := plugin.NewMemoryLargeValueStore()
+	deployment := newDeployment()
+	assert.NoError(t, OffloadBlobs(deployment, store, 32))
+	assert.NoError(t, CompactDeployment(deployment))
+	assert.Len(t, deployment.SharedValues, 1)
+
+	// Blobs that were offloaded from shared values are resolved in place.
+	assert.NoError(t, ResolveBlobs(deployment, store))
+	assert.NoError(t, ExpandDeployment(deployment))
+	assert.Equal(t, newDeployment(), deployment)
+}
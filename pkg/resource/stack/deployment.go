@@ -25,6 +25,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/apitype/migrate"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -45,6 +46,10 @@ var (
 	// ErrDeploymentSchemaVersionTooNew is returned from `DeserializeDeployment` if the
 	// untyped deployment being deserialized is too new to understand.
 	ErrDeploymentSchemaVersionTooNew = fmt.Errorf("this stack's deployment version is too new")
+
+	// ErrDeploymentDowngradeRequired is returned from `MigrateUntypedDeployment` if the requested
+	// schema version is older than the deployment's and downgrades were not allowed.
+	ErrDeploymentDowngradeRequired = fmt.Errorf("writing an older deployment schema requires a downgrade")
 )
 
 // SerializeDeployment serializes an entire snapshot as a deploy record.
@@ -100,34 +105,102 @@ func DeserializeUntypedDeployment(deployment *apitype.UntypedDeployment) (*deplo
 		return nil, ErrDeploymentSchemaVersionTooOld
 	}
 
+	v3deployment, err := upToDeploymentV3(deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeserializeDeploymentV3(v3deployment)
+}
+
+// upToDeploymentV3 migrates an untyped deployment of any supported version to a DeploymentV3.
+func upToDeploymentV3(deployment *apitype.UntypedDeployment) (apitype.DeploymentV3, error) {
 	var v3deployment apitype.DeploymentV3
 	switch deployment.Version {
 	case 1:
 		var v1deployment apitype.DeploymentV1
 		if err := json.Unmarshal([]byte(deployment.Deployment), &v1deployment); err != nil {
-			return nil, err
+			return apitype.DeploymentV3{}, err
 		}
 		v2deployment := migrate.UpToDeploymentV2(v1deployment)
 		v3deployment = migrate.UpToDeploymentV3(v2deployment)
 	case 2:
 		var v2deployment apitype.DeploymentV2
 		if err := json.Unmarshal([]byte(deployment.Deployment), &v2deployment); err != nil {
-			return nil, err
+			return apitype.DeploymentV3{}, err
 		}
 		v3deployment = migrate.UpToDeploymentV3(v2deployment)
 	case 3:
 		if err := json.Unmarshal([]byte(deployment.Deployment), &v3deployment); err != nil {
-			return nil, err
+			return apitype.DeploymentV3{}, err
 		}
 	default:
 		contract.Failf("unrecognized version: %d", deployment.Version)
 	}
+	return v3deployment, nil
+}
 
-	return DeserializeDeploymentV3(v3deployment)
+// MigrateUntypedDeployment migrates an untyped deployment to the given schema version. Deployments are always
+// upgraded to the current schema first. Producing a deployment in an older schema is lossy, so unless allowDowngrade
+// is true, MigrateUntypedDeployment returns ErrDeploymentDowngradeRequired if version is older than the deployment's
+// own version. Deployments that use features the older schema cannot represent fail to migrate regardless.
+func MigrateUntypedDeployment(deployment *apitype.UntypedDeployment, version int,
+	allowDowngrade bool) (*apitype.UntypedDeployment, error) {
+
+	contract.Require(deployment != nil, "deployment")
+	switch {
+	case deployment.Version > apitype.DeploymentSchemaVersionCurrent || version > apitype.DeploymentSchemaVersionCurrent:
+		return nil, ErrDeploymentSchemaVersionTooNew
+	case deployment.Version < DeploymentSchemaVersionOldestSupported || version < DeploymentSchemaVersionOldestSupported:
+		return nil, ErrDeploymentSchemaVersionTooOld
+	case version == deployment.Version:
+		return deployment, nil
+	case version < deployment.Version && !allowDowngrade:
+		return nil, ErrDeploymentDowngradeRequired
+	}
+
+	v3deployment, err := upToDeploymentV3(deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	switch version {
+	case 1:
+		v2deployment, err := migrate.DownToDeploymentV2(v3deployment)
+		if err != nil {
+			return nil, errors.Wrap(err, "downgrading deployment to version 2")
+		}
+		if result, err = migrate.DownToDeploymentV1(v2deployment); err != nil {
+			return nil, errors.Wrap(err, "downgrading deployment to version 1")
+		}
+	case 2:
+		if result, err = migrate.DownToDeploymentV2(v3deployment); err != nil {
+			return nil, errors.Wrap(err, "downgrading deployment to version 2")
+		}
+	case 3:
+		result = v3deployment
+	default:
+		contract.Failf("unrecognized version: %d", version)
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &apitype.UntypedDeployment{
+		Version:    version,
+		Deployment: json.RawMessage(bytes),
+	}, nil
 }
 
-// DeserializeDeploymentV3 deserializes a typed DeploymentV3 into a `deploy.Snapshot`.
+// DeserializeDeploymentV3 deserializes a typed DeploymentV3 into a `deploy.Snapshot`. If the deployment was compacted,
+// its resources' shared values are expanded in place.
 func DeserializeDeploymentV3(deployment apitype.DeploymentV3) (*deploy.Snapshot, error) {
+	if err := ExpandDeployment(&deployment); err != nil {
+		return nil, err
+	}
+
 	// Unpack the versions.
 	manifest := deploy.Manifest{
 		Time:    deployment.Manifest.Time,
@@ -150,10 +223,12 @@ func DeserializeDeploymentV3(deployment apitype.DeploymentV3) (*deploy.Snapshot,
 		})
 	}
 
-	// For every serialized resource vertex, create a ResourceDeployment out of it.
+	// For every serialized resource vertex, create a ResourceDeployment out of it. Large snapshots repeat the same
+	// keys, URNs, and small values many times over, so share a single interner across the entire deployment.
+	interner := resource.NewInterner()
 	var resources []*resource.State
 	for _, res := range deployment.Resources {
-		desres, err := DeserializeResource(res)
+		desres, err := deserializeResource(res, interner)
 		if err != nil {
 			return nil, err
 		}
@@ -162,7 +237,7 @@ func DeserializeDeploymentV3(deployment apitype.DeploymentV3) (*deploy.Snapshot,
 
 	var ops []resource.Operation
 	for _, op := range deployment.PendingOperations {
-		desop, err := DeserializeOperation(op)
+		desop, err := deserializeOperation(op, interner)
 		if err != nil {
 			return nil, err
 		}
@@ -187,6 +262,12 @@ func SerializeResource(res *resource.State) apitype.ResourceV3 {
 		outputs = SerializeProperties(outp)
 	}
 
+	var customTimeouts *resource.CustomTimeouts
+	if !res.CustomTimeouts.IsZero() {
+		timeouts := res.CustomTimeouts
+		customTimeouts = &timeouts
+	}
+
 	return apitype.ResourceV3{
 		URN:                  res.URN,
 		Custom:               res.Custom,
@@ -203,6 +284,12 @@ func SerializeResource(res *resource.State) apitype.ResourceV3 {
 		Provider:             res.Provider,
 		PropertyDependencies: res.PropertyDependencies,
 		PendingReplacement:   res.PendingReplacement,
+		RetainOnDelete:       res.RetainOnDelete,
+		Defaults:             res.Defaults,
+		CustomTimeouts:       customTimeouts,
+		PropertyReads:        res.PropertyReads,
+		Provenance:           res.Provenance,
+		InputHashes:          res.InputHashes,
 	}
 }
 
@@ -248,6 +335,28 @@ func SerializePropertyValue(prop resource.PropertyValue) interface{} {
 		return SerializeProperties(prop.ObjectValue())
 	}
 
+	// JSON values are saved as the strings that hold them.
+	if prop.IsJSON() {
+		return prop.JSONValue().Raw
+	}
+
+	// File references are saved as their paths and hashes rather than the values that they refer to.
+	if prop.IsFileRef() {
+		return prop.FileRefValue().Serialize()
+	}
+
+	// Secrets are saved as objects that wrap their values, so that they remain secret when they are read back.
+	if prop.IsSecret() {
+		elem := SerializePropertyValue(prop.SecretValue().Element)
+		if elem == nil {
+			return nil
+		}
+		return map[string]interface{}{
+			resource.SigKey: resource.SecretSig,
+			"value":         elem,
+		}
+	}
+
 	// For assets, we need to serialize them a little carefully, so we can recover them afterwards.
 	if prop.IsAsset() {
 		return prop.AssetValue().Serialize()
@@ -261,24 +370,52 @@ func SerializePropertyValue(prop resource.PropertyValue) interface{} {
 
 // DeserializeResource turns a serialized resource back into its usual form.
 func DeserializeResource(res apitype.ResourceV3) (*resource.State, error) {
+	return deserializeResource(res, nil)
+}
+
+func deserializeResource(res apitype.ResourceV3, interner *resource.Interner) (*resource.State, error) {
 	// Deserialize the resource properties, if they exist.
-	inputs, err := DeserializeProperties(res.Inputs)
+	inputs, err := deserializeProperties(res.Inputs, interner)
 	if err != nil {
 		return nil, err
 	}
-	outputs, err := DeserializeProperties(res.Outputs)
+	outputs, err := deserializeProperties(res.Outputs, interner)
 	if err != nil {
 		return nil, err
 	}
 
-	return resource.NewState(
-		res.Type, res.URN, res.Custom, res.Delete, res.ID,
-		inputs, outputs, res.Parent, res.Protect, res.External, res.Dependencies, res.InitErrors, res.Provider,
-		res.PropertyDependencies, res.PendingReplacement), nil
+	// Share the strings that are commonly repeated across resources.
+	typ := tokens.Type(interner.Intern(string(res.Type)))
+	parent := resource.URN(interner.Intern(string(res.Parent)))
+	provider := interner.Intern(res.Provider)
+	var deps []resource.URN
+	if res.Dependencies != nil {
+		deps = make([]resource.URN, len(res.Dependencies))
+		for i, dep := range res.Dependencies {
+			deps[i] = resource.URN(interner.Intern(string(dep)))
+		}
+	}
+
+	var customTimeouts resource.CustomTimeouts
+	if res.CustomTimeouts != nil {
+		customTimeouts = *res.CustomTimeouts
+	}
+
+	state := resource.NewState(
+		typ, res.URN, res.Custom, res.Delete, res.ID,
+		inputs, outputs, parent, res.Protect, res.External, deps, res.InitErrors, provider,
+		res.PropertyDependencies, res.PendingReplacement, res.RetainOnDelete, res.Defaults,
+		customTimeouts, res.PropertyReads, res.Provenance)
+	state.InputHashes = res.InputHashes
+	return state, nil
 }
 
 func DeserializeOperation(op apitype.OperationV2) (resource.Operation, error) {
-	res, err := DeserializeResource(op.Resource)
+	return deserializeOperation(op, nil)
+}
+
+func deserializeOperation(op apitype.OperationV2, interner *resource.Interner) (resource.Operation, error) {
+	res, err := deserializeResource(op.Resource, interner)
 	if err != nil {
 		return resource.Operation{}, err
 	}
@@ -287,31 +424,42 @@ func DeserializeOperation(op apitype.OperationV2) (resource.Operation, error) {
 
 // DeserializeProperties deserializes an entire map of deploy properties into a resource property map.
 func DeserializeProperties(props map[string]interface{}) (resource.PropertyMap, error) {
-	result := make(resource.PropertyMap)
+	return deserializeProperties(props, nil)
+}
+
+func deserializeProperties(props map[string]interface{}, interner *resource.Interner) (resource.PropertyMap, error) {
+	result := make(resource.PropertyMap, len(props))
 	for k, prop := range props {
-		desprop, err := DeserializePropertyValue(prop)
+		desprop, err := deserializePropertyValue(prop, interner)
 		if err != nil {
 			return nil, err
 		}
-		result[resource.PropertyKey(k)] = desprop
+		result[interner.Key(k)] = desprop
 	}
 	return result, nil
 }
 
 // DeserializePropertyValue deserializes a single deploy property into a resource property value.
 func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
+	return deserializePropertyValue(v, nil)
+}
+
+func deserializePropertyValue(v interface{}, interner *resource.Interner) (resource.PropertyValue, error) {
 	if v != nil {
 		switch w := v.(type) {
 		case bool:
-			return resource.NewBoolProperty(w), nil
+			return interner.Bool(w), nil
 		case float64:
-			return resource.NewNumberProperty(w), nil
+			return interner.Number(w), nil
 		case string:
-			return resource.NewStringProperty(w), nil
+			return interner.String(w), nil
 		case []interface{}:
 			var arr []resource.PropertyValue
+			if len(w) > 0 {
+				arr = make([]resource.PropertyValue, 0, len(w))
+			}
 			for _, elem := range w {
-				ev, err := DeserializePropertyValue(elem)
+				ev, err := deserializePropertyValue(elem, interner)
 				if err != nil {
 					return resource.PropertyValue{}, err
 				}
@@ -319,7 +467,7 @@ func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
 			}
 			return resource.NewArrayProperty(arr), nil
 		case map[string]interface{}:
-			obj, err := DeserializeProperties(w)
+			obj, err := deserializeProperties(w, interner)
 			if err != nil {
 				return resource.PropertyValue{}, err
 			}
@@ -342,9 +490,19 @@ func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
 					}
 					contract.Assert(isarchive)
 					return resource.NewArchiveProperty(archive), nil
+				case resource.FileRefSig:
+					ref, isref, err := resource.DeserializeFileRef(objmap)
+					if err != nil {
+						return resource.PropertyValue{}, err
+					}
+					contract.Assert(isref)
+					return resource.NewFileRefProperty(ref.Path, ref.Hash), nil
 				case resource.SecretSig:
-					return resource.PropertyValue{},
-						errors.New("this version of the Pulumi SDK does not support first-class secrets")
+					elem, has := obj["value"]
+					if !has {
+						return resource.PropertyValue{}, errors.New("secret is missing its value")
+					}
+					return resource.MakeSecret(elem), nil
 				default:
 					return resource.PropertyValue{}, errors.Errorf("unrecognized signature '%v' in property map", sig)
 				}
@@ -357,5 +515,5 @@ func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
 		}
 	}
 
-	return resource.NewNullProperty(), nil
+	return interner.Null(), nil
 }
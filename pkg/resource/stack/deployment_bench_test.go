@@ -0,0 +1,111 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// largeDeployment returns a serialized deployment resembling a stack with many similar resources, each of which
+// depends on a shared set of resources and carries the tags, regions, and flags that real cloud resources tend to.
+func largeDeployment(b *testing.B, count int) apitype.DeploymentV3 {
+	typ := tokens.Type("aws:ec2/instance:Instance")
+	var resources []*resource.State
+	var shared []resource.URN
+	for i := 0; i < count; i++ {
+		urn := resource.NewURN("stack", "project", "", typ, tokens.QName(fmt.Sprintf("instance-%d", i)))
+		props := resource.NewPropertyMapFromMap(map[string]interface{}{
+			"ami":                 "ami-7172b611",
+			"instanceType":        "t2.micro",
+			"availabilityZone":    "us-west-2a",
+			"ebsOptimized":        false,
+			"monitoring":          true,
+			"rootBlockDeviceSize": float64(8),
+			"tags": map[string]interface{}{
+				"Name":        fmt.Sprintf("instance-%d", i),
+				"Environment": "production",
+				"Owner":       "platform-team",
+			},
+			"securityGroups": []interface{}{"default", "web"},
+		})
+		resources = append(resources, resource.NewState(typ, urn, true, false, resource.ID(fmt.Sprintf("i-%08x", i)),
+			props, props, "", false, false, shared, nil, "", nil, false, false, nil, resource.CustomTimeouts{}, nil, nil))
+		if len(shared) < 8 {
+			shared = append(shared, urn)
+		}
+	}
+
+	// Round-trip the deployment through JSON so that every string is allocated separately, as it would be when a
+	// checkpoint is read from disk.
+	bytes, err := json.Marshal(SerializeDeployment(deploy.NewSnapshot(deploy.Manifest{}, resources, nil)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var deployment apitype.DeploymentV3
+	if err = json.Unmarshal(bytes, &deployment); err != nil {
+		b.Fatal(err)
+	}
+	return deployment
+}
+
+// benchmarkDeserializeDeployment deserializes a large deployment, logging the heap retained by the resulting snapshot
+// in addition to reporting the allocations made while building it.
+func benchmarkDeserializeDeployment(b *testing.B, intern bool) {
+	deployment := largeDeployment(b, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var retained int64
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		var interner *resource.Interner
+		if intern {
+			interner = resource.NewInterner()
+		}
+		resources := make([]*resource.State, 0, len(deployment.Resources))
+		for _, res := range deployment.Resources {
+			state, err := deserializeResource(res, interner)
+			if err != nil {
+				b.Fatal(err)
+			}
+			resources = append(resources, state)
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		retained += int64(after.HeapAlloc) - int64(before.HeapAlloc)
+		runtime.KeepAlive(resources)
+	}
+	b.Logf("retained %d bytes/op", retained/int64(b.N))
+}
+
+func BenchmarkDeserializeDeployment(b *testing.B) {
+	benchmarkDeserializeDeployment(b, false)
+}
+
+func BenchmarkDeserializeDeploymentInterned(b *testing.B) {
+	benchmarkDeserializeDeployment(b, true)
+}
@@ -15,6 +15,7 @@
 package stack
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -78,6 +79,7 @@ func TestDeploymentSerialization(t *testing.T) {
 		"",
 		nil,
 		false,
+		false, nil, resource.CustomTimeouts{}, nil, nil,
 	)
 
 	dep := SerializeResource(res)
@@ -149,6 +151,32 @@ func TestDeploymentSerialization(t *testing.T) {
 	assert.Equal(t, 0, len(dep.Outputs["out-empty-map"].(map[string]interface{})))
 }
 
+// TestCustomTimeoutsSerialization ensures that a resource's custom timeouts survive a round trip through its
+// deployment record, and that resources without custom timeouts do not record any.
+func TestCustomTimeoutsSerialization(t *testing.T) {
+	urn := resource.NewURN("test", "test", "", "pkgA:m:typA", "resA")
+	timeouts := resource.CustomTimeouts{Create: 300, Delete: 60}
+
+	res := resource.NewState("pkgA:m:typA", urn, true, false, "id", resource.PropertyMap{}, resource.PropertyMap{},
+		"", false, false, nil, nil, "", nil, false, false, nil, timeouts, nil, nil)
+	dep := SerializeResource(res)
+	assert.Equal(t, &timeouts, dep.CustomTimeouts)
+
+	b, err := json.Marshal(dep)
+	assert.NoError(t, err)
+	var roundTripped apitype.ResourceV3
+	err = json.Unmarshal(b, &roundTripped)
+	assert.NoError(t, err)
+
+	state, err := DeserializeResource(roundTripped)
+	assert.NoError(t, err)
+	assert.Equal(t, timeouts, state.CustomTimeouts)
+
+	res.CustomTimeouts = resource.CustomTimeouts{}
+	dep = SerializeResource(res)
+	assert.Nil(t, dep.CustomTimeouts)
+}
+
 func TestLoadTooNewDeployment(t *testing.T) {
 	untypedDeployment := &apitype.UntypedDeployment{
 		Version: apitype.DeploymentSchemaVersionCurrent + 1,
@@ -171,6 +199,67 @@ func TestLoadTooOldDeployment(t *testing.T) {
 	assert.Equal(t, ErrDeploymentSchemaVersionTooOld, err)
 }
 
+func TestMigrateDeployment(t *testing.T) {
+	v3 := apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{{
+			URN:    resource.URN("urn:pulumi:test::test::pkg:index:type::name"),
+			Custom: true,
+			ID:     resource.ID("id"),
+			Type:   tokens.Type("pkg:index:type"),
+		}},
+	}
+	bytes, err := json.Marshal(v3)
+	assert.NoError(t, err)
+	untypedDeployment := &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(bytes),
+	}
+
+	// Writing an older version must be explicitly allowed.
+	_, err = MigrateUntypedDeployment(untypedDeployment, 2, false)
+	assert.Equal(t, ErrDeploymentDowngradeRequired, err)
+
+	v2deployment, err := MigrateUntypedDeployment(untypedDeployment, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v2deployment.Version)
+
+	// The downgraded deployment must still load, and upgrading it again must not require a flag.
+	snap, err := DeserializeUntypedDeployment(v2deployment)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 1)
+
+	v3deployment, err := MigrateUntypedDeployment(v2deployment, 3, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v3deployment.Version)
+
+	// Deployments that use newer features cannot be downgraded at all.
+	v3.Resources[0].PendingReplacement = true
+	bytes, err = json.Marshal(v3)
+	assert.NoError(t, err)
+	untypedDeployment.Deployment = json.RawMessage(bytes)
+	_, err = MigrateUntypedDeployment(untypedDeployment, 2, true)
+	assert.Error(t, err)
+}
+
+// TestFileRefSerialization checks that file references are saved as references rather than as the values that they
+// refer to.
+func TestFileRefSerialization(t *testing.T) {
+	ref := resource.NewFileRefProperty("template.yaml", "1f2e3d4c")
+	serialized := SerializePropertyValue(ref)
+	assert.Equal(t, map[string]interface{}{
+		resource.SigKey: resource.FileRefSig,
+		"path":          "template.yaml",
+		"hash":          "1f2e3d4c",
+	}, serialized)
+
+	deserialized, err := DeserializePropertyValue(serialized)
+	assert.NoError(t, err)
+	assert.Equal(t, ref, deserialized)
+
+	_, err = DeserializePropertyValue(map[string]interface{}{resource.SigKey: resource.FileRefSig, "path": "a"})
+	assert.Error(t, err)
+}
+
 func TestUnsupportedSecret(t *testing.T) {
 	rawProp := map[string]interface{}{
 		resource.SigKey: resource.SecretSig,
@@ -179,6 +268,21 @@ func TestUnsupportedSecret(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSecretRoundTrip(t *testing.T) {
+	props := resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"keys": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.MakeSecret(resource.NewObjectProperty(resource.PropertyMap{
+				"id": resource.NewStringProperty("k1"),
+			})),
+		}),
+	}
+	serialized := SerializeProperties(props)
+	deserialized, err := DeserializeProperties(serialized)
+	assert.NoError(t, err)
+	assert.Equal(t, props, deserialized)
+}
+
 func TestUnknownSig(t *testing.T) {
 	rawProp := map[string]interface{}{
 		resource.SigKey: "foobar",
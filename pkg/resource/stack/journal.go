@@ -0,0 +1,96 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+)
+
+// DiffDeployments returns the journal entry that turns the old deployment, which may be nil, into the new one.
+func DiffDeployments(journal string, sequence int, old, new *apitype.DeploymentV3) apitype.JournalEntryV1 {
+	var olds []apitype.ResourceV3
+	if old != nil {
+		olds = old.Resources
+	}
+	news := new.Resources
+
+	prefix := 0
+	for prefix < len(olds) && prefix < len(news) && reflect.DeepEqual(olds[prefix], news[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(olds)-prefix && suffix < len(news)-prefix &&
+		reflect.DeepEqual(olds[len(olds)-1-suffix], news[len(news)-1-suffix]) {
+		suffix++
+	}
+
+	return apitype.JournalEntryV1{
+		Journal:           journal,
+		Sequence:          sequence,
+		Manifest:          new.Manifest,
+		KeepPrefix:        prefix,
+		KeepSuffix:        suffix,
+		Resources:         news[prefix : len(news)-suffix],
+		PendingOperations: new.PendingOperations,
+	}
+}
+
+// ReplayJournal brings the given checkpoint, whose journal has the given ID, up to date by applying the entries of
+// that journal in sequence. Entries of other journals are ignored. It is an error for an entry to be missing from
+// the sequence, or for an entry to keep more resources than the deployment has.
+func ReplayJournal(chk *apitype.CheckpointV3, journal string, entries []apitype.JournalEntryV1) error {
+	if journal == "" {
+		return nil
+	}
+
+	sequence := 0
+	for _, entry := range entries {
+		if entry.Journal != journal {
+			continue
+		}
+		if entry.Sequence != sequence+1 {
+			return errors.Errorf("journal %s is missing entry %d", journal, sequence+1)
+		}
+		sequence = entry.Sequence
+
+		latest := chk.Latest
+		if latest == nil {
+			latest = &apitype.DeploymentV3{}
+		}
+		olds := latest.Resources
+		if entry.KeepPrefix < 0 || entry.KeepSuffix < 0 || entry.KeepPrefix+entry.KeepSuffix > len(olds) {
+			return errors.Errorf("journal %s entry %d keeps %d+%d of only %d resources", journal, entry.Sequence,
+				entry.KeepPrefix, entry.KeepSuffix, len(olds))
+		}
+
+		resources := make([]apitype.ResourceV3, 0, entry.KeepPrefix+len(entry.Resources)+entry.KeepSuffix)
+		resources = append(resources, olds[:entry.KeepPrefix]...)
+		resources = append(resources, entry.Resources...)
+		resources = append(resources, olds[len(olds)-entry.KeepSuffix:]...)
+
+		chk.Latest = &apitype.DeploymentV3{
+			Manifest:          entry.Manifest,
+			SecretsProviders:  latest.SecretsProviders,
+			Resources:         resources,
+			PendingOperations: entry.PendingOperations,
+			SharedValues:      latest.SharedValues,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+)
+
+func journalDeployment(ops []apitype.OperationV2, urns ...string) *apitype.DeploymentV3 {
+	d := &apitype.DeploymentV3{PendingOperations: ops}
+	for _, urn := range urns {
+		d.Resources = append(d.Resources, apitype.ResourceV3{URN: resource.URN(urn), Type: "test:index:Res"})
+	}
+	return d
+}
+
+func TestJournalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	creating := []apitype.OperationV2{{
+		Resource: apitype.ResourceV3{URN: "d", Type: "test:index:Res"},
+		Type:     apitype.OperationTypeCreating,
+	}}
+	steps := []*apitype.DeploymentV3{
+		journalDeployment(nil, "a", "b", "c"),
+		journalDeployment(creating, "a", "b", "c"),
+		journalDeployment(nil, "a", "d", "b", "c"),
+		journalDeployment(nil, "a", "d", "c"),
+		journalDeployment(nil, "d", "c", "e"),
+	}
+	// Outputs registered for an existing resource change it in place.
+	steps = append(steps, journalDeployment(nil, "d", "c", "e"))
+	steps[5].Resources[1].Outputs = map[string]interface{}{"size": 3.0}
+
+	chk := &apitype.CheckpointV3{Latest: steps[0]}
+	var entries []apitype.JournalEntryV1
+	for i := 1; i < len(steps); i++ {
+		entries = append(entries, DiffDeployments("j1", i, steps[i-1], steps[i]))
+	}
+	assert.Equal(t, 1, entries[1].KeepPrefix)
+	assert.Equal(t, 2, entries[1].KeepSuffix)
+	assert.Len(t, entries[1].Resources, 1)
+
+	// Entries of other journals, such as those left behind by a crash during compaction, are ignored.
+	stale := DiffDeployments("j0", 1, steps[0], journalDeployment(nil))
+	assert.NoError(t, ReplayJournal(chk, "j1", append([]apitype.JournalEntryV1{stale}, entries...)))
+	assert.Equal(t, steps[len(steps)-1], chk.Latest)
+
+	// A gap in the sequence is an error.
+	chk = &apitype.CheckpointV3{Latest: steps[0]}
+	assert.Error(t, ReplayJournal(chk, "j1", entries[1:]))
+}
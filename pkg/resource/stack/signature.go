@@ -0,0 +1,274 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/version"
+)
+
+// checkpointSignatureDomain is prepended to the data that is signed, so that a checkpoint signature cannot be
+// mistaken for a signature over anything else.
+const checkpointSignatureDomain = "pulumi-checkpoint-v1\x00"
+
+// ecdsaAlgorithm is the name of the ECDSA with SHA-256 signature algorithm.
+const ecdsaAlgorithm = "ecdsa-sha256"
+
+// CheckpointSigner signs the digests of checkpoints.  Signers backed by a key management service may implement this
+// interface in place of a local key.
+type CheckpointSigner interface {
+	// Algorithm returns the name of the signature algorithm.
+	Algorithm() string
+	// KeyID returns an identifier for the signing key, which is recorded alongside each signature.
+	KeyID() string
+	// Sign returns the signature for the given SHA-256 digest.
+	Sign(digest []byte) ([]byte, error)
+}
+
+// CheckpointVerifier verifies the signatures of checkpoints.
+type CheckpointVerifier interface {
+	// Verify returns an error if the given signature is not a valid signature for the given SHA-256 digest.
+	Verify(sig apitype.CheckpointSignatureV1, digest []byte) error
+}
+
+// NewProvenance returns the provenance of a checkpoint for the given snapshot, which may be nil.  The git commit of the
+// program is optional.
+func NewProvenance(snap *deploy.Snapshot, gitCommit string, gitDirty bool) *apitype.ProvenanceV1 {
+	prov := &apitype.ProvenanceV1{
+		EngineVersion: version.Version,
+		GitCommit:     gitCommit,
+		GitDirty:      gitCommit != "" && gitDirty,
+	}
+	if snap != nil {
+		for _, plug := range snap.Manifest.Plugins {
+			var v string
+			if plug.Version != nil {
+				v = plug.Version.String()
+			}
+			prov.Plugins = append(prov.Plugins, apitype.ProvenancePluginV1{Name: plug.Name, Kind: plug.Kind, Version: v})
+		}
+	}
+	return prov
+}
+
+// checkpointDigest returns the digest that is signed for the given checkpoint.  The checkpoint document is compacted
+// first, so that the digest does not depend on how the document was indented when it was written.
+func checkpointDigest(chk *apitype.VersionedCheckpoint) ([]byte, error) {
+	var doc bytes.Buffer
+	if err := json.Compact(&doc, chk.Checkpoint); err != nil {
+		return nil, errors.Wrap(err, "compacting checkpoint")
+	}
+	prov, err := json.Marshal(chk.Provenance)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	_, err = h.Write([]byte(checkpointSignatureDomain))
+	contract.IgnoreError(err)
+	_, err = h.Write(doc.Bytes())
+	contract.IgnoreError(err)
+	_, err = h.Write([]byte{0})
+	contract.IgnoreError(err)
+	_, err = h.Write(prov)
+	contract.IgnoreError(err)
+	return h.Sum(nil), nil
+}
+
+// SignCheckpoint signs the given checkpoint, including its provenance, and records the signature in it.
+func SignCheckpoint(chk *apitype.VersionedCheckpoint, signer CheckpointSigner) error {
+	contract.Require(chk != nil, "chk")
+	contract.Require(signer != nil, "signer")
+
+	digest, err := checkpointDigest(chk)
+	if err != nil {
+		return err
+	}
+	sig, err := signer.Sign(digest)
+	if err != nil {
+		return errors.Wrap(err, "signing checkpoint")
+	}
+	chk.Signature = &apitype.CheckpointSignatureV1{
+		Algorithm: signer.Algorithm(),
+		KeyID:     signer.KeyID(),
+		Value:     base64.StdEncoding.EncodeToString(sig),
+	}
+	return nil
+}
+
+// VerifyCheckpoint returns an error if the given checkpoint is unsigned or if its signature is not valid.
+func VerifyCheckpoint(chk *apitype.VersionedCheckpoint, verifier CheckpointVerifier) error {
+	contract.Require(chk != nil, "chk")
+	contract.Require(verifier != nil, "verifier")
+
+	if chk.Signature == nil {
+		return errors.New("checkpoint is not signed")
+	}
+	digest, err := checkpointDigest(chk)
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(*chk.Signature, digest)
+}
+
+// ecdsaSignature is the ASN.1 encoding of an ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ecdsaKeyID returns the identifier of an ECDSA public key: a prefix of the SHA-256 digest of its DER encoding.
+func ecdsaKeyID(key *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// NewECDSACheckpointSigner returns a checkpoint signer that signs with the given ECDSA private key.
+func NewECDSACheckpointSigner(key *ecdsa.PrivateKey) (CheckpointSigner, error) {
+	id, err := ecdsaKeyID(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsaSigner{key: key, id: id}, nil
+}
+
+type ecdsaSigner struct {
+	key *ecdsa.PrivateKey
+	id  string
+}
+
+func (s *ecdsaSigner) Algorithm() string { return ecdsaAlgorithm }
+func (s *ecdsaSigner) KeyID() string     { return s.id }
+
+func (s *ecdsaSigner) Sign(digest []byte) ([]byte, error) {
+	r, ss, err := ecdsa.Sign(rand.Reader, s.key, digest)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ecdsaSignature{R: r, S: ss})
+}
+
+// NewECDSACheckpointVerifier returns a checkpoint verifier that accepts signatures made by any of the given ECDSA keys.
+func NewECDSACheckpointVerifier(keys ...*ecdsa.PublicKey) (CheckpointVerifier, error) {
+	v := &ecdsaVerifier{keys: make(map[string]*ecdsa.PublicKey)}
+	for _, key := range keys {
+		id, err := ecdsaKeyID(key)
+		if err != nil {
+			return nil, err
+		}
+		v.keys[id] = key
+	}
+	return v, nil
+}
+
+type ecdsaVerifier struct {
+	keys map[string]*ecdsa.PublicKey
+}
+
+func (v *ecdsaVerifier) Verify(sig apitype.CheckpointSignatureV1, digest []byte) error {
+	if sig.Algorithm != ecdsaAlgorithm {
+		return errors.Errorf("unsupported checkpoint signature algorithm '%s'", sig.Algorithm)
+	}
+	key, has := v.keys[sig.KeyID]
+	if !has {
+		return errors.Errorf("checkpoint is signed by unknown key '%s'", sig.KeyID)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return errors.Wrap(err, "decoding checkpoint signature")
+	}
+	var es ecdsaSignature
+	if rest, err := asn1.Unmarshal(der, &es); err != nil || len(rest) != 0 || es.R == nil || es.S == nil {
+		return errors.New("malformed checkpoint signature")
+	}
+	if !ecdsa.Verify(key, digest, es.R, es.S) {
+		return errors.New("checkpoint signature does not match its contents")
+	}
+	return nil
+}
+
+// LoadCheckpointSigner returns a checkpoint signer for the PEM-encoded ECDSA private key in the given file.
+func LoadCheckpointSigner(path string) (CheckpointSigner, error) {
+	key, err := loadECDSAKey(path)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("%s does not hold an ECDSA private key", path)
+	}
+	return NewECDSACheckpointSigner(priv)
+}
+
+// LoadCheckpointVerifier returns a checkpoint verifier for the PEM-encoded ECDSA public key, or the public half of the
+// PEM-encoded ECDSA private key, in the given file.
+func LoadCheckpointVerifier(path string) (CheckpointVerifier, error) {
+	key, err := loadECDSAKey(path)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return NewECDSACheckpointVerifier(&key.PublicKey)
+	case *ecdsa.PublicKey:
+		return NewECDSACheckpointVerifier(key)
+	default:
+		return nil, errors.Errorf("%s does not hold an ECDSA key", path)
+	}
+}
+
+// loadECDSAKey reads the first PEM block from the given file and parses it as a private or public key.
+func loadECDSAKey(path string) (interface{}, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("%s does not hold a PEM-encoded key", path)
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, errors.Errorf("%s holds an unsupported PEM block of type '%s'", path, block.Type)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+)
+
+func newTestSignerAndVerifier(t *testing.T) (CheckpointSigner, CheckpointVerifier) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signer, err := NewECDSACheckpointSigner(key)
+	assert.NoError(t, err)
+	verifier, err := NewECDSACheckpointVerifier(&key.PublicKey)
+	assert.NoError(t, err)
+	return signer, verifier
+}
+
+func TestSignCheckpoint(t *testing.T) {
+	signer, verifier := newTestSignerAndVerifier(t)
+
+	chk := SerializeCheckpoint("test", nil, nil)
+	chk.Provenance = NewProvenance(nil, "0123456789abcdef", true)
+	assert.NoError(t, SignCheckpoint(chk, signer))
+	assert.NotNil(t, chk.Signature)
+	assert.Equal(t, signer.KeyID(), chk.Signature.KeyID)
+	assert.NoError(t, VerifyCheckpoint(chk, verifier))
+
+	// The signature survives the checkpoint being written with indentation and read back.
+	b, err := json.MarshalIndent(chk, "", "    ")
+	assert.NoError(t, err)
+	var read apitype.VersionedCheckpoint
+	assert.NoError(t, json.Unmarshal(b, &read))
+	assert.NoError(t, VerifyCheckpoint(&read, verifier))
+
+	// Changing the provenance invalidates the signature.
+	read.Provenance.GitCommit = "fedcba9876543210"
+	assert.Error(t, VerifyCheckpoint(&read, verifier))
+
+	// So does changing the checkpoint itself.
+	tampered := SerializeCheckpoint("other", nil, nil)
+	tampered.Provenance, tampered.Signature = chk.Provenance, chk.Signature
+	assert.Error(t, VerifyCheckpoint(tampered, verifier))
+
+	// And a signature by another key is rejected.
+	_, other := newTestSignerAndVerifier(t)
+	assert.Error(t, VerifyCheckpoint(chk, other))
+}
+
+func TestVerifyUnsignedCheckpoint(t *testing.T) {
+	_, verifier := newTestSignerAndVerifier(t)
+
+	chk := SerializeCheckpoint("test", nil, nil)
+	assert.Error(t, VerifyCheckpoint(chk, verifier))
+}
@@ -0,0 +1,168 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testing provides generators of arbitrary resource property values, and harnesses that check that those
+// values survive the round trips through RPC and checkpoint serialization that the engine puts them through.
+package testing
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// GeneratorOptions controls the shape of generated property values.
+type GeneratorOptions struct {
+	MaxDepth  int  // the maximum depth of nested arrays and objects.
+	MaxLength int  // the maximum number of elements in arrays, objects, and archives, and of characters in strings.
+	Unknowns  bool // true to generate computed and output values.
+	Assets    bool // true to generate assets and archives.
+}
+
+// DefaultGeneratorOptions generates every kind of property value.
+var DefaultGeneratorOptions = GeneratorOptions{MaxDepth: 3, MaxLength: 5, Unknowns: true, Assets: true}
+
+// stringRunes are the runes from which generated strings are drawn. They include characters that need escaping in
+// JSON, and multi-byte characters.
+var stringRunes = []rune("abcXYZ019 _-.:/\"\\\n\té日\U0001F600")
+
+// Values fills the given arguments with arbitrary property maps. It is suitable for use as the Values function of a
+// quick.Config for properties that take property maps as arguments.
+func (opts GeneratorOptions) Values(args []reflect.Value, r *rand.Rand) {
+	for i := range args {
+		args[i] = reflect.ValueOf(opts.PropertyMap(r))
+	}
+}
+
+// PropertyMap generates an arbitrary property map.
+func (opts GeneratorOptions) PropertyMap(r *rand.Rand) resource.PropertyMap {
+	return opts.propertyMap(r, opts.MaxDepth)
+}
+
+// PropertyValue generates an arbitrary property value.
+func (opts GeneratorOptions) PropertyValue(r *rand.Rand) resource.PropertyValue {
+	return opts.propertyValue(r, opts.MaxDepth)
+}
+
+func (opts GeneratorOptions) propertyMap(r *rand.Rand, depth int) resource.PropertyMap {
+	result := make(resource.PropertyMap)
+	for n := r.Intn(opts.MaxLength + 1); n > 0; n-- {
+		result[resource.PropertyKey(opts.string(r))] = opts.propertyValue(r, depth)
+	}
+	return result
+}
+
+func (opts GeneratorOptions) propertyValue(r *rand.Rand, depth int) resource.PropertyValue {
+	for {
+		switch r.Intn(10) {
+		case 0:
+			return resource.NewNullProperty()
+		case 1:
+			return resource.NewBoolProperty(r.Intn(2) == 0)
+		case 2:
+			return resource.NewNumberProperty(opts.number(r))
+		case 3:
+			return resource.NewStringProperty(opts.string(r))
+		case 4:
+			if depth > 0 {
+				arr := []resource.PropertyValue{}
+				for n := r.Intn(opts.MaxLength + 1); n > 0; n-- {
+					arr = append(arr, opts.propertyValue(r, depth-1))
+				}
+				return resource.NewArrayProperty(arr)
+			}
+		case 5:
+			if depth > 0 {
+				return resource.NewObjectProperty(opts.propertyMap(r, depth-1))
+			}
+		case 6:
+			if opts.Assets {
+				return resource.NewAssetProperty(opts.asset(r))
+			}
+		case 7:
+			if opts.Assets {
+				return resource.NewArchiveProperty(opts.archive(r))
+			}
+		case 8:
+			if opts.Unknowns {
+				return resource.MakeComputed(opts.unknownElement(r))
+			}
+		case 9:
+			if opts.Unknowns {
+				return resource.MakeOutput(opts.unknownElement(r))
+			}
+		}
+	}
+}
+
+// number generates a finite number, which is either an integer or a fraction.
+func (opts GeneratorOptions) number(r *rand.Rand) float64 {
+	if r.Intn(2) == 0 {
+		return float64(r.Int63n(1<<53) - 1<<52)
+	}
+	return r.NormFloat64() * 1e6
+}
+
+func (opts GeneratorOptions) string(r *rand.Rand) string {
+	runes := make([]rune, r.Intn(opts.MaxLength+1))
+	for i := range runes {
+		runes[i] = stringRunes[r.Intn(len(stringRunes))]
+	}
+	return string(runes)
+}
+
+func (opts GeneratorOptions) asset(r *rand.Rand) *resource.Asset {
+	asset, err := resource.NewTextAsset(opts.string(r) + "\n")
+	contract.AssertNoError(err)
+	return asset
+}
+
+func (opts GeneratorOptions) archive(r *rand.Rand) *resource.Archive {
+	assets := make(map[string]interface{})
+	for n := r.Intn(opts.MaxLength + 1); n > 0; n-- {
+		assets[fmt.Sprintf("file%d.txt", n)] = opts.asset(r)
+	}
+	archive, err := resource.NewAssetArchive(assets)
+	contract.AssertNoError(err)
+	return archive
+}
+
+// unknownElement generates the element of an unknown value. Like the elements of the unknowns that the engine creates,
+// it is the zero value of a non-null kind.
+func (opts GeneratorOptions) unknownElement(r *rand.Rand) resource.PropertyValue {
+	kinds := []resource.PropertyValue{
+		resource.NewBoolProperty(false),
+		resource.NewNumberProperty(0),
+		resource.NewStringProperty(""),
+		resource.NewArrayProperty([]resource.PropertyValue{}),
+		resource.NewObjectProperty(resource.PropertyMap{}),
+	}
+	if opts.Assets {
+		kinds = append(kinds,
+			resource.NewAssetProperty(&resource.Asset{}),
+			resource.NewArchiveProperty(&resource.Archive{}))
+	}
+	return kinds[r.Intn(len(kinds))]
+}
+
+// ArbitraryPropertyMap is a property map that testing/quick generates using DefaultGeneratorOptions.
+type ArbitraryPropertyMap resource.PropertyMap
+
+// Generate implements quick.Generator.
+func (ArbitraryPropertyMap) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(ArbitraryPropertyMap(DefaultGeneratorOptions.PropertyMap(r)))
+}
@@ -0,0 +1,233 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/quick"
+
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+)
+
+// RPCRoundTrip marshals the given properties to their wire form using the given options, encodes and decodes that
+// form as protobuf, and unmarshals the result.
+func RPCRoundTrip(props resource.PropertyMap, opts plugin.MarshalOptions) (resource.PropertyMap, error) {
+	m, err := plugin.MarshalProperties(props, opts)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var decoded structpb.Struct
+	if err = proto.Unmarshal(bytes, &decoded); err != nil {
+		return nil, err
+	}
+	return plugin.UnmarshalProperties(&decoded, opts)
+}
+
+// JSONRoundTrip serializes the given properties as they are serialized in checkpoints, encodes and decodes the result
+// as JSON, and deserializes it.
+func JSONRoundTrip(props resource.PropertyMap) (resource.PropertyMap, error) {
+	bytes, err := json.Marshal(stack.SerializeProperties(props))
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err = json.Unmarshal(bytes, &decoded); err != nil {
+		return nil, err
+	}
+	return stack.DeserializeProperties(decoded)
+}
+
+// ExpectedRPCRoundTrip returns the result that RPCRoundTrip is expected to produce for the given properties. Outputs
+// are dropped from objects, as are unknowns unless the options keep them and nulls if the options skip them. Unknowns
+// that are kept come back as computed values whose elements are the zero values of their kinds.
+func ExpectedRPCRoundTrip(props resource.PropertyMap, opts plugin.MarshalOptions) resource.PropertyMap {
+	result := make(resource.PropertyMap)
+	for k, v := range props {
+		if v.IsOutput() || v.IsComputed() && !opts.KeepUnknowns || v.IsNull() && opts.SkipNulls {
+			continue
+		}
+		result[k] = expectedRPCValue(v, opts)
+	}
+	return result
+}
+
+func expectedRPCValue(v resource.PropertyValue, opts plugin.MarshalOptions) resource.PropertyValue {
+	switch {
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			arr[i] = expectedRPCValue(elem, opts)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		return resource.NewObjectProperty(ExpectedRPCRoundTrip(v.ObjectValue(), opts))
+	case v.IsComputed():
+		return resource.MakeComputed(unknownElement(v.Input().Element))
+	case v.IsOutput():
+		return resource.MakeComputed(unknownElement(v.OutputValue().Element))
+	default:
+		return v
+	}
+}
+
+// unknownElement returns the zero value of the kind of the given unknown element.
+func unknownElement(elem resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case elem.IsBool():
+		return resource.NewBoolProperty(false)
+	case elem.IsNumber():
+		return resource.NewNumberProperty(0)
+	case elem.IsString():
+		return resource.NewStringProperty("")
+	case elem.IsArray():
+		return resource.NewArrayProperty([]resource.PropertyValue{})
+	case elem.IsAsset():
+		return resource.NewAssetProperty(&resource.Asset{})
+	case elem.IsArchive():
+		return resource.NewArchiveProperty(&resource.Archive{})
+	case elem.IsObject():
+		return resource.NewObjectProperty(resource.PropertyMap{})
+	case elem.IsComputed():
+		return unknownElement(elem.Input().Element)
+	case elem.IsOutput():
+		return unknownElement(elem.OutputValue().Element)
+	default:
+		return elem
+	}
+}
+
+// ExpectedJSONRoundTrip returns the result that JSONRoundTrip is expected to produce for the given properties. Nulls,
+// computed values, and outputs are dropped from objects, and become nulls in arrays.
+func ExpectedJSONRoundTrip(props resource.PropertyMap) resource.PropertyMap {
+	result := make(resource.PropertyMap)
+	for k, v := range props {
+		if !v.IsComputed() && v.HasValue() {
+			result[k] = expectedJSONValue(v)
+		}
+	}
+	return result
+}
+
+func expectedJSONValue(v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, elem := range v.ArrayValue() {
+			arr[i] = expectedJSONValue(elem)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		return resource.NewObjectProperty(ExpectedJSONRoundTrip(v.ObjectValue()))
+	case v.IsComputed() || v.IsOutput():
+		return resource.NewNullProperty()
+	default:
+		return v
+	}
+}
+
+// Equal returns true if the given property maps are equal. Unlike PropertyMap.DeepEquals, it distinguishes null
+// properties from absent ones, and compares unknowns by kind rather than panicking on uncomparable elements.
+func Equal(a, b resource.PropertyMap) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, has := b[k]; !has || !EqualValues(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualValues returns true if the given property values are equal, in the sense of Equal.
+func EqualValues(a, b resource.PropertyValue) bool {
+	switch {
+	case a.IsArray():
+		if !b.IsArray() || len(a.ArrayValue()) != len(b.ArrayValue()) {
+			return false
+		}
+		for i, elem := range a.ArrayValue() {
+			if !EqualValues(elem, b.ArrayValue()[i]) {
+				return false
+			}
+		}
+		return true
+	case a.IsObject():
+		return b.IsObject() && Equal(a.ObjectValue(), b.ObjectValue())
+	case a.IsAsset():
+		return b.IsAsset() && a.AssetValue().Equals(b.AssetValue())
+	case a.IsArchive():
+		return b.IsArchive() && a.ArchiveValue().Equals(b.ArchiveValue())
+	case a.IsComputed():
+		return b.IsComputed() && a.Input().Element.TypeString() == b.Input().Element.TypeString()
+	case a.IsOutput():
+		return b.IsOutput() && a.OutputValue().Element.TypeString() == b.OutputValue().Element.TypeString()
+	default:
+		return a.V == b.V
+	}
+}
+
+// CheckRPCRoundTrip checks that arbitrary property maps survive RPCRoundTrip with the given options as
+// ExpectedRPCRoundTrip describes. Unknowns are only generated if the options keep them. The config may be nil.
+func CheckRPCRoundTrip(t *testing.T, opts plugin.MarshalOptions, config *quick.Config) {
+	gen := DefaultGeneratorOptions
+	gen.Unknowns = opts.KeepUnknowns && !opts.RejectUnknowns
+	checkRoundTrip(t, gen, config,
+		func(props resource.PropertyMap) (resource.PropertyMap, error) { return RPCRoundTrip(props, opts) },
+		func(props resource.PropertyMap) resource.PropertyMap { return ExpectedRPCRoundTrip(props, opts) })
+}
+
+// CheckJSONRoundTrip checks that arbitrary property maps survive JSONRoundTrip as ExpectedJSONRoundTrip describes.
+// The config may be nil.
+func CheckJSONRoundTrip(t *testing.T, config *quick.Config) {
+	checkRoundTrip(t, DefaultGeneratorOptions, config, JSONRoundTrip, ExpectedJSONRoundTrip)
+}
+
+func checkRoundTrip(t *testing.T, gen GeneratorOptions, config *quick.Config,
+	roundTrip func(resource.PropertyMap) (resource.PropertyMap, error),
+	expected func(resource.PropertyMap) resource.PropertyMap) {
+
+	var c quick.Config
+	if config != nil {
+		c = *config
+	}
+	c.Values = gen.Values
+
+	err := quick.Check(func(props resource.PropertyMap) bool {
+		actual, err := roundTrip(props)
+		if err != nil {
+			t.Logf("round trip of %v failed: %v", props, err)
+			return false
+		}
+		if expect := expected(props); !Equal(expect, actual) {
+			t.Logf("round trip of %v: expected %v, got %v", props, expect, actual)
+			return false
+		}
+		return true
+	}, &c)
+	if err != nil {
+		t.Error(err)
+	}
+}
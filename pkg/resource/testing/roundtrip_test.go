@@ -0,0 +1,87 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+)
+
+func TestGenerateAllKinds(t *testing.T) {
+	kinds := make(map[string]bool)
+	var visit func(v resource.PropertyValue)
+	visit = func(v resource.PropertyValue) {
+		kinds[v.TypeString()] = true
+		switch {
+		case v.IsArray():
+			for _, elem := range v.ArrayValue() {
+				visit(elem)
+			}
+		case v.IsObject():
+			for _, elem := range v.ObjectValue() {
+				visit(elem)
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		visit(DefaultGeneratorOptions.PropertyValue(r))
+	}
+	for _, kind := range []string{"null", "bool", "number", "string", "[]", "object", "asset", "archive",
+		"output<bool>", "output<number>", "output<string>", "output<[]>", "output<object>"} {
+		assert.True(t, kinds[kind], "no %v generated", kind)
+	}
+}
+
+func TestArbitraryPropertyMap(t *testing.T) {
+	err := quick.Check(func(props ArbitraryPropertyMap) bool {
+		return Equal(resource.PropertyMap(props), resource.PropertyMap(props).Copy())
+	}, nil)
+	assert.NoError(t, err)
+}
+
+func TestEqual(t *testing.T) {
+	// Nulls are distinguished from absent properties.
+	assert.False(t, Equal(resource.PropertyMap{"a": resource.NewNullProperty()}, resource.PropertyMap{}))
+
+	// Unknowns are compared by kind.
+	assert.True(t, EqualValues(
+		resource.MakeComputed(resource.NewArrayProperty([]resource.PropertyValue{})),
+		resource.MakeComputed(resource.NewArrayProperty(nil))))
+	assert.False(t, EqualValues(
+		resource.MakeComputed(resource.NewStringProperty("")),
+		resource.MakeOutput(resource.NewStringProperty(""))))
+	assert.False(t, EqualValues(
+		resource.MakeComputed(resource.NewStringProperty("")),
+		resource.MakeComputed(resource.NewNumberProperty(0))))
+}
+
+func TestRPCRoundTrip(t *testing.T) {
+	CheckRPCRoundTrip(t, plugin.MarshalOptions{}, nil)
+	CheckRPCRoundTrip(t, plugin.MarshalOptions{KeepUnknowns: true}, nil)
+	CheckRPCRoundTrip(t, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true}, nil)
+	CheckRPCRoundTrip(t, plugin.MarshalOptions{KeepUnknowns: true, Interner: resource.NewInterner()}, nil)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	CheckJSONRoundTrip(t, nil)
+}
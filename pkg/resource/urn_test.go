@@ -49,3 +49,30 @@ func TestURNRoundTripping2(t *testing.T) {
 	assert.Equal(t, typ, urn.Type())
 	assert.Equal(t, name, urn.Name())
 }
+
+func TestIndexedNames(t *testing.T) {
+	name := IndexedName("web", 12)
+	assert.Equal(t, tokens.QName("web[12]"), name)
+	base, index, ok := ParseIndexedName(name)
+	assert.True(t, ok)
+	assert.Equal(t, tokens.QName("web"), base)
+	assert.Equal(t, 12, index)
+
+	for _, n := range []tokens.QName{"web", "web[]", "web[x]", "[0]", "web[0]x"} {
+		_, _, ok = ParseIndexedName(n)
+		assert.False(t, ok, n)
+		assert.False(t, IsMalformedIndexedName(n), n)
+	}
+	for _, n := range []tokens.QName{"web[01]", "web[-1]", "web[+1]"} {
+		_, _, ok = ParseIndexedName(n)
+		assert.False(t, ok, n)
+	}
+	assert.True(t, IsMalformedIndexedName("web[01]"))
+	assert.True(t, IsMalformedIndexedName("web[-1]"))
+
+	urn := NewURN("stck", "proj", "parent$type", "pkg:m:typ", IndexedName("web", 3))
+	collection, index, ok := urn.CollectionIndex()
+	assert.True(t, ok)
+	assert.Equal(t, 3, index)
+	assert.Equal(t, NewURN("stck", "proj", "parent$type", "pkg:m:typ", "web"), collection)
+}
@@ -0,0 +1,101 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TreeDigest returns a digest of the names, sizes, and modification times of the files at or beneath each of the given
+// paths. Directories whose names appear in skipDirs are not descended into. Paths that do not exist contribute to the
+// digest as missing, so creating or removing them changes it.
+func TreeDigest(paths []string, skipDirs []string) (string, error) {
+	skip := make(map[string]bool, len(skipDirs))
+	for _, dir := range skipDirs {
+		skip[dir] = true
+	}
+
+	h := sha256.New()
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				// Files may be removed while the tree is being walked; only the roots are recorded as missing.
+				if path == root {
+					_, err = fmt.Fprintf(h, "%s missing\n", path)
+					return err
+				}
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != root && skip[info.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			_, err = fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Watch polls the given paths every interval until the context is done, and calls onChange whenever their TreeDigest
+// changes. So that a burst of writes (for example, an editor saving several files) produces a single call, onChange
+// is only called once the paths have stopped changing for a full interval. onChange is called synchronously, so
+// changes made while it runs are reported after it returns.
+func Watch(ctx context.Context, paths []string, skipDirs []string, interval time.Duration, onChange func()) error {
+	last, err := TreeDigest(paths, skipDirs)
+	if err != nil {
+		return err
+	}
+
+	pending := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := TreeDigest(paths, skipDirs)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case current == last:
+			pending = ""
+		case current != pending:
+			// The paths have changed since the last poll; wait for them to settle.
+			pending = current
+		default:
+			last, pending = current, ""
+			onChange()
+		}
+	}
+}
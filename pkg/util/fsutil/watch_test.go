@@ -0,0 +1,88 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsutil
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeDigest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-watch-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "Pulumi.dev.yaml")
+	paths := []string{dir, config}
+	skipDirs := []string{"node_modules"}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.js"), []byte("a"), 0600))
+	d1, err := TreeDigest(paths, skipDirs)
+	assert.NoError(t, err)
+
+	// Changes within skipped directories are ignored.
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "dep"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "node_modules", "dep", "index.js"), []byte("b"), 0600))
+	d2, err := TreeDigest(paths, skipDirs)
+	assert.NoError(t, err)
+	assert.Equal(t, d1, d2)
+
+	// Editing a file changes the digest.
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.js"), []byte("ab"), 0600))
+	d3, err := TreeDigest(paths, skipDirs)
+	assert.NoError(t, err)
+	assert.NotEqual(t, d2, d3)
+
+	// So does creating a path that was missing.
+	assert.NoError(t, ioutil.WriteFile(config, []byte("config: {}"), 0600))
+	d4, err := TreeDigest(paths, skipDirs)
+	assert.NoError(t, err)
+	assert.NotEqual(t, d3, d4)
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-watch-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	changes := make(chan bool, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, []string{dir}, nil, 10*time.Millisecond, func() { changes <- true })
+	}()
+
+	// Let the watcher take its initial digest, then write a burst of files.
+	time.Sleep(20 * time.Millisecond)
+	for _, name := range []string{"a", "b", "c"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0600))
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "no change was reported")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Len(t, changes, 0)
+}
@@ -0,0 +1,354 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides counters and histograms that can be exposed to Prometheus. Long-running programs that embed
+// the engine can serve the metrics recorded by the engine, its providers, and its backends by mounting Handler.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets used by histograms that measure latencies.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// Registry is a set of metrics that are exposed together.
+type Registry struct {
+	lock    sync.Mutex
+	metrics map[string]metric
+}
+
+// NewRegistry creates a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// Default is the registry in which the engine, its providers, and its backends record their metrics.
+var Default = NewRegistry()
+
+type metric interface {
+	write(w io.Writer) error
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, has := r.metrics[name]
+	contract.Assertf(!has, "metric %s registered twice", name)
+	r.metrics[name] = m
+}
+
+// WriteTo writes the current value of each metric in the registry in the Prometheus text exposition format.
+func (r *Registry) WriteTo(w io.Writer) error {
+	r.lock.Lock()
+	names := make([]string, 0, len(r.metrics))
+	metrics := make(map[string]metric, len(r.metrics))
+	for name, m := range r.metrics {
+		names, metrics[name] = append(names, name), m
+	}
+	r.lock.Unlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		if err := metrics[name].write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns an HTTP handler that serves the metrics in the default registry to Prometheus.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// Handler returns an HTTP handler that serves the registry's metrics to Prometheus.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := r.WriteTo(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// family holds the series of a metric, one for each combination of label values.
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	lock   sync.Mutex
+	series map[string]interface{}
+	newFn  func() interface{}
+}
+
+func (f *family) with(values []string) interface{} {
+	contract.Assertf(len(values) == len(f.labels), "metric %s expects %d label values, got %d",
+		f.name, len(f.labels), len(values))
+
+	key := strings.Join(values, "\xff")
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = f.newFn()
+		f.series[key] = s
+	}
+	return s
+}
+
+// each calls fn with the label set and series of each of the family's series, in a stable order.
+func (f *family) each(fn func(labels string, series interface{}) error) error {
+	f.lock.Lock()
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	series := make(map[string]interface{}, len(f.series))
+	for key, s := range f.series {
+		series[key] = s
+	}
+	f.lock.Unlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		var values []string
+		if len(f.labels) > 0 {
+			values = strings.Split(key, "\xff")
+		}
+		if err := fn(formatLabels(f.labels, values), series[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *family) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escape(f.help, false), f.name, f.kind)
+	return err
+}
+
+// Counter is a metric whose value only increases, partitioned by a set of labels.
+type Counter struct {
+	family
+}
+
+// NewCounter registers a new counter with the given name, help text, and label names in the registry.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family{name: name, help: help, kind: "counter", labels: labels,
+		series: make(map[string]interface{}), newFn: func() interface{} { return &counterSeries{} }}}
+	r.register(name, c)
+	return c
+}
+
+type counterSeries struct {
+	lock  sync.Mutex
+	value float64
+}
+
+// Add adds the given non-negative delta to the counter's series for the given label values.
+func (c *Counter) Add(delta float64, values ...string) {
+	contract.Assertf(delta >= 0, "counters cannot decrease")
+	s := c.with(values).(*counterSeries)
+	s.lock.Lock()
+	s.value += delta
+	s.lock.Unlock()
+}
+
+// Inc increments the counter's series for the given label values.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *Counter) write(w io.Writer) error {
+	if err := c.writeHeader(w); err != nil {
+		return err
+	}
+	return c.each(func(labels string, series interface{}) error {
+		s := series.(*counterSeries)
+		s.lock.Lock()
+		value := s.value
+		s.lock.Unlock()
+		_, err := fmt.Fprintf(w, "%s%s %s\n", c.name, labels, formatFloat(value))
+		return err
+	})
+}
+
+// Gauge is a metric whose value may rise and fall, partitioned by a set of labels.
+type Gauge struct {
+	family
+}
+
+// NewGauge registers a new gauge with the given name, help text, and label names in the registry.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{family{name: name, help: help, kind: "gauge", labels: labels,
+		series: make(map[string]interface{}), newFn: func() interface{} { return &counterSeries{} }}}
+	r.register(name, g)
+	return g
+}
+
+// Add adds the given delta, which may be negative, to the gauge's series for the given label values.
+func (g *Gauge) Add(delta float64, values ...string) {
+	s := g.with(values).(*counterSeries)
+	s.lock.Lock()
+	s.value += delta
+	s.lock.Unlock()
+}
+
+// Set sets the gauge's series for the given label values to the given value.
+func (g *Gauge) Set(value float64, values ...string) {
+	s := g.with(values).(*counterSeries)
+	s.lock.Lock()
+	s.value = value
+	s.lock.Unlock()
+}
+
+func (g *Gauge) write(w io.Writer) error {
+	if err := g.writeHeader(w); err != nil {
+		return err
+	}
+	return g.each(func(labels string, series interface{}) error {
+		s := series.(*counterSeries)
+		s.lock.Lock()
+		value := s.value
+		s.lock.Unlock()
+		_, err := fmt.Fprintf(w, "%s%s %s\n", g.name, labels, formatFloat(value))
+		return err
+	})
+}
+
+// Histogram is a metric that counts observations in buckets, partitioned by a set of labels.
+type Histogram struct {
+	family
+
+	buckets []float64
+}
+
+// NewHistogram registers a new histogram with the given name, help text, bucket upper bounds, and label names in the
+// registry. If buckets is nil, DefaultBuckets is used.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	contract.Assertf(sort.Float64sAreSorted(buckets), "histogram buckets must be sorted")
+
+	h := &Histogram{buckets: buckets}
+	h.family = family{name: name, help: help, kind: "histogram", labels: labels,
+		series: make(map[string]interface{}),
+		newFn:  func() interface{} { return &histogramSeries{counts: make([]uint64, len(buckets))} }}
+	r.register(name, h)
+	return h
+}
+
+type histogramSeries struct {
+	lock   sync.Mutex
+	counts []uint64 // the number of observations in each bucket, not including those in smaller buckets.
+	count  uint64
+	sum    float64
+}
+
+// Observe records an observation in the histogram's series for the given label values.
+func (h *Histogram) Observe(value float64, values ...string) {
+	s := h.with(values).(*histogramSeries)
+	i := sort.SearchFloat64s(h.buckets, value)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += value
+}
+
+// ObserveSince records the time elapsed since start, in seconds, in the histogram's series for the given label values.
+func (h *Histogram) ObserveSince(start time.Time, values ...string) {
+	h.Observe(time.Since(start).Seconds(), values...)
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	if err := h.writeHeader(w); err != nil {
+		return err
+	}
+	return h.each(func(labels string, series interface{}) error {
+		s := series.(*histogramSeries)
+		s.lock.Lock()
+		counts, count, sum := append([]uint64(nil), s.counts...), s.count, s.sum
+		s.lock.Unlock()
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += counts[i]
+			le := withLabel(labels, "le", formatFloat(bound))
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, le, cumulative); err != nil {
+				return err
+			}
+		}
+		le := withLabel(labels, "le", "+Inf")
+		_, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.name, le, count, h.name, labels, formatFloat(sum), h.name, labels, count)
+		return err
+	})
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", name, escape(values[i], true))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// withLabel adds a label to a formatted label set.
+func withLabel(labels, name, value string) string {
+	pair := fmt.Sprintf("%s=\"%s\"", name, value)
+	if labels == "" {
+		return "{" + pair + "}"
+	}
+	return labels[:len(labels)-1] + "," + pair + "}"
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// escape escapes backslashes and newlines, and if quoted is true, double quotes, as the exposition format requires.
+func escape(s string, quoted bool) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	if quoted {
+		s = strings.Replace(s, `"`, `\"`, -1)
+	}
+	return s
+}
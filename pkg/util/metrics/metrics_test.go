@@ -0,0 +1,92 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_steps_total", "The number of steps.", "op")
+	c.Inc("create")
+	c.Inc("create")
+	c.Add(3, "update \"quoted\"")
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteTo(&buf))
+	assert.Equal(t, "# HELP test_steps_total The number of steps.\n"+
+		"# TYPE test_steps_total counter\n"+
+		"test_steps_total{op=\"create\"} 2\n"+
+		"test_steps_total{op=\"update \\\"quoted\\\"\"} 3\n", buf.String())
+}
+
+func TestGauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGauge("test_queued", "The number of queued calls.", "limiter")
+	g.Add(3, "aws")
+	g.Add(-1, "aws")
+	g.Set(5, "gcp")
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteTo(&buf))
+	assert.Equal(t, "# HELP test_queued The number of queued calls.\n"+
+		"# TYPE test_queued gauge\n"+
+		"test_queued{limiter=\"aws\"} 2\n"+
+		"test_queued{limiter=\"gcp\"} 5\n", buf.String())
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(1)
+	h.Observe(2)
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteTo(&buf))
+	assert.Equal(t, "# HELP test_latency_seconds Latency.\n"+
+		"# TYPE test_latency_seconds histogram\n"+
+		"test_latency_seconds_bucket{le=\"0.1\"} 1\n"+
+		"test_latency_seconds_bucket{le=\"1\"} 3\n"+
+		"test_latency_seconds_bucket{le=\"+Inf\"} 4\n"+
+		"test_latency_seconds_sum 3.55\n"+
+		"test_latency_seconds_count 4\n", buf.String())
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("b_total", "B.").Inc()
+	r.NewHistogram("a_seconds", "A.", []float64{1}, "pkg").Observe(0.5, "aws")
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "# HELP a_seconds A.\n"+
+		"# TYPE a_seconds histogram\n"+
+		"a_seconds_bucket{pkg=\"aws\",le=\"1\"} 1\n"+
+		"a_seconds_bucket{pkg=\"aws\",le=\"+Inf\"} 1\n"+
+		"a_seconds_sum{pkg=\"aws\"} 0.5\n"+
+		"a_seconds_count{pkg=\"aws\"} 1\n"+
+		"# HELP b_total B.\n"+
+		"# TYPE b_total counter\n"+
+		"b_total 1\n", rec.Body.String())
+}
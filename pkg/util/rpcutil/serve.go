@@ -37,6 +37,17 @@ func IsBenignCloseErr(err error) bool {
 // eventually return an error, and an error, in case something went wrong.  The channel is non-nil and waits until
 // the server is finished, in the case of a successful launch of the RPC server.
 func Serve(port int, cancel chan bool, registers []func(*grpc.Server) error) (int, chan error, error) {
+	opts, err := TransportOptions{}.ServerOptions()
+	if err != nil {
+		return port, nil, err
+	}
+	return ServeWithOptions(port, cancel, registers, opts...)
+}
+
+// ServeWithOptions is like Serve, but creates the gRPC server with the given options in addition to the defaults.
+func ServeWithOptions(port int, cancel chan bool, registers []func(*grpc.Server) error,
+	options ...grpc.ServerOption) (int, chan error, error) {
+
 	// Listen on a TCP port, but let the kernel choose a free port for us.
 	lis, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
 	if err != nil {
@@ -44,7 +55,8 @@ func Serve(port int, cancel chan bool, registers []func(*grpc.Server) error) (in
 	}
 
 	// Now new up a gRPC server and register any RPC interfaces the caller wants.
-	srv := grpc.NewServer(grpc.UnaryInterceptor(OpenTracingServerInterceptor()))
+	srv := grpc.NewServer(append([]grpc.ServerOption{grpc.UnaryInterceptor(OpenTracingServerInterceptor())},
+		options...)...)
 	for _, register := range registers {
 		if err := register(srv); err != nil {
 			return port, nil, errors.Errorf("failed to register RPC handler: %v", err)
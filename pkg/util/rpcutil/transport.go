@@ -0,0 +1,229 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip" // registers the gzip compressor with both clients and servers.
+	"google.golang.org/grpc/keepalive"
+)
+
+// DefaultMaxMessageSize is the default maximum size of a single RPC message.  gRPC's own default of 4MB is easily
+// exceeded by resources with large properties (inline code, policy documents, and so on).
+const DefaultMaxMessageSize = 400 * 1024 * 1024
+
+const (
+	// MaxMessageSizeEnvVar overrides the maximum size, in bytes, of a single RPC message.
+	MaxMessageSizeEnvVar = "PULUMI_RPC_MAX_MESSAGE_SIZE"
+	// KeepaliveTimeEnvVar enables keepalive pings on idle connections at the given interval (e.g. "30s").
+	KeepaliveTimeEnvVar = "PULUMI_RPC_KEEPALIVE_TIME"
+	// KeepaliveTimeoutEnvVar overrides the time to wait for a keepalive ping to be acknowledged (e.g. "20s").
+	KeepaliveTimeoutEnvVar = "PULUMI_RPC_KEEPALIVE_TIMEOUT"
+	// CompressionEnvVar names the compressor used for outgoing requests (e.g. "gzip"); "none" disables compression.
+	CompressionEnvVar = "PULUMI_RPC_COMPRESSION"
+	// TLSCertEnvVar names a PEM certificate file used to authenticate this end of a provider connection.
+	TLSCertEnvVar = "PULUMI_PROVIDER_TLS_CERT"
+	// TLSKeyEnvVar names the PEM private key file for the certificate named by TLSCertEnvVar.
+	TLSKeyEnvVar = "PULUMI_PROVIDER_TLS_KEY"
+	// TLSCAEnvVar names a PEM file of CA certificates used to verify the other end of a provider connection.
+	TLSCAEnvVar = "PULUMI_PROVIDER_TLS_CA"
+	// TLSServerNameEnvVar overrides the server name used to verify a provider's certificate.
+	TLSServerNameEnvVar = "PULUMI_PROVIDER_TLS_SERVER_NAME"
+)
+
+// TransportOptions configures the gRPC transport between the engine and its plugins.  The zero value uses a large
+// message size limit, no keepalives, and no transport security, which is appropriate for local plugins.
+type TransportOptions struct {
+	MaxMessageSize   int           // the maximum message size in bytes (<=0 for DefaultMaxMessageSize).
+	KeepaliveTime    time.Duration // the interval between keepalive pings on idle connections (0 to disable).
+	KeepaliveTimeout time.Duration // the time to wait for a keepalive ping to be acknowledged (0 for gRPC's default).
+	Compression      string        // the compressor used for outgoing requests ("" for none).
+	TLS              *TLSOptions   // optional mutual TLS configuration.
+}
+
+// SupportedCompressors lists the compressors that may be named in TransportOptions.  Servers always accept requests
+// compressed with any of these, and reply using the same compressor, so only the client needs to opt in.
+var SupportedCompressors = []string{gzip.Name}
+
+// TLSOptions configures mutual TLS for a connection.  Each end presents the certificate in CertFile and verifies the
+// other end's certificate using the CAs in CAFile, which is required.
+type TLSOptions struct {
+	CertFile   string // a PEM certificate file.
+	KeyFile    string // the PEM private key file for CertFile.
+	CAFile     string // a PEM file of CA certificates used to verify the other end of the connection.
+	ServerName string // an optional server name used by clients to verify the server's certificate.
+}
+
+// TransportOptionsFromEnv reads transport options from the environment.  Because plugins inherit the engine's
+// environment, both ends of a connection configured this way agree on their settings.
+func TransportOptionsFromEnv() (TransportOptions, error) {
+	var opts TransportOptions
+	if v := os.Getenv(MaxMessageSizeEnvVar); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "invalid %s", MaxMessageSizeEnvVar)
+		}
+		opts.MaxMessageSize = size
+	}
+	if v := os.Getenv(KeepaliveTimeEnvVar); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "invalid %s", KeepaliveTimeEnvVar)
+		}
+		opts.KeepaliveTime = d
+	}
+	if v := os.Getenv(KeepaliveTimeoutEnvVar); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "invalid %s", KeepaliveTimeoutEnvVar)
+		}
+		opts.KeepaliveTimeout = d
+	}
+	if v := os.Getenv(CompressionEnvVar); v != "" && v != "none" {
+		if !isSupportedCompressor(v) {
+			return opts, errors.Errorf("invalid %s: unsupported compressor %q", CompressionEnvVar, v)
+		}
+		opts.Compression = v
+	}
+	if cert := os.Getenv(TLSCertEnvVar); cert != "" {
+		opts.TLS = &TLSOptions{
+			CertFile:   cert,
+			KeyFile:    os.Getenv(TLSKeyEnvVar),
+			CAFile:     os.Getenv(TLSCAEnvVar),
+			ServerName: os.Getenv(TLSServerNameEnvVar),
+		}
+		if opts.TLS.CAFile == "" {
+			return opts, errors.Errorf("%s must be set along with %s", TLSCAEnvVar, TLSCertEnvVar)
+		}
+	}
+	return opts, nil
+}
+
+func isSupportedCompressor(name string) bool {
+	for _, c := range SupportedCompressors {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (opts TransportOptions) maxMessageSize() int {
+	if opts.MaxMessageSize <= 0 {
+		return DefaultMaxMessageSize
+	}
+	return opts.MaxMessageSize
+}
+
+// DialOptions returns the gRPC dial options for a client using these transport options.
+func (opts TransportOptions) DialOptions() ([]grpc.DialOption, error) {
+	size := opts.maxMessageSize()
+	callOpts := []grpc.CallOption{grpc.MaxCallRecvMsgSize(size), grpc.MaxCallSendMsgSize(size)}
+	if opts.Compression != "" {
+		if !isSupportedCompressor(opts.Compression) {
+			return nil, errors.Errorf("unsupported RPC compressor %q", opts.Compression)
+		}
+		callOpts = append(callOpts, grpc.UseCompressor(opts.Compression))
+	}
+	dialOpts := []grpc.DialOption{grpc.WithDefaultCallOptions(callOpts...)}
+
+	if opts.KeepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                opts.KeepaliveTime,
+			Timeout:             opts.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	if opts.TLS == nil {
+		return append(dialOpts, grpc.WithInsecure()), nil
+	}
+	config, err := opts.TLS.config()
+	if err != nil {
+		return nil, err
+	}
+	config.RootCAs = config.ClientCAs
+	config.ClientCAs = nil
+	config.ServerName = opts.TLS.ServerName
+	return append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(config))), nil
+}
+
+// ServerOptions returns the gRPC server options for a server using these transport options.
+func (opts TransportOptions) ServerOptions() ([]grpc.ServerOption, error) {
+	size := opts.maxMessageSize()
+	serverOpts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(size),
+		grpc.MaxSendMsgSize(size),
+	}
+
+	// Unless the server permits them, clients that ping more often than every five minutes are disconnected.
+	if opts.KeepaliveTime > 0 {
+		serverOpts = append(serverOpts,
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+				MinTime:             opts.KeepaliveTime,
+				PermitWithoutStream: true,
+			}),
+			grpc.KeepaliveParams(keepalive.ServerParameters{
+				Time:    opts.KeepaliveTime,
+				Timeout: opts.KeepaliveTimeout,
+			}))
+	}
+
+	if opts.TLS != nil {
+		config, err := opts.TLS.config()
+		if err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(config)))
+	}
+	return serverOpts, nil
+}
+
+// config loads the certificate and CAs named by these options.  The CAs are returned in ClientCAs; clients must move
+// them to RootCAs.  Without CAs, neither end could verify the other, so they are required.
+func (opts *TLSOptions) config() (*tls.Config, error) {
+	if opts.CAFile == "" {
+		return nil, errors.New("mutual TLS requires a CA file with which to verify the other end of the connection")
+	}
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading TLS certificate %s", opts.CertFile)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	pem, err := ioutil.ReadFile(opts.CAFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading TLS CA file %s", opts.CAFile)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Errorf("no certificates found in TLS CA file %s", opts.CAFile)
+	}
+	config.ClientCAs = pool
+	return config, nil
+}
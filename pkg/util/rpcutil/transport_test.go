@@ -0,0 +1,129 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutil
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setEnv(t *testing.T, vars map[string]string) func() {
+	for k, v := range vars {
+		assert.NoError(t, os.Setenv(k, v))
+	}
+	return func() {
+		for k := range vars {
+			assert.NoError(t, os.Unsetenv(k))
+		}
+	}
+}
+
+func TestTransportOptionsFromEnv(t *testing.T) {
+	reset := setEnv(t, map[string]string{
+		MaxMessageSizeEnvVar:   "1024",
+		KeepaliveTimeEnvVar:    "30s",
+		KeepaliveTimeoutEnvVar: "10s",
+		TLSCertEnvVar:          "cert.pem",
+		TLSKeyEnvVar:           "key.pem",
+		TLSCAEnvVar:            "ca.pem",
+		CompressionEnvVar:      "gzip",
+	})
+	opts, err := TransportOptionsFromEnv()
+	reset()
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, opts.MaxMessageSize)
+	assert.Equal(t, 30*time.Second, opts.KeepaliveTime)
+	assert.Equal(t, 10*time.Second, opts.KeepaliveTimeout)
+	assert.Equal(t, "gzip", opts.Compression)
+	assert.Equal(t, &TLSOptions{CertFile: "cert.pem", KeyFile: "key.pem", CAFile: "ca.pem"}, opts.TLS)
+
+	// With nothing set, the zero value is returned.
+	opts, err = TransportOptionsFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, TransportOptions{}, opts)
+
+	// Malformed values are rejected.
+	reset = setEnv(t, map[string]string{KeepaliveTimeEnvVar: "soon"})
+	_, err = TransportOptionsFromEnv()
+	reset()
+	assert.Error(t, err)
+
+	// Compression may be explicitly disabled, but unknown compressors are rejected.
+	reset = setEnv(t, map[string]string{CompressionEnvVar: "none"})
+	opts, err = TransportOptionsFromEnv()
+	reset()
+	assert.NoError(t, err)
+	assert.Equal(t, "", opts.Compression)
+	reset = setEnv(t, map[string]string{CompressionEnvVar: "lzma"})
+	_, err = TransportOptionsFromEnv()
+	reset()
+	assert.Error(t, err)
+
+	// Mutual TLS requires a CA.
+	reset = setEnv(t, map[string]string{TLSCertEnvVar: "cert.pem", TLSKeyEnvVar: "key.pem"})
+	_, err = TransportOptionsFromEnv()
+	reset()
+	assert.Error(t, err)
+}
+
+func TestTransportOptions(t *testing.T) {
+	assert.Equal(t, DefaultMaxMessageSize, TransportOptions{}.maxMessageSize())
+	assert.Equal(t, 1024, TransportOptions{MaxMessageSize: 1024}.maxMessageSize())
+
+	// Message size limits plus insecure transport.
+	dialOpts, err := TransportOptions{}.DialOptions()
+	assert.NoError(t, err)
+	assert.Len(t, dialOpts, 2)
+	serverOpts, err := TransportOptions{}.ServerOptions()
+	assert.NoError(t, err)
+	assert.Len(t, serverOpts, 2)
+
+	// Keepalives add client parameters, and a server enforcement policy that permits them.
+	keepalive := TransportOptions{KeepaliveTime: time.Minute}
+	dialOpts, err = keepalive.DialOptions()
+	assert.NoError(t, err)
+	assert.Len(t, dialOpts, 3)
+	serverOpts, err = keepalive.ServerOptions()
+	assert.NoError(t, err)
+	assert.Len(t, serverOpts, 4)
+
+	// Compression only changes the client's default call options.
+	compressed := TransportOptions{Compression: "gzip"}
+	dialOpts, err = compressed.DialOptions()
+	assert.NoError(t, err)
+	assert.Len(t, dialOpts, 2)
+	serverOpts, err = compressed.ServerOptions()
+	assert.NoError(t, err)
+	assert.Len(t, serverOpts, 2)
+	_, err = TransportOptions{Compression: "lzma"}.DialOptions()
+	assert.Error(t, err)
+
+	// Missing certificates and CAs are reported on both ends.
+	tls := TransportOptions{TLS: &TLSOptions{CertFile: "missing-cert.pem", KeyFile: "missing-key.pem", CAFile: "ca.pem"}}
+	_, err = tls.DialOptions()
+	assert.Error(t, err)
+	_, err = tls.ServerOptions()
+	assert.Error(t, err)
+	tls.TLS.CAFile = ""
+	_, err = tls.DialOptions()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "CA file")
+	}
+	_, err = tls.ServerOptions()
+	assert.Error(t, err)
+}
@@ -29,8 +29,12 @@ import (
 )
 
 const (
+	// AuditDir is the name of the folder where the audit logs of stack updates are stored.
+	AuditDir = "audit"
 	// BackupDir is the name of the folder where backup stack information is stored.
 	BackupDir = "backups"
+	// BlobDir is the name of the folder where large property values offloaded from checkpoints are stored.
+	BlobDir = "blobs"
 	// BookkeepingDir is the name of our bookeeping folder, we store state here (like .git for git).
 	BookkeepingDir = ".pulumi"
 	// ConfigDir is the name of the folder that holds local configuration information.
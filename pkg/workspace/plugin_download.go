@@ -0,0 +1,191 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/httputil"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+const (
+	// DefaultPluginDownloadURL is the release URL from which missing plugins are downloaded by default.
+	DefaultPluginDownloadURL = "https://get.pulumi.com/releases/plugins"
+
+	// PluginDownloadURLEnvVar may be set to override the release URL from which missing plugins are downloaded.
+	PluginDownloadURLEnvVar = "PULUMI_PLUGIN_DOWNLOAD_URL"
+
+	// DisableAutomaticPluginAcquisitionEnvVar may be set to prevent the engine from downloading missing plugins.
+	DisableAutomaticPluginAcquisitionEnvVar = "PULUMI_DISABLE_AUTOMATIC_PLUGIN_ACQUISITION"
+
+	// SkipPluginChecksumEnvVar may be set to accept downloaded plugins whose releases do not publish a checksum.
+	SkipPluginChecksumEnvVar = "PULUMI_SKIP_PLUGIN_CHECKSUM"
+)
+
+// GetPluginDownloadURL returns the release URL from which missing plugins should be downloaded.
+func GetPluginDownloadURL() string {
+	if url := os.Getenv(PluginDownloadURLEnvVar); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return DefaultPluginDownloadURL
+}
+
+// Tarball returns the name of the release tarball that contains this plugin for the current OS and architecture.
+func (info PluginInfo) Tarball() (string, error) {
+	contract.Assert(info.Version != nil)
+
+	switch runtime.GOOS {
+	case "darwin", "linux", "windows":
+	default:
+		return "", errors.Errorf("unsupported plugin OS: %s", runtime.GOOS)
+	}
+	switch runtime.GOARCH {
+	case "amd64":
+	default:
+		return "", errors.Errorf("unsupported plugin architecture: %s", runtime.GOARCH)
+	}
+
+	return fmt.Sprintf("pulumi-%s-%s-v%s-%s-%s.tar.gz",
+		info.Kind, info.Name, info.Version, runtime.GOOS, runtime.GOARCH), nil
+}
+
+// DownloadPlugin downloads the given plugin's tarball from the release URL at baseURL. The download is verified against
+// the SHA-256 checksum that the release publishes alongside the tarball (at the tarball's URL plus ".sha256") before it
+// is returned. A release that publishes no checksum is rejected unless SkipPluginChecksumEnvVar is set. The returned
+// reader should be passed to PluginInfo.Install, which closes it.
+func DownloadPlugin(info PluginInfo, baseURL string) (io.ReadCloser, error) {
+	if info.Version == nil {
+		return nil, errors.Errorf("cannot download plugin %s without a version", info.Name)
+	}
+	tarball, err := info.Tarball()
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/" + tarball
+
+	// Download the tarball into a temporary file, hashing it as we go.
+	logging.V(5).Infof("DownloadPlugin(%s): downloading from %s", info, url)
+	resp, err := httputil.GetWithRetry(url, http.DefaultClient)
+	if err != nil {
+		return nil, errors.Wrapf(err, "downloading %s", url)
+	}
+	defer contract.IgnoreClose(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	tmp, err := ioutil.TempFile("", tarball)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary file")
+	}
+	file := &tempFile{File: tmp}
+
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		contract.IgnoreClose(file)
+		return nil, errors.Wrapf(err, "downloading %s", url)
+	}
+
+	// Verify the tarball against its published checksum, if any.
+	expected, err := downloadChecksum(url + ".sha256")
+	if err != nil {
+		contract.IgnoreClose(file)
+		return nil, err
+	}
+	if expected == "" {
+		if os.Getenv(SkipPluginChecksumEnvVar) == "" {
+			contract.IgnoreClose(file)
+			return nil, errors.Errorf("no checksum is published for %s; set %s to install it without verification",
+				url, SkipPluginChecksumEnvVar)
+		}
+		logging.V(3).Infof("DownloadPlugin(%s): no checksum published for %s; skipping verification", info, url)
+	} else if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		contract.IgnoreClose(file)
+		return nil, errors.Errorf("checksum mismatch for %s: expected %s, got %s", url, expected, actual)
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		contract.IgnoreClose(file)
+		return nil, err
+	}
+	return file, nil
+}
+
+// downloadChecksum fetches the SHA-256 checksum at the given URL.  The checksum file may be in the format produced by
+// `sha256sum`, i.e. the hex digest followed by the file name.  If no checksum is published, an empty string is
+// returned.
+func downloadChecksum(url string) (string, error) {
+	resp, err := httputil.GetWithRetry(url, http.DefaultClient)
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading %s", url)
+	}
+	defer contract.IgnoreClose(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Continue on.
+	case http.StatusNotFound, http.StatusForbidden:
+		return "", nil
+	default:
+		return "", errors.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading %s", url)
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", errors.Errorf("checksum file %s is empty", url)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// tempFile is an *os.File that is removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	contract.IgnoreError(os.Remove(f.File.Name()))
+	return err
+}
+
+// InstallPlugin downloads the given plugin from the release URL at baseURL and installs it into the plugin cache,
+// unless a compatible version of the plugin is already installed.
+func InstallPlugin(info PluginInfo, baseURL string) error {
+	if has, err := HasPluginGTE(info); err == nil && has {
+		return nil
+	}
+
+	tarball, err := DownloadPlugin(info, baseURL)
+	if err != nil {
+		return err
+	}
+	return info.Install(tarball)
+}
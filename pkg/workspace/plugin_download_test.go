@@ -0,0 +1,95 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadPlugin(t *testing.T) {
+	version := semver.MustParse("1.2.3")
+	info := PluginInfo{Kind: ResourcePlugin, Name: "test", Version: &version}
+	tarball, err := info.Tarball()
+	if err != nil {
+		t.Skipf("plugins are not available for this platform: %v", err)
+	}
+
+	contents := []byte("not really a tarball")
+	digest := sha256.Sum256(contents)
+	checksum := hex.EncodeToString(digest[:])
+
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	download := func() ([]byte, error) {
+		rc, err := DownloadPlugin(info, server.URL+"/releases/")
+		if err != nil {
+			return nil, err
+		}
+		defer func() { assert.NoError(t, rc.Close()) }()
+		return ioutil.ReadAll(rc)
+	}
+
+	// A missing tarball is an error.
+	_, err = download()
+	assert.Error(t, err)
+
+	// A tarball without a published checksum is rejected, unless the user opts out of verification.
+	files["/releases/"+tarball] = string(contents)
+	_, err = download()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), SkipPluginChecksumEnvVar)
+	}
+	assert.NoError(t, os.Setenv(SkipPluginChecksumEnvVar, "true"))
+	actual, err := download()
+	assert.NoError(t, os.Unsetenv(SkipPluginChecksumEnvVar))
+	assert.NoError(t, err)
+	assert.Equal(t, contents, actual)
+
+	// A tarball with a matching checksum is accepted; the checksum may be in `sha256sum` format.
+	files["/releases/"+tarball+".sha256"] = checksum + "  " + tarball + "\n"
+	actual, err = download()
+	assert.NoError(t, err)
+	assert.Equal(t, contents, actual)
+
+	// A tarball with a mismatched checksum is rejected.
+	files["/releases/"+tarball] = "tampered"
+	_, err = download()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "checksum mismatch")
+	}
+
+	// A plugin without a version cannot be downloaded.
+	_, err = DownloadPlugin(PluginInfo{Kind: ResourcePlugin, Name: "test"}, server.URL)
+	assert.Error(t, err)
+}
@@ -27,6 +27,7 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
@@ -167,45 +168,7 @@ func (info PluginInfo) Install(tarball io.ReadCloser) error {
 	// before we later try to rename the directory. Otherwise, the open file handles cause issues on Windows.
 	err = (func() error {
 		defer contract.IgnoreClose(tarball)
-		gzr, err := gzip.NewReader(tarball)
-		if err != nil {
-			return errors.Wrapf(err, "unzipping")
-		}
-		r := tar.NewReader(gzr)
-		for {
-			header, err := r.Next()
-			if err == io.EOF {
-				break
-			} else if err != nil {
-				return errors.Wrapf(err, "untarring")
-			}
-
-			path := filepath.Join(tempDir, header.Name)
-
-			switch header.Typeflag {
-			case tar.TypeDir:
-				// Create any directories as needed.
-				if _, err := os.Stat(path); err != nil {
-					if err = os.MkdirAll(path, 0700); err != nil {
-						return errors.Wrapf(err, "untarring dir %s", path)
-					}
-				}
-			case tar.TypeReg:
-				// Expand files into the target directory.
-				dst, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-				if err != nil {
-					return errors.Wrapf(err, "opening file %s for untar", path)
-				}
-				defer contract.IgnoreClose(dst)
-				if _, err = io.Copy(dst, r); err != nil {
-					return errors.Wrapf(err, "untarring file %s", path)
-				}
-			default:
-				return errors.Errorf("unexpected plugin file type %s (%v)", header.Name, header.Typeflag)
-			}
-		}
-
-		return nil
+		return extractTarball(tarball, tempDir)
 	})()
 	if err != nil {
 		return err
@@ -221,6 +184,55 @@ func (info PluginInfo) Install(tarball io.ReadCloser) error {
 	return nil
 }
 
+// extractTarball unzips and untars the given plugin tarball into dir. Entries that would be written outside of dir are
+// rejected.
+func extractTarball(tarball io.Reader, dir string) error {
+	gzr, err := gzip.NewReader(tarball)
+	if err != nil {
+		return errors.Wrapf(err, "unzipping")
+	}
+	r := tar.NewReader(gzr)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "untarring")
+		}
+
+		// Refuse entries that would be written outside of the plugin's directory.
+		path := filepath.Join(dir, header.Name)
+		if rel, relErr := filepath.Rel(dir, path); filepath.IsAbs(header.Name) || relErr != nil ||
+			rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return errors.Errorf("plugin file %s is outside of the plugin's directory", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			// Create any directories as needed.
+			if _, err := os.Stat(path); err != nil {
+				if err = os.MkdirAll(path, 0700); err != nil {
+					return errors.Wrapf(err, "untarring dir %s", path)
+				}
+			}
+		case tar.TypeReg:
+			// Expand files into the target directory.
+			dst, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			if err != nil {
+				return errors.Wrapf(err, "opening file %s for untar", path)
+			}
+			defer contract.IgnoreClose(dst)
+			if _, err = io.Copy(dst, r); err != nil {
+				return errors.Wrapf(err, "untarring file %s", path)
+			}
+		default:
+			return errors.Errorf("unexpected plugin file type %s (%v)", header.Name, header.Typeflag)
+		}
+	}
+
+	return nil
+}
+
 func (info PluginInfo) String() string {
 	var version string
 	if v := info.Version; v != nil {
@@ -15,6 +15,12 @@
 package workspace
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/blang/semver"
@@ -234,3 +240,37 @@ func TestPluginSelection_EmptyVersionWithAlternatives(t *testing.T) {
 	assert.Equal(t, "myplugin", result.Name)
 	assert.Equal(t, "0.2.0", result.Version.String())
 }
+
+func TestExtractTarball(t *testing.T) {
+	tarball := func(names ...string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		w := tar.NewWriter(gzw)
+		for _, name := range names {
+			assert.NoError(t, w.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: 2, Typeflag: tar.TypeReg}))
+			_, err := w.Write([]byte("hi"))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		assert.NoError(t, gzw.Close())
+		return &buf
+	}
+
+	root, err := ioutil.TempDir("", "extract")
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(root)) }()
+	dir := filepath.Join(root, "plugin")
+	assert.NoError(t, os.Mkdir(dir, 0700))
+
+	assert.NoError(t, extractTarball(tarball("pulumi-resource-test", "./README.md"), dir))
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "pulumi-resource-test"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", string(contents))
+
+	// Entries may not escape the plugin's directory.
+	for _, name := range []string{"../escaped", "bin/../../escaped", "/escaped"} {
+		assert.Error(t, extractTarball(tarball(name), dir), name)
+	}
+	_, err = os.Stat(filepath.Join(root, "escaped"))
+	assert.True(t, os.IsNotExist(err))
+}
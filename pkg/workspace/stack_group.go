@@ -0,0 +1,205 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/fsutil"
+)
+
+// StackGroupFile is the base name of a stack group manifest.
+const StackGroupFile = "Pulumi.stacks"
+
+// StackGroup is a manifest that declares a set of stacks, possibly from different projects, that are operated on
+// together.  A stack that consumes the outputs of another stack (e.g. through a StackReference) declares that stack
+// as a dependency, so that the stacks are always operated on in dependency order.
+type StackGroup struct {
+	// Stacks maps from the name of each member of the group to its definition.
+	Stacks map[string]StackGroupMember `json:"stacks" yaml:"stacks"`
+}
+
+// StackGroupMember is a single stack within a stack group.
+type StackGroupMember struct {
+	// Project is the directory holding the stack's project, relative to the manifest.
+	Project string `json:"project" yaml:"project"`
+	// Stack is the name of the stack.  If it is empty, the member's name is used.
+	Stack string `json:"stack,omitempty" yaml:"stack,omitempty"`
+	// DependsOn lists the members of the group whose outputs this stack consumes.
+	DependsOn []string `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+}
+
+// StackName returns the name of the stack for the member with the given name.
+func (m StackGroupMember) StackName(name string) string {
+	if m.Stack != "" {
+		return m.Stack
+	}
+	return name
+}
+
+// Validate returns an error if any member of the group lacks a project or depends on a stack outside of the group.
+func (g *StackGroup) Validate() error {
+	if len(g.Stacks) == 0 {
+		return errors.New("stack group does not declare any stacks")
+	}
+	for _, name := range g.names() {
+		m := g.Stacks[name]
+		if m.Project == "" {
+			return errors.Errorf("stack '%s' is missing a 'project' attribute", name)
+		}
+		for _, dep := range m.DependsOn {
+			if _, has := g.Stacks[dep]; !has {
+				return errors.Errorf("stack '%s' depends on unknown stack '%s'", name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// Order returns the names of the group's members in an order in which each member follows all of the members it
+// depends on.  Members that do not depend on one another are ordered by name.  An error is returned if the
+// dependencies form a cycle.
+func (g *StackGroup) Order() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var order []string
+	marks := make(map[string]int)
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("stack dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		marks[name] = visiting
+		deps := append([]string(nil), g.Stacks[name].DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range g.names() {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Dependents returns the names of the members that depend on the given member, directly or transitively, sorted by
+// name.
+func (g *StackGroup) Dependents(name string) []string {
+	dependents := make(map[string]bool)
+	var walk func(name string)
+	walk = func(name string) {
+		for _, other := range g.names() {
+			if dependents[other] {
+				continue
+			}
+			for _, dep := range g.Stacks[other].DependsOn {
+				if dep == name {
+					dependents[other] = true
+					walk(other)
+					break
+				}
+			}
+		}
+	}
+	walk(name)
+
+	var result []string
+	for other := range dependents {
+		result = append(result, other)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// names returns the names of the group's members, sorted.
+func (g *StackGroup) names() []string {
+	var names []string
+	for name := range g.Stacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadStackGroup reads a stack group manifest from a file.
+func LoadStackGroup(path string) (*StackGroup, error) {
+	contract.Require(path != "", "path")
+
+	m, err := marshallerForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var group StackGroup
+	if err = m.Unmarshal(b, &group); err != nil {
+		return nil, err
+	}
+
+	if err = group.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// DetectStackGroupPath locates the closest stack group manifest from the current working directory, searching
+// "upwards" in the directory hierarchy.  If no manifest is found, an empty path is returned.
+func DetectStackGroupPath() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return fsutil.WalkUp(dir, func(path string) bool {
+		return isMarkupFile(path, StackGroupFile)
+	}, nil)
+}
+
+// StackGroupProjectDir returns the directory holding the project of the given member of the stack group whose
+// manifest is at the given path.
+func StackGroupProjectDir(manifestPath string, m StackGroupMember) string {
+	if filepath.IsAbs(m.Project) {
+		return m.Project
+	}
+	return filepath.Join(filepath.Dir(manifestPath), m.Project)
+}
@@ -0,0 +1,89 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackGroupOrder(t *testing.T) {
+	group := &StackGroup{Stacks: map[string]StackGroupMember{
+		"app":      {Project: "app", DependsOn: []string{"network", "database"}},
+		"database": {Project: "database", DependsOn: []string{"network"}},
+		"network":  {Project: "network"},
+		"dns":      {Project: "dns"},
+	}}
+	assert.NoError(t, group.Validate())
+
+	order, err := group.Order()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"network", "database", "app", "dns"}, order)
+
+	assert.Equal(t, []string{"app", "database"}, group.Dependents("network"))
+	assert.Equal(t, []string{"app"}, group.Dependents("database"))
+	assert.Empty(t, group.Dependents("dns"))
+}
+
+func TestStackGroupCycle(t *testing.T) {
+	group := &StackGroup{Stacks: map[string]StackGroupMember{
+		"a": {Project: "a", DependsOn: []string{"b"}},
+		"b": {Project: "b", DependsOn: []string{"c"}},
+		"c": {Project: "c", DependsOn: []string{"a"}},
+	}}
+	_, err := group.Order()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "a -> b -> c -> a")
+	}
+}
+
+func TestStackGroupValidate(t *testing.T) {
+	assert.Error(t, (&StackGroup{}).Validate())
+	assert.Error(t, (&StackGroup{Stacks: map[string]StackGroupMember{"a": {}}}).Validate())
+	assert.Error(t, (&StackGroup{Stacks: map[string]StackGroupMember{
+		"a": {Project: "a", DependsOn: []string{"missing"}},
+	}}).Validate())
+}
+
+func TestLoadStackGroup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stackgroup")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, StackGroupFile+".yaml")
+	err = ioutil.WriteFile(path, []byte(`stacks:
+  network:
+    project: ./network
+    stack: dev
+  app:
+    project: ./app
+    dependsOn: [network]
+`), 0600)
+	assert.NoError(t, err)
+
+	group, err := LoadStackGroup(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", group.Stacks["network"].StackName("network"))
+	assert.Equal(t, "app", group.Stacks["app"].StackName("app"))
+	assert.Equal(t, filepath.Join(dir, "app"), StackGroupProjectDir(path, group.Stacks["app"]))
+
+	order, err := group.Order()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"network", "app"}, order)
+}
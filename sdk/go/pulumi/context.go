@@ -15,7 +15,9 @@
 package pulumi
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/golang/glog"
@@ -41,6 +43,18 @@ type Context struct {
 	rpcs        int         // the number of outstanding RPC requests.
 	rpcsDone    *sync.Cond  // an event signaling completion of RPCs.
 	rpcsLock    *sync.Mutex // a lock protecting the RPC count and event.
+
+	providers     map[*URNOutput]map[string]ProviderResource // the providers each resource's children inherit.
+	providersLock sync.Mutex                                 // a lock protecting the providers map.
+
+	urns     map[typedID]URN // the URNs of the resources registered so far, by type and ID.
+	urnsLock sync.Mutex      // a lock protecting the URNs map.
+}
+
+// typedID identifies a resource by its type and provider-assigned ID.
+type typedID struct {
+	t  string
+	id ID
 }
 
 // NewContext creates a fresh run context out of the given metadata.
@@ -80,6 +94,8 @@ func NewContext(ctx context.Context, info RunInfo) (*Context, error) {
 		rpcs:        0,
 		rpcsLock:    mutex,
 		rpcsDone:    sync.NewCond(mutex),
+		providers:   make(map[*URNOutput]map[string]ProviderResource),
+		urns:        make(map[typedID]URN),
 	}, nil
 }
 
@@ -177,8 +193,9 @@ func (ctx *Context) Invoke(tok string, args map[string]interface{}, opts ...Invo
 	return outs, err
 }
 
-// ReadResource reads an existing custom resource's state from the resource monitor.  Note that resources read in this
-// way will not be part of the resulting stack's state, as they are presumed to belong to another.
+// ReadResource reads an existing custom resource's state from the resource monitor.  Resources read in this way are
+// recorded in the stack's state as external resources: their outputs may be used by other resources, but the engine
+// never updates or deletes them, as they are presumed to belong to another.
 func (ctx *Context) ReadResource(
 	t, name string, id ID, props map[string]interface{}, opts ...ResourceOpt) (*ResourceState, error) {
 	if t == "" {
@@ -194,8 +211,9 @@ func (ctx *Context) ReadResource(
 		return nil, err
 	}
 
-	// Create resolvers for the resource's outputs.
+	// Create resolvers for the resource's outputs, and use the parent's provider if none was supplied.
 	outputs := makeResourceOutputs(true, props)
+	opts = ctx.inheritProviders(t, true, (*URNOutput)(outputs.urn.out), opts)
 
 	// Kick off the resource read operation.  This will happen asynchronously and resolve the above properties.
 	go func() {
@@ -216,20 +234,23 @@ func (ctx *Context) ReadResource(
 
 		glog.V(9).Infof("ReadResource(%s, %s): Goroutine spawned, RPC call being made", t, name)
 		resp, err := ctx.monitor.ReadResource(ctx.ctx, &pulumirpc.ReadResourceRequest{
-			Type:       t,
-			Name:       name,
-			Parent:     inputs.parent,
-			Properties: inputs.rpcProps,
-			Provider:   inputs.provider,
+			Type:         t,
+			Name:         name,
+			Id:           string(id),
+			Parent:       inputs.parent,
+			Properties:   inputs.rpcProps,
+			Provider:     inputs.provider,
+			Dependencies: inputs.deps,
 		})
 		if err != nil {
-			glog.V(9).Infof("RegisterResource(%s, %s): error: %v", t, name, err)
+			glog.V(9).Infof("ReadResource(%s, %s): error: %v", t, name, err)
 		} else {
-			glog.V(9).Infof("RegisterResource(%s, %s): success: %s %s ...", t, name, resp.Urn, id)
+			glog.V(9).Infof("ReadResource(%s, %s): success: %s %s ...", t, name, resp.Urn, id)
 		}
 		if resp != nil {
 			urn, resID = resp.Urn, string(id)
 			state = resp.Properties
+			ctx.recordID(t, ID(resID), URN(urn))
 		}
 	}()
 
@@ -260,8 +281,9 @@ func (ctx *Context) RegisterResource(
 		return nil, err
 	}
 
-	// Create resolvers for the resource's outputs.
+	// Create resolvers for the resource's outputs, and use the parent's provider if none was supplied.
 	outputs := makeResourceOutputs(custom, props)
+	opts = ctx.inheritProviders(t, custom, (*URNOutput)(outputs.urn.out), opts)
 
 	// Kick off the resource registration.  If we are actually performing a deployment, the resulting properties
 	// will be resolved asynchronously as the RPC operation completes.  If we're just planning, values won't resolve.
@@ -302,6 +324,7 @@ func (ctx *Context) RegisterResource(
 		if resp != nil {
 			urn, resID = resp.Urn, resp.Id
 			state = resp.Object
+			ctx.recordID(t, ID(resID), URN(urn))
 		}
 	}()
 
@@ -320,6 +343,29 @@ func (ctx *Context) RegisterResource(
 	}, nil
 }
 
+// RegisterResourceCollection creates and registers count resources that form an indexed collection named name.  The
+// resource at index i is named `name[i]` and is given the properties returned by props(i).  Because each resource's
+// URN depends only upon its index, changing count creates or deletes only the resources at the indices that were added
+// or removed; the others are left in place.
+func (ctx *Context) RegisterResourceCollection(t, name string, count int, custom bool,
+	props func(i int) map[string]interface{}, opts ...ResourceOpt) ([]*ResourceState, error) {
+	if count < 0 {
+		return nil, errors.New("resource collection count cannot be negative")
+	} else if name == "" {
+		return nil, errors.New("resource collection name argument (for URN creation) cannot be empty")
+	}
+
+	states := make([]*ResourceState, count)
+	for i := range states {
+		state, err := ctx.RegisterResource(t, fmt.Sprintf("%s[%d]", name, i), custom, props(i), opts...)
+		if err != nil {
+			return nil, err
+		}
+		states[i] = state
+	}
+	return states, nil
+}
+
 // resourceOutputs captures the outputs and resolvers for a resource operation.
 type resourceOutputs struct {
 	urn   *resourceOutput
@@ -536,6 +582,93 @@ func (ctx *Context) getOpts(opts ...ResourceOpt) (URN, []URN, bool, string, bool
 	return parentURN, depURNs, protect, providerRef, false, nil
 }
 
+// LookupURN returns the URN of the resource of type t with the given ID, provided that resource has been registered or
+// read by this program and its ID has resolved.  This is useful for mapping the IDs that providers return in other
+// resources' outputs back to the resources they refer to.  During previews, IDs are usually unknown, so lookups fail.
+func (ctx *Context) LookupURN(t string, id ID) (URN, bool) {
+	ctx.urnsLock.Lock()
+	defer ctx.urnsLock.Unlock()
+	urn, has := ctx.urns[typedID{t: t, id: id}]
+	return urn, has
+}
+
+// recordID records the URN of a resource once its ID is known, for use by LookupURN.
+func (ctx *Context) recordID(t string, id ID, urn URN) {
+	if id == "" || id == rpcTokenUnknownValue || urn == "" {
+		return
+	}
+	ctx.urnsLock.Lock()
+	defer ctx.urnsLock.Unlock()
+	ctx.urns[typedID{t: t, id: id}] = urn
+}
+
+// inheritProviders records the providers that the children of the new resource of type t with the given URN will
+// inherit, and returns the options to register the resource with.  The children inherit the providers of the
+// resource's parent, along with any supplied in ResourceOpt.Providers.  A custom resource that was not given a
+// provider uses its parent's provider for its package; one that was given a provider passes it on to its children in
+// place of the parent's.
+func (ctx *Context) inheritProviders(t string, custom bool, urn *URNOutput, opts []ResourceOpt) []ResourceOpt {
+	var parent Resource
+	var provider ProviderResource
+	var providers map[string]ProviderResource
+	for _, opt := range opts {
+		if parent == nil && opt.Parent != nil {
+			parent = opt.Parent
+		}
+		if provider == nil && opt.Provider != nil {
+			provider = opt.Provider
+		}
+		if providers == nil && opt.Providers != nil {
+			providers = opt.Providers
+		}
+	}
+
+	ctx.providersLock.Lock()
+	defer ctx.providersLock.Unlock()
+
+	var inherited map[string]ProviderResource
+	if parent != nil {
+		inherited = ctx.providers[parent.URN()]
+	}
+
+	// Copy the inherited providers before changing them, as the parent's siblings may share the same map.
+	children := inherited
+	with := func(pkg string, p ProviderResource) {
+		if children[pkg] == p {
+			return
+		}
+		copied := make(map[string]ProviderResource)
+		for k, v := range children {
+			copied[k] = v
+		}
+		copied[pkg] = p
+		children = copied
+	}
+
+	if custom {
+		pkg := t
+		if i := strings.Index(t, ":"); i != -1 {
+			pkg = t[:i]
+		}
+		if provider == nil {
+			provider = inherited[pkg]
+			if provider != nil {
+				opts = append(append([]ResourceOpt(nil), opts...), ResourceOpt{Provider: provider})
+			}
+		} else {
+			with(pkg, provider)
+		}
+	} else {
+		for pkg, p := range providers {
+			with(pkg, p)
+		}
+	}
+	if len(children) > 0 {
+		ctx.providers[urn] = children
+	}
+	return opts
+}
+
 func (ctx *Context) resolveProviderReference(provider ProviderResource) (string, error) {
 	urn, err := provider.URN().Value()
 	if err != nil {
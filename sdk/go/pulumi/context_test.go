@@ -0,0 +1,86 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestResource() *ResourceState {
+	urn, _, _ := NewOutput(nil)
+	id, _, _ := NewOutput(nil)
+	return &ResourceState{urn: (*URNOutput)(urn), id: (*IDOutput)(id)}
+}
+
+// providerOf returns the provider that the given options select.
+func providerOf(opts []ResourceOpt) ProviderResource {
+	for _, opt := range opts {
+		if opt.Provider != nil {
+			return opt.Provider
+		}
+	}
+	return nil
+}
+
+// TestInheritProviders ensures that children inherit their parents' providers by package.
+func TestInheritProviders(t *testing.T) {
+	ctx := &Context{providers: make(map[*URNOutput]map[string]ProviderResource)}
+	aws, aws2, gcp := newTestResource(), newTestResource(), newTestResource()
+
+	// A component's providers are inherited by its children, but only for the matching package.
+	component := newTestResource()
+	opts := ctx.inheritProviders("my:component:Component", false, component.URN(), []ResourceOpt{{
+		Providers: map[string]ProviderResource{"aws": aws, "gcp": gcp},
+	}})
+	assert.Nil(t, providerOf(opts))
+
+	bucket := newTestResource()
+	opts = ctx.inheritProviders("aws:s3/bucket:Bucket", true, bucket.URN(), []ResourceOpt{{Parent: component}})
+	assert.Equal(t, aws, providerOf(opts))
+	opts = ctx.inheritProviders("azure:core:ResourceGroup", true, newTestResource().URN(),
+		[]ResourceOpt{{Parent: component}})
+	assert.Nil(t, providerOf(opts))
+
+	// An explicit provider takes precedence, and is passed on to the resource's children.
+	object := newTestResource()
+	opts = ctx.inheritProviders("aws:s3/bucketObject:BucketObject", true, object.URN(),
+		[]ResourceOpt{{Parent: component, Provider: aws2}})
+	assert.Equal(t, aws2, providerOf(opts))
+	opts = ctx.inheritProviders("aws:s3/bucketPolicy:BucketPolicy", true, newTestResource().URN(),
+		[]ResourceOpt{{Parent: object}})
+	assert.Equal(t, aws2, providerOf(opts))
+
+	// Overriding a provider must not affect the parent's other children.
+	assert.Equal(t, aws, ctx.providers[component.URN()]["aws"])
+	assert.Equal(t, gcp, ctx.providers[object.URN()]["gcp"])
+}
+
+// TestLookupURN ensures that resources can be found by their type and ID once their IDs are known.
+func TestLookupURN(t *testing.T) {
+	ctx := &Context{urns: make(map[typedID]URN)}
+	ctx.recordID("aws:s3/bucket:Bucket", "my-bucket", "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::bucket")
+	ctx.recordID("aws:s3/bucket:Bucket", rpcTokenUnknownValue, "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::other")
+
+	urn, has := ctx.LookupURN("aws:s3/bucket:Bucket", "my-bucket")
+	assert.True(t, has)
+	assert.Equal(t, URN("urn:pulumi:dev::proj::aws:s3/bucket:Bucket::bucket"), urn)
+
+	_, has = ctx.LookupURN("aws:s3/bucketObject:BucketObject", "my-bucket")
+	assert.False(t, has)
+	_, has = ctx.LookupURN("aws:s3/bucket:Bucket", rpcTokenUnknownValue)
+	assert.False(t, has)
+}
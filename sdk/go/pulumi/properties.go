@@ -321,6 +321,34 @@ func (out *Output) URN() (URN, error) {
 // Outputs is a map of property name to value, one for each resource output property.
 type Outputs map[string]*Output
 
+// FormatInput is an input string that the engine builds by interpolating the given arguments, which may be outputs,
+// into a template.  Each `%s` in the template is replaced by the next argument, and each `%%` by a single `%`.
+// Because the engine evaluates it, a format whose arguments are not yet known is itself unknown rather than a string
+// that holds a placeholder.
+type FormatInput struct {
+	Template string
+	Args     []interface{}
+}
+
+// Format returns an input string that interpolates the given arguments into the template.
+func Format(template string, args ...interface{}) FormatInput {
+	return FormatInput{Template: template, Args: args}
+}
+
+// FileRefInput is an input string whose value is held in a file, such as a rendered template or a certificate.  Only
+// the file's path and the SHA-256 hash of its contents are sent to the engine, which reads the file when it sends the
+// value to the resource's provider, and which compares the value with others by its hash.
+type FileRefInput struct {
+	Path string
+	Hash string
+}
+
+// FileRef returns an input string that refers to the contents of the file at the given path, which have the given
+// hex-encoded SHA-256 hash.
+func FileRef(path, sha256 string) FileRefInput {
+	return FileRefInput{Path: path, Hash: sha256}
+}
+
 // ArchiveOutput is an Output that is typed to return archive values.
 type ArchiveOutput Output
 
@@ -58,8 +58,13 @@ type ResourceOpt struct {
 	DependsOn []Resource
 	// Protect, when set to true, ensures that this resource cannot be deleted (without first setting it to false).
 	Protect bool
-	// Provider is an optional provider resource to use for this resource's CRUD operations.
+	// Provider is an optional provider resource to use for this resource's CRUD operations.  If no provider is
+	// supplied, the provider that the resource's parent uses for its package, if any, is used instead.  Otherwise, the
+	// default provider for the resource's package is used.
 	Provider ProviderResource
+	// Providers is an optional set of providers to use for this resource's children, keyed by package name (e.g.
+	// "aws").  Only component resources use this option; the children of a custom resource inherit its Provider.
+	Providers map[string]ProviderResource
 	// DeleteBeforeReplace, when set to true, ensures that this resource is deleted prior to replacement.
 	DeleteBeforeReplace bool
 }
@@ -68,6 +68,8 @@ const (
 	rpcTokenSpecialAssetSig   = "c44067f5952c0a294b673a41bacd8c17"
 	rpcTokenSpecialArchiveSig = "0def7320c3a5731c473e5ecbe6d01bc7"
 	rpcTokenSpecialSecretSig  = "1b47061264138c4ac30d75fd1eb44270"
+	rpcTokenSpecialFormatSig  = "7d2d6bc3f0a1e06e4ea1f1a5e2b27d3c"
+	rpcTokenSpecialFileRefSig = "82e9bc30b221968556dd59ad0f1f12f8"
 	rpcTokenUnknownValue      = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
 )
 
@@ -108,6 +110,22 @@ func marshalInput(v interface{}) (interface{}, []Resource, error) {
 			"path":                t.Path(),
 			"uri":                 t.URI(),
 		}, nil, nil
+	case FormatInput:
+		args, deps, err := marshalInput(t.Args)
+		if err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{
+			rpcTokenSpecialSigKey: rpcTokenSpecialFormatSig,
+			"template":            t.Template,
+			"args":                args,
+		}, deps, nil
+	case FileRefInput:
+		return map[string]interface{}{
+			rpcTokenSpecialSigKey: rpcTokenSpecialFileRefSig,
+			"path":                t.Path,
+			"hash":                t.Hash,
+		}, nil, nil
 	case Output:
 		return marshalInputOutput(&t)
 	case *Output:
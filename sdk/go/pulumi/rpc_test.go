@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/go/pulumi/asset"
 )
 
@@ -105,3 +106,39 @@ func TestUnmarshalUnknownSig(t *testing.T) {
 	_, err = unmarshalOutput(m)
 	assert.Error(t, err)
 }
+
+func TestMarshalFileRef(t *testing.T) {
+	m, _, _, err := marshalInputs(map[string]interface{}{
+		"cert": FileRef("cert.pem", "9f86d081884c7d65"),
+	})
+	assert.NoError(t, err)
+
+	// The engine recovers the reference without reading the file.
+	props, err := plugin.UnmarshalProperties(m, plugin.MarshalOptions{})
+	assert.NoError(t, err)
+	assert.True(t, props["cert"].IsFileRef())
+	assert.Equal(t, "cert.pem", props["cert"].FileRefValue().Path)
+	assert.Equal(t, "9f86d081884c7d65", props["cert"].FileRefValue().Hash)
+}
+
+func TestMarshalFormat(t *testing.T) {
+	known, resolveKnown, _ := NewOutput(nil)
+	resolveKnown("my-bucket", true)
+	unknown, resolveUnknown, _ := NewOutput(nil)
+	resolveUnknown(nil, false)
+
+	m, _, _, err := marshalInputs(map[string]interface{}{
+		"known":   Format("arn:aws:s3:::%s/%s", known, 42),
+		"unknown": Format("arn:aws:s3:::%s", unknown),
+	})
+	assert.NoError(t, err)
+
+	// The engine recovers the formats and evaluates them, propagating unknowns.
+	props, err := plugin.UnmarshalProperties(m, plugin.MarshalOptions{KeepUnknowns: true})
+	assert.NoError(t, err)
+	assert.True(t, props["known"].IsFormat())
+	props, err = props.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:s3:::my-bucket/42", props["known"].StringValue())
+	assert.True(t, props["unknown"].IsComputed())
+}
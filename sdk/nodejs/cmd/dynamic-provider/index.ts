@@ -201,7 +201,9 @@ async function updateRPC(call: any, callback: any): Promise<void> {
         const req: any = call.request;
         const resp = new provproto.UpdateResponse();
 
-        const olds = req.getOlds().toJavaScript();
+        // Dynamic providers have always been handed their old inputs merged with their old outputs, so preserve that.
+        const oldInputs = req.getOldinputs() ? req.getOldinputs().toJavaScript() : {};
+        const olds = Object.assign({}, oldInputs, req.getOlds().toJavaScript());
         const news = req.getNews().toJavaScript();
 
         let result: any = {};
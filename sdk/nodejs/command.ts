@@ -0,0 +1,84 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { Input, Output } from "./output";
+import { CustomResource, CustomResourceOptions } from "./resource";
+
+/**
+ * Runs local commands when it is created, updated, and deleted. The commands are run by the Pulumi engine on the
+ * machine that runs the update, and the standard output of the last create or update command is available via the
+ * `stdout` property.
+ */
+export class Command extends CustomResource {
+    /**
+     * The standard output of the last create or update command.
+     */
+    public readonly stdout: Output<string>;
+
+    /**
+     * Create a Command resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the command.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args: CommandArgs, opts?: CustomResourceOptions) {
+        super("pulumi:command:Command", name, {
+            create: args.create,
+            update: args.update,
+            delete: args.delete,
+            triggers: args.triggers,
+            environment: args.environment,
+            dir: args.dir,
+            interpreter: args.interpreter,
+            stdout: undefined,
+        }, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a Command resource.
+ */
+export interface CommandArgs {
+    /**
+     * The command to run when the resource is created.
+     */
+    readonly create: Input<string>;
+    /**
+     * The command to run when the triggers or environment change. If it is not set, such changes replace the
+     * resource instead, which runs the create command again.
+     */
+    readonly update?: Input<string>;
+    /**
+     * The command to run when the resource is deleted.
+     */
+    readonly delete?: Input<string>;
+    /**
+     * Arbitrary values whose changes cause the resource to be updated or replaced.
+     */
+    readonly triggers?: Input<{[key: string]: any}>;
+    /**
+     * Variables to add to the environment of each command, e.g. the outputs of other resources.
+     */
+    readonly environment?: Input<{[name: string]: Input<string>}>;
+    /**
+     * The directory in which to run the commands.
+     */
+    readonly dir?: Input<string>;
+    /**
+     * The program and arguments that run each command. Defaults to `["/bin/sh", "-c"]`, or `["cmd", "/C"]` on
+     * Windows.
+     */
+    readonly interpreter?: Input<Input<string>[]>;
+}
@@ -0,0 +1,72 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { Input } from "../output";
+import * as runtime from "../runtime";
+
+/**
+ * The arguments to an HTTP request.
+ */
+export interface RequestArgs {
+    /**
+     * The URL to send the request to.
+     */
+    readonly url: Input<string>;
+    /**
+     * The request's method. Defaults to GET. Because functions also run during previews, `request` only sends GET
+     * and HEAD requests.
+     */
+    readonly method?: Input<string>;
+    /**
+     * The request's headers.
+     */
+    readonly headers?: Input<{[name: string]: Input<string>}>;
+    /**
+     * The request's body.
+     */
+    readonly body?: Input<string>;
+}
+
+/**
+ * The response to an HTTP request.
+ */
+export interface Response {
+    /**
+     * The response's status code.
+     */
+    readonly status: number;
+    /**
+     * The response's headers. A header that appears more than once holds its values joined by commas.
+     */
+    readonly headers: {[name: string]: string};
+    /**
+     * The response's body. Webhook calls record their responses' bodies as secrets.
+     */
+    readonly body?: string;
+}
+
+/**
+ * Sends an HTTP request from the Pulumi engine and returns its response, e.g. to fetch a token from an external
+ * system. Responses with a status other than 2xx are reported as errors.
+ *
+ * @param args The request to send.
+ */
+export function request(args: RequestArgs): Promise<Response> {
+    return runtime.invoke("pulumi:http:request", {
+        url: args.url,
+        method: args.method,
+        headers: args.headers,
+        body: args.body,
+    });
+}
@@ -16,6 +16,7 @@
 import "source-map-support/register";
 
 // Export top-level elements.
+export * from "./command";
 export * from "./config";
 export * from "./errors";
 export * from "./invoke";
@@ -27,10 +28,12 @@ export * from "./stackReference";
 // Export submodules individually.
 import * as asset from "./asset";
 import * as dynamic from "./dynamic";
+import * as http from "./http";
 import * as iterable from "./iterable";
 import * as log from "./log";
 import * as runtime from "./runtime";
-export { asset, dynamic, iterable, log, runtime };
+import * as webhook from "./webhook";
+export { asset, dynamic, http, iterable, log, runtime, webhook };
 
 // @pulumi is a deployment-only module.  If someone tries to capture it, and we fail for some reason
 // we want to give a good message about what the problem likely is.  Note that capturing a
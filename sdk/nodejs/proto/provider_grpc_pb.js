@@ -99,6 +99,28 @@ function deserialize_pulumirpc_DeleteRequest(buffer_arg) {
   return provider_pb.DeleteRequest.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_pulumirpc_DiffBatchRequest(arg) {
+  if (!(arg instanceof provider_pb.DiffBatchRequest)) {
+    throw new Error('Expected argument of type pulumirpc.DiffBatchRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_DiffBatchRequest(buffer_arg) {
+  return provider_pb.DiffBatchRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_DiffBatchResponse(arg) {
+  if (!(arg instanceof provider_pb.DiffBatchResponse)) {
+    throw new Error('Expected argument of type pulumirpc.DiffBatchResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_DiffBatchResponse(buffer_arg) {
+  return provider_pb.DiffBatchResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_pulumirpc_DiffRequest(arg) {
   if (!(arg instanceof provider_pb.DiffRequest)) {
     throw new Error('Expected argument of type pulumirpc.DiffRequest');
@@ -121,6 +143,28 @@ function deserialize_pulumirpc_DiffResponse(buffer_arg) {
   return provider_pb.DiffResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_pulumirpc_GetSchemaRequest(arg) {
+  if (!(arg instanceof provider_pb.GetSchemaRequest)) {
+    throw new Error('Expected argument of type pulumirpc.GetSchemaRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_GetSchemaRequest(buffer_arg) {
+  return provider_pb.GetSchemaRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_GetSchemaResponse(arg) {
+  if (!(arg instanceof provider_pb.GetSchemaResponse)) {
+    throw new Error('Expected argument of type pulumirpc.GetSchemaResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_GetSchemaResponse(buffer_arg) {
+  return provider_pb.GetSchemaResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_pulumirpc_InvokeRequest(arg) {
   if (!(arg instanceof provider_pb.InvokeRequest)) {
     throw new Error('Expected argument of type pulumirpc.InvokeRequest');
@@ -154,6 +198,39 @@ function deserialize_pulumirpc_PluginInfo(buffer_arg) {
   return plugin_pb.PluginInfo.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_pulumirpc_ProviderCapabilities(arg) {
+  if (!(arg instanceof provider_pb.ProviderCapabilities)) {
+    throw new Error('Expected argument of type pulumirpc.ProviderCapabilities');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ProviderCapabilities(buffer_arg) {
+  return provider_pb.ProviderCapabilities.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_ReadBatchRequest(arg) {
+  if (!(arg instanceof provider_pb.ReadBatchRequest)) {
+    throw new Error('Expected argument of type pulumirpc.ReadBatchRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ReadBatchRequest(buffer_arg) {
+  return provider_pb.ReadBatchRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_ReadBatchResponse(arg) {
+  if (!(arg instanceof provider_pb.ReadBatchResponse)) {
+    throw new Error('Expected argument of type pulumirpc.ReadBatchResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ReadBatchResponse(buffer_arg) {
+  return provider_pb.ReadBatchResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_pulumirpc_ReadRequest(arg) {
   if (!(arg instanceof provider_pb.ReadRequest)) {
     throw new Error('Expected argument of type pulumirpc.ReadRequest');
@@ -328,6 +405,32 @@ var ResourceProviderService = exports.ResourceProviderService = {
     responseSerialize: serialize_google_protobuf_Empty,
     responseDeserialize: deserialize_google_protobuf_Empty,
   },
+  // ReadBatch reads the current live state of many resources in a single call, e.g. during a refresh.  Providers
+  // that do not implement it return UNIMPLEMENTED, and the engine falls back to calling Read for each resource.
+  readBatch: {
+    path: '/pulumirpc.ResourceProvider/ReadBatch',
+    requestStream: false,
+    responseStream: false,
+    requestType: provider_pb.ReadBatchRequest,
+    responseType: provider_pb.ReadBatchResponse,
+    requestSerialize: serialize_pulumirpc_ReadBatchRequest,
+    requestDeserialize: deserialize_pulumirpc_ReadBatchRequest,
+    responseSerialize: serialize_pulumirpc_ReadBatchResponse,
+    responseDeserialize: deserialize_pulumirpc_ReadBatchResponse,
+  },
+  // DiffBatch checks what impacts hypothetical updates will have on many resources in a single call.  Providers
+  // that do not implement it return UNIMPLEMENTED, and the engine falls back to calling Diff for each resource.
+  diffBatch: {
+    path: '/pulumirpc.ResourceProvider/DiffBatch',
+    requestStream: false,
+    responseStream: false,
+    requestType: provider_pb.DiffBatchRequest,
+    responseType: provider_pb.DiffBatchResponse,
+    requestSerialize: serialize_pulumirpc_DiffBatchRequest,
+    requestDeserialize: deserialize_pulumirpc_DiffBatchRequest,
+    responseSerialize: serialize_pulumirpc_DiffBatchResponse,
+    responseDeserialize: deserialize_pulumirpc_DiffBatchResponse,
+  },
   // Cancel signals the provider to abort all outstanding resource operations.
   cancel: {
     path: '/pulumirpc.ResourceProvider/Cancel',
@@ -352,6 +455,32 @@ var ResourceProviderService = exports.ResourceProviderService = {
     responseSerialize: serialize_pulumirpc_PluginInfo,
     responseDeserialize: deserialize_pulumirpc_PluginInfo,
   },
+  // GetCapabilities returns the optional features that this provider supports, so that the engine can adapt to
+  // them.  Providers that do not implement it return UNIMPLEMENTED, and the engine assumes a default set.
+  getCapabilities: {
+    path: '/pulumirpc.ResourceProvider/GetCapabilities',
+    requestStream: false,
+    responseStream: false,
+    requestType: google_protobuf_empty_pb.Empty,
+    responseType: provider_pb.ProviderCapabilities,
+    requestSerialize: serialize_google_protobuf_Empty,
+    requestDeserialize: deserialize_google_protobuf_Empty,
+    responseSerialize: serialize_pulumirpc_ProviderCapabilities,
+    responseDeserialize: deserialize_pulumirpc_ProviderCapabilities,
+  },
+  // GetSchema returns a machine-readable description of the resource types that this provider manages and of their
+  // properties, from which clients can generate code.  Providers that do not implement it return UNIMPLEMENTED.
+  getSchema: {
+    path: '/pulumirpc.ResourceProvider/GetSchema',
+    requestStream: false,
+    responseStream: false,
+    requestType: provider_pb.GetSchemaRequest,
+    responseType: provider_pb.GetSchemaResponse,
+    requestSerialize: serialize_pulumirpc_GetSchemaRequest,
+    requestDeserialize: deserialize_pulumirpc_GetSchemaRequest,
+    responseSerialize: serialize_pulumirpc_GetSchemaResponse,
+    responseDeserialize: deserialize_pulumirpc_GetSchemaResponse,
+  },
 };
 
 exports.ResourceProviderClient = grpc.makeGenericClientConstructor(ResourceProviderService);
@@ -18,7 +18,10 @@ goog.exportSymbol('proto.pulumirpc.ReadResourceRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.ReadResourceResponse', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceOutputsRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest', null, global);
+goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.CustomTimeouts', null, global);
+goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.Hook', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.PropertyDependencies', null, global);
+goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.PropertyRead', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceResponse', null, global);
 
 /**
@@ -571,7 +574,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<number>}
  * @const
  */
-proto.pulumirpc.RegisterResourceRequest.repeatedFields_ = [7];
+proto.pulumirpc.RegisterResourceRequest.repeatedFields_ = [7,12,13,14,15,17];
 
 
 
@@ -611,7 +614,15 @@ proto.pulumirpc.RegisterResourceRequest.toObject = function(includeInstance, msg
     dependenciesList: jspb.Message.getRepeatedField(msg, 7),
     provider: jspb.Message.getFieldWithDefault(msg, 8, ""),
     propertydependenciesMap: (f = msg.getPropertydependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.toObject) : [],
-    deletebeforereplace: jspb.Message.getFieldWithDefault(msg, 10, false)
+    deletebeforereplace: jspb.Message.getFieldWithDefault(msg, 10, false),
+    retainondelete: jspb.Message.getFieldWithDefault(msg, 11, false),
+    aliasesList: jspb.Message.getRepeatedField(msg, 12),
+    ignorechangesList: jspb.Message.getRepeatedField(msg, 13),
+    replaceonchangesList: jspb.Message.getRepeatedField(msg, 14),
+    readyconditionsList: jspb.Message.getRepeatedField(msg, 15),
+    customtimeouts: (f = msg.getCustomtimeouts()) && proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject(includeInstance, f),
+    hooksList: jspb.Message.toObjectList(msg.getHooksList(),
+    proto.pulumirpc.RegisterResourceRequest.Hook.toObject, includeInstance)
   };
 
   if (includeInstance) {
@@ -691,6 +702,36 @@ proto.pulumirpc.RegisterResourceRequest.deserializeBinaryFromReader = function(m
       var value = /** @type {boolean} */ (reader.readBool());
       msg.setDeletebeforereplace(value);
       break;
+    case 11:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setRetainondelete(value);
+      break;
+    case 12:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAliases(value);
+      break;
+    case 13:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addIgnorechanges(value);
+      break;
+    case 14:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addReplaceonchanges(value);
+      break;
+    case 15:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addReadyconditions(value);
+      break;
+    case 16:
+      var value = new proto.pulumirpc.RegisterResourceRequest.CustomTimeouts;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinaryFromReader);
+      msg.setCustomtimeouts(value);
+      break;
+    case 17:
+      var value = new proto.pulumirpc.RegisterResourceRequest.Hook;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.Hook.deserializeBinaryFromReader);
+      msg.addHooks(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -788,6 +829,57 @@ proto.pulumirpc.RegisterResourceRequest.serializeBinaryToWriter = function(messa
       f
     );
   }
+  f = message.getRetainondelete();
+  if (f) {
+    writer.writeBool(
+      11,
+      f
+    );
+  }
+  f = message.getAliasesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      12,
+      f
+    );
+  }
+  f = message.getIgnorechangesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      13,
+      f
+    );
+  }
+  f = message.getReplaceonchangesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      14,
+      f
+    );
+  }
+  f = message.getReadyconditionsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      15,
+      f
+    );
+  }
+  f = message.getCustomtimeouts();
+  if (f != null) {
+    writer.writeMessage(
+      16,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.serializeBinaryToWriter
+    );
+  }
+  f = message.getHooksList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      17,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.Hook.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -814,7 +906,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<number>}
  * @const
  */
-proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.repeatedFields_ = [1];
+proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.repeatedFields_ = [1,2];
 
 
 
@@ -845,7 +937,9 @@ proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.prototype.toObject
  */
 proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.toObject = function(includeInstance, msg) {
   var f, obj = {
-    urnsList: jspb.Message.getRepeatedField(msg, 1)
+    urnsList: jspb.Message.getRepeatedField(msg, 1),
+    readsList: jspb.Message.toObjectList(msg.getReadsList(),
+    proto.pulumirpc.RegisterResourceRequest.PropertyRead.toObject, includeInstance)
   };
 
   if (includeInstance) {
@@ -886,6 +980,11 @@ proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.deserializeBinaryFr
       var value = /** @type {string} */ (reader.readString());
       msg.addUrns(value);
       break;
+    case 2:
+      var value = new proto.pulumirpc.RegisterResourceRequest.PropertyRead;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.PropertyRead.deserializeBinaryFromReader);
+      msg.addReads(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -922,6 +1021,14 @@ proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.serializeBinaryToWr
       f
     );
   }
+  f = message.getReadsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      2,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.PropertyRead.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -955,183 +1062,887 @@ proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.prototype.clearUrns
 
 
 /**
- * optional string type = 1;
- * @return {string}
+ * repeated PropertyRead reads = 2;
+ * @return {!Array.<!proto.pulumirpc.RegisterResourceRequest.PropertyRead>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getType = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.prototype.getReadsList = function() {
+  return /** @type{!Array.<!proto.pulumirpc.RegisterResourceRequest.PropertyRead>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.pulumirpc.RegisterResourceRequest.PropertyRead, 2));
 };
 
 
-/** @param {string} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setType = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+/** @param {!Array.<!proto.pulumirpc.RegisterResourceRequest.PropertyRead>} value */
+proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.prototype.setReadsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 2, value);
 };
 
 
 /**
- * optional string name = 2;
- * @return {string}
+ * @param {!proto.pulumirpc.RegisterResourceRequest.PropertyRead=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest.PropertyRead}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getName = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.prototype.addReads = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 2, opt_value, proto.pulumirpc.RegisterResourceRequest.PropertyRead, opt_index);
 };
 
 
-/** @param {string} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setName = function(value) {
-  jspb.Message.setProto3StringField(this, 2, value);
+proto.pulumirpc.RegisterResourceRequest.PropertyDependencies.prototype.clearReadsList = function() {
+  this.setReadsList([]);
 };
 
 
+
 /**
- * optional string parent = 3;
- * @return {string}
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getParent = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
-};
-
-
-/** @param {string} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setParent = function(value) {
-  jspb.Message.setProto3StringField(this, 3, value);
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
+goog.inherits(proto.pulumirpc.RegisterResourceRequest.CustomTimeouts, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.displayName = 'proto.pulumirpc.RegisterResourceRequest.CustomTimeouts';
+}
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * optional bool custom = 4;
- * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
- * You should avoid comparisons like {@code val === true/false} in those cases.
- * @return {boolean}
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getCustom = function() {
-  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 4, false));
-};
-
-
-/** @param {boolean} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setCustom = function(value) {
-  jspb.Message.setProto3BooleanField(this, 4, value);
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * optional google.protobuf.Struct object = 5;
- * @return {?proto.google.protobuf.Struct}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getObject = function() {
-  return /** @type{?proto.google.protobuf.Struct} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 5));
-};
-
-
-/** @param {?proto.google.protobuf.Struct|undefined} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setObject = function(value) {
-  jspb.Message.setWrapperField(this, 5, value);
-};
-
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    create: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    update: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    pb_delete: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
 
-proto.pulumirpc.RegisterResourceRequest.prototype.clearObject = function() {
-  this.setObject(undefined);
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * Returns whether this field is set.
- * @return {!boolean}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.hasObject = function() {
-  return jspb.Message.getField(this, 5) != null;
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceRequest.CustomTimeouts;
+  return proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * optional bool protect = 6;
- * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
- * You should avoid comparisons like {@code val === true/false} in those cases.
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getProtect = function() {
-  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 6, false));
-};
-
-
-/** @param {boolean} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setProtect = function(value) {
-  jspb.Message.setProto3BooleanField(this, 6, value);
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setCreate(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUpdate(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDelete(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * repeated string dependencies = 7;
- * @return {!Array.<string>}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getDependenciesList = function() {
-  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 7));
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
-/** @param {!Array.<string>} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setDependenciesList = function(value) {
-  jspb.Message.setField(this, 7, value || []);
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCreate();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getUpdate();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getDelete();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
 };
 
 
 /**
- * @param {!string} value
- * @param {number=} opt_index
+ * optional string create = 1;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addDependencies = function(value, opt_index) {
-  jspb.Message.addToRepeatedField(this, 7, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.getCreate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
-proto.pulumirpc.RegisterResourceRequest.prototype.clearDependenciesList = function() {
-  this.setDependenciesList([]);
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.setCreate = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string provider = 8;
+ * optional string update = 2;
  * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getProvider = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.getUpdate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /** @param {string} value */
-proto.pulumirpc.RegisterResourceRequest.prototype.setProvider = function(value) {
-  jspb.Message.setProto3StringField(this, 8, value);
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.setUpdate = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * map<string, PropertyDependencies> propertyDependencies = 9;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>}
+ * optional string delete = 3;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getPropertydependenciesMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>} */ (
-      jspb.Message.getMapField(this, 9, opt_noLazyCreate,
-      proto.pulumirpc.RegisterResourceRequest.PropertyDependencies));
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.getDelete = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
-proto.pulumirpc.RegisterResourceRequest.prototype.clearPropertydependenciesMap = function() {
-  this.getPropertydependenciesMap().clear();
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.setDelete = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
+
 /**
- * optional bool deleteBeforeReplace = 10;
- * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
- * You should avoid comparisons like {@code val === true/false} in those cases.
- * @return {boolean}
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.getDeletebeforereplace = function() {
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.pulumirpc.RegisterResourceRequest.Hook.repeatedFields_, null);
+};
+goog.inherits(proto.pulumirpc.RegisterResourceRequest.Hook, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  proto.pulumirpc.RegisterResourceRequest.Hook.displayName = 'proto.pulumirpc.RegisterResourceRequest.Hook';
+}
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.repeatedFields_ = [2,3];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceRequest.Hook.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceRequest.Hook} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    eventsList: jspb.Message.getRepeatedField(msg, 2),
+    commandList: jspb.Message.getRepeatedField(msg, 3),
+    advisory: jspb.Message.getFieldWithDefault(msg, 4, false),
+    timeout: jspb.Message.getFieldWithDefault(msg, 5, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.Hook}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceRequest.Hook;
+  return proto.pulumirpc.RegisterResourceRequest.Hook.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.Hook} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.Hook}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addEvents(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addCommand(value);
+      break;
+    case 4:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setAdvisory(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceRequest.Hook.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.Hook} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getEventsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      2,
+      f
+    );
+  }
+  f = message.getCommandList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      3,
+      f
+    );
+  }
+  f = message.getAdvisory();
+  if (f) {
+    writer.writeBool(
+      4,
+      f
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string name = 1;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * repeated string events = 2;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.getEventsList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 2));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.setEventsList = function(value) {
+  jspb.Message.setField(this, 2, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.addEvents = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 2, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.clearEventsList = function() {
+  this.setEventsList([]);
+};
+
+
+/**
+ * repeated string command = 3;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.getCommandList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.setCommandList = function(value) {
+  jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.addCommand = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.clearCommandList = function() {
+  this.setCommandList([]);
+};
+
+
+/**
+ * optional bool advisory = 4;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.getAdvisory = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 4, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.setAdvisory = function(value) {
+  jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+/**
+ * optional string timeout = 5;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.Hook.prototype.setTimeout = function(value) {
+  jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.pulumirpc.RegisterResourceRequest.PropertyRead.repeatedFields_, null);
+};
+goog.inherits(proto.pulumirpc.RegisterResourceRequest.PropertyRead, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  proto.pulumirpc.RegisterResourceRequest.PropertyRead.displayName = 'proto.pulumirpc.RegisterResourceRequest.PropertyRead';
+}
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.repeatedFields_ = [2];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceRequest.PropertyRead.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceRequest.PropertyRead} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    pathsList: jspb.Message.getRepeatedField(msg, 2)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.PropertyRead}
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceRequest.PropertyRead;
+  return proto.pulumirpc.RegisterResourceRequest.PropertyRead.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.PropertyRead} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.PropertyRead}
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrn(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addPaths(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceRequest.PropertyRead.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.PropertyRead} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrn();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getPathsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string urn = 1;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.getUrn = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.setUrn = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * repeated string paths = 2;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.getPathsList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 2));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.setPathsList = function(value) {
+  jspb.Message.setField(this, 2, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.addPaths = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 2, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.PropertyRead.prototype.clearPathsList = function() {
+  this.setPathsList([]);
+};
+
+
+/**
+ * optional string type = 1;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setType = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string name = 2;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string parent = 3;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getParent = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setParent = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional bool custom = 4;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getCustom = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 4, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setCustom = function(value) {
+  jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+/**
+ * optional google.protobuf.Struct object = 5;
+ * @return {?proto.google.protobuf.Struct}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getObject = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 5));
+};
+
+
+/** @param {?proto.google.protobuf.Struct|undefined} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setObject = function(value) {
+  jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearObject = function() {
+  this.setObject(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {!boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.hasObject = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional bool protect = 6;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getProtect = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 6, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setProtect = function(value) {
+  jspb.Message.setProto3BooleanField(this, 6, value);
+};
+
+
+/**
+ * repeated string dependencies = 7;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getDependenciesList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 7));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setDependenciesList = function(value) {
+  jspb.Message.setField(this, 7, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addDependencies = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 7, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearDependenciesList = function() {
+  this.setDependenciesList([]);
+};
+
+
+/**
+ * optional string provider = 8;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getProvider = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+};
+
+
+/** @param {string} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setProvider = function(value) {
+  jspb.Message.setProto3StringField(this, 8, value);
+};
+
+
+/**
+ * map<string, PropertyDependencies> propertyDependencies = 9;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getPropertydependenciesMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>} */ (
+      jspb.Message.getMapField(this, 9, opt_noLazyCreate,
+      proto.pulumirpc.RegisterResourceRequest.PropertyDependencies));
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearPropertydependenciesMap = function() {
+  this.getPropertydependenciesMap().clear();
+};
+
+
+/**
+ * optional bool deleteBeforeReplace = 10;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getDeletebeforereplace = function() {
   return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 10, false));
 };
 
@@ -1142,6 +1953,200 @@ proto.pulumirpc.RegisterResourceRequest.prototype.setDeletebeforereplace = funct
 };
 
 
+/**
+ * optional bool retainOnDelete = 11;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetainondelete = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 11, false));
+};
+
+
+/** @param {boolean} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetainondelete = function(value) {
+  jspb.Message.setProto3BooleanField(this, 11, value);
+};
+
+
+/**
+ * repeated string aliases = 12;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getAliasesList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 12));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setAliasesList = function(value) {
+  jspb.Message.setField(this, 12, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addAliases = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 12, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearAliasesList = function() {
+  this.setAliasesList([]);
+};
+
+
+/**
+ * repeated string ignoreChanges = 13;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getIgnorechangesList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 13));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setIgnorechangesList = function(value) {
+  jspb.Message.setField(this, 13, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addIgnorechanges = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 13, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearIgnorechangesList = function() {
+  this.setIgnorechangesList([]);
+};
+
+
+/**
+ * repeated string replaceOnChanges = 14;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getReplaceonchangesList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 14));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setReplaceonchangesList = function(value) {
+  jspb.Message.setField(this, 14, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addReplaceonchanges = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 14, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearReplaceonchangesList = function() {
+  this.setReplaceonchangesList([]);
+};
+
+
+/**
+ * repeated string readyConditions = 15;
+ * @return {!Array.<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getReadyconditionsList = function() {
+  return /** @type {!Array.<string>} */ (jspb.Message.getRepeatedField(this, 15));
+};
+
+
+/** @param {!Array.<string>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setReadyconditionsList = function(value) {
+  jspb.Message.setField(this, 15, value || []);
+};
+
+
+/**
+ * @param {!string} value
+ * @param {number=} opt_index
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addReadyconditions = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 15, value, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearReadyconditionsList = function() {
+  this.setReadyconditionsList([]);
+};
+
+
+/**
+ * optional CustomTimeouts customTimeouts = 16;
+ * @return {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getCustomtimeouts = function() {
+  return /** @type{?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.CustomTimeouts, 16));
+};
+
+
+/** @param {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts|undefined} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setCustomtimeouts = function(value) {
+  jspb.Message.setWrapperField(this, 16, value);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearCustomtimeouts = function() {
+  this.setCustomtimeouts(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {!boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.hasCustomtimeouts = function() {
+  return jspb.Message.getField(this, 16) != null;
+};
+
+
+/**
+ * repeated Hook hooks = 17;
+ * @return {!Array.<!proto.pulumirpc.RegisterResourceRequest.Hook>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getHooksList = function() {
+  return /** @type{!Array.<!proto.pulumirpc.RegisterResourceRequest.Hook>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.pulumirpc.RegisterResourceRequest.Hook, 17));
+};
+
+
+/** @param {!Array.<!proto.pulumirpc.RegisterResourceRequest.Hook>} value */
+proto.pulumirpc.RegisterResourceRequest.prototype.setHooksList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 17, value);
+};
+
+
+/**
+ * @param {!proto.pulumirpc.RegisterResourceRequest.Hook=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest.Hook}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addHooks = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 17, opt_value, proto.pulumirpc.RegisterResourceRequest.Hook, opt_index);
+};
+
+
+proto.pulumirpc.RegisterResourceRequest.prototype.clearHooksList = function() {
+  this.setHooksList([]);
+};
+
+
 
 /**
  * Generated by JsPbCodeGenerator.
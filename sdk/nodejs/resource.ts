@@ -158,6 +158,28 @@ export interface ResourceOptions {
      * When set to true, protect ensures this resource cannot be deleted.
      */
     protect?: boolean;
+    /**
+     * An optional list of URNs by which this resource was previously known. If the stack's state contains a resource
+     * with one of these URNs, that resource is updated in place rather than replaced. This allows resources to be
+     * renamed or moved under a new parent without being recreated.
+     */
+    aliases?: URN[];
+    /**
+     * An optional list of property paths, e.g. `tags.Name` or `rules[0].cidr`, whose changes should be ignored when
+     * updating this resource. The values of these properties are taken from the resource's current state instead of
+     * from the program, which prevents perpetual diffs on properties that are changed outside of Pulumi.
+     */
+    ignoreChanges?: string[];
+    /**
+     * An optional list of property paths whose changes should force this resource to be replaced, even if its
+     * provider would otherwise update it in place. The path `*` forces a replacement on any change.
+     */
+    replaceOnChanges?: string[];
+    /**
+     * An optional list of hooks that run local commands when this resource is created or updated. These run after any
+     * hooks that are configured for the stack.
+     */
+    hooks?: ResourceHook[];
 }
 
 /**
@@ -176,6 +198,80 @@ export interface CustomResourceOptions extends ResourceOptions {
      * is created when replacement is necessary.
      */
     deleteBeforeReplace?: boolean;
+
+    /**
+     * When set to true, retainOnDelete indicates that deleting this resource should only remove it from the stack's
+     * state; the provider will not be asked to delete the underlying resource.
+     */
+    retainOnDelete?: boolean;
+
+    /**
+     * An optional list of conditions on this resource's outputs that must hold before it is considered ready, such as
+     * `status.phase == Running`. After the resource is created or updated, it is refreshed until every condition holds,
+     * and resources that depend on it wait until then. A condition that is just a property path requires that the
+     * property be present and truthy.
+     */
+    readyConditions?: string[];
+
+    /**
+     * An optional set of timeouts that bound how long the provider may take to create, update, or delete this
+     * resource. If an operation times out, its outcome is unknown, so it is recorded as pending in the stack's state.
+     */
+    customTimeouts?: CustomTimeouts;
+}
+
+/**
+ * CustomTimeouts bounds how long each of a custom resource's provider operations may take. Each timeout is a duration
+ * string such as "30s", "5m", or "1h30m". Operations without a timeout may take as long as they need.
+ */
+export interface CustomTimeouts {
+    /**
+     * The optional maximum time to wait for the resource to be created.
+     */
+    create?: string;
+
+    /**
+     * The optional maximum time to wait for the resource to be updated.
+     */
+    update?: string;
+
+    /**
+     * The optional maximum time to wait for the resource to be deleted.
+     */
+    delete?: string;
+}
+
+/**
+ * ResourceHook runs a local command at points in the lifecycle of the resource for which it is registered. The command
+ * receives the event and the resource's URN in the `PULUMI_HOOK_EVENT` and `PULUMI_HOOK_URN` environment variables,
+ * and the resource's properties as a JSON object on its standard input. Hooks do not run during previews.
+ */
+export interface ResourceHook {
+    /**
+     * An optional name for the hook, used in diagnostics.
+     */
+    name?: string;
+
+    /**
+     * The events at which the hook runs. Because a deleted resource is no longer registered by its program, hooks
+     * that run when resources are deleted must be configured for the stack instead.
+     */
+    events: ("before-create" | "after-create" | "before-update" | "after-update")[];
+
+    /**
+     * The command to run and its arguments.
+     */
+    command: string[];
+
+    /**
+     * When set to true, the hook's failures are reported as warnings rather than stopping the resource's operation.
+     */
+    advisory?: boolean;
+
+    /**
+     * How long the command may run, e.g. "30s". If unset, the engine's default timeout applies.
+     */
+    timeout?: string;
 }
 
 /**
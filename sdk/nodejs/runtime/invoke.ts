@@ -85,6 +85,12 @@ export async function invoke(tok: string, props: Inputs, opts?: InvokeOptions):
             throw new Error(`Invoke of '${tok}' failed: ${failures[0].reason} (${failures[0].property})`);
         }
 
+        // If the engine could not perform the invoke because some of its arguments were unknown, then so is its
+        // result.
+        if (resp.getUnknown()) {
+            return undefined;
+        }
+
         // Finally propagate any other properties that were given to us as outputs.
         return deserializeProperties(resp.getReturn());
     }
@@ -142,6 +142,30 @@ export function registerResource(res: Resource, t: string, name: string, custom:
         req.setProvider(resop.providerRef);
         req.setDependenciesList(Array.from(resop.allDirectDependencyURNs));
         req.setDeletebeforereplace((<any>opts).deleteBeforeReplace || false);
+        req.setRetainondelete((<any>opts).retainOnDelete || false);
+        req.setAliasesList(opts.aliases || []);
+        req.setIgnorechangesList(opts.ignoreChanges || []);
+        req.setReplaceonchangesList(opts.replaceOnChanges || []);
+        req.setReadyconditionsList((<any>opts).readyConditions || []);
+
+        const customTimeouts = (<any>opts).customTimeouts;
+        if (customTimeouts) {
+            const timeouts = new resproto.RegisterResourceRequest.CustomTimeouts();
+            timeouts.setCreate(customTimeouts.create || "");
+            timeouts.setUpdate(customTimeouts.update || "");
+            timeouts.setDelete(customTimeouts.delete || "");
+            req.setCustomtimeouts(timeouts);
+        }
+
+        for (const hook of opts.hooks || []) {
+            const h = new resproto.RegisterResourceRequest.Hook();
+            h.setName(hook.name || "");
+            h.setEventsList(hook.events);
+            h.setCommandList(hook.command);
+            h.setAdvisory(hook.advisory || false);
+            h.setTimeout(hook.timeout || "");
+            req.addHooks(h);
+        }
 
         const propertyDependencies = req.getPropertydependenciesMap();
         for (const [key, resourceURNs] of resop.propertyToDirectDependencyURNs) {
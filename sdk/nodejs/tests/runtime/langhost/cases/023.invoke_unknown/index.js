@@ -0,0 +1,16 @@
+// Test that invokes whose arguments are unknown resolve to an unknown result.
+
+let assert = require("assert");
+let pulumi = require("../../../../../");
+
+class MyResource extends pulumi.CustomResource {
+	constructor(name, args, opts) {
+		super("test:index:MyResource", name, args, opts);
+	}
+}
+
+let result = pulumi.runtime.invoke("invoke:index:echo", { a: "hello" });
+result.then((v) => {
+    assert.strictEqual(v, undefined);
+    new MyResource("testResource1");
+});
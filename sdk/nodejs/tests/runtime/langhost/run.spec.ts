@@ -44,7 +44,7 @@ interface RunCase {
     };
     skipRootResourceEndpoints?: boolean;
     showRootResourceRegistration?: boolean;
-    invoke?: (ctx: any, tok: string, args: any) => { failures: any, ret: any };
+    invoke?: (ctx: any, tok: string, args: any) => { failures: any, ret: any, unknown?: boolean };
     readResource?: (ctx: any, t: string, name: string, id: string, par: string, state: any) => {
         urn: URN | undefined, props: any | undefined };
     registerResource?: (ctx: any, dryrun: boolean, t: string, name: string, res: any, dependencies?: string[],
@@ -612,6 +612,20 @@ describe("rpc", () => {
                 return { urn: makeUrn(t, name), id: undefined, props: undefined };
             },
         },
+        // Test that an invoke the engine reports as unknown resolves to undefined.
+        "invoke_unknown": {
+            program: path.join(base, "023.invoke_unknown"),
+            expectResourceCount: 1,
+            invoke: (ctx: any, tok: string, args: any) => {
+                assert.strictEqual(tok, "invoke:index:echo");
+                return { failures: undefined, ret: {}, unknown: true };
+            },
+            registerResource: (ctx: any, dryrun: boolean, t: string, name: string) => {
+                assert.strictEqual(t, "test:index:MyResource");
+                assert.strictEqual(name, "testResource1");
+                return { urn: makeUrn(t, name), id: undefined, props: undefined };
+            },
+        },
     };
 
     for (const casename of Object.keys(cases)) {
@@ -638,10 +652,11 @@ describe("rpc", () => {
                         if (opts.invoke) {
                             const req: any = call.request;
                             const args: any = req.getArgs().toJavaScript();
-                            const { failures, ret } =
+                            const { failures, ret, unknown } =
                                 opts.invoke(ctx, req.getTok(), args);
                             resp.setFailuresList(failures);
                             resp.setReturn(gstruct.Struct.fromJavaScript(ret));
+                            resp.setUnknown(!!unknown);
                         }
                         callback(undefined, resp);
                     },
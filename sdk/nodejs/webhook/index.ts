@@ -0,0 +1,57 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import * as http from "../http";
+import { Input, Output } from "../output";
+import { CustomResource, CustomResourceOptions } from "../resource";
+
+/**
+ * Sends an HTTP request from the Pulumi engine when it is created and whenever its inputs change, e.g. to notify an
+ * external system of a deployment. The status, headers, and body of the response to the last request are available
+ * via the `response` property. The request's headers and the response's body are recorded as secrets.
+ */
+export class Call extends CustomResource {
+    /**
+     * The response to the last request.
+     */
+    public readonly response: Output<http.Response>;
+
+    /**
+     * Create a Call resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the webhook call.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args: CallArgs, opts?: CustomResourceOptions) {
+        super("pulumi:webhook:Call", name, {
+            url: args.url,
+            method: args.method,
+            headers: args.headers,
+            body: args.body,
+            triggers: args.triggers,
+            response: undefined,
+        }, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a webhook Call resource. The request's method defaults to POST.
+ */
+export interface CallArgs extends http.RequestArgs {
+    /**
+     * Arbitrary values whose changes cause the request to be sent again.
+     */
+    readonly triggers?: Input<{[key: string]: any}>;
+}
@@ -51,6 +51,79 @@ func (DiffResponse_DiffChanges) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_provider_90e24a988a8884a7, []int{8, 0}
 }
 
+type PropertyDiff_Kind int32
+
+const (
+	PropertyDiff_ADD            PropertyDiff_Kind = 0
+	PropertyDiff_ADD_REPLACE    PropertyDiff_Kind = 1
+	PropertyDiff_DELETE         PropertyDiff_Kind = 2
+	PropertyDiff_DELETE_REPLACE PropertyDiff_Kind = 3
+	PropertyDiff_UPDATE         PropertyDiff_Kind = 4
+	PropertyDiff_UPDATE_REPLACE PropertyDiff_Kind = 5
+)
+
+var PropertyDiff_Kind_name = map[int32]string{
+	0: "ADD",
+	1: "ADD_REPLACE",
+	2: "DELETE",
+	3: "DELETE_REPLACE",
+	4: "UPDATE",
+	5: "UPDATE_REPLACE",
+}
+var PropertyDiff_Kind_value = map[string]int32{
+	"ADD":            0,
+	"ADD_REPLACE":    1,
+	"DELETE":         2,
+	"DELETE_REPLACE": 3,
+	"UPDATE":         4,
+	"UPDATE_REPLACE": 5,
+}
+
+func (x PropertyDiff_Kind) String() string {
+	return proto.EnumName(PropertyDiff_Kind_name, int32(x))
+}
+func (PropertyDiff_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{17, 0}
+}
+
+type ErrorProviderFailure_Class int32
+
+const (
+	ErrorProviderFailure_UNKNOWN   ErrorProviderFailure_Class = 0
+	ErrorProviderFailure_RETRYABLE ErrorProviderFailure_Class = 1
+	ErrorProviderFailure_THROTTLED ErrorProviderFailure_Class = 2
+	ErrorProviderFailure_CONFLICT  ErrorProviderFailure_Class = 3
+	ErrorProviderFailure_NOT_FOUND ErrorProviderFailure_Class = 4
+	ErrorProviderFailure_AUTH      ErrorProviderFailure_Class = 5
+	ErrorProviderFailure_INVALID   ErrorProviderFailure_Class = 6
+)
+
+var ErrorProviderFailure_Class_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "RETRYABLE",
+	2: "THROTTLED",
+	3: "CONFLICT",
+	4: "NOT_FOUND",
+	5: "AUTH",
+	6: "INVALID",
+}
+var ErrorProviderFailure_Class_value = map[string]int32{
+	"UNKNOWN":   0,
+	"RETRYABLE": 1,
+	"THROTTLED": 2,
+	"CONFLICT":  3,
+	"NOT_FOUND": 4,
+	"AUTH":      5,
+	"INVALID":   6,
+}
+
+func (x ErrorProviderFailure_Class) String() string {
+	return proto.EnumName(ErrorProviderFailure_Class_name, int32(x))
+}
+func (ErrorProviderFailure_Class) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{25, 0}
+}
+
 type ConfigureRequest struct {
 	Variables            map[string]string `protobuf:"bytes,1,rep,name=variables" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Args                 *_struct.Struct   `protobuf:"bytes,2,opt,name=args" json:"args,omitempty"`
@@ -239,6 +312,7 @@ func (m *InvokeRequest) GetProvider() string {
 type InvokeResponse struct {
 	Return               *_struct.Struct `protobuf:"bytes,1,opt,name=return" json:"return,omitempty"`
 	Failures             []*CheckFailure `protobuf:"bytes,2,rep,name=failures" json:"failures,omitempty"`
+	Unknown              bool            `protobuf:"varint,3,opt,name=unknown" json:"unknown,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -282,6 +356,13 @@ func (m *InvokeResponse) GetFailures() []*CheckFailure {
 	return nil
 }
 
+func (m *InvokeResponse) GetUnknown() bool {
+	if m != nil {
+		return m.Unknown
+	}
+	return false
+}
+
 type CheckRequest struct {
 	Urn                  string          `protobuf:"bytes,1,opt,name=urn" json:"urn,omitempty"`
 	Olds                 *_struct.Struct `protobuf:"bytes,2,opt,name=olds" json:"olds,omitempty"`
@@ -339,6 +420,7 @@ func (m *CheckRequest) GetNews() *_struct.Struct {
 type CheckResponse struct {
 	Inputs               *_struct.Struct `protobuf:"bytes,1,opt,name=inputs" json:"inputs,omitempty"`
 	Failures             []*CheckFailure `protobuf:"bytes,2,rep,name=failures" json:"failures,omitempty"`
+	PreviewOutputs       *_struct.Struct `protobuf:"bytes,3,opt,name=previewOutputs" json:"previewOutputs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -382,6 +464,13 @@ func (m *CheckResponse) GetFailures() []*CheckFailure {
 	return nil
 }
 
+func (m *CheckResponse) GetPreviewOutputs() *_struct.Struct {
+	if m != nil {
+		return m.PreviewOutputs
+	}
+	return nil
+}
+
 type CheckFailure struct {
 	Property             string   `protobuf:"bytes,1,opt,name=property" json:"property,omitempty"`
 	Reason               string   `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
@@ -433,6 +522,7 @@ type DiffRequest struct {
 	Urn                  string          `protobuf:"bytes,2,opt,name=urn" json:"urn,omitempty"`
 	Olds                 *_struct.Struct `protobuf:"bytes,3,opt,name=olds" json:"olds,omitempty"`
 	News                 *_struct.Struct `protobuf:"bytes,4,opt,name=news" json:"news,omitempty"`
+	OldInputs            *_struct.Struct `protobuf:"bytes,5,opt,name=oldInputs" json:"oldInputs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -490,12 +580,22 @@ func (m *DiffRequest) GetNews() *_struct.Struct {
 	return nil
 }
 
+func (m *DiffRequest) GetOldInputs() *_struct.Struct {
+	if m != nil {
+		return m.OldInputs
+	}
+	return nil
+}
+
 type DiffResponse struct {
 	Replaces             []string                 `protobuf:"bytes,1,rep,name=replaces" json:"replaces,omitempty"`
 	Stables              []string                 `protobuf:"bytes,2,rep,name=stables" json:"stables,omitempty"`
 	DeleteBeforeReplace  bool                     `protobuf:"varint,3,opt,name=deleteBeforeReplace" json:"deleteBeforeReplace,omitempty"`
 	Changes              DiffResponse_DiffChanges `protobuf:"varint,4,opt,name=changes,enum=pulumirpc.DiffResponse_DiffChanges" json:"changes,omitempty"`
 	Diffs                []string                 `protobuf:"bytes,5,rep,name=diffs" json:"diffs,omitempty"`
+	DetailedDiff         map[string]*PropertyDiff `protobuf:"bytes,6,rep,name=detailedDiff" json:"detailedDiff,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	HasDetailedDiff      bool                     `protobuf:"varint,7,opt,name=hasDetailedDiff" json:"hasDetailedDiff,omitempty"`
+	PreviewOutputs       *_struct.Struct          `protobuf:"bytes,8,opt,name=previewOutputs" json:"previewOutputs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
 	XXX_unrecognized     []byte                   `json:"-"`
 	XXX_sizecache        int32                    `json:"-"`
@@ -560,9 +660,31 @@ func (m *DiffResponse) GetDiffs() []string {
 	return nil
 }
 
+func (m *DiffResponse) GetDetailedDiff() map[string]*PropertyDiff {
+	if m != nil {
+		return m.DetailedDiff
+	}
+	return nil
+}
+
+func (m *DiffResponse) GetHasDetailedDiff() bool {
+	if m != nil {
+		return m.HasDetailedDiff
+	}
+	return false
+}
+
+func (m *DiffResponse) GetPreviewOutputs() *_struct.Struct {
+	if m != nil {
+		return m.PreviewOutputs
+	}
+	return nil
+}
+
 type CreateRequest struct {
 	Urn                  string          `protobuf:"bytes,1,opt,name=urn" json:"urn,omitempty"`
 	Properties           *_struct.Struct `protobuf:"bytes,2,opt,name=properties" json:"properties,omitempty"`
+	Timeout              float64         `protobuf:"fixed64,3,opt,name=timeout" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -606,6 +728,13 @@ func (m *CreateRequest) GetProperties() *_struct.Struct {
 	return nil
 }
 
+func (m *CreateRequest) GetTimeout() float64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 type CreateResponse struct {
 	Id                   string          `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	Properties           *_struct.Struct `protobuf:"bytes,2,opt,name=properties" json:"properties,omitempty"`
@@ -765,6 +894,8 @@ type UpdateRequest struct {
 	Urn                  string          `protobuf:"bytes,2,opt,name=urn" json:"urn,omitempty"`
 	Olds                 *_struct.Struct `protobuf:"bytes,3,opt,name=olds" json:"olds,omitempty"`
 	News                 *_struct.Struct `protobuf:"bytes,4,opt,name=news" json:"news,omitempty"`
+	OldInputs            *_struct.Struct `protobuf:"bytes,5,opt,name=oldInputs" json:"oldInputs,omitempty"`
+	Timeout              float64         `protobuf:"fixed64,6,opt,name=timeout" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -822,6 +953,20 @@ func (m *UpdateRequest) GetNews() *_struct.Struct {
 	return nil
 }
 
+func (m *UpdateRequest) GetOldInputs() *_struct.Struct {
+	if m != nil {
+		return m.OldInputs
+	}
+	return nil
+}
+
+func (m *UpdateRequest) GetTimeout() float64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 type UpdateResponse struct {
 	Properties           *_struct.Struct `protobuf:"bytes,1,opt,name=properties" json:"properties,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
@@ -864,6 +1009,7 @@ type DeleteRequest struct {
 	Id                   string          `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	Urn                  string          `protobuf:"bytes,2,opt,name=urn" json:"urn,omitempty"`
 	Properties           *_struct.Struct `protobuf:"bytes,3,opt,name=properties" json:"properties,omitempty"`
+	Timeout              float64         `protobuf:"fixed64,4,opt,name=timeout" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -914,6 +1060,13 @@ func (m *DeleteRequest) GetProperties() *_struct.Struct {
 	return nil
 }
 
+func (m *DeleteRequest) GetTimeout() float64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 // ErrorResourceInitFailed is sent as a Detail `ResourceProvider.{Create, Update}` fail because a
 // resource was created successfully, but failed to initialize.
 type ErrorResourceInitFailed struct {
@@ -978,6 +1131,528 @@ func (m *ErrorResourceInitFailed) GetInputs() *_struct.Struct {
 	return nil
 }
 
+type PropertyDiff struct {
+	Kind                 PropertyDiff_Kind `protobuf:"varint,1,opt,name=kind,enum=pulumirpc.PropertyDiff_Kind" json:"kind,omitempty"`
+	InputDiff            bool              `protobuf:"varint,2,opt,name=inputDiff" json:"inputDiff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PropertyDiff) Reset()         { *m = PropertyDiff{} }
+func (m *PropertyDiff) String() string { return proto.CompactTextString(m) }
+func (*PropertyDiff) ProtoMessage()    {}
+func (*PropertyDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{17}
+}
+func (m *PropertyDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PropertyDiff.Unmarshal(m, b)
+}
+func (m *PropertyDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PropertyDiff.Marshal(b, m, deterministic)
+}
+func (dst *PropertyDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PropertyDiff.Merge(dst, src)
+}
+func (m *PropertyDiff) XXX_Size() int {
+	return xxx_messageInfo_PropertyDiff.Size(m)
+}
+func (m *PropertyDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_PropertyDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PropertyDiff proto.InternalMessageInfo
+
+func (m *PropertyDiff) GetKind() PropertyDiff_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return PropertyDiff_ADD
+}
+
+func (m *PropertyDiff) GetInputDiff() bool {
+	if m != nil {
+		return m.InputDiff
+	}
+	return false
+}
+
+type ReadBatchRequest struct {
+	Requests             []*ReadRequest `protobuf:"bytes,1,rep,name=requests" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ReadBatchRequest) Reset()         { *m = ReadBatchRequest{} }
+func (m *ReadBatchRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadBatchRequest) ProtoMessage()    {}
+func (*ReadBatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{18}
+}
+func (m *ReadBatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadBatchRequest.Unmarshal(m, b)
+}
+func (m *ReadBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadBatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReadBatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadBatchRequest.Merge(dst, src)
+}
+func (m *ReadBatchRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadBatchRequest.Size(m)
+}
+func (m *ReadBatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadBatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadBatchRequest proto.InternalMessageInfo
+
+func (m *ReadBatchRequest) GetRequests() []*ReadRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+type ReadBatchResponse struct {
+	Results              []*ReadBatchResponse_Result `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *ReadBatchResponse) Reset()         { *m = ReadBatchResponse{} }
+func (m *ReadBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadBatchResponse) ProtoMessage()    {}
+func (*ReadBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{19}
+}
+func (m *ReadBatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadBatchResponse.Unmarshal(m, b)
+}
+func (m *ReadBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadBatchResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReadBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadBatchResponse.Merge(dst, src)
+}
+func (m *ReadBatchResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadBatchResponse.Size(m)
+}
+func (m *ReadBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadBatchResponse proto.InternalMessageInfo
+
+func (m *ReadBatchResponse) GetResults() []*ReadBatchResponse_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type ReadBatchResponse_Result struct {
+	Response             *ReadResponse            `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	Error                string                   `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+	InitFailed           *ErrorResourceInitFailed `protobuf:"bytes,3,opt,name=initFailed" json:"initFailed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ReadBatchResponse_Result) Reset()         { *m = ReadBatchResponse_Result{} }
+func (m *ReadBatchResponse_Result) String() string { return proto.CompactTextString(m) }
+func (*ReadBatchResponse_Result) ProtoMessage()    {}
+func (*ReadBatchResponse_Result) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{19, 0}
+}
+func (m *ReadBatchResponse_Result) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadBatchResponse_Result.Unmarshal(m, b)
+}
+func (m *ReadBatchResponse_Result) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadBatchResponse_Result.Marshal(b, m, deterministic)
+}
+func (dst *ReadBatchResponse_Result) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadBatchResponse_Result.Merge(dst, src)
+}
+func (m *ReadBatchResponse_Result) XXX_Size() int {
+	return xxx_messageInfo_ReadBatchResponse_Result.Size(m)
+}
+func (m *ReadBatchResponse_Result) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadBatchResponse_Result.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadBatchResponse_Result proto.InternalMessageInfo
+
+func (m *ReadBatchResponse_Result) GetResponse() *ReadResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *ReadBatchResponse_Result) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ReadBatchResponse_Result) GetInitFailed() *ErrorResourceInitFailed {
+	if m != nil {
+		return m.InitFailed
+	}
+	return nil
+}
+
+type DiffBatchRequest struct {
+	Requests             []*DiffRequest `protobuf:"bytes,1,rep,name=requests" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *DiffBatchRequest) Reset()         { *m = DiffBatchRequest{} }
+func (m *DiffBatchRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffBatchRequest) ProtoMessage()    {}
+func (*DiffBatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{20}
+}
+func (m *DiffBatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffBatchRequest.Unmarshal(m, b)
+}
+func (m *DiffBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffBatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *DiffBatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffBatchRequest.Merge(dst, src)
+}
+func (m *DiffBatchRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffBatchRequest.Size(m)
+}
+func (m *DiffBatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffBatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffBatchRequest proto.InternalMessageInfo
+
+func (m *DiffBatchRequest) GetRequests() []*DiffRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+type DiffBatchResponse struct {
+	Results              []*DiffBatchResponse_Result `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *DiffBatchResponse) Reset()         { *m = DiffBatchResponse{} }
+func (m *DiffBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*DiffBatchResponse) ProtoMessage()    {}
+func (*DiffBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{21}
+}
+func (m *DiffBatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffBatchResponse.Unmarshal(m, b)
+}
+func (m *DiffBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffBatchResponse.Marshal(b, m, deterministic)
+}
+func (dst *DiffBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffBatchResponse.Merge(dst, src)
+}
+func (m *DiffBatchResponse) XXX_Size() int {
+	return xxx_messageInfo_DiffBatchResponse.Size(m)
+}
+func (m *DiffBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffBatchResponse proto.InternalMessageInfo
+
+func (m *DiffBatchResponse) GetResults() []*DiffBatchResponse_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type DiffBatchResponse_Result struct {
+	Response             *DiffResponse `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	Error                string        `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *DiffBatchResponse_Result) Reset()         { *m = DiffBatchResponse_Result{} }
+func (m *DiffBatchResponse_Result) String() string { return proto.CompactTextString(m) }
+func (*DiffBatchResponse_Result) ProtoMessage()    {}
+func (*DiffBatchResponse_Result) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{21, 0}
+}
+func (m *DiffBatchResponse_Result) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffBatchResponse_Result.Unmarshal(m, b)
+}
+func (m *DiffBatchResponse_Result) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffBatchResponse_Result.Marshal(b, m, deterministic)
+}
+func (dst *DiffBatchResponse_Result) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffBatchResponse_Result.Merge(dst, src)
+}
+func (m *DiffBatchResponse_Result) XXX_Size() int {
+	return xxx_messageInfo_DiffBatchResponse_Result.Size(m)
+}
+func (m *DiffBatchResponse_Result) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffBatchResponse_Result.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffBatchResponse_Result proto.InternalMessageInfo
+
+func (m *DiffBatchResponse_Result) GetResponse() *DiffResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *DiffBatchResponse_Result) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ProviderCapabilities struct {
+	AcceptSecrets        bool     `protobuf:"varint,1,opt,name=acceptSecrets" json:"acceptSecrets,omitempty"`
+	TypedUnknowns        bool     `protobuf:"varint,2,opt,name=typedUnknowns" json:"typedUnknowns,omitempty"`
+	DetailedDiff         bool     `protobuf:"varint,3,opt,name=detailedDiff" json:"detailedDiff,omitempty"`
+	Read                 bool     `protobuf:"varint,4,opt,name=read" json:"read,omitempty"`
+	Batch                bool     `protobuf:"varint,5,opt,name=batch" json:"batch,omitempty"`
+	Int64Values          bool     `protobuf:"varint,6,opt,name=int64Values" json:"int64Values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProviderCapabilities) Reset()         { *m = ProviderCapabilities{} }
+func (m *ProviderCapabilities) String() string { return proto.CompactTextString(m) }
+func (*ProviderCapabilities) ProtoMessage()    {}
+func (*ProviderCapabilities) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{22}
+}
+func (m *ProviderCapabilities) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProviderCapabilities.Unmarshal(m, b)
+}
+func (m *ProviderCapabilities) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProviderCapabilities.Marshal(b, m, deterministic)
+}
+func (dst *ProviderCapabilities) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProviderCapabilities.Merge(dst, src)
+}
+func (m *ProviderCapabilities) XXX_Size() int {
+	return xxx_messageInfo_ProviderCapabilities.Size(m)
+}
+func (m *ProviderCapabilities) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProviderCapabilities.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProviderCapabilities proto.InternalMessageInfo
+
+func (m *ProviderCapabilities) GetAcceptSecrets() bool {
+	if m != nil {
+		return m.AcceptSecrets
+	}
+	return false
+}
+
+func (m *ProviderCapabilities) GetTypedUnknowns() bool {
+	if m != nil {
+		return m.TypedUnknowns
+	}
+	return false
+}
+
+func (m *ProviderCapabilities) GetDetailedDiff() bool {
+	if m != nil {
+		return m.DetailedDiff
+	}
+	return false
+}
+
+func (m *ProviderCapabilities) GetRead() bool {
+	if m != nil {
+		return m.Read
+	}
+	return false
+}
+
+func (m *ProviderCapabilities) GetBatch() bool {
+	if m != nil {
+		return m.Batch
+	}
+	return false
+}
+
+func (m *ProviderCapabilities) GetInt64Values() bool {
+	if m != nil {
+		return m.Int64Values
+	}
+	return false
+}
+
+type GetSchemaRequest struct {
+	Version              int32    `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSchemaRequest) Reset()         { *m = GetSchemaRequest{} }
+func (m *GetSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSchemaRequest) ProtoMessage()    {}
+func (*GetSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{23}
+}
+func (m *GetSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSchemaRequest.Unmarshal(m, b)
+}
+func (m *GetSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSchemaRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSchemaRequest.Merge(dst, src)
+}
+func (m *GetSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSchemaRequest.Size(m)
+}
+func (m *GetSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSchemaRequest proto.InternalMessageInfo
+
+func (m *GetSchemaRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type GetSchemaResponse struct {
+	Schema               string   `protobuf:"bytes,1,opt,name=schema" json:"schema,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSchemaResponse) Reset()         { *m = GetSchemaResponse{} }
+func (m *GetSchemaResponse) String() string { return proto.CompactTextString(m) }
+func (*GetSchemaResponse) ProtoMessage()    {}
+func (*GetSchemaResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{24}
+}
+func (m *GetSchemaResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSchemaResponse.Unmarshal(m, b)
+}
+func (m *GetSchemaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSchemaResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetSchemaResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSchemaResponse.Merge(dst, src)
+}
+func (m *GetSchemaResponse) XXX_Size() int {
+	return xxx_messageInfo_GetSchemaResponse.Size(m)
+}
+func (m *GetSchemaResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSchemaResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSchemaResponse proto.InternalMessageInfo
+
+func (m *GetSchemaResponse) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
+type ErrorProviderFailure struct {
+	Class                ErrorProviderFailure_Class `protobuf:"varint,1,opt,name=class,enum=pulumirpc.ErrorProviderFailure_Class" json:"class,omitempty"`
+	Code                 string                     `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+	Retryable            bool                       `protobuf:"varint,3,opt,name=retryable" json:"retryable,omitempty"`
+	RetryAfter           float64                    `protobuf:"fixed64,4,opt,name=retryAfter" json:"retryAfter,omitempty"`
+	Remediation          string                     `protobuf:"bytes,5,opt,name=remediation" json:"remediation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *ErrorProviderFailure) Reset()         { *m = ErrorProviderFailure{} }
+func (m *ErrorProviderFailure) String() string { return proto.CompactTextString(m) }
+func (*ErrorProviderFailure) ProtoMessage()    {}
+func (*ErrorProviderFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_provider_90e24a988a8884a7, []int{25}
+}
+func (m *ErrorProviderFailure) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorProviderFailure.Unmarshal(m, b)
+}
+func (m *ErrorProviderFailure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorProviderFailure.Marshal(b, m, deterministic)
+}
+func (dst *ErrorProviderFailure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorProviderFailure.Merge(dst, src)
+}
+func (m *ErrorProviderFailure) XXX_Size() int {
+	return xxx_messageInfo_ErrorProviderFailure.Size(m)
+}
+func (m *ErrorProviderFailure) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorProviderFailure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorProviderFailure proto.InternalMessageInfo
+
+func (m *ErrorProviderFailure) GetClass() ErrorProviderFailure_Class {
+	if m != nil {
+		return m.Class
+	}
+	return ErrorProviderFailure_UNKNOWN
+}
+
+func (m *ErrorProviderFailure) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *ErrorProviderFailure) GetRetryable() bool {
+	if m != nil {
+		return m.Retryable
+	}
+	return false
+}
+
+func (m *ErrorProviderFailure) GetRetryAfter() float64 {
+	if m != nil {
+		return m.RetryAfter
+	}
+	return 0
+}
+
+func (m *ErrorProviderFailure) GetRemediation() string {
+	if m != nil {
+		return m.Remediation
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ConfigureRequest)(nil), "pulumirpc.ConfigureRequest")
 	proto.RegisterMapType((map[string]string)(nil), "pulumirpc.ConfigureRequest.VariablesEntry")
@@ -990,6 +1665,7 @@ func init() {
 	proto.RegisterType((*CheckFailure)(nil), "pulumirpc.CheckFailure")
 	proto.RegisterType((*DiffRequest)(nil), "pulumirpc.DiffRequest")
 	proto.RegisterType((*DiffResponse)(nil), "pulumirpc.DiffResponse")
+	proto.RegisterMapType((map[string]*PropertyDiff)(nil), "pulumirpc.DiffResponse.DetailedDiffEntry")
 	proto.RegisterType((*CreateRequest)(nil), "pulumirpc.CreateRequest")
 	proto.RegisterType((*CreateResponse)(nil), "pulumirpc.CreateResponse")
 	proto.RegisterType((*ReadRequest)(nil), "pulumirpc.ReadRequest")
@@ -998,7 +1674,20 @@ func init() {
 	proto.RegisterType((*UpdateResponse)(nil), "pulumirpc.UpdateResponse")
 	proto.RegisterType((*DeleteRequest)(nil), "pulumirpc.DeleteRequest")
 	proto.RegisterType((*ErrorResourceInitFailed)(nil), "pulumirpc.ErrorResourceInitFailed")
+	proto.RegisterType((*PropertyDiff)(nil), "pulumirpc.PropertyDiff")
+	proto.RegisterType((*ReadBatchRequest)(nil), "pulumirpc.ReadBatchRequest")
+	proto.RegisterType((*ReadBatchResponse)(nil), "pulumirpc.ReadBatchResponse")
+	proto.RegisterType((*ReadBatchResponse_Result)(nil), "pulumirpc.ReadBatchResponse.Result")
+	proto.RegisterType((*DiffBatchRequest)(nil), "pulumirpc.DiffBatchRequest")
+	proto.RegisterType((*DiffBatchResponse)(nil), "pulumirpc.DiffBatchResponse")
+	proto.RegisterType((*DiffBatchResponse_Result)(nil), "pulumirpc.DiffBatchResponse.Result")
+	proto.RegisterType((*ProviderCapabilities)(nil), "pulumirpc.ProviderCapabilities")
+	proto.RegisterType((*GetSchemaRequest)(nil), "pulumirpc.GetSchemaRequest")
+	proto.RegisterType((*GetSchemaResponse)(nil), "pulumirpc.GetSchemaResponse")
+	proto.RegisterType((*ErrorProviderFailure)(nil), "pulumirpc.ErrorProviderFailure")
 	proto.RegisterEnum("pulumirpc.DiffResponse_DiffChanges", DiffResponse_DiffChanges_name, DiffResponse_DiffChanges_value)
+	proto.RegisterEnum("pulumirpc.PropertyDiff_Kind", PropertyDiff_Kind_name, PropertyDiff_Kind_value)
+	proto.RegisterEnum("pulumirpc.ErrorProviderFailure_Class", ErrorProviderFailure_Class_name, ErrorProviderFailure_Class_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1038,10 +1727,22 @@ type ResourceProviderClient interface {
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	// Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed to still exist.
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ReadBatch reads the current live state of many resources in a single call, e.g. during a refresh.  Providers
+	// that do not implement it return UNIMPLEMENTED, and the engine falls back to calling Read for each resource.
+	ReadBatch(ctx context.Context, in *ReadBatchRequest, opts ...grpc.CallOption) (*ReadBatchResponse, error)
+	// DiffBatch checks what impacts hypothetical updates will have on many resources in a single call.  Providers
+	// that do not implement it return UNIMPLEMENTED, and the engine falls back to calling Diff for each resource.
+	DiffBatch(ctx context.Context, in *DiffBatchRequest, opts ...grpc.CallOption) (*DiffBatchResponse, error)
 	// Cancel signals the provider to abort all outstanding resource operations.
 	Cancel(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
 	// GetPluginInfo returns generic information about this plugin, like its version.
 	GetPluginInfo(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PluginInfo, error)
+	// GetCapabilities returns the optional features that this provider supports, so that the engine can adapt to
+	// them.  Providers that do not implement it return UNIMPLEMENTED, and the engine assumes a default set.
+	GetCapabilities(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ProviderCapabilities, error)
+	// GetSchema returns a machine-readable description of the resource types that this provider manages and of their
+	// properties, from which clients can generate code.  Providers that do not implement it return UNIMPLEMENTED.
+	GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaResponse, error)
 }
 
 type resourceProviderClient struct {
@@ -1142,6 +1843,24 @@ func (c *resourceProviderClient) Delete(ctx context.Context, in *DeleteRequest,
 	return out, nil
 }
 
+func (c *resourceProviderClient) ReadBatch(ctx context.Context, in *ReadBatchRequest, opts ...grpc.CallOption) (*ReadBatchResponse, error) {
+	out := new(ReadBatchResponse)
+	err := grpc.Invoke(ctx, "/pulumirpc.ResourceProvider/ReadBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceProviderClient) DiffBatch(ctx context.Context, in *DiffBatchRequest, opts ...grpc.CallOption) (*DiffBatchResponse, error) {
+	out := new(DiffBatchResponse)
+	err := grpc.Invoke(ctx, "/pulumirpc.ResourceProvider/DiffBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *resourceProviderClient) Cancel(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := grpc.Invoke(ctx, "/pulumirpc.ResourceProvider/Cancel", in, out, c.cc, opts...)
@@ -1160,6 +1879,24 @@ func (c *resourceProviderClient) GetPluginInfo(ctx context.Context, in *empty.Em
 	return out, nil
 }
 
+func (c *resourceProviderClient) GetCapabilities(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ProviderCapabilities, error) {
+	out := new(ProviderCapabilities)
+	err := grpc.Invoke(ctx, "/pulumirpc.ResourceProvider/GetCapabilities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceProviderClient) GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaResponse, error) {
+	out := new(GetSchemaResponse)
+	err := grpc.Invoke(ctx, "/pulumirpc.ResourceProvider/GetSchema", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for ResourceProvider service
 
 type ResourceProviderServer interface {
@@ -1189,10 +1926,22 @@ type ResourceProviderServer interface {
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	// Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed to still exist.
 	Delete(context.Context, *DeleteRequest) (*empty.Empty, error)
+	// ReadBatch reads the current live state of many resources in a single call, e.g. during a refresh.  Providers
+	// that do not implement it return UNIMPLEMENTED, and the engine falls back to calling Read for each resource.
+	ReadBatch(context.Context, *ReadBatchRequest) (*ReadBatchResponse, error)
+	// DiffBatch checks what impacts hypothetical updates will have on many resources in a single call.  Providers
+	// that do not implement it return UNIMPLEMENTED, and the engine falls back to calling Diff for each resource.
+	DiffBatch(context.Context, *DiffBatchRequest) (*DiffBatchResponse, error)
 	// Cancel signals the provider to abort all outstanding resource operations.
 	Cancel(context.Context, *empty.Empty) (*empty.Empty, error)
 	// GetPluginInfo returns generic information about this plugin, like its version.
 	GetPluginInfo(context.Context, *empty.Empty) (*PluginInfo, error)
+	// GetCapabilities returns the optional features that this provider supports, so that the engine can adapt to
+	// them.  Providers that do not implement it return UNIMPLEMENTED, and the engine assumes a default set.
+	GetCapabilities(context.Context, *empty.Empty) (*ProviderCapabilities, error)
+	// GetSchema returns a machine-readable description of the resource types that this provider manages and of their
+	// properties, from which clients can generate code.  Providers that do not implement it return UNIMPLEMENTED.
+	GetSchema(context.Context, *GetSchemaRequest) (*GetSchemaResponse, error)
 }
 
 func RegisterResourceProviderServer(s *grpc.Server, srv ResourceProviderServer) {
@@ -1379,6 +2128,42 @@ func _ResourceProvider_Delete_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceProvider_ReadBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceProviderServer).ReadBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.ResourceProvider/ReadBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceProviderServer).ReadBatch(ctx, req.(*ReadBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceProvider_DiffBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceProviderServer).DiffBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.ResourceProvider/DiffBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceProviderServer).DiffBatch(ctx, req.(*DiffBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ResourceProvider_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
@@ -1415,6 +2200,42 @@ func _ResourceProvider_GetPluginInfo_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceProvider_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceProviderServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.ResourceProvider/GetCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceProviderServer).GetCapabilities(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceProvider_GetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceProviderServer).GetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.ResourceProvider/GetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceProviderServer).GetSchema(ctx, req.(*GetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ResourceProvider_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pulumirpc.ResourceProvider",
 	HandlerType: (*ResourceProviderServer)(nil),
@@ -1459,6 +2280,14 @@ var _ResourceProvider_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ResourceProvider_Delete_Handler,
 		},
+		{
+			MethodName: "ReadBatch",
+			Handler:    _ResourceProvider_ReadBatch_Handler,
+		},
+		{
+			MethodName: "DiffBatch",
+			Handler:    _ResourceProvider_DiffBatch_Handler,
+		},
 		{
 			MethodName: "Cancel",
 			Handler:    _ResourceProvider_Cancel_Handler,
@@ -1467,6 +2296,14 @@ var _ResourceProvider_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetPluginInfo",
 			Handler:    _ResourceProvider_GetPluginInfo_Handler,
 		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _ResourceProvider_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "GetSchema",
+			Handler:    _ResourceProvider_GetSchema_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "provider.proto",
@@ -1475,64 +2312,109 @@ var _ResourceProvider_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("provider.proto", fileDescriptor_provider_90e24a988a8884a7) }
 
 var fileDescriptor_provider_90e24a988a8884a7 = []byte{
-	// 937 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xae, 0x93, 0x34, 0xdb, 0x9c, 0xfc, 0x28, 0x1a, 0xa0, 0xcd, 0x7a, 0xb9, 0xa8, 0xcc, 0xcd,
-	0x0a, 0x24, 0x07, 0x75, 0x2f, 0x80, 0xd5, 0xae, 0x40, 0xd9, 0xa6, 0x10, 0xad, 0x9a, 0x16, 0x57,
-	0x05, 0xc1, 0x0d, 0x72, 0xed, 0x49, 0x6a, 0xe2, 0xd8, 0x66, 0x3c, 0x0e, 0x0a, 0x4f, 0xc0, 0x8f,
-	0xc4, 0x9b, 0x70, 0xc3, 0x4b, 0xf0, 0x5a, 0x8c, 0x67, 0xc6, 0x8e, 0x27, 0x69, 0xd2, 0x34, 0xaa,
-	0xe0, 0x6e, 0x8e, 0xcf, 0x99, 0x73, 0xbe, 0xef, 0x9c, 0x99, 0xcf, 0x03, 0xad, 0x88, 0x84, 0x33,
-	0xcf, 0xc5, 0xc4, 0x64, 0x0b, 0x1a, 0xa2, 0x5a, 0x94, 0xf8, 0xc9, 0xd4, 0x23, 0x91, 0xa3, 0x37,
-	0x22, 0x3f, 0x19, 0x7b, 0x81, 0x70, 0xe8, 0xcf, 0xc6, 0x61, 0x38, 0xf6, 0x71, 0x97, 0x5b, 0x37,
-	0xc9, 0xa8, 0x8b, 0xa7, 0x11, 0x9d, 0x4b, 0xe7, 0xfb, 0xcb, 0xce, 0x98, 0x92, 0xc4, 0xa1, 0xc2,
-	0x6b, 0xfc, 0xa3, 0x41, 0xfb, 0x4d, 0x18, 0x8c, 0xbc, 0x71, 0x42, 0xb0, 0x85, 0x7f, 0x4a, 0x70,
-	0x4c, 0xd1, 0x57, 0x50, 0x9b, 0xd9, 0xc4, 0xb3, 0x6f, 0x7c, 0x1c, 0x77, 0xb4, 0xe3, 0xf2, 0xf3,
-	0xfa, 0xc9, 0x87, 0x66, 0x5e, 0xdc, 0x5c, 0x8e, 0x37, 0xbf, 0xc9, 0x82, 0xfb, 0x01, 0x25, 0x73,
-	0x6b, 0xb1, 0x19, 0x7d, 0x04, 0x15, 0x9b, 0x8c, 0xe3, 0x4e, 0xe9, 0x58, 0x63, 0x49, 0x8e, 0x4c,
-	0x81, 0xc5, 0xcc, 0xb0, 0x98, 0x57, 0x1c, 0x8b, 0xc5, 0x83, 0xf4, 0x57, 0xd0, 0x52, 0x33, 0xa1,
-	0x36, 0x94, 0x27, 0x78, 0xce, 0x20, 0x68, 0xcf, 0x6b, 0x56, 0xba, 0x44, 0xef, 0xc2, 0xfe, 0xcc,
-	0xf6, 0x13, 0xcc, 0x33, 0xd6, 0x2c, 0x61, 0xbc, 0x2c, 0x7d, 0xaa, 0x19, 0x7f, 0x6b, 0xf0, 0x34,
-	0x47, 0xd6, 0x27, 0x24, 0x24, 0xe7, 0x5e, 0x1c, 0x7b, 0xc1, 0xf8, 0x2d, 0x9e, 0xc7, 0xe8, 0x6b,
-	0xa8, 0x4f, 0x17, 0xa6, 0x24, 0xd5, 0xbd, 0x8b, 0xd4, 0xf2, 0x56, 0x73, 0xb1, 0xb6, 0x8a, 0x39,
-	0xf4, 0x1e, 0xc0, 0xc2, 0x85, 0x10, 0x54, 0x02, 0x7b, 0x8a, 0x25, 0x56, 0xbe, 0x46, 0xc7, 0x50,
-	0x77, 0x71, 0xec, 0x10, 0x2f, 0xa2, 0x5e, 0x18, 0x48, 0xc8, 0xc5, 0x4f, 0xc6, 0x8f, 0xd0, 0x1c,
-	0x04, 0xb3, 0x70, 0x92, 0xb7, 0x9e, 0x31, 0xa6, 0xe1, 0x24, 0x63, 0xcc, 0x96, 0x0f, 0x6a, 0x21,
-	0xd2, 0xe1, 0x20, 0x3b, 0x34, 0x9d, 0x32, 0xcf, 0x91, 0xdb, 0xc6, 0x0c, 0x5a, 0x59, 0xad, 0x38,
-	0x0a, 0x83, 0x18, 0xa3, 0x2e, 0x54, 0x09, 0xa6, 0x09, 0x09, 0x78, 0xbd, 0x0d, 0xc9, 0x65, 0x18,
-	0x7a, 0x01, 0x07, 0x23, 0xdb, 0xf3, 0x59, 0x97, 0x52, 0x3c, 0x65, 0xbe, 0xa5, 0xd0, 0xc2, 0x5b,
-	0xec, 0x4c, 0xce, 0x84, 0xdf, 0xca, 0x03, 0x8d, 0x5f, 0xa0, 0xc1, 0x3d, 0x05, 0x8a, 0x59, 0x49,
-	0x46, 0x31, 0x4d, 0xcb, 0x28, 0x86, 0xbe, 0x7b, 0x3f, 0xc5, 0x34, 0x28, 0x0d, 0x0e, 0xf0, 0xcf,
-	0x31, 0xa7, 0xb7, 0x29, 0x38, 0x0d, 0x32, 0x12, 0x68, 0xca, 0xda, 0x0b, 0xca, 0x5e, 0x10, 0x25,
-	0x34, 0xbe, 0x97, 0xb2, 0x08, 0xdb, 0x8d, 0x72, 0x4f, 0x52, 0x96, 0x1e, 0x39, 0x96, 0x08, 0x13,
-	0x9a, 0x1d, 0xe6, 0xdc, 0x46, 0x87, 0xe9, 0x10, 0xec, 0x38, 0x3f, 0x1f, 0xd2, 0x32, 0x7e, 0xd3,
-	0xa0, 0x7e, 0xea, 0x8d, 0x46, 0x59, 0xdb, 0x5a, 0x50, 0xf2, 0x5c, 0xb9, 0x9b, 0xad, 0xb2, 0x36,
-	0x96, 0x56, 0xdb, 0x58, 0x7e, 0x48, 0x1b, 0x2b, 0xdb, 0xb4, 0xf1, 0xf7, 0x12, 0x34, 0x04, 0x16,
-	0xd9, 0x46, 0x46, 0x88, 0xe0, 0xc8, 0xb7, 0x1d, 0x29, 0x10, 0x8c, 0x50, 0x66, 0xa3, 0x0e, 0x3c,
-	0x89, 0xa9, 0xd0, 0x8e, 0x12, 0x77, 0x65, 0x26, 0xfa, 0x18, 0xde, 0x71, 0xb1, 0x8f, 0x29, 0xee,
-	0xe1, 0x51, 0x98, 0xca, 0x07, 0xdf, 0xc1, 0xf1, 0x1e, 0x58, 0x77, 0xb9, 0xd0, 0x6b, 0x78, 0xe2,
-	0xdc, 0xda, 0xc1, 0x18, 0x0b, 0xa0, 0xad, 0x93, 0x0f, 0x0a, 0xcd, 0x2f, 0x22, 0xe2, 0xc6, 0x1b,
-	0x11, 0x6a, 0x65, 0x7b, 0x52, 0xb5, 0x70, 0xd9, 0xf7, 0xb8, 0xb3, 0xcf, 0x81, 0x08, 0xc3, 0x78,
-	0x2d, 0x1a, 0x2b, 0xa3, 0x59, 0x23, 0x1b, 0xa7, 0x83, 0xb3, 0xb3, 0x1f, 0xae, 0x87, 0x6f, 0x87,
-	0x17, 0xdf, 0x0e, 0xdb, 0x7b, 0xa8, 0x09, 0x35, 0xfe, 0x65, 0x78, 0x31, 0xec, 0xb7, 0xb5, 0xdc,
-	0xbc, 0xba, 0x38, 0xef, 0xb7, 0x4b, 0xc6, 0xf7, 0xec, 0x4c, 0xb1, 0x19, 0x51, 0xbc, 0xfe, 0x40,
-	0x7f, 0x02, 0x20, 0xe7, 0xeb, 0xe1, 0x7b, 0x8f, 0x75, 0x21, 0xd4, 0xf8, 0x0e, 0x5a, 0x59, 0x6e,
-	0xd9, 0xe9, 0xe5, 0xb1, 0xef, 0x9c, 0xfa, 0x16, 0xea, 0x16, 0xb6, 0xdd, 0xed, 0x8f, 0x93, 0x5a,
-	0xa9, 0xbc, 0x7d, 0xa5, 0x5f, 0x35, 0x68, 0x88, 0x52, 0x8f, 0xcc, 0xa1, 0x70, 0x7b, 0xcb, 0x5b,
-	0xdd, 0x5e, 0xe3, 0x0f, 0x0d, 0x9a, 0xd7, 0x91, 0x5b, 0x18, 0xd6, 0xff, 0x79, 0x8d, 0x06, 0xd0,
-	0xca, 0xc0, 0xc8, 0xce, 0xa8, 0x9d, 0xd0, 0xb6, 0xef, 0x31, 0xfb, 0x71, 0x9c, 0xf2, 0xfb, 0xf2,
-	0x1f, 0xcc, 0xf3, 0x2f, 0x0d, 0x8e, 0xf8, 0x5f, 0x91, 0xc1, 0x0e, 0x13, 0xe2, 0xe0, 0x41, 0xe0,
-	0xd1, 0x54, 0xda, 0xb0, 0xfb, 0x78, 0xa3, 0x65, 0xaa, 0x21, 0x84, 0x2f, 0x85, 0xc6, 0x55, 0x43,
-	0x9a, 0x85, 0xa1, 0x57, 0xb6, 0x1a, 0xfa, 0xc9, 0x9f, 0x55, 0x68, 0x67, 0x50, 0x2f, 0xe5, 0xdf,
-	0x0f, 0xf5, 0xa0, 0xce, 0x25, 0x59, 0xfc, 0xe7, 0xd1, 0x8a, 0x88, 0xcb, 0x3e, 0xea, 0x9d, 0x55,
-	0x87, 0x98, 0x95, 0xb1, 0x87, 0x3e, 0x07, 0xe0, 0xc2, 0x21, 0x52, 0x1c, 0xae, 0x48, 0x91, 0xc8,
-	0x70, 0xb4, 0x46, 0xa2, 0x58, 0x82, 0x1e, 0xd4, 0xf2, 0x77, 0x06, 0x7a, 0xb6, 0xe1, 0x49, 0xa5,
-	0x1f, 0xae, 0x90, 0xec, 0xa7, 0x6f, 0x3a, 0x0e, 0xa2, 0x2a, 0x7e, 0xe3, 0xa8, 0x08, 0x55, 0x79,
-	0x45, 0xe8, 0x4f, 0xef, 0xf0, 0xe4, 0x20, 0x5e, 0xc1, 0x3e, 0x27, 0xb6, 0x5b, 0x0f, 0x3e, 0x83,
-	0x4a, 0x4a, 0x6a, 0x17, 0xf6, 0x0c, 0xb9, 0x10, 0x37, 0x05, 0xb9, 0xa2, 0xa5, 0x0a, 0x72, 0x55,
-	0x09, 0x45, 0xed, 0x54, 0x57, 0x94, 0xda, 0x05, 0x4d, 0x53, 0x6a, 0x17, 0x05, 0x48, 0xd4, 0x16,
-	0x57, 0x4f, 0xa9, 0xad, 0x48, 0x83, 0x52, 0x5b, 0xbd, 0xa7, 0xbc, 0x6b, 0x55, 0x71, 0xe1, 0x94,
-	0x04, 0xca, 0x1d, 0xdc, 0x30, 0xb4, 0x97, 0x8c, 0xba, 0x1d, 0x38, 0xd8, 0x47, 0x6b, 0x62, 0x36,
-	0xec, 0xfd, 0x02, 0x9a, 0x5f, 0x62, 0x7a, 0xc9, 0x1f, 0xfc, 0x83, 0x60, 0x14, 0xae, 0x4d, 0xf1,
-	0x5e, 0x01, 0xd8, 0x22, 0xdc, 0xd8, 0xbb, 0xa9, 0xf2, 0xc0, 0x17, 0xff, 0x06, 0x00, 0x00, 0xff,
-	0xff, 0x01, 0x6d, 0x48, 0x86, 0x51, 0x0c, 0x00, 0x00,
+	// 1649 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0xd5, 0x58, 0xcd, 0x73, 0xdb, 0x44,
+	0x14, 0xaf, 0xfc, 0x15, 0xfb, 0xd9, 0x71, 0xdd, 0xa5, 0xb4, 0xae, 0x9b, 0x81, 0x8e, 0x0a, 0x33,
+	0x85, 0x82, 0xc3, 0xa4, 0x7c, 0x96, 0x76, 0xc0, 0x89, 0x6d, 0xe2, 0xa9, 0x6b, 0x07, 0xc5, 0x29,
+	0xf4, 0x54, 0x14, 0x79, 0xed, 0x88, 0xd8, 0x92, 0x91, 0x64, 0x77, 0xc2, 0x99, 0x03, 0xff, 0x01,
+	0x37, 0xae, 0x9c, 0x18, 0x66, 0x38, 0x70, 0xe0, 0xc4, 0x8d, 0x3b, 0x67, 0xfe, 0x04, 0xfe, 0x08,
+	0xf6, 0x4b, 0xf2, 0xae, 0x1d, 0x3b, 0x4e, 0xa6, 0x1c, 0xb8, 0xe9, 0xed, 0x7b, 0xfb, 0xbe, 0xf7,
+	0xb7, 0x6f, 0x05, 0xf9, 0x91, 0xe7, 0x4e, 0xec, 0x2e, 0xf6, 0xca, 0xe4, 0x23, 0x70, 0x51, 0x66,
+	0x34, 0x1e, 0x8c, 0x87, 0xb6, 0x37, 0xb2, 0x4a, 0xb9, 0xd1, 0x60, 0xdc, 0xb7, 0x1d, 0xce, 0x28,
+	0xdd, 0xec, 0xbb, 0x6e, 0x7f, 0x80, 0x37, 0x19, 0x75, 0x38, 0xee, 0x6d, 0xe2, 0xe1, 0x28, 0x38,
+	0x11, 0xcc, 0x8d, 0x59, 0xa6, 0x1f, 0x78, 0x63, 0x2b, 0xe0, 0x5c, 0xfd, 0x4f, 0x0d, 0x0a, 0x3b,
+	0xae, 0xd3, 0xb3, 0xfb, 0x63, 0x0f, 0x1b, 0xf8, 0x9b, 0x31, 0xf6, 0x03, 0xb4, 0x0b, 0x99, 0x89,
+	0xe9, 0xd9, 0xe6, 0xe1, 0x00, 0xfb, 0x45, 0xed, 0x56, 0xfc, 0x4e, 0x76, 0xeb, 0xcd, 0x72, 0x64,
+	0xbc, 0x3c, 0x2b, 0x5f, 0x7e, 0x12, 0x0a, 0xd7, 0x9c, 0xc0, 0x3b, 0x31, 0xa6, 0x9b, 0xd1, 0x5d,
+	0x48, 0x98, 0x5e, 0xdf, 0x2f, 0xc6, 0x6e, 0x69, 0x44, 0xc9, 0xf5, 0x32, 0xf7, 0xa5, 0x1c, 0xfa,
+	0x52, 0xde, 0x67, 0xbe, 0x18, 0x4c, 0xa8, 0xf4, 0x00, 0xf2, 0xaa, 0x26, 0x54, 0x80, 0xf8, 0x31,
+	0x3e, 0x21, 0x2e, 0x68, 0x77, 0x32, 0x06, 0xfd, 0x44, 0x57, 0x21, 0x39, 0x31, 0x07, 0x63, 0xcc,
+	0x34, 0x66, 0x0c, 0x4e, 0xdc, 0x8f, 0x7d, 0xa8, 0xe9, 0xbf, 0x6a, 0x70, 0x23, 0xf2, 0xac, 0xe6,
+	0x79, 0xae, 0xf7, 0xd8, 0xf6, 0x7d, 0xdb, 0xe9, 0x3f, 0xc2, 0x27, 0x3e, 0xfa, 0x1c, 0xb2, 0xc3,
+	0x29, 0x29, 0x82, 0xda, 0x3c, 0x2d, 0xa8, 0xd9, 0xad, 0xe5, 0xe9, 0xb7, 0x21, 0xeb, 0x28, 0x6d,
+	0x03, 0x4c, 0x59, 0x08, 0x41, 0xc2, 0x31, 0x87, 0x58, 0xf8, 0xca, 0xbe, 0xd1, 0x2d, 0xc8, 0x76,
+	0xb1, 0x6f, 0x79, 0xf6, 0x28, 0xb0, 0x5d, 0x47, 0xb8, 0x2c, 0x2f, 0xe9, 0x5f, 0xc3, 0x7a, 0xc3,
+	0x99, 0xb8, 0xc7, 0x51, 0xea, 0x49, 0xc4, 0x81, 0x7b, 0x1c, 0x46, 0x4c, 0x3e, 0xcf, 0x95, 0x42,
+	0x54, 0x82, 0x74, 0xd8, 0x34, 0xc5, 0x38, 0xd3, 0x11, 0xd1, 0xfa, 0x04, 0xf2, 0xa1, 0x2d, 0x7f,
+	0xe4, 0x3a, 0x3e, 0x46, 0x9b, 0x90, 0xf2, 0x70, 0x30, 0xf6, 0x1c, 0x66, 0x6f, 0x89, 0x72, 0x21,
+	0x86, 0xee, 0x41, 0xba, 0x67, 0xda, 0x03, 0x92, 0x25, 0xea, 0x4f, 0x9c, 0x6d, 0x91, 0x52, 0x78,
+	0x84, 0xad, 0xe3, 0x3a, 0xe7, 0x1b, 0x91, 0xa0, 0xfe, 0x2d, 0xe4, 0x18, 0x47, 0x0a, 0x31, 0x34,
+	0x49, 0x42, 0xa4, 0x6a, 0x49, 0x88, 0xee, 0xa0, 0x7b, 0x76, 0x88, 0x54, 0x88, 0x0a, 0x3b, 0xf8,
+	0xb9, 0xcf, 0xc2, 0x5b, 0x26, 0x4c, 0x85, 0xf4, 0xdf, 0x34, 0x58, 0x17, 0xc6, 0xa7, 0x31, 0xdb,
+	0xce, 0x68, 0x1c, 0xf8, 0x67, 0xc6, 0xcc, 0xc5, 0x2e, 0x14, 0x33, 0xfa, 0x04, 0xc8, 0xe1, 0xc5,
+	0x13, 0x1b, 0x3f, 0x6f, 0x8f, 0x03, 0x66, 0xed, 0x0c, 0x77, 0x67, 0xc4, 0xf5, 0x6d, 0x91, 0x34,
+	0xa1, 0x5a, 0x14, 0x76, 0x84, 0xbd, 0x20, 0x3c, 0x0e, 0x11, 0x8d, 0xae, 0xd1, 0x32, 0x9a, 0x7e,
+	0xd4, 0x61, 0x82, 0xd2, 0xff, 0xd0, 0x20, 0x5b, 0xb5, 0x7b, 0xbd, 0x30, 0xf1, 0x79, 0x88, 0xd9,
+	0x5d, 0xb1, 0x9b, 0x7c, 0x85, 0x85, 0x88, 0xcd, 0x17, 0x22, 0x7e, 0x9e, 0x42, 0x24, 0x56, 0x28,
+	0x04, 0x7a, 0x0f, 0x32, 0x64, 0x53, 0x83, 0x67, 0x3e, 0xb9, 0x7c, 0xc7, 0x54, 0x52, 0xff, 0x31,
+	0x01, 0x39, 0x1e, 0x82, 0x28, 0x1f, 0xc9, 0x83, 0x87, 0x47, 0x03, 0xd3, 0x12, 0xc8, 0x44, 0xf2,
+	0x10, 0xd2, 0xa8, 0x08, 0x6b, 0x7e, 0xc0, 0x41, 0x2b, 0xc6, 0x58, 0x21, 0x89, 0xde, 0x81, 0x97,
+	0xba, 0x78, 0x80, 0x03, 0xbc, 0x8d, 0x7b, 0x2e, 0xc5, 0x2d, 0xb6, 0x83, 0x85, 0x99, 0x36, 0x4e,
+	0x63, 0xa1, 0x87, 0xb0, 0x66, 0x1d, 0x99, 0x4e, 0x1f, 0xf3, 0xf8, 0xf2, 0x5b, 0xb7, 0xa5, 0xa2,
+	0xcb, 0x1e, 0x31, 0x62, 0x87, 0x8b, 0x1a, 0xe1, 0x1e, 0x0a, 0x53, 0x5d, 0xb2, 0x4e, 0x43, 0xa5,
+	0x8e, 0x70, 0x02, 0x3d, 0x86, 0x5c, 0x17, 0x07, 0xa4, 0xa2, 0xb8, 0x4b, 0x77, 0x15, 0x53, 0xac,
+	0x9d, 0xde, 0x58, 0xa8, 0x59, 0x92, 0xe5, 0xc8, 0xaa, 0x6c, 0x47, 0x77, 0xe0, 0xf2, 0x91, 0xe9,
+	0xcb, 0x52, 0xc5, 0x35, 0x16, 0xd1, 0xec, 0xf2, 0x29, 0xed, 0x98, 0x3e, 0x57, 0x3b, 0x96, 0xbe,
+	0x84, 0x2b, 0x73, 0xde, 0x9c, 0x82, 0xce, 0x6f, 0xcb, 0xe8, 0xac, 0x1e, 0x94, 0x3d, 0xd1, 0xad,
+	0x2c, 0x42, 0x09, 0xb6, 0x1f, 0xf2, 0x1e, 0x15, 0x19, 0x24, 0x3a, 0x73, 0xd5, 0x46, 0xbd, 0xfe,
+	0xec, 0xa0, 0xf5, 0xa8, 0xd5, 0xfe, 0xa2, 0x55, 0xb8, 0x84, 0xd6, 0x21, 0xc3, 0x56, 0x5a, 0xed,
+	0x56, 0xad, 0xa0, 0x45, 0xe4, 0x7e, 0xfb, 0x71, 0xad, 0x10, 0xd3, 0x03, 0x72, 0xbe, 0x49, 0xbb,
+	0x07, 0x78, 0x31, 0xba, 0x7c, 0x00, 0x20, 0x8e, 0x8a, 0x8d, 0xcf, 0xc4, 0x18, 0x49, 0x94, 0xf6,
+	0x53, 0x60, 0x0f, 0xb1, 0x3b, 0x0e, 0x58, 0xa7, 0x68, 0x46, 0x48, 0xea, 0x4f, 0x21, 0x1f, 0x5a,
+	0x15, 0x7d, 0x39, 0x7b, 0xb6, 0x2e, 0x6a, 0x54, 0x3f, 0x82, 0xac, 0x81, 0xcd, 0xee, 0xea, 0x67,
+	0x56, 0xb5, 0x14, 0x5f, 0xdd, 0xd2, 0xf7, 0x1a, 0xe4, 0xb8, 0xa9, 0x17, 0x1c, 0x83, 0x84, 0xb1,
+	0xf1, 0x95, 0x30, 0x56, 0xff, 0x9b, 0xc0, 0xf4, 0xc1, 0xa8, 0x2b, 0x95, 0xf1, 0xff, 0x87, 0x55,
+	0x72, 0xbb, 0xa4, 0xd4, 0x76, 0x69, 0x40, 0x3e, 0x8c, 0x4e, 0xa4, 0x5a, 0x4d, 0xad, 0xb6, 0x7a,
+	0xd1, 0xbe, 0x23, 0x99, 0xaa, 0x32, 0xbc, 0xfa, 0xef, 0x3b, 0x44, 0x8e, 0x28, 0xa1, 0x46, 0xf4,
+	0xb3, 0x06, 0xd7, 0xd9, 0xa0, 0x44, 0x22, 0x72, 0xc7, 0x9e, 0x85, 0x1b, 0x8e, 0x1d, 0xd4, 0x19,
+	0x3e, 0xbc, 0xb8, 0x36, 0x22, 0xe6, 0xf9, 0x4d, 0x46, 0x9d, 0x66, 0x78, 0x2e, 0x48, 0xa9, 0xc1,
+	0x12, 0xab, 0x35, 0x18, 0xb9, 0x0a, 0x73, 0x32, 0x02, 0x91, 0x1b, 0x21, 0x71, 0x6c, 0x3b, 0xdc,
+	0xcd, 0xfc, 0xd6, 0xc6, 0x02, 0xa0, 0x2a, 0x3f, 0x22, 0x32, 0x06, 0x93, 0x44, 0x1b, 0x90, 0x61,
+	0xca, 0x18, 0xce, 0xc6, 0x18, 0xce, 0x4e, 0x17, 0xf4, 0xaf, 0x20, 0x41, 0x65, 0xd1, 0x1a, 0xc4,
+	0x2b, 0xd5, 0x2a, 0x81, 0xad, 0xcb, 0x90, 0x25, 0x1f, 0xcf, 0x8c, 0xda, 0x5e, 0xb3, 0xb2, 0x43,
+	0x81, 0x0b, 0x20, 0x55, 0xad, 0x35, 0x6b, 0x1d, 0x82, 0x5a, 0x64, 0x58, 0xcc, 0xf3, 0xef, 0x88,
+	0x1f, 0xa7, 0xfc, 0x83, 0xbd, 0x6a, 0x85, 0xf0, 0x13, 0x94, 0xcf, 0xbf, 0x23, 0x7e, 0x52, 0xaf,
+	0x43, 0x81, 0x9e, 0xd6, 0x6d, 0x33, 0xb0, 0x8e, 0xc2, 0xda, 0x6f, 0xd1, 0xdb, 0x90, 0x7d, 0x86,
+	0x23, 0xed, 0x35, 0x29, 0x12, 0x09, 0x47, 0x8c, 0x48, 0x4e, 0xff, 0x47, 0x83, 0x2b, 0x92, 0x22,
+	0xd1, 0x90, 0x0f, 0x69, 0xae, 0xfd, 0xf1, 0x20, 0x52, 0x74, 0x7b, 0x46, 0x91, 0x22, 0x4e, 0x56,
+	0xa8, 0xac, 0x11, 0xee, 0x29, 0xfd, 0xa0, 0x41, 0x8a, 0xaf, 0xd1, 0x79, 0xc9, 0x13, 0x62, 0x51,
+	0x63, 0xcf, 0xfa, 0xc4, 0xd9, 0x46, 0x24, 0x48, 0xef, 0x4b, 0x4c, 0xdb, 0x29, 0x1c, 0xeb, 0x19,
+	0x81, 0xc8, 0x84, 0x6d, 0x47, 0x7d, 0x25, 0x1a, 0x57, 0x97, 0x94, 0x2d, 0xe8, 0x40, 0x43, 0xda,
+	0x45, 0xd3, 0x46, 0x0b, 0x74, 0x8e, 0xb4, 0x49, 0x23, 0x93, 0x94, 0xb6, 0x9f, 0x48, 0xda, 0x24,
+	0x45, 0xab, 0xa4, 0x6d, 0x4e, 0x7c, 0x2e, 0x6d, 0xfb, 0x2b, 0x66, 0x4d, 0x1e, 0x0b, 0xce, 0xca,
+	0x9a, 0xfe, 0x97, 0x06, 0x57, 0xf7, 0xc4, 0xd0, 0xbf, 0x63, 0x8e, 0xcc, 0x43, 0x7b, 0x60, 0xb3,
+	0xf3, 0xf4, 0x1a, 0xac, 0x9b, 0x96, 0x85, 0x47, 0xc1, 0x3e, 0xb6, 0xc8, 0x40, 0xcf, 0x71, 0x27,
+	0x6d, 0xa8, 0x8b, 0x54, 0x2a, 0x38, 0x19, 0xe1, 0xee, 0x81, 0x73, 0xec, 0xb8, 0xcf, 0x1d, 0x5f,
+	0xf4, 0xba, 0xba, 0x88, 0xf4, 0x99, 0x51, 0x86, 0x8f, 0x52, 0xea, 0x7c, 0x42, 0x9e, 0x44, 0xe4,
+	0xc0, 0x76, 0xd9, 0x19, 0x4d, 0x1b, 0xec, 0x9b, 0xba, 0x7c, 0x48, 0x53, 0xc2, 0x70, 0x35, 0x6d,
+	0x70, 0x82, 0x3e, 0x94, 0x6c, 0x27, 0x78, 0xff, 0xdd, 0x27, 0x74, 0x2c, 0xf0, 0x19, 0x7c, 0xa6,
+	0x0d, 0x79, 0x49, 0x7f, 0x0b, 0x0a, 0x9f, 0xe1, 0x60, 0xdf, 0x3a, 0xc2, 0x43, 0x33, 0x2c, 0x23,
+	0xc1, 0x87, 0x09, 0xf6, 0x7c, 0xfa, 0xb4, 0xa2, 0x91, 0x24, 0x8d, 0x90, 0xd4, 0xef, 0xc2, 0x15,
+	0x49, 0x5a, 0x64, 0x8b, 0x8c, 0xc9, 0x3e, 0x5b, 0x11, 0xd8, 0x24, 0x28, 0xfd, 0x97, 0x18, 0x5c,
+	0x65, 0x9d, 0x14, 0x26, 0x2d, 0x9c, 0xb9, 0x3f, 0x86, 0xa4, 0x35, 0x30, 0x7d, 0x5f, 0x80, 0xc4,
+	0xeb, 0xb3, 0x9d, 0x37, 0x23, 0x5f, 0xde, 0xa1, 0xc2, 0x06, 0xdf, 0x43, 0x83, 0xb7, 0xdc, 0x6e,
+	0xf8, 0x4e, 0x65, 0xdf, 0x14, 0x42, 0x48, 0x8a, 0xbd, 0x13, 0x3a, 0x94, 0x8a, 0x8c, 0x4d, 0x17,
+	0xd0, 0x2b, 0x00, 0x8c, 0xa8, 0xf4, 0x02, 0xf2, 0x7a, 0xe3, 0x80, 0x2b, 0xad, 0xd0, 0x24, 0x79,
+	0x78, 0x88, 0xbb, 0xb6, 0xc9, 0x5e, 0x93, 0x49, 0xfe, 0x9a, 0x94, 0x96, 0xf4, 0x1e, 0x24, 0x99,
+	0x0f, 0x28, 0x0b, 0x6b, 0xca, 0x00, 0x65, 0xd4, 0x3a, 0xc6, 0xd3, 0xca, 0x76, 0x53, 0x0c, 0x50,
+	0x9d, 0x5d, 0xa3, 0xdd, 0xe9, 0x34, 0x6b, 0x55, 0x02, 0x45, 0x39, 0x48, 0xef, 0xb4, 0x5b, 0xf5,
+	0x66, 0x63, 0xa7, 0x43, 0x40, 0x88, 0x30, 0x5b, 0xed, 0xce, 0xb3, 0x7a, 0xfb, 0xa0, 0x55, 0x25,
+	0x38, 0x94, 0x86, 0x44, 0xe5, 0xa0, 0xb3, 0x5b, 0x48, 0x52, 0x8d, 0x8d, 0xd6, 0x93, 0x4a, 0xb3,
+	0x51, 0x2d, 0xa4, 0xb6, 0x7e, 0x4f, 0x53, 0x2c, 0xe2, 0xc7, 0x2e, 0x4c, 0x02, 0x39, 0xac, 0x59,
+	0xf6, 0x62, 0xe1, 0x0f, 0x69, 0x34, 0xf7, 0x48, 0x12, 0x55, 0x2b, 0x15, 0xe7, 0x19, 0xbc, 0x40,
+	0xfa, 0x25, 0x32, 0xa7, 0x02, 0x1b, 0x06, 0xb9, 0x8a, 0x05, 0x87, 0xb2, 0xb4, 0xe8, 0x64, 0x10,
+	0x05, 0xdb, 0x90, 0x89, 0x1e, 0xf2, 0xe8, 0xe6, 0x92, 0x7f, 0x16, 0xa5, 0x6b, 0x73, 0x57, 0x46,
+	0x8d, 0xfe, 0x34, 0x61, 0x4e, 0xa4, 0xf8, 0x3b, 0x19, 0xc9, 0xae, 0x2a, 0xcf, 0xf4, 0xd2, 0x8d,
+	0x53, 0x38, 0x91, 0x13, 0x0f, 0x48, 0x19, 0x68, 0x60, 0x17, 0xcb, 0xc1, 0x47, 0x90, 0x60, 0xa7,
+	0xe7, 0x02, 0xd1, 0x13, 0xcf, 0xf9, 0x58, 0xaa, 0x78, 0xae, 0xcc, 0xc7, 0x8a, 0xe7, 0xea, 0x0c,
+	0xcb, 0x6d, 0x53, 0x80, 0x46, 0x0b, 0x6e, 0x91, 0xd2, 0x22, 0x24, 0xe7, 0xb6, 0xf9, 0x8c, 0xa3,
+	0xd8, 0x56, 0x86, 0x3a, 0xc5, 0xb6, 0x3a, 0x10, 0xb1, 0xac, 0xa5, 0xf8, 0x60, 0xa3, 0x28, 0x50,
+	0x66, 0x9d, 0x25, 0x45, 0xdb, 0x25, 0x4d, 0x1e, 0xde, 0x52, 0x4a, 0xe1, 0x67, 0xef, 0xcc, 0xd2,
+	0xc6, 0xb2, 0x8b, 0x8d, 0x6b, 0x8a, 0x80, 0x5b, 0xd1, 0x34, 0x7b, 0x8d, 0x28, 0x9a, 0xe6, 0xb0,
+	0x9e, 0x68, 0xba, 0x4f, 0xca, 0x61, 0x3a, 0x16, 0x1e, 0xa0, 0x05, 0x7e, 0x2f, 0x89, 0xe7, 0x53,
+	0x58, 0x27, 0x08, 0xb6, 0xc7, 0xfe, 0xf2, 0x35, 0x9c, 0x9e, 0xbb, 0x50, 0xc5, 0xcb, 0xf2, 0xe8,
+	0x12, 0x89, 0x13, 0x0d, 0x4d, 0xb8, 0x4c, 0x34, 0x28, 0x17, 0xc0, 0x22, 0x1d, 0xaf, 0xaa, 0xe3,
+	0xcf, 0xdc, 0xcd, 0xc1, 0xb3, 0x12, 0x21, 0xaa, 0x92, 0x95, 0x59, 0x54, 0x56, 0xb2, 0x32, 0x07,
+	0xc2, 0xfa, 0xa5, 0xc3, 0x14, 0x33, 0x7e, 0xef, 0x5f, 0x47, 0xe6, 0xad, 0x27, 0xde, 0x14, 0x00,
+	0x00,
 }
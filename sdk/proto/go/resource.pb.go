@@ -171,6 +171,13 @@ type RegisterResourceRequest struct {
 	Provider             string                                                   `protobuf:"bytes,8,opt,name=provider" json:"provider,omitempty"`
 	PropertyDependencies map[string]*RegisterResourceRequest_PropertyDependencies `protobuf:"bytes,9,rep,name=propertyDependencies" json:"propertyDependencies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	DeleteBeforeReplace  bool                                                     `protobuf:"varint,10,opt,name=deleteBeforeReplace" json:"deleteBeforeReplace,omitempty"`
+	RetainOnDelete       bool                                                     `protobuf:"varint,11,opt,name=retainOnDelete" json:"retainOnDelete,omitempty"`
+	Aliases              []string                                                 `protobuf:"bytes,12,rep,name=aliases" json:"aliases,omitempty"`
+	IgnoreChanges        []string                                                 `protobuf:"bytes,13,rep,name=ignoreChanges" json:"ignoreChanges,omitempty"`
+	ReplaceOnChanges     []string                                                 `protobuf:"bytes,14,rep,name=replaceOnChanges" json:"replaceOnChanges,omitempty"`
+	ReadyConditions      []string                                                 `protobuf:"bytes,15,rep,name=readyConditions" json:"readyConditions,omitempty"`
+	CustomTimeouts       *RegisterResourceRequest_CustomTimeouts                  `protobuf:"bytes,16,opt,name=customTimeouts" json:"customTimeouts,omitempty"`
+	Hooks                []*RegisterResourceRequest_Hook                          `protobuf:"bytes,17,rep,name=hooks" json:"hooks,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                                                 `json:"-"`
 	XXX_unrecognized     []byte                                                   `json:"-"`
 	XXX_sizecache        int32                                                    `json:"-"`
@@ -270,12 +277,62 @@ func (m *RegisterResourceRequest) GetDeleteBeforeReplace() bool {
 	return false
 }
 
+func (m *RegisterResourceRequest) GetRetainOnDelete() bool {
+	if m != nil {
+		return m.RetainOnDelete
+	}
+	return false
+}
+
+func (m *RegisterResourceRequest) GetAliases() []string {
+	if m != nil {
+		return m.Aliases
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest) GetIgnoreChanges() []string {
+	if m != nil {
+		return m.IgnoreChanges
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest) GetReplaceOnChanges() []string {
+	if m != nil {
+		return m.ReplaceOnChanges
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest) GetReadyConditions() []string {
+	if m != nil {
+		return m.ReadyConditions
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest) GetCustomTimeouts() *RegisterResourceRequest_CustomTimeouts {
+	if m != nil {
+		return m.CustomTimeouts
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest) GetHooks() []*RegisterResourceRequest_Hook {
+	if m != nil {
+		return m.Hooks
+	}
+	return nil
+}
+
 // PropertyDependencies describes the resources that a particular property depends on.
 type RegisterResourceRequest_PropertyDependencies struct {
-	Urns                 []string `protobuf:"bytes,1,rep,name=urns" json:"urns,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Urns                 []string                                `protobuf:"bytes,1,rep,name=urns" json:"urns,omitempty"`
+	Reads                []*RegisterResourceRequest_PropertyRead `protobuf:"bytes,2,rep,name=reads" json:"reads,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                `json:"-"`
+	XXX_unrecognized     []byte                                  `json:"-"`
+	XXX_sizecache        int32                                   `json:"-"`
 }
 
 func (m *RegisterResourceRequest_PropertyDependencies) Reset() {
@@ -313,6 +370,196 @@ func (m *RegisterResourceRequest_PropertyDependencies) GetUrns() []string {
 	return nil
 }
 
+func (m *RegisterResourceRequest_PropertyDependencies) GetReads() []*RegisterResourceRequest_PropertyRead {
+	if m != nil {
+		return m.Reads
+	}
+	return nil
+}
+
+// CustomTimeouts bounds how long each of a resource's provider operations may take.  Each timeout is a duration
+// string such as "30s" or "1h30m"; an empty string leaves the operation without a timeout.
+type RegisterResourceRequest_CustomTimeouts struct {
+	Create               string   `protobuf:"bytes,1,opt,name=create" json:"create,omitempty"`
+	Update               string   `protobuf:"bytes,2,opt,name=update" json:"update,omitempty"`
+	Delete               string   `protobuf:"bytes,3,opt,name=delete" json:"delete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterResourceRequest_CustomTimeouts) Reset() {
+	*m = RegisterResourceRequest_CustomTimeouts{}
+}
+func (m *RegisterResourceRequest_CustomTimeouts) String() string {
+	return proto.CompactTextString(m)
+}
+func (*RegisterResourceRequest_CustomTimeouts) ProtoMessage() {}
+func (*RegisterResourceRequest_CustomTimeouts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_resource_03e51d5764cd9ae8, []int{2, 1}
+}
+func (m *RegisterResourceRequest_CustomTimeouts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterResourceRequest_CustomTimeouts.Unmarshal(m, b)
+}
+func (m *RegisterResourceRequest_CustomTimeouts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterResourceRequest_CustomTimeouts.Marshal(b, m, deterministic)
+}
+func (dst *RegisterResourceRequest_CustomTimeouts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterResourceRequest_CustomTimeouts.Merge(dst, src)
+}
+func (m *RegisterResourceRequest_CustomTimeouts) XXX_Size() int {
+	return xxx_messageInfo_RegisterResourceRequest_CustomTimeouts.Size(m)
+}
+func (m *RegisterResourceRequest_CustomTimeouts) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterResourceRequest_CustomTimeouts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterResourceRequest_CustomTimeouts proto.InternalMessageInfo
+
+func (m *RegisterResourceRequest_CustomTimeouts) GetCreate() string {
+	if m != nil {
+		return m.Create
+	}
+	return ""
+}
+
+func (m *RegisterResourceRequest_CustomTimeouts) GetUpdate() string {
+	if m != nil {
+		return m.Update
+	}
+	return ""
+}
+
+func (m *RegisterResourceRequest_CustomTimeouts) GetDelete() string {
+	if m != nil {
+		return m.Delete
+	}
+	return ""
+}
+
+// PropertyRead records the output properties of a resource that a property's value was computed from.  A property
+// that depends on a resource for which no reads are recorded is assumed to have read all of its outputs.
+type RegisterResourceRequest_PropertyRead struct {
+	Urn                  string   `protobuf:"bytes,1,opt,name=urn" json:"urn,omitempty"`
+	Paths                []string `protobuf:"bytes,2,rep,name=paths" json:"paths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterResourceRequest_PropertyRead) Reset() {
+	*m = RegisterResourceRequest_PropertyRead{}
+}
+func (m *RegisterResourceRequest_PropertyRead) String() string {
+	return proto.CompactTextString(m)
+}
+func (*RegisterResourceRequest_PropertyRead) ProtoMessage() {}
+func (*RegisterResourceRequest_PropertyRead) Descriptor() ([]byte, []int) {
+	return fileDescriptor_resource_03e51d5764cd9ae8, []int{2, 2}
+}
+func (m *RegisterResourceRequest_PropertyRead) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterResourceRequest_PropertyRead.Unmarshal(m, b)
+}
+func (m *RegisterResourceRequest_PropertyRead) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterResourceRequest_PropertyRead.Marshal(b, m, deterministic)
+}
+func (dst *RegisterResourceRequest_PropertyRead) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterResourceRequest_PropertyRead.Merge(dst, src)
+}
+func (m *RegisterResourceRequest_PropertyRead) XXX_Size() int {
+	return xxx_messageInfo_RegisterResourceRequest_PropertyRead.Size(m)
+}
+func (m *RegisterResourceRequest_PropertyRead) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterResourceRequest_PropertyRead.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterResourceRequest_PropertyRead proto.InternalMessageInfo
+
+func (m *RegisterResourceRequest_PropertyRead) GetUrn() string {
+	if m != nil {
+		return m.Urn
+	}
+	return ""
+}
+
+func (m *RegisterResourceRequest_PropertyRead) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+// Hook runs a local command at points in the lifecycle of the resource that registers it.
+type RegisterResourceRequest_Hook struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Events               []string `protobuf:"bytes,2,rep,name=events" json:"events,omitempty"`
+	Command              []string `protobuf:"bytes,3,rep,name=command" json:"command,omitempty"`
+	Advisory             bool     `protobuf:"varint,4,opt,name=advisory" json:"advisory,omitempty"`
+	Timeout              string   `protobuf:"bytes,5,opt,name=timeout" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterResourceRequest_Hook) Reset()         { *m = RegisterResourceRequest_Hook{} }
+func (m *RegisterResourceRequest_Hook) String() string { return proto.CompactTextString(m) }
+func (*RegisterResourceRequest_Hook) ProtoMessage()    {}
+func (*RegisterResourceRequest_Hook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_resource_03e51d5764cd9ae8, []int{2, 3}
+}
+func (m *RegisterResourceRequest_Hook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterResourceRequest_Hook.Unmarshal(m, b)
+}
+func (m *RegisterResourceRequest_Hook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterResourceRequest_Hook.Marshal(b, m, deterministic)
+}
+func (dst *RegisterResourceRequest_Hook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterResourceRequest_Hook.Merge(dst, src)
+}
+func (m *RegisterResourceRequest_Hook) XXX_Size() int {
+	return xxx_messageInfo_RegisterResourceRequest_Hook.Size(m)
+}
+func (m *RegisterResourceRequest_Hook) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterResourceRequest_Hook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterResourceRequest_Hook proto.InternalMessageInfo
+
+func (m *RegisterResourceRequest_Hook) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RegisterResourceRequest_Hook) GetEvents() []string {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest_Hook) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *RegisterResourceRequest_Hook) GetAdvisory() bool {
+	if m != nil {
+		return m.Advisory
+	}
+	return false
+}
+
+func (m *RegisterResourceRequest_Hook) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
 // RegisterResourceResponse is returned by the engine after a resource has finished being initialized.  It includes the
 // auto-assigned URN, the provider-assigned ID, and any other properties initialized by the engine.
 type RegisterResourceResponse struct {
@@ -438,6 +685,9 @@ func init() {
 	proto.RegisterType((*RegisterResourceRequest)(nil), "pulumirpc.RegisterResourceRequest")
 	proto.RegisterMapType((map[string]*RegisterResourceRequest_PropertyDependencies)(nil), "pulumirpc.RegisterResourceRequest.PropertyDependenciesEntry")
 	proto.RegisterType((*RegisterResourceRequest_PropertyDependencies)(nil), "pulumirpc.RegisterResourceRequest.PropertyDependencies")
+	proto.RegisterType((*RegisterResourceRequest_CustomTimeouts)(nil), "pulumirpc.RegisterResourceRequest.CustomTimeouts")
+	proto.RegisterType((*RegisterResourceRequest_PropertyRead)(nil), "pulumirpc.RegisterResourceRequest.PropertyRead")
+	proto.RegisterType((*RegisterResourceRequest_Hook)(nil), "pulumirpc.RegisterResourceRequest.Hook")
 	proto.RegisterType((*RegisterResourceResponse)(nil), "pulumirpc.RegisterResourceResponse")
 	proto.RegisterType((*RegisterResourceOutputsRequest)(nil), "pulumirpc.RegisterResourceOutputsRequest")
 }
@@ -616,43 +866,54 @@ var _ResourceMonitor_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("resource.proto", fileDescriptor_resource_03e51d5764cd9ae8) }
 
 var fileDescriptor_resource_03e51d5764cd9ae8 = []byte{
-	// 601 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x55, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xae, 0x9d, 0xd6, 0x49, 0xa6, 0x55, 0xa8, 0xb6, 0x51, 0xeb, 0x1a, 0x54, 0x2a, 0x73, 0x01,
-	0x0e, 0x0e, 0x84, 0x43, 0x11, 0x42, 0x42, 0x42, 0xf4, 0xc0, 0xa1, 0x02, 0xcc, 0x19, 0x24, 0xc7,
-	0x9e, 0x46, 0xa6, 0x8e, 0x77, 0x59, 0xaf, 0x23, 0xe5, 0xc6, 0x9b, 0xf0, 0x48, 0xbc, 0x01, 0x27,
-	0x1e, 0x84, 0x5d, 0xaf, 0x1d, 0xe2, 0xd8, 0x69, 0x2a, 0x4e, 0x99, 0xbf, 0xfd, 0x76, 0xe6, 0x9b,
-	0x6f, 0x1d, 0x18, 0x70, 0xcc, 0x68, 0xce, 0x43, 0xf4, 0x18, 0xa7, 0x82, 0x92, 0x3e, 0xcb, 0x93,
-	0x7c, 0x16, 0x73, 0x16, 0x3a, 0xf7, 0xa7, 0x94, 0x4e, 0x13, 0x1c, 0x15, 0x89, 0x49, 0x7e, 0x3d,
-	0xc2, 0x19, 0x13, 0x0b, 0x5d, 0xe7, 0x3c, 0x58, 0x4f, 0x66, 0x82, 0xe7, 0xa1, 0x28, 0xb3, 0x03,
-	0xf9, 0x33, 0x8f, 0x23, 0xe4, 0xda, 0x77, 0x7f, 0x1b, 0x70, 0xe4, 0x63, 0x10, 0xf9, 0xe5, 0x65,
-	0x3e, 0x7e, 0xcf, 0x31, 0x13, 0x64, 0x00, 0x66, 0x1c, 0xd9, 0xc6, 0xb9, 0xf1, 0xb8, 0xef, 0x4b,
-	0x8b, 0x10, 0xd8, 0x15, 0x0b, 0x86, 0xb6, 0x59, 0x44, 0x0a, 0x5b, 0xc5, 0xd2, 0x60, 0x86, 0x76,
-	0x47, 0xc7, 0x94, 0x4d, 0x8e, 0xc1, 0x62, 0x01, 0xc7, 0x54, 0xd8, 0xbb, 0x45, 0xb4, 0xf4, 0xc8,
-	0x05, 0x80, 0xbc, 0x90, 0x21, 0x17, 0x31, 0x66, 0xf6, 0x9e, 0xcc, 0xed, 0x8f, 0x4f, 0x3c, 0xdd,
-	0xaa, 0x57, 0xb5, 0xea, 0x7d, 0x2e, 0x5a, 0xf5, 0x57, 0x4a, 0x89, 0x0b, 0x07, 0x11, 0x32, 0x4c,
-	0x23, 0x4c, 0x43, 0x75, 0xd4, 0x3a, 0xef, 0x48, 0xd8, 0x5a, 0x8c, 0x38, 0xd0, 0xab, 0xc6, 0xb2,
-	0xbb, 0xc5, 0xb5, 0x4b, 0xdf, 0x0d, 0x60, 0x58, 0x9f, 0x2f, 0x63, 0x34, 0xcd, 0x90, 0x1c, 0x42,
-	0x27, 0xe7, 0x69, 0x39, 0xa1, 0x32, 0xd7, 0x5a, 0x34, 0xef, 0xdc, 0xa2, 0xfb, 0x6b, 0x17, 0x4e,
-	0x7c, 0x9c, 0xc6, 0x99, 0x40, 0xbe, 0xce, 0x63, 0xc5, 0x9b, 0xd1, 0xc2, 0x9b, 0xd9, 0xca, 0x5b,
-	0xa7, 0xc6, 0x9b, 0x8c, 0x87, 0x79, 0x26, 0xe8, 0xac, 0xe0, 0xb3, 0xe7, 0x97, 0x1e, 0x19, 0x81,
-	0x45, 0x27, 0xdf, 0x30, 0x14, 0xdb, 0xb8, 0x2c, 0xcb, 0x88, 0x0d, 0x5d, 0x95, 0x52, 0x27, 0xac,
-	0x02, 0xa9, 0x72, 0x1b, 0x0c, 0x77, 0xb7, 0x30, 0xdc, 0xab, 0x33, 0x4c, 0x18, 0x0c, 0x4b, 0x32,
-	0x16, 0xef, 0x56, 0x71, 0xfa, 0x12, 0x67, 0x7f, 0xfc, 0xda, 0x5b, 0xea, 0xd6, 0xdb, 0x40, 0x92,
-	0xf7, 0xb1, 0xe5, 0xf8, 0x65, 0x2a, 0xf8, 0xc2, 0x6f, 0x45, 0x26, 0xcf, 0xe0, 0x28, 0xc2, 0x04,
-	0x05, 0xbe, 0xc5, 0x6b, 0xca, 0x25, 0x0c, 0x4b, 0x82, 0x10, 0x6d, 0x28, 0xe6, 0x6a, 0x4b, 0x39,
-	0x4f, 0x61, 0xd8, 0x76, 0x89, 0x5a, 0x85, 0x5c, 0x7d, 0x26, 0xd7, 0xa3, 0x66, 0x2e, 0x6c, 0xe7,
-	0x87, 0x01, 0xa7, 0x1b, 0x3b, 0x52, 0xba, 0xb9, 0xc1, 0x45, 0xa5, 0x1b, 0x69, 0x92, 0x2b, 0xd8,
-	0x9b, 0x07, 0x49, 0x8e, 0xa5, 0x64, 0x2e, 0xfe, 0x73, 0x60, 0x5f, 0xa3, 0xbc, 0x32, 0x5f, 0x1a,
-	0xee, 0x4f, 0x03, 0xec, 0xe6, 0xd9, 0x8d, 0xca, 0xd5, 0x8f, 0xd5, 0x5c, 0x3e, 0xd6, 0x7f, 0xe2,
-	0xe8, 0xdc, 0x4d, 0x1c, 0x52, 0x65, 0x99, 0x08, 0x26, 0x09, 0x56, 0x2a, 0xd3, 0x9e, 0x12, 0x8d,
-	0xb6, 0xd4, 0x93, 0x55, 0x0c, 0x55, 0xae, 0x8b, 0x70, 0xb6, 0xde, 0xe0, 0x87, 0x5c, 0xb0, 0x5c,
-	0x64, 0x95, 0xf2, 0x9b, 0x6d, 0x3e, 0x87, 0x2e, 0xd5, 0x35, 0xdb, 0x5e, 0x57, 0x55, 0x37, 0xfe,
-	0x63, 0xc2, 0xbd, 0x0a, 0xff, 0x8a, 0xa6, 0xb1, 0xa0, 0x9c, 0xbc, 0x01, 0xeb, 0x7d, 0x3a, 0xa7,
-	0x37, 0xb2, 0xbd, 0x15, 0xaa, 0x75, 0xa8, 0xbc, 0xdc, 0x39, 0x6d, 0xc9, 0x68, 0xfa, 0xdc, 0x1d,
-	0xf2, 0x09, 0x0e, 0x56, 0x3f, 0x09, 0xe4, 0xac, 0xb6, 0xb1, 0xc6, 0xb7, 0xd0, 0x79, 0xb8, 0x31,
-	0xbf, 0x84, 0xfc, 0x02, 0x87, 0xeb, 0x74, 0x10, 0x77, 0xbb, 0x10, 0x9c, 0x47, 0xb7, 0xd6, 0x2c,
-	0xe1, 0xbf, 0x36, 0x3f, 0x30, 0x25, 0xdb, 0xe4, 0xc9, 0x2d, 0x08, 0xf5, 0x8d, 0x38, 0xc7, 0x0d,
-	0xba, 0x2f, 0xd5, 0xff, 0x86, 0xbb, 0x33, 0xb1, 0x8a, 0xc8, 0x8b, 0xbf, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x00, 0xdf, 0xec, 0x9d, 0x74, 0x06, 0x00, 0x00,
+	// 773 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9d, 0x56, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x26, 0x49, 0xf3, 0x37, 0x4d, 0xd3, 0x68, 0x1b, 0xb5, 0xae, 0x41, 0xa5, 0x32, 0x08, 0x15,
+	0x0e, 0x09, 0x2d, 0x12, 0x45, 0x08, 0x09, 0x89, 0xb6, 0x07, 0x0e, 0x55, 0xc1, 0x70, 0x80, 0x03,
+	0x48, 0x8e, 0x3d, 0x4d, 0x4d, 0x13, 0xaf, 0xbb, 0x5e, 0x57, 0xca, 0x8d, 0x37, 0xe1, 0x59, 0x78,
+	0x11, 0x4e, 0x3c, 0x08, 0xfb, 0x63, 0x87, 0xd8, 0x71, 0x9a, 0xc2, 0x29, 0x3b, 0xdf, 0xcc, 0x7e,
+	0x3b, 0xfb, 0xed, 0xcc, 0x38, 0xd0, 0x66, 0x18, 0xd1, 0x98, 0xb9, 0xd8, 0x0b, 0x19, 0xe5, 0x94,
+	0x34, 0xc3, 0x78, 0x14, 0x8f, 0x7d, 0x16, 0xba, 0xe6, 0xdd, 0x21, 0xa5, 0xc3, 0x11, 0xf6, 0x95,
+	0x63, 0x10, 0x9f, 0xf7, 0x71, 0x1c, 0xf2, 0x89, 0x8e, 0x33, 0xef, 0xe5, 0x9d, 0x11, 0x67, 0xb1,
+	0xcb, 0x13, 0x6f, 0x5b, 0xfc, 0x5c, 0xfb, 0x1e, 0x32, 0x6d, 0x5b, 0xbf, 0x4a, 0xb0, 0x61, 0xa3,
+	0xe3, 0xd9, 0xc9, 0x61, 0x36, 0x5e, 0xc5, 0x18, 0x71, 0xd2, 0x86, 0xb2, 0xef, 0x19, 0xa5, 0xdd,
+	0xd2, 0x5e, 0xd3, 0x16, 0x2b, 0x42, 0x60, 0x85, 0x4f, 0x42, 0x34, 0xca, 0x0a, 0x51, 0x6b, 0x89,
+	0x05, 0xce, 0x18, 0x8d, 0x8a, 0xc6, 0xe4, 0x9a, 0x6c, 0x42, 0x2d, 0x74, 0x18, 0x06, 0xdc, 0x58,
+	0x51, 0x68, 0x62, 0x91, 0x43, 0x00, 0x71, 0x60, 0x88, 0x8c, 0xfb, 0x18, 0x19, 0x55, 0xe1, 0x5b,
+	0x3d, 0xd8, 0xea, 0xe9, 0x54, 0x7b, 0x69, 0xaa, 0xbd, 0x0f, 0x2a, 0x55, 0x7b, 0x26, 0x94, 0x58,
+	0xd0, 0xf2, 0x30, 0xc4, 0xc0, 0xc3, 0xc0, 0x95, 0x5b, 0x6b, 0xbb, 0x15, 0x41, 0x9b, 0xc1, 0x88,
+	0x09, 0x8d, 0xf4, 0x5a, 0x46, 0x5d, 0x1d, 0x3b, 0xb5, 0x2d, 0x07, 0xba, 0xd9, 0xfb, 0x45, 0x21,
+	0x0d, 0x22, 0x24, 0x1d, 0xa8, 0xc4, 0x2c, 0x48, 0x6e, 0x28, 0x97, 0xb9, 0x14, 0xcb, 0xb7, 0x4e,
+	0xd1, 0xfa, 0xd9, 0x80, 0x2d, 0x1b, 0x87, 0x7e, 0xc4, 0x91, 0xe5, 0x75, 0x4c, 0x75, 0x2b, 0x15,
+	0xe8, 0x56, 0x2e, 0xd4, 0xad, 0x92, 0xd1, 0x4d, 0xe0, 0x6e, 0x1c, 0x71, 0x3a, 0x56, 0x7a, 0x36,
+	0xec, 0xc4, 0x22, 0x7d, 0xa8, 0xd1, 0xc1, 0x37, 0x74, 0xf9, 0x32, 0x2d, 0x93, 0x30, 0x62, 0x40,
+	0x5d, 0xba, 0xe4, 0x8e, 0x9a, 0x62, 0x4a, 0xcd, 0x39, 0x85, 0xeb, 0x4b, 0x14, 0x6e, 0x64, 0x15,
+	0x26, 0x21, 0x74, 0x13, 0x31, 0x26, 0xc7, 0xb3, 0x3c, 0x4d, 0xc1, 0xb3, 0x7a, 0xf0, 0xaa, 0x37,
+	0xad, 0xdb, 0xde, 0x02, 0x91, 0x7a, 0xef, 0x0a, 0xb6, 0x9f, 0x04, 0x9c, 0x4d, 0xec, 0x42, 0x66,
+	0xf2, 0x14, 0x36, 0x3c, 0x1c, 0x21, 0xc7, 0x37, 0x78, 0x4e, 0x99, 0xa0, 0x09, 0x47, 0x8e, 0x8b,
+	0x06, 0xa8, 0x7b, 0x15, 0xb9, 0xc8, 0x23, 0x10, 0xed, 0xc4, 0x1d, 0x3f, 0x38, 0x0b, 0x8e, 0x95,
+	0xdb, 0x58, 0x55, 0xc1, 0x39, 0x54, 0xaa, 0xe4, 0x8c, 0x7c, 0x27, 0x12, 0xe9, 0xb7, 0x94, 0x0c,
+	0xa9, 0x49, 0x1e, 0xc2, 0x9a, 0x3f, 0x0c, 0x04, 0xe5, 0xd1, 0x85, 0x13, 0x0c, 0x85, 0x7f, 0x4d,
+	0xf9, 0xb3, 0x20, 0x79, 0x02, 0x1d, 0xa6, 0x8f, 0x3c, 0x0b, 0xd2, 0xc0, 0xb6, 0x0a, 0x9c, 0xc3,
+	0xc9, 0x1e, 0xac, 0x33, 0x51, 0x99, 0x93, 0x23, 0x1a, 0x78, 0x3e, 0xf7, 0x45, 0x55, 0x1a, 0xeb,
+	0x2a, 0x34, 0x0f, 0x93, 0xcf, 0xd0, 0xd6, 0xcf, 0xfe, 0xd1, 0x1f, 0x23, 0x8d, 0x79, 0x64, 0x74,
+	0xd4, 0xa3, 0xef, 0xdf, 0x42, 0xdb, 0xa3, 0xcc, 0x46, 0x3b, 0x47, 0x64, 0x5e, 0x41, 0xb7, 0x48,
+	0x7d, 0x59, 0xa3, 0xa2, 0x27, 0x22, 0x51, 0xb7, 0x32, 0x23, 0xb5, 0x26, 0x27, 0x50, 0x95, 0x99,
+	0xc9, 0xde, 0x90, 0x2f, 0xdb, 0xff, 0x87, 0x97, 0x55, 0x2d, 0xa8, 0x77, 0x9b, 0x9f, 0xa0, 0x9d,
+	0x4d, 0x4a, 0x15, 0xb9, 0xf0, 0xf1, 0xb4, 0x4d, 0x12, 0x4b, 0xe2, 0x71, 0xe8, 0x49, 0x5c, 0xb7,
+	0x4a, 0x62, 0x49, 0x5c, 0x3f, 0x72, 0xda, 0x2c, 0xda, 0x32, 0x9f, 0x43, 0x6b, 0xf6, 0xc0, 0x82,
+	0x1e, 0xef, 0x42, 0x35, 0x74, 0xf8, 0x85, 0xbe, 0x42, 0xd3, 0xd6, 0x86, 0xf9, 0xbd, 0x04, 0xdb,
+	0x0b, 0x6b, 0x50, 0xb2, 0x5c, 0xe2, 0x24, 0x65, 0x11, 0x4b, 0x72, 0x0a, 0xd5, 0x6b, 0x67, 0x14,
+	0x63, 0x32, 0x24, 0x0e, 0xff, 0xb3, 0xc4, 0x6d, 0xcd, 0xf2, 0xb2, 0xfc, 0xa2, 0x64, 0xfd, 0x28,
+	0x81, 0x31, 0xbf, 0x77, 0xe1, 0xac, 0xd2, 0xe3, 0xb9, 0x3c, 0x1d, 0xcf, 0x7f, 0xc7, 0x41, 0xe5,
+	0x76, 0xe3, 0x40, 0x48, 0x18, 0x71, 0x67, 0x30, 0xc2, 0x74, 0xae, 0x68, 0x4b, 0x36, 0x80, 0x5e,
+	0xc9, 0x21, 0xad, 0x1a, 0x20, 0x31, 0x2d, 0x84, 0x9d, 0x7c, 0x82, 0x67, 0x31, 0x0f, 0x65, 0x51,
+	0x25, 0xb3, 0x6e, 0x3e, 0xcd, 0x7d, 0xa8, 0x53, 0x1d, 0xb3, 0x6c, 0x9e, 0xa6, 0x71, 0x07, 0xbf,
+	0xcb, 0xb0, 0x9e, 0xf2, 0x9f, 0xd2, 0xc0, 0xe7, 0x94, 0x91, 0xd7, 0x50, 0x7b, 0x1b, 0x5c, 0xd3,
+	0x4b, 0x91, 0xde, 0x8c, 0xd4, 0x1a, 0x4a, 0x0e, 0x37, 0xb7, 0x0b, 0x3c, 0x5a, 0x3e, 0xeb, 0x0e,
+	0x79, 0x0f, 0xad, 0xd9, 0x8f, 0x00, 0xd9, 0xc9, 0xbc, 0xd8, 0xdc, 0xd7, 0xcf, 0xbc, 0xbf, 0xd0,
+	0x3f, 0xa5, 0xfc, 0x02, 0x9d, 0xbc, 0x1c, 0xc4, 0x5a, 0x5e, 0x08, 0xe6, 0x83, 0x1b, 0x63, 0xa6,
+	0xf4, 0x5f, 0xe7, 0x3f, 0x29, 0x89, 0xda, 0xe4, 0xf1, 0x0d, 0x0c, 0xd9, 0x17, 0x31, 0x37, 0xe7,
+	0xe4, 0x3e, 0x91, 0xff, 0x14, 0xac, 0x3b, 0x83, 0x9a, 0x42, 0x9e, 0xfd, 0x01, 0xdc, 0xa3, 0xea,
+	0x19, 0x66, 0x08, 0x00, 0x00,
 }